@@ -45,7 +45,9 @@ base_subprocess.BaseSubprocessTransport.__del__ = _patched_del
 
 # Type stubs for lazy imports - fixes linter warnings
 if TYPE_CHECKING:
+	from browser_use.agent.playbooks import Playbook
 	from browser_use.agent.prompts import SystemPrompt
+	from browser_use.agent.replay import HistoryReplayer
 	from browser_use.agent.service import Agent
 	from browser_use.agent.views import ActionModel, ActionResult, AgentHistoryList
 	from browser_use.browser import BrowserProfile, BrowserSession
@@ -63,7 +65,9 @@ if TYPE_CHECKING:
 	from browser_use.llm.ollama.chat import ChatOllama
 	from browser_use.llm.openai.chat import ChatOpenAI
 	from browser_use.llm.vercel.chat import ChatVercel
+	from browser_use.metrics import AgentMetrics
 	from browser_use.sandbox import sandbox
+	from browser_use.tools.script import BrowserScript
 	from browser_use.tools.service import Controller, Tools
 
 	# Lazy imports mapping - only import when actually accessed
@@ -72,6 +76,12 @@ _LAZY_IMPORTS = {
 	'Agent': ('browser_use.agent.service', 'Agent'),
 	# System prompt (moderate weight due to agent.views imports)
 	'SystemPrompt': ('browser_use.agent.prompts', 'SystemPrompt'),
+	# Site-specific playbooks
+	'Playbook': ('browser_use.agent.playbooks', 'Playbook'),
+	# Deterministic history replay without the LLM
+	'HistoryReplayer': ('browser_use.agent.replay', 'HistoryReplayer'),
+	# Prometheus metrics (needs the metrics extra)
+	'AgentMetrics': ('browser_use.metrics', 'AgentMetrics'),
 	# Agent views (very heavy - over 1 second!)
 	'ActionModel': ('browser_use.agent.views', 'ActionModel'),
 	'ActionResult': ('browser_use.agent.views', 'ActionResult'),
@@ -82,6 +92,8 @@ _LAZY_IMPORTS = {
 	# Tools (moderate weight)
 	'Tools': ('browser_use.tools.service', 'Tools'),
 	'Controller': ('browser_use.tools.service', 'Controller'),  # alias
+	# Scripted control with the agent's actions
+	'BrowserScript': ('browser_use.tools.script', 'BrowserScript'),
 	# DOM service (moderate weight)
 	'DomService': ('browser_use.dom.service', 'DomService'),
 	# Chat models (very heavy imports)
@@ -133,6 +145,9 @@ __all__ = [
 	'Controller',
 	'DomService',
 	'SystemPrompt',
+	'Playbook',
+	'HistoryReplayer',
+	'AgentMetrics',
 	'ActionResult',
 	'ActionModel',
 	'AgentHistoryList',
@@ -150,6 +165,7 @@ __all__ = [
 	'ChatVercel',
 	'Tools',
 	'Controller',
+	'BrowserScript',
 	# LLM models module
 	'models',
 	# Sandbox execution
@@ -0,0 +1,354 @@
+"""Page and Element operations over WebDriver BiDi, with the same methods as the CDP Page and Element."""
+
+import json
+import re
+from typing import TYPE_CHECKING, Any, Literal
+
+from browser_use.actor.element import BoundingBox, ModifierType
+
+if TYPE_CHECKING:
+	from browser_use.browser.bidi import BiDiSession
+
+# WebDriver key codes of the non-printable keys, https://w3c.github.io/webdriver/#keyboard-actions
+BIDI_KEYS = {
+	'Backspace': '\uE003',
+	'Tab': '\uE004',
+	'Enter': '\uE007',
+	'Shift': '\uE008',
+	'Control': '\uE009',
+	'Alt': '\uE00A',
+	'Escape': '\uE00C',
+	'Space': ' ',
+	'PageUp': '\uE00E',
+	'PageDown': '\uE00F',
+	'End': '\uE010',
+	'Home': '\uE011',
+	'ArrowLeft': '\uE012',
+	'ArrowUp': '\uE013',
+	'ArrowRight': '\uE014',
+	'ArrowDown': '\uE015',
+	'Insert': '\uE016',
+	'Delete': '\uE017',
+	'Meta': '\uE03D',
+	**{f'F{n}': chr(0xE031 + n - 1) for n in range(1, 13)},
+}
+
+_MOUSE_BUTTONS = {'left': 0, 'middle': 1, 'right': 2}
+
+
+def key_actions(key: str) -> list[dict[str, str]]:
+	"""keyDown/keyUp actions for a key or a combination like 'Control+A'."""
+	keys = [BIDI_KEYS.get(part, part) for part in (key.split('+') if len(key) > 1 else [key])]
+	return [{'type': 'keyDown', 'value': k} for k in keys] + [{'type': 'keyUp', 'value': k} for k in reversed(keys)]
+
+
+def deserialize_remote_value(value: dict[str, Any]) -> Any:
+	"""Turn a BiDi RemoteValue into plain Python, nodes and other non-serializable values become None."""
+	kind = value.get('type')
+	if kind in ('string', 'boolean'):
+		return value.get('value')
+	if kind == 'number':
+		number = value.get('value')
+		return None if isinstance(number, str) else number  # NaN, Infinity, -0
+	if kind == 'bigint':
+		return int(value['value'])
+	if kind in ('array', 'set'):
+		return [deserialize_remote_value(item) for item in value.get('value', [])]
+	if kind in ('object', 'map'):
+		return {
+			key if isinstance(key, str) else str(deserialize_remote_value(key)): deserialize_remote_value(item)
+			for key, item in value.get('value', [])
+		}
+	if kind == 'date':
+		return value.get('value')
+	return None
+
+
+def _to_string(value: Any) -> str:
+	"""The string the CDP Page.evaluate returns for a value."""
+	if value is None:
+		return ''
+	if isinstance(value, str):
+		return value
+	return json.dumps(value) if isinstance(value, (dict, list)) else str(value)
+
+
+class BiDiPage:
+	"""Page operations on a top-level browsing context (tab)."""
+
+	def __init__(self, bidi_session: 'BiDiSession', context: str):
+		self._bidi_session = bidi_session
+		self._client = bidi_session.client
+		self.context = context
+
+	async def _call(self, function: str, *args: Any, this: dict[str, Any] | None = None) -> Any:
+		"""Call a JS function in the page with JSON arguments, returns the deserialized result."""
+		params: dict[str, Any] = {
+			'functionDeclaration': function,
+			'arguments': [_local_value(arg) for arg in args],
+			'target': {'context': self.context},
+			'awaitPromise': True,
+		}
+		if this is not None:
+			params['this'] = this
+		result = await self._client.send('script.callFunction', params)
+		if result.get('type') == 'exception':
+			details = result.get('exceptionDetails', {})
+			raise RuntimeError(f'JavaScript evaluation failed: {details.get("text", details)}')
+		return deserialize_remote_value(result.get('result', {}))
+
+	async def reload(self) -> None:
+		"""Reload the page."""
+		await self._client.send('browsingContext.reload', {'context': self.context, 'wait': 'complete'})
+
+	async def add_init_script(self, script: str) -> str:
+		"""Evaluate a script in this page before any page script runs, on every future navigation.
+
+		The script also runs right away in the current document. Returns an identifier for remove_init_script().
+		"""
+		function = f'() => {{ {script}\n}}'
+		result = await self._client.send('script.addPreloadScript', {'functionDeclaration': function, 'contexts': [self.context]})
+		await self._call(function)
+		return result['script']
+
+	async def remove_init_script(self, identifier: str) -> None:
+		"""Stop evaluating a script added with add_init_script() on new documents."""
+		await self._client.send('script.removePreloadScript', {'script': identifier})
+
+	async def evaluate(self, page_function: str, *args) -> str:
+		"""Execute JavaScript in the page.
+
+		Args:
+			page_function: JavaScript code that MUST start with (...args) => format
+			*args: Arguments to pass to the function
+
+		Returns:
+			String representation of the JavaScript execution result.
+			Objects and arrays are JSON-stringified.
+		"""
+		page_function = page_function.strip()
+		if not (page_function.startswith('(') and '=>' in page_function):
+			raise ValueError(f'JavaScript code must start with (...args) => format. Got: {page_function[:50]}...')
+		return _to_string(await self._call(page_function, *args))
+
+	async def screenshot(self, format: str = 'png', quality: int | None = None) -> str:
+		"""Take a screenshot of the viewport and return base64 encoded image."""
+		params: dict[str, Any] = {'context': self.context, 'format': _image_format(format, quality)}
+		result = await self._client.send('browsingContext.captureScreenshot', params)
+		return result['data']
+
+	async def press(self, key: str) -> None:
+		"""Press a key on the page (sends keyboard input to the focused element or page), e.g. 'Enter' or 'Control+A'."""
+		await self._perform([{'type': 'key', 'id': 'keyboard', 'actions': key_actions(key)}])
+
+	async def set_viewport_size(self, width: int, height: int) -> None:
+		"""Set the viewport size."""
+		await self._client.send(
+			'browsingContext.setViewport', {'context': self.context, 'viewport': {'width': width, 'height': height}}
+		)
+
+	async def get_url(self) -> str:
+		"""Get the current URL."""
+		result = await self._client.send('browsingContext.getTree', {'root': self.context, 'maxDepth': 0})
+		contexts = result.get('contexts', [])
+		return contexts[0].get('url', '') if contexts else ''
+
+	async def get_title(self) -> str:
+		"""Get the current title."""
+		return await self._call('() => document.title') or ''
+
+	async def goto(self, url: str) -> None:
+		"""Navigate this page to a URL and wait for it to load."""
+		await self._client.send('browsingContext.navigate', {'context': self.context, 'url': url, 'wait': 'complete'})
+
+	async def navigate(self, url: str) -> None:
+		"""Alias for goto."""
+		await self.goto(url)
+
+	async def go_back(self) -> None:
+		"""Navigate back in history."""
+		try:
+			await self._client.send('browsingContext.traverseHistory', {'context': self.context, 'delta': -1})
+		except Exception as e:
+			raise RuntimeError(f'Failed to navigate back: {e}')
+
+	async def go_forward(self) -> None:
+		"""Navigate forward in history."""
+		try:
+			await self._client.send('browsingContext.traverseHistory', {'context': self.context, 'delta': 1})
+		except Exception as e:
+			raise RuntimeError(f'Failed to navigate forward: {e}')
+
+	async def get_elements_by_css_selector(self, selector: str) -> list['BiDiElement']:
+		"""Get elements by CSS selector."""
+		result = await self._client.send(
+			'browsingContext.locateNodes', {'context': self.context, 'locator': {'type': 'css', 'value': selector}}
+		)
+		return [BiDiElement(self, node['sharedId']) for node in result.get('nodes', []) if node.get('sharedId')]
+
+	async def _perform(self, actions: list[dict[str, Any]]) -> None:
+		await self._client.send('input.performActions', {'context': self.context, 'actions': actions})
+		await self._client.send('input.releaseActions', {'context': self.context})
+
+
+class BiDiElement:
+	"""Element operations on a node shared by BiDi (sharedId)."""
+
+	def __init__(self, page: BiDiPage, shared_id: str):
+		self._page = page
+		self.shared_id = shared_id
+
+	@property
+	def _reference(self) -> dict[str, Any]:
+		return {'sharedId': self.shared_id}
+
+	async def _call(self, function: str, *args: Any) -> Any:
+		"""Call a JS function with this bound to the element."""
+		return await self._page._call(function, *args, this=self._reference)
+
+	def _move_to_center(self) -> dict[str, Any]:
+		# Pointer moves relative to an element origin are relative to its center
+		return {'type': 'pointerMove', 'x': 0, 'y': 0, 'origin': {'type': 'element', 'element': self._reference}}
+
+	async def _pointer(self, actions: list[dict[str, Any]], modifiers: list[ModifierType] | None = None) -> None:
+		pointer = {'type': 'pointer', 'id': 'mouse', 'parameters': {'pointerType': 'mouse'}, 'actions': actions}
+		keys = [BIDI_KEYS[modifier] for modifier in modifiers or []]
+		if not keys:
+			await self._page._perform([pointer])
+			return
+		# Modifiers are held on the keyboard source for the duration of the pointer actions
+		pauses = [{'type': 'pause'} for _ in actions]
+		keyboard = {
+			'type': 'key',
+			'id': 'keyboard',
+			'actions': [{'type': 'keyDown', 'value': k} for k in keys] + pauses + [{'type': 'keyUp', 'value': k} for k in keys],
+		}
+		pointer['actions'] = [{'type': 'pause'} for _ in keys] + actions
+		await self._page._perform([keyboard, pointer])
+
+	async def click(
+		self,
+		button: Literal['left', 'middle', 'right'] = 'left',
+		click_count: int = 1,
+		modifiers: list[ModifierType] | None = None,
+	) -> None:
+		"""Scroll the element into view and click its center."""
+		await self._call('function () { this.scrollIntoView({block: "center", inline: "center"}); }')
+		button_id = _MOUSE_BUTTONS[button]
+		actions = [self._move_to_center()]
+		for _ in range(click_count):
+			actions += [{'type': 'pointerDown', 'button': button_id}, {'type': 'pointerUp', 'button': button_id}]
+		await self._pointer(actions, modifiers)
+
+	async def fill(self, value: str, clear: bool = True) -> None:
+		"""Focus the element, optionally clear it, and type the value key by key."""
+		await self.focus()
+		if clear:
+			await self._call(
+				"""function () {
+					if ('value' in this) { this.value = ''; this.dispatchEvent(new Event('input', {bubbles: true})); }
+					else if (this.isContentEditable) { this.textContent = ''; }
+				}"""
+			)
+		actions = [action for char in value for action in ({'type': 'keyDown', 'value': char}, {'type': 'keyUp', 'value': char})]
+		await self._page._perform([{'type': 'key', 'id': 'keyboard', 'actions': actions}])
+
+	async def hover(self) -> None:
+		"""Hover over the element."""
+		await self._call('function () { this.scrollIntoView({block: "center", inline: "center"}); }')
+		await self._pointer([self._move_to_center()])
+
+	async def focus(self) -> None:
+		"""Focus the element."""
+		await self._call('function () { this.focus(); }')
+
+	async def check(self) -> None:
+		"""Check or uncheck a checkbox/radio button."""
+		await self.click()
+
+	async def select_option(self, values: str | list[str]) -> None:
+		"""Select option(s) in a select element, by value or visible text."""
+		if isinstance(values, str):
+			values = [values]
+		await self._call(
+			"""function (values) {
+				for (const option of this.options) {
+					option.selected = values.includes(option.value) || values.includes(option.text.trim());
+				}
+				this.dispatchEvent(new Event('input', {bubbles: true}));
+				this.dispatchEvent(new Event('change', {bubbles: true}));
+			}""",
+			values,
+		)
+
+	async def get_attribute(self, name: str) -> str | None:
+		"""Get an attribute value."""
+		return await self._call('function (name) { return this.getAttribute(name); }', name)
+
+	async def get_bounding_box(self) -> BoundingBox | None:
+		"""Get the bounding box of the element in viewport coordinates."""
+		box = await self._call(
+			'function () { const r = this.getBoundingClientRect(); return {x: r.x, y: r.y, width: r.width, height: r.height}; }'
+		)
+		if not box or not box['width'] or not box['height']:
+			return None
+		return BoundingBox(x=box['x'], y=box['y'], width=box['width'], height=box['height'])
+
+	async def screenshot(self, format: str = 'png', quality: int | None = None) -> str:
+		"""Take a screenshot of this element and return base64 encoded image."""
+		params = {
+			'context': self._page.context,
+			'format': _image_format(format, quality),
+			'clip': {'type': 'element', 'element': self._reference},
+		}
+		result = await self._page._client.send('browsingContext.captureScreenshot', params)
+		return result['data']
+
+	async def evaluate(self, page_function: str, *args) -> str:
+		"""Execute JavaScript code with 'this' bound to the element.
+
+		Args:
+			page_function: JavaScript code that MUST start with (...args) => format
+			*args: Arguments to pass to the function
+
+		Returns:
+			String representation of the JavaScript execution result.
+			Objects and arrays are JSON-stringified.
+		"""
+		page_function = page_function.strip()
+		is_async = page_function.startswith('async')
+		# Arrow functions don't bind this, convert to a function declaration like the CDP Element does
+		arrow_match = re.match(r'\s*\(([^)]*)\)\s*=>\s*(.+)', page_function[5:] if is_async else page_function, re.DOTALL)
+		if not arrow_match:
+			raise ValueError(
+				f'JavaScript code must start with (...args) => or async (...args) => format. Got: {page_function[:50]}...'
+			)
+		params_str, body = arrow_match.group(1).strip(), arrow_match.group(2).strip()
+		if not body.startswith('{'):
+			body = f'{{ return {body}; }}'
+		function = f'{"async " if is_async else ""}function({params_str}) {body}'
+		return _to_string(await self._call(function, *args))
+
+
+def _local_value(value: Any) -> dict[str, Any]:
+	"""A JSON-compatible Python value as a BiDi LocalValue."""
+	if value is None:
+		return {'type': 'null'}
+	if isinstance(value, bool):
+		return {'type': 'boolean', 'value': value}
+	if isinstance(value, (int, float)):
+		return {'type': 'number', 'value': value}
+	if isinstance(value, str):
+		return {'type': 'string', 'value': value}
+	if isinstance(value, (list, tuple)):
+		return {'type': 'array', 'value': [_local_value(item) for item in value]}
+	if isinstance(value, dict):
+		return {'type': 'object', 'value': [[str(key), _local_value(item)] for key, item in value.items()]}
+	raise TypeError(f'Cannot pass {type(value).__name__} to JavaScript')
+
+
+def _image_format(format: str, quality: int | None) -> dict[str, Any]:
+	image_format: dict[str, Any] = {'type': f'image/{format.lower()}'}
+	if quality is not None and format.lower() in ('jpeg', 'webp'):
+		image_format['quality'] = quality / 100
+	return image_format
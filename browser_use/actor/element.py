@@ -6,6 +6,9 @@ from typing import TYPE_CHECKING, Literal, Union
 from cdp_use.client import logger
 from typing_extensions import TypedDict
 
+from browser_use.actor.utils import describe_occluder, find_clickable_point, get_click_candidate_points
+from browser_use.browser.cdp_batch import result_or_none, send_pipelined
+
 if TYPE_CHECKING:
 	from cdp_use.cdp.dom.commands import (
 		DescribeNodeParameters,
@@ -81,8 +84,8 @@ class Element:
 
 	async def _get_remote_object_id(self) -> str | None:
 		"""Get remote object ID for this element."""
-		node_id = await self._get_node_id()
-		params: 'ResolveNodeParameters' = {'nodeId': node_id}
+		# Resolved from the backend node id directly, pushing the node to the frontend first would cost a round-trip
+		params: 'ResolveNodeParameters' = {'backendNodeId': self._backend_node_id}
 		result = await self._client.send.DOM.resolveNode(params, session_id=self._session_id)
 		object_id = result['object'].get('objectId', None)
 
@@ -99,23 +102,30 @@ class Element:
 		"""Click the element using the advanced watchdog implementation."""
 
 		try:
-			# Get viewport dimensions for visibility checks
-			layout_metrics = await self._client.send.Page.getLayoutMetrics(session_id=self._session_id)
+			# Get viewport dimensions for visibility checks, and scroll element into view BEFORE measuring it,
+			# so the click coordinates are current. Independent commands are pipelined, one round-trip each batch.
+			node = {'backendNodeId': self._backend_node_id}
+			layout_metrics, _ = await send_pipelined(
+				self._client, [('Page.getLayoutMetrics', None), ('DOM.scrollIntoViewIfNeeded', node)], self._session_id
+			)
+			if isinstance(layout_metrics, Exception):
+				raise layout_metrics
 			viewport_width = layout_metrics['layoutViewport']['clientWidth']
 			viewport_height = layout_metrics['layoutViewport']['clientHeight']
+			await asyncio.sleep(0.05)  # Wait for scroll to complete
+
+			# Measure the element and resolve it for the hit test in the same round-trip
+			content_quads_result, resolved = await send_pipelined(
+				self._client, [('DOM.getContentQuads', node), ('DOM.resolveNode', node)], self._session_id
+			)
+			object_id = ((result_or_none(resolved) or {}).get('object') or {}).get('objectId')
 
 			# Try multiple methods to get element geometry
 			quads = []
 
-			# Method 1: Try DOM.getContentQuads first (best for inline elements and complex layouts)
-			try:
-				content_quads_result = await self._client.send.DOM.getContentQuads(
-					params={'backendNodeId': self._backend_node_id}, session_id=self._session_id
-				)
-				if 'quads' in content_quads_result and content_quads_result['quads']:
-					quads = content_quads_result['quads']
-			except Exception:
-				pass
+			# Method 1: DOM.getContentQuads first (best for inline elements and complex layouts)
+			if (content_quads := result_or_none(content_quads_result)) and content_quads.get('quads'):
+				quads = content_quads['quads']
 
 			# Method 2: Fall back to DOM.getBoxModel
 			if not quads:
@@ -145,12 +155,7 @@ class Element:
 			# Method 3: Fall back to JavaScript getBoundingClientRect
 			if not quads:
 				try:
-					result = await self._client.send.DOM.resolveNode(
-						params={'backendNodeId': self._backend_node_id}, session_id=self._session_id
-					)
-					if 'object' in result and 'objectId' in result['object']:
-						object_id = result['object']['objectId']
-
+					if object_id:
 						# Get bounding rect via JavaScript
 						bounds_result = await self._client.send.Runtime.callFunctionOn(
 							params={
@@ -193,12 +198,8 @@ class Element:
 			# If we still don't have quads, fall back to JS click
 			if not quads:
 				try:
-					result = await self._client.send.DOM.resolveNode(
-						params={'backendNodeId': self._backend_node_id}, session_id=self._session_id
-					)
-					if 'object' not in result or 'objectId' not in result['object']:
+					if not object_id:
 						raise Exception('Failed to find DOM element based on backendNodeId, maybe page content changed?')
-					object_id = result['object']['objectId']
 
 					await self._client.send.Runtime.callFunctionOn(
 						params={
@@ -248,22 +249,28 @@ class Element:
 				# No visible quad found, use the first quad anyway
 				best_quad = quads[0]
 
-			# Calculate center point of the best quad
-			center_x = sum(best_quad[i] for i in range(0, 8, 2)) / 4
-			center_y = sum(best_quad[i] for i in range(1, 8, 2)) / 4
-
-			# Ensure click point is within viewport bounds
-			center_x = max(0, min(viewport_width - 1, center_x))
-			center_y = max(0, min(viewport_height - 1, center_y))
-
-			# Scroll element into view
+			# Find a point where the click reaches the element instead of an overlay covering it:
+			# the center of its visible area first, then points around it
+			candidate_points = get_click_candidate_points(best_quad, viewport_width, viewport_height)
 			try:
-				await self._client.send.DOM.scrollIntoViewIfNeeded(
-					params={'backendNodeId': self._backend_node_id}, session_id=self._session_id
+				click_point, occluder = await find_clickable_point(
+					self._client, self._session_id, self._backend_node_id, candidate_points, object_id=object_id
 				)
-				await asyncio.sleep(0.05)  # Wait for scroll to complete
 			except Exception:
-				pass
+				click_point, occluder = candidate_points[0], None
+
+			if click_point is None:
+				# Covered at every point: a mouse click would hit the overlay, so click via JavaScript instead
+				logger.debug(f'Element is covered by {describe_occluder(occluder)}, falling back to JavaScript click')
+				if not object_id:
+					raise Exception('Failed to find DOM element based on backendNodeId, maybe page content changed?')
+				await self._client.send.Runtime.callFunctionOn(
+					params={'functionDeclaration': 'function() { this.click(); }', 'objectId': object_id},
+					session_id=self._session_id,
+				)
+				await asyncio.sleep(0.05)
+				return
+			center_x, center_y = click_point
 
 			# Calculate modifier bitmask for CDP
 			modifier_value = 0
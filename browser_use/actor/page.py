@@ -21,7 +21,13 @@ if TYPE_CHECKING:
 	from cdp_use.cdp.input.commands import (
 		DispatchKeyEventParameters,
 	)
-	from cdp_use.cdp.page.commands import CaptureScreenshotParameters, NavigateParameters, NavigateToHistoryEntryParameters
+	from cdp_use.cdp.page.commands import (
+		AddScriptToEvaluateOnNewDocumentParameters,
+		CaptureScreenshotParameters,
+		NavigateParameters,
+		NavigateToHistoryEntryParameters,
+		RemoveScriptToEvaluateOnNewDocumentParameters,
+	)
 	from cdp_use.cdp.runtime.commands import EvaluateParameters
 	from cdp_use.cdp.target.commands import (
 		AttachToTargetParameters,
@@ -92,6 +98,24 @@ class Page:
 		session_id = await self._ensure_session()
 		await self._client.send.Page.reload(session_id=session_id)
 
+	async def add_init_script(self, script: str) -> str:
+		"""Evaluate a script in this target before any page script runs, on every future navigation.
+
+		The script also runs right away in the current document. Returns an identifier for remove_init_script().
+		"""
+		session_id = await self._ensure_session()
+
+		params: 'AddScriptToEvaluateOnNewDocumentParameters' = {'source': script, 'runImmediately': True}
+		result = await self._client.send.Page.addScriptToEvaluateOnNewDocument(params, session_id=session_id)
+		return result['identifier']
+
+	async def remove_init_script(self, identifier: str) -> None:
+		"""Stop evaluating a script added with add_init_script() on new documents."""
+		session_id = await self._ensure_session()
+
+		params: 'RemoveScriptToEvaluateOnNewDocumentParameters' = {'identifier': identifier}
+		await self._client.send.Page.removeScriptToEvaluateOnNewDocument(params, session_id=session_id)
+
 	async def get_element(self, backend_node_id: int) -> 'Element':
 		"""Get an element by its backend node ID."""
 		session_id = await self._ensure_session()
@@ -1,5 +1,7 @@
 """Utility functions for actor operations."""
 
+from typing import Any
+
 
 class Utils:
 	"""Utility functions for actor operations."""
@@ -174,3 +176,119 @@ def get_key_info(key: str) -> tuple[str, int | None]:
 	https://docs.microsoft.com/en-us/windows/win32/inputdev/virtual-key-codes
 	"""
 	return Utils.get_key_info(key)
+
+
+# Hit-tests a list of viewport points against `this` element and returns the index of the first point where a click
+# would reach it (the element itself, a descendant/ancestor, or an associated label/input), or -1 plus the occluder.
+CLICK_HIT_TEST_JS = """
+function(points) {
+	const getElementInfo = (el) => ({
+		tagName: el.tagName,
+		id: el.id || '',
+		className: typeof el.className === 'string' ? el.className : '',
+		textContent: (el.textContent || '').trim().substring(0, 100)
+	});
+
+	const isHitBy = (target, atPoint) => {
+		if (target === atPoint || target.contains(atPoint) || atPoint.contains(target)) return true;
+		// target is <input>, atPoint is its associated <label> (or child of that label)
+		if (target.tagName === 'INPUT' && target.id) {
+			const assocLabel = document.querySelector('label[for="' + CSS.escape(target.id) + '"]');
+			if (assocLabel && (assocLabel === atPoint || assocLabel.contains(atPoint))) return true;
+		}
+		// target is <input>, atPoint is inside a <label> ancestor that wraps the target
+		if (target.tagName === 'INPUT') {
+			let ancestor = atPoint;
+			for (let i = 0; i < 3 && ancestor; i++) {
+				if (ancestor.tagName === 'LABEL' && ancestor.contains(target)) return true;
+				ancestor = ancestor.parentElement;
+			}
+		}
+		// target is <label>, atPoint is the associated <input> or an input inside the label
+		if (target.tagName === 'LABEL' && atPoint.tagName === 'INPUT') {
+			if ((target.htmlFor && atPoint.id === target.htmlFor) || target.contains(atPoint)) return true;
+		}
+		return false;
+	};
+
+	let occluder = null;
+	for (let i = 0; i < points.length; i++) {
+		const atPoint = document.elementFromPoint(points[i][0], points[i][1]);
+		if (!atPoint) continue;
+		if (isHitBy(this, atPoint)) return { index: i, targetInfo: getElementInfo(this) };
+		if (!occluder) occluder = getElementInfo(atPoint);
+	}
+	return { index: -1, targetInfo: getElementInfo(this), occluderInfo: occluder };
+}
+"""
+
+
+def get_click_candidate_points(quad: list[float], viewport_width: float, viewport_height: float) -> list[tuple[float, float]]:
+	"""Points to try clicking an element at: the center of its visible area first, then inset points around it.
+
+	Used to click partially covered elements (sticky headers, cookie banners) where the center is occluded.
+	"""
+	xs = [quad[i] for i in range(0, 8, 2)]
+	ys = [quad[i] for i in range(1, 8, 2)]
+	min_x, max_x = max(0.0, min(xs)), min(viewport_width - 1, max(xs))
+	min_y, max_y = max(0.0, min(ys)), min(viewport_height - 1, max(ys))
+	if max_x < min_x or max_y < min_y:
+		# Element is outside the viewport: clamp its center into it
+		center_x = max(0.0, min(viewport_width - 1, sum(xs) / 4))
+		center_y = max(0.0, min(viewport_height - 1, sum(ys) / 4))
+		return [(center_x, center_y)]
+
+	width, height = max_x - min_x, max_y - min_y
+	fractions = [(0.5, 0.5), (0.5, 0.2), (0.5, 0.8), (0.2, 0.5), (0.8, 0.5), (0.2, 0.2), (0.8, 0.2), (0.2, 0.8), (0.8, 0.8)]
+	points: list[tuple[float, float]] = []
+	for fx, fy in fractions:
+		point = (min_x + width * fx, min_y + height * fy)
+		if point not in points:
+			points.append(point)
+	return points
+
+
+async def find_clickable_point(
+	cdp_client: Any,
+	session_id: str | None,
+	backend_node_id: int,
+	points: list[tuple[float, float]],
+	object_id: str | None = None,
+) -> tuple[tuple[float, float] | None, dict[str, Any] | None]:
+	"""Return the first of `points` where a click would hit the element, and info about the occluding element if none.
+
+	Pass the element's object_id if it is already resolved, saving a round-trip. Raises if the element can no longer
+	be resolved.
+	"""
+	if object_id is None:
+		result = await cdp_client.send.DOM.resolveNode(params={'backendNodeId': backend_node_id}, session_id=session_id)
+		object_id = result.get('object', {}).get('objectId')
+	if not object_id:
+		raise RuntimeError('Failed to find DOM element based on backendNodeId, maybe page content changed?')
+
+	hit_test = await cdp_client.send.Runtime.callFunctionOn(
+		params={
+			'objectId': object_id,
+			'functionDeclaration': CLICK_HIT_TEST_JS,
+			'arguments': [{'value': [list(point) for point in points]}],
+			'returnByValue': True,
+		},
+		session_id=session_id,
+	)
+	data = hit_test.get('result', {}).get('value') or {}
+	index = data.get('index', -1)
+	if 0 <= index < len(points):
+		return points[index], None
+	return None, data.get('occluderInfo')
+
+
+def describe_occluder(occluder: dict[str, Any] | None) -> str:
+	"""Short human readable description of the element covering a click target, e.g. <div id="cookie-banner">"""
+	if not occluder:
+		return 'another element'
+	description = f'<{occluder.get("tagName", "element").lower()}'
+	if occluder.get('id'):
+		description += f' id="{occluder["id"]}"'
+	elif occluder.get('className'):
+		description += f' class="{occluder["className"][:50]}"'
+	return description + '>'
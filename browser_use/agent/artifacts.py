@@ -0,0 +1,115 @@
+"""Export a self-contained bundle of an agent run for auditing: history, screenshots, files, result and LLM conversation."""
+
+import base64
+import json
+import shutil
+from collections.abc import Callable
+from pathlib import Path
+from typing import Any
+
+from browser_use.agent.views import AgentHistoryList
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.screenshots.service import ScreenshotStore
+
+
+def export_run_artifacts(
+	output_dir: str | Path,
+	task: str,
+	history: AgentHistoryList[Any],
+	file_system: FileSystem | None = None,
+	conversation_dir: str | Path | None = None,
+	sensitive_data: dict[str, str | dict[str, str]] | None = None,
+	as_zip: bool = False,
+	write_har: Callable[[Path], Any] | None = None,
+	redact: Callable[[str], str] | None = None,
+	screenshot_store: ScreenshotStore | None = None,
+) -> Path:
+	"""Write the artifacts of a run to output_dir and return the path of the bundle.
+
+	Layout:
+		steps.json       the agent history, screenshot paths point into screenshots/
+		result.json      task, final result, success, errors, judgement and token usage
+		screenshots/     step_<n>.png for every step that has a screenshot
+		files/           the files the agent wrote with its file system
+		conversation/    the LLM request and response of every step
+		network.har      the network activity of the run, when write_har is given (browser recording HAR)
+
+	With as_zip=True the directory is packed into output_dir.zip and removed.
+	redact is applied to every text written to steps.json, result.json and conversation/ (see Agent(redaction=...)).
+	Screenshots kept off the local disk (S3, GCS) are downloaded through screenshot_store.
+	"""
+	bundle_dir = Path(output_dir).expanduser().resolve()
+	bundle_dir.mkdir(parents=True, exist_ok=True)
+
+	# Screenshots live in the agent's temp directory or a remote store, copy them so the bundle is self-contained
+	steps = history.model_dump(sensitive_data=sensitive_data)
+	screenshots_dir = bundle_dir / 'screenshots'
+	remote_store = screenshot_store if screenshot_store is not None and not screenshot_store.is_local else None
+	for step_number, step in enumerate(steps['history'], start=1):
+		state = step.get('state') or {}
+		screenshot_path = state.get('screenshot_path')
+		if not screenshot_path:
+			continue
+		target = screenshots_dir / f'step_{step_number}{Path(screenshot_path).suffix or ".png"}'
+		if remote_store is not None:
+			screenshot_b64 = remote_store.get_screenshot_sync(screenshot_path)
+			if not screenshot_b64:
+				continue
+			screenshots_dir.mkdir(exist_ok=True)
+			target.write_bytes(base64.b64decode(screenshot_b64))
+		elif Path(screenshot_path).is_file():
+			screenshots_dir.mkdir(exist_ok=True)
+			shutil.copyfile(screenshot_path, target)
+		else:
+			continue
+		state['screenshot_path'] = str(target.relative_to(bundle_dir))
+	if redact is not None:
+		steps = _redact_json(steps, redact)
+	(bundle_dir / 'steps.json').write_text(json.dumps(steps, indent=2, ensure_ascii=False), encoding='utf-8')
+
+	result = {
+		'task': task,
+		'is_done': history.is_done(),
+		'is_successful': history.is_successful(),
+		'final_result': history.final_result(),
+		'errors': [error for error in history.errors() if error],
+		'judgement': history.judgement(),
+		'number_of_steps': history.number_of_steps(),
+		'total_duration_seconds': history.total_duration_seconds(),
+		'usage': history.usage.model_dump(mode='json') if history.usage else None,
+	}
+	if redact is not None:
+		result = _redact_json(result, redact)
+	(bundle_dir / 'result.json').write_text(json.dumps(result, indent=2, ensure_ascii=False), encoding='utf-8')
+
+	if file_system is not None and file_system.files:
+		files_dir = bundle_dir / 'files'
+		files_dir.mkdir(exist_ok=True)
+		for file_obj in file_system.files.values():
+			file_obj.sync_to_disk_sync(files_dir)
+
+	if conversation_dir is not None and Path(conversation_dir).is_dir():
+		shutil.copytree(conversation_dir, bundle_dir / 'conversation', dirs_exist_ok=True)
+		if redact is not None:
+			for path in (bundle_dir / 'conversation').rglob('*'):
+				if path.is_file():
+					path.write_text(redact(path.read_text(encoding='utf-8', errors='replace')), encoding='utf-8')
+
+	if write_har is not None:
+		write_har(bundle_dir / 'network.har')
+
+	if as_zip:
+		archive = shutil.make_archive(str(bundle_dir), 'zip', root_dir=bundle_dir)
+		shutil.rmtree(bundle_dir)
+		return Path(archive)
+	return bundle_dir
+
+
+def _redact_json(value: Any, redact: Callable[[str], str]) -> Any:
+	if isinstance(value, str):
+		return redact(value)
+	if isinstance(value, dict):
+		return {key: _redact_json(item, redact) for key, item in value.items()}
+	if isinstance(value, list):
+		return [_redact_json(item, redact) for item in value]
+	return value
@@ -0,0 +1,413 @@
+"""HTTP/WebSocket JSON API for driving agents from other processes and languages (e.g. a web dashboard)."""
+
+from __future__ import annotations
+
+import asyncio
+import json
+import logging
+import secrets
+import shutil
+import tempfile
+from collections import deque
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Any, Literal
+
+from aiohttp import WSMsgType, web
+from uuid_extensions import uuid7str
+
+from browser_use.agent.debug_server import _get_history_item, _screenshot_response, _serialize_step
+
+if TYPE_CHECKING:
+	from browser_use.agent.service import Agent
+	from browser_use.browser.session import BrowserSession
+	from browser_use.llm.base import BaseChatModel
+
+logger = logging.getLogger(__name__)
+
+TaskStatus = Literal['running', 'paused', 'done', 'failed', 'stopped']
+
+# BrowserProfile fields a client may set, anything touching the server's files, binaries or network setup
+# (user_data_dir, executable_path, args, cdp_url, proxy, downloads_path, extension_paths, ...) stays with the server
+SESSION_PROFILE_FIELDS = frozenset(
+	{
+		'headless',
+		'viewport',
+		'window_size',
+		'screen',
+		'device',
+		'device_scale_factor',
+		'is_mobile',
+		'has_touch',
+		'user_agent',
+		'locale',
+		'timezone_id',
+		'allowed_domains',
+		'prohibited_domains',
+		'block_ip_addresses',
+		'respect_robots_txt',
+		'max_agent_tabs',
+		'deterministic_rendering',
+		'highlight_elements',
+		'minimum_wait_page_load_time',
+		'wait_for_network_idle_page_load_time',
+		'wait_between_actions',
+	}
+)
+
+# Events kept per task for clients that connect later, older ones are dropped
+MAX_BUFFERED_EVENTS = 500
+
+
+@dataclass
+class _Session:
+	id: str
+	browser_session: BrowserSession
+	task_ids: list[str] = field(default_factory=list)
+
+
+@dataclass
+class _Task:
+	id: str
+	session_id: str
+	agent: Agent
+	status: TaskStatus = 'running'
+	error: str | None = None
+	events: deque[dict[str, Any]] = field(default_factory=lambda: deque(maxlen=MAX_BUFFERED_EVENTS))
+	subscribers: set[asyncio.Queue[dict[str, Any] | None]] = field(default_factory=set)
+	runner: asyncio.Task | None = None
+	artifacts_dir: Path | None = None  # temp dir of the exported bundle, removed with the session
+	artifacts_bundle: Path | None = None
+
+	def emit(self, event: dict[str, Any]) -> None:
+		self.events.append(event)
+		for queue in self.subscribers:
+			queue.put_nowait(event)
+
+	def summary(self) -> dict[str, Any]:
+		history = self.agent.history
+		return {
+			'task_id': self.id,
+			'session_id': self.session_id,
+			'task': self.agent.task,
+			'status': self.status,
+			'steps': len(history.history),
+			'is_done': history.is_done(),
+			'success': history.is_successful(),
+			'final_result': history.final_result(),
+			'error': self.error,
+		}
+
+
+class AgentControlServer:
+	"""Creates browser sessions and runs agent tasks on them on behalf of remote clients.
+
+	Routes (JSON in and out):
+		POST   /sessions                        create a browser session, body: {"profile": {SESSION_PROFILE_FIELDS}}
+		GET    /sessions                        sessions and their tasks
+		DELETE /sessions/{id}                   stop the session's task and close its browser
+		POST   /sessions/{id}/tasks             start a task, body: {"task": "...", "max_steps": 100, "model": "..."}
+		GET    /tasks/{id}                      status, step count and final result
+		POST   /tasks/{id}/pause|resume|stop    control a running task
+		GET    /tasks/{id}/events               WebSocket: past and future events, one JSON object per message
+		GET    /tasks/{id}/step/{n}/screenshot  screenshot of step n (1-based)
+		GET    /tasks/{id}/artifacts            zip of the run (history, screenshots, files), once the task ended
+
+	Events are {"type": "step", ...step as served by the debug server} after every step, {"type": "status",
+	"status": ...} on pause/resume, and a final {"type": "finished", ...task status}.
+
+	Tasks use the server's llm unless the request names a "model" (see browser_use.llm.models.get_llm_by_name,
+	API keys come from the environment). Sessions run a real browser with the server's permissions, so the
+	server binds to localhost and every request needs an "Authorization: Bearer <token>" header; a random token
+	is generated when none is passed (see .token). Request bodies must be application/json, and requests from
+	a web page (an Origin header) are only accepted from the server's own origin and allowed_origins.
+
+	Usage:
+		server = AgentControlServer(llm=ChatOpenAI(model='gpt-4.1-mini'), port=9243)
+		await server.start()
+	"""
+
+	def __init__(
+		self,
+		llm: BaseChatModel | None = None,
+		host: str = '127.0.0.1',
+		port: int = 9243,
+		token: str | None = None,
+		agent_kwargs: dict[str, Any] | None = None,
+		allowed_origins: list[str] | None = None,
+	):
+		self.llm = llm
+		self.host = host
+		self.port = port
+		self.token = token or secrets.token_urlsafe(24)
+		self.allowed_origins = [origin.rstrip('/') for origin in allowed_origins or []]
+		self.agent_kwargs = agent_kwargs or {}
+		self._sessions: dict[str, _Session] = {}
+		self._tasks: dict[str, _Task] = {}
+		self._runner: web.AppRunner | None = None
+
+	@property
+	def url(self) -> str:
+		return f'http://{self.host}:{self.port}'
+
+	@property
+	def is_running(self) -> bool:
+		return self._runner is not None
+
+	async def start(self) -> None:
+		if self._runner is not None:
+			return
+
+		app = web.Application(middlewares=[self._auth_middleware])
+		app.add_routes(
+			[
+				web.post('/sessions', self._handle_create_session),
+				web.get('/sessions', self._handle_sessions),
+				web.delete('/sessions/{session_id}', self._handle_close_session),
+				web.post('/sessions/{session_id}/tasks', self._handle_create_task),
+				web.get('/tasks/{task_id}', self._handle_task),
+				web.post('/tasks/{task_id}/{command:pause|resume|stop}', self._handle_task_command),
+				web.get('/tasks/{task_id}/events', self._handle_events),
+				web.get('/tasks/{task_id}/step/{step}/screenshot', self._handle_screenshot),
+				web.get('/tasks/{task_id}/artifacts', self._handle_artifacts),
+			]
+		)
+		runner = web.AppRunner(app, access_log=None)
+		await runner.setup()
+		await web.TCPSite(runner, self.host, self.port).start()
+		self._runner = runner
+
+		# Resolve the real port when bound to port 0
+		if runner.addresses:
+			self.port = runner.addresses[0][1]
+		logger.info(f'🛰️ Agent control server listening on {self.url}')
+
+	async def stop(self) -> None:
+		"""Stop all tasks, close all browsers and shut the server down."""
+		for session_id in list(self._sessions):
+			await self._close_session(session_id)
+		for task in self._tasks.values():
+			_remove_artifacts(task)
+		if self._runner is not None:
+			await self._runner.cleanup()
+			self._runner = None
+
+	async def serve_forever(self) -> None:
+		await self.start()
+		try:
+			await asyncio.Event().wait()
+		finally:
+			await self.stop()
+
+	# --- Handlers ---
+
+	@web.middleware
+	async def _auth_middleware(self, request: web.Request, handler) -> web.StreamResponse:
+		# Web pages can reach localhost too, a request (or WebSocket) they start carries their Origin
+		origin = request.headers.get('Origin')
+		if origin is not None and origin.rstrip('/') not in (self.url, *self.allowed_origins):
+			raise web.HTTPForbidden(text=f'Origin {origin} is not allowed')
+		if not secrets.compare_digest(request.headers.get('Authorization', ''), f'Bearer {self.token}'):
+			raise web.HTTPUnauthorized(text='Missing or wrong bearer token')
+		return await handler(request)
+
+	async def _handle_create_session(self, request: web.Request) -> web.Response:
+		from browser_use.browser import BrowserProfile, BrowserSession
+
+		body = await _read_json(request)
+		profile_fields = body.get('profile', {})
+		if not isinstance(profile_fields, dict):
+			raise web.HTTPBadRequest(text='"profile" must be a JSON object')
+		if rejected := sorted(set(profile_fields) - SESSION_PROFILE_FIELDS):
+			raise web.HTTPBadRequest(text=f'Profile fields not allowed: {", ".join(rejected)}')
+		try:
+			profile = BrowserProfile(**{**profile_fields, 'user_data_dir': None, 'keep_alive': True})
+		except Exception as e:
+			raise web.HTTPBadRequest(text=f'Invalid profile: {e}')
+		browser_session = BrowserSession(browser_profile=profile)
+		await browser_session.start()
+		session = _Session(id=uuid7str(), browser_session=browser_session)
+		self._sessions[session.id] = session
+		return web.json_response({'session_id': session.id}, status=201)
+
+	async def _handle_sessions(self, request: web.Request) -> web.Response:
+		sessions = [{'session_id': session.id, 'task_ids': session.task_ids} for session in self._sessions.values()]
+		return web.json_response({'sessions': sessions})
+
+	async def _handle_close_session(self, request: web.Request) -> web.Response:
+		session = self._get_session(request)
+		await self._close_session(session.id)
+		return web.json_response({'session_id': session.id, 'closed': True})
+
+	async def _handle_create_task(self, request: web.Request) -> web.Response:
+		from browser_use.agent.service import Agent
+
+		session = self._get_session(request)
+		if any(self._tasks[task_id].status in ('running', 'paused') for task_id in session.task_ids):
+			raise web.HTTPConflict(text=f'Session {session.id} already runs a task')
+		body = await _read_json(request)
+		task_text = body.get('task')
+		if not isinstance(task_text, str) or not task_text.strip():
+			raise web.HTTPBadRequest(text='"task" is required')
+		max_steps = body.get('max_steps', 100)
+		if not isinstance(max_steps, int) or max_steps < 1:
+			raise web.HTTPBadRequest(text='"max_steps" must be a positive integer')
+
+		llm = self.llm
+		if body.get('model'):
+			from browser_use.llm.models import get_llm_by_name
+
+			try:
+				llm = get_llm_by_name(body['model'])
+			except Exception as e:
+				raise web.HTTPBadRequest(text=f'Unknown model {body["model"]!r}: {e}')
+		if llm is None:
+			raise web.HTTPBadRequest(text='The server has no default llm, pass "model"')
+
+		agent = Agent(task=task_text, llm=llm, browser_session=session.browser_session, **self.agent_kwargs)
+		task = _Task(id=uuid7str(), session_id=session.id, agent=agent)
+		self._tasks[task.id] = task
+		session.task_ids.append(task.id)
+		task.runner = asyncio.create_task(self._run_task(task, max_steps), name=f'control_server_task_{task.id[-4:]}')
+		return web.json_response(task.summary(), status=201)
+
+	async def _handle_task(self, request: web.Request) -> web.Response:
+		return web.json_response(self._get_task(request).summary())
+
+	async def _handle_task_command(self, request: web.Request) -> web.Response:
+		task = self._get_task(request)
+		command = request.match_info['command']
+		if task.status not in ('running', 'paused'):
+			raise web.HTTPConflict(text=f'Task {task.id} is {task.status}')
+		if command == 'pause' and task.status == 'running':
+			task.agent.pause()
+			task.status = 'paused'
+			task.emit({'type': 'status', 'status': 'paused'})
+		elif command == 'resume' and task.status == 'paused':
+			task.agent.resume()
+			task.status = 'running'
+			task.emit({'type': 'status', 'status': 'running'})
+		elif command == 'stop':
+			task.agent.stop()
+		return web.json_response(task.summary())
+
+	async def _handle_events(self, request: web.Request) -> web.WebSocketResponse:
+		task = self._get_task(request)
+		ws = web.WebSocketResponse(heartbeat=30)
+		await ws.prepare(request)
+
+		queue: asyncio.Queue[dict[str, Any] | None] = asyncio.Queue()
+		for event in task.events:
+			queue.put_nowait(event)
+		if task.runner is not None and task.runner.done():
+			queue.put_nowait(None)
+		else:
+			task.subscribers.add(queue)
+
+		async def close_on_client_disconnect() -> None:
+			async for message in ws:
+				if message.type in (WSMsgType.CLOSE, WSMsgType.ERROR):
+					break
+			queue.put_nowait(None)
+
+		reader = asyncio.create_task(close_on_client_disconnect())
+		try:
+			while (event := await queue.get()) is not None:
+				await ws.send_str(json.dumps(event, default=str))
+		finally:
+			task.subscribers.discard(queue)
+			reader.cancel()
+			await ws.close()
+		return ws
+
+	async def _handle_screenshot(self, request: web.Request) -> web.Response:
+		task = self._get_task(request)
+		step, item = _get_history_item(request, task.agent)
+		screenshot_b64 = await asyncio.to_thread(item.state.get_screenshot, task.agent.screenshot_service)
+		return _screenshot_response(step, screenshot_b64)
+
+	async def _handle_artifacts(self, request: web.Request) -> web.FileResponse:
+		task = self._get_task(request)
+		if task.status in ('running', 'paused'):
+			raise web.HTTPConflict(text=f'Task {task.id} is still {task.status}')
+		# The run is over, so the bundle is exported once and served again on later requests
+		if task.artifacts_bundle is None:
+			task.artifacts_dir = Path(tempfile.mkdtemp(prefix='browser_use_artifacts_'))
+			task.artifacts_bundle = await asyncio.to_thread(
+				task.agent.export_artifacts, task.artifacts_dir / f'task_{task.id}', True
+			)
+		bundle = task.artifacts_bundle
+		return web.FileResponse(
+			bundle, headers={'Content-Type': 'application/zip', 'Content-Disposition': f'attachment; filename="{bundle.name}"'}
+		)
+
+	# --- Helpers ---
+
+	async def _run_task(self, task: _Task, max_steps: int) -> None:
+		async def on_step_end(agent: Agent) -> None:
+			step = len(agent.history.history)
+			if step:
+				event = {'type': 'step', **_serialize_step(task.id, step, agent.history.history[-1])}
+				if event['screenshot_url']:
+					event['screenshot_url'] = f'/tasks/{task.id}/step/{step}/screenshot'
+				task.emit(event)
+
+		try:
+			await task.agent.run(max_steps=max_steps, on_step_end=on_step_end)
+			task.status = 'stopped' if task.agent.state.stopped else 'done'
+		except Exception as e:
+			task.status = 'failed'
+			task.error = f'{type(e).__name__}: {e}'
+			logger.error(f'Control server task {task.id[-4:]} failed: {task.error}')
+		finally:
+			task.emit({'type': 'finished', **task.summary()})
+			for queue in task.subscribers:
+				queue.put_nowait(None)
+
+	async def _close_session(self, session_id: str) -> None:
+		session = self._sessions.pop(session_id)
+		for task_id in session.task_ids:
+			task = self._tasks[task_id]
+			if task.runner is not None and not task.runner.done():
+				task.agent.stop()
+				try:
+					await asyncio.wait_for(asyncio.shield(task.runner), timeout=10)
+				except Exception:
+					task.runner.cancel()
+			_remove_artifacts(task)
+		await session.browser_session.kill()
+
+	def _get_session(self, request: web.Request) -> _Session:
+		session_id = request.match_info['session_id']
+		session = self._sessions.get(session_id)
+		if session is None:
+			raise web.HTTPNotFound(text=f'Unknown session {session_id}')
+		return session
+
+	def _get_task(self, request: web.Request) -> _Task:
+		task_id = request.match_info['task_id']
+		task = self._tasks.get(task_id)
+		if task is None:
+			raise web.HTTPNotFound(text=f'Unknown task {task_id}')
+		return task
+
+
+def _remove_artifacts(task: _Task) -> None:
+	if task.artifacts_dir is not None:
+		shutil.rmtree(task.artifacts_dir, ignore_errors=True)
+		task.artifacts_dir = task.artifacts_bundle = None
+
+
+async def _read_json(request: web.Request) -> dict[str, Any]:
+	if not request.can_read_body:
+		return {}
+	# HTML forms can post text/plain or form data cross-site without a preflight, JSON they can't
+	if request.content_type != 'application/json':
+		raise web.HTTPUnsupportedMediaType(text='Body must be application/json')
+	try:
+		body = await request.json()
+	except json.JSONDecodeError:
+		raise web.HTTPBadRequest(text='Body must be JSON')
+	if not isinstance(body, dict):
+		raise web.HTTPBadRequest(text='Body must be a JSON object')
+	return body
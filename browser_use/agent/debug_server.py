@@ -0,0 +1,256 @@
+"""Optional HTTP server exposing per-step agent state, for inspecting live runs in a browser."""
+
+from __future__ import annotations
+
+import asyncio
+import base64
+import html
+import json
+import logging
+import secrets
+import weakref
+from typing import TYPE_CHECKING, Any
+
+from aiohttp import web
+
+if TYPE_CHECKING:
+	from browser_use.agent.service import Agent
+	from browser_use.agent.views import AgentHistory
+
+logger = logging.getLogger(__name__)
+
+_LOOPBACK_HOSTS = ('127.0.0.1', 'localhost', '::1')
+_WILDCARD_HOSTS = ('0.0.0.0', '::', '')
+_TOKEN_COOKIE = 'browser_use_debug_token'
+
+
+class AgentDebugServer:
+	"""Serves each step's state text, screenshot, model output and action results while agents run.
+
+	Routes:
+		GET /debug/runs                           runs registered with this server
+		GET /debug/run/{id}                       run summary with one entry per step
+		GET /debug/run/{id}/step/{n}              step n (1-based): state text, model output, action results
+		GET /debug/run/{id}/step/{n}/screenshot   screenshot of step n (PNG, JPEG or WebP)
+
+	Responses are HTML when requested by a browser (Accept: text/html) and JSON otherwise.
+	Step state contains page content and model output, so the server binds to localhost by default and every
+	request needs the token: an "Authorization: Bearer <token>" header, or ?token=<token> once, which sets a
+	cookie for the following pages. A random token is generated when none is passed (see .token, run_url()
+	includes it). Requests must name the server's own host in the Host header, so a page that rebinds its DNS
+	name to 127.0.0.1 can't read them. Binding to any other host than localhost needs an explicit token.
+
+	Usage:
+		agent = Agent(task=..., llm=llm, debug_server=True)  # http://127.0.0.1:9242/debug/runs
+		# or share one server between agents and keep it running after agent.run() returns
+		server = AgentDebugServer(port=9000)
+		agent = Agent(task=..., llm=llm, debug_server=server)
+	"""
+
+	def __init__(self, host: str = '127.0.0.1', port: int = 9242, token: str | None = None):
+		if host not in _LOOPBACK_HOSTS and not token:
+			raise ValueError(f'Refusing to serve agent state on {host or "all interfaces"} without a token, pass token=...')
+		self.host = host
+		self.port = port
+		self.token = token or secrets.token_urlsafe(24)
+		self._runs: weakref.WeakValueDictionary[str, Agent] = weakref.WeakValueDictionary()
+		self._runner: web.AppRunner | None = None
+
+	@property
+	def url(self) -> str:
+		return f'http://{self.host}:{self.port}'
+
+	@property
+	def is_running(self) -> bool:
+		return self._runner is not None
+
+	def register(self, agent: Agent) -> str:
+		"""Expose an agent's steps under /debug/run/{agent.id}. Agents are held weakly."""
+		self._runs[agent.id] = agent
+		return agent.id
+
+	def run_url(self, run_id: str) -> str:
+		return f'{self.url}/debug/run/{run_id}?token={self.token}'
+
+	async def start(self) -> None:
+		if self._runner is not None:
+			return
+
+		app = web.Application(middlewares=[self._auth_middleware])
+		app.add_routes(
+			[
+				web.get('/debug/runs', self._handle_runs),
+				web.get('/debug/run/{run_id}', self._handle_run),
+				web.get('/debug/run/{run_id}/step/{step}', self._handle_step),
+				web.get('/debug/run/{run_id}/step/{step}/screenshot', self._handle_screenshot),
+			]
+		)
+		runner = web.AppRunner(app, access_log=None)
+		await runner.setup()
+		await web.TCPSite(runner, self.host, self.port).start()
+		self._runner = runner
+
+		# Resolve the real port when bound to port 0
+		if runner.addresses:
+			self.port = runner.addresses[0][1]
+		logger.info(f'🐞 Agent debug server listening on {self.url}/debug/runs?token={self.token}')
+
+	async def stop(self) -> None:
+		if self._runner is None:
+			return
+		await self._runner.cleanup()
+		self._runner = None
+
+	@web.middleware
+	async def _auth_middleware(self, request: web.Request, handler) -> web.StreamResponse:
+		# A page whose DNS name was rebound to this address sends its own name in the Host header
+		if self.host not in _WILDCARD_HOSTS and request.url.host not in (*_LOOPBACK_HOSTS, self.host):
+			raise web.HTTPForbidden(text=f'Host {request.host} is not allowed')
+		authorized = secrets.compare_digest(request.headers.get('Authorization', ''), f'Bearer {self.token}')
+		authorized = authorized or secrets.compare_digest(request.cookies.get(_TOKEN_COOKIE, ''), self.token)
+		from_query = not authorized and secrets.compare_digest(request.query.get('token', ''), self.token)
+		if not authorized and not from_query:
+			raise web.HTTPUnauthorized(text='Missing or wrong token')
+		response = await handler(request)
+		if from_query:
+			# Remember the token so the links and screenshots of the HTML pages work without it
+			response.set_cookie(_TOKEN_COOKIE, self.token, path='/debug', httponly=True, samesite='Strict')
+		return response
+
+	# --- Handlers ---
+
+	async def _handle_runs(self, request: web.Request) -> web.Response:
+		runs = [
+			{'id': run_id, 'task': agent.task, 'steps': len(agent.history.history), 'href': f'/debug/run/{run_id}'}
+			for run_id, agent in list(self._runs.items())
+		]
+		if _wants_html(request):
+			items = ''.join(
+				f'<li><a href="{run["href"]}">{html.escape(run["id"])}</a> ({run["steps"]} steps): '
+				f'{html.escape(run["task"][:200])}</li>'
+				for run in runs
+			)
+			return _html_response('Agent runs', f'<ul>{items}</ul>')
+		return web.json_response({'runs': runs})
+
+	async def _handle_run(self, request: web.Request) -> web.Response:
+		run_id, agent = self._get_agent(request)
+		steps = [
+			{
+				'step': n,
+				'url': item.state.url,
+				'actions': [next(iter(action.model_dump(exclude_unset=True)), 'unknown') for action in item.model_output.action]
+				if item.model_output
+				else [],
+				'errors': [result.error for result in item.result if result.error],
+				'href': f'/debug/run/{run_id}/step/{n}',
+			}
+			for n, item in enumerate(agent.history.history, start=1)
+		]
+		data = {
+			'id': run_id,
+			'task': agent.task,
+			'n_steps': len(steps),
+			'is_done': agent.history.is_done(),
+			'steps': steps,
+		}
+		if _wants_html(request):
+			items = ''.join(
+				f'<li><a href="{step["href"]}">Step {step["step"]}</a> {html.escape(", ".join(step["actions"]))} '
+				f'<small>{html.escape(step["url"])}</small>{" ❌" if step["errors"] else ""}</li>'
+				for step in steps
+			)
+			return _html_response(f'Run {run_id}', f'<p>{html.escape(agent.task)}</p><ol>{items}</ol>')
+		return web.json_response(data)
+
+	async def _handle_step(self, request: web.Request) -> web.Response:
+		run_id, agent = self._get_agent(request)
+		step, item = _get_history_item(request, agent)
+		data = _serialize_step(run_id, step, item)
+		if _wants_html(request):
+			return _html_response(f'Run {run_id} - step {step}', _render_step_html(data, len(agent.history.history)))
+		return web.json_response(data, dumps=lambda obj: json.dumps(obj, default=str))
+
+	async def _handle_screenshot(self, request: web.Request) -> web.Response:
+		_, agent = self._get_agent(request)
+		step, item = _get_history_item(request, agent)
+		screenshot_b64 = await asyncio.to_thread(item.state.get_screenshot, agent.screenshot_service)
+		return _screenshot_response(step, screenshot_b64)
+
+	def _get_agent(self, request: web.Request) -> tuple[str, Agent]:
+		run_id = request.match_info['run_id']
+		agent = self._runs.get(run_id)
+		if agent is None:
+			raise web.HTTPNotFound(text=f'Unknown run {run_id}')
+		return run_id, agent
+
+
+def _get_history_item(request: web.Request, agent: Agent) -> tuple[int, AgentHistory]:
+	try:
+		step = int(request.match_info['step'])
+	except ValueError:
+		raise web.HTTPBadRequest(text='Step must be an integer')
+	history = agent.history.history
+	if not 1 <= step <= len(history):
+		raise web.HTTPNotFound(text=f'Step {step} not found, run has {len(history)} steps')
+	return step, history[step - 1]
+
+
+def _screenshot_response(step: int, screenshot_b64: str | None) -> web.Response:
+	"""The screenshot with the content type of its actual format, screenshots are not always PNG."""
+	if not screenshot_b64:
+		raise web.HTTPNotFound(text=f'Step {step} has no screenshot')
+	data = base64.b64decode(screenshot_b64)
+	if data.startswith(b'\xff\xd8\xff'):
+		content_type = 'image/jpeg'
+	elif data[:4] == b'RIFF' and data[8:12] == b'WEBP':
+		content_type = 'image/webp'
+	else:
+		content_type = 'image/png'
+	return web.Response(body=data, content_type=content_type)
+
+
+def _serialize_step(run_id: str, step: int, item: AgentHistory) -> dict[str, Any]:
+	return {
+		'run_id': run_id,
+		'step': step,
+		'url': item.state.url,
+		'title': item.state.title,
+		'state_message': item.state_message,
+		'model_output': item.model_output.model_dump(mode='json', exclude_none=True) if item.model_output else None,
+		'results': [result.model_dump(mode='json', exclude_none=True) for result in item.result],
+		'metadata': item.metadata.model_dump(mode='json') if item.metadata else None,
+		'screenshot_url': f'/debug/run/{run_id}/step/{step}/screenshot' if item.state.screenshot_path else None,
+	}
+
+
+def _wants_html(request: web.Request) -> bool:
+	return 'text/html' in request.headers.get('Accept', '')
+
+
+def _html_response(title: str, body: str) -> web.Response:
+	page = (
+		f'<!DOCTYPE html><html><head><meta charset="utf-8"><title>{html.escape(title)}</title>'
+		'<style>body{font-family:sans-serif;margin:2em}pre{background:#f4f4f4;padding:1em;white-space:pre-wrap}'
+		'img{max-width:100%;border:1px solid #ccc}</style>'
+		f'</head><body><h1>{html.escape(title)}</h1>{body}</body></html>'
+	)
+	return web.Response(text=page, content_type='text/html')
+
+
+def _render_step_html(data: dict[str, Any], n_steps: int) -> str:
+	step = data['step']
+	base = f'/debug/run/{data["run_id"]}'
+	nav = [f'<a href="{base}">all steps</a>']
+	if step > 1:
+		nav.insert(0, f'<a href="{base}/step/{step - 1}">← step {step - 1}</a>')
+	if step < n_steps:
+		nav.append(f'<a href="{base}/step/{step + 1}">step {step + 1} →</a>')
+
+	parts = [f'<p>{" | ".join(nav)}</p>', f'<p><b>URL:</b> {html.escape(data["url"] or "")}</p>']
+	if data['screenshot_url']:
+		parts.append(f'<img src="{data["screenshot_url"]}" alt="screenshot of step {step}">')
+	for title, key in (('Model output', 'model_output'), ('Action results', 'results'), ('Metadata', 'metadata')):
+		parts.append(f'<h2>{title}</h2><pre>{html.escape(json.dumps(data[key], indent=2, default=str))}</pre>')
+	parts.append(f'<h2>State sent to the model</h2><pre>{html.escape(data["state_message"] or "(not recorded)")}</pre>')
+	return ''.join(parts)
@@ -14,6 +14,8 @@ from browser_use.config import CONFIG
 if TYPE_CHECKING:
 	from PIL import Image, ImageFont
 
+	from browser_use.screenshots.service import ScreenshotStore
+
 logger = logging.getLogger(__name__)
 
 
@@ -46,8 +48,12 @@ def create_history_gif(
 	goal_font_size: int = 44,
 	margin: int = 40,
 	line_spacing: float = 1.5,
+	screenshot_store: ScreenshotStore | None = None,
 ) -> None:
-	"""Create a GIF from the agent's history with overlaid task and goal text."""
+	"""Create a GIF from the agent's history with overlaid task and goal text.
+
+	screenshot_store: the store the agent kept its screenshots in, needed when they are not on the local disk (S3, GCS).
+	"""
 	if not history.history:
 		logger.warning('No history to create GIF from')
 		return
@@ -62,7 +68,7 @@ def create_history_gif(
 		return
 
 	# Get all screenshots from history (including None placeholders)
-	screenshots = history.screenshots(return_none_if_not_screenshot=True)
+	screenshots = history.screenshots(return_none_if_not_screenshot=True, screenshot_store=screenshot_store)
 
 	if not screenshots:
 		logger.warning('No screenshots found in history')
@@ -138,7 +144,7 @@ def create_history_gif(
 		# Find the first non-placeholder screenshot for the task frame
 		first_real_screenshot = None
 		for item in history.history:
-			screenshot_b64 = item.state.get_screenshot()
+			screenshot_b64 = item.state.get_screenshot(screenshot_store)
 			if screenshot_b64 and screenshot_b64 != PLACEHOLDER_4PX_SCREENSHOT:
 				first_real_screenshot = screenshot_b64
 				break
@@ -49,6 +49,7 @@ def construct_judge_messages(
 	max_images: int = 10,
 	ground_truth: str | None = None,
 	use_vision: bool | Literal['auto'] = True,
+	screenshots: list[str] | None = None,
 ) -> list[BaseMessage]:
 	"""
 	Construct messages for judge evaluation of agent trace.
@@ -60,6 +61,7 @@ def construct_judge_messages(
 		screenshot_paths: List of screenshot file paths
 		max_images: Maximum number of screenshots to include
 		ground_truth: Optional ground truth answer or criteria that must be satisfied for success
+		screenshots: Base64 screenshots to use instead of reading screenshot_paths from disk
 
 	Returns:
 		List of messages for LLM judge evaluation
@@ -74,10 +76,13 @@ def construct_judge_messages(
 	if use_vision is not False:
 		# Select last N screenshots
 		selected_screenshots = screenshot_paths[-max_images:] if len(screenshot_paths) > max_images else screenshot_paths
+		if screenshots is not None:
+			encoded_screenshots = screenshots[-max_images:]
+		else:
+			encoded_screenshots = [_encode_image(img_path) for img_path in selected_screenshots]
 
 		# Encode screenshots
-		for img_path in selected_screenshots:
-			encoded = _encode_image(img_path)
+		for encoded in encoded_screenshots:
 			if encoded:
 				encoded_images.append(
 					ContentPartImageParam(
@@ -183,6 +188,7 @@ Respond with EXACTLY this JSON structure (no additional text before or after):
 	"reasoning": "Breakdown of user task into key points. Detailed analysis covering: what went well, what didn't work, trajectory quality assessment, tool usage evaluation, output quality review, and overall user satisfaction prediction.",
 	"verdict": true or false,
 	"failure_reason": "Max 5 sentences explanation of why the task was not completed successfully in case of failure. If verdict is true, use an empty string.",
+	"missing_steps": ["Parts of the task the agent did not do or did not verify, one short item each. Empty list if verdict is true."],
 	"impossible_task": true or false,
 	"reached_captcha": true or false
 }}
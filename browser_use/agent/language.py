@@ -0,0 +1,135 @@
+"""
+Output language for the agent's reasoning and final answer, see Agent(language=...).
+
+The check is a cheap heuristic, not a language detector: languages with their own script are recognized by the share
+of letters in that script, a few Latin-script languages by their most common words. Anything else is never rejected.
+"""
+
+import re
+
+# Language names and codes mapped to a canonical name
+_ALIASES: dict[str, str] = {
+	'en': 'english',
+	'de': 'german',
+	'deutsch': 'german',
+	'fr': 'french',
+	'français': 'french',
+	'francais': 'french',
+	'es': 'spanish',
+	'español': 'spanish',
+	'espanol': 'spanish',
+	'pt': 'portuguese',
+	'português': 'portuguese',
+	'portugues': 'portuguese',
+	'it': 'italian',
+	'italiano': 'italian',
+	'nl': 'dutch',
+	'nederlands': 'dutch',
+	'zh': 'chinese',
+	'中文': 'chinese',
+	'ja': 'japanese',
+	'日本語': 'japanese',
+	'ko': 'korean',
+	'한국어': 'korean',
+	'ru': 'russian',
+	'русский': 'russian',
+	'uk': 'ukrainian',
+	'bg': 'bulgarian',
+	'ar': 'arabic',
+	'fa': 'persian',
+	'farsi': 'persian',
+	'he': 'hebrew',
+	'el': 'greek',
+	'hi': 'hindi',
+	'th': 'thai',
+}
+
+_HAN = '一-鿿㐀-䶿'
+_SCRIPTS: dict[str, str] = {
+	'chinese': _HAN,
+	'japanese': f'぀-ヿ{_HAN}',
+	'korean': '가-힯ᄀ-ᇿ㄰-㆏',
+	'russian': 'Ѐ-ӿ',
+	'ukrainian': 'Ѐ-ӿ',
+	'bulgarian': 'Ѐ-ӿ',
+	'arabic': '؀-ۿ',
+	'persian': '؀-ۿ',
+	'hebrew': '֐-׿',
+	'greek': 'Ͱ-Ͽ',
+	'hindi': 'ऀ-ॿ',
+	'thai': '฀-๿',
+}
+_LATIN = 'a-zA-ZÀ-ɏ'
+_LATIN_LETTER = re.compile(f'[{_LATIN}]')
+
+_STOPWORDS: dict[str, frozenset[str]] = {
+	'english': frozenset('the and is are of to in for with that this was on it not from have'.split()),
+	'german': frozenset('der die das und ist nicht mit für auf den dem ein eine sind von zu wurde'.split()),
+	'french': frozenset('le la les et est des une pour dans que pas sur avec du sont été'.split()),
+	'spanish': frozenset('el la los las y es de que en para con una por del son está'.split()),
+	'portuguese': frozenset('o a os as e é de que em para com uma não do da são'.split()),
+	'italian': frozenset('il lo la gli le e è di che per con una non del della sono'.split()),
+	'dutch': frozenset('de het een en is van dat niet met voor op zijn er wordt'.split()),
+}
+
+_MIN_LETTERS = 10  # Shorter texts (numbers, names, URLs) are never rejected
+_MIN_SCRIPT_SHARE = 0.3  # Share of letters that must be in the language's own script
+_MIN_WORDS = 12  # Latin-script texts need this many words before common words are compared
+
+
+def normalize_language(language: str) -> str:
+	"""Canonical lowercase name for a language name or code, e.g. 'de-DE' -> 'german', 'Spanish' -> 'spanish'."""
+	key = language.strip().lower()
+	if key in _ALIASES:
+		return _ALIASES[key]
+	base = re.split(r'[-_]', key)[0]
+	return _ALIASES.get(base, key)
+
+
+def text_matches_language(text: str, language: str) -> bool | None:
+	"""Whether text looks written in language. None if the text is too short or the language is not recognized."""
+	name = normalize_language(language)
+	letters = re.findall(r'[^\W\d_]', text)
+	if len(letters) < _MIN_LETTERS:
+		return None
+
+	if name in _SCRIPTS:
+		script = re.compile(f'[{_SCRIPTS[name]}]')
+		in_script = sum(1 for letter in letters if script.match(letter))
+		return in_script / len(letters) >= _MIN_SCRIPT_SHARE
+
+	if name not in _STOPWORDS:
+		return None
+	latin = sum(1 for letter in letters if _LATIN_LETTER.match(letter))
+	if latin / len(letters) < 0.5:
+		return False
+	words = re.findall(rf'[{_LATIN}]+', text.lower())
+	if len(words) < _MIN_WORDS:
+		return None
+	hits = {lang: sum(1 for word in words if word in stopwords) for lang, stopwords in _STOPWORDS.items()}
+	target_hits = hits.pop(name)
+	best_other = max(hits.values())
+	# Only reject when another language clearly dominates, quoted names and data are often in English
+	return not (best_other >= 3 and best_other >= 2 * max(target_hits, 1))
+
+
+def language_instructions(language: str) -> str:
+	"""System prompt section replacing the default "respond in the user's language" settings."""
+	return (
+		'<language_settings>\n'
+		f'- Working language: **{language}**\n'
+		f'- Write thinking, evaluation_previous_goal, memory, next_goal and the final done text in {language}, '
+		'regardless of the language of the task, the website or error messages\n'
+		'- Keep quoted page content, names, URLs and code as they are\n'
+		'</language_settings>'
+	)
+
+
+def language_reminder(language: str) -> str:
+	"""Appended to error feedback, which is always in English."""
+	return f'(Errors are reported in English, keep writing in {language}.)'
+
+
+def language_rejection(language: str) -> str:
+	"""Error fed back when the final done text is not written in language."""
+	return f'The final answer must be written in {language}. Call done again with the same result, translated to {language}.'
@@ -0,0 +1,125 @@
+"""
+Incremental element lists for the state message, see Agent(dom_diff_mode=True).
+
+Only the latest state message is kept in the conversation, so a diff can't simply leave unchanged elements out: they
+are listed in a compact one line form (index, tag and a short label) while new and changed elements keep their full
+representation. Elements are matched by their index, which stays the same for a DOM node across steps.
+"""
+
+import re
+from dataclasses import dataclass, field
+
+from pydantic import BaseModel, Field
+
+_ELEMENT_LINE = re.compile(r'^(\t*)((?:\|SHADOW\((?:open|closed)\)\|)?)\*?((?:\|scroll element)?)\[(\d+)\](.*)$')
+_TAG = re.compile(r'^<([\w-]+)')
+_LABEL_LENGTH = 40
+_MAX_NEW_TEXT_LINES = 30
+
+
+@dataclass
+class _ElementBlock:
+	index: int
+	head: str  # element line without indentation and new marker
+	text: list[str] = field(default_factory=list)  # text lines nested under the element
+
+	@property
+	def signature(self) -> str:
+		return '\n'.join([self.head, *self.text])
+
+	def full(self) -> str:
+		return '\n'.join([self.head, *(f'\t{line}' for line in self.text)])
+
+	def compact(self) -> str:
+		rest = self.head.split(f'[{self.index}]', 1)[1]
+		tag_match = _TAG.match(rest)
+		tag = tag_match.group(1) if tag_match else ''
+		label = ' '.join(self.text) or rest
+		if len(label) > _LABEL_LENGTH:
+			label = label[: _LABEL_LENGTH - 3] + '...'
+		return f'[{self.index}]<{tag}> {label}' if tag else f'[{self.index}] {label}'
+
+
+def _depth(line: str) -> int:
+	return len(line) - len(line.lstrip('\t'))
+
+
+def parse_elements(elements_text: str) -> tuple[dict[int, _ElementBlock], list[str]]:
+	"""Split a serialized DOM into interactive element blocks (in page order) and the page text outside of them."""
+	elements: dict[int, _ElementBlock] = {}
+	page_text: list[str] = []
+	current: _ElementBlock | None = None
+	current_depth = 0
+	for line in elements_text.split('\n'):
+		if not line.strip():
+			continue
+		match = _ELEMENT_LINE.match(line)
+		if match:
+			depth, shadow, scroll, index, rest = match.groups()
+			current = _ElementBlock(index=int(index), head=f'{shadow}{scroll}[{index}]{rest}')
+			current_depth = len(depth)
+			elements[current.index] = current
+		elif current is not None and _depth(line) > current_depth:
+			current.text.append(line.strip())
+		else:
+			current = None
+			page_text.append(line.strip())
+	return elements, page_text
+
+
+class DOMDiffState(BaseModel):
+	"""What the last state message showed, to diff the next one against."""
+
+	elements: dict[int, str] = Field(default_factory=dict)  # element index -> signature
+	page_text: list[str] = Field(default_factory=list)
+	url: str | None = None
+	steps_since_full: int = 0
+
+	def render(self, elements_text: str, url: str, full_refresh_every: int) -> tuple[str, str | None]:
+		"""Element list for this step and a header describing the diff, header is None when the full list is sent.
+
+		The full list is sent on the first step, after navigating to another URL and every full_refresh_every steps.
+		"""
+		elements, page_text = parse_elements(elements_text)
+		full = (
+			self.url is None
+			or url != self.url
+			or not self.elements
+			or (full_refresh_every > 0 and self.steps_since_full + 1 >= full_refresh_every)
+		)
+		previous_elements, previous_text = self.elements, set(self.page_text)
+		self.elements = {index: block.signature for index, block in elements.items()}
+		self.page_text = page_text
+		self.url = url
+		if full:
+			self.steps_since_full = 0
+			return elements_text, None
+		self.steps_since_full += 1
+
+		new = [block for index, block in elements.items() if index not in previous_elements]
+		changed = [
+			block
+			for index, block in elements.items()
+			if index in previous_elements and previous_elements[index] != block.signature
+		]
+		unchanged = [block for index, block in elements.items() if previous_elements.get(index) == block.signature]
+		removed = [index for index in previous_elements if index not in elements]
+		new_text = [line for line in page_text if line not in previous_text][:_MAX_NEW_TEXT_LINES]
+
+		sections: list[str] = []
+		if new:
+			sections.append('New:\n' + '\n'.join(block.full() for block in new))
+		if changed:
+			sections.append('Changed:\n' + '\n'.join(block.full() for block in changed))
+		if removed:
+			sections.append('Removed: ' + ', '.join(f'[{index}]' for index in removed))
+		if new_text:
+			sections.append('New text on the page:\n' + '\n'.join(new_text))
+		if unchanged:
+			sections.append('Unchanged (shortened):\n' + '\n'.join(block.compact() for block in unchanged))
+
+		header = (
+			f'changes since the last step: {len(new)} new, {len(changed)} changed, {len(removed)} removed, '
+			f'{len(unchanged)} unchanged'
+		)
+		return '\n'.join(sections), header
@@ -3,16 +3,19 @@ from __future__ import annotations
 import logging
 from typing import Literal
 
+from browser_use.agent.language import language_reminder
 from browser_use.agent.message_manager.views import (
 	HistoryItem,
 )
 from browser_use.agent.prompts import AgentMessagePrompt
+from browser_use.agent.redaction import Redactor
 from browser_use.agent.views import (
 	ActionResult,
 	AgentOutput,
 	AgentStepInfo,
 	MessageCompactionSettings,
 	MessageManagerState,
+	VisionBudget,
 )
 from browser_use.browser.views import BrowserStateSummary
 from browser_use.filesystem.file_system import FileSystem
@@ -29,6 +32,7 @@ from browser_use.utils import (
 	collect_sensitive_data_values,
 	match_url_with_domain_pattern,
 	redact_sensitive_string,
+	safe_slice,
 	time_execution_sync,
 )
 
@@ -120,6 +124,16 @@ class MessageManager:
 		sample_images: list[ContentPartTextParam | ContentPartImageParam] | None = None,
 		llm_screenshot_size: tuple[int, int] | None = None,
 		max_clickable_elements_length: int = 40000,
+		max_interactive_elements: int | None = None,
+		max_attribute_length: int = 100,
+		computer_use_mode: bool = False,
+		vision_budget: VisionBudget | None = None,
+		language: str | None = None,
+		dom_diff_mode: bool = False,
+		dom_diff_full_refresh_every: int = 5,
+		include_element_boxes: bool = False,
+		redactor: Redactor | None = None,
+		flash_mode: bool = False,
 	):
 		self.task = task
 		self.state = state
@@ -134,6 +148,18 @@ class MessageManager:
 		self.sample_images = sample_images
 		self.llm_screenshot_size = llm_screenshot_size
 		self.max_clickable_elements_length = max_clickable_elements_length
+		self.max_interactive_elements = max_interactive_elements
+		self.max_attribute_length = max_attribute_length
+		self.computer_use_mode = computer_use_mode
+		self.vision_budget = vision_budget
+		self.language = language
+		self.dom_diff_mode = dom_diff_mode
+		self.dom_diff_full_refresh_every = dom_diff_full_refresh_every
+		self.include_element_boxes = include_element_boxes
+		self.redactor = redactor
+		self.flash_mode = flash_mode
+		# Screenshots sent on recent steps, re-sent as context when vision_budget.include_last > 1
+		self._recent_screenshots: list[str] = []
 
 		assert max_history_items is None or max_history_items > 5, 'max_history_items must be None or greater than 5'
 
@@ -193,6 +219,10 @@ class MessageManager:
 		task_update_item = HistoryItem(system_message=new_task)
 		self.state.agent_history_items.append(task_update_item)
 
+	def add_system_note(self, note: str) -> None:
+		"""Record something the framework did on the agent's behalf so the model knows about it."""
+		self.state.agent_history_items.append(HistoryItem(system_message=f'<sys>{note}</sys>'))
+
 	def prepare_step_state(
 		self,
 		browser_state_summary: BrowserStateSummary,
@@ -252,7 +282,7 @@ class MessageManager:
 			compaction_sections.append(f'<read_state>\n{self.state.read_state_description}\n</read_state>')
 		compaction_input = '\n\n'.join(compaction_sections)
 
-		if self.sensitive_data:
+		if self.sensitive_data or self.redactor:
 			filtered = self._filter_sensitive_data(UserMessage(content=compaction_input))
 			compaction_input = filtered.text
 
@@ -338,17 +368,20 @@ class MessageManager:
 
 			if action_result.error:
 				if len(action_result.error) > 200:
-					error_text = action_result.error[:100] + '......' + action_result.error[-100:]
+					error_text = safe_slice(action_result.error, 100) + '......' + action_result.error[-100:]
 				else:
 					error_text = action_result.error
 				action_results += f'{error_text}\n'
 				logger.debug(f'Added error to action_results: {error_text}')
 
+		if self.language and any(action_result.error for action_result in result):
+			action_results += f'{language_reminder(self.language)}\n'
+
 		# Simple 60k character limit for read_state_description
 		MAX_CONTENT_SIZE = 60000
 		if len(self.state.read_state_description) > MAX_CONTENT_SIZE:
 			self.state.read_state_description = (
-				self.state.read_state_description[:MAX_CONTENT_SIZE] + '\n... [Content truncated at 60k characters]'
+				safe_slice(self.state.read_state_description, MAX_CONTENT_SIZE) + '\n... [Content truncated at 60k characters]'
 			)
 			logger.debug(f'Truncated read_state_description to {MAX_CONTENT_SIZE} characters')
 
@@ -360,7 +393,7 @@ class MessageManager:
 
 		# Simple 60k character limit for action_results
 		if action_results and len(action_results) > MAX_CONTENT_SIZE:
-			action_results = action_results[:MAX_CONTENT_SIZE] + '\n... [Content truncated at 60k characters]'
+			action_results = safe_slice(action_results, MAX_CONTENT_SIZE) + '\n... [Content truncated at 60k characters]'
 			logger.debug(f'Truncated action_results to {MAX_CONTENT_SIZE} characters')
 
 		# Build the history item
@@ -430,6 +463,7 @@ class MessageManager:
 		available_file_paths: list[str] | None = None,  # Always pass current available_file_paths
 		unavailable_skills_info: str | None = None,  # Information about skills that cannot be used yet
 		plan_description: str | None = None,  # Rendered plan for injection into agent state
+		site_guidance: str | None = None,  # Guidance from playbooks matching the current page
 		skip_state_update: bool = False,
 	) -> None:
 		"""Create single state message with all content"""
@@ -469,7 +503,12 @@ class MessageManager:
 		# else: use_vision is False, never include screenshot (include_screenshot stays False)
 
 		if include_screenshot and browser_state_summary.screenshot:
-			screenshots.append(browser_state_summary.screenshot)
+			if self.vision_budget is None:
+				screenshots.append(browser_state_summary.screenshot)
+			elif self._screenshot_within_budget(result, step_info, include_screenshot_requested):
+				self._recent_screenshots = [*self._recent_screenshots, browser_state_summary.screenshot]
+				self._recent_screenshots = self._recent_screenshots[-self.vision_budget.include_last :]
+				screenshots.extend(self._recent_screenshots)
 
 		# Use vision in the user message if screenshots are included
 		effective_use_vision = len(screenshots) > 0
@@ -486,6 +525,8 @@ class MessageManager:
 			step_info=step_info,
 			page_filtered_actions=page_filtered_actions,
 			max_clickable_elements_length=self.max_clickable_elements_length,
+			max_interactive_elements=self.max_interactive_elements,
+			max_attribute_length=self.max_attribute_length,
 			sensitive_data=self.sensitive_data_description,
 			available_file_paths=available_file_paths,
 			screenshots=screenshots,
@@ -494,8 +535,15 @@ class MessageManager:
 			sample_images=self.sample_images,
 			read_state_images=self.state.read_state_images,
 			llm_screenshot_size=self.llm_screenshot_size,
+			screenshot_jpeg_quality=self.vision_budget.jpeg_quality if self.vision_budget else None,
 			unavailable_skills_info=unavailable_skills_info,
 			plan_description=plan_description,
+			site_guidance=site_guidance,
+			computer_use_mode=self.computer_use_mode,
+			dom_diff_state=self.state.dom_diff if self.dom_diff_mode else None,
+			dom_diff_full_refresh_every=self.dom_diff_full_refresh_every,
+			include_element_boxes=self.include_element_boxes,
+			flash_mode=self.flash_mode,
 		).get_user_message(effective_use_vision)
 
 		# Store state message text for history
@@ -504,6 +552,36 @@ class MessageManager:
 		# Set the state message with caching enabled
 		self._set_message_with_type(state_message, 'state')
 
+	def _screenshot_within_budget(
+		self, result: list[ActionResult] | None, step_info: AgentStepInfo | None, requested: bool
+	) -> bool:
+		"""Decide whether this step's screenshot is sent under the vision budget, and count the decision"""
+		assert self.vision_budget is not None
+		budget = self.vision_budget
+		step_number = step_info.step_number if step_info else 0
+
+		reason = None
+		if budget.max_screenshots is not None and self.state.screenshots_sent >= budget.max_screenshots:
+			reason = f'max_screenshots={budget.max_screenshots} reached'
+		elif requested:
+			pass
+		elif budget.after_failures_only and not any(r.error for r in result or []):
+			reason = 'previous step had no failures'
+		elif step_number % budget.every_n_steps != 0:
+			reason = f'only every {budget.every_n_steps} steps'
+
+		if reason:
+			self.state.screenshots_skipped += 1
+			logger.debug(f'📷 Vision budget: skipping screenshot of step {step_number + 1} ({reason})')
+			return False
+
+		self.state.screenshots_sent += 1
+		sent = f'{self.state.screenshots_sent} sent'
+		if budget.max_screenshots is not None:
+			sent += f', {budget.max_screenshots - self.state.screenshots_sent} left'
+		logger.debug(f'📷 Vision budget: sending screenshot of step {step_number + 1} ({sent})')
+		return True
+
 	def _log_history_lines(self) -> str:
 		"""Generate a formatted log string of message history for debugging / printing to terminal"""
 		# TODO: fix logging
@@ -550,6 +628,11 @@ class MessageManager:
 		self.last_input_messages = self.state.history.get_messages()
 		return self.last_input_messages
 
+	def set_system_message(self, system_message: SystemMessage) -> None:
+		"""Replace the system prompt, e.g. after actions were registered"""
+		self.system_prompt = system_message
+		self._set_message_with_type(system_message, 'system')
+
 	def _set_message_with_type(self, message: BaseMessage, message_type: Literal['system', 'state']) -> None:
 		"""Replace a specific state message slot with a new message"""
 		# System messages don't need filtering - they only contain instructions/placeholders
@@ -558,7 +641,7 @@ class MessageManager:
 		if message_type == 'system':
 			self.state.history.system_message = message
 		elif message_type == 'state':
-			if self.sensitive_data:
+			if self.sensitive_data or self.redactor:
 				message = self._filter_sensitive_data(message)
 			self.state.history.state_message = message
 		else:
@@ -575,6 +658,8 @@ class MessageManager:
 		"""Filter out sensitive data from the message"""
 
 		def replace_sensitive(value: str) -> str:
+			if self.redactor is not None:
+				value = self.redactor.redact_text(value)
 			if not self.sensitive_data:
 				return value
 
@@ -4,6 +4,7 @@ from typing import TYPE_CHECKING, Any
 
 from pydantic import BaseModel, ConfigDict, Field
 
+from browser_use.agent.message_manager.dom_diff import DOMDiffState
 from browser_use.llm.messages import (
 	BaseMessage,
 )
@@ -97,5 +98,10 @@ class MessageManagerState(BaseModel):
 	compacted_memory: str | None = None
 	compaction_count: int = 0
 	last_compaction_step: int | None = None
+	# Screenshot budget counters, see VisionBudget
+	screenshots_sent: int = 0
+	screenshots_skipped: int = 0
+	# Element list of the last state message, see Agent(dom_diff_mode=True)
+	dom_diff: DOMDiffState = Field(default_factory=DOMDiffState)
 
 	model_config = ConfigDict(arbitrary_types_allowed=True)
@@ -0,0 +1,132 @@
+"""
+Push notifications for unattended runs: when a run finishes or fails, and when the agent waits for a human.
+
+	agent = Agent(task=..., llm=llm, notifiers=[SlackNotifier(os.environ['SLACK_WEBHOOK_URL'])])
+
+Implement Notifier.notify to send them anywhere else. Notifier errors are logged and never fail the run.
+"""
+
+import asyncio
+import logging
+import platform
+import shutil
+from abc import ABC, abstractmethod
+from datetime import datetime, timezone
+from typing import Any, Literal
+
+import httpx
+from pydantic import BaseModel, Field
+
+logger = logging.getLogger(__name__)
+
+NotificationEvent = Literal['run_succeeded', 'run_failed', 'human_input_needed']
+
+_EVENT_TITLES: dict[str, str] = {
+	'run_succeeded': '✅ Task done',
+	'run_failed': '❌ Task failed',
+	'human_input_needed': '✋ Waiting for approval',
+}
+
+
+class AgentNotification(BaseModel):
+	"""What happened to a run, sent to every notifier of the agent."""
+
+	event: NotificationEvent
+	agent_id: str
+	task: str
+	message: str  # final result, error or what the agent is waiting for
+	steps: int
+	url: str | None = None  # current page of the agent
+	success: bool | None = None
+	artifacts_path: str | None = None  # bundle written to artifacts_dir for this run
+	timestamp: str = Field(default_factory=lambda: datetime.now(timezone.utc).isoformat())
+
+	def summary(self) -> str:
+		"""Short text for chat messages and speech"""
+		lines = [f'{_EVENT_TITLES[self.event]}: {self.task}', self.message]
+		if self.url:
+			lines.append(f'Page: {self.url}')
+		if self.artifacts_path:
+			lines.append(f'Artifacts: {self.artifacts_path}')
+		return '\n'.join(line for line in lines if line)
+
+
+class Notifier(ABC):
+	"""Receives the notifications of an agent, see Agent(notifiers=[...])."""
+
+	@abstractmethod
+	async def notify(self, notification: AgentNotification) -> None: ...
+
+
+class WebhookNotifier(Notifier):
+	"""POST the notification as JSON to a URL."""
+
+	def __init__(
+		self,
+		url: str,
+		headers: dict[str, str] | None = None,
+		events: list[NotificationEvent] | None = None,
+		timeout: float = 10.0,
+	):
+		self.url = url
+		self.headers = headers or {}
+		self.events = events  # None sends all events
+		self.timeout = timeout
+
+	def _payload(self, notification: AgentNotification) -> dict[str, Any]:
+		return notification.model_dump()
+
+	async def notify(self, notification: AgentNotification) -> None:
+		if self.events is not None and notification.event not in self.events:
+			return
+		async with httpx.AsyncClient(timeout=self.timeout) as client:
+			response = await client.post(self.url, json=self._payload(notification), headers=self.headers)
+			response.raise_for_status()
+
+
+class SlackNotifier(WebhookNotifier):
+	"""Post the notification to a Slack channel through an incoming webhook URL."""
+
+	def _payload(self, notification: AgentNotification) -> dict[str, Any]:
+		return {'text': notification.summary()}
+
+
+class SpeechNotifier(Notifier):
+	"""Read the notification out loud with the system's text-to-speech (say on macOS, espeak or spd-say on Linux)."""
+
+	def __init__(self, events: list[NotificationEvent] | None = None, command: list[str] | None = None):
+		self.events = events
+		self.command = command or _default_speech_command()
+
+	async def notify(self, notification: AgentNotification) -> None:
+		if self.events is not None and notification.event not in self.events:
+			return
+		if not self.command:
+			logger.warning('🔈 No text-to-speech command found, install espeak or pass command=[...] to SpeechNotifier')
+			return
+		text = f'{_EVENT_TITLES[notification.event].split(" ", 1)[1]}. {notification.message}'
+		process = await asyncio.create_subprocess_exec(
+			*self.command, text, stdout=asyncio.subprocess.DEVNULL, stderr=asyncio.subprocess.DEVNULL
+		)
+		await process.wait()
+
+
+def _default_speech_command() -> list[str] | None:
+	if platform.system() == 'Darwin':
+		return ['say']
+	for command in ('espeak', 'spd-say'):
+		if shutil.which(command):
+			return [command]
+	return None
+
+
+async def send_notification(notifiers: list[Notifier], notification: AgentNotification, timeout: float = 15.0) -> None:
+	"""Send to all notifiers concurrently, logging instead of raising when one fails or takes too long."""
+
+	async def _send(notifier: Notifier) -> None:
+		try:
+			await asyncio.wait_for(notifier.notify(notification), timeout=timeout)
+		except Exception as e:
+			logger.warning(f'🔔 {type(notifier).__name__} failed to send {notification.event}: {type(e).__name__}: {e}')
+
+	await asyncio.gather(*(_send(notifier) for notifier in notifiers))
@@ -0,0 +1,73 @@
+"""Site-specific playbooks: extra guidance and hooks for the agent while it is on matching pages."""
+
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass
+from typing import TYPE_CHECKING
+
+from browser_use.utils import match_url_with_domain_pattern
+
+if TYPE_CHECKING:
+	from browser_use.agent.service import Agent
+	from browser_use.browser.views import BrowserStateSummary
+
+# Called with the agent and the browser state of the step. A returned string is shown to the model:
+# before hooks add it to the guidance of the current step, after hooks to the results of the step.
+PlaybookHook = Callable[['Agent', 'BrowserStateSummary'], Awaitable[str | None]]
+
+
+@dataclass
+class Playbook:
+	"""Guidance and hooks that apply while the current page matches one of the domain patterns.
+
+	Patterns use the same syntax as allowed_domains, e.g. '*.linkedin.com' or 'http*://localhost'.
+	"""
+
+	name: str
+	domains: list[str]
+	guidance: str | None = None
+	on_first_visit: PlaybookHook | None = None  # once per agent, before the first step on a matching page
+	before_step: PlaybookHook | None = None  # before every step on a matching page
+	after_step: PlaybookHook | None = None  # after the actions of every step that started on a matching page
+
+	def __post_init__(self):
+		if not self.domains:
+			raise ValueError(f'Playbook {self.name!r} needs at least one domain pattern')
+
+	def matches(self, url: str) -> bool:
+		return any(match_url_with_domain_pattern(url, pattern) for pattern in self.domains)
+
+
+class PlaybookRegistry:
+	"""Playbooks of an agent, looked up by the URL of the current page."""
+
+	def __init__(self, playbooks: list[Playbook] | None = None):
+		self.playbooks: dict[str, Playbook] = {}
+		for playbook in playbooks or []:
+			self.register(playbook)
+
+	def register(self, playbook: Playbook) -> None:
+		if playbook.name in self.playbooks:
+			raise ValueError(f'Playbook {playbook.name!r} is already registered')
+		self.playbooks[playbook.name] = playbook
+
+	def playbook(self, name: str, domains: list[str], guidance: str | None = None) -> Callable[[PlaybookHook], PlaybookHook]:
+		"""Decorator registering a function as the before_step hook of a new playbook."""
+
+		def decorator(func: PlaybookHook) -> PlaybookHook:
+			self.register(Playbook(name=name, domains=domains, guidance=guidance, before_step=func))
+			return func
+
+		return decorator
+
+	def get_matching(self, url: str) -> list[Playbook]:
+		return [playbook for playbook in self.playbooks.values() if playbook.matches(url)]
+
+	def __len__(self) -> int:
+		return len(self.playbooks)
+
+
+def format_playbook_guidance(guidance: list[tuple[str, str]]) -> str | None:
+	"""Render (playbook name, text) pairs for the <site_guidance> section of the state message."""
+	if not guidance:
+		return None
+	return '\n'.join(f'[{name}] {text.strip()}' for name, text in guidance if text.strip()) or None
@@ -0,0 +1,283 @@
+"""
+Procedures learned from successful runs, reused by later runs on similar tasks and shareable between teams.
+
+	store = FileProcedureStore('~/.config/browseruse/procedures')
+	agent = Agent(task='Download the March invoice from billing.example.com', llm=llm, procedure_store=store)
+
+After a successful run the agent distills its action trace into a Procedure, keyed by the keywords of the task and
+the domain of the site. Runs on similar tasks get the best matching procedures as guidance before their first step.
+A procedure can also be replayed as a macro without the LLM:
+
+	await HistoryReplayer(browser).replay(procedure.replay_actions())
+
+Share a library with store.export_library(path) and store.import_library(path).
+"""
+
+import json
+import logging
+import re
+from abc import ABC, abstractmethod
+from collections.abc import Callable
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+from urllib.parse import urlparse
+
+from pydantic import BaseModel, Field
+from uuid_extensions import uuid7str
+
+from browser_use.utils import URL_PATTERN
+
+if TYPE_CHECKING:
+	from browser_use.agent.views import AgentHistoryList
+
+logger = logging.getLogger(__name__)
+
+_WORD = re.compile(r'[a-z][a-z0-9_-]+')
+_STOPWORDS = frozenset(
+	'the and for with from into onto that this then than there their them they you your our out all any are was were '
+	'can could should would will please just also only get got make sure use using via its on in of to at by as '
+	'is be an or if it me my we us do does did not no yes go open find page site website'.split()
+)
+_MAX_GUIDANCE_STEPS = 25
+_SKIPPED_ACTIONS = ('done',)
+
+
+def task_keywords(task: str) -> list[str]:
+	"""Lowercase words that identify what a task is about, without URLs, numbers and filler words."""
+	text = URL_PATTERN.sub(' ', task.lower())
+	return sorted({word for word in _WORD.findall(text) if word not in _STOPWORDS and len(word) > 2})
+
+
+def url_domain(url: str | None) -> str | None:
+	"""Host of a URL without a leading www., None for browser pages like about:blank."""
+	if not url:
+		return None
+	if '://' not in url:
+		url = f'https://{url}'
+	parsed = urlparse(url)
+	if parsed.scheme not in ('http', 'https') or not parsed.hostname:
+		return None
+	host = parsed.hostname.lower()
+	return host[4:] if host.startswith('www.') else host
+
+
+def _same_site(domain: str, other: str) -> bool:
+	return domain == other or domain.endswith(f'.{other}') or other.endswith(f'.{domain}')
+
+
+def _redact_strings(value: Any, redact: Callable[[str], str]) -> Any:
+	"""Apply redact to every string in nested dicts and lists."""
+	if isinstance(value, str):
+		return redact(value)
+	if isinstance(value, dict):
+		return {key: _redact_strings(item, redact) for key, item in value.items()}
+	if isinstance(value, list):
+		return [_redact_strings(item, redact) for item in value]
+	return value
+
+
+class ProcedureStep(BaseModel):
+	"""One action of a learned procedure and the goal the agent had when it took it."""
+
+	action: dict[str, Any]  # e.g. {'click': {'index': 12}}
+	goal: str | None = None
+	url: str | None = None  # page the action ran on
+	expected_url: str | None = None  # page the browser was on after the step
+	element: dict[str, Any] | None = None  # DOMInteractedElement.to_dict() of the target element
+
+	@property
+	def name(self) -> str:
+		return next(iter(self.action))
+
+	def describe(self) -> str:
+		params = self.action.get(self.name) or {}
+		details = []
+		if self.element:
+			label = self.element.get('ax_name') or (self.element.get('attributes') or {}).get('aria-label')
+			tag = self.element.get('node_name', '').lower()
+			details.append(f'<{tag}> "{label}"' if label else self.element.get('x_path', ''))
+		details.extend(f'{key}={value!r}' for key, value in params.items() if key != 'index' and value not in (None, ''))
+		text = f'{self.name}({", ".join(detail for detail in details if detail)})'
+		return f'{self.goal.rstrip(".")}: {text}' if self.goal else text
+
+
+class Procedure(BaseModel):
+	"""A distilled action trace of a successful run, keyed by task keywords and domain."""
+
+	id: str = Field(default_factory=uuid7str)
+	task: str
+	keywords: list[str]
+	domain: str | None = None
+	steps: list[ProcedureStep]
+	final_result: str | None = None
+	success_count: int = 1
+	updated_at: str = Field(default_factory=lambda: datetime.now(timezone.utc).isoformat())
+
+	@property
+	def key(self) -> tuple[str, str | None]:
+		return ' '.join(self.keywords), self.domain
+
+	@classmethod
+	def from_history(
+		cls, task: str, history: 'AgentHistoryList', redact: Callable[[str], str] | None = None
+	) -> 'Procedure | None':
+		"""Distill the actions that worked in a run, failed actions the agent recovered from are left out.
+
+		redact is applied to every text of the procedure (task, inputs, elements, URLs, result) before it is stored,
+		the agent passes one that masks its sensitive_data values.
+		"""
+		steps: list[ProcedureStep] = []
+		for i, item in enumerate(history.history):
+			if not item.model_output:
+				continue
+			elements = item.state.interacted_element or []
+			next_url = next((h.state.url for h in history.history[i + 1 :] if h.state.url), None)
+			actions = [action.model_dump(exclude_unset=True, mode='json') for action in item.model_output.action]
+			for j, action in enumerate(actions):
+				if not action or next(iter(action)) in _SKIPPED_ACTIONS or (j < len(item.result) and item.result[j].error):
+					continue
+				element = elements[j] if j < len(elements) else None
+				steps.append(
+					ProcedureStep(
+						action=action,
+						goal=item.model_output.next_goal if j == 0 else None,
+						url=item.state.url,
+						expected_url=next_url if j == len(actions) - 1 else None,
+						element=element.to_dict() if element else None,
+					)
+				)
+		if not steps:
+			return None
+		domain = next((domain for domain in map(url_domain, history.urls()) if domain), None)
+		final_result = history.final_result()
+		if redact is not None:
+			task, final_result = redact(task), final_result and redact(final_result)
+			steps = [ProcedureStep.model_validate(_redact_strings(step.model_dump(), redact)) for step in steps]
+		return cls(task=task, keywords=task_keywords(task), domain=domain, steps=steps, final_result=final_result)
+
+	def similarity(self, task: str) -> float:
+		"""Overlap of the task keywords with this procedure's, from 0 to 1."""
+		keywords, own = set(task_keywords(task)), set(self.keywords)
+		if not keywords or not own:
+			return 0.0
+		return len(keywords & own) / len(keywords | own)
+
+	def to_guidance(self) -> str:
+		lines = [f'Task: {self.task} (succeeded {self.success_count}x{f" on {self.domain}" if self.domain else ""})']
+		for number, step in enumerate(self.steps[:_MAX_GUIDANCE_STEPS], start=1):
+			lines.append(f'{number}. {step.describe()}')
+		if len(self.steps) > _MAX_GUIDANCE_STEPS:
+			lines.append(f'... {len(self.steps) - _MAX_GUIDANCE_STEPS} more steps')
+		return '\n'.join(lines)
+
+	def replay_actions(self) -> list[dict[str, Any]]:
+		"""The steps in the format of HistoryReplayer.steps_from_actions, to run the procedure without the LLM."""
+		return [
+			{**step.action, 'interacted_element': step.element, 'expected_url': step.expected_url}
+			for step in self.steps
+		]
+
+
+def format_procedure_guidance(procedures: list[Procedure]) -> str:
+	"""Note shown to the agent before its first step, see Agent(procedure_store=...)."""
+	blocks = '\n\n'.join(procedure.to_guidance() for procedure in procedures)
+	return (
+		'Procedures that completed similar tasks before. Follow them where they fit, element indices are from earlier '
+		f'runs so find the elements again on the current page, and adapt where the page or the task differs:\n{blocks}'
+	)
+
+
+class ProcedureStore(ABC):
+	"""Where learned procedures are kept. Implement save, load and delete to use a database or a shared service."""
+
+	@abstractmethod
+	def save(self, procedure: Procedure) -> None:
+		"""Insert or replace a procedure by its id."""
+
+	@abstractmethod
+	def load(self) -> list[Procedure]:
+		"""All stored procedures."""
+
+	@abstractmethod
+	def delete(self, procedure_id: str) -> None: ...
+
+	def find(self, task: str, domain: str | None = None, limit: int = 2, min_similarity: float = 0.3) -> list[Procedure]:
+		"""Procedures for similar tasks, best match first. With a domain, only procedures learned on that site."""
+		scored = []
+		for procedure in self.load():
+			if domain and procedure.domain and not _same_site(domain, procedure.domain):
+				continue
+			score = procedure.similarity(task)
+			if score >= min_similarity:
+				scored.append((score, procedure.success_count, procedure))
+		scored.sort(key=lambda item: (item[0], item[1]), reverse=True)
+		return [procedure for _, _, procedure in scored[:limit]]
+
+	def learn(self, task: str, history: 'AgentHistoryList', redact: Callable[[str], str] | None = None) -> Procedure | None:
+		"""Store the procedure of a successful run, replacing the steps of an earlier one for the same task and domain."""
+		procedure = Procedure.from_history(task, history, redact=redact)
+		if procedure is None:
+			return None
+		existing = next((stored for stored in self.load() if stored.key == procedure.key), None)
+		if existing is not None:
+			procedure = procedure.model_copy(update={'id': existing.id, 'success_count': existing.success_count + 1})
+		self.save(procedure)
+		return procedure
+
+	def export_library(self, path: str | Path) -> Path:
+		"""Write all procedures to one JSON file to share them."""
+		path = Path(path).expanduser()
+		path.parent.mkdir(parents=True, exist_ok=True)
+		library = [procedure.model_dump(mode='json') for procedure in self.load()]
+		path.write_text(json.dumps(library, indent=2, ensure_ascii=False), encoding='utf-8')
+		return path
+
+	def import_library(self, path: str | Path) -> int:
+		"""Add the procedures of an exported library, returns how many were imported."""
+		library = json.loads(Path(path).expanduser().read_text(encoding='utf-8'))
+		for entry in library:
+			self.save(Procedure.model_validate(entry))
+		return len(library)
+
+
+class InMemoryProcedureStore(ProcedureStore):
+	"""Procedures kept for the lifetime of the process, e.g. shared by the agents of one batch."""
+
+	def __init__(self, procedures: list[Procedure] | None = None):
+		self._procedures: dict[str, Procedure] = {procedure.id: procedure for procedure in procedures or []}
+
+	def save(self, procedure: Procedure) -> None:
+		self._procedures[procedure.id] = procedure
+
+	def load(self) -> list[Procedure]:
+		return list(self._procedures.values())
+
+	def delete(self, procedure_id: str) -> None:
+		self._procedures.pop(procedure_id, None)
+
+
+class FileProcedureStore(ProcedureStore):
+	"""One JSON file per procedure in a directory, which can live in a shared or version-controlled folder."""
+
+	def __init__(self, directory: str | Path):
+		self.directory = Path(directory).expanduser()
+		self.directory.mkdir(parents=True, exist_ok=True)
+
+	def save(self, procedure: Procedure) -> None:
+		path = self.directory / f'{procedure.id}.json'
+		tmp_path = path.with_suffix('.json.tmp')
+		tmp_path.write_text(procedure.model_dump_json(indent=2), encoding='utf-8')
+		tmp_path.replace(path)
+
+	def load(self) -> list[Procedure]:
+		procedures = []
+		for path in sorted(self.directory.glob('*.json')):
+			try:
+				procedures.append(Procedure.model_validate_json(path.read_text(encoding='utf-8')))
+			except Exception as e:
+				logger.warning(f'Skipping unreadable procedure {path.name}: {type(e).__name__}: {e}')
+		return procedures
+
+	def delete(self, procedure_id: str) -> None:
+		(self.directory / f'{procedure_id}.json').unlink(missing_ok=True)
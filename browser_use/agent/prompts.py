@@ -1,18 +1,35 @@
+import functools
 import importlib.resources
+import re
+import string
 from datetime import datetime
+from pathlib import Path
 from typing import TYPE_CHECKING, Literal, Optional
 
+from browser_use.agent.language import language_instructions
 from browser_use.browser.views import PLACEHOLDER_4PX_SCREENSHOT
 from browser_use.dom.views import NodeType, SimplifiedNode
 from browser_use.llm.messages import ContentPartImageParam, ContentPartTextParam, ImageURL, SystemMessage, UserMessage
 from browser_use.observability import observe_debug
-from browser_use.utils import is_new_tab_page, sanitize_surrogates
+from browser_use.utils import is_new_tab_page, sanitize_text
 
 if TYPE_CHECKING:
+	from browser_use.agent.message_manager.dom_diff import DOMDiffState
 	from browser_use.agent.views import AgentStepInfo
 	from browser_use.browser.views import BrowserStateSummary
 	from browser_use.filesystem.file_system import FileSystem
 
+MAX_PAGE_ERRORS_IN_STATE = 10  # Most recent page errors shown in the browser state, older ones are summarized
+
+# {name} placeholders in user-provided system prompts and their {{name}} escape, see render_prompt_variables
+_PROMPT_VARIABLE = re.compile(r'\{\{(\w+)\}\}|\{(\w+)\}')
+
+# Default language section of the built-in templates, replaced when a language is configured
+_LANGUAGE_SETTINGS = re.compile(r'<language_settings>.*?</language_settings>', re.DOTALL)
+
+# Lines of the serialized DOM that list an interactive element, e.g. "\t*[12]<button" or "|SHADOW(open)|[3]<input"
+_INTERACTIVE_ELEMENT_LINE = re.compile(r'^\t*(?:\|SHADOW\((?:open|closed)\)\|)?\*?(?:\|scroll element)?\[\d+\]')
+
 
 def _is_anthropic_4_5_model(model_name: str | None) -> bool:
 	"""Check if the model is Claude Opus 4.5 or Haiku 4.5 (requires 4096+ token prompts for caching)."""
@@ -25,6 +42,77 @@ def _is_anthropic_4_5_model(model_name: str | None) -> bool:
 	return is_opus_4_5 or is_haiku_4_5
 
 
+def render_prompt_variables(text: str, variables: dict[str, str]) -> str:
+	"""Fill the {name} placeholders of a user-provided prompt.
+
+	Only names present in variables are replaced, any other braces (e.g. JSON examples) are kept as they are.
+	Write {{name}} to keep a placeholder literally, it renders as {name}.
+	"""
+
+	def replace(match: re.Match[str]) -> str:
+		escaped, name = match.groups()
+		if escaped is not None:
+			return f'{{{escaped}}}'
+		return variables.get(name, match.group(0))
+
+	return _PROMPT_VARIABLE.sub(replace, text)
+
+
+class PromptTemplate:
+	"""A system prompt template compiled once and rendered per agent.
+
+	Placeholders are {name}, literal braces are written {{ and }}. Unlike render_prompt_variables, every placeholder
+	must get a value, so a typo in a template fails loudly instead of reaching the model.
+	"""
+
+	def __init__(self, text: str, source: str = '<string>'):
+		self.source = source
+		self._parts: list[tuple[str, str | None]] = []
+		try:
+			parsed = list(string.Formatter().parse(text))
+		except ValueError as e:
+			raise ValueError(f'Invalid prompt template {source}: {e}') from e
+		for literal, field_name, format_spec, conversion in parsed:
+			if field_name is not None and (not field_name.isidentifier() or format_spec or conversion):
+				field = field_name + (f'!{conversion}' if conversion else '') + (f':{format_spec}' if format_spec else '')
+				raise ValueError(
+					f'Invalid placeholder {{{field}}} in prompt template {source}, '
+					'use {name} or write literal braces as {{ }}'
+				)
+			self._parts.append((literal, field_name))
+		self.variables = frozenset(name for _, name in self._parts if name is not None)
+
+	def render(self, variables: dict[str, str]) -> str:
+		missing = sorted(self.variables - variables.keys())
+		if missing:
+			raise ValueError(
+				f'Prompt template {self.source} uses undefined variables {missing}, pass them in system_prompt_variables'
+			)
+		return ''.join(literal + (variables[name] if name is not None else '') for literal, name in self._parts)
+
+
+@functools.cache
+def load_prompt_template(filename: str) -> PromptTemplate:
+	"""Compile a built-in template of browser_use/agent/system_prompts, once per process."""
+	# This works both in development and when installed as a package
+	text = importlib.resources.files('browser_use.agent.system_prompts').joinpath(filename).read_text(encoding='utf-8')
+	return PromptTemplate(text, source=filename)
+
+
+def load_prompt_template_file(path: str | Path) -> PromptTemplate:
+	"""Compile a template file, recompiling only when its modification time changes.
+
+	Compiled templates are kept in memory for the process, nothing is written to disk.
+	"""
+	resolved = Path(path).expanduser().resolve()
+	return _compile_prompt_template_file(resolved, resolved.stat().st_mtime_ns)
+
+
+@functools.lru_cache(maxsize=32)
+def _compile_prompt_template_file(path: Path, mtime_ns: int) -> PromptTemplate:
+	return PromptTemplate(path.read_text(encoding='utf-8'), source=str(path))
+
+
 class SystemPrompt:
 	def __init__(
 		self,
@@ -36,6 +124,12 @@ class SystemPrompt:
 		is_anthropic: bool = False,
 		is_browser_use_model: bool = False,
 		model_name: str | None = None,
+		computer_use_mode: bool = False,
+		task: str | None = None,
+		available_actions: str | None = None,
+		prompt_variables: dict[str, str] | None = None,
+		language: str | None = None,
+		template_path: str | Path | None = None,
 	):
 		self.max_actions_per_step = max_actions_per_step
 		self.use_thinking = use_thinking
@@ -43,54 +137,75 @@ class SystemPrompt:
 		self.is_anthropic = is_anthropic
 		self.is_browser_use_model = is_browser_use_model
 		self.model_name = model_name
+		self.computer_use_mode = computer_use_mode
 		# Check if this is an Anthropic 4.5 model that needs longer prompts for caching
 		self.is_anthropic_4_5 = _is_anthropic_4_5_model(model_name)
+		# Variables for {name} placeholders in the template and override_system_message / extend_system_message
+		self.prompt_variables: dict[str, str] = {
+			'max_actions': str(self.max_actions_per_step),
+			'task': task or '',
+			'date': datetime.now().strftime('%Y-%m-%d'),
+			'available_actions': available_actions or '',
+			**{name: str(value) for name, value in (prompt_variables or {}).items()},
+		}
+		# override / extend messages are used verbatim unless the caller declared prompt_variables ({} for only the built-ins)
+		render_messages = prompt_variables is not None
 		prompt = ''
 		if override_system_message is not None:
-			prompt = override_system_message
+			prompt = (
+				render_prompt_variables(override_system_message, self.prompt_variables)
+				if render_messages
+				else override_system_message
+			)
 		else:
-			self._load_prompt_template()
-			prompt = self.prompt_template.format(max_actions=self.max_actions_per_step)
+			self._load_prompt_template(template_path)
+			prompt = self.prompt_template.render(self.prompt_variables)
+
+		if language:
+			if _LANGUAGE_SETTINGS.search(prompt):
+				prompt = _LANGUAGE_SETTINGS.sub(lambda _: language_instructions(language), prompt, count=1)
+			else:
+				prompt += f'\n{language_instructions(language)}'
 
 		if extend_system_message:
+			if render_messages:
+				extend_system_message = render_prompt_variables(extend_system_message, self.prompt_variables)
 			prompt += f'\n{extend_system_message}'
 
 		self.system_message = SystemMessage(content=prompt, cache=True)
 
-	def _load_prompt_template(self) -> None:
-		"""Load the prompt template from the markdown file."""
+	def _load_prompt_template(self, template_path: str | Path | None = None) -> None:
+		"""Load the compiled prompt template, a custom template file or the built-in one for the model and mode."""
 		try:
-			# Choose the appropriate template based on model type and mode
-			# Browser-use models use simplified prompts optimized for fine-tuned models
-			if self.is_browser_use_model:
-				if self.flash_mode:
-					template_filename = 'system_prompt_browser_use_flash.md'
-				elif self.use_thinking:
-					template_filename = 'system_prompt_browser_use.md'
-				else:
-					template_filename = 'system_prompt_browser_use_no_thinking.md'
-			# Anthropic 4.5 models (Opus 4.5, Haiku 4.5) need 4096+ token prompts for caching
-			elif self.is_anthropic_4_5 and self.flash_mode:
-				template_filename = 'system_prompt_anthropic_flash.md'
-			elif self.flash_mode and self.is_anthropic:
-				template_filename = 'system_prompt_flash_anthropic.md'
-			elif self.flash_mode:
-				template_filename = 'system_prompt_flash.md'
-			elif self.use_thinking:
-				template_filename = 'system_prompt.md'
+			if template_path is not None:
+				self.prompt_template = load_prompt_template_file(template_path)
 			else:
-				template_filename = 'system_prompt_no_thinking.md'
-
-			# This works both in development and when installed as a package
-			with (
-				importlib.resources.files('browser_use.agent.system_prompts')
-				.joinpath(template_filename)
-				.open('r', encoding='utf-8') as f
-			):
-				self.prompt_template = f.read()
+				self.prompt_template = load_prompt_template(self._template_filename())
 		except Exception as e:
 			raise RuntimeError(f'Failed to load system prompt template: {e}')
 
+	def _template_filename(self) -> str:
+		# Computer-use mode works from screenshots and coordinates, none of the DOM based prompts apply
+		if self.computer_use_mode:
+			return 'system_prompt_computer_use.md'
+		# Browser-use models use simplified prompts optimized for fine-tuned models
+		if self.is_browser_use_model:
+			if self.flash_mode:
+				return 'system_prompt_browser_use_flash.md'
+			if self.use_thinking:
+				return 'system_prompt_browser_use.md'
+			return 'system_prompt_browser_use_no_thinking.md'
+		# Anthropic 4.5 models (Opus 4.5, Haiku 4.5) need 4096+ token prompts for caching
+		if self.is_anthropic_4_5 and self.flash_mode:
+			return 'system_prompt_anthropic_flash.md'
+		if self.flash_mode and self.is_anthropic:
+			return 'system_prompt_flash_anthropic.md'
+		if self.flash_mode:
+			return 'system_prompt_flash.md'
+		if self.use_thinking:
+			return 'system_prompt.md'
+		return 'system_prompt_no_thinking.md'
+
 	def get_system_message(self) -> SystemMessage:
 		"""
 		Get the system prompt for the agent.
@@ -115,6 +230,8 @@ class AgentMessagePrompt:
 		step_info: Optional['AgentStepInfo'] = None,
 		page_filtered_actions: str | None = None,
 		max_clickable_elements_length: int = 40000,
+		max_interactive_elements: int | None = None,
+		max_attribute_length: int = 100,
 		sensitive_data: str | None = None,
 		available_file_paths: list[str] | None = None,
 		screenshots: list[str] | None = None,
@@ -123,8 +240,15 @@ class AgentMessagePrompt:
 		sample_images: list[ContentPartTextParam | ContentPartImageParam] | None = None,
 		read_state_images: list[dict] | None = None,
 		llm_screenshot_size: tuple[int, int] | None = None,
+		screenshot_jpeg_quality: int | None = None,
 		unavailable_skills_info: str | None = None,
 		plan_description: str | None = None,
+		site_guidance: str | None = None,
+		computer_use_mode: bool = False,
+		dom_diff_state: 'DOMDiffState | None' = None,
+		dom_diff_full_refresh_every: int = 5,
+		include_element_boxes: bool = False,
+		flash_mode: bool = False,
 	):
 		self.browser_state: 'BrowserStateSummary' = browser_state_summary
 		self.file_system: 'FileSystem | None' = file_system
@@ -135,6 +259,8 @@ class AgentMessagePrompt:
 		self.step_info = step_info
 		self.page_filtered_actions: str | None = page_filtered_actions
 		self.max_clickable_elements_length: int = max_clickable_elements_length
+		self.max_interactive_elements: int | None = max_interactive_elements
+		self.max_attribute_length: int = max_attribute_length
 		self.sensitive_data: str | None = sensitive_data
 		self.available_file_paths: list[str] | None = available_file_paths
 		self.screenshots = screenshots or []
@@ -144,7 +270,15 @@ class AgentMessagePrompt:
 		self.read_state_images = read_state_images or []
 		self.unavailable_skills_info: str | None = unavailable_skills_info
 		self.plan_description: str | None = plan_description
+		self.site_guidance: str | None = site_guidance
 		self.llm_screenshot_size = llm_screenshot_size
+		self.screenshot_jpeg_quality = screenshot_jpeg_quality
+		self.computer_use_mode = computer_use_mode
+		self.dom_diff_state = dom_diff_state
+		self.dom_diff_full_refresh_every = dom_diff_full_refresh_every
+		self.include_element_boxes = include_element_boxes
+		# Flash mode sends a reduced state: no page statistics, a one-line page_info, tabs only when there are several
+		self.flash_mode = flash_mode
 		assert self.browser_state
 
 	def _extract_page_statistics(self) -> dict[str, int]:
@@ -220,6 +354,43 @@ class AgentMessagePrompt:
 		traverse_node(self.browser_state.dom_state._root)
 		return stats
 
+	def _truncate_elements_text(self, elements_text: str) -> tuple[str, str]:
+		"""Apply the element count and character limits to the elements list.
+
+		Returns the (possibly cut) text and the suffix for the "Interactive elements" header. When elements are
+		cut off, a paging note tells the agent how many interactive elements it can't see.
+		"""
+		lines = elements_text.split('\n')
+		total_elements = sum(1 for line in lines if _INTERACTIVE_ELEMENT_LINE.match(line))
+		limits: list[str] = []
+
+		if self.max_interactive_elements is not None and total_elements > self.max_interactive_elements:
+			seen = 0
+			for i, line in enumerate(lines):
+				if _INTERACTIVE_ELEMENT_LINE.match(line):
+					seen += 1
+					if seen > self.max_interactive_elements:
+						lines = lines[:i]
+						break
+			limits.append(f'{self.max_interactive_elements} elements')
+
+		truncated = '\n'.join(lines)
+		if len(truncated) > self.max_clickable_elements_length:
+			truncated = truncated[: self.max_clickable_elements_length]
+			limits.append(f'{self.max_clickable_elements_length} characters')
+
+		if not limits:
+			return elements_text, ''
+
+		shown_elements = sum(1 for line in truncated.split('\n') if _INTERACTIVE_ELEMENT_LINE.match(line))
+		hidden_elements = total_elements - shown_elements
+		if hidden_elements > 0:
+			truncated += (
+				f'\n... {hidden_elements} more interactive elements not shown (showing {shown_elements} of {total_elements}) '
+				'- scroll down to bring later elements into the list, or use search_page / find_elements to locate specific ones'
+			)
+		return truncated, f' (truncated to {" and ".join(limits)})'
+
 	@observe_debug(ignore_input=True, ignore_output=True, name='_get_browser_state_description')
 	def _get_browser_state_description(self) -> str:
 		# Extract page statistics first
@@ -248,14 +419,31 @@ class AgentMessagePrompt:
 			stats_text += f', {page_stats["images"]} images'
 		stats_text += f', {page_stats["total_elements"]} total elements'
 		stats_text += '</page_stats>\n'
-
-		elements_text = self.browser_state.dom_state.llm_representation(include_attributes=self.include_attributes)
-
-		if len(elements_text) > self.max_clickable_elements_length:
-			elements_text = elements_text[: self.max_clickable_elements_length]
-			truncated_text = f' (truncated to {self.max_clickable_elements_length} characters)'
-		else:
-			truncated_text = ''
+		if self.flash_mode:
+			empty_page = page_stats['total_elements'] < 10
+			stats_text = '<page_stats>Page appears empty - consider waiting</page_stats>\n' if empty_page else ''
+
+		# Element boxes are stored in page coordinates, shift them by the scroll position to viewport coordinates
+		box_origin = None
+		box_text = ''
+		if self.include_element_boxes:
+			pi = self.browser_state.page_info
+			box_origin = (pi.scroll_x, pi.scroll_y) if pi else (0.0, 0.0)
+			box_text = ' (box=x,y,width,height in CSS pixels from the top left of the viewport)'
+		elements_text = self.browser_state.dom_state.llm_representation(
+			include_attributes=self.include_attributes, max_attribute_length=self.max_attribute_length, box_origin=box_origin
+		)
+		diff_text = ''
+		if self.dom_diff_state is not None:
+			elements_text, diff_header = self.dom_diff_state.render(
+				elements_text, self.browser_state.url, self.dom_diff_full_refresh_every
+			)
+			if diff_header:
+				full_list_note = 'after navigation'
+				if self.dom_diff_full_refresh_every > 0:
+					full_list_note = f'every {self.dom_diff_full_refresh_every} steps and {full_list_note}'
+				diff_text = f' ({diff_header}, the full list is sent {full_list_note})'
+		elements_text, truncated_text = self._truncate_elements_text(elements_text)
 
 		has_content_above = False
 		has_content_below = False
@@ -269,18 +457,64 @@ class AgentMessagePrompt:
 			has_content_above = pages_above > 0
 			has_content_below = pages_below > 0
 			page_info_text = '<page_info>'
-			page_info_text += f'{pages_above:.1f} pages above, {pages_below:.1f} pages below'
+			page_info_text += f'viewport {pi.viewport_width}x{pi.viewport_height}px, page {pi.page_width}x{pi.page_height}px, '
+			page_info_text += f'scrollY {pi.scroll_y}px'
+			if pi.scroll_x > 0:
+				page_info_text += f', scrollX {pi.scroll_x}px'
+			if not has_content_above and not has_content_below:
+				page_info_text += ' (whole page visible)'
+			elif not has_content_above:
+				page_info_text += ' (at top of page)'
+			elif not has_content_below:
+				page_info_text += ' (at bottom of page)'
+			page_info_text += f'\n{pages_above:.1f} pages above, {pages_below:.1f} pages below'
 			if pages_below > 0.2:
 				page_info_text += ' — scroll down to reveal more content'
+			if pi.pixels_left > 0 or pi.pixels_right > 0:
+				page_info_text += f'\n{pi.pixels_left}px left, {pi.pixels_right}px right — the page also scrolls horizontally'
 			page_info_text += '</page_info>\n'
+			if self.flash_mode:
+				page_info_text = f'<page_info>{pages_above:.1f} pages above, {pages_below:.1f} pages below</page_info>\n'
 		if elements_text != '':
 			if not has_content_above:
 				elements_text = f'[Start of page]\n{elements_text}'
-			if not has_content_below:
+			if not has_content_below and not truncated_text:
 				elements_text = f'{elements_text}\n[End of page]'
 		else:
 			elements_text = 'empty page'
 
+		browser_state = f"""{stats_text}{self._get_tabs_description()}
+{page_info_text}
+{self._get_page_events_description()}Interactive elements{box_text}{diff_text}{truncated_text}:
+{elements_text}
+"""
+		return browser_state
+
+	def _get_computer_use_state_description(self) -> str:
+		"""Browser state for computer-use mode: no DOM elements, only what is needed to act on the screenshot."""
+		screenshot_text = ''
+		pi = self.browser_state.page_info
+		if pi:
+			# Coordinates refer to the screenshot the model sees, resized from the viewport or in device pixels
+			width, height = self.llm_screenshot_size or (
+				round(pi.viewport_width * pi.device_pixel_ratio),
+				round(pi.viewport_height * pi.device_pixel_ratio),
+			)
+			pages_above = pi.pixels_above / pi.viewport_height if pi.viewport_height > 0 else 0
+			pages_below = pi.pixels_below / pi.viewport_height if pi.viewport_height > 0 else 0
+			screenshot_text = (
+				f'<screenshot_info>screenshot {width}x{height}px, coordinates in px from its top-left corner\n'
+				f'{pages_above:.1f} pages above, {pages_below:.1f} pages below</screenshot_info>\n'
+			)
+
+		return f"""Current URL: {self.browser_state.url}
+{self._get_tabs_description()}
+{screenshot_text}
+{self._get_page_events_description()}"""
+
+	def _get_tabs_description(self) -> str:
+		if self.flash_mode and len(self.browser_state.tabs) <= 1:
+			return ''
 		tabs_text = ''
 		current_tab_candidates = []
 
@@ -295,18 +529,15 @@ class AgentMessagePrompt:
 
 		for tab in self.browser_state.tabs:
 			tabs_text += f'Tab {tab.target_id[-4:]}: {tab.url} - {tab.title[:30]}\n'
+			if tab.recent_urls:
+				tabs_text += f'  Previously visited: {" -> ".join(tab.recent_urls)}\n'
 
 		current_tab_text = f'Current tab: {current_target_id[-4:]}' if current_target_id is not None else ''
+		return f'{current_tab_text}\nAvailable tabs:\n{tabs_text}'
 
-		# Check if current page is a PDF viewer and add appropriate message
-		pdf_message = ''
-		if self.browser_state.is_pdf_viewer:
-			pdf_message = (
-				'PDF viewer cannot be rendered. In this page, DO NOT use the extract action as PDF content cannot be rendered. '
-			)
-			pdf_message += (
-				'Use the read_file action on the downloaded PDF in available_file_paths to read the full text content.\n\n'
-			)
+	def _get_page_events_description(self) -> str:
+		# Describe pages without a usable DOM (PDF viewer, browser internal pages)
+		page_notice_text = f'{self.browser_state.page_notice}\n\n' if self.browser_state.page_notice else ''
 
 		# Add recent events if available and requested
 		recent_events_text = ''
@@ -321,28 +552,38 @@ class AgentMessagePrompt:
 				closed_popups_text += f'  - {popup_msg}\n'
 			closed_popups_text += '\n'
 
-		browser_state = f"""{stats_text}{current_tab_text}
-Available tabs:
-{tabs_text}
-{page_info_text}
-{recent_events_text}{closed_popups_text}{pdf_message}Interactive elements{truncated_text}:
-{elements_text}
-"""
-		return browser_state
+		# Add JS exceptions and failed requests since the last step, e.g. a form submit that returned a 500
+		page_errors_text = ''
+		if self.browser_state.page_errors:
+			shown_errors = self.browser_state.page_errors[-MAX_PAGE_ERRORS_IN_STATE:]
+			omitted = len(self.browser_state.page_errors) - len(shown_errors)
+			page_errors_text = '<page_errors>\n'
+			if omitted:
+				page_errors_text += f'({omitted} earlier errors omitted)\n'
+			page_errors_text += ''.join(f'{error}\n' for error in shown_errors)
+			page_errors_text += '</page_errors>\n'
+
+		return f'{recent_events_text}{closed_popups_text}{page_errors_text}{page_notice_text}'
 
 	def _get_agent_state_description(self) -> str:
 		_todo_contents = self.file_system.get_todo_contents() if self.file_system else ''
-		if not len(_todo_contents):
+		if not len(_todo_contents) and not self.flash_mode:
 			_todo_contents = '[empty todo.md, fill it when applicable]'
 
+		file_system_description = self.file_system.describe() if self.file_system else 'No file system available'
+		if self.file_system and self.file_system.fallback_reason:
+			file_system_description = (
+				'Note: disk storage is unavailable, files are kept in memory and will not persist after this task.\n'
+				+ file_system_description
+			).strip('\n')
+
 		agent_state = f"""
 <file_system>
-{self.file_system.describe() if self.file_system else 'No file system available'}
+{file_system_description}
 </file_system>
-<todo_contents>
-{_todo_contents}
-</todo_contents>
 """
+		if _todo_contents:
+			agent_state += f'<todo_contents>\n{_todo_contents}\n</todo_contents>\n'
 		if self.plan_description:
 			agent_state += f'<plan>\n{self.plan_description}\n</plan>\n'
 
@@ -396,6 +637,26 @@ Available tabs:
 			logging.getLogger(__name__).warning(f'Failed to resize screenshot: {e}, using original')
 			return screenshot_b64
 
+	def _compress_screenshot(self, screenshot_b64: str) -> tuple[str, Literal['image/png', 'image/jpeg']]:
+		"""Re-encode screenshot as JPEG if screenshot_jpeg_quality is configured, returns (base64, media type)."""
+		if not self.screenshot_jpeg_quality:
+			return screenshot_b64, 'image/png'
+
+		try:
+			import base64
+			import logging
+			from io import BytesIO
+
+			from PIL import Image
+
+			img = Image.open(BytesIO(base64.b64decode(screenshot_b64))).convert('RGB')
+			buffer = BytesIO()
+			img.save(buffer, format='JPEG', quality=self.screenshot_jpeg_quality)
+			return base64.b64encode(buffer.getvalue()).decode('utf-8'), 'image/jpeg'
+		except Exception as e:
+			logging.getLogger(__name__).warning(f'Failed to compress screenshot: {e}, using original')
+			return screenshot_b64, 'image/png'
+
 	@observe_debug(ignore_input=True, ignore_output=True, name='get_user_message')
 	def get_user_message(self, use_vision: bool = True) -> UserMessage:
 		"""Get complete state as a single cached message"""
@@ -411,7 +672,10 @@ Available tabs:
 			+ '\n</agent_history>\n\n'
 		)
 		state_description += '<agent_state>\n' + self._get_agent_state_description().strip('\n') + '\n</agent_state>\n'
-		state_description += '<browser_state>\n' + self._get_browser_state_description().strip('\n') + '\n</browser_state>\n'
+		browser_state_description = (
+			self._get_computer_use_state_description() if self.computer_use_mode else self._get_browser_state_description()
+		)
+		state_description += '<browser_state>\n' + browser_state_description.strip('\n') + '\n</browser_state>\n'
 		# Only add read_state if it has content
 		read_state_description = self.read_state_description.strip('\n').strip() if self.read_state_description else ''
 		if read_state_description:
@@ -422,6 +686,9 @@ Available tabs:
 			state_description += self.page_filtered_actions + '\n'
 			state_description += '</page_specific_actions>\n'
 
+		if self.site_guidance:
+			state_description += '<site_guidance>\n' + self.site_guidance + '\n</site_guidance>\n'
+
 		# Add unavailable skills information if any
 		if self.unavailable_skills_info:
 			state_description += '\n' + self.unavailable_skills_info + '\n'
@@ -431,7 +698,7 @@ Available tabs:
 		state_description += self._get_step_meta_description()
 
 		# Sanitize surrogates from all text content
-		state_description = sanitize_surrogates(state_description)
+		state_description = sanitize_text(state_description)
 
 		# Check if we have images to include (from read_file action)
 		has_images = bool(self.read_state_images)
@@ -457,13 +724,14 @@ Available tabs:
 
 				# Resize screenshot if llm_screenshot_size is configured
 				processed_screenshot = self._resize_screenshot(screenshot)
+				processed_screenshot, screenshot_media_type = self._compress_screenshot(processed_screenshot)
 
 				# Add the screenshot
 				content_parts.append(
 					ContentPartImageParam(
 						image_url=ImageURL(
-							url=f'data:image/png;base64,{processed_screenshot}',
-							media_type='image/png',
+							url=f'data:{screenshot_media_type};base64,{processed_screenshot}',
+							media_type=screenshot_media_type,
 							detail=self.vision_detail_level,
 						),
 					)
@@ -0,0 +1,195 @@
+"""
+Redaction of sensitive page content before it reaches the LLM, the logs or the run artifacts.
+
+	agent = Agent(task=..., llm=llm, redaction=RedactionConfig.pii(selectors=['.account-number']))
+
+Elements matching the CSS selectors are blacked out on screenshots and their text is masked wherever it appears in
+the state text. Text matching the regex patterns is masked as well, e.g. card numbers, emails or IBANs.
+"""
+
+import base64
+import json
+import logging
+import re
+from io import BytesIO
+from typing import TYPE_CHECKING, Any
+
+from pydantic import BaseModel, Field
+
+if TYPE_CHECKING:
+	from browser_use.browser.session import BrowserSession
+	from browser_use.browser.views import BrowserStateSummary
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_SENSITIVE_SELECTORS = (
+	'input[type=password]',
+	'input[autocomplete^="cc-"]',
+	'input[autocomplete="one-time-code"]',
+	'[data-sensitive]',
+)
+
+PII_PATTERNS: dict[str, str] = {
+	'email': r'[\w.+-]+@[\w-]+\.[\w.-]+',
+	'card_number': r'\b[2-6](?:[ -]?\d){12,18}\b',  # only masked when the digits pass the Luhn check
+	'iban': r'\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b',
+	'us_ssn': r'\b\d{3}-\d{2}-\d{4}\b',
+}
+
+# Element text lines that are one word without digits, like the label "Balance" in a [data-sensitive] block
+_PLAIN_WORD = re.compile(r'[^\W\d_]+[:.]?')
+
+
+def passes_luhn_check(number: str) -> bool:
+	"""Whether the digits of a card number candidate have a valid Luhn checksum, e.g. rules out most timestamps and IDs."""
+	digits = [int(char) for char in number if char.isdigit()]
+	total = 0
+	for position, digit in enumerate(reversed(digits)):
+		if position % 2 == 1:
+			digit = digit * 2 - 9 if digit > 4 else digit * 2
+		total += digit
+	return total % 10 == 0
+
+
+# Finds the elements matching the selectors in the page, with their box in CSS pixels of the viewport and their text
+_MATCH_ELEMENTS_JS = """
+(selectors) => {
+	const matches = [];
+	for (const selector of selectors) {
+		let elements;
+		try { elements = document.querySelectorAll(selector); } catch (e) { continue; }
+		for (const el of elements) {
+			const rect = el.getBoundingClientRect();
+			const is_field = typeof el.value === 'string';
+			const text = is_field ? el.value : (el.innerText || '');
+			const box = {x: rect.left, y: rect.top, width: rect.width, height: rect.height};
+			matches.push({...box, text: text.slice(0, 2000), is_field});
+		}
+	}
+	return {matches, viewport_width: window.innerWidth};
+}
+"""
+
+
+class RedactionConfig(BaseModel):
+	"""What to redact, see Agent(redaction=...)."""
+
+	selectors: list[str] = Field(default_factory=lambda: list(DEFAULT_SENSITIVE_SELECTORS))
+	patterns: list[str] = Field(default_factory=list)  # regexes masked in state text, logs and artifacts
+	replacement: str = '[REDACTED]'
+	redact_screenshots: bool = True  # draw black boxes over elements matching the selectors
+	redact_logs: bool = True
+	min_text_length: int = 3  # shorter element texts are not masked, they would match all over the page
+	min_element_text_length: int = 6  # same for the text lines of non-input elements, which are often labels
+
+	@classmethod
+	def pii(cls, selectors: list[str] | None = None, patterns: list[str] | None = None, **kwargs: Any) -> 'RedactionConfig':
+		"""The default selectors plus patterns for emails, card numbers, IBANs and US social security numbers."""
+		return cls(
+			selectors=[*DEFAULT_SENSITIVE_SELECTORS, *(selectors or [])],
+			patterns=[*PII_PATTERNS.values(), *(patterns or [])],
+			**kwargs,
+		)
+
+
+class Redactor:
+	"""Applies a RedactionConfig to the browser state of each step and to any text leaving the agent."""
+
+	def __init__(self, config: RedactionConfig):
+		self.config = config
+		self._patterns = [re.compile(pattern) for pattern in config.patterns]
+		self._card_pattern = PII_PATTERNS['card_number']
+		# Texts of matched elements seen during the run, kept so they stay masked in the history of later steps
+		self._values: set[str] = set()
+
+	def redact_text(self, text: str) -> str:
+		for value in sorted(self._values, key=len, reverse=True):
+			text = text.replace(value, self.config.replacement)
+		for pattern in self._patterns:
+			if pattern.pattern == self._card_pattern:
+				text = pattern.sub(self._redact_card_number, text)
+			else:
+				text = pattern.sub(self.config.replacement, text)
+		return text
+
+	def _redact_card_number(self, match: re.Match[str]) -> str:
+		return self.config.replacement if passes_luhn_check(match.group()) else match.group()
+
+	def add_values(self, texts: list[str], element_text: bool = False) -> None:
+		"""Remember texts to mask from now on, line by line.
+
+		element_text marks the text of non-input elements: their lines must be longer, and single words without digits
+		are skipped, otherwise the labels of a [data-sensitive] block would be masked all over the page.
+		"""
+		min_length = self.config.min_element_text_length if element_text else self.config.min_text_length
+		for text in texts:
+			for line in text.splitlines():
+				line = line.strip()
+				if len(line) < min_length or (element_text and _PLAIN_WORD.fullmatch(line)):
+					continue
+				self._values.add(line)
+
+	async def redact_state(self, browser_session: 'BrowserSession', state: 'BrowserStateSummary') -> None:
+		"""Learn the texts of sensitive elements on the page and black them out on the state's screenshot."""
+		if not self.config.selectors:
+			return
+		try:
+			cdp_session = await browser_session.get_or_create_cdp_session()
+			result = await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={
+					'expression': f'({_MATCH_ELEMENTS_JS})({json.dumps(self.config.selectors)})',
+					'returnByValue': True,
+				},
+				session_id=cdp_session.session_id,
+			)
+			data = result.get('result', {}).get('value') or {}
+			matches = data.get('matches') or []
+			self.add_values([match['text'] for match in matches if match.get('is_field')])
+			self.add_values([match['text'] for match in matches if not match.get('is_field')], element_text=True)
+			if self.config.redact_screenshots and state.screenshot:
+				boxes = [match for match in matches if match['width'] > 0 and match['height'] > 0]
+				state.screenshot = mask_screenshot(state.screenshot, boxes, data.get('viewport_width'))
+		except Exception as e:
+			# Never pass on a screenshot that could not be checked for sensitive elements
+			logger.warning(f'🙈 Could not redact the page state, dropping the screenshot: {type(e).__name__}: {e}')
+			state.screenshot = None
+
+	def log_filter(self) -> 'RedactionLogFilter':
+		return RedactionLogFilter(self)
+
+
+def mask_screenshot(screenshot_b64: str, boxes: list[dict[str, float]], viewport_width: float | None = None) -> str:
+	"""Draw black boxes over the given viewport rectangles (CSS pixels) of a base64 screenshot."""
+	if not boxes:
+		return screenshot_b64
+
+	from PIL import Image, ImageDraw
+
+	image = Image.open(BytesIO(base64.b64decode(screenshot_b64)))
+	# Screenshots are taken in device pixels, the boxes are in CSS pixels
+	scale = image.width / viewport_width if viewport_width else 1.0
+	draw = ImageDraw.Draw(image)
+	for box in boxes:
+		left, top = max(0, box['x'] * scale), max(0, box['y'] * scale)
+		right = min(image.width, (box['x'] + box['width']) * scale)
+		bottom = min(image.height, (box['y'] + box['height']) * scale)
+		if right > left and bottom > top:
+			draw.rectangle((left, top, right, bottom), fill='black')
+	buffer = BytesIO()
+	image.save(buffer, format=image.format or 'PNG')
+	return base64.b64encode(buffer.getvalue()).decode('utf-8')
+
+
+class RedactionLogFilter(logging.Filter):
+	"""Masks the redacted values and patterns in log records."""
+
+	def __init__(self, redactor: Redactor):
+		super().__init__()
+		self.redactor = redactor
+
+	def filter(self, record: logging.LogRecord) -> bool:
+		message = record.getMessage()
+		redacted = self.redactor.redact_text(message)
+		if redacted != message:
+			record.msg, record.args = redacted, None
+		return True
@@ -0,0 +1,393 @@
+"""Deterministic replay of recorded agent actions against a browser session, without calling an LLM."""
+
+import asyncio
+import inspect
+import logging
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass, field
+from typing import Any
+from urllib.parse import urlparse
+
+from pydantic import TypeAdapter
+
+from browser_use.agent.views import ActionResult, AgentHistoryList
+from browser_use.browser import BrowserSession
+from browser_use.dom.views import DOMInteractedElement, DOMSelectorMap, MatchLevel
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.llm.base import BaseChatModel
+from browser_use.tools.service import Tools
+
+logger = logging.getLogger(__name__)
+
+# Actions that only make sense inside an agent run, they are not replayed
+_SKIPPED_ACTIONS = ('done',)
+
+# Actions that leave the page as it was (or only load a given page), running them again after a failed check is safe.
+# Any other action (click, input, send_keys, ...) may have changed the page already and is never run twice.
+_REPEATABLE_ACTIONS = ('navigate', 'search', 'switch', 'scroll', 'find_text', 'wait', 'extract', 'screenshot', 'dropdown_options')
+
+
+def find_matching_element(
+	historical_element: DOMInteractedElement,
+	selector_map: DOMSelectorMap,
+	logger: logging.Logger = logger,
+) -> tuple[int, MatchLevel] | None:
+	"""Find the element of the current page that corresponds to a recorded element.
+
+	Cascading matching strategy (tries each level in order):
+	1. EXACT: Full element_hash match (includes all attributes + ax_name)
+	2. STABLE: Hash with dynamic CSS classes filtered out (focus, hover, animation, etc.)
+	3. XPATH: XPath string match (structural position in DOM)
+	4. AX_NAME: Accessible name (visible text) match from accessibility tree (robust for dynamic menus)
+	5. ATTRIBUTE: Unique attribute match (name, id, aria-label) for old history files
+	"""
+	selector_items = list(selector_map.items())
+	if historical_element.frame_id:
+		same_frame_items = [
+			(index, element) for index, element in selector_items if element.frame_id == historical_element.frame_id
+		]
+		if same_frame_items:
+			selector_items = same_frame_items + [
+				(index, element) for index, element in selector_items if element.frame_id != historical_element.frame_id
+			]
+	highlight_index: int | None = None
+	match_level: MatchLevel | None = None
+
+	# Debug: log what we're looking for and what's available
+	logger.info(
+		f'🔍 Searching for element: <{historical_element.node_name}> '
+		f'hash={historical_element.element_hash} stable_hash={historical_element.stable_hash}'
+	)
+	# Log what elements are in selector_map for debugging
+	if historical_element.node_name:
+		hist_name = historical_element.node_name.lower()
+		matching_nodes = [
+			(idx, elem.node_name, elem.attributes.get('name') if elem.attributes else None)
+			for idx, elem in selector_items
+			if elem.node_name.lower() == hist_name
+		]
+		logger.info(
+			f'🔍 Selector map has {len(selector_map)} elements, '
+			f'{len(matching_nodes)} are <{hist_name.upper()}>: {matching_nodes}'
+		)
+
+	# Level 1: EXACT hash match
+	for idx, elem in selector_items:
+		if elem.element_hash == historical_element.element_hash:
+			highlight_index = idx
+			match_level = MatchLevel.EXACT
+			break
+
+	if highlight_index is None:
+		logger.debug(f'EXACT hash match failed (checked {len(selector_map)} elements)')
+
+	# Level 2: STABLE hash match (dynamic classes filtered)
+	# Use stored stable_hash (computed at save time from EnhancedDOMTreeNode - single source of truth)
+	if highlight_index is None and historical_element.stable_hash is not None:
+		for idx, elem in selector_items:
+			if elem.compute_stable_hash() == historical_element.stable_hash:
+				highlight_index = idx
+				match_level = MatchLevel.STABLE
+				logger.info('Element matched at STABLE level (dynamic classes filtered)')
+				break
+		if highlight_index is None:
+			logger.debug('STABLE hash match failed')
+	elif highlight_index is None:
+		logger.debug('STABLE hash match skipped (no stable_hash in history)')
+
+	# Level 3: XPATH match
+	if highlight_index is None and historical_element.x_path:
+		for idx, elem in selector_items:
+			if elem.xpath == historical_element.x_path:
+				highlight_index = idx
+				match_level = MatchLevel.XPATH
+				logger.info(f'Element matched at XPATH level: {historical_element.x_path}')
+				break
+		if highlight_index is None:
+			logger.debug(f'XPATH match failed for: {historical_element.x_path[-60:]}')
+
+	# Level 4: ax_name (accessible name) match - robust for dynamic SPAs with menus
+	# This uses the accessible name from the accessibility tree which is stable
+	# even when DOM structure changes (e.g., dynamically generated menu items)
+	if highlight_index is None and historical_element.ax_name:
+		hist_name = historical_element.node_name.lower()
+		hist_ax_name = historical_element.ax_name
+		for idx, elem in selector_items:
+			# Match by node type and accessible name
+			elem_ax_name = elem.ax_node.name if elem.ax_node else None
+			if elem.node_name.lower() == hist_name and elem_ax_name == hist_ax_name:
+				highlight_index = idx
+				match_level = MatchLevel.AX_NAME
+				logger.info(f'Element matched at AX_NAME level: "{hist_ax_name}"')
+				break
+		if highlight_index is None:
+			# Log available ax_names for debugging
+			same_type_ax_names = [
+				(idx, elem.ax_node.name if elem.ax_node else None)
+				for idx, elem in selector_items
+				if elem.node_name.lower() == hist_name and elem.ax_node and elem.ax_node.name
+			]
+			logger.debug(
+				f'AX_NAME match failed for <{hist_name.upper()}> ax_name="{hist_ax_name}". '
+				f'Page has {len(same_type_ax_names)} <{hist_name.upper()}> with ax_names: '
+				f'{same_type_ax_names[:5]}{"..." if len(same_type_ax_names) > 5 else ""}'
+			)
+
+	# Level 5: Unique attribute fallback (for old history files without stable_hash)
+	if highlight_index is None and historical_element.attributes:
+		hist_attrs = historical_element.attributes
+		hist_name = historical_element.node_name.lower()
+
+		# Try matching by unique identifiers: name, id, or aria-label
+		for attr_key in ['name', 'id', 'aria-label']:
+			if attr_key in hist_attrs and hist_attrs[attr_key]:
+				for idx, elem in selector_items:
+					if (
+						elem.node_name.lower() == hist_name
+						and elem.attributes
+						and elem.attributes.get(attr_key) == hist_attrs[attr_key]
+					):
+						highlight_index = idx
+						match_level = MatchLevel.ATTRIBUTE
+						logger.info(f'Element matched via {attr_key} attribute: {hist_attrs[attr_key]}')
+						break
+				if highlight_index is not None:
+					break
+
+		if highlight_index is None:
+			tried_attrs = [k for k in ['name', 'id', 'aria-label'] if k in hist_attrs and hist_attrs[k]]
+			# Log what was tried and what's available on the page for debugging
+			same_node_elements = [
+				(idx, elem.attributes.get('aria-label') or elem.attributes.get('id') or elem.attributes.get('name'))
+				for idx, elem in selector_items
+				if elem.node_name.lower() == hist_name and elem.attributes
+			]
+			logger.info(
+				f'🔍 ATTRIBUTE match failed for <{hist_name.upper()}> '
+				f'(tried: {tried_attrs}, looking for: {[hist_attrs.get(k) for k in tried_attrs]}). '
+				f'Page has {len(same_node_elements)} <{hist_name.upper()}> elements with identifiers: '
+				f'{same_node_elements[:5]}{"..." if len(same_node_elements) > 5 else ""}'
+			)
+
+	if highlight_index is None or match_level is None:
+		return None
+	return highlight_index, match_level
+
+
+@dataclass
+class ReplayStep:
+	"""A single recorded action, e.g. {'click': {'index': 12}}, and what is known about the page around it."""
+
+	action: dict[str, Any]
+	interacted_element: DOMInteractedElement | None = None
+	expected_url: str | None = None  # page the browser should be on once the action ran
+
+	@property
+	def name(self) -> str:
+		return next(iter(self.action))
+
+
+@dataclass
+class ReplayStepResult:
+	step: ReplayStep
+	success: bool
+	results: list[ActionResult] = field(default_factory=list)
+	error: str | None = None
+	attempts: int = 0
+	match_level: MatchLevel | None = None
+	skipped: bool = False
+	executed: bool = False  # the action ran at least once, even if the step failed afterwards
+
+
+# Extra success check run after each replayed action, e.g. asserting that a confirmation message is shown
+StepCheck = Callable[[ReplayStep, list[ActionResult], BrowserSession], bool | Awaitable[bool]]
+
+
+class HistoryReplayer:
+	"""Re-run a recorded action sequence without the LLM, turning an agent run into a reusable script.
+
+	Element indices change between page loads, so every action that targets an element is resolved again
+	against the current page with the same matching as Agent.rerun_history (hash, xpath, accessible name, then
+	attributes). After each action the step is checked: the action must not report an error, the page must reach
+	the URL seen in the original run (check_urls) and the optional step_check callback must return True.
+
+	Like rerun_history, a step whose element is not on the page yet is retried. Once a state-changing action
+	(click, input, send_keys, ...) ran, a failed step is reported instead of running the action a second time.
+	Use Agent.rerun_history instead to re-evaluate extract steps with an LLM and get a summary of the rerun.
+	"""
+
+	def __init__(
+		self,
+		browser_session: BrowserSession,
+		tools: Tools | None = None,
+		delay: float = 1.0,
+		max_retries: int = 2,
+		retry_delay: float = 2.0,
+		check_urls: bool = True,
+		url_timeout: float = 5.0,
+		stop_on_failure: bool = True,
+		step_check: StepCheck | None = None,
+		page_extraction_llm: BaseChatModel | None = None,
+		sensitive_data: dict[str, str | dict[str, str]] | None = None,
+		available_file_paths: list[str] | None = None,
+		file_system: FileSystem | None = None,
+	):
+		self.browser_session = browser_session
+		self.tools = tools or Tools()
+		self.delay = delay
+		self.max_retries = max_retries
+		self.retry_delay = retry_delay
+		self.check_urls = check_urls
+		self.url_timeout = url_timeout
+		self.stop_on_failure = stop_on_failure
+		self.step_check = step_check
+		self.page_extraction_llm = page_extraction_llm
+		self.sensitive_data = sensitive_data
+		self.available_file_paths = available_file_paths
+		self.file_system = file_system
+		self.ActionModel = self.tools.registry.create_action_model()
+
+	@staticmethod
+	def steps_from_history(history: AgentHistoryList) -> list[ReplayStep]:
+		"""Flatten an agent history into replay steps, the URL of the following step is the expected outcome.
+
+		Actions that failed in the original run are left out, the agent recovered from them in a later step.
+		"""
+		steps: list[ReplayStep] = []
+		for i, item in enumerate(history.history):
+			if not item.model_output:
+				continue
+			actions = [action.model_dump(exclude_unset=True, mode='json') for action in item.model_output.action if action]
+			elements = item.state.interacted_element or []
+			next_url = next((h.state.url for h in history.history[i + 1 :] if h.state.url), None)
+			for j, action in enumerate(actions):
+				if not action or (j < len(item.result) and item.result[j].error):
+					continue
+				steps.append(
+					ReplayStep(
+						action=action,
+						interacted_element=elements[j] if j < len(elements) else None,
+						expected_url=next_url if j == len(actions) - 1 else None,
+					)
+				)
+		return steps
+
+	@staticmethod
+	def steps_from_actions(actions: list[dict[str, Any]]) -> list[ReplayStep]:
+		"""Build replay steps from an exported action list, e.g. AgentHistoryList.model_actions() saved as JSON.
+
+		Each entry holds a single action plus optional 'interacted_element' and 'expected_url' keys.
+		"""
+		element_adapter = TypeAdapter(DOMInteractedElement)
+		steps: list[ReplayStep] = []
+		for entry in actions:
+			entry = dict(entry)
+			element = entry.pop('interacted_element', None)
+			if isinstance(element, dict):
+				element = element_adapter.validate_python(element)
+			expected_url = entry.pop('expected_url', None)
+			if len(entry) != 1:
+				raise ValueError(f'Each action entry needs exactly one action, got {list(entry)}')
+			steps.append(ReplayStep(action=entry, interacted_element=element, expected_url=expected_url))
+		return steps
+
+	async def replay(self, source: AgentHistoryList | list[dict[str, Any]] | list[ReplayStep]) -> list[ReplayStepResult]:
+		"""Replay all steps in order and return one result per step."""
+		if isinstance(source, AgentHistoryList):
+			steps = self.steps_from_history(source)
+		else:
+			steps = [step if isinstance(step, ReplayStep) else self.steps_from_actions([step])[0] for step in source]
+
+		await self.browser_session.start()
+
+		step_results: list[ReplayStepResult] = []
+		for i, step in enumerate(steps):
+			if i > 0 and self.delay:
+				await asyncio.sleep(self.delay)
+			step_result = await self._replay_step(step)
+			step_results.append(step_result)
+
+			if step_result.skipped:
+				logger.info(f'⏭️ Replay step {i + 1}/{len(steps)} {step.name}: skipped')
+			elif step_result.success:
+				logger.info(f'✅ Replay step {i + 1}/{len(steps)} {step.name}')
+			else:
+				logger.error(f'❌ Replay step {i + 1}/{len(steps)} {step.name} failed: {step_result.error}')
+				if self.stop_on_failure:
+					break
+		return step_results
+
+	async def _replay_step(self, step: ReplayStep) -> ReplayStepResult:
+		if step.name in _SKIPPED_ACTIONS or (step.name == 'extract' and self.page_extraction_llm is None):
+			return ReplayStepResult(step=step, success=True, skipped=True)
+
+		step_result = ReplayStepResult(step=step, success=False)
+		for attempt in range(1, self.max_retries + 2):
+			step_result.attempts = attempt
+			try:
+				step_result.error = await self._run_attempt(step, step_result)
+			except Exception as e:
+				step_result.error = f'{type(e).__name__}: {e}'
+			if step_result.error is None:
+				step_result.success = True
+				return step_result
+			if step_result.executed and step.name not in _REPEATABLE_ACTIONS:
+				logger.warning(f'Replay of {step.name} failed after it ran, not repeating it: {step_result.error}')
+				break
+			if attempt <= self.max_retries:
+				logger.warning(f'Replay of {step.name} failed (attempt {attempt}), retrying: {step_result.error}')
+				await asyncio.sleep(self.retry_delay)
+		return step_result
+
+	async def _run_attempt(self, step: ReplayStep, step_result: ReplayStepResult) -> str | None:
+		"""Run the action once, returns the reason it failed or None when the step checks passed."""
+		action = self.ActionModel.model_validate(step.action)
+
+		if step.interacted_element is not None and action.get_index() is not None:
+			state = await self.browser_session.get_browser_state_summary(include_screenshot=False)
+			match = find_matching_element(step.interacted_element, state.dom_state.selector_map)
+			if match is None:
+				return f'Could not find the recorded <{step.interacted_element.node_name}> element on the current page'
+			index, step_result.match_level = match
+			action.set_index(index)
+
+		step_result.executed = True
+		result = await self.tools.act(
+			action=action,
+			browser_session=self.browser_session,
+			page_extraction_llm=self.page_extraction_llm,
+			sensitive_data=self.sensitive_data,
+			available_file_paths=self.available_file_paths,
+			file_system=self.file_system,
+		)
+		step_result.results = [result]
+		if result.error:
+			return result.error
+
+		if self.check_urls and step.expected_url and not await self._wait_for_url(step.expected_url):
+			current_url = await self.browser_session.get_current_page_url()
+			return f'Expected to be on {step.expected_url} after {step.name}, but the page is at {current_url}'
+
+		if self.step_check is not None:
+			passed = self.step_check(step, step_result.results, self.browser_session)
+			if inspect.isawaitable(passed):
+				passed = await passed
+			if not passed:
+				return f'step_check rejected the result of {step.name}'
+		return None
+
+	async def _wait_for_url(self, expected_url: str) -> bool:
+		"""Wait until the current page is the expected one, query strings and fragments are ignored."""
+		loop = asyncio.get_running_loop()
+		deadline = loop.time() + self.url_timeout
+		while True:
+			if _same_page(await self.browser_session.get_current_page_url(), expected_url):
+				return True
+			if loop.time() >= deadline:
+				return False
+			await asyncio.sleep(0.25)
+
+
+def _same_page(url: str, other: str) -> bool:
+	a, b = urlparse(url), urlparse(other)
+	return (a.scheme, a.netloc, a.path.rstrip('/')) == (b.scheme, b.netloc, b.path.rstrip('/'))
@@ -5,13 +5,18 @@ import json
 import logging
 import re
 import tempfile
+import threading
 import time
 from collections.abc import Awaitable, Callable
+from functools import partial
 from pathlib import Path
 from typing import TYPE_CHECKING, Any, Generic, Literal, TypeVar, cast
 from urllib.parse import urlparse
 
 if TYPE_CHECKING:
+	from browser_use.agent.debug_server import AgentDebugServer
+	from browser_use.metrics import AgentMetrics
+	from browser_use.screenshots.service import ScreenshotStore
 	from browser_use.skills.views import Skill
 
 from dotenv import load_dotenv
@@ -26,8 +31,10 @@ from browser_use.agent.cloud_events import (
 from browser_use.agent.message_manager.utils import save_conversation
 from browser_use.llm.base import BaseChatModel
 from browser_use.llm.exceptions import ModelOutputTruncatedError, ModelProviderError, ModelRateLimitError
-from browser_use.llm.messages import BaseMessage, ContentPartImageParam, ContentPartTextParam, UserMessage
+from browser_use.llm.messages import BaseMessage, ContentPartImageParam, ContentPartTextParam, SystemMessage, UserMessage
+from browser_use.llm.transport import LLMTransport
 from browser_use.tokens.service import TokenCost
+from browser_use.tokens.views import UsageSummary
 
 load_dotenv()
 
@@ -37,13 +44,19 @@ from uuid_extensions import uuid7str
 
 from browser_use import Browser, BrowserProfile, BrowserSession
 from browser_use.agent.judge import construct_judge_messages
+from browser_use.agent.language import language_rejection, text_matches_language
 
 # Lazy import for gif to avoid heavy agent.views import at startup
 # from browser_use.agent.gif import create_history_gif
 from browser_use.agent.message_manager.service import (
 	MessageManager,
 )
+from browser_use.agent.notifiers import AgentNotification, NotificationEvent, Notifier, send_notification
+from browser_use.agent.playbooks import Playbook, PlaybookRegistry, format_playbook_guidance
+from browser_use.agent.procedures import ProcedureStore, format_procedure_guidance, url_domain
 from browser_use.agent.prompts import SystemPrompt
+from browser_use.agent.redaction import RedactionConfig, RedactionLogFilter, Redactor
+from browser_use.agent.replay import find_matching_element
 from browser_use.agent.views import (
 	ActionResult,
 	AgentError,
@@ -55,29 +68,38 @@ from browser_use.agent.views import (
 	AgentStepInfo,
 	AgentStructuredOutput,
 	BrowserStateHistory,
+	BudgetExceeded,
 	DetectedVariable,
 	JudgementResult,
 	MessageCompactionSettings,
+	ModelRouting,
 	PlanItem,
+	RepeatedFailureTracker,
+	StepContext,
 	StepMetadata,
+	VisionBudget,
 )
-from browser_use.browser.events import _get_timeout
+from browser_use.browser.events import DismissConsentBannerEvent, _get_timeout
 from browser_use.browser.session import DEFAULT_BROWSER_PROFILE
-from browser_use.browser.views import BrowserStateSummary
+from browser_use.browser.views import BrowserStateSummary, is_connection_error
 from browser_use.config import CONFIG
-from browser_use.dom.views import DOMInteractedElement, MatchLevel
-from browser_use.filesystem.file_system import FileSystem
+from browser_use.dom.views import DOMInteractedElement
+from browser_use.filesystem.file_system import FileSystem, FileSystemError
 from browser_use.observability import observe, observe_debug
 from browser_use.telemetry.service import ProductTelemetry
 from browser_use.telemetry.views import AgentTelemetryEvent
 from browser_use.tools.registry.views import ActionModel
 from browser_use.tools.service import Tools
+from browser_use.tools.views import AskHumanAction
 from browser_use.utils import (
 	URL_PATTERN,
 	_log_pretty_path,
 	check_latest_browser_use_version,
+	collect_sensitive_data_values,
 	get_browser_use_version,
 	is_placeholder_url,
+	redact_sensitive_string,
+	safe_slice,
 	sanitize_url_candidate,
 	time_execution_async,
 	time_execution_sync,
@@ -124,11 +146,76 @@ def log_response(response: AgentOutput, registry=None, logger=None) -> None:
 		logger.info(f'  \033[34m🎯 Next goal: {next_goal}\033[0m')
 
 
+_CONTEXT_OVERFLOW_MARKERS = (
+	'context length',
+	'context_length_exceeded',
+	'context window',
+	'maximum context',
+	'prompt is too long',
+	'too many tokens',
+	'input is too long',
+)
+
+
+def _is_context_overflow_error(error: ModelProviderError) -> bool:
+	"""Whether a provider rejected the request because the prompt exceeds the model's context window."""
+	message = str(error.message).lower()
+	return any(marker in message for marker in _CONTEXT_OVERFLOW_MARKERS)
+
+
 Context = TypeVar('Context')
+ExtractedT = TypeVar('ExtractedT', bound=BaseModel)
 
 
 AgentHookFunc = Callable[['Agent'], Awaitable[None]]
 
+# Returns True/None to approve, False to reject, or an (edited) AgentOutput to execute instead
+StepApprovalCallback = (
+	Callable[['BrowserStateSummary', 'AgentOutput', int], 'AgentOutput | bool | None']  # Sync callback
+	| Callable[['BrowserStateSummary', 'AgentOutput', int], Awaitable['AgentOutput | bool | None']]  # Async callback
+)
+
+# Receives the agent's question and returns the user's answer
+AskHumanCallback = Callable[[str], str] | Callable[[str], Awaitable[str]]
+
+
+def _call_in_daemon_thread(func: Callable[[str], Any], arg: str) -> 'asyncio.Future[Any]':
+	"""Run a blocking call like input() in a daemon thread.
+
+	Unlike asyncio.to_thread, a call that is given up on is abandoned: its late result is dropped and the thread
+	keeps neither the event loop nor the interpreter from shutting down.
+	"""
+	loop = asyncio.get_running_loop()
+	future: asyncio.Future[Any] = loop.create_future()
+
+	def _resolve(result: Any, error: BaseException | None) -> None:
+		if future.done():
+			return  # timed out or cancelled meanwhile
+		if error is not None:
+			future.set_exception(error)
+		else:
+			future.set_result(result)
+
+	def _run() -> None:
+		try:
+			result, error = func(arg), None
+		except BaseException as e:
+			result, error = None, e
+		try:
+			loop.call_soon_threadsafe(_resolve, result, error)
+		except RuntimeError:
+			pass  # the loop was closed while the call was still running
+
+	threading.Thread(target=_run, name='ask_human_callback', daemon=True).start()
+	return future
+
+
+# Returns True to give the agent one last step to call done with partial results, False to stop the run right away
+BudgetExceededCallback = Callable[['Agent', BudgetExceeded], bool] | Callable[['Agent', BudgetExceeded], Awaitable[bool]]
+
+# Inspects or changes the step through its StepContext, raising an exception fails the step
+StepHook = Callable[[StepContext], None] | Callable[[StepContext], Awaitable[None]]
+
 
 class Agent(Generic[Context, AgentStructuredOutput]):
 	@time_execution_sync('--init')
@@ -146,6 +233,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		skill_ids: list[str | Literal['*']] | None = None,
 		skills: list[str | Literal['*']] | None = None,  # Alias for skill_ids
 		skill_service: Any | None = None,
+		playbooks: PlaybookRegistry | list[Playbook] | None = None,
 		# Initial agent run parameters
 		sensitive_data: dict[str, str | dict[str, str]] | None = None,
 		initial_actions: list[dict[str, dict[str, Any]]] | None = None,
@@ -162,31 +250,54 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		) = None,
 		register_external_agent_status_raise_error_callback: Callable[[], Awaitable[bool]] | None = None,
 		register_should_stop_callback: Callable[[], Awaitable[bool]] | None = None,
+		register_step_approval_callback: StepApprovalCallback | None = None,
+		register_ask_human_callback: AskHumanCallback | None = None,
+		ask_human_timeout: float = 600,
+		register_budget_exceeded_callback: BudgetExceededCallback | None = None,
+		register_before_step_hook: StepHook | None = None,
+		register_after_step_hook: StepHook | None = None,
 		# Agent settings
 		output_model_schema: type[AgentStructuredOutput] | None = None,
 		extraction_schema: dict | None = None,
 		use_vision: bool | Literal['auto'] = True,
+		vision_budget: VisionBudget | None = None,
 		save_conversation_path: str | Path | None = None,
 		save_conversation_path_encoding: str | None = 'utf-8',
+		artifacts_dir: str | Path | None = None,
+		artifacts_zip: bool = False,
+		screenshot_store: 'ScreenshotStore | None' = None,
+		max_screenshots: int | None = None,
 		max_failures: int = 5,
 		override_system_message: str | None = None,
 		extend_system_message: str | None = None,
+		system_prompt_variables: dict[str, str] | None = None,
+		system_prompt_template: str | Path | None = None,
+		language: str | None = None,
 		generate_gif: bool | str = False,
 		available_file_paths: list[str] | None = None,
 		include_attributes: list[str] | None = None,
 		max_actions_per_step: int = 5,
 		use_thinking: bool = True,
 		flash_mode: bool = False,
+		computer_use_mode: bool = False,
+		dismiss_consent_banners: Literal['reject', 'accept'] | None = None,
 		demo_mode: bool | None = None,
 		max_history_items: int | None = None,
 		page_extraction_llm: BaseChatModel | None = None,
-		fallback_llm: BaseChatModel | None = None,
+		fallback_llm: BaseChatModel | list[BaseChatModel] | None = None,
+		model_routing: ModelRouting | None = None,
 		use_judge: bool = True,
 		ground_truth: str | None = None,
 		judge_llm: BaseChatModel | None = None,
+		llm_transport: LLMTransport | None = None,
+		metrics: 'AgentMetrics | None' = None,
+		notifiers: list[Notifier] | None = None,
+		procedure_store: ProcedureStore | None = None,
+		redaction: RedactionConfig | None = None,
 		injected_agent_state: AgentState | None = None,
 		source: str | None = None,
 		file_system_path: str | None = None,
+		file_system: FileSystem | None = None,
 		task_id: str | None = None,
 		calculate_cost: bool = False,
 		pricing_url: str | None = None,
@@ -195,22 +306,36 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		vision_detail_level: Literal['auto', 'low', 'high'] = 'auto',
 		llm_timeout: int | None = None,
 		step_timeout: int = 180,
+		max_duration: float | None = None,
 		directly_open_url: bool = True,
 		include_recent_events: bool = False,
 		sample_images: list[ContentPartTextParam | ContentPartImageParam] | None = None,
 		final_response_after_failure: bool = True,
+		interactive: bool = False,
+		debug_server: 'AgentDebugServer | bool' = False,
 		enable_planning: bool = True,
 		planning_replan_on_stall: int = 3,
 		planning_exploration_limit: int = 5,
 		loop_detection_window: int = 20,
 		loop_detection_enabled: bool = True,
+		repeated_failure_hint_after: int = 2,
+		repeated_failure_replan_after: int = 3,
+		max_repeated_failures: int = 5,
 		llm_screenshot_size: tuple[int, int] | None = None,
 		message_compaction: MessageCompactionSettings | bool | None = True,
 		max_clickable_elements_length: int = 40000,
+		max_interactive_elements: int | None = None,
+		max_attribute_length: int = 100,
+		dom_diff_mode: bool = False,
+		dom_diff_full_refresh_every: int = 5,
+		include_element_boxes: bool = False,
 		_url_shortening_limit: int = 25,
 		enable_signal_handler: bool = True,
 		**kwargs,
 	):
+		if vision_budget is not None and vision_budget.size is not None and llm_screenshot_size is None:
+			llm_screenshot_size = vision_budget.size
+
 		# Validate llm_screenshot_size
 		if llm_screenshot_size is not None:
 			if not isinstance(llm_screenshot_size, tuple) or len(llm_screenshot_size) != 2:
@@ -298,6 +423,13 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			browser_profile=browser_profile,
 			id=uuid7str()[:-4] + self.id[-4:],  # re-use the same 4-char suffix so they show up together in logs
 		)
+		if self.browser_session.browser_profile.protocol == 'bidi':
+			raise ValueError(
+				"Agent needs a CDP browser, protocol='bidi' sessions only provide the page and element APIs of "
+				'browser_session.bidi. Use protocol="cdp" (the default) for agents.'
+			)
+		# Own tab focus and selector map when the session is shared with other agents, see SessionHandle
+		self._session_handle = self.browser_session.create_handle(name=f'agent-{self.id[-4:]}')
 
 		self._demo_mode_enabled: bool = bool(self.browser_profile.demo_mode) if self.browser_session else False
 		if self._demo_mode_enabled and getattr(self.browser_profile, 'headless', False):
@@ -331,6 +463,13 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		if supports_coordinate_clicking:
 			self.tools.set_coordinate_clicking(True)
 
+		# Computer-use mode: the model only sees the screenshot and acts on its coordinates
+		if computer_use_mode:
+			if use_vision is not True:
+				logger.warning(f'computer_use_mode needs the screenshot every step, ignoring use_vision={use_vision!r}')
+				use_vision = True
+			self.tools.use_computer_use_actions()
+
 		# Handle skills vs skill_ids parameter (skills takes precedence)
 		if skills and skill_ids:
 			raise ValueError('Cannot specify both "skills" and "skill_ids" parameters. Use "skills" for the cleaner API.')
@@ -346,6 +485,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 			self.skill_service = SkillService(skill_ids=skill_ids)
 
+		# Site-specific playbooks, applied while the current page matches their domains
+		self.playbooks = playbooks if isinstance(playbooks, PlaybookRegistry) else PlaybookRegistry(playbooks)
+		self._visited_playbooks: set[str] = set()
+		self._step_playbooks: list[Playbook] = []
+
 		# Structured output - use explicit param or detect from tools
 		tools_output_model = self.tools.get_output_model()
 		if output_model_schema is not None and tools_output_model is not None:
@@ -374,10 +518,18 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.llm = llm
 		self.judge_llm = judge_llm
 
-		# Fallback LLM configuration
-		self._fallback_llm: BaseChatModel | None = fallback_llm
+		# Fallback LLM configuration: a single model or a chain tried in order
+		if isinstance(fallback_llm, list):
+			self._fallback_llms: list[BaseChatModel] = list(fallback_llm)
+		else:
+			self._fallback_llms = [fallback_llm] if fallback_llm is not None else []
+		self._fallback_index: int = 0  # Index of the next fallback in the chain
 		self._using_fallback_llm: bool = False
 		self._original_llm: BaseChatModel = llm  # Store original for reference
+		# Model routing: steps left on the strong llm before returning to the default one
+		self._routing_hold_steps: int = 0
+		# A done text in the wrong language is sent back once, after that it is accepted with a warning
+		self._language_retry_used: bool = False
 		self.directly_open_url = directly_open_url
 		self.include_recent_events = include_recent_events
 		self._url_shortening_limit = _url_shortening_limit
@@ -392,23 +544,35 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.settings = AgentSettings(
 			use_vision=use_vision,
 			vision_detail_level=vision_detail_level,
+			vision_budget=vision_budget,
+			model_routing=model_routing,
 			save_conversation_path=save_conversation_path,
 			save_conversation_path_encoding=save_conversation_path_encoding,
+			artifacts_dir=artifacts_dir,
+			artifacts_zip=artifacts_zip,
+			max_screenshots=max_screenshots,
 			max_failures=max_failures,
 			override_system_message=override_system_message,
 			extend_system_message=extend_system_message,
+			system_prompt_variables=system_prompt_variables,
+			system_prompt_template=system_prompt_template,
+			language=language,
 			generate_gif=generate_gif,
 			include_attributes=include_attributes,
 			max_actions_per_step=max_actions_per_step,
 			use_thinking=use_thinking,
 			flash_mode=flash_mode,
+			computer_use_mode=computer_use_mode,
+			dismiss_consent_banners=dismiss_consent_banners,
 			max_history_items=max_history_items,
 			page_extraction_llm=page_extraction_llm,
 			calculate_cost=calculate_cost,
 			include_tool_call_examples=include_tool_call_examples,
 			llm_timeout=llm_timeout,
 			step_timeout=step_timeout,
+			max_duration=max_duration,
 			final_response_after_failure=final_response_after_failure,
+			interactive=interactive,
 			use_judge=use_judge,
 			ground_truth=ground_truth,
 			enable_planning=enable_planning,
@@ -416,8 +580,16 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			planning_exploration_limit=planning_exploration_limit,
 			loop_detection_window=loop_detection_window,
 			loop_detection_enabled=loop_detection_enabled,
+			repeated_failure_hint_after=repeated_failure_hint_after,
+			repeated_failure_replan_after=repeated_failure_replan_after,
+			max_repeated_failures=max_repeated_failures,
 			message_compaction=message_compaction,
 			max_clickable_elements_length=max_clickable_elements_length,
+			max_interactive_elements=max_interactive_elements,
+			max_attribute_length=max_attribute_length,
+			dom_diff_mode=dom_diff_mode,
+			dom_diff_full_refresh_every=dom_diff_full_refresh_every,
+			include_element_boxes=include_element_boxes,
 		)
 
 		# Token cost service
@@ -427,6 +599,40 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.token_cost_service.register_llm(judge_llm)
 		if self.settings.message_compaction and self.settings.message_compaction.compaction_llm:
 			self.token_cost_service.register_llm(self.settings.message_compaction.compaction_llm)
+		if model_routing is not None:
+			self.token_cost_service.register_llm(model_routing.strong_llm)
+
+		# Every model the agent calls: main, extraction, judge, fallbacks, compaction and the routing strong model
+		models = [llm, page_extraction_llm, judge_llm, *self._fallback_llms]
+		if model_routing is not None:
+			models.append(model_routing.strong_llm)
+		if self.settings.message_compaction and self.settings.message_compaction.compaction_llm:
+			models.append(self.settings.message_compaction.compaction_llm)
+		models = list({id(model): model for model in models}.values())
+
+		# Send the requests of every model through the same transport
+		self.llm_transport = llm_transport
+		if llm_transport is not None:
+			for model in models:
+				applied = ', '.join(llm_transport.apply(model)) or 'nothing'
+				self.logger.debug(f'🔌 Applied LLM transport to {model.provider}/{model.model}: {applied}')
+
+		# Prometheus metrics for runs, steps, actions, LLM calls and the browser session's CDP connection
+		self.metrics = metrics
+		if metrics is not None:
+			for model in models:
+				metrics.instrument_llm(model)
+			self.browser_session.attach_metrics(metrics)
+
+		# Pushed when the run finishes or fails and when the agent waits for approval
+		self.notifiers: list[Notifier] = list(notifiers or [])
+
+		# Learned procedures: guidance from similar successful runs, and this run's procedure saved once it succeeds
+		self.procedure_store = procedure_store
+
+		# Sensitive elements masked on screenshots, and their text and matching patterns masked in state, logs and artifacts
+		self.redactor = Redactor(redaction) if redaction else None
+		self._redaction_log_filter: RedactionLogFilter | None = None
 
 		# Store signal handler setting (not part of AgentSettings as it's runtime behavior)
 		self.enable_signal_handler = enable_signal_handler
@@ -448,10 +654,16 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.agent_directory = base_tmp / f'browser_use_agent_{self.id}_{timestamp}'
 
 		# Initialize file system and screenshot service
-		self._set_file_system(file_system_path)
-		self._set_screenshot_service()
+		self._set_file_system(file_system_path, file_system)
+		self._set_screenshot_service(screenshot_store)
 
 		# Action setup
+		self.register_ask_human_callback = register_ask_human_callback
+		self.ask_human_timeout = ask_human_timeout
+		self._human_wait_started: float | None = None  # set while an ask_human answer or step approval is pending
+		self._human_wait_s = 0.0  # time the current step spent waiting for the user
+		if register_ask_human_callback is not None:
+			self._register_ask_human_action()
 		self._setup_action_models()
 		self._set_browser_use_version_and_source(source)
 
@@ -492,28 +704,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Store llm_screenshot_size in browser_session so tools can access it
 		self.browser_session.llm_screenshot_size = llm_screenshot_size
 
-		# Check if LLM is ChatAnthropic instance
-		from browser_use.llm.anthropic.chat import ChatAnthropic
-
-		is_anthropic = isinstance(self.llm, ChatAnthropic)
-
-		# Check if model is a browser-use fine-tuned model (uses simplified prompts)
-		is_browser_use_model = 'browser-use/' in self.llm.model.lower()
-
 		# Initialize message manager with state
-		# Initial system prompt with all actions - will be updated during each step
+		# Initial system prompt with all actions, rebuilt once skills are registered as actions
 		self._message_manager = MessageManager(
 			task=self.task,
-			system_message=SystemPrompt(
-				max_actions_per_step=self.settings.max_actions_per_step,
-				override_system_message=override_system_message,
-				extend_system_message=extend_system_message,
-				use_thinking=self.settings.use_thinking,
-				flash_mode=self.settings.flash_mode,
-				is_anthropic=is_anthropic,
-				is_browser_use_model=is_browser_use_model,
-				model_name=self.llm.model,
-			).get_system_message(),
+			system_message=self._build_system_message(),
 			file_system=self.file_system,
 			state=self.state.message_manager_state,
 			use_thinking=self.settings.use_thinking,
@@ -527,6 +722,16 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			sample_images=self.sample_images,
 			llm_screenshot_size=llm_screenshot_size,
 			max_clickable_elements_length=self.settings.max_clickable_elements_length,
+			max_interactive_elements=self.settings.max_interactive_elements,
+			max_attribute_length=self.settings.max_attribute_length,
+			computer_use_mode=self.settings.computer_use_mode,
+			vision_budget=self.settings.vision_budget,
+			language=self.settings.language,
+			dom_diff_mode=self.settings.dom_diff_mode,
+			dom_diff_full_refresh_every=self.settings.dom_diff_full_refresh_every,
+			include_element_boxes=self.settings.include_element_boxes,
+			redactor=self.redactor,
+			flash_mode=self.settings.flash_mode,
 		)
 
 		if self.sensitive_data:
@@ -583,6 +788,24 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.register_done_callback = register_done_callback
 		self.register_should_stop_callback = register_should_stop_callback
 		self.register_external_agent_status_raise_error_callback = register_external_agent_status_raise_error_callback
+		self.register_step_approval_callback = register_step_approval_callback
+		self.register_budget_exceeded_callback = register_budget_exceeded_callback
+		self.register_before_step_hook = register_before_step_hook
+		self.register_after_step_hook = register_after_step_hook
+		# Set when max_steps or max_duration is reached and the agent gets one last step to call done
+		self._exceeded_budget: BudgetExceeded | None = None
+
+		# Debug server: True creates one owned (and stopped on close) by this agent,
+		# pass an AgentDebugServer instance to share it between agents and keep it running afterwards
+		self.debug_server: AgentDebugServer | None = None
+		self._owns_debug_server = False
+		if debug_server is True:
+			from browser_use.agent.debug_server import AgentDebugServer
+
+			self.debug_server = AgentDebugServer()
+			self._owns_debug_server = True
+		elif debug_server:
+			self.debug_server = debug_server
 
 		# Telemetry
 		self.telemetry = ProductTelemetry()
@@ -595,6 +818,9 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		if self.settings.save_conversation_path:
 			self.settings.save_conversation_path = Path(self.settings.save_conversation_path).expanduser().resolve()
 			self.logger.info(f'💬 Saving conversation to {_log_pretty_path(self.settings.save_conversation_path)}')
+		if self.settings.artifacts_dir:
+			self.settings.artifacts_dir = Path(self.settings.artifacts_dir).expanduser().resolve()
+			self.logger.info(f'🗃️ Saving run artifacts to {_log_pretty_path(self.settings.artifacts_dir)}')
 
 		# Initialize download tracking
 		assert self.browser_session is not None, 'BrowserSession is not set up'
@@ -643,6 +869,16 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		assert self.browser_session is not None, 'BrowserSession is not set up'
 		return self.browser_session.browser_profile
 
+	@property
+	def _fallback_llm(self) -> BaseChatModel | None:
+		"""First fallback LLM in the chain, kept for single-fallback configurations."""
+		return self._fallback_llms[0] if self._fallback_llms else None
+
+	@_fallback_llm.setter
+	def _fallback_llm(self, llm: BaseChatModel | None) -> None:
+		self._fallback_llms = [llm] if llm is not None else []
+		self._fallback_index = 0
+
 	@property
 	def is_using_fallback_llm(self) -> bool:
 		"""Check if the agent is currently using the fallback LLM."""
@@ -653,6 +889,53 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		"""Get the model name of the currently active LLM."""
 		return self.llm.model if hasattr(self.llm, 'model') else 'unknown'
 
+	async def _dismiss_consent_banner(self) -> str | None:
+		"""Click away a cookie consent banner before the state is captured, returns a note for the agent history."""
+		if not self.settings.dismiss_consent_banners:
+			return None
+
+		assert self.browser_session is not None, 'BrowserSession is not set up'
+
+		try:
+			policy = self.settings.dismiss_consent_banners
+			event = self.browser_session.event_bus.dispatch(DismissConsentBannerEvent(policy=policy))
+			await event
+			return await event.event_result(raise_if_any=True, raise_if_none=False)
+		except Exception as e:
+			self.logger.debug(f'🍪 Consent banner check failed: {type(e).__name__}: {e}')
+			return None
+
+	async def _recover_unhealthy_tab(self) -> None:
+		"""Repair a crashed or unexpectedly blank tab before the state is captured, and tell the model what happened."""
+		assert self.browser_session is not None, 'BrowserSession is not set up'
+
+		try:
+			recovery = await self.browser_session.recover_unhealthy_tab()
+		except Exception as e:
+			self.logger.warning(f'🩹 Tab recovery failed: {type(e).__name__}: {e}')
+			return
+		if recovery is None:
+			return
+
+		self.logger.warning(f'🩹 {recovery.message}')
+		# Shown with the results of the last actions, which is where the model looks for what changed
+		self.state.last_result = [*(self.state.last_result or []), ActionResult(long_term_memory=recovery.message)]
+
+	def _attach_redaction_log_filter(self) -> None:
+		"""Mask redacted text in the logs of the run, on the handlers that print browser_use logs"""
+		if self.redactor is None or not self.redactor.config.redact_logs or self._redaction_log_filter is not None:
+			return
+		self._redaction_log_filter = self.redactor.log_filter()
+		for handler in logging.getLogger('browser_use').handlers or logging.getLogger().handlers:
+			handler.addFilter(self._redaction_log_filter)
+
+	def _detach_redaction_log_filter(self) -> None:
+		if self._redaction_log_filter is None:
+			return
+		for handler in [*logging.getLogger('browser_use').handlers, *logging.getLogger().handlers]:
+			handler.removeFilter(self._redaction_log_filter)
+		self._redaction_log_filter = None
+
 	async def _check_and_update_downloads(self, context: str = '') -> None:
 		"""Check for new downloads and update available file paths."""
 		if not self.has_downloads_path:
@@ -690,13 +973,23 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		else:
 			self.logger.debug(f'📁 No new downloads detected (tracking {len(current_files)} files)')
 
-	def _set_file_system(self, file_system_path: str | None = None) -> None:
+	def _set_file_system(self, file_system_path: str | None = None, file_system: FileSystem | None = None) -> None:
 		# Check for conflicting parameters
-		if self.state.file_system_state and file_system_path:
+		if self.state.file_system_state and (file_system_path or file_system):
 			raise ValueError(
-				'Cannot provide both file_system_state (from agent state) and file_system_path. '
+				'Cannot provide both file_system_state (from agent state) and file_system_path or file_system. '
 				'Either restore from existing state or create new file system at specified path, not both.'
 			)
+		if file_system_path and file_system:
+			raise ValueError('Cannot provide both file_system_path and file_system. Set the path on the FileSystem instead.')
+
+		# Use a caller-provided file system (e.g. InMemoryFileSystem or one with size limits) as-is
+		if file_system is not None:
+			self.file_system = file_system
+			self.file_system_path = str(file_system.base_dir)
+			self.state.file_system_state = self.file_system.get_state()
+			self.logger.debug(f'💾 Using provided {type(file_system).__name__} at: {self.file_system_path}')
+			return
 
 		# Check if we should restore from existing state first
 		if self.state.file_system_state:
@@ -711,14 +1004,15 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				self.logger.error(f'💾 Failed to restore file system from state: {e}')
 				raise e
 
-		# Initialize new file system
+		# Initialize new file system (in-memory if the directory is not writable, e.g. read-only containers)
 		try:
 			if file_system_path:
-				self.file_system = FileSystem(file_system_path)
+				# Other agents may share the path, each gets its own data dir under it
+				self.file_system = FileSystem.with_in_memory_fallback(file_system_path, own_data_dir=True)
 				self.file_system_path = file_system_path
 			else:
 				# Use the agent directory for file system
-				self.file_system = FileSystem(self.agent_directory)
+				self.file_system = FileSystem.with_in_memory_fallback(self.agent_directory)
 				self.file_system_path = str(self.agent_directory)
 		except Exception as e:
 			self.logger.error(f'💾 Failed to initialize file system: {e}.')
@@ -729,13 +1023,25 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		self.logger.debug(f'💾 File system path: {self.file_system_path}')
 
-	def _set_screenshot_service(self) -> None:
-		"""Initialize screenshot service using agent directory"""
+	def _set_screenshot_service(self, screenshot_store: 'ScreenshotStore | None' = None) -> None:
+		"""Initialize screenshot service using agent directory, unless a custom screenshot store is given"""
+		if screenshot_store is not None:
+			self.screenshot_service = screenshot_store
+			self.logger.debug(f'📸 Using screenshot store: {type(screenshot_store).__name__}')
+			return
 		try:
-			from browser_use.screenshots.service import ScreenshotService
+			from browser_use.screenshots.service import InMemoryScreenshotStore, ScreenshotService
 
-			self.screenshot_service = ScreenshotService(self.agent_directory)
-			self.logger.debug(f'📸 Screenshot service initialized in: {self.agent_directory}/screenshots')
+			try:
+				self.screenshot_service = ScreenshotService(self.agent_directory)
+				self.logger.debug(f'📸 Screenshot service initialized in: {self.agent_directory}/screenshots')
+			except OSError as e:
+				# Same fallback as the file system, e.g. read-only containers
+				self.screenshot_service = InMemoryScreenshotStore()
+				self.logger.warning(
+					f'📸 Could not create screenshot directory in {self.agent_directory} ({type(e).__name__}: {e}). '
+					'Keeping screenshots in memory; they will not persist after the run.'
+				)
 		except Exception as e:
 			self.logger.error(f'📸 Failed to initialize screenshot service: {e}.')
 			raise e
@@ -827,6 +1133,99 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		else:
 			return slug
 
+	def _add_procedure_guidance(self) -> None:
+		"""Show the agent procedures that completed similar tasks before, see procedure_store"""
+		if self.procedure_store is None or self.history.history:
+			return
+		domain = next((url_domain(url) for url in URL_PATTERN.findall(self.task) if url_domain(url)), None)
+		try:
+			procedures = self.procedure_store.find(self.task, domain=domain)
+		except Exception as e:
+			self.logger.warning(f'Failed to load learned procedures: {type(e).__name__}: {e}')
+			return
+		if procedures:
+			self.logger.info(f'📚 Using {len(procedures)} learned procedure(s) from similar tasks')
+			self._message_manager.add_system_note(format_procedure_guidance(procedures))
+
+	def _learn_procedure(self, agent_run_error: str | None) -> None:
+		"""Save the procedure of a successful run to the procedure_store"""
+		if self.procedure_store is None or agent_run_error is not None or not self.history.is_successful():
+			return
+		judgement = self.history.judgement()
+		if judgement is not None and judgement.get('verdict') is False:
+			return
+		try:
+			procedure = self.procedure_store.learn(self.task, self.history, redact=self._redact_sensitive_text)
+		except Exception as e:
+			self.logger.warning(f'Failed to save the learned procedure: {type(e).__name__}: {e}')
+			return
+		if procedure is not None:
+			self.logger.info(f'📚 Saved procedure with {len(procedure.steps)} steps for similar tasks')
+
+	def _register_ask_human_action(self) -> None:
+		"""Let the agent ask the user a question and wait for the answer, see register_ask_human_callback"""
+		# The action is bound to this agent, register it on a copy in case the tools are shared with other agents
+		self.tools = self.tools.copy()
+		# The agent waits for the user inside the action, don't let the action timeout cut that short
+		self.tools.action_timeouts.setdefault('ask_human', self.ask_human_timeout + 5)
+
+		@self.tools.registry.action(
+			'Ask the user a question and wait for the answer. Use it only for information you cannot find or '
+			'must not guess: 2FA or verification codes, choices between options the task leaves open, approval '
+			'before irreversible steps like payments or deleting data.',
+			param_model=AskHumanAction,
+		)
+		async def ask_human(params: AskHumanAction) -> ActionResult:
+			callback = self.register_ask_human_callback
+			assert callback is not None
+			self.logger.info(f'🙋 Asking the user: {params.question}')
+			await self._notify('human_input_needed', params.question)
+			# The step timeout is paused while the user answers, see _run_step_with_timeout
+			self._human_wait_started = time.monotonic()
+			try:
+				if inspect.iscoroutinefunction(callback):
+					answer = await asyncio.wait_for(callback(params.question), timeout=self.ask_human_timeout)
+				else:
+					# Sync callbacks like input() run in a thread so the browser connection stays alive meanwhile
+					answer = await asyncio.wait_for(_call_in_daemon_thread(callback, params.question), self.ask_human_timeout)
+					if inspect.isawaitable(answer):
+						answer = await asyncio.wait_for(answer, timeout=self.ask_human_timeout)
+			except TimeoutError:
+				if not inspect.iscoroutinefunction(callback):
+					self.logger.warning('🙋 The ask_human callback is still running, its answer will be ignored')
+				return ActionResult(error=f'The user did not answer within {self.ask_human_timeout:g} seconds.')
+			finally:
+				self._human_wait_s += time.monotonic() - self._human_wait_started
+				self._human_wait_started = None
+			answer = str(answer or '').strip()
+			if not answer:
+				return ActionResult(error='The user did not answer the question.')
+			self.logger.info('💬 The user answered')
+			memory = f'Asked the user: {params.question}\nThe user answered: {answer}'
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+	def _build_system_message(self) -> SystemMessage:
+		"""Render the system prompt with the actions currently registered in the tools"""
+		from browser_use.llm.anthropic.chat import ChatAnthropic
+
+		return SystemPrompt(
+			max_actions_per_step=self.settings.max_actions_per_step,
+			override_system_message=self.settings.override_system_message,
+			extend_system_message=self.settings.extend_system_message,
+			use_thinking=self.settings.use_thinking,
+			flash_mode=self.settings.flash_mode,
+			is_anthropic=isinstance(self.llm, ChatAnthropic),
+			# browser-use fine-tuned models use simplified prompts
+			is_browser_use_model='browser-use/' in self.llm.model.lower(),
+			model_name=self.llm.model,
+			computer_use_mode=self.settings.computer_use_mode,
+			task=self.task,
+			available_actions=self.tools.registry.get_prompt_description(),
+			prompt_variables=self.settings.system_prompt_variables,
+			language=self.settings.language,
+			template_path=self.settings.system_prompt_template,
+		).get_system_message()
+
 	async def _register_skills_as_actions(self) -> None:
 		"""Register each skill as a separate action using slug as action name"""
 		if not self.skill_service or self._skills_registered:
@@ -900,8 +1299,9 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Mark as registered
 		self._skills_registered = True
 
-		# Rebuild action models to include the new skill actions
+		# Rebuild action models and the system prompt's action list to include the new skill actions
 		self._setup_action_models()
+		self._message_manager.set_system_message(self._build_system_message())
 
 		# Reconvert initial actions with the new ActionModel type if they exist
 		if self.initial_actions:
@@ -999,11 +1399,36 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Reset control flags so agent can continue
 		self.state.stopped = False
 		self.state.paused = False
+		# Failures of the previous task shouldn't count against the new one
+		self.state.consecutive_failures = 0
+		self.state.failure_tracker = RepeatedFailureTracker()
 		agent_id_suffix = str(self.id)[-4:].replace('-', '_')
 		if agent_id_suffix and agent_id_suffix[0].isdigit():
 			agent_id_suffix = 'a' + agent_id_suffix
 		self.eventbus = EventBus(name=f'Agent_{agent_id_suffix}')
 
+	async def run_task(
+		self,
+		task: str,
+		max_steps: int = 500,
+		on_step_start: AgentHookFunc | None = None,
+		on_step_end: AgentHookFunc | None = None,
+	) -> AgentHistoryList[AgentStructuredOutput]:
+		"""Run a follow-up task on this agent, continuing from the previous run.
+
+		The new request is appended to the existing history, so the agent keeps its memory of earlier
+		steps, its file system and (with keep_alive=True) the open tabs. max_steps counts the steps of
+		this task only, the returned history contains the steps of all tasks run so far.
+		"""
+		if self.state.n_steps > 1 and not self.browser_session.browser_profile.keep_alive:
+			self.logger.warning(
+				'⚠️ The browser was closed after the previous run, the follow-up task starts with a fresh browser. '
+				'Use keep_alive=True to keep tabs open between tasks.'
+			)
+		self.add_new_task(task)
+		# run() counts steps across all tasks, offset the limit by the steps already taken
+		return await self.run(max_steps=self.state.n_steps - 1 + max_steps, on_step_start=on_step_start, on_step_end=on_step_end)
+
 	async def _check_stop_or_pause(self) -> None:
 		"""Check if the agent should stop or pause, and handle accordingly."""
 
@@ -1055,7 +1480,10 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 					self.logger.warning(f'Phase 0 captcha wait failed (non-fatal): {e}')
 
 			# Phase 1: Prepare context and timing
-			browser_state_summary = await self._prepare_context(step_info)
+			async with self._session_handle:
+				browser_state_summary = await self._prepare_context(step_info)
+
+			self._route_llm(browser_state_summary)
 
 			# Clear previous step state after context preparation (which needs
 			# them for the "previous action result" prompt) but before the LLM
@@ -1064,12 +1492,27 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			self.state.last_model_output = None
 			self.state.last_result = None
 
-			# Phase 2: Get model output and execute actions
-			await self._get_next_action(browser_state_summary)
-			await self._execute_actions()
-
-			# Phase 3: Post-processing
-			await self._post_process()
+			step_context = await self._run_before_step_hook(browser_state_summary)
+			if step_context and step_context.cancel_reason is not None:
+				self.logger.info(f'⏭️ Step {self.state.n_steps}: Cancelled by before step hook: {step_context.cancel_reason}')
+				memory = f'Step cancelled: {step_context.cancel_reason}'
+				self.state.last_result = [ActionResult(extracted_content=memory, long_term_memory=memory)]
+			else:
+				# Phase 2: Get model output and execute actions
+				# The session is not held during the LLM call, so agents sharing it can take turns
+				await self._get_next_action(browser_state_summary)
+				execute_actions = await self._confirm_step_actions(browser_state_summary)
+				async with self._session_handle:
+					if execute_actions:
+						await self._execute_actions()
+
+					# Phase 3: Post-processing
+					await self._run_playbook_after_hooks(browser_state_summary)
+					await self._post_process()
+
+			if step_context:
+				self._add_hook_results(step_context.injected_results)
+			await self._run_after_step_hook(browser_state_summary, step_context)
 
 		except Exception as e:
 			# Handle ALL exceptions in one place
@@ -1078,23 +1521,87 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		finally:
 			await self._finalize(browser_state_summary)
 
+	async def _call_step_hook(self, hook: StepHook, step_context: StepContext) -> None:
+		result = hook(step_context)
+		if inspect.isawaitable(result):
+			await result
+
+	async def _run_before_step_hook(self, browser_state_summary: BrowserStateSummary) -> StepContext | None:
+		"""Let the before step hook inspect or change the state message, add results or cancel the step"""
+		if self.register_before_step_hook is None:
+			return None
+		state_message = self._message_manager.state.history.state_message
+		assert isinstance(state_message, UserMessage), 'State message must be prepared before the step hook runs'
+
+		step_context = StepContext(
+			step_number=self.state.n_steps,
+			task=self.task,
+			browser_state=browser_state_summary,
+			state_message=state_message,
+		)
+		await self._call_step_hook(self.register_before_step_hook, step_context)
+
+		# The hook may have replaced the message instead of editing it
+		if step_context.state_message is not state_message:
+			self._message_manager.state.history.state_message = step_context.state_message
+		self._message_manager.last_state_message_text = step_context.state_message.text
+		return step_context
+
+	async def _run_after_step_hook(
+		self, browser_state_summary: BrowserStateSummary, step_context: StepContext | None
+	) -> None:
+		"""Let the after step hook inspect the model output and action results, and add results"""
+		if self.register_after_step_hook is None:
+			return
+		state_message = self._message_manager.state.history.state_message
+		after_context = StepContext(
+			step_number=self.state.n_steps,
+			task=self.task,
+			browser_state=browser_state_summary,
+			state_message=state_message if isinstance(state_message, UserMessage) else UserMessage(content=''),
+			model_output=self.state.last_model_output,
+			action_results=list(self.state.last_result or []),
+			cancel_reason=step_context.cancel_reason if step_context else None,
+			after_step=True,
+		)
+		await self._call_step_hook(self.register_after_step_hook, after_context)
+		self._add_hook_results(after_context.injected_results)
+
+	def _add_hook_results(self, results: list[ActionResult]) -> None:
+		"""Add results from step hooks to the step, before its done result so the run still ends with done"""
+		if not results:
+			return
+		last_result = list(self.state.last_result or [])
+		if last_result and last_result[-1].is_done:
+			self.state.last_result = [*last_result[:-1], *results, last_result[-1]]
+		else:
+			self.state.last_result = [*last_result, *results]
+
 	async def _prepare_context(self, step_info: AgentStepInfo | None = None) -> BrowserStateSummary:
 		"""Prepare the context for the step: browser state, action models, page actions"""
 		# step_start_time is now set in step() method
 
 		assert self.browser_session is not None, 'BrowserSession is not set up'
 
+		await self._recover_unhealthy_tab()
+		consent_note = await self._dismiss_consent_banner()
+
 		self.logger.debug(f'🌐 Step {self.state.n_steps}: Getting browser state...')
 		# Always take screenshots for all steps
 		self.logger.debug('📸 Requesting browser state with include_screenshot=True')
 		browser_state_summary = await self.browser_session.get_browser_state_summary(
 			include_screenshot=True,  # always capture even if use_vision=False so that cloud sync is useful (it's fast now anyway)
 			include_recent_events=self.include_recent_events,
+			include_dom=not self.settings.computer_use_mode,
 		)
 		if browser_state_summary.screenshot:
 			self.logger.debug(f'📸 Got browser state WITH screenshot, length: {len(browser_state_summary.screenshot)}')
 		else:
 			self.logger.debug('📸 Got browser state WITHOUT screenshot')
+		if self.redactor is not None:
+			await self.redactor.redact_state(self.browser_session, browser_state_summary)
+		# Page errors are now part of this step's state, start collecting the ones caused by this step's actions
+		self.browser_session.clear_page_errors()
 
 		# Check for new downloads after getting browser state (catches PDF auto-downloads and previous step downloads)
 		await self._check_and_update_downloads(f'Step {self.state.n_steps}: after getting browser state')
@@ -1120,6 +1627,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Render plan description for injection into agent context
 		plan_description = self._render_plan_description()
 
+		site_guidance = await self._run_playbook_before_hooks(browser_state_summary)
+
 		self._message_manager.prepare_step_state(
 			browser_state_summary=browser_state_summary,
 			model_output=self.state.last_model_output,
@@ -1127,6 +1636,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			step_info=step_info,
 			sensitive_data=self.sensitive_data,
 		)
+		if consent_note:
+			self._message_manager.add_system_note(consent_note)
 
 		await self._maybe_compact_messages(step_info)
 
@@ -1141,6 +1652,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			available_file_paths=self.available_file_paths,  # Always pass current available_file_paths
 			unavailable_skills_info=unavailable_skills_info,
 			plan_description=plan_description,
+			site_guidance=site_guidance,
 			skip_state_update=True,
 		)
 
@@ -1149,6 +1661,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self._inject_exploration_nudge()
 		self._update_loop_detector_page_state(browser_state_summary)
 		self._inject_loop_detection_nudge()
+		self._inject_repeated_failure_hint()
 		await self._force_done_after_last_step(step_info)
 		await self._force_done_after_failure()
 		return browser_state_summary
@@ -1202,6 +1715,113 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# check again if Ctrl+C was pressed before we commit the output to history
 		await self._check_stop_or_pause()
 
+	async def _confirm_step_actions(self, browser_state_summary: BrowserStateSummary) -> bool:
+		"""In interactive mode, ask for approval of the proposed actions before executing them.
+
+		Returns True if the actions should be executed. The approval callback may also return an
+		edited AgentOutput, which replaces the model output for this step.
+		"""
+		if not self.settings.interactive or self.state.last_model_output is None:
+			return True
+
+		if self.notifiers:
+			model_output = self.state.last_model_output
+			actions = ', '.join(next(iter(action.model_dump(exclude_unset=True)), '?') for action in model_output.action)
+			goal = f' to {model_output.next_goal}' if model_output.next_goal else ''
+			await self._notify('human_input_needed', f'Step {self.state.n_steps} waits for approval of {actions}{goal}')
+
+		callback = self.register_step_approval_callback or self._prompt_step_approval_stdin
+		# The step timeout is paused while the user decides, see _run_step_with_timeout
+		self._human_wait_started = time.monotonic()
+		try:
+			decision = callback(browser_state_summary, self.state.last_model_output, self.state.n_steps)
+			if inspect.isawaitable(decision):
+				decision = await decision
+		finally:
+			self._human_wait_s += time.monotonic() - self._human_wait_started
+			self._human_wait_started = None
+
+		# The user may have stopped or paused the agent while we were waiting for approval
+		await self._check_stop_or_pause()
+
+		if isinstance(decision, AgentOutput):
+			self.logger.info(f'✏️ Step {self.state.n_steps}: Actions edited by user')
+			self.state.last_model_output = decision
+			return True
+
+		if decision is False:
+			self.logger.info(f'🚫 Step {self.state.n_steps}: Actions rejected by user')
+			self.state.last_result = [
+				ActionResult(
+					extracted_content='The user rejected the proposed actions.',
+					long_term_memory='The user rejected the proposed actions. Choose a different approach.',
+				)
+			]
+			return False
+
+		return True
+
+	async def _prompt_step_approval_stdin(
+		self, browser_state_summary: BrowserStateSummary, model_output: AgentOutput, n_steps: int
+	) -> AgentOutput | bool:
+		"""Default approval callback for interactive mode: prompt on stdin.
+
+		[Enter]/y approves, n rejects, q stops the agent, and a JSON list of actions replaces the proposed ones.
+		"""
+		self._log_step_approval_prompt(model_output, n_steps)
+		while True:
+			answer = (await asyncio.to_thread(input, '   Approve? [Y/n/q or JSON actions]: ')).strip()
+			if answer.lower() in ('', 'y', 'yes'):
+				return True
+			if answer.lower() in ('n', 'no'):
+				return False
+			if answer.lower() in ('q', 'quit'):
+				self.stop()
+				return False
+			try:
+				actions = [self.ActionModel.model_validate(action) for action in json.loads(answer)]
+			except (ValueError, TypeError, ValidationError) as e:
+				print(f'   ❌ Invalid actions: {e}')
+				continue
+			return model_output.model_copy(update={'action': actions})
+
+	async def _notify(
+		self, event: NotificationEvent, message: str, success: bool | None = None, artifacts_path: str | None = None
+	) -> None:
+		"""Send a notification to the agent's notifiers, never raises"""
+		if not self.notifiers:
+			return
+		url = None
+		if self.browser_session is not None:
+			try:
+				url = await self.browser_session.get_current_page_url()
+			except Exception:
+				pass
+		notification = AgentNotification(
+			event=event,
+			agent_id=self.id,
+			task=self._redact_sensitive_text(self.task),
+			message=self._redact_sensitive_text(message.strip()),
+			steps=self.history.number_of_steps(),
+			url=self._redact_sensitive_text(url) if url else None,
+			success=success,
+			artifacts_path=artifacts_path,
+		)
+		await send_notification(self.notifiers, notification)
+
+	def _redact_sensitive_text(self, text: str) -> str:
+		"""Mask sensitive_data values and redacted page text in text that leaves the agent (notifications, procedures)"""
+		text = redact_sensitive_string(text, collect_sensitive_data_values(self.sensitive_data))
+		return self.redactor.redact_text(text) if self.redactor else text
+
+	def _log_step_approval_prompt(self, model_output: AgentOutput, n_steps: int) -> None:
+		"""Print the proposed actions for the stdin approval prompt"""
+		print(f'\n🙋 Step {n_steps} proposes {len(model_output.action)} action(s):')
+		if model_output.next_goal:
+			print(f'   🎯 {model_output.next_goal}')
+		for action in model_output.action:
+			print(f'   ▶️  {json.dumps(action.model_dump(exclude_unset=True), default=str)}')
+
 	async def _execute_actions(self) -> None:
 		"""Execute the actions from model output"""
 		if self.state.last_model_output is None:
@@ -1223,6 +1843,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		# Record executed actions for loop detection
 		self._update_loop_detector_actions()
+		self._update_failure_tracker()
 
 		# check for action errors - only count single-action steps toward consecutive failures;
 		# multi-action steps with errors are handled by loop detection and replan nudges instead
@@ -1237,6 +1858,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		# Log completion results
 		if self.state.last_result and len(self.state.last_result) > 0 and self.state.last_result[-1].is_done:
+			self._add_assertion_summary(self.state.last_result[-1])
 			success = self.state.last_result[-1].success
 			if success:
 				# Green color for success
@@ -1249,6 +1871,24 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				for i, file_path in enumerate(self.state.last_result[-1].attachments):
 					self.logger.info(f'👉 Attachment {i + 1 if total_attachments > 1 else ""}: {file_path}')
 
+	def _add_assertion_summary(self, done_result: ActionResult) -> None:
+		"""Summarize the outcomes of the run's assert actions in the final result"""
+		results = [*self.history.action_results(), *(self.state.last_result or [])]
+		assertions = [r.metadata['assertion'] for r in results if r.metadata and 'assertion' in r.metadata]
+		if not assertions:
+			return
+
+		passed = sum(1 for assertion in assertions if assertion['passed'])
+		done_result.metadata = {
+			**(done_result.metadata or {}),
+			'assertions': {'passed': passed, 'failed': len(assertions) - passed, 'results': assertions},
+		}
+		# Structured output must stay valid JSON, the summary is only in metadata then
+		if self.output_model_schema is None:
+			lines = [f'Assertions: {passed}/{len(assertions)} passed']
+			lines += [f'{"✅" if a["passed"] else "❌"} {a["description"]} (actual: {a["actual"]})' for a in assertions]
+			done_result.extracted_content = '\n'.join([done_result.extracted_content or '', '', *lines]).lstrip('\n')
+
 	async def _handle_step_error(self, error: Exception) -> None:
 		"""Handle all types of errors that can occur during a step"""
 
@@ -1261,19 +1901,13 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		# Handle browser closed/disconnected errors
 		if self._is_connection_like_error(error):
-			# If reconnection is in progress, wait for it instead of stopping
-			if self.browser_session.is_reconnecting:
+			# If the websocket dropped, wait for the reconnection (starting it if the drop wasn't noticed yet)
+			if self.browser_session.is_reconnecting or not self.browser_session.is_cdp_connected:
 				wait_timeout = self.browser_session.RECONNECT_WAIT_TIMEOUT
-				self.logger.warning(
-					f'🔄 Connection error during reconnection, waiting up to {wait_timeout}s for reconnect: {error}'
-				)
-				try:
-					await asyncio.wait_for(self.browser_session._reconnect_event.wait(), timeout=wait_timeout)
-				except TimeoutError:
-					pass
+				self.logger.warning(f'🔄 Connection error, waiting up to {wait_timeout}s for reconnect: {error}')
 
 				# Check if reconnection succeeded
-				if self.browser_session.is_cdp_connected:
+				if await self.browser_session.wait_for_reconnect(timeout=wait_timeout):
 					self.logger.info('🔄 Reconnection succeeded, retrying step...')
 					self.state.last_result = [ActionResult(error=f'Connection lost and recovered: {error}')]
 					return
@@ -1304,7 +1938,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			self.logger.log(log_level, f'{prefix}{error_msg}')
 
 		await self._demo_mode_log(f'Step error: {error_msg}', 'error', {'step': self.state.n_steps})
-		self.state.last_result = [ActionResult(error=error_msg)]
+		self.state.last_result = [ActionResult(error=error_msg, error_type=AgentError.get_error_type(error))]
 		return None
 
 	def _is_connection_like_error(self, error: Exception) -> bool:
@@ -1313,15 +1947,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		Unlike _is_browser_closed_error(), this does NOT check if the CDP client is None
 		or if reconnection is in progress — it purely looks at the error signature.
 		"""
-		error_str = str(error).lower()
-		return (
-			isinstance(error, ConnectionError)
-			or 'websocket connection closed' in error_str
-			or 'connection closed' in error_str
-			or 'browser has been closed' in error_str
-			or 'browser closed' in error_str
-			or 'no browser' in error_str
-		)
+		return is_connection_error(error)
 
 	def _is_browser_closed_error(self, error: Exception) -> bool:
 		"""Check if the browser has been closed or disconnected.
@@ -1336,16 +1962,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		if self.browser_session.is_reconnecting:
 			return False
 
-		error_str = str(error).lower()
-		is_connection_error = (
-			isinstance(error, ConnectionError)
-			or 'websocket connection closed' in error_str
-			or 'connection closed' in error_str
-			or 'browser has been closed' in error_str
-			or 'browser closed' in error_str
-			or 'no browser' in error_str
-		)
-		return is_connection_error and self.browser_session._cdp_client_root is None
+		return is_connection_error(error) and self.browser_session._cdp_client_root is None
 
 	async def _finalize(self, browser_state_summary: BrowserStateSummary | None) -> None:
 		"""Finalize the step with history, logging, and events"""
@@ -1367,6 +1984,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				step_start_time=self.step_start_time,
 				step_end_time=step_end_time,
 				step_interval=step_interval,
+				llm_provider=self.llm.provider if isinstance(getattr(self.llm, 'provider', None), str) else None,
+				llm_model=self.current_llm_model if isinstance(getattr(self.llm, 'model', None), str) else None,
 			)
 
 			# Use _make_history_item like main branch
@@ -1405,6 +2024,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			)
 			self.eventbus.dispatch(step_event)
 
+		if self.metrics is not None:
+			actions = self.state.last_model_output.action if self.state.last_model_output else []
+			action_names = [next(iter(action.model_dump(exclude_unset=True)), 'unknown') for action in actions]
+			self.metrics.record_step(action_names, self.state.last_result)
+
 		# Increment step counter after step is fully completed
 		self.state.n_steps += 1
 
@@ -1455,6 +2079,47 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			lines.append(f'{marker} {i}: {step.text}')
 		return '\n'.join(lines)
 
+	async def _run_playbook_before_hooks(self, browser_state_summary: BrowserStateSummary) -> str | None:
+		"""Collect the guidance of playbooks matching the current page and run their before hooks."""
+		self._step_playbooks = self.playbooks.get_matching(browser_state_summary.url) if self.playbooks else []
+		guidance: list[tuple[str, str]] = []
+		for playbook in self._step_playbooks:
+			if playbook.guidance:
+				guidance.append((playbook.name, playbook.guidance))
+			hooks = [playbook.before_step]
+			if playbook.name not in self._visited_playbooks:
+				self._visited_playbooks.add(playbook.name)
+				self.logger.info(f'📘 Using playbook {playbook.name} for {browser_state_summary.url}')
+				hooks.insert(0, playbook.on_first_visit)
+			for hook in hooks:
+				if hook is None:
+					continue
+				try:
+					note = await hook(self, browser_state_summary)
+				except Exception as e:
+					self.logger.warning(f'Playbook {playbook.name} hook failed (non-fatal): {type(e).__name__}: {e}')
+					continue
+				if note:
+					guidance.append((playbook.name, note))
+		return format_playbook_guidance(guidance)
+
+	async def _run_playbook_after_hooks(self, browser_state_summary: BrowserStateSummary) -> None:
+		"""Run the after hooks of the playbooks this step started with, their notes become step results."""
+		for playbook in self._step_playbooks:
+			if playbook.after_step is None:
+				continue
+			try:
+				note = await playbook.after_step(self, browser_state_summary)
+			except Exception as e:
+				self.logger.warning(f'Playbook {playbook.name} hook failed (non-fatal): {type(e).__name__}: {e}')
+				continue
+			if note:
+				note_result = ActionResult(long_term_memory=f'[{playbook.name}] {note}')
+				if self.state.last_result:
+					self.state.last_result.append(note_result)
+				else:
+					self.state.last_result = [note_result]
+
 	def _inject_replan_nudge(self) -> None:
 		"""Inject a replan nudge when stall detection threshold is met."""
 		if not self.settings.enable_planning or self.state.plan is None:
@@ -1518,6 +2183,54 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				params = {}
 			self.state.loop_detector.record_action(action_name, params)
 
+	def _update_failure_tracker(self) -> None:
+		"""Record which of the latest step's actions failed, results line up with the actions that were executed."""
+		if self.state.last_model_output is None or not self.state.last_result:
+			return
+		for action, result in zip(self.state.last_model_output.action, self.state.last_result):
+			action_data = action.model_dump(exclude_unset=True)
+			action_name = next(iter(action_data.keys()), 'unknown')
+			if action_name == 'done':
+				continue
+			params = action_data.get(action_name, {})
+			self.state.failure_tracker.record(action_name, params if isinstance(params, dict) else {}, result.error)
+
+	def _inject_repeated_failure_hint(self) -> None:
+		"""Tell the agent to stop retrying an action that keeps failing with the same params, firmer the more it fails."""
+		repeated = self.state.failure_tracker.most_repeated()
+		if repeated is None:
+			return
+		description, failures, last_error = repeated
+		replan_after = self.settings.repeated_failure_replan_after
+		hint_after = self.settings.repeated_failure_hint_after
+		if replan_after > 0 and failures >= replan_after:
+			msg = (
+				f'STRATEGY CHANGE REQUIRED: `{description}` has failed {failures} times with the same parameters '
+				f'(last error: {last_error}). Retrying it will fail again. Do not repeat it: use a different element, '
+				'action or route to the goal (e.g. navigate directly, search, use another page), and update your plan.'
+			)
+		elif hint_after > 0 and failures >= hint_after:
+			msg = (
+				f'Note: `{description}` has failed {failures} times with the same parameters (last error: {last_error}). '
+				'Check the current page state and try different parameters or another action instead.'
+			)
+		else:
+			return
+		if self.settings.max_repeated_failures > 0:
+			msg += f' The run is aborted after {self.settings.max_repeated_failures} such failures.'
+		self.logger.info(f'🔁 Repeated failure hint injected ({description} failed {failures} times)')
+		self._message_manager._add_context_message(UserMessage(content=msg))
+
+	def _repeated_failure_error(self) -> str | None:
+		"""Error to abort the run with once an action failed max_repeated_failures times with the same params."""
+		repeated = self.state.failure_tracker.most_repeated()
+		if repeated is None or self.settings.max_repeated_failures <= 0:
+			return None
+		description, failures, last_error = repeated
+		if failures < self.settings.max_repeated_failures:
+			return None
+		return f'Stopped because `{description}` failed {failures} times with the same parameters, last error: {last_error}'
+
 	def _update_loop_detector_page_state(self, browser_state_summary: BrowserStateSummary) -> None:
 		"""Record the current page state for stagnation detection."""
 		if not self.settings.loop_detection_enabled:
@@ -1526,7 +2239,10 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		element_count = len(browser_state_summary.dom_state.selector_map) if browser_state_summary.dom_state else 0
 		# Use the DOM text representation for fingerprinting
 		dom_text = ''
-		if browser_state_summary.dom_state:
+		if self.settings.computer_use_mode:
+			# Without a DOM the screenshot is what changes when the page does
+			dom_text = browser_state_summary.screenshot or ''
+		elif browser_state_summary.dom_state:
 			try:
 				dom_text = browser_state_summary.dom_state.llm_representation()
 			except Exception:
@@ -1561,15 +2277,38 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 	async def _force_done_after_last_step(self, step_info: AgentStepInfo | None = None) -> None:
 		"""Handle special processing for the last step"""
-		if step_info and step_info.is_last_step():
+		if self._exceeded_budget is not None or (step_info and step_info.is_last_step()):
 			# Add last step warning if needed
-			msg = 'You reached max_steps - this is your last step. Your only tool available is the "done" tool. No other tool is available. All other tools which you see in history or examples are not available.'
+			limit = 'max_steps'
+			if self._exceeded_budget is not None and self._exceeded_budget.reason == 'max_duration':
+				limit = f'the max_duration of {self._exceeded_budget.limit:g} seconds'
+			msg = f'You reached {limit} - this is your last step. Your only tool available is the "done" tool. No other tool is available. All other tools which you see in history or examples are not available.'
 			msg += '\nIf the task is not yet fully finished as requested by the user, set success in "done" to false! E.g. if not all steps are fully completed. Else success to true.'
 			msg += '\nInclude everything you found out for the ultimate task in the done text.'
 			self.logger.debug('Last step finishing up')
 			self._message_manager._add_context_message(UserMessage(content=msg))
 			self.AgentOutput = self.DoneAgentOutput
 
+	def _get_exceeded_budget(self, step_info: AgentStepInfo, run_started_at: float) -> BudgetExceeded | None:
+		"""Return which budget the upcoming step reaches: the last of max_steps, or a step started past max_duration"""
+		elapsed = time.monotonic() - run_started_at
+		n_steps = step_info.step_number
+		if self.settings.max_duration is not None and elapsed >= self.settings.max_duration:
+			return BudgetExceeded(
+				reason='max_duration', n_steps=n_steps, elapsed_seconds=elapsed, limit=self.settings.max_duration
+			)
+		if step_info.is_last_step():
+			return BudgetExceeded(reason='max_steps', n_steps=n_steps, elapsed_seconds=elapsed, limit=step_info.max_steps)
+		return None
+
+	async def _should_force_done_on_budget(self, budget: BudgetExceeded) -> bool:
+		"""Ask the budget exceeded callback whether to give the agent a last step to call done, defaults to yes"""
+		if self.register_budget_exceeded_callback is None:
+			return True
+		if inspect.iscoroutinefunction(self.register_budget_exceeded_callback):
+			return bool(await self.register_budget_exceeded_callback(self, budget))
+		return bool(self.register_budget_exceeded_callback(self, budget))
+
 	async def _force_done_after_failure(self) -> None:
 		"""Force done after failure"""
 		# Create recovery message
@@ -1590,6 +2329,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		final_result = self.history.final_result() or ''
 		agent_steps = self.history.agent_steps()
 		screenshot_paths = [p for p in self.history.screenshot_paths() if p is not None]
+		screenshots = None
+		if not self.screenshot_service.is_local:
+			# Screenshots in remote storage are only loaded for the steps the judge looks at
+			loaded = [await self.screenshot_service.get_screenshot(path) for path in screenshot_paths[-10:]]
+			screenshots = [screenshot for screenshot in loaded if screenshot]
 
 		# Construct input messages for judge evaluation
 		input_messages = construct_judge_messages(
@@ -1597,6 +2341,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			final_result=final_result,
 			agent_steps=agent_steps,
 			screenshot_paths=screenshot_paths,
+			screenshots=screenshots,
 			max_images=10,
 			ground_truth=self.settings.ground_truth,
 			use_vision=self.settings.use_vision,
@@ -1619,6 +2364,51 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			# Return a default judgement on failure
 			return None
 
+	async def evaluate(self) -> JudgementResult | None:
+		"""Judge the run so far with judge_llm and attach the verdict to the last step of the history.
+
+		Runs automatically when the agent calls done and use_judge=True. Call it after run() to also judge
+		runs that stopped early (max steps, failures) or were run with use_judge=False, e.g. to gate a CI
+		pipeline on history.is_validated(). Returns None if there is nothing to judge or the judge call failed.
+		"""
+		if not self.history.history or not self.history.history[-1].result:
+			return None
+
+		judgement = await self._judge_trace()
+		if judgement:
+			self.history.history[-1].result[-1].judgement = judgement
+		return judgement
+
+	async def extract(
+		self,
+		query: str,
+		output_model: type[ExtractedT],
+		max_retries: int = 2,
+		extract_links: bool = False,
+		main_content_only: bool = False,
+	) -> ExtractedT:
+		"""Extract data from the page the agent is on as an instance of output_model, e.g. after run() reached it.
+
+		Uses page_extraction_llm with output_model as the output format and retries with the validation errors when the
+		answer does not match, raises ExtractionValidationError after max_retries retries.
+		"""
+		from browser_use.tools.extraction.typed import extract_typed
+
+		if self.browser_session is None:
+			raise RuntimeError('Agent has no browser session to extract from')
+		assert self.settings.page_extraction_llm is not None
+		return await extract_typed(
+			self.browser_session,
+			self.settings.page_extraction_llm,
+			query,
+			output_model,
+			max_retries=max_retries,
+			extract_links=extract_links,
+			main_content_only=main_content_only,
+			max_chars=self.tools.extract_max_chars,
+			redact_text=self.redactor.redact_text if self.redactor else None,
+		)
+
 	async def _judge_and_log(self) -> None:
 		"""Run judge evaluation and log the verdict.
 
@@ -1626,12 +2416,10 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		last_result.success — that stays as the agent's self-report. Telemetry
 		sends both values so the eval platform can compare agent vs judge.
 		"""
-		judgement = await self._judge_trace()
+		judgement = await self.evaluate()
 
-		# Attach judgement to last action result
 		if self.history.history[-1].result[-1].is_done:
 			last_result = self.history.history[-1].result[-1]
-			last_result.judgement = judgement
 
 			# Get self-reported success
 			self_reported_success = last_result.success
@@ -1653,6 +2441,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				judge_log += f'⚖️  {verdict_color}Judge Verdict: {verdict_text}\033[0m\n'
 				if judgement.failure_reason:
 					judge_log += f'   Failure Reason: {judgement.failure_reason}\n'
+				if judgement.missing_steps:
+					judge_log += f'   Missing Steps: {"; ".join(judgement.missing_steps)}\n'
 				if judgement.reached_captcha:
 					self.logger.warning(
 						'Agent was blocked by a captcha. Cloud browsers include stealth fingerprinting and proxy rotation to avoid this.\n'
@@ -1728,6 +2518,13 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				target,
 				self.settings.save_conversation_path_encoding,
 			)
+		if self.settings.artifacts_dir and self.state.last_model_output:
+			# Kept next to the screenshots until the run ends, then copied into the artifacts bundle
+			await save_conversation(
+				input_messages,
+				self.state.last_model_output,
+				self.agent_directory / 'conversation' / f'step_{self.state.n_steps}.txt',
+			)
 
 	async def _make_history_item(
 		self,
@@ -1772,6 +2569,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		)
 
 		self.history.add_item(history_item)
+		await self._prune_screenshots()
 
 	def _remove_think_tags(self, text: str) -> str:
 		THINK_TAGS = re.compile(r'<think>.*?</think>', re.DOTALL)
@@ -1976,58 +2774,102 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 	def _try_switch_to_fallback_llm(self, error: ModelRateLimitError | ModelProviderError) -> bool:
 		"""
-		Attempt to switch to a fallback LLM after a rate limit or provider error.
+		Attempt to switch to the next fallback LLM after a rate limit or provider error.
 
-		Returns True if successfully switched to a fallback, False if no fallback available.
-		Once switched, the agent will use the fallback LLM for the rest of the run.
+		Returns True if successfully switched to a fallback, False if the chain is exhausted.
+		Once switched, the agent keeps using that fallback until it fails as well.
 		"""
-		# Already using fallback - can't switch again
-		if self._using_fallback_llm:
-			self.logger.warning(
-				f'⚠️ Fallback LLM also failed ({type(error).__name__}: {error.message}), no more fallbacks available'
-			)
-			return False
-
-		# Check if error is retryable (rate limit, auth errors, or server errors)
+		# Check if error is retryable (rate limit, auth errors, server errors or context overflow)
 		# 401: API key invalid/expired - fallback to different provider
 		# 402: Insufficient credits/payment required - fallback to different provider
 		# 429: Rate limit exceeded
 		# 500, 502, 503, 504: Server errors
 		# ModelOutputTruncatedError: not retryable on the same model, but a fallback may have a higher cap
+		# Context overflow (400): the prompt may still fit the context window of a fallback model
 		retryable_status_codes = {401, 402, 429, 500, 502, 503, 504}
-		is_retryable = isinstance(error, (ModelRateLimitError, ModelOutputTruncatedError)) or (
-			hasattr(error, 'status_code') and error.status_code in retryable_status_codes
+		is_retryable = (
+			isinstance(error, (ModelRateLimitError, ModelOutputTruncatedError))
+			or (hasattr(error, 'status_code') and error.status_code in retryable_status_codes)
+			or _is_context_overflow_error(error)
 		)
 
 		if not is_retryable:
 			return False
 
-		# Check if we have a fallback LLM configured
-		if self._fallback_llm is None:
+		if not self._fallback_llms:
 			self.logger.warning(f'⚠️ LLM error ({type(error).__name__}: {error.message}) but no fallback_llm configured')
 			return False
 
-		self._log_fallback_switch(error, self._fallback_llm)
+		# Chain exhausted - every fallback has been tried
+		if self._fallback_index >= len(self._fallback_llms):
+			self.logger.warning(
+				f'⚠️ Fallback LLM also failed ({type(error).__name__}: {error.message}), no more fallbacks available'
+			)
+			return False
+
+		fallback = self._fallback_llms[self._fallback_index]
+		self._fallback_index += 1
+		self._log_fallback_switch(error, fallback)
 
 		# Switch to the fallback LLM
-		self.llm = self._fallback_llm
+		self.llm = fallback
 		self._using_fallback_llm = True
 
 		# Register the fallback LLM for token cost tracking
-		self.token_cost_service.register_llm(self._fallback_llm)
+		self.token_cost_service.register_llm(fallback)
 
 		return True
 
+	def _route_llm(self, browser_state_summary: BrowserStateSummary) -> None:
+		"""Pick the default or the strong llm for this step, based on the previous step and the page size."""
+		routing = self.settings.model_routing
+		# A fallback switch after provider errors takes precedence over routing
+		if routing is None or self._using_fallback_llm:
+			return
+
+		last_result = self.state.last_result or []
+		last_step_failed = any(r.error for r in last_result)
+		reason = None
+		if self.state.consecutive_failures >= routing.escalate_after_failures:
+			reason = f'{self.state.consecutive_failures} consecutive failures'
+		elif routing.escalate_on_parse_error and any(
+			r.error_type == 'invalid_model_output' or 'Could not parse response' in (r.error or '') for r in last_result
+		):
+			reason = 'unparseable model output'
+
+		if reason is not None:
+			self._routing_hold_steps = routing.deescalate_after_steps
+		elif self._routing_hold_steps > 0:
+			# A failure on the strong llm restarts the count of successful steps needed to de-escalate
+			if last_step_failed:
+				self._routing_hold_steps = routing.deescalate_after_steps
+			else:
+				self._routing_hold_steps -= 1
+
+		element_count = len(browser_state_summary.dom_state.selector_map)
+		if reason is None and routing.escalate_above_elements is not None and element_count > routing.escalate_above_elements:
+			reason = f'{element_count} elements on the page'
+
+		llm = routing.strong_llm if reason is not None or self._routing_hold_steps > 0 else self._original_llm
+		if llm is self.llm:
+			return
+		if llm is routing.strong_llm:
+			self.logger.info(f'⬆️ Step {self.state.n_steps}: Escalating to {llm.model} ({reason})')
+		else:
+			self.logger.info(f'⬇️ Step {self.state.n_steps}: Back to {llm.model}')
+		self.llm = llm
+
 	def _log_fallback_switch(self, error: ModelRateLimitError | ModelProviderError, fallback: BaseChatModel) -> None:
 		"""Log when switching to a fallback LLM."""
-		original_model = self._original_llm.model if hasattr(self._original_llm, 'model') else 'unknown'
+		failed_model = self.llm.model if hasattr(self.llm, 'model') else 'unknown'
 		fallback_model = fallback.model if hasattr(fallback, 'model') else 'unknown'
 		error_type = type(error).__name__
 		status_code = getattr(error, 'status_code', 'N/A')
+		which = 'Primary' if self.llm is self._original_llm else 'Fallback'
 
 		self.logger.warning(
-			f'⚠️ Primary LLM ({original_model}) failed with {error_type} (status={status_code}), '
-			f'switching to fallback LLM ({fallback_model})'
+			f'⚠️ {which} LLM ({failed_model}) failed with {error_type} (status={status_code}), '
+			f'switching to fallback LLM {self._fallback_index}/{len(self._fallback_llms)} ({fallback_model})'
 		)
 
 	async def _log_agent_run(self) -> None:
@@ -2080,7 +2922,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 					if key == 'index':
 						param_summary.append(f'#{value}')
 					elif key == 'text' and isinstance(value, str):
-						text_preview = value[:30] + '...' if len(value) > 30 else value
+						text_preview = safe_slice(value, 30) + '...' if len(value) > 30 else value
 						param_summary.append(f'text="{text_preview}"')
 					elif key == 'url':
 						param_summary.append(f'url="{value}"')
@@ -2097,6 +2939,17 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Previously truncated long entries; keep full text for better context in demo panel
 		return message.strip()
 
+	async def _start_debug_server(self) -> None:
+		"""Start the debug server (if not already running) and expose this run's steps on it"""
+		assert self.debug_server is not None
+		try:
+			await self.debug_server.start()
+		except OSError as e:
+			self.logger.warning(f'🐞 Could not start debug server on {self.debug_server.url}: {e}')
+			return
+		run_id = self.debug_server.register(self)
+		self.logger.info(f'🐞 Inspect this run at {self.debug_server.run_url(run_id)}')
+
 	async def _demo_mode_log(self, message: str, level: str = 'info', metadata: dict[str, Any] | None = None) -> None:
 		if not self._demo_mode_enabled or not message or self.browser_session is None:
 			return
@@ -2438,6 +3291,32 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		return None
 
+	async def _run_step_with_timeout(self, step_info: AgentStepInfo) -> None:
+		"""Run a step with the step timeout, the time spent waiting for ask_human answers or step approval does not count"""
+		task = asyncio.ensure_future(self.step(step_info))
+		self._human_wait_s = 0.0
+		started = time.monotonic()
+		try:
+			while True:
+				now = time.monotonic()
+				if self._human_wait_started is not None:
+					# Paused until the user answered, check back regularly
+					wait = 0.5
+				else:
+					wait = self.settings.step_timeout - (now - started - self._human_wait_s)
+					if wait <= 0:
+						raise TimeoutError
+				done, _ = await asyncio.wait({task}, timeout=wait)
+				if done:
+					return task.result()
+		finally:
+			if not task.done():
+				task.cancel()
+				try:
+					await task
+				except asyncio.CancelledError:
+					pass
+
 	async def _execute_step(
 		self,
 		step: int,
@@ -2464,10 +3343,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		self.logger.debug(f'🚶 Starting step {step + 1}/{max_steps}...')
 
 		try:
-			await asyncio.wait_for(
-				self.step(step_info),
-				timeout=self.settings.step_timeout,
-			)
+			await self._run_step_with_timeout(step_info)
 			self.logger.debug(f'✅ Completed step {step + 1}/{max_steps}')
 		except TimeoutError:
 			# Handle step timeout gracefully
@@ -2476,6 +3352,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			await self._demo_mode_log(error_msg, 'error', {'step': step + 1})
 			self.state.consecutive_failures += 1
 			self.state.last_result = [ActionResult(error=error_msg)]
+			self._record_timed_out_step(step + 1, error_msg)
 			# Ensure step counter advances on timeout — _finalize() may have
 			# been skipped or returned early due to the cancellation.
 			if self.state.n_steps == step + 1:
@@ -2501,6 +3378,54 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		return False
 
+	async def _prune_screenshots(self) -> None:
+		"""Delete the stored screenshots of all but the max_screenshots most recent steps"""
+		max_screenshots = self.settings.max_screenshots
+		if max_screenshots is None:
+			return
+		with_screenshots = [item for item in self.history.history if item.state.screenshot_path]
+		for item in with_screenshots[: max(0, len(with_screenshots) - max_screenshots)]:
+			screenshot_path = item.state.screenshot_path
+			assert screenshot_path is not None
+			try:
+				await self.screenshot_service.delete_screenshot(screenshot_path)
+			except Exception as e:
+				self.logger.debug(f'📸 Failed to delete old screenshot {screenshot_path}: {e}')
+			item.state.screenshot_path = None
+
+	def _record_timed_out_step(self, step_number: int, error_msg: str) -> None:
+		"""Add a failed history item for a step that was cancelled before _finalize() could record it"""
+		last_item = self.history.history[-1] if self.history.history else None
+		if last_item and last_item.metadata and last_item.metadata.step_number == step_number:
+			return
+
+		cached_state = self.browser_session._cached_browser_state_summary if self.browser_session else None
+		self.history.add_item(
+			AgentHistory(
+				model_output=self.state.last_model_output,
+				result=[ActionResult(error=error_msg, include_in_memory=True)],
+				state=BrowserStateHistory(
+					url=cached_state.url if cached_state else '',
+					title=cached_state.title if cached_state else '',
+					tabs=cached_state.tabs if cached_state else [],
+					interacted_element=[],
+					screenshot_path=None,
+				),
+				metadata=StepMetadata(
+					step_number=step_number,
+					step_start_time=self.step_start_time,
+					step_end_time=time.time(),
+				),
+			)
+		)
+
+	async def _get_usage_summary(self) -> UsageSummary:
+		"""Token usage of the run, with the screenshots sent and skipped under the vision budget"""
+		summary = await self.token_cost_service.get_usage_summary()
+		summary.screenshots_sent = self.state.message_manager_state.screenshots_sent
+		summary.screenshots_skipped = self.state.message_manager_state.screenshots_skipped
+		return summary
+
 	@observe(name='agent.run', ignore_input=True, ignore_output=True)
 	@time_execution_async('--run')
 	async def run(
@@ -2536,6 +3461,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			disabled=not self.enable_signal_handler,
 		)
 		signal_handler.register()
+		self._attach_redaction_log_filter()
 
 		try:
 			await self._log_agent_run()
@@ -2575,6 +3501,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			# Register skills as actions if SkillService is configured
 			await self._register_skills_as_actions()
 
+			self._add_procedure_guidance()
+
+			if self.debug_server is not None:
+				await self._start_debug_server()
+
 			# Normally there was no try catch here but the callback can raise an InterruptedError.
 			# Wrap with step_timeout so initial actions (usually a single URL navigate) can't
 			# hang indefinitely on a silent CDP WebSocket — without this the agent would take
@@ -2600,6 +3531,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			self.logger.debug(
 				f'🔄 Starting main execution loop with max {max_steps} steps (currently at step {self.state.n_steps})...'
 			)
+			run_started_at = time.monotonic()
+			self._exceeded_budget = None
 			while self.state.n_steps <= max_steps:
 				current_step = self.state.n_steps - 1  # Convert to 0-indexed for step_info
 
@@ -2617,6 +3550,20 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 					agent_run_error = f'Stopped due to {self.settings.max_failures} consecutive failures'
 					break
 
+				repeated_failure_error = self._repeated_failure_error()
+				if repeated_failure_error is not None:
+					agent_run_error = repeated_failure_error
+					self.logger.error(f'❌ {agent_run_error}')
+					self.history.add_item(
+						AgentHistory(
+							model_output=None,
+							result=[ActionResult(error=agent_run_error, include_in_memory=True)],
+							state=BrowserStateHistory(url='', title='', tabs=[], interacted_element=[], screenshot_path=None),
+							metadata=None,
+						)
+					)
+					break
+
 				# Check control flags before each step
 				if self.state.stopped:
 					self.logger.info('🛑 Agent stopped')
@@ -2624,6 +3571,21 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 					break
 
 				step_info = AgentStepInfo(step_number=current_step, max_steps=max_steps)
+				exceeded_budget = self._get_exceeded_budget(step_info, run_started_at)
+				if exceeded_budget is not None and exceeded_budget.reason == 'max_duration':
+					# The last step to call done was already given and didn't finish the task
+					if self._exceeded_budget is not None:
+						agent_run_error = f'Failed to complete task within max_duration of {self.settings.max_duration:g} seconds'
+						self.logger.info(f'❌ {agent_run_error}')
+						break
+					self.logger.info(f'⏱️ Reached max_duration after {exceeded_budget.elapsed_seconds:.0f}s')
+				if exceeded_budget is not None:
+					if not await self._should_force_done_on_budget(exceeded_budget):
+						agent_run_error = f'Stopped after reaching {exceeded_budget.reason}'
+						self.logger.info(f'🛑 {agent_run_error}')
+						break
+					self._exceeded_budget = exceeded_budget
+
 				is_done = await self._execute_step(current_step, max_steps, step_info, on_step_start, on_step_end)
 
 				if is_done:
@@ -2654,7 +3616,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 				self.logger.info(f'❌ {agent_run_error}')
 
-			self.history.usage = await self.token_cost_service.get_usage_summary()
+			self.history.usage = await self._get_usage_summary()
 
 			# set the model output schema and call it on the fly
 			if self.history._output_model_schema is None and self.output_model_schema is not None:
@@ -2667,7 +3629,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			self.logger.debug('Got KeyboardInterrupt during execution, returning current history')
 			agent_run_error = 'KeyboardInterrupt'
 
-			self.history.usage = await self.token_cost_service.get_usage_summary()
+			self.history.usage = await self._get_usage_summary()
 
 			return self.history
 
@@ -2683,6 +3645,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				await self._demo_mode_log(f'Agent stopped: {agent_run_error}', 'error', {'tag': 'run'})
 			# Log token usage summary
 			await self.token_cost_service.log_usage_summary()
+			if self.settings.vision_budget is not None:
+				mm_state = self.state.message_manager_state
+				self.logger.info(
+					f'📷 Vision budget: {mm_state.screenshots_sent} screenshots sent, {mm_state.screenshots_skipped} skipped'
+				)
 
 			# Unregister signal handlers before cleanup
 			signal_handler.unregister()
@@ -2696,6 +3663,9 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				# ADDED: Info message when custom telemetry for SIGINT was already logged
 				self.logger.debug('Telemetry for force exit (SIGINT) was logged by custom exit callback.')
 
+			if self.metrics is not None:
+				self.metrics.record_run(self.history, error=agent_run_error)
+
 			# NOTE: CreateAgentSessionEvent and CreateAgentTaskEvent are now emitted at the START of run()
 			# to match backend requirements for CREATE events to be fired when entities are created,
 			# not when they are completed
@@ -2712,19 +3682,45 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				# Lazy import gif module to avoid heavy startup cost
 				from browser_use.agent.gif import create_history_gif
 
-				create_history_gif(task=self.task, history=self.history, output_path=output_path)
+				create_history_gif(
+					task=self.task, history=self.history, output_path=output_path, screenshot_store=self.screenshot_service
+				)
 
 				# Only emit output file event if GIF was actually created
 				if Path(output_path).exists():
 					output_event = await CreateAgentOutputFileEvent.from_agent_and_file(self, output_path)
 					self.eventbus.dispatch(output_event)
 
+			self._learn_procedure(agent_run_error)
+
+			bundle: Path | None = None
+			if self.settings.artifacts_dir:
+				try:
+					bundle_name = f'browser_use_run_{self.id}_{int(time.time())}'
+					bundle_dir = Path(self.settings.artifacts_dir) / bundle_name
+					bundle = self.export_artifacts(bundle_dir, as_zip=self.settings.artifacts_zip)
+					self.logger.info(f'🗃️ Run artifacts saved to {_log_pretty_path(bundle)}')
+				except Exception as e:
+					self.logger.error(f'Failed to export run artifacts: {type(e).__name__}: {e}')
+
+			if self.notifiers:
+				success = self.history.is_successful()
+				final_result = self.history.final_result()
+				artifacts_path = str(bundle) if bundle else None
+				if agent_run_error is None and self.history.is_done() and success is not False:
+					await self._notify('run_succeeded', final_result or 'Task completed', True, artifacts_path)
+				else:
+					errors = [error for error in self.history.errors() if error]
+					message = agent_run_error or final_result or (errors[-1] if errors else 'The agent stopped before finishing')
+					await self._notify('run_failed', message, False, artifacts_path)
+
 			# Log final messages to user based on outcome
 			self._log_final_outcome_messages()
 
 			# Stop the event bus gracefully, waiting for all events to be processed
 			# Configurable via TIMEOUT_AgentEventBusStop env var (default: 3.0s)
 			await self.eventbus.stop(clear=True, timeout=_get_timeout('TIMEOUT_AgentEventBusStop', 3.0))
+			self._detach_redaction_log_filter()
 
 			await self.close()
 
@@ -2790,8 +3786,12 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 					sensitive_data=self.sensitive_data,
 					available_file_paths=self.available_file_paths,
 					extraction_schema=self.extraction_schema,
+					redact_text=self.redactor.redact_text if self.redactor else None,
 				)
 
+				if result.is_done and not result.error and self._done_in_wrong_language(result):
+					result = ActionResult(error=language_rejection(self.settings.language or ''))
+
 				if result.error:
 					await self._demo_mode_log(
 						f'Action "{action_name}" failed: {result.error}',
@@ -2850,6 +3850,20 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 
 		return results
 
+	def _done_in_wrong_language(self, result: ActionResult) -> bool:
+		"""Whether a done text should be sent back for not being in the configured language (only once per run)."""
+		language = self.settings.language
+		if not language or self._language_retry_used or self.output_model_schema is not None:
+			return False
+		# A forced final step (max_steps, max_failures) has no step left to retry in
+		if self.AgentOutput is self.DoneAgentOutput:
+			return False
+		if text_matches_language(result.extracted_content or '', language) is not False:
+			return False
+		self._language_retry_used = True
+		self.logger.warning(f'🌐 Final answer is not in {language}, asking the agent to translate it')
+		return True
+
 	async def _log_action(self, action, action_name: str, action_num: int, total_actions: int) -> None:
 		"""Log the action before execution with colored formatting"""
 		# Color definitions
@@ -2877,7 +3891,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			for param_name, value in params.items():
 				# Truncate long values for readability
 				if isinstance(value, str) and len(value) > 150:
-					display_value = value[:150] + '...'
+					display_value = safe_slice(value, 150) + '...'
 				elif isinstance(value, list) and len(str(value)) > 200:
 					display_value = str(value)[:200] + '...'
 				else:
@@ -3020,7 +4034,7 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		"""
 		from browser_use.agent.prompts import get_ai_step_system_prompt, get_ai_step_user_prompt, get_rerun_summary_message
 		from browser_use.llm.messages import SystemMessage, UserMessage
-		from browser_use.utils import sanitize_surrogates
+		from browser_use.utils import sanitize_text
 
 		# Use provided LLM or agent's LLM
 		llm = ai_step_llm or self.llm
@@ -3059,8 +4073,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			stats_summary += f' (filtered {chars_filtered:,} chars of noise)'
 
 		# Sanitize content
-		content = sanitize_surrogates(content)
-		query = sanitize_surrogates(query)
+		content = sanitize_text(content)
+		query = sanitize_text(query)
 
 		# Get prompts from prompts.py
 		system_prompt = get_ai_step_system_prompt()
@@ -3088,8 +4102,11 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 				memory = extracted_content
 				include_extracted_content_only_once = False
 			else:
-				file_name = await self.file_system.save_extracted_content(extracted_content)
-				memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+				try:
+					file_name = await self.file_system.save_extracted_content(extracted_content)
+					memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+				except FileSystemError as e:
+					memory = f'Query: {query}\nContent shown once in <read_state>, not saved to a file: {e}'
 				include_extracted_content_only_once = True
 
 			self.logger.info(f'🤖 AI Step: {memory}')
@@ -3288,7 +4305,8 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		# Execute initial actions if provided
 		if self.initial_actions and not self.state.follow_up_task:
 			self.logger.debug(f'⚡ Executing {len(self.initial_actions)} initial actions...')
-			result = await self.multi_act(self.initial_actions)
+			async with self._session_handle:
+				result = await self.multi_act(self.initial_actions)
 			# update result 1 to mention that its was automatically loaded
 			if result and self.initial_url and result[0].long_term_memory:
 				result[0].long_term_memory = f'Found initial url and automatically loaded it. {result[0].long_term_memory}'
@@ -3539,147 +4557,15 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 		Update action indices based on current page state.
 		Returns updated action or None if element cannot be found.
 
-		Cascading matching strategy (tries each level in order):
-		1. EXACT: Full element_hash match (includes all attributes + ax_name)
-		2. STABLE: Hash with dynamic CSS classes filtered out (focus, hover, animation, etc.)
-		3. XPATH: XPath string match (structural position in DOM)
-		4. AX_NAME: Accessible name match from accessibility tree (robust for dynamic menus)
-		5. ATTRIBUTE: Unique attribute match (name, id, aria-label) for old history files
+		Elements are matched with the cascading strategy of find_matching_element (hash, xpath, ax_name, attributes).
 		"""
 		if not historical_element or not browser_state_summary.dom_state.selector_map:
 			return action
 
-		selector_map = browser_state_summary.dom_state.selector_map
-		selector_items = list(selector_map.items())
-		if historical_element.frame_id:
-			same_frame_items = [
-				(index, element) for index, element in selector_items if element.frame_id == historical_element.frame_id
-			]
-			if same_frame_items:
-				selector_items = same_frame_items + [
-					(index, element) for index, element in selector_items if element.frame_id != historical_element.frame_id
-				]
-		highlight_index: int | None = None
-		match_level: MatchLevel | None = None
-
-		# Debug: log what we're looking for and what's available
-		self.logger.info(
-			f'🔍 Searching for element: <{historical_element.node_name}> '
-			f'hash={historical_element.element_hash} stable_hash={historical_element.stable_hash}'
-		)
-		# Log what elements are in selector_map for debugging
-		if historical_element.node_name:
-			hist_name = historical_element.node_name.lower()
-			matching_nodes = [
-				(idx, elem.node_name, elem.attributes.get('name') if elem.attributes else None)
-				for idx, elem in selector_items
-				if elem.node_name.lower() == hist_name
-			]
-			self.logger.info(
-				f'🔍 Selector map has {len(selector_map)} elements, '
-				f'{len(matching_nodes)} are <{hist_name.upper()}>: {matching_nodes}'
-			)
-
-		# Level 1: EXACT hash match
-		for idx, elem in selector_items:
-			if elem.element_hash == historical_element.element_hash:
-				highlight_index = idx
-				match_level = MatchLevel.EXACT
-				break
-
-		if highlight_index is None:
-			self.logger.debug(f'EXACT hash match failed (checked {len(selector_map)} elements)')
-
-		# Level 2: STABLE hash match (dynamic classes filtered)
-		# Use stored stable_hash (computed at save time from EnhancedDOMTreeNode - single source of truth)
-		if highlight_index is None and historical_element.stable_hash is not None:
-			for idx, elem in selector_items:
-				if elem.compute_stable_hash() == historical_element.stable_hash:
-					highlight_index = idx
-					match_level = MatchLevel.STABLE
-					self.logger.info('Element matched at STABLE level (dynamic classes filtered)')
-					break
-			if highlight_index is None:
-				self.logger.debug('STABLE hash match failed')
-		elif highlight_index is None:
-			self.logger.debug('STABLE hash match skipped (no stable_hash in history)')
-
-		# Level 3: XPATH match
-		if highlight_index is None and historical_element.x_path:
-			for idx, elem in selector_items:
-				if elem.xpath == historical_element.x_path:
-					highlight_index = idx
-					match_level = MatchLevel.XPATH
-					self.logger.info(f'Element matched at XPATH level: {historical_element.x_path}')
-					break
-			if highlight_index is None:
-				self.logger.debug(f'XPATH match failed for: {historical_element.x_path[-60:]}')
-
-		# Level 4: ax_name (accessible name) match - robust for dynamic SPAs with menus
-		# This uses the accessible name from the accessibility tree which is stable
-		# even when DOM structure changes (e.g., dynamically generated menu items)
-		if highlight_index is None and historical_element.ax_name:
-			hist_name = historical_element.node_name.lower()
-			hist_ax_name = historical_element.ax_name
-			for idx, elem in selector_items:
-				# Match by node type and accessible name
-				elem_ax_name = elem.ax_node.name if elem.ax_node else None
-				if elem.node_name.lower() == hist_name and elem_ax_name == hist_ax_name:
-					highlight_index = idx
-					match_level = MatchLevel.AX_NAME
-					self.logger.info(f'Element matched at AX_NAME level: "{hist_ax_name}"')
-					break
-			if highlight_index is None:
-				# Log available ax_names for debugging
-				same_type_ax_names = [
-					(idx, elem.ax_node.name if elem.ax_node else None)
-					for idx, elem in selector_items
-					if elem.node_name.lower() == hist_name and elem.ax_node and elem.ax_node.name
-				]
-				self.logger.debug(
-					f'AX_NAME match failed for <{hist_name.upper()}> ax_name="{hist_ax_name}". '
-					f'Page has {len(same_type_ax_names)} <{hist_name.upper()}> with ax_names: '
-					f'{same_type_ax_names[:5]}{"..." if len(same_type_ax_names) > 5 else ""}'
-				)
-
-		# Level 5: Unique attribute fallback (for old history files without stable_hash)
-		if highlight_index is None and historical_element.attributes:
-			hist_attrs = historical_element.attributes
-			hist_name = historical_element.node_name.lower()
-
-			# Try matching by unique identifiers: name, id, or aria-label
-			for attr_key in ['name', 'id', 'aria-label']:
-				if attr_key in hist_attrs and hist_attrs[attr_key]:
-					for idx, elem in selector_items:
-						if (
-							elem.node_name.lower() == hist_name
-							and elem.attributes
-							and elem.attributes.get(attr_key) == hist_attrs[attr_key]
-						):
-							highlight_index = idx
-							match_level = MatchLevel.ATTRIBUTE
-							self.logger.info(f'Element matched via {attr_key} attribute: {hist_attrs[attr_key]}')
-							break
-					if highlight_index is not None:
-						break
-
-			if highlight_index is None:
-				tried_attrs = [k for k in ['name', 'id', 'aria-label'] if k in hist_attrs and hist_attrs[k]]
-				# Log what was tried and what's available on the page for debugging
-				same_node_elements = [
-					(idx, elem.attributes.get('aria-label') or elem.attributes.get('id') or elem.attributes.get('name'))
-					for idx, elem in selector_items
-					if elem.node_name.lower() == hist_name and elem.attributes
-				]
-				self.logger.info(
-					f'🔍 ATTRIBUTE match failed for <{hist_name.upper()}> '
-					f'(tried: {tried_attrs}, looking for: {[hist_attrs.get(k) for k in tried_attrs]}). '
-					f'Page has {len(same_node_elements)} <{hist_name.upper()}> elements with identifiers: '
-					f'{same_node_elements[:5]}{"..." if len(same_node_elements) > 5 else ""}'
-				)
-
-		if highlight_index is None:
+		match = find_matching_element(historical_element, browser_state_summary.dom_state.selector_map, self.logger)
+		if match is None:
 			return None
+		highlight_index, match_level = match
 
 		old_index = action.get_index()
 		if old_index != highlight_index:
@@ -3917,6 +4803,40 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			file_path = 'AgentHistory.json'
 		self.history.save_to_file(file_path, sensitive_data=self.sensitive_data)
 
+	def export_artifacts(self, output_dir: str | Path, as_zip: bool = False) -> Path:
+		"""Export the run as a self-contained bundle (steps.json, screenshots, files, result, conversation) for auditing.
+
+		The LLM conversation is only included when the agent was created with artifacts_dir, the network activity
+		(network.har) when the browser records HAR (record_har=True).
+		Returns the path of the bundle directory, or of the zip file with as_zip=True.
+		"""
+		from browser_use.agent.artifacts import export_run_artifacts
+
+		write_har: Callable[[Path], Any] | None = None
+		if self.browser_session is not None and self.browser_session.is_recording_har:
+			# Only the requests of this run, the browser may have been used before
+			first_step = self.history.history[0].metadata if self.history.history else None
+			run_start = first_step.step_start_time if first_step else None
+			write_har = partial(
+				self.browser_session.export_har,
+				since=run_start,
+				sensitive_data=self.sensitive_data,
+				redact=self.redactor.redact_text if self.redactor else None,
+			)
+
+		return export_run_artifacts(
+			output_dir,
+			task=self.task,
+			history=self.history,
+			file_system=self.file_system,
+			conversation_dir=self.agent_directory / 'conversation',
+			sensitive_data=self.sensitive_data,
+			as_zip=as_zip,
+			write_har=write_har,
+			redact=self.redactor.redact_text if self.redactor else None,
+			screenshot_store=self.screenshot_service,
+		)
+
 	def pause(self) -> None:
 		"""Pause the agent before the next step"""
 		print('\n\n⏸️ Paused the agent and left the browser open.\n\tPress [Enter] to resume or [Ctrl+C] again to quit.')
@@ -4003,6 +4923,9 @@ class Agent(Generic[Context, AgentStructuredOutput]):
 			if self.skill_service is not None:
 				await self.skill_service.close()
 
+			if self.debug_server is not None and self._owns_debug_server:
+				await self.debug_server.stop()
+
 			# Force garbage collection
 			gc.collect()
 
@@ -5,7 +5,7 @@ import json
 import logging
 import re
 import traceback
-from dataclasses import dataclass
+from dataclasses import dataclass, field
 from pathlib import Path
 from typing import Any, Generic, Literal
 
@@ -14,7 +14,13 @@ from typing_extensions import TypeVar
 from uuid_extensions import uuid7str
 
 from browser_use.agent.message_manager.views import MessageManagerState
-from browser_use.browser.views import BrowserStateHistory
+from browser_use.browser.views import (
+	BrowserDisconnectedError,
+	BrowserError,
+	BrowserStateHistory,
+	BrowserStateSummary,
+	is_connection_error,
+)
 from browser_use.dom.views import DEFAULT_INCLUDE_ATTRIBUTES, DOMInteractedElement, DOMSelectorMap
 
 # from browser_use.dom.history_tree_processor.service import (
@@ -25,6 +31,9 @@ from browser_use.dom.views import DEFAULT_INCLUDE_ATTRIBUTES, DOMInteractedEleme
 # from browser_use.dom.views import SelectorMap
 from browser_use.filesystem.file_system import FileSystemState
 from browser_use.llm.base import BaseChatModel
+from browser_use.llm.exceptions import ModelError
+from browser_use.llm.messages import ContentPartTextParam, UserMessage
+from browser_use.screenshots.service import ScreenshotStore
 from browser_use.tokens.views import UsageSummary
 from browser_use.tools.registry.views import ActionModel
 from browser_use.utils import collect_sensitive_data_values, redact_sensitive_string
@@ -56,21 +65,60 @@ class MessageCompactionSettings(BaseModel):
 		return self
 
 
+class VisionBudget(BaseModel):
+	"""Limits how many screenshots are sent to the LLM when vision is enabled.
+
+	Screenshots explicitly requested by an action (e.g. the screenshot action) skip the every_n_steps and
+	after_failures_only rules, but still count against max_screenshots.
+	"""
+
+	every_n_steps: int = Field(default=1, ge=1)  # Send a screenshot on every Nth step, starting with the first
+	after_failures_only: bool = False  # Only send a screenshot when the previous step had a failed action
+	max_screenshots: int | None = Field(default=None, ge=0)  # Max new screenshots sent per run, None for no limit
+	include_last: int = Field(default=1, ge=1, le=5)  # Screenshots of the K most recent vision steps kept in context
+	size: tuple[int, int] | None = None  # Downscale to (width, height), used as llm_screenshot_size when that is unset
+	jpeg_quality: int | None = Field(default=None, ge=1, le=100)  # Re-encode screenshots as JPEG at this quality
+
+
+class ModelRouting(BaseModel):
+	"""Runs simple steps on the agent's llm and escalates to strong_llm when the agent struggles.
+
+	A strong step after failures or unparseable output keeps strong_llm until deescalate_after_steps steps in a row
+	succeed. A large page only escalates the steps taken on it.
+	"""
+
+	strong_llm: BaseChatModel
+	escalate_after_failures: int = Field(default=2, ge=1)  # Consecutive failed steps before escalating
+	escalate_on_parse_error: bool = True  # Escalate right after the model output could not be parsed
+	escalate_above_elements: int | None = Field(default=None, ge=1)  # Escalate while the page has more interactive elements
+	deescalate_after_steps: int = Field(default=2, ge=1)  # Successful strong steps before returning to the default llm
+
+
 class AgentSettings(BaseModel):
 	"""Configuration options for the Agent"""
 
 	use_vision: bool | Literal['auto'] = True
 	vision_detail_level: Literal['auto', 'low', 'high'] = 'auto'
+	vision_budget: VisionBudget | None = None
+	model_routing: ModelRouting | None = None
 	save_conversation_path: str | Path | None = None
 	save_conversation_path_encoding: str | None = 'utf-8'
+	artifacts_dir: str | Path | None = None
+	artifacts_zip: bool = False
+	max_screenshots: int | None = None  # Keep screenshots of the N most recent steps in the screenshot store, None keeps all
 	max_failures: int = 5
 	generate_gif: bool | str = False
 	override_system_message: str | None = None
 	extend_system_message: str | None = None
+	system_prompt_variables: dict[str, str] | None = None  # Extra {name} placeholders for override/extend_system_message
+	system_prompt_template: str | Path | None = None  # Template file replacing the built-in system prompt
+	language: str | None = None  # Language for reasoning and the final answer, e.g. 'German' or 'ja', None follows the task
 	include_attributes: list[str] | None = DEFAULT_INCLUDE_ATTRIBUTES
 	max_actions_per_step: int = 5
 	use_thinking: bool = True
 	flash_mode: bool = False  # If enabled, disables evaluation_previous_goal and next_goal, and sets use_thinking = False
+	computer_use_mode: bool = False  # If enabled, the agent acts on screenshot coordinates only and no DOM is indexed
+	dismiss_consent_banners: Literal['reject', 'accept'] | None = None  # Auto-click cookie banners before each step
 	use_judge: bool = True
 	ground_truth: str | None = None  # Ground truth answer or criteria for judge validation
 	max_history_items: int | None = None
@@ -84,12 +132,23 @@ class AgentSettings(BaseModel):
 	include_tool_call_examples: bool = False
 	llm_timeout: int = 60  # Timeout in seconds for LLM calls (auto-detected: 30s for gemini, 90s for o3, 60s default)
 	step_timeout: int = 180  # Timeout in seconds for each step
+	max_duration: float | None = None  # Wall-clock limit in seconds for run(), None for no limit
 	final_response_after_failure: bool = True  # If True, attempt one final recovery call after max_failures
+	interactive: bool = False  # If True, pause before executing each step's actions and wait for approval
 
 	# Loop detection settings
 	loop_detection_window: int = 20  # Rolling window size for action similarity tracking
 	loop_detection_enabled: bool = True  # Whether to enable loop detection nudges
+	# Same action with the same params failing again and again; each threshold 0 = disabled
+	repeated_failure_hint_after: int = 2  # failures before a hint to try something else
+	repeated_failure_replan_after: int = 3  # failures before the agent is told to change strategy
+	max_repeated_failures: int = 5  # failures before the run is aborted
 	max_clickable_elements_length: int = 40000  # Max characters for clickable elements in prompt
+	max_interactive_elements: int | None = None  # Max interactive elements listed in prompt, None for no limit
+	max_attribute_length: int = 100  # Max characters per attribute value in the elements list
+	dom_diff_mode: bool = False  # After the first step, send new/changed/removed elements and shorten unchanged ones
+	dom_diff_full_refresh_every: int = 5  # Steps between full element lists in dom_diff_mode, 0 = only after navigation
+	include_element_boxes: bool = False  # Add box=x,y,width,height (viewport CSS pixels) to interactive elements
 
 
 class PageFingerprint(BaseModel):
@@ -248,6 +307,37 @@ class ActionLoopDetector(BaseModel):
 		return None
 
 
+class RepeatedFailureTracker(BaseModel):
+	"""Counts how often the same action with the same params failed since it last succeeded.
+
+	Unlike max_failures, which counts failing steps in a row, this also catches a broken action retried at the end of
+	multi-action steps or alternated with other actions.
+	"""
+
+	failures: dict[str, int] = Field(default_factory=dict)  # action hash -> failures since its last success
+	descriptions: dict[str, str] = Field(default_factory=dict)  # action hash -> e.g. 'click {"index": 12}'
+	last_errors: dict[str, str] = Field(default_factory=dict)
+
+	def record(self, action_name: str, params: dict[str, Any], error: str | None) -> None:
+		"""Record the outcome of an executed action."""
+		h = compute_action_hash(action_name, params)
+		if error is None:
+			self.failures.pop(h, None)
+			self.descriptions.pop(h, None)
+			self.last_errors.pop(h, None)
+			return
+		self.failures[h] = self.failures.get(h, 0) + 1
+		self.descriptions[h] = f'{action_name} {json.dumps(params, sort_keys=True, default=str)[:200]}'
+		self.last_errors[h] = error[:300]
+
+	def most_repeated(self) -> tuple[str, int, str] | None:
+		"""(description, failures, last error) of the action that failed most often, None if nothing is failing."""
+		if not self.failures:
+			return None
+		h = max(self.failures, key=lambda k: self.failures[k])
+		return self.descriptions[h], self.failures[h], self.last_errors[h]
+
+
 class AgentState(BaseModel):
 	"""Holds all state information for an Agent"""
 
@@ -273,6 +363,7 @@ class AgentState(BaseModel):
 
 	# Loop detection state
 	loop_detector: ActionLoopDetector = Field(default_factory=ActionLoopDetector)
+	failure_tracker: RepeatedFailureTracker = Field(default_factory=RepeatedFailureTracker)
 
 
 @dataclass
@@ -285,6 +376,51 @@ class AgentStepInfo:
 		return self.step_number >= self.max_steps - 1
 
 
+@dataclass
+class StepContext:
+	"""Passed to the before/after step hooks, which may change the state message, cancel the step or add results.
+
+	Before the step, state_message is the browser state about to be sent to the LLM. After the step, model_output
+	and action_results hold what the LLM decided and what the actions returned.
+	"""
+
+	step_number: int
+	task: str
+	browser_state: BrowserStateSummary
+	state_message: UserMessage
+	model_output: AgentOutput | None = None
+	action_results: list[ActionResult] = field(default_factory=list)
+	injected_results: list[ActionResult] = field(default_factory=list)
+	cancel_reason: str | None = None
+	after_step: bool = False
+
+	def add_result(self, result: ActionResult) -> None:
+		"""Record an extra result for this step, the LLM sees it like an action result on the next step"""
+		self.injected_results.append(result)
+
+	def add_context(self, text: str) -> None:
+		"""Append text to the state message sent to the LLM, e.g. external data relevant to the current page"""
+		if isinstance(self.state_message.content, str):
+			self.state_message.content = f'{self.state_message.content}\n{text}'
+		else:
+			self.state_message.content = [*self.state_message.content, ContentPartTextParam(text=text)]
+
+	def cancel(self, reason: str) -> None:
+		"""Skip the LLM call and actions of this step, only possible before the step"""
+		if self.after_step:
+			raise RuntimeError('A step can only be cancelled by a before step hook')
+		self.cancel_reason = reason
+
+
+class BudgetExceeded(BaseModel):
+	"""Passed to the budget exceeded callback when a run reaches max_steps or max_duration"""
+
+	reason: Literal['max_steps', 'max_duration']
+	n_steps: int  # Steps taken so far in this run
+	elapsed_seconds: float  # Wall-clock time since run() started
+	limit: float  # The max_steps or max_duration (seconds) that was reached
+
+
 class JudgementResult(BaseModel):
 	"""LLM judgement of agent trace"""
 
@@ -294,6 +430,10 @@ class JudgementResult(BaseModel):
 		default=None,
 		description='Max 5 sentences explanation of why the task was not completed successfully in case of failure. If verdict is true, use an empty string.',
 	)
+	missing_steps: list[str] = Field(
+		default_factory=list,
+		description='Parts of the task the agent did not do or did not verify, one short item each. Empty if verdict is true.',
+	)
 	impossible_task: bool = Field(
 		default=False,
 		description='True if the task was impossible to complete due to vague instructions, broken website, inaccessible links, missing login credentials, or other insurmountable obstacles',
@@ -316,6 +456,9 @@ class ActionResult(BaseModel):
 
 	# Error handling - always include in long term memory
 	error: str | None = None
+	# Machine-readable kind of the error, e.g. 'element_not_found', 'navigation_timeout', 'browser_disconnected',
+	# 'llm_rate_limited' (see AgentError.get_error_type), None when unknown
+	error_type: str | None = None
 
 	# Files
 	attachments: list[str] | None = None  # Files to display in the done message
@@ -366,6 +509,8 @@ class StepMetadata(BaseModel):
 	step_end_time: float
 	step_number: int
 	step_interval: float | None = None
+	llm_provider: str | None = None  # Provider of the LLM that produced this step's output, e.g. after a fallback switch
+	llm_model: str | None = None
 
 	@property
 	def duration_seconds(self) -> float:
@@ -385,6 +530,31 @@ class AgentBrain(BaseModel):
 	next_goal: str
 
 
+# Keys small models use for the action list in flash mode instead of "action"
+_FLASH_ACTION_ALIASES = ('actions', 'a')
+
+
+def normalize_flash_output(data: Any, fields: set[str]) -> Any:
+	"""Accept the shortcuts fast models take with the flash mode format before validating it.
+
+	A bare list of actions, "actions" instead of "action", a single action object instead of a list, and leftover keys
+	like "thinking" or "reasoning" that the flash format omits are all turned into {"memory": ..., "action": [...]}.
+	"""
+	if isinstance(data, list):
+		return {'action': data}
+	if not isinstance(data, dict):
+		return data
+	data = dict(data)
+	for alias in _FLASH_ACTION_ALIASES:
+		if 'action' not in data and alias in data:
+			data['action'] = data.pop(alias)
+	if isinstance(data.get('action'), dict):
+		data['action'] = [data['action']]
+	if isinstance(data.get('memory'), (list, dict)):
+		data['memory'] = json.dumps(data['memory'], ensure_ascii=False)
+	return {key: value for key, value in data.items() if key in fields}
+
+
 class AgentOutput(BaseModel):
 	model_config = ConfigDict(arbitrary_types_allowed=True, extra='forbid')
 
@@ -459,6 +629,11 @@ class AgentOutput(BaseModel):
 		"""Extend actions with custom actions for flash mode - memory and action fields only"""
 
 		class AgentOutputFlashMode(AgentOutput):
+			@model_validator(mode='before')
+			@classmethod
+			def _accept_abbreviated_output(cls, data: Any) -> Any:
+				return normalize_flash_output(data, set(cls.model_fields))
+
 			@classmethod
 			def model_json_schema(cls, **kwargs):
 				schema = super().model_json_schema(**kwargs)
@@ -714,6 +889,14 @@ class AgentHistoryList(BaseModel, Generic[AgentStructuredOutput]):
 			errors.append(step_errors[0] if step_errors else None)
 		return errors
 
+	def error_types(self) -> list[str | None]:
+		"""Get the error type of each step (see ActionResult.error_type), with None for steps without typed errors"""
+		error_types = []
+		for h in self.history:
+			step_error_types = [r.error_type for r in h.result if r.error and r.error_type]
+			error_types.append(step_error_types[0] if step_error_types else None)
+		return error_types
+
 	def final_result(self) -> None | str:
 		"""Final result from history"""
 		if self.history and len(self.history[-1].result) > 0:
@@ -783,8 +966,13 @@ class AgentHistoryList(BaseModel, Generic[AgentStructuredOutput]):
 			else:
 				return [h.state.screenshot_path for h in self.history[-n_last:] if h.state.screenshot_path is not None]
 
-	def screenshots(self, n_last: int | None = None, return_none_if_not_screenshot: bool = True) -> list[str | None]:
-		"""Get all screenshots from history as base64 strings"""
+	def screenshots(
+		self,
+		n_last: int | None = None,
+		return_none_if_not_screenshot: bool = True,
+		screenshot_store: ScreenshotStore | None = None,
+	) -> list[str | None]:
+		"""Get all screenshots from history as base64 strings, pass the agent's screenshot_store for S3 or GCS screenshots"""
 		if n_last == 0:
 			return []
 
@@ -792,7 +980,7 @@ class AgentHistoryList(BaseModel, Generic[AgentStructuredOutput]):
 		screenshots = []
 
 		for item in history_items:
-			screenshot_b64 = item.state.get_screenshot()
+			screenshot_b64 = item.state.get_screenshot(screenshot_store)
 			if screenshot_b64:
 				screenshots.append(screenshot_b64)
 			else:
@@ -861,6 +1049,10 @@ class AgentHistoryList(BaseModel, Generic[AgentStructuredOutput]):
 			results.extend([r for r in h.result if r])
 		return results
 
+	def assertion_results(self) -> list[dict[str, Any]]:
+		"""Get the pass/fail results of all assert actions from history"""
+		return [r.metadata['assertion'] for r in self.action_results() if r.metadata and 'assertion' in r.metadata]
+
 	def extracted_content(self) -> list[str]:
 		"""Get all extracted content from history"""
 		content = []
@@ -984,6 +1176,24 @@ class AgentError:
 			return f'{str(error)}\nStacktrace:\n{traceback.format_exc()}'
 		return f'{str(error)}'
 
+	@staticmethod
+	def get_error_type(error: BaseException) -> str | None:
+		"""Machine-readable kind of an error for ActionResult.error_type, None if it is not one we know"""
+		if isinstance(error, (BrowserError, ModelError)):
+			return error.error_type
+		if isinstance(error, ValidationError):
+			return 'invalid_model_output'
+		if is_connection_error(error):
+			return BrowserDisconnectedError.error_type
+		if isinstance(error, TimeoutError):
+			return 'timeout'
+		# Lazy import to avoid loading openai SDK (~800ms) at module level
+		from openai import RateLimitError
+
+		if isinstance(error, RateLimitError):
+			return 'llm_rate_limited'
+		return None
+
 
 class DetectedVariable(BaseModel):
 	"""A detected variable in agent history"""
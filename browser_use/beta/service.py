@@ -4098,10 +4098,10 @@ def _init_file_system(
 		file_system = FileSystem.from_state(state.file_system_state)
 		return file_system, str(file_system.base_dir)
 	if file_system_path:
-		file_system = FileSystem(file_system_path)
+		file_system = FileSystem.with_in_memory_fallback(file_system_path)
 		state.file_system_state = file_system.get_state()
 		return file_system, file_system_path
-	file_system = FileSystem(agent_directory)
+	file_system = FileSystem.with_in_memory_fallback(agent_directory)
 	state.file_system_state = file_system.get_state()
 	return file_system, str(agent_directory)
 
@@ -2,15 +2,19 @@ from typing import TYPE_CHECKING
 
 # Type stubs for lazy imports
 if TYPE_CHECKING:
+	from .backend import BrowserBackend
 	from .profile import BrowserProfile, ProxySettings
 	from .session import BrowserSession
+	from .session_handle import SessionHandle
 
 
 # Lazy imports mapping for heavy browser components
 _LAZY_IMPORTS = {
+	'BrowserBackend': ('.backend', 'BrowserBackend'),
 	'ProxySettings': ('.profile', 'ProxySettings'),
 	'BrowserProfile': ('.profile', 'BrowserProfile'),
 	'BrowserSession': ('.session', 'BrowserSession'),
+	'SessionHandle': ('.session_handle', 'SessionHandle'),
 }
 
 
@@ -35,7 +39,9 @@ def __getattr__(name: str):
 
 
 __all__ = [
+	'BrowserBackend',
 	'BrowserSession',
+	'SessionHandle',
 	'BrowserProfile',
 	'ProxySettings',
 ]
@@ -18,6 +18,11 @@ Configure the cap via:
 Default (60s) is generous for slow operations like `Page.captureScreenshot`
 or `Page.printToPDF` on heavy pages, but well below the 180s agent step
 timeout and the typical outer agent watchdog.
+
+Remote browsers send multi-megabyte screenshots and DOM snapshots: the
+WebSocket negotiates permessage-deflate (the websockets default) and the
+largest accepted message (`max_ws_frame_size`, None for no limit) comes from
+`BrowserProfile.cdp_max_message_size`.
 """
 
 from __future__ import annotations
@@ -26,10 +31,15 @@ import asyncio
 import logging
 import math
 import os
-from typing import Any
+import time
+from collections.abc import Callable
+from typing import TYPE_CHECKING, Any
 
 from cdp_use import CDPClient
 
+if TYPE_CHECKING:
+	from browser_use.browser.cdp_log import CDPTrafficLogger
+
 logger = logging.getLogger(__name__)
 
 _CDP_TIMEOUT_FALLBACK_S = 60.0
@@ -94,8 +104,17 @@ class TimeoutWrappedCDPClient(CDPClient):
 	Any CDP method that doesn't receive a response within `cdp_request_timeout_s`
 	raises `TimeoutError` instead of hanging forever. This turns silent-hang
 	failure modes (cloud proxy alive, browser dead) into fast observable errors.
+
+	`command_observer`, when set, is called with the method name and duration of every request.
+	`event_observer`, when set, is called with the method, params and session id of every event received, after
+	the handler registered for it with `register` (which holds one handler per method) has run.
+	`traffic_logger`, when set, writes every command, response and event to the session's CDP wire log.
 	"""
 
+	command_observer: Callable[[str, float], None] | None = None
+	event_observer: Callable[[str, Any, str | None], None] | None = None
+	traffic_logger: CDPTrafficLogger | None = None
+
 	def __init__(
 		self,
 		*args: Any,
@@ -105,21 +124,51 @@ class TimeoutWrappedCDPClient(CDPClient):
 		super().__init__(*args, **kwargs)
 		self._cdp_request_timeout_s: float = _coerce_valid_timeout(cdp_request_timeout_s)
 
+		# Events go through a single-slot registry, observe them after dispatch instead of taking a slot
+		registry = self._event_registry
+		handle_event = registry.handle_event
+
+		async def handle_and_observe_event(method: str, params: Any, session_id: str | None = None) -> bool:
+			if self.traffic_logger is not None:
+				self.traffic_logger.log_event(method, params, session_id)
+			handled = await handle_event(method, params, session_id)
+			if self.event_observer is not None:
+				self.event_observer(method, params, session_id)
+			return handled
+
+		registry.handle_event = handle_and_observe_event
+
 	async def send_raw(
 		self,
 		method: str,
 		params: Any | None = None,
 		session_id: str | None = None,
 	) -> dict[str, Any]:
+		start = time.perf_counter()
+		traffic_logger = self.traffic_logger
+		if traffic_logger is not None:
+			traffic_logger.log_command(method, params, session_id)
 		try:
-			return await asyncio.wait_for(
+			result = await asyncio.wait_for(
 				super().send_raw(method=method, params=params, session_id=session_id),
 				timeout=self._cdp_request_timeout_s,
 			)
+			if traffic_logger is not None:
+				traffic_logger.log_response(method, result, session_id, time.perf_counter() - start)
+			return result
 		except TimeoutError as e:
+			if traffic_logger is not None:
+				traffic_logger.log_error(method, e, session_id, time.perf_counter() - start)
 			# Raise a plain TimeoutError so existing `except TimeoutError`
 			# handlers in browser-use / tools treat this uniformly.
 			raise TimeoutError(
 				f'CDP method {method!r} did not respond within {self._cdp_request_timeout_s:.0f}s. '
 				f'The browser may be unresponsive (silent WebSocket — container crashed or proxy lost upstream).'
 			) from e
+		except Exception as e:
+			if traffic_logger is not None:
+				traffic_logger.log_error(method, e, session_id, time.perf_counter() - start)
+			raise
+		finally:
+			if self.command_observer is not None:
+				self.command_observer(method, time.perf_counter() - start)
@@ -0,0 +1,219 @@
+"""
+Named, encrypted login sessions ("login vault") for browser sessions.
+
+Requires the optional cryptography dependency: pip install "browser-use[vault]"
+
+Each saved session holds the cookies and localStorage/sessionStorage of the sites it was saved for, encrypted with a key
+taken from the BROWSER_USE_AUTH_VAULT_KEY environment variable (any passphrase):
+
+	await browser_session.save_auth('github-work', sites=['github.com'])
+	...
+	await browser_session.restore_auth('github-work')  # in a later run, skips the login
+"""
+
+import base64
+import json
+import logging
+import os
+import re
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Any
+from urllib.parse import urlparse
+
+from pydantic import BaseModel, Field
+
+logger = logging.getLogger(__name__)
+
+AUTH_VAULT_KEY_ENV = 'BROWSER_USE_AUTH_VAULT_KEY'
+AUTH_VAULT_DIR_ENV = 'BROWSER_USE_AUTH_VAULT_DIR'
+
+_VAULT_FORMAT_VERSION = 1
+_KDF_ITERATIONS = 390_000
+_NAME_RE = re.compile(r'^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$')
+
+
+class AuthVaultError(Exception):
+	"""Raised when a vault entry is missing, cannot be decrypted or the vault is not configured."""
+
+
+class SavedAuth(BaseModel):
+	"""A decrypted vault entry."""
+
+	name: str
+	sites: list[str] = Field(default_factory=list)
+	saved_at: datetime
+	storage_state: dict[str, Any]
+
+	@property
+	def cookies_count(self) -> int:
+		return len(self.storage_state.get('cookies', []))
+
+	@property
+	def origins_count(self) -> int:
+		return len(self.storage_state.get('origins', []))
+
+	def find_site(self, url: str) -> str | None:
+		"""The saved site url belongs to, if any."""
+		host = site_host(url)
+		return next((site for site in self.sites if host and _host_matches_site(host, site)), None)
+
+
+def site_host(site: str) -> str:
+	"""Normalize a site given as a hostname or URL to a bare lowercase hostname."""
+	site = site.strip().lower()
+	host = urlparse(site).hostname if '://' in site else site.split('/')[0].split(':')[0]
+	return (host or '').lstrip('.').removeprefix('www.')
+
+
+def _host_matches_site(host: str, site: str) -> bool:
+	"""True if host is the site itself or one of its subdomains."""
+	host = host.lower().lstrip('.').removeprefix('www.')
+	return host == site or host.endswith('.' + site)
+
+
+def _cookie_domain_matches_site(domain: str, site: str) -> bool:
+	"""True if a cookie of domain is sent to the site, cookie domains may also be a parent of the site."""
+	domain = domain.lower().lstrip('.').removeprefix('www.')
+	return _host_matches_site(domain, site) or site.endswith('.' + domain)
+
+
+def filter_storage_state(storage_state: dict[str, Any], sites: list[str]) -> dict[str, Any]:
+	"""Keep only the cookies and origins belonging to the given sites."""
+	hosts = [site_host(site) for site in sites]
+	return {
+		'cookies': [
+			cookie
+			for cookie in storage_state.get('cookies', [])
+			if any(_cookie_domain_matches_site(cookie.get('domain', ''), host) for host in hosts)
+		],
+		'origins': [
+			origin
+			for origin in storage_state.get('origins', [])
+			if any(_host_matches_site(urlparse(origin.get('origin', '')).hostname or '', host) for host in hosts)
+		],
+	}
+
+
+def sites_in_storage_state(storage_state: dict[str, Any]) -> list[str]:
+	"""The distinct sites a storage state has cookies or storage for."""
+	hosts = {site_host(cookie.get('domain', '')) for cookie in storage_state.get('cookies', [])}
+	hosts |= {site_host(origin.get('origin', '')) for origin in storage_state.get('origins', [])}
+	return sorted(host for host in hosts if host)
+
+
+class AuthVault:
+	"""Directory of named login sessions, one encrypted file per name.
+
+	The key is read from BROWSER_USE_AUTH_VAULT_KEY unless passed explicitly, the directory defaults to
+	BROWSER_USE_AUTH_VAULT_DIR or <config dir>/auth_vault. Site names are encrypted together with the session,
+	so entries() and find() need the key too.
+	"""
+
+	def __init__(self, directory: str | Path | None = None, key: str | None = None):
+		try:
+			from cryptography.fernet import Fernet  # noqa: F401
+		except ImportError as e:
+			raise ImportError('AuthVault requires cryptography: pip install "browser-use[vault]"') from e
+
+		key = key if key is not None else os.getenv(AUTH_VAULT_KEY_ENV)
+		if not key:
+			raise AuthVaultError(f'No auth vault key configured, set {AUTH_VAULT_KEY_ENV}')
+		self._key = key
+
+		if directory is None:
+			from browser_use.config import CONFIG
+
+			directory = os.getenv(AUTH_VAULT_DIR_ENV) or CONFIG.BROWSER_USE_CONFIG_DIR / 'auth_vault'
+		self.directory = Path(directory).expanduser().resolve()
+
+	@classmethod
+	def from_env(cls) -> 'AuthVault | None':
+		"""The vault configured through environment variables, None if no key is set."""
+		if not os.getenv(AUTH_VAULT_KEY_ENV):
+			return None
+		return cls()
+
+	def save(self, name: str, storage_state: dict[str, Any], sites: list[str] | None = None) -> SavedAuth:
+		"""Encrypt and store a storage state under name, keeping only the given sites if any. Overwrites an existing entry."""
+		if sites:
+			storage_state = filter_storage_state(storage_state, sites)
+			sites = [site_host(site) for site in sites]
+		else:
+			sites = sites_in_storage_state(storage_state)
+
+		entry = SavedAuth(name=name, sites=sites, saved_at=datetime.now(timezone.utc), storage_state=storage_state)
+		salt = os.urandom(16)
+		token = self._fernet(salt).encrypt(entry.model_dump_json().encode())
+		payload = {
+			'version': _VAULT_FORMAT_VERSION,
+			'salt': base64.urlsafe_b64encode(salt).decode(),
+			'token': token.decode(),
+		}
+
+		path = self._path(name)
+		path.parent.mkdir(parents=True, exist_ok=True)
+		temp_path = path.with_suffix('.tmp')
+		temp_path.write_text(json.dumps(payload), encoding='utf-8')
+		os.chmod(temp_path, 0o600)
+		temp_path.replace(path)
+		logger.debug(f'🔐 Saved auth session {name!r} ({entry.cookies_count} cookies, {entry.origins_count} origins)')
+		return entry
+
+	def load(self, name: str) -> SavedAuth:
+		"""Decrypt the entry stored under name."""
+		from cryptography.fernet import InvalidToken
+
+		path = self._path(name)
+		if not path.exists():
+			raise AuthVaultError(f'No saved auth session named {name!r}')
+		try:
+			payload = json.loads(path.read_text(encoding='utf-8'))
+			salt = base64.urlsafe_b64decode(payload['salt'])
+			data = self._fernet(salt).decrypt(payload['token'].encode())
+		except InvalidToken as e:
+			raise AuthVaultError(f'Could not decrypt auth session {name!r}, wrong {AUTH_VAULT_KEY_ENV}?') from e
+		except (ValueError, KeyError) as e:
+			raise AuthVaultError(f'Auth session file {path} is corrupted: {e}') from e
+		return SavedAuth.model_validate_json(data)
+
+	def delete(self, name: str) -> bool:
+		"""Remove the entry stored under name, returns False if there was none."""
+		path = self._path(name)
+		if not path.exists():
+			return False
+		path.unlink()
+		return True
+
+	def names(self) -> list[str]:
+		if not self.directory.exists():
+			return []
+		return sorted(path.stem for path in self.directory.glob('*.vault'))
+
+	def entries(self) -> list[SavedAuth]:
+		"""All entries that can be decrypted with the current key, unreadable ones are skipped with a warning."""
+		entries = []
+		for name in self.names():
+			try:
+				entries.append(self.load(name))
+			except AuthVaultError as e:
+				logger.warning(f'⚠️ Skipping auth session {name!r}: {e}')
+		return entries
+
+	def find(self, url: str) -> list[SavedAuth]:
+		"""Entries saved for the site of url, most recently saved first."""
+		matches = [entry for entry in self.entries() if entry.find_site(url)]
+		return sorted(matches, key=lambda entry: entry.saved_at, reverse=True)
+
+	def _path(self, name: str) -> Path:
+		if not _NAME_RE.match(name):
+			raise AuthVaultError(f'Invalid auth session name {name!r}, use letters, digits, ".", "_" and "-"')
+		return self.directory / f'{name}.vault'
+
+	def _fernet(self, salt: bytes):
+		from cryptography.fernet import Fernet
+		from cryptography.hazmat.primitives import hashes
+		from cryptography.hazmat.primitives.kdf.pbkdf2 import PBKDF2HMAC
+
+		kdf = PBKDF2HMAC(algorithm=hashes.SHA256(), length=32, salt=salt, iterations=_KDF_ITERATIONS)
+		return Fernet(base64.urlsafe_b64encode(kdf.derive(self._key.encode())))
@@ -0,0 +1,123 @@
+"""
+The interface between the agent / action layer and the browser it drives.
+
+Actions don't talk to the browser directly, they dispatch events (NavigateToUrlEvent, ClickElementEvent, ...) on the
+backend's event bus and read the page through a few query methods. BrowserSession implements this over Chrome's CDP,
+another backend (e.g. Firefox over WebDriver BiDi or WebKit over the Playwright server protocol) implements the same
+methods and handles the same events:
+
+	class BiDiBackend:
+		def __init__(self):
+			self.event_bus = EventBus()
+			self.event_bus.on(NavigateToUrlEvent, self.on_NavigateToUrlEvent)
+			...
+
+	assert isinstance(BiDiBackend(), BrowserBackend)
+	assert missing_action_handlers(backend) == []
+
+Actions that are not covered by an event (e.g. evaluate, extract with JS, PDF export) still use CDP through
+BrowserSession.get_or_create_cdp_session() and are only available on the CDP backend.
+"""
+
+from typing import TYPE_CHECKING, Any, Protocol, runtime_checkable
+
+from bubus import BaseEvent, EventBus
+
+from browser_use.browser.events import (
+	BrowserStateRequestEvent,
+	ClickCoordinateEvent,
+	ClickElementEvent,
+	CloseTabEvent,
+	GetDropdownOptionsEvent,
+	GoBackEvent,
+	GoForwardEvent,
+	NavigateToUrlEvent,
+	RefreshEvent,
+	ScreenshotEvent,
+	ScrollEvent,
+	ScrollToTextEvent,
+	SelectDropdownOptionEvent,
+	SendKeysEvent,
+	SwitchTabEvent,
+	TypeTextEvent,
+	UploadFileEvent,
+)
+
+if TYPE_CHECKING:
+	from browser_use.browser.views import BrowserStateSummary, TabInfo
+	from browser_use.dom.views import EnhancedDOMTreeNode
+
+# Events dispatched by the action layer and the agent, a backend registers a handler for each of them on its event bus
+ACTION_EVENTS: tuple[type[BaseEvent[Any]], ...] = (
+	NavigateToUrlEvent,
+	GoBackEvent,
+	GoForwardEvent,
+	RefreshEvent,
+	ClickElementEvent,
+	ClickCoordinateEvent,
+	TypeTextEvent,
+	ScrollEvent,
+	ScrollToTextEvent,
+	SendKeysEvent,
+	UploadFileEvent,
+	GetDropdownOptionsEvent,
+	SelectDropdownOptionEvent,
+	SwitchTabEvent,
+	CloseTabEvent,
+	ScreenshotEvent,
+	BrowserStateRequestEvent,
+)
+
+
+@runtime_checkable
+class BrowserBackend(Protocol):
+	"""What the agent and the action layer need from a browser, implemented by BrowserSession for Chrome over CDP."""
+
+	event_bus: EventBus
+	agent_focus_target_id: str | None  # the tab actions act on
+
+	@property
+	def pinned_target_id(self) -> str | None: ...
+
+	async def start(self) -> None: ...
+
+	async def stop(self, close_tabs: bool | None = None, close_browser: bool = False) -> None: ...
+
+	async def kill(self) -> None: ...
+
+	async def navigate_to(self, url: str, new_tab: bool = False) -> None: ...
+
+	async def get_browser_state_summary(
+		self,
+		include_screenshot: bool = True,
+		cached: bool = False,
+		include_recent_events: bool = False,
+	) -> 'BrowserStateSummary': ...
+
+	async def get_selector_map(self) -> dict[int, 'EnhancedDOMTreeNode']: ...
+
+	async def get_element_by_index(self, index: int) -> 'EnhancedDOMTreeNode | None': ...
+
+	async def get_tabs(self, include_thumbnails: bool = False) -> list['TabInfo']: ...
+
+	async def get_target_id_from_tab_id(self, tab_id: str) -> str: ...
+
+	async def get_current_page_url(self) -> str: ...
+
+	async def get_current_page_title(self) -> str: ...
+
+	async def get_page_text(self, max_chars: int | None = 4000, main_content_only: bool = True) -> str: ...
+
+	async def take_screenshot(
+		self,
+		path: str | None = None,
+		full_page: bool = False,
+		format: str = 'png',
+		quality: int | None = None,
+		clip: dict | None = None,
+	) -> bytes: ...
+
+
+def missing_action_handlers(backend: BrowserBackend) -> list[str]:
+	"""Names of the ACTION_EVENTS nothing handles on the backend's event bus, actions dispatching them would hang."""
+	return [event.__name__ for event in ACTION_EVENTS if not backend.event_bus.handlers.get(event.__name__)]
@@ -0,0 +1,208 @@
+"""
+WebDriver BiDi transport, an alternative to CDP for browsers that speak the W3C protocol (Firefox natively, Chrome
+through chromedriver or chromium-bidi).
+
+	session = BrowserSession(protocol='bidi', bidi_url='ws://127.0.0.1:9222/session')
+	await session.start()
+	page = await session.bidi.get_current_page()
+	await page.goto('https://example.com')
+	[link] = await page.get_elements_by_css_selector('a')
+	await link.click()
+
+Pages and elements have the same methods as the CDP actors in browser_use.actor. The agent's DOM snapshots, watchdogs
+and most actions are built on CDP and need protocol='cdp'.
+"""
+
+import asyncio
+import itertools
+import json
+import logging
+from collections.abc import Awaitable, Callable
+from typing import TYPE_CHECKING, Any
+from urllib.parse import urlparse
+
+import websockets
+
+from browser_use.browser._cdp_timeout import DEFAULT_CDP_REQUEST_TIMEOUT_S
+
+if TYPE_CHECKING:
+	from browser_use.actor.bidi import BiDiPage
+
+logger = logging.getLogger(__name__)
+
+BiDiEventHandler = Callable[[dict[str, Any]], Awaitable[None] | None]
+
+
+class BiDiError(Exception):
+	"""Error response of a BiDi command, e.g. 'no such frame' or 'invalid argument'."""
+
+	def __init__(self, method: str, error: str, message: str):
+		super().__init__(f'{method} failed: {error}: {message}')
+		self.method = method
+		self.error = error
+		self.message = message
+
+
+class BiDiClient:
+	"""JSON command/response/event transport over the BiDi WebSocket."""
+
+	def __init__(
+		self,
+		url: str,
+		additional_headers: dict[str, str] | None = None,
+		max_message_size: int | None = 200 * 1024 * 1024,
+		request_timeout_s: float = DEFAULT_CDP_REQUEST_TIMEOUT_S,
+	):
+		self.url = url
+		self.additional_headers = additional_headers
+		self.max_message_size = max_message_size
+		self.request_timeout_s = request_timeout_s
+		self.ws: Any = None
+		self._ids = itertools.count(1)
+		self._pending: dict[int, tuple[str, asyncio.Future[dict[str, Any]]]] = {}
+		self._handlers: dict[str, list[BiDiEventHandler]] = {}
+		self._reader_task: asyncio.Task | None = None
+
+	async def start(self) -> None:
+		if self.ws is not None:
+			raise RuntimeError('Client is already started')
+		self.ws = await websockets.connect(
+			self.url, additional_headers=self.additional_headers, max_size=self.max_message_size, compression='deflate'
+		)
+		self._reader_task = asyncio.create_task(self._read_messages())
+
+	async def stop(self) -> None:
+		if self._reader_task:
+			self._reader_task.cancel()
+			await asyncio.gather(self._reader_task, return_exceptions=True)
+			self._reader_task = None
+		if self.ws is not None:
+			await self.ws.close()
+			self.ws = None
+		for method, future in self._pending.values():
+			if not future.done():
+				future.set_exception(ConnectionError(f'BiDi connection closed before {method} returned'))
+		self._pending.clear()
+
+	async def send(self, method: str, params: dict[str, Any] | None = None) -> dict[str, Any]:
+		"""Send a command and return its result, raises BiDiError for error responses."""
+		if self.ws is None:
+			raise RuntimeError('BiDi client is not started')
+		command_id = next(self._ids)
+		future: asyncio.Future[dict[str, Any]] = asyncio.get_running_loop().create_future()
+		self._pending[command_id] = (method, future)
+		try:
+			await self.ws.send(json.dumps({'id': command_id, 'method': method, 'params': params or {}}))
+			return await asyncio.wait_for(future, timeout=self.request_timeout_s)
+		except TimeoutError as e:
+			raise TimeoutError(f'BiDi method {method!r} did not respond within {self.request_timeout_s:.0f}s') from e
+		finally:
+			self._pending.pop(command_id, None)
+
+	def on(self, event: str, handler: BiDiEventHandler) -> None:
+		"""Call handler with the params of every event of this name, after subscribing to it with session.subscribe."""
+		self._handlers.setdefault(event, []).append(handler)
+
+	def off(self, event: str, handler: BiDiEventHandler) -> None:
+		handlers = self._handlers.get(event, [])
+		if handler in handlers:
+			handlers.remove(handler)
+
+	async def _read_messages(self) -> None:
+		assert self.ws is not None
+		async for raw in self.ws:
+			message = json.loads(raw)
+			if message.get('type') == 'event':
+				await self._dispatch_event(message['method'], message.get('params') or {})
+				continue
+			pending = self._pending.get(message.get('id', -1))
+			if pending is None:
+				continue
+			method, future = pending
+			if future.done():
+				continue
+			if message.get('type') == 'error':
+				future.set_exception(BiDiError(method, message.get('error', 'unknown error'), message.get('message', '')))
+			else:
+				future.set_result(message.get('result') or {})
+
+	async def _dispatch_event(self, method: str, params: dict[str, Any]) -> None:
+		for handler in list(self._handlers.get(method, [])):
+			try:
+				result = handler(params)
+				if asyncio.iscoroutine(result):
+					await result
+			except Exception as e:
+				logger.debug(f'BiDi handler for {method} failed: {type(e).__name__}: {e}')
+
+
+class BiDiSession:
+	"""A WebDriver BiDi session: its client and the top-level browsing contexts (tabs) of the browser."""
+
+	def __init__(self, client: BiDiClient, capabilities: dict[str, Any] | None = None):
+		self.client = client
+		self.capabilities = capabilities or {}
+		self.session_id: str | None = None
+		self.current_context: str | None = None
+		self._owns_session = False
+
+	async def start(self) -> None:
+		"""Connect and create the session, unless the URL belongs to one made over WebDriver classic (/session/<id>)."""
+		await self.client.start()
+		if urlparse(self.client.url).path.rstrip('/').endswith('/session'):
+			result = await self.client.send('session.new', {'capabilities': {'alwaysMatch': self.capabilities}})
+			self.session_id = result.get('sessionId')
+			self._owns_session = True
+		contexts = await self.get_contexts()
+		self.current_context = contexts[0]['context'] if contexts else await self._create_context()
+
+	async def stop(self) -> None:
+		try:
+			if self._owns_session and self.client.ws is not None:
+				await self.client.send('session.end')
+		except Exception as e:
+			logger.debug(f'Could not end the BiDi session: {type(e).__name__}: {e}')
+		finally:
+			await self.client.stop()
+
+	async def get_contexts(self) -> list[dict[str, Any]]:
+		"""The top-level browsing contexts, with their url and child frames."""
+		result = await self.client.send('browsingContext.getTree', {'maxDepth': 0})
+		return result.get('contexts', [])
+
+	async def _create_context(self) -> str:
+		result = await self.client.send('browsingContext.create', {'type': 'tab'})
+		return result['context']
+
+	async def new_page(self, url: str | None = None) -> 'BiDiPage':
+		"""Open a new tab and make it the current page."""
+		from browser_use.actor.bidi import BiDiPage
+
+		self.current_context = await self._create_context()
+		page = BiDiPage(self, self.current_context)
+		if url:
+			await page.goto(url)
+		return page
+
+	async def get_current_page(self) -> 'BiDiPage | None':
+		from browser_use.actor.bidi import BiDiPage
+
+		return BiDiPage(self, self.current_context) if self.current_context else None
+
+	async def must_get_current_page(self) -> 'BiDiPage':
+		page = await self.get_current_page()
+		if not page:
+			raise RuntimeError('No current browsing context found')
+		return page
+
+	async def get_pages(self) -> list['BiDiPage']:
+		from browser_use.actor.bidi import BiDiPage
+
+		return [BiDiPage(self, context['context']) for context in await self.get_contexts()]
+
+	async def close_page(self, page: 'BiDiPage | str') -> None:
+		context = page if isinstance(page, str) else page.context
+		await self.client.send('browsingContext.close', {'context': context})
+		if context == self.current_context:
+			contexts = await self.get_contexts()
+			self.current_context = contexts[0]['context'] if contexts else None
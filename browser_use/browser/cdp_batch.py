@@ -0,0 +1,48 @@
+"""
+Pipelined CDP commands: send several commands of a session without waiting for each response.
+
+Every awaited CDP command costs a round-trip to the browser, which adds up on remote browsers with high latency.
+Commands that don't depend on each other's results can go out together instead:
+
+	metrics, scrolled, quads = await send_pipelined(
+		cdp_client,
+		[
+			('Page.getLayoutMetrics', None),
+			('DOM.scrollIntoViewIfNeeded', {'backendNodeId': backend_node_id}),
+			('DOM.getContentQuads', {'backendNodeId': backend_node_id}),
+		],
+		session_id=session_id,
+	)
+
+The browser handles the commands of a session in the order they were sent, so each command sees the effects of the
+ones before it, like the quads above are measured after scrolling.
+"""
+
+import asyncio
+from collections.abc import Sequence
+from typing import Any
+
+from cdp_use import CDPClient
+
+CDPCommand = tuple[str, dict[str, Any] | None]  # method and params, e.g. ('DOM.resolveNode', {'backendNodeId': 12})
+
+
+async def send_pipelined(
+	client: CDPClient, commands: Sequence[CDPCommand], session_id: str | None = None
+) -> list[dict[str, Any] | Exception]:
+	"""Send the commands in order in one round-trip and return their results in the same order.
+
+	A failed command does not affect the others, its exception is returned in place of its result.
+	"""
+	# Tasks start in creation order, so the requests are written to the connection in order before any response arrives
+	tasks = [asyncio.ensure_future(client.send_raw(method, params, session_id)) for method, params in commands]
+	results = await asyncio.gather(*tasks, return_exceptions=True)
+	for result in results:
+		if isinstance(result, BaseException) and not isinstance(result, Exception):
+			raise result  # cancellation and the like are not command errors
+	return list(results)  # type: ignore[arg-type]
+
+
+def result_or_none(result: dict[str, Any] | Exception) -> dict[str, Any] | None:
+	"""The result of a pipelined command, None if it failed."""
+	return None if isinstance(result, Exception) else result
@@ -0,0 +1,231 @@
+"""
+Public CDP event subscriptions for building custom watchdogs on top of a BrowserSession.
+
+cdp-use keeps a single handler per CDP method, which browser-use's own watchdogs already use, so registering on
+cdp_client.register directly would silently replace them. BrowserSession.subscribe_cdp_event() adds handlers
+next to them instead:
+
+	def on_response(event: CDPEvent) -> None:
+		response = event.decode(NetworkResponseReceived)
+		print(response.status, response.url)
+
+	subscription_id = browser_session.subscribe_cdp_event('Network.responseReceived', on_response)
+	...
+	browser_session.unsubscribe_cdp_event(subscription_id)
+
+Events only arrive for domains enabled on the session they belong to, browser-use enables Page and Network on every
+tab. Others (e.g. Runtime, Log, Audits) need send.<Domain>.enable() on the tab's CDP session first.
+"""
+
+import fnmatch
+import json
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass
+from typing import Any, ClassVar, TypeVar
+
+from pydantic import BaseModel, ConfigDict, Field
+
+T = TypeVar('T', bound='CDPEventModel')
+
+
+@dataclass(frozen=True)
+class CDPEvent:
+	"""A CDP event as received from the browser."""
+
+	method: str  # e.g. 'Network.responseReceived'
+	params: dict[str, Any]
+	session_id: str | None = None  # CDP session the event was sent on, None for browser-level events
+	target_id: str | None = None  # Target (tab, iframe, worker) of that session, if known
+
+	def decode(self, model: type[T]) -> T:
+		"""Parse the params into one of the typed event models below, e.g. event.decode(NetworkResponseReceived)."""
+		if model.cdp_method != self.method:
+			raise ValueError(f'{model.__name__} decodes {model.cdp_method} events, not {self.method}')
+		return model.from_params(self.params)
+
+
+CDPEventHandler = Callable[[CDPEvent], Awaitable[None] | None]
+
+
+@dataclass(frozen=True)
+class BindingCall:
+	"""A call of a binding added with BrowserSession.add_binding(), from the JavaScript of a page."""
+
+	name: str
+	payload: str  # the string the page passed, window.<name>(payload)
+	target_id: str | None = None
+	execution_context_id: int | None = None
+
+	def json(self) -> Any:
+		"""The payload parsed as JSON, for pages calling window.<name>(JSON.stringify(data))."""
+		return json.loads(self.payload)
+
+
+BindingHandler = Callable[[BindingCall], Awaitable[None] | None]
+
+
+@dataclass
+class CDPEventSubscription:
+	"""A handler subscribed to the events matching a method (or glob like 'Network.*'), optionally for one target."""
+
+	id: str
+	method: str
+	handler: CDPEventHandler
+	target_id: str | None = None
+
+	def matches(self, method: str, target_id: str | None) -> bool:
+		if self.target_id is not None and target_id != self.target_id:
+			return False
+		if '*' in self.method:
+			return fnmatch.fnmatchcase(method, self.method)
+		return method == self.method
+
+
+class CDPEventModel(BaseModel):
+	"""Typed view of a CDP event's params, the raw params stay available in `raw`."""
+
+	model_config = ConfigDict(extra='ignore')
+
+	cdp_method: ClassVar[str] = ''
+	raw: dict[str, Any] = Field(default_factory=dict, repr=False)
+
+	@classmethod
+	def from_params(cls: type[T], params: dict[str, Any]) -> T:
+		raise NotImplementedError
+
+
+class NetworkRequestWillBeSent(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Network.requestWillBeSent'
+
+	request_id: str
+	url: str
+	http_method: str
+	headers: dict[str, Any] = Field(default_factory=dict)
+	resource_type: str | None = None
+	frame_id: str | None = None
+	timestamp: float | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'NetworkRequestWillBeSent':
+		request = params.get('request', {})
+		return cls(
+			request_id=params['requestId'],
+			url=request.get('url', ''),
+			http_method=request.get('method', ''),
+			headers=request.get('headers', {}),
+			resource_type=params.get('type'),
+			frame_id=params.get('frameId'),
+			timestamp=params.get('timestamp'),
+			raw=params,
+		)
+
+
+class NetworkResponseReceived(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Network.responseReceived'
+
+	request_id: str
+	url: str
+	status: int
+	status_text: str = ''
+	mime_type: str = ''
+	headers: dict[str, Any] = Field(default_factory=dict)
+	resource_type: str | None = None
+	frame_id: str | None = None
+	remote_ip_address: str | None = None
+	from_cache: bool = False
+	timestamp: float | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'NetworkResponseReceived':
+		response = params.get('response', {})
+		return cls(
+			request_id=params['requestId'],
+			url=response.get('url', ''),
+			status=response.get('status', 0),
+			status_text=response.get('statusText', ''),
+			mime_type=response.get('mimeType', ''),
+			headers=response.get('headers', {}),
+			resource_type=params.get('type'),
+			frame_id=params.get('frameId'),
+			remote_ip_address=response.get('remoteIPAddress'),
+			from_cache=bool(response.get('fromDiskCache') or response.get('fromServiceWorker')),
+			timestamp=params.get('timestamp'),
+			raw=params,
+		)
+
+
+class NetworkLoadingFailed(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Network.loadingFailed'
+
+	request_id: str
+	error_text: str
+	canceled: bool = False
+	blocked_reason: str | None = None
+	resource_type: str | None = None
+	timestamp: float | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'NetworkLoadingFailed':
+		return cls(
+			request_id=params['requestId'],
+			error_text=params.get('errorText', ''),
+			canceled=params.get('canceled', False),
+			blocked_reason=params.get('blockedReason'),
+			resource_type=params.get('type'),
+			timestamp=params.get('timestamp'),
+			raw=params,
+		)
+
+
+class RuntimeConsoleAPICalled(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Runtime.consoleAPICalled'
+
+	type: str  # log, warning, error, ...
+	text: str  # The arguments rendered the way the console shows primitives
+	timestamp: float | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'RuntimeConsoleAPICalled':
+		parts = []
+		for arg in params.get('args', []):
+			if 'value' in arg:
+				parts.append(str(arg['value']))
+			else:
+				parts.append(arg.get('description') or arg.get('type', ''))
+		return cls(type=params.get('type', ''), text=' '.join(parts), timestamp=params.get('timestamp'), raw=params)
+
+
+class RuntimeExceptionThrown(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Runtime.exceptionThrown'
+
+	text: str
+	url: str | None = None
+	line_number: int | None = None
+	column_number: int | None = None
+	timestamp: float | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'RuntimeExceptionThrown':
+		details = params.get('exceptionDetails', {})
+		exception = details.get('exception') or {}
+		return cls(
+			text=exception.get('description') or details.get('text', ''),
+			url=details.get('url'),
+			line_number=details.get('lineNumber'),
+			column_number=details.get('columnNumber'),
+			timestamp=params.get('timestamp'),
+			raw=params,
+		)
+
+
+class PageFrameNavigated(CDPEventModel):
+	cdp_method: ClassVar[str] = 'Page.frameNavigated'
+
+	frame_id: str
+	url: str
+	parent_frame_id: str | None = None
+
+	@classmethod
+	def from_params(cls, params: dict[str, Any]) -> 'PageFrameNavigated':
+		frame = params.get('frame', {})
+		return cls(frame_id=frame.get('id', ''), url=frame.get('url', ''), parent_frame_id=frame.get('parentId'), raw=params)
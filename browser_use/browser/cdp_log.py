@@ -0,0 +1,102 @@
+"""
+Wire log of the CDP traffic of a browser session, for debugging odd browser behavior.
+
+	BrowserProfile(cdp_log_dir='./cdp_logs', cdp_log_exclude=['DOMSnapshot.*', 'Network.dataReceived'])
+
+Writes one line per command, response and event to cdp_logs/cdp_<session id>.log:
+
+	12:01:02.345 → Page.navigate [A1B2] {"url": "https://example.com"}
+	12:01:02.812 ← Page.navigate [A1B2] 467ms {"frameId": "...", "loaderId": "..."}
+	12:01:02.813 ⚡ Page.frameNavigated [A1B2] {"frame": {...}}
+
+Screenshots, response bodies and other base64 blobs are replaced by their size, long payloads are truncated.
+"""
+
+import fnmatch
+import json
+import re
+import threading
+from datetime import datetime
+from pathlib import Path
+from typing import Any
+
+_BASE64_RE = re.compile(r'^[A-Za-z0-9+/]+={0,2}$')
+_BLOB_MIN_LENGTH = 256  # shorter strings are kept even if they look like base64
+
+
+def redact_blobs(value: Any) -> Any:
+	"""Replace base64 strings (screenshots, bodies, fonts) in a CDP payload by a placeholder with their length."""
+	if isinstance(value, dict):
+		return {key: redact_blobs(item) for key, item in value.items()}
+	if isinstance(value, list):
+		return [redact_blobs(item) for item in value]
+	if isinstance(value, str) and len(value) >= _BLOB_MIN_LENGTH:
+		if value.startswith('data:') and ';base64,' in value[:100]:
+			return f'<{value[: value.index(";base64,")]} {len(value)} chars>'
+		if _BASE64_RE.match(value[:4096]):
+			return f'<base64 {len(value)} chars>'
+	return value
+
+
+class CDPTrafficLogger:
+	"""Writes the filtered, redacted CDP commands, responses and events of one session to a file."""
+
+	def __init__(
+		self,
+		path: str | Path,
+		include: list[str] | None = None,
+		exclude: list[str] | None = None,
+		max_payload_chars: int | None = 2000,
+	):
+		self.path = Path(path).expanduser()
+		self.path.parent.mkdir(parents=True, exist_ok=True)
+		self.include = include
+		self.exclude = exclude or []
+		self.max_payload_chars = max_payload_chars
+		self._file = self.path.open('a', encoding='utf-8', buffering=1)
+		self._lock = threading.Lock()
+
+	def matches(self, method: str) -> bool:
+		"""Whether a method is logged: it matches an include pattern (all when None) and no exclude pattern."""
+		if self.include is not None and not any(fnmatch.fnmatchcase(method, pattern) for pattern in self.include):
+			return False
+		return not any(fnmatch.fnmatchcase(method, pattern) for pattern in self.exclude)
+
+	def log_command(self, method: str, params: Any, session_id: str | None) -> None:
+		self._write('→', method, session_id, params)
+
+	def log_response(self, method: str, result: Any, session_id: str | None, duration: float) -> None:
+		self._write('←', method, session_id, result, f'{duration * 1000:.0f}ms')
+
+	def log_error(self, method: str, error: BaseException, session_id: str | None, duration: float) -> None:
+		self._write('✖', method, session_id, f'{type(error).__name__}: {error}', f'{duration * 1000:.0f}ms')
+
+	def log_event(self, method: str, params: Any, session_id: str | None) -> None:
+		self._write('⚡', method, session_id, params)
+
+	def close(self) -> None:
+		with self._lock:
+			if not self._file.closed:
+				self._file.close()
+
+	def _write(self, direction: str, method: str, session_id: str | None, payload: Any, note: str | None = None) -> None:
+		if not self.matches(method):
+			return
+		if payload is None or payload == {}:
+			text = ''
+		elif isinstance(payload, str):
+			text = payload
+		else:
+			text = json.dumps(redact_blobs(payload), ensure_ascii=False, default=str)
+		if self.max_payload_chars is not None and len(text) > self.max_payload_chars:
+			text = f'{text[: self.max_payload_chars]}… ({len(text)} chars)'
+		parts = [datetime.now().strftime('%H:%M:%S.%f')[:-3], direction, method]
+		if session_id:
+			parts.append(f'[{session_id[-4:]}]')
+		if note:
+			parts.append(note)
+		if text:
+			parts.append(text)
+		with self._lock:
+			if not self._file.closed:
+				self._file.write(' '.join(parts) + '\n')
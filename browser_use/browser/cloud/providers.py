@@ -0,0 +1,172 @@
+"""Connectors for third-party hosted browser services (Browserbase, Steel, Anchor Browser).
+
+Each provider creates a remote browser session over the provider's REST API and resolves the
+CDP websocket URL to connect to, so BrowserSession(cloud_provider='browserbase') works without
+hand-rolled API calls. The session is released again when the BrowserSession is stopped.
+"""
+
+import logging
+import os
+from typing import Any, ClassVar, Literal
+from urllib.parse import parse_qs, urlencode, urlparse, urlunparse
+
+import httpx
+
+from browser_use.browser.cloud.views import CloudBrowserAuthError, CloudBrowserError, RemoteBrowserSession
+
+logger = logging.getLogger(__name__)
+
+CloudProviderName = Literal['browserbase', 'steel', 'anchor']
+
+
+class CloudProvider:
+	"""Base class for hosted browser providers, subclasses implement the provider specific API calls."""
+
+	name: ClassVar[str]
+	default_api_base_url: ClassVar[str]
+	api_key_env_var: ClassVar[str]
+	api_key_header: ClassVar[str]
+
+	def __init__(self, api_key: str | None = None, params: dict[str, Any] | None = None, api_base_url: str | None = None):
+		self.api_key = api_key or os.getenv(self.api_key_env_var)
+		if not self.api_key:
+			raise CloudBrowserAuthError(f'{self.api_key_env_var} is not set. Pass cloud_provider_api_key or set the env var.')
+		self.params = dict(params or {})
+		self.api_base_url = (api_base_url or self.default_api_base_url).rstrip('/')
+		self.client = httpx.AsyncClient(timeout=30.0)
+
+	async def create_session(self, keep_alive: bool = False) -> RemoteBrowserSession:
+		"""Create a remote browser session and return its CDP url.
+
+		keep_alive: ask the provider to keep the browser running when the CDP connection drops, where supported.
+		"""
+		raise NotImplementedError
+
+	async def stop_session(self, session_id: str) -> None:
+		"""Release a remote browser session so it stops counting against the account's usage."""
+		raise NotImplementedError
+
+	async def close(self) -> None:
+		if not self.client.is_closed:
+			await self.client.aclose()
+
+	async def _request(self, method: str, path: str, json: dict[str, Any] | None = None) -> dict[str, Any]:
+		headers = {self.api_key_header: self.api_key or '', 'Content-Type': 'application/json'}
+		try:
+			response = await self.client.request(method, f'{self.api_base_url}{path}', headers=headers, json=json)
+		except httpx.TimeoutException:
+			raise CloudBrowserError(f'Timeout while calling the {self.name} API. Please try again.')
+		except httpx.ConnectError:
+			raise CloudBrowserError(f'Failed to connect to the {self.name} API. Please check your internet connection.')
+
+		if response.status_code in (401, 403):
+			raise CloudBrowserAuthError(
+				f'{self.name} rejected the API key (HTTP {response.status_code}), check {self.api_key_env_var}.'
+			)
+		if not response.is_success:
+			raise CloudBrowserError(f'{self.name} API error: HTTP {response.status_code} - {response.text[:500]}')
+		if not response.content:
+			return {}
+		return response.json()
+
+
+class BrowserbaseProvider(CloudProvider):
+	"""https://docs.browserbase.com/reference/api/create-a-session"""
+
+	name = 'browserbase'
+	default_api_base_url = 'https://api.browserbase.com'
+	api_key_env_var = 'BROWSERBASE_API_KEY'
+	api_key_header = 'X-BB-API-Key'
+
+	def __init__(self, api_key: str | None = None, params: dict[str, Any] | None = None, api_base_url: str | None = None):
+		super().__init__(api_key=api_key, params=params, api_base_url=api_base_url)
+		project_id = self.params.get('projectId') or os.getenv('BROWSERBASE_PROJECT_ID')
+		if project_id:
+			self.params['projectId'] = project_id
+
+	async def create_session(self, keep_alive: bool = False) -> RemoteBrowserSession:
+		body = {**self.params}
+		if keep_alive:
+			body.setdefault('keepAlive', True)
+		data = await self._request('POST', '/v1/sessions', json=body)
+		if not data.get('connectUrl'):
+			raise CloudBrowserError(f'browserbase did not return a connectUrl for session {data.get("id")}')
+
+		live_url = None
+		try:
+			debug_data = await self._request('GET', f'/v1/sessions/{data["id"]}/debug')
+			live_url = debug_data.get('debuggerFullscreenUrl')
+		except CloudBrowserError as e:
+			logger.debug(f'Could not fetch browserbase live URL: {e}')
+
+		return RemoteBrowserSession(provider=self.name, id=data['id'], cdp_url=data['connectUrl'], live_url=live_url)
+
+	async def stop_session(self, session_id: str) -> None:
+		body: dict[str, Any] = {'status': 'REQUEST_RELEASE'}
+		if self.params.get('projectId'):
+			body['projectId'] = self.params['projectId']
+		await self._request('POST', f'/v1/sessions/{session_id}', json=body)
+
+
+class SteelProvider(CloudProvider):
+	"""https://docs.steel.dev/api-reference"""
+
+	name = 'steel'
+	default_api_base_url = 'https://api.steel.dev'
+	api_key_env_var = 'STEEL_API_KEY'
+	api_key_header = 'steel-api-key'
+
+	async def create_session(self, keep_alive: bool = False) -> RemoteBrowserSession:
+		# Steel sessions outlive the CDP connection until released or timed out, so keep_alive needs no flag
+		data = await self._request('POST', '/v1/sessions', json=self.params)
+		session_id = data['id']
+
+		# The websocket url authenticates with query params instead of headers
+		websocket_url = data.get('websocketUrl') or 'wss://connect.steel.dev'
+		parsed = urlparse(websocket_url)
+		query = parse_qs(parsed.query)
+		query.setdefault('apiKey', [self.api_key or ''])
+		query.setdefault('sessionId', [session_id])
+		cdp_url = urlunparse(parsed._replace(query=urlencode(query, doseq=True)))
+
+		return RemoteBrowserSession(provider=self.name, id=session_id, cdp_url=cdp_url, live_url=data.get('sessionViewerUrl'))
+
+	async def stop_session(self, session_id: str) -> None:
+		await self._request('POST', f'/v1/sessions/{session_id}/release')
+
+
+class AnchorProvider(CloudProvider):
+	"""https://docs.anchorbrowser.io/api-reference"""
+
+	name = 'anchor'
+	default_api_base_url = 'https://api.anchorbrowser.io'
+	api_key_env_var = 'ANCHOR_API_KEY'
+	api_key_header = 'anchor-api-key'
+
+	async def create_session(self, keep_alive: bool = False) -> RemoteBrowserSession:
+		data = (await self._request('POST', '/v1/sessions', json=self.params)).get('data', {})
+		if not data.get('cdp_url'):
+			raise CloudBrowserError(f'anchor did not return a cdp_url for session {data.get("id")}')
+		return RemoteBrowserSession(
+			provider=self.name, id=data['id'], cdp_url=data['cdp_url'], live_url=data.get('live_view_url')
+		)
+
+	async def stop_session(self, session_id: str) -> None:
+		await self._request('DELETE', f'/v1/sessions/{session_id}')
+
+
+CLOUD_PROVIDERS: dict[str, type[CloudProvider]] = {
+	BrowserbaseProvider.name: BrowserbaseProvider,
+	SteelProvider.name: SteelProvider,
+	AnchorProvider.name: AnchorProvider,
+}
+
+
+def get_cloud_provider(
+	name: str, api_key: str | None = None, params: dict[str, Any] | None = None, api_base_url: str | None = None
+) -> CloudProvider:
+	"""Create the connector for a hosted browser provider by name."""
+	provider_class = CLOUD_PROVIDERS.get(name.lower())
+	if provider_class is None:
+		raise ValueError(f'Unknown cloud browser provider {name!r}, expected one of: {", ".join(CLOUD_PROVIDERS)}')
+	return provider_class(api_key=api_key, params=params, api_base_url=api_base_url)
@@ -83,6 +83,16 @@ class CloudBrowserResponse(BaseModel):
 	finishedAt: str | None = Field(alias='finishedAt', default=None)
 
 
+class RemoteBrowserSession(BaseModel):
+	"""A browser session created on a third-party hosted browser provider."""
+
+	provider: str
+	id: str
+	cdp_url: str = Field(repr=False)  # usually carries the API key as a query param
+	headers: dict[str, str] = Field(default_factory=dict, repr=False)  # extra headers for the CDP websocket connection
+	live_url: str | None = None
+
+
 # Errors
 class CloudBrowserError(Exception):
 	"""Exception raised when cloud browser operations fail."""
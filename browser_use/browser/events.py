@@ -107,8 +107,49 @@ class ElementSelectedEvent(BaseEvent[T_EventResultType]):
 # 	page: PageHandle
 
 
-class NavigateToUrlEvent(BaseEvent[None]):
-	"""Navigate to a specific URL."""
+class NavigationResult(BaseModel):
+	"""Where a navigation ended up and how the server answered, from the Network events of the tab's main document."""
+
+	url: str  # as requested
+	final_url: str
+	status: int | None = None  # None for pages without an HTTP response, e.g. about:blank, data: or same-document URLs
+	status_text: str = ''
+	redirect_chain: list[str] = Field(default_factory=list)  # URLs that redirected, in order, the requested one first
+	loading_status: str | None = None  # set when the wait_until signal timed out
+	reused_tab_id: str | None = None  # open tab on the same origin navigated instead of a new tab, see reuse_same_origin_tabs
+	closed_tab_ids: list[str] = Field(default_factory=list)  # least recently used tabs closed to respect max_agent_tabs
+
+	def error_message(self) -> str | None:
+		"""An actionable error for the LLM if the server answered with an HTTP error, None otherwise."""
+		if self.status is None or self.status < 400:
+			return None
+		answer = f'{self.final_url} returned HTTP {self.status}{f" {self.status_text}" if self.status_text else ""}'
+		if self.status in (404, 410):
+			hint = 'The page does not exist. Check the URL for typos, or find the page via the site navigation or a search.'
+		elif self.status in (401, 403):
+			hint = 'Access is denied. The page may require logging in, or the site blocks automated browsers.'
+		elif self.status == 429:
+			hint = 'The site is rate limiting requests. Wait before retrying or use another source.'
+		elif self.status >= 500:
+			hint = 'The server failed. Retry later or use another source.'
+		else:
+			hint = 'The request was rejected. Check the URL and its parameters.'
+		return f'{answer}. {hint}'
+
+	def describe(self) -> str:
+		"""One line for the action result, e.g. 'http://a.com -> https://www.a.com/ (HTTP 200)'"""
+		description = self.url if self.final_url in ('', self.url) else f'{self.url} -> {self.final_url}'
+		if len(self.redirect_chain) > 1:
+			description += f' (redirected via {" -> ".join(self.redirect_chain[1:])})'
+		if self.status is not None:
+			description += f' (HTTP {self.status})'
+		if self.loading_status:
+			description += f' (page may not be fully loaded: {self.loading_status})'
+		return description
+
+
+class NavigateToUrlEvent(BaseEvent[NavigationResult | None]):
+	"""Navigate to a specific URL, returns a NavigationResult once the page reached wait_until."""
 
 	url: str
 	wait_until: Literal['load', 'domcontentloaded', 'networkidle', 'commit'] = 'load'
@@ -147,7 +188,7 @@ class ClickCoordinateEvent(BaseEvent[dict]):
 class TypeTextEvent(ElementSelectedEvent[dict | None]):
 	"""Type text into an element."""
 
-	node: 'EnhancedDOMTreeNode'
+	node: 'EnhancedDOMTreeNode | None' = None  # None means type into the focused element
 	text: str
 	clear: bool = True
 	is_sensitive: bool = False  # Flag to indicate if text contains sensitive data
@@ -162,6 +203,8 @@ class ScrollEvent(ElementSelectedEvent[None]):
 	direction: Literal['up', 'down', 'left', 'right']
 	amount: int  # pixels
 	node: 'EnhancedDOMTreeNode | None' = None  # None means scroll page
+	coordinate_x: int | None = None  # Page scrolls happen under this viewport point, None means the viewport center
+	coordinate_y: int | None = None
 
 	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_ScrollEvent', 8.0))  # seconds
 
@@ -250,6 +293,7 @@ class UploadFileEvent(ElementSelectedEvent[None]):
 
 	node: 'EnhancedDOMTreeNode'
 	file_path: str
+	file_paths: list[str] = Field(default_factory=list)  # all files when uploading several, file_path is the first
 
 	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_UploadFileEvent', 30.0))  # seconds
 
@@ -274,7 +318,9 @@ class SelectDropdownOptionEvent(ElementSelectedEvent[dict[str, str]]):
 	node: 'EnhancedDOMTreeNode'
 	text: str  # The option text to select
 
-	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_SelectDropdownOptionEvent', 8.0))  # seconds
+	event_timeout: float | None = Field(
+		default_factory=lambda: _get_timeout('TIMEOUT_SelectDropdownOptionEvent', 15.0)
+	)  # custom dropdowns are opened and their virtualized option lists scrolled to find the option
 
 
 class ScrollToTextEvent(BaseEvent[None]):
@@ -299,9 +345,15 @@ class BrowserStartEvent(BaseEvent):
 
 
 class BrowserStopEvent(BaseEvent):
-	"""Stop/disconnect from browser."""
+	"""Stop/disconnect from browser.
+
+	close_tabs: close the tabs opened during the session when disconnecting from a browser we don't own
+	(None = BrowserProfile.close_created_tabs). close_browser: also shut down a remote browser over CDP.
+	"""
 
 	force: bool = False
+	close_tabs: bool | None = None
+	close_browser: bool = False
 
 	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_BrowserStopEvent', 45.0))  # seconds
 
@@ -632,6 +684,22 @@ class CaptchaSolverFinishedEvent(BaseEvent):
 	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_CaptchaSolverFinishedEvent', 5.0))
 
 
+# ============================================================================
+# Consent Banner Events
+# ============================================================================
+
+
+class DismissConsentBannerEvent(BaseEvent[str | None]):
+	"""Detect a cookie consent banner on the focused tab and click its reject or accept button.
+
+	Returns a short description of what was clicked, or None if no known consent banner was found.
+	"""
+
+	policy: Literal['reject', 'accept'] = 'reject'
+
+	event_timeout: float | None = Field(default_factory=lambda: _get_timeout('TIMEOUT_DismissConsentBannerEvent', 10.0))
+
+
 # Note: Model rebuilding for forward references is handled in the importing modules
 # Events with 'EnhancedDOMTreeNode' forward references (ClickElementEvent, TypeTextEvent,
 # ScrollEvent, UploadFileEvent) need model_rebuild() called after imports are complete
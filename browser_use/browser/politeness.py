@@ -0,0 +1,126 @@
+"""
+Politeness controls for scraping jobs: per-domain navigation rate limits, tab caps and robots.txt.
+
+	BrowserProfile(domain_rate_limit=2.0, domain_rate_limit_jitter=1.0, max_tabs_per_domain=2, respect_robots_txt=True)
+
+Navigations (navigate, search, new tabs) to a domain wait until domain_rate_limit seconds (plus a random jitter) passed
+since the previous one, and a robots.txt Crawl-delay raises that interval. Domains are compared without "www.".
+"""
+
+import asyncio
+import logging
+import random
+import time
+from urllib.parse import urlparse
+from urllib.robotparser import RobotFileParser
+
+import httpx
+
+from browser_use.browser.views import URLNotAllowedError
+
+logger = logging.getLogger(__name__)
+
+ROBOTS_TXT_TIMEOUT_S = 10.0
+
+
+class RobotsTxtDisallowedError(URLNotAllowedError):
+	"""The site's robots.txt disallows the URL and the session has respect_robots_txt=True"""
+
+	error_type = 'robots_txt_disallowed'
+
+
+class TooManyDomainTabsError(URLNotAllowedError):
+	"""Opening the URL would exceed max_tabs_per_domain"""
+
+	error_type = 'too_many_domain_tabs'
+
+
+def politeness_domain(url: str) -> str | None:
+	"""The domain politeness limits apply to, None for URLs that don't load from a site (about:, data:, chrome://)."""
+	parsed = urlparse(url)
+	if parsed.scheme not in ('http', 'https') or not parsed.hostname:
+		return None
+	host = parsed.hostname.lower()
+	return host[4:] if host.startswith('www.') else host
+
+
+class DomainPoliteness:
+	"""Per-domain navigation pacing and robots.txt rules of one browser session."""
+
+	def __init__(
+		self,
+		rate_limit: float | None = None,
+		jitter: float = 0.0,
+		respect_robots_txt: bool = False,
+		user_agent: str | None = None,
+		proxy: str | None = None,
+	):
+		self.rate_limit = rate_limit
+		self.jitter = jitter
+		self.respect_robots_txt = respect_robots_txt
+		self.user_agent = user_agent
+		self.proxy = proxy
+		self._last_navigation: dict[str, float] = {}
+		self._locks: dict[str, asyncio.Lock] = {}
+		self._robots: dict[str, RobotFileParser] = {}
+
+	async def before_navigation(self, url: str) -> None:
+		"""Raise RobotsTxtDisallowedError for disallowed URLs, otherwise wait for the domain's turn."""
+		domain = politeness_domain(url)
+		if domain is None:
+			return
+		robots = await self._get_robots(url) if self.respect_robots_txt else None
+		if robots is not None and not robots.can_fetch(self._robots_agent, url):
+			raise RobotsTxtDisallowedError(
+				f'Navigation to {url} blocked: robots.txt of {domain} disallows it (respect_robots_txt=True)',
+				long_term_memory=f'robots.txt of {domain} disallows {url}, use another page or site',
+			)
+
+		interval = self.rate_limit or 0.0
+		crawl_delay = robots.crawl_delay(self._robots_agent) if robots is not None else None
+		if crawl_delay:
+			interval = max(interval, float(crawl_delay))
+		if not interval:
+			return
+
+		# One navigation per domain at a time, so parallel tabs take turns instead of all firing after the wait
+		lock = self._locks.setdefault(domain, asyncio.Lock())
+		async with lock:
+			last = self._last_navigation.get(domain)
+			if last is not None:
+				wait = last + interval + random.uniform(0, self.jitter) - time.monotonic()
+				if wait > 0:
+					logger.info(f'🐢 Waiting {wait:.1f}s before the next navigation to {domain}')
+					await asyncio.sleep(wait)
+			self._last_navigation[domain] = time.monotonic()
+
+	@property
+	def _robots_agent(self) -> str:
+		return self.user_agent or '*'
+
+	async def _get_robots(self, url: str) -> RobotFileParser:
+		"""The parsed robots.txt of the URL's origin, fetched once per origin."""
+		parsed = urlparse(url)
+		origin = f'{parsed.scheme}://{parsed.netloc}'
+		if origin in self._robots:
+			return self._robots[origin]
+
+		robots = RobotFileParser(f'{origin}/robots.txt')
+		try:
+			headers = {'User-Agent': self.user_agent} if self.user_agent else None
+			async with httpx.AsyncClient(
+				timeout=ROBOTS_TXT_TIMEOUT_S, follow_redirects=True, headers=headers, proxy=self.proxy
+			) as client:
+				response = await client.get(robots.url)
+			# Same rules as RobotFileParser.read(): 401/403 disallow everything, other errors allow everything
+			if response.status_code in (401, 403):
+				robots.disallow_all = True
+			elif response.status_code >= 400:
+				robots.allow_all = True
+			else:
+				robots.parse(response.text.splitlines())
+		except Exception as e:
+			logger.debug(f'Could not fetch {robots.url}, treating it as allowing everything: {type(e).__name__}: {e}')
+			robots.allow_all = True
+		self._robots[origin] = robots
+		return robots
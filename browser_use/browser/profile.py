@@ -1,4 +1,6 @@
+import json
 import os
+import socket
 import sys
 import tempfile
 from collections.abc import Iterable
@@ -11,6 +13,7 @@ from urllib.parse import urlparse
 
 from pydantic import AfterValidator, AliasChoices, BaseModel, ConfigDict, Field, field_validator, model_validator
 
+from browser_use.browser.cloud.providers import CloudProviderName
 from browser_use.browser.cloud.views import CloudBrowserParams
 from browser_use.config import CONFIG
 from browser_use.utils import _log_pretty_path, logger
@@ -113,6 +116,57 @@ def _is_chrome_profile_lock_error(error: BaseException) -> bool:
 	return False
 
 
+class ProfileInUseError(RuntimeError):
+	"""Raised when another running browser already uses the user_data_dir of a persistent profile."""
+
+
+def _is_temp_user_data_dir(user_data_dir: str | Path) -> bool:
+	"""Temp profiles (user_data_dir=None, copied Chrome profiles, launch retries) are never shared."""
+	path = str(user_data_dir).lower()
+	return 'browser-use-user-data-dir-' in path or 'browseruse-tmp-' in path
+
+
+def _get_profile_lock_owner(user_data_dir: str | Path) -> str | None:
+	"""Return the holder of Chrome's profile lock on user_data_dir, None if the profile is free or the lock is stale."""
+	user_data_dir = Path(user_data_dir)
+
+	if sys.platform == 'win32':
+		# Chrome keeps "lockfile" open exclusively while it runs
+		lockfile = user_data_dir / 'lockfile'
+		if not lockfile.exists():
+			return None
+		try:
+			with open(lockfile, 'a'):
+				return None
+		except PermissionError:
+			return 'another process'
+
+	# On macOS/Linux SingletonLock is a symlink to "<hostname>-<pid>" of the browser using the profile
+	try:
+		owner = os.readlink(user_data_dir / 'SingletonLock')
+	except OSError:
+		return None
+	hostname, _, pid = owner.rpartition('-')
+	if hostname != socket.gethostname():
+		# Processes on other machines (shared home dirs) can't be checked, Chrome refuses these profiles too
+		return owner
+
+	import psutil
+
+	if pid.isdigit() and psutil.pid_exists(int(pid)):
+		return owner
+	return None
+
+
+def _merge_prefs(target: dict[str, Any], prefs: dict[str, Any]) -> None:
+	"""Recursively merge nested prefs into target, keys are taken literally."""
+	for key, value in prefs.items():
+		if isinstance(value, dict) and isinstance(target.get(key), dict):
+			_merge_prefs(target[key], value)
+		else:
+			target[key] = value
+
+
 CHROME_HEADLESS_ARGS = [
 	'--headless=new',
 ]
@@ -233,6 +287,78 @@ class ViewportSize(BaseModel):
 		setattr(self, key, value)
 
 
+class DevicePreset(BaseModel):
+	"""Screen metrics, user agent and input capabilities of a device to emulate."""
+
+	viewport: ViewportSize
+	screen: ViewportSize
+	device_scale_factor: float
+	user_agent: str
+	is_mobile: bool = True
+	has_touch: bool = True
+
+
+_IOS_USER_AGENT = (
+	'Mozilla/5.0 ({device}; CPU {os} 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) '
+	'Version/17.5 Mobile/15E148 Safari/604.1'
+)
+_ANDROID_USER_AGENT = (
+	'Mozilla/5.0 (Linux; Android 14; {model}) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36'
+)
+
+# Viewport sizes are the visible page area in the device's default browser (screen minus browser UI)
+DEVICE_PRESETS: dict[str, DevicePreset] = {
+	'iPhone 15': DevicePreset(
+		viewport=ViewportSize(width=393, height=659),
+		screen=ViewportSize(width=393, height=852),
+		device_scale_factor=3,
+		user_agent=_IOS_USER_AGENT.format(device='iPhone', os='iPhone OS'),
+	),
+	'iPhone 15 Pro Max': DevicePreset(
+		viewport=ViewportSize(width=430, height=739),
+		screen=ViewportSize(width=430, height=932),
+		device_scale_factor=3,
+		user_agent=_IOS_USER_AGENT.format(device='iPhone', os='iPhone OS'),
+	),
+	'iPhone SE': DevicePreset(
+		viewport=ViewportSize(width=375, height=548),
+		screen=ViewportSize(width=375, height=667),
+		device_scale_factor=2,
+		user_agent=_IOS_USER_AGENT.format(device='iPhone', os='iPhone OS'),
+	),
+	'Pixel 7': DevicePreset(
+		viewport=ViewportSize(width=412, height=839),
+		screen=ViewportSize(width=412, height=915),
+		device_scale_factor=2.625,
+		user_agent=_ANDROID_USER_AGENT.format(model='Pixel 7'),
+	),
+	'Pixel 8 Pro': DevicePreset(
+		viewport=ViewportSize(width=448, height=922),
+		screen=ViewportSize(width=448, height=998),
+		device_scale_factor=3,
+		user_agent=_ANDROID_USER_AGENT.format(model='Pixel 8 Pro'),
+	),
+	'Galaxy S24': DevicePreset(
+		viewport=ViewportSize(width=360, height=704),
+		screen=ViewportSize(width=360, height=780),
+		device_scale_factor=3,
+		user_agent=_ANDROID_USER_AGENT.format(model='SM-S921B'),
+	),
+	'iPad Mini': DevicePreset(
+		viewport=ViewportSize(width=768, height=1024),
+		screen=ViewportSize(width=768, height=1024),
+		device_scale_factor=2,
+		user_agent=_IOS_USER_AGENT.format(device='iPad', os='OS'),
+	),
+	'iPad Pro 11': DevicePreset(
+		viewport=ViewportSize(width=834, height=1194),
+		screen=ViewportSize(width=834, height=1194),
+		device_scale_factor=2,
+		user_agent=_IOS_USER_AGENT.format(device='iPad', os='OS'),
+	),
+}
+
+
 @cache
 def get_display_size() -> ViewportSize | None:
 	# macOS
@@ -363,16 +489,32 @@ class BrowserContextArgs(BaseModel):
 
 	# Viewport options
 	user_agent: str | None = None
+	locale: str | None = Field(default=None, description='Locale to emulate, e.g. "de-DE" (navigator.language, Accept-Language).')
+	timezone_id: str | None = Field(default=None, description='Timezone to emulate, e.g. "Europe/Berlin".')
 	screen: ViewportSize | None = None
 	viewport: ViewportSize | None = Field(default=None)
 	no_viewport: bool | None = None
 	device_scale_factor: NonNegativeFloat | None = None
+	is_mobile: bool = Field(default=False, description='Emulate a mobile device, pages honor meta viewport tags.')
+	has_touch: bool = Field(default=False, description='Emulate a touch screen, clicks and scrolls are sent as touch gestures.')
 	# geolocation: Geolocation | None = None
 
 	# Recording Options
 	record_har_content: RecordHarContent = RecordHarContent.EMBED
 	record_har_mode: RecordHarMode = RecordHarMode.FULL
 	record_har_path: str | Path | None = Field(default=None, validation_alias=AliasChoices('save_har_path', 'record_har_path'))
+	record_har: bool = Field(
+		default=False,
+		description='Record network activity as HAR without a fixed record_har_path, agents with artifacts_dir save it as network.har in each run bundle.',
+	)
+	record_har_max_body_size: int | None = Field(
+		default=None,
+		description='Leave request and response bodies larger than this many bytes out of the HAR (record_har_content embed or attach).',
+	)
+	record_har_sensitive: bool = Field(
+		default=False,
+		description='Keep credential headers (Authorization, Cookie, Set-Cookie, ...) and request bodies in the HAR, they are masked and left out by default.',
+	)
 	record_video_dir: str | Path | None = Field(
 		default=None, validation_alias=AliasChoices('save_recording_path', 'record_video_dir')
 	)
@@ -390,6 +532,23 @@ class BrowserConnectArgs(BaseModel):
 	model_config = ConfigDict(extra='ignore', validate_assignment=True, revalidate_instances='always', populate_by_name=True)
 
 	headers: dict[str, str] | None = Field(default=None, description='Additional HTTP headers to be sent with connect request')
+	cdp_max_message_size: int | None = Field(
+		default=200 * 1024 * 1024,
+		gt=0,
+		description='Largest CDP WebSocket message accepted in bytes, e.g. a full-page screenshot. None for no limit.',
+	)
+	cdp_log_dir: str | Path | None = Field(
+		default=None, description='Write the CDP commands, responses and events of each session to cdp_<session id>.log here.'
+	)
+	cdp_log_include: list[str] | None = Field(
+		default=None, description="Only log CDP methods matching these globs, e.g. ['Page.*', 'Input.*']. None logs all."
+	)
+	cdp_log_exclude: list[str] = Field(
+		default_factory=list, description="Don't log CDP methods matching these globs, e.g. ['Network.dataReceived']."
+	)
+	cdp_log_max_payload_chars: int | None = Field(
+		default=2000, description='Truncate logged CDP payloads to this many characters, base64 blobs are always redacted.'
+	)
 
 
 class BrowserLaunchArgs(BaseModel):
@@ -596,6 +755,13 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 
 	# Session/connection configuration
 	cdp_url: str | None = Field(default=None, description='CDP URL for connecting to existing browser instance')
+	protocol: Literal['cdp', 'bidi'] = Field(
+		default='cdp',
+		description="Automation protocol, 'bidi' drives the browser at bidi_url over WebDriver BiDi (page and element APIs only, Agent needs 'cdp').",
+	)
+	bidi_url: str | None = Field(
+		default=None, description='WebDriver BiDi WebSocket URL, e.g. ws://127.0.0.1:9222/session for Firefox'
+	)
 	is_local: bool = Field(default=False, description='Whether this is a local browser instance')
 	use_cloud: bool = Field(
 		default=False,
@@ -610,6 +776,19 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 	cloud_browser_params: CloudBrowserParams | None = Field(
 		default=None, description='Parameters for creating a cloud browser instance'
 	)
+	cloud_provider: CloudProviderName | None = Field(
+		default=None,
+		description='Create the browser on a third-party hosted browser service (browserbase, steel or anchor) instead of launching one locally.',
+	)
+	cloud_provider_api_key: str | None = Field(
+		default=None,
+		repr=False,
+		description='API key for cloud_provider, defaults to the BROWSERBASE_API_KEY / STEEL_API_KEY / ANCHOR_API_KEY env var.',
+	)
+	cloud_provider_params: dict[str, Any] | None = Field(
+		default=None,
+		description='Extra session creation parameters sent to the cloud_provider API as-is, e.g. {"region": "us-west-2"} for browserbase.',
+	)
 
 	# custom options we provide that aren't native playwright kwargs
 	disable_security: bool = Field(default=False, description='Disable browser security features.')
@@ -626,7 +805,59 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 		default=False,
 		description='Block navigation to URLs containing IP addresses (both IPv4 and IPv6). When True, blocks all IP-based URLs including localhost and private networks.',
 	)
+	domain_rate_limit: float | None = Field(
+		default=None,
+		gt=0,
+		description='Minimum seconds between two navigations to the same domain (navigate, search, new tabs).',
+	)
+	domain_rate_limit_jitter: float = Field(
+		default=0.0, ge=0, description='Random extra delay of up to this many seconds added to domain_rate_limit waits.'
+	)
+	max_tabs_per_domain: int | None = Field(
+		default=None, ge=1, description='Refuse navigations that would open more than this many tabs on the same domain.'
+	)
+	respect_robots_txt: bool = Field(
+		default=False,
+		description='Refuse navigations to URLs the site robots.txt disallows (for user_agent, or *) and wait its Crawl-delay.',
+	)
+	reuse_same_origin_tabs: bool = Field(
+		default=False,
+		description='Make navigations with new_tab=True navigate an already open tab on the same origin instead of opening another.',
+	)
+	max_agent_tabs: int | None = Field(
+		default=None,
+		ge=1,
+		description='Keep at most this many tabs opened during the session, closing the least recently used ones when navigate opens another.',
+	)
 	keep_alive: bool | None = Field(default=None, description='Keep browser alive after agent run.')
+	close_created_tabs: bool = Field(
+		default=True,
+		description='When stopping a session connected to an existing browser via cdp_url, close the tabs and popups opened during the session. Ignored with keep_alive=True.',
+	)
+	target_id: str | None = Field(
+		default=None,
+		description='When connecting to an existing browser, attach the agent to the already-open tab with this CDP target id instead of the first tab.',
+	)
+	target_url_pattern: str | None = Field(
+		default=None,
+		description='When connecting to an existing browser, attach the agent to the first open tab whose URL contains this text or matches this glob (e.g. "*/dashboard/*").',
+	)
+	target_title_pattern: str | None = Field(
+		default=None,
+		description='When connecting to an existing browser, attach the agent to the first open tab whose title contains this text or matches this glob, case-insensitive.',
+	)
+	restrict_to_target: bool = Field(
+		default=False,
+		description='Keep the agent on the tab it attached to: other tabs are hidden from it, tab switches are refused and new_tab navigations load in the attached tab.',
+	)
+	isolated_context: bool = Field(
+		default=False,
+		description='Run in a new incognito-like browser context (Target.createBrowserContext): cookies, storage and cache are not shared with other contexts of the same browser, and the session only sees its own tabs. The context is disposed when the session stops.',
+	)
+	browser_context_name: str | None = Field(
+		default=None,
+		description='Like isolated_context, but sessions with the same name (and cdp_url) in this process reuse one context and its cookies. Named contexts are kept when a session stops and end with the browser.',
+	)
 
 	# --- Proxy settings ---
 	# New consolidated proxy config (typed)
@@ -638,6 +869,18 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 		default_factory=_get_enable_default_extensions_default,
 		description="Enable automation-optimized extensions: ad blocking (uBlock Origin), cookie handling (I still don't care about cookies), and URL cleaning (ClearURLs). All extensions work automatically without manual intervention. Extensions are automatically downloaded and loaded when enabled. Can be disabled via BROWSER_USE_DISABLE_EXTENSIONS=1 environment variable.",
 	)
+	extension_paths: list[str | Path] = Field(
+		default_factory=list,
+		description='Paths to unpacked extension directories (containing a manifest.json) to load, in addition to the default extensions.',
+	)
+	prefs: dict[str, Any] = Field(
+		default_factory=dict,
+		description='Chrome preferences written to the profile before launch, as nested dicts or dotted keys e.g. {"download.default_directory": "/tmp/downloads", "download.prompt_for_download": False}.',
+	)
+	block_notifications: bool = Field(
+		default=False,
+		description='Block notification permission prompts by setting the profile.default_content_setting_values.notifications pref.',
+	)
 	captcha_solver: bool = Field(
 		default=True,
 		description='Enable the captcha solver watchdog that listens for captcha events from the browser proxy. Automatically pauses agent steps while a CAPTCHA is being solved. Only active when the browser emits BrowserUse CDP events (e.g. Browser Use cloud browsers). Harmless when disabled or when events are not emitted.',
@@ -681,6 +924,13 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 	wait_for_network_idle_page_load_time: float = Field(default=0.5, description='Time to wait for network idle.')
 
 	wait_between_actions: float = Field(default=0.1, description='Time to wait between actions.')
+	typing_delay: float | None = Field(
+		default=None,
+		ge=0,
+		description='Seconds between typed characters and keys in input and send_keys, None types as fast as possible. '
+		'Around 0.05-0.15 looks like human typing to bot detection and autocomplete widgets.',
+	)
+	typing_delay_jitter: float = Field(default=0.0, ge=0, description='Random extra delay of up to this many seconds per key.')
 
 	# --- UI/viewport/DOM ---
 	highlight_elements: bool = Field(default=True, description='Highlight interactive elements on the page.')
@@ -697,6 +947,24 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 	)
 	interaction_highlight_duration: float = Field(default=1.0, description='Duration in seconds to show interaction highlights.')
 
+	device: str | None = Field(
+		default=None,
+		description=f'Device preset to emulate: {", ".join(DEVICE_PRESETS)}. Sets viewport, screen, device_scale_factor, user_agent, is_mobile and has_touch unless they are set explicitly.',
+	)
+
+	stealth: bool = Field(
+		default=False,
+		description='Patch common headless/automation fingerprints (navigator.webdriver, plugins, languages, HeadlessChrome user agent and client hints) in every tab.',
+	)
+	capture_page_errors: bool = Field(
+		default=True,
+		description='Collect JavaScript errors and failed network requests and show them to the agent in its browser state.',
+	)
+	recover_crashed_tabs: bool = Field(
+		default=True,
+		description="Before each agent step, reload or replace a crashed tab and take the blank tab left after the agent's tab disappeared back to its last page.",
+	)
+
 	# --- Downloads ---
 	auto_download_pdfs: bool = Field(default=True, description='Automatically download PDFs when navigating to PDF viewer pages.')
 
@@ -752,6 +1020,16 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 
 		return v
 
+	@field_validator('device', mode='after')
+	@classmethod
+	def validate_device(cls, v: str | None) -> str | None:
+		if v is None:
+			return v
+		for name in DEVICE_PRESETS:
+			if name.lower() == v.strip().lower():
+				return name
+		raise ValueError(f'Unknown device preset {v!r}, available presets: {", ".join(DEVICE_PRESETS)}')
+
 	@model_validator(mode='after')
 	def copy_old_config_names_to_new(self) -> Self:
 		"""Copy old config window_width & window_height to window_size."""
@@ -766,6 +1044,12 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 
 		return self
 
+	@model_validator(mode='after')
+	def validate_bidi_url(self) -> Self:
+		if self.protocol == 'bidi' and not self.bidi_url:
+			raise ValueError("protocol='bidi' needs bidi_url, the browser's WebDriver BiDi WebSocket URL")
+		return self
+
 	@model_validator(mode='after')
 	def warn_storage_state_user_data_dir_conflict(self) -> Self:
 		"""Warn when both storage_state and user_data_dir are set, as this can cause conflicts."""
@@ -832,6 +1116,7 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 
 	def model_post_init(self, __context: Any) -> None:
 		"""Called after model initialization to set up display configuration."""
+		self.apply_device_preset()
 		self.detect_display_configuration()
 		self._copy_profile()
 
@@ -892,6 +1177,55 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 
 		self.user_data_dir = temp_dir
 
+	def check_profile_not_in_use(self) -> None:
+		"""Raise ProfileInUseError if another running browser uses this persistent user_data_dir.
+
+		Two browsers on the same profile corrupt it, and Chrome hands the second launch over to the first one.
+		"""
+		if self.user_data_dir is None or _is_temp_user_data_dir(self.user_data_dir):
+			return
+		owner = _get_profile_lock_owner(self.user_data_dir)
+		if owner:
+			raise ProfileInUseError(
+				f'user_data_dir={_log_pretty_path(self.user_data_dir)} is already in use by another browser ({owner}). '
+				'Close that browser, or give each agent its own user_data_dir (user_data_dir=None uses a temporary profile).'
+			)
+
+	def get_prefs(self) -> dict[str, Any]:
+		"""Get the Chrome prefs to write into the profile before launch."""
+		prefs: dict[str, Any] = {}
+		if self.block_notifications:
+			prefs['profile.default_content_setting_values.notifications'] = 2  # 2 = block
+		prefs.update(self.prefs)
+		return prefs
+
+	def write_prefs(self) -> None:
+		"""Merge get_prefs() into <user_data_dir>/<profile_directory>/Preferences, keeping the prefs already there."""
+		prefs = self.get_prefs()
+		if not prefs or self.user_data_dir is None:
+			return
+
+		preferences_path = Path(self.user_data_dir) / self.profile_directory / 'Preferences'
+		existing: dict[str, Any] = {}
+		if preferences_path.exists():
+			try:
+				existing = json.loads(preferences_path.read_text(encoding='utf-8'))
+			except (OSError, ValueError) as e:
+				logger.warning(f'Could not read {_log_pretty_path(preferences_path)}, rewriting it with the prefs: {e}')
+
+		for key, value in prefs.items():
+			# Dotted keys address nested prefs, e.g. download.default_directory
+			*parents, name = key.split('.')
+			target = existing
+			for parent in parents:
+				if not isinstance(target.get(parent), dict):
+					target[parent] = {}
+				target = target[parent]
+			_merge_prefs(target, {name: value})
+
+		preferences_path.parent.mkdir(parents=True, exist_ok=True)
+		preferences_path.write_text(json.dumps(existing), encoding='utf-8')
+
 	def get_args(self) -> list[str]:
 		"""Get the list of all Chrome CLI launch args for this profile (compiled from defaults, user-provided, and system-specific)."""
 
@@ -924,7 +1258,7 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 				if self.window_position
 				else []
 			),
-			*(self._get_extension_args() if self.enable_default_extensions else []),
+			*(self._get_extension_args() if self.enable_default_extensions or self.extension_paths else []),
 		]
 
 		# Proxy flags
@@ -940,6 +1274,10 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 		if self.user_agent:
 			pre_conversion_args.append(f'--user-agent={self.user_agent}')
 
+		# Locale flag, navigator.language / Accept-Language are also overridden per tab by the StealthWatchdog
+		if self.locale:
+			pre_conversion_args.append(f'--lang={self.locale}')
+
 		# Special handling for --disable-features to merge values instead of overwriting
 		# This prevents disable_security=True from breaking extensions by ensuring
 		# both default features (including extension-related) and security features are preserved
@@ -973,8 +1311,14 @@ class BrowserProfile(BrowserConnectArgs, BrowserLaunchPersistentContextArgs, Bro
 		return final_args_list
 
 	def _get_extension_args(self) -> list[str]:
-		"""Get Chrome args for enabling default extensions (ad blocker and cookie handler)."""
-		extension_paths = self._ensure_default_extensions_downloaded()
+		"""Get Chrome args for loading the default extensions (ad blocker and cookie handler) and extension_paths."""
+		extension_paths = self._ensure_default_extensions_downloaded() if self.enable_default_extensions else []
+		for extension_path in self.extension_paths:
+			extension_dir = Path(extension_path).expanduser().resolve()
+			if not (extension_dir / 'manifest.json').is_file():
+				logger.warning(f'Skipping extension {_log_pretty_path(extension_dir)}: no manifest.json in the directory')
+				continue
+			extension_paths.append(str(extension_dir))
 
 		args = [
 			'--enable-extensions',
@@ -1232,6 +1576,26 @@ async function initialize(checkInitialized, magic) {{
 
 				os.unlink(temp_zip.name)
 
+	def apply_device_preset(self) -> None:
+		"""Fill the display and input options from the `device` preset, values set explicitly by the user win."""
+		if not self.device:
+			return
+
+		preset = DEVICE_PRESETS[self.device]
+		explicitly_set = self.model_fields_set
+		if 'viewport' not in explicitly_set:
+			self.viewport = preset.viewport.model_copy()
+		if 'screen' not in explicitly_set:
+			self.screen = preset.screen.model_copy()
+		if 'device_scale_factor' not in explicitly_set:
+			self.device_scale_factor = preset.device_scale_factor
+		if 'user_agent' not in explicitly_set:
+			self.user_agent = preset.user_agent
+		if 'is_mobile' not in explicitly_set:
+			self.is_mobile = preset.is_mobile
+		if 'has_touch' not in explicitly_set:
+			self.has_touch = preset.has_touch
+
 	def detect_display_configuration(self) -> None:
 		"""
 		Detect the system display size and initialize the display-related config defaults:
@@ -0,0 +1,263 @@
+"""
+Live screencast of a browser session, e.g. to show the browser on a dashboard while an agent works.
+
+	async with browser_session.screencast(quality=60, max_width=1280) as screencast:
+		async for frame in screencast.frames():
+			await websocket.send_bytes(frame.data)
+
+The screencast follows the agent's focused tab. mjpeg_handler() serves it as an MJPEG stream that <img> tags play:
+
+	app.router.add_get('/live', mjpeg_handler(screencast))  # <img src="http://localhost:8080/live">
+
+Chrome sends a frame when the page changes, not at a fixed rate, and only encodes screencasts as JPEG or PNG.
+Consumers that fall behind skip the oldest frames instead of holding up the browser.
+"""
+
+import asyncio
+import base64
+import logging
+import time
+from collections.abc import AsyncIterator, Awaitable, Callable
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any, Literal
+
+from aiohttp import web
+
+from browser_use.utils import create_task_with_error_handling
+
+if TYPE_CHECKING:
+	from browser_use.browser.cdp_events import CDPEvent
+	from browser_use.browser.session import BrowserSession
+
+logger = logging.getLogger(__name__)
+
+ScreencastFormat = Literal['jpeg', 'png']
+
+FOCUS_POLL_INTERVAL_S = 0.2
+MJPEG_BOUNDARY = 'screencastframe'
+
+
+@dataclass(frozen=True)
+class ScreencastFrame:
+	"""One encoded frame of a screencast."""
+
+	data: bytes
+	format: ScreencastFormat
+	timestamp: float  # seconds since the epoch, when the browser produced the frame
+	width: int | None = None  # viewport size in CSS pixels, the image may be scaled down to max_width/max_height
+	height: int | None = None
+	target_id: str | None = None
+
+	@property
+	def mime_type(self) -> str:
+		return f'image/{self.format}'
+
+
+class Screencast:
+	"""CDP Page.startScreencast on the agent's focused tab, delivered to any number of frames() consumers."""
+
+	def __init__(
+		self,
+		browser_session: 'BrowserSession',
+		format: ScreencastFormat = 'jpeg',
+		quality: int = 80,
+		max_width: int | None = None,
+		max_height: int | None = None,
+		every_nth_frame: int = 1,
+		max_buffered_frames: int = 2,
+	):
+		if format not in ('jpeg', 'png'):
+			raise ValueError(f"Screencast format must be 'jpeg' or 'png', got {format!r}")
+		if not 0 <= quality <= 100:
+			raise ValueError(f'Screencast quality must be between 0 and 100, got {quality}')
+		if every_nth_frame < 1 or max_buffered_frames < 1:
+			raise ValueError('every_nth_frame and max_buffered_frames must be at least 1')
+		self.browser_session = browser_session
+		self.format: ScreencastFormat = format
+		self.max_buffered_frames = max_buffered_frames
+		self.latest_frame: ScreencastFrame | None = None
+		self._params: dict[str, Any] = {'format': format, 'everyNthFrame': every_nth_frame}
+		if format == 'jpeg':
+			self._params['quality'] = quality
+		if max_width:
+			self._params['maxWidth'] = max_width
+		if max_height:
+			self._params['maxHeight'] = max_height
+		self._queues: set[asyncio.Queue[ScreencastFrame | None]] = set()
+		self._subscription_id: str | None = None
+		self._follow_task: asyncio.Task | None = None
+		self._target_id: str | None = None
+		self._session_id: str | None = None
+
+	@property
+	def is_running(self) -> bool:
+		return self._subscription_id is not None
+
+	async def start(self) -> None:
+		"""Start the screencast on the focused tab, it switches tabs along with the agent until stop()."""
+		if self.is_running:
+			return
+		recording = self.browser_session._recording_watchdog
+		if recording is not None and recording.is_recording:
+			# Chrome runs one screencast per tab, starting a second one would take over the recording's
+			raise RuntimeError('Cannot start a screencast while the session records video (record_video_dir)')
+
+		self._subscription_id = self.browser_session.subscribe_cdp_event('Page.screencastFrame', self._on_frame)
+		try:
+			await self._switch_to_focused_tab()
+		except Exception:
+			await self.stop()
+			raise
+		self._follow_task = create_task_with_error_handling(
+			self._follow_focus(), name='screencast_follow_focus', logger_instance=logger, suppress_exceptions=True
+		)
+
+	async def stop(self) -> None:
+		"""Stop the screencast, frames() iterators end after the frames they already got."""
+		if self._subscription_id is None:
+			return
+		self.browser_session.unsubscribe_cdp_event(self._subscription_id)
+		self._subscription_id = None
+		if self._follow_task is not None:
+			self._follow_task.cancel()
+			try:
+				await self._follow_task
+			except asyncio.CancelledError:
+				pass
+			self._follow_task = None
+		await self._stop_on_current_tab()
+		for queue in list(self._queues):
+			self._put(queue, None)
+
+	async def __aenter__(self) -> 'Screencast':
+		await self.start()
+		return self
+
+	async def __aexit__(self, *exc_info: Any) -> None:
+		await self.stop()
+
+	async def frames(self) -> AsyncIterator[ScreencastFrame]:
+		"""Yield frames as they arrive until stop(). Every iterator gets every frame, so consumers can share a screencast."""
+		queue: asyncio.Queue[ScreencastFrame | None] = asyncio.Queue(maxsize=self.max_buffered_frames)
+		self._queues.add(queue)
+		try:
+			while self.is_running or not queue.empty():
+				frame = await queue.get()
+				if frame is None:
+					return
+				yield frame
+		finally:
+			self._queues.discard(queue)
+
+	async def _follow_focus(self) -> None:
+		while True:
+			await asyncio.sleep(FOCUS_POLL_INTERVAL_S)
+			if self.browser_session.agent_focus_target_id == self._target_id:
+				continue
+			try:
+				await self._switch_to_focused_tab()
+			except Exception as e:
+				# The tab may have closed while switching, the next poll picks up the new focus
+				logger.debug(f'Could not move the screencast to the focused tab: {type(e).__name__}: {e}')
+
+	async def _switch_to_focused_tab(self) -> None:
+		target_id = self.browser_session.agent_focus_target_id
+		if target_id is None:
+			return
+		await self._stop_on_current_tab()
+		cdp_session = await self.browser_session.get_or_create_cdp_session(target_id, focus=False)
+		self._target_id, self._session_id = target_id, cdp_session.session_id
+		await cdp_session.cdp_client.send.Page.startScreencast(
+			params=self._params,  # type: ignore
+			session_id=cdp_session.session_id,
+		)
+		logger.debug(f'📺 Screencasting tab {target_id[-4:]}')
+
+	async def _stop_on_current_tab(self) -> None:
+		session_id = self._session_id
+		self._target_id = self._session_id = None
+		if session_id is None:
+			return
+		try:
+			await self.browser_session.cdp_client.send.Page.stopScreencast(session_id=session_id)
+		except Exception as e:
+			# The tab may already be closed
+			logger.debug(f'Failed to stop screencast on {session_id}: {e}')
+
+	def _on_frame(self, event: 'CDPEvent') -> None:
+		# Frames still in flight from a tab the screencast moved away from
+		if event.session_id is None or event.session_id != self._session_id:
+			return
+		params = event.params
+		create_task_with_error_handling(
+			self._ack_frame(params['sessionId'], event.session_id),
+			name='ack_screencast_frame',
+			logger_instance=logger,
+			suppress_exceptions=True,
+		)
+		metadata = params.get('metadata', {})
+		width, height = metadata.get('deviceWidth'), metadata.get('deviceHeight')
+		frame = ScreencastFrame(
+			data=base64.b64decode(params['data']),
+			format=self.format,
+			timestamp=metadata.get('timestamp') or time.time(),
+			width=int(width) if width else None,
+			height=int(height) if height else None,
+			target_id=event.target_id,
+		)
+		self.latest_frame = frame
+		for queue in list(self._queues):
+			self._put(queue, frame)
+
+	async def _ack_frame(self, frame_session_id: int, session_id: str) -> None:
+		"""Chrome sends the next frame only after the previous one is acknowledged."""
+		try:
+			await self.browser_session.cdp_client.send.Page.screencastFrameAck(
+				params={'sessionId': frame_session_id}, session_id=session_id
+			)
+		except Exception as e:
+			logger.debug(f'Failed to acknowledge screencast frame: {e}')
+
+	@staticmethod
+	def _put(queue: 'asyncio.Queue[ScreencastFrame | None]', item: ScreencastFrame | None) -> None:
+		"""Enqueue without blocking, dropping the oldest frame of a consumer that fell behind."""
+		if queue.full():
+			queue.get_nowait()
+		queue.put_nowait(item)
+
+
+def mjpeg_handler(screencast: Screencast) -> Callable[[web.Request], Awaitable[web.StreamResponse]]:
+	"""An aiohttp handler streaming a JPEG screencast as multipart/x-mixed-replace (MJPEG), which <img> tags play live.
+
+	The screencast must be started separately, clients connected when it stops get the end of the stream.
+	"""
+	if screencast.format != 'jpeg':
+		raise ValueError(f"MJPEG streams need a 'jpeg' screencast, got {screencast.format!r}")
+
+	async def handler(request: web.Request) -> web.StreamResponse:
+		response = web.StreamResponse(
+			headers={
+				'Content-Type': f'multipart/x-mixed-replace; boundary={MJPEG_BOUNDARY}',
+				'Cache-Control': 'no-cache, no-store',
+			}
+		)
+		await response.prepare(request)
+		frames = screencast.frames()
+		try:
+			# A page that doesn't change sends no frames, so new clients start with the last one
+			if screencast.latest_frame is not None:
+				await response.write(_mjpeg_part(screencast.latest_frame))
+			async for frame in frames:
+				await response.write(_mjpeg_part(frame))
+		except ConnectionResetError:
+			logger.debug('MJPEG client disconnected')
+		finally:
+			await frames.aclose()
+		return response
+
+	return handler
+
+
+def _mjpeg_part(frame: ScreencastFrame) -> bytes:
+	header = f'--{MJPEG_BOUNDARY}\r\nContent-Type: {frame.mime_type}\r\nContent-Length: {len(frame.data)}\r\n\r\n'
+	return header.encode() + frame.data + b'\r\n'
@@ -1,9 +1,12 @@
 """Event-driven browser session with backwards compatibility."""
 
 import asyncio
+import fnmatch
+import inspect
 import logging
 import re
 import time
+from collections.abc import Awaitable, Callable
 from dataclasses import replace
 from functools import cached_property
 from pathlib import Path
@@ -14,7 +17,8 @@ from uuid import UUID
 import httpx
 from bubus import BaseEvent, EventBus
 from cdp_use import CDPClient
-from cdp_use.cdp.fetch import AuthRequiredEvent, RequestPausedEvent
+from cdp_use.cdp.fetch import AuthChallengeResponse, AuthRequiredEvent, RequestPausedEvent
+from cdp_use.cdp.fetch.commands import EnableParameters as FetchEnableParameters
 from cdp_use.cdp.network import Cookie
 from cdp_use.cdp.target import SessionID, TargetID
 from cdp_use.cdp.target.commands import CreateTargetParameters
@@ -26,7 +30,8 @@ from browser_use.browser.cloud.cloud import CloudBrowserAuthError, CloudBrowserC
 
 # CDP logging is now handled by setup_logging() in logging_config.py
 # It automatically sets CDP logs to the same level as browser_use logs
-from browser_use.browser.cloud.views import CloudBrowserParams, CreateBrowserRequest, ProxyCountryCode
+from browser_use.browser.cloud.providers import CloudProvider, CloudProviderName, get_cloud_provider
+from browser_use.browser.cloud.views import CloudBrowserParams, CreateBrowserRequest, ProxyCountryCode, RemoteBrowserSession
 
 # Sentinel to distinguish "not passed" from "explicitly None" for proxy params.
 # When a user passes proxy_country_code=None, they mean "disable the proxy".
@@ -48,29 +53,92 @@ from browser_use.browser.events import (
 	FileDownloadedEvent,
 	NavigateToUrlEvent,
 	NavigationCompleteEvent,
+	NavigationResult,
 	NavigationStartedEvent,
 	SwitchTabEvent,
 	TabClosedEvent,
 	TabCreatedEvent,
 )
-from browser_use.browser.profile import BrowserProfile, ProxySettings
-from browser_use.browser.views import BrowserStateSummary, TabInfo
+from browser_use.browser.profile import BrowserProfile, ProxySettings, ViewportSize
+from browser_use.browser.views import (
+	BrowserError,
+	BrowserStateSummary,
+	CapturedNetworkRequest,
+	NavigationError,
+	NavigationTimeoutError,
+	TabInfo,
+	TabRecovery,
+	TargetNotFoundError,
+	URLNotAllowedError,
+)
 from browser_use.dom.views import DOMRect, EnhancedDOMTreeNode, TargetInfo
 from browser_use.observability import observe_debug
 from browser_use.utils import _log_pretty_url, create_task_with_error_handling, is_new_tab_page
 
 if TYPE_CHECKING:
 	from browser_use.actor.page import Page
+	from browser_use.browser.auth_vault import AuthVault, SavedAuth
+	from browser_use.browser.bidi import BiDiSession
+	from browser_use.browser.cdp_events import BindingCall, BindingHandler, CDPEvent, CDPEventHandler, CDPEventSubscription
+	from browser_use.browser.cdp_log import CDPTrafficLogger
 	from browser_use.browser.demo_mode import DemoMode
+	from browser_use.browser.politeness import DomainPoliteness
+	from browser_use.browser.screencast import Screencast, ScreencastFormat
+	from browser_use.browser.session_handle import SessionHandle
 	from browser_use.browser.watchdogs.captcha_watchdog import CaptchaWaitResult
+	from browser_use.metrics import AgentMetrics
 
 DEFAULT_BROWSER_PROFILE = BrowserProfile()
 
+RECENT_TAB_URLS = 5  # previously visited URLs per tab included in the browser state
+
+_DOM_STABLE_BINDING = '__browserUseDomStable'
+# Calls the binding with the token once the document had no mutations for quiet_ms, gives up after timeout_ms
+_DOM_STABLE_JS = """
+(() => {
+	let timer;
+	const done = () => { observer.disconnect(); window.%(binding)s('%(token)s'); };
+	const observer = new MutationObserver(() => { clearTimeout(timer); timer = setTimeout(done, %(quiet_ms)d); });
+	observer.observe(document, {subtree: true, childList: true, attributes: true, characterData: true});
+	timer = setTimeout(done, %(quiet_ms)d);
+	setTimeout(() => { observer.disconnect(); clearTimeout(timer); }, %(timeout_ms)d);
+})()
+"""
+
+# Full-page screenshots of taller pages are captured in strips of this many CSS pixels and stitched together, so no
+# single CDP message carries the whole page
+SCREENSHOT_CHUNK_HEIGHT = 4000
+# Full-page screenshots are cut off at this height in CSS pixels, the stitched image of an endless feed would not fit in memory
+SCREENSHOT_MAX_HEIGHT = 16000
+
+# (cdp_url, browser_context_name) -> browserContextId, see BrowserProfile.browser_context_name
+_NAMED_BROWSER_CONTEXTS: dict[tuple[str, str], str] = {}
+
 _LOGGED_UNIQUE_SESSION_IDS = set()  # track unique session IDs that have been logged to make sure we always assign a unique enough id to new sessions and avoid ambiguity in logs
 red = '\033[91m'
 reset = '\033[0m'
 
 
+async def _await_cdp_event_handler(result: Awaitable[Any]) -> None:
+	await result
+
+
+def _tab_matches(value: str, pattern: str) -> bool:
+	"""Case-insensitive match of a tab URL or title against a glob with * or a plain substring."""
+	value, pattern = value.lower(), pattern.lower()
+	if '*' in pattern:
+		return fnmatch.fnmatchcase(value, pattern)
+	return pattern in value
+
+
+def _url_origin(url: str) -> str | None:
+	"""scheme://host[:port] of http(s) URLs, None for others (about:blank, data:, chrome://)."""
+	parsed = urlparse(url)
+	if parsed.scheme not in ('http', 'https') or not parsed.netloc:
+		return None
+	return f'{parsed.scheme}://{parsed.netloc.lower()}'
+
+
 class Target(BaseModel):
 	"""Browser target (page, iframe, worker) - the actual entity being controlled.
 
@@ -177,15 +245,27 @@ class BrowserSession(BaseModel):
 		use_cloud: bool | None = None,
 		cloud_browser: bool | None = None,  # Backward compatibility alias
 		cloud_browser_params: CloudBrowserParams | None = None,
+		# Third-party hosted browser providers
+		cloud_provider: CloudProviderName | None = None,
+		cloud_provider_api_key: str | None = None,
+		cloud_provider_params: dict[str, Any] | None = None,
 		# Common params that work with cloud
 		id: str | None = None,
 		headers: dict[str, str] | None = None,
 		allowed_domains: list[str] | None = None,
 		prohibited_domains: list[str] | None = None,
+		domain_rate_limit: float | None = None,
+		domain_rate_limit_jitter: float | None = None,
+		max_tabs_per_domain: int | None = None,
+		respect_robots_txt: bool | None = None,
+		reuse_same_origin_tabs: bool | None = None,
+		max_agent_tabs: int | None = None,
 		keep_alive: bool | None = None,
 		minimum_wait_page_load_time: float | None = None,
 		wait_for_network_idle_page_load_time: float | None = None,
 		wait_between_actions: float | None = None,
+		typing_delay: float | None = None,
+		typing_delay_jitter: float | None = None,
 		captcha_solver: bool | None = None,
 		auto_download_pdfs: bool | None = None,
 		cookie_whitelist_domains: list[str] | None = None,
@@ -205,6 +285,8 @@ class BrowserSession(BaseModel):
 		# Core configuration for local
 		id: str | None = None,
 		cdp_url: str | None = None,
+		protocol: Literal['cdp', 'bidi'] | None = None,
+		bidi_url: str | None = None,
 		browser_profile: BrowserProfile | None = None,
 		# Local browser launch params
 		executable_path: str | Path | None = None,
@@ -216,10 +298,25 @@ class BrowserSession(BaseModel):
 		headers: dict[str, str] | None = None,
 		allowed_domains: list[str] | None = None,
 		prohibited_domains: list[str] | None = None,
+		domain_rate_limit: float | None = None,
+		domain_rate_limit_jitter: float | None = None,
+		max_tabs_per_domain: int | None = None,
+		respect_robots_txt: bool | None = None,
+		reuse_same_origin_tabs: bool | None = None,
+		max_agent_tabs: int | None = None,
 		keep_alive: bool | None = None,
+		close_created_tabs: bool | None = None,
+		target_id: str | None = None,
+		target_url_pattern: str | None = None,
+		target_title_pattern: str | None = None,
+		restrict_to_target: bool | None = None,
+		isolated_context: bool | None = None,
+		browser_context_name: str | None = None,
 		minimum_wait_page_load_time: float | None = None,
 		wait_for_network_idle_page_load_time: float | None = None,
 		wait_between_actions: float | None = None,
+		typing_delay: float | None = None,
+		typing_delay_jitter: float | None = None,
 		auto_download_pdfs: bool | None = None,
 		cookie_whitelist_domains: list[str] | None = None,
 		cross_origin_iframes: bool | None = None,
@@ -238,13 +335,19 @@ class BrowserSession(BaseModel):
 		accept_downloads: bool | None = None,
 		permissions: list[str] | None = None,
 		user_agent: str | None = None,
+		locale: str | None = None,
+		timezone_id: str | None = None,
 		screen: dict | None = None,
 		viewport: dict | None = None,
 		no_viewport: bool | None = None,
 		device_scale_factor: float | None = None,
+		is_mobile: bool | None = None,
+		has_touch: bool | None = None,
 		record_har_content: str | None = None,
 		record_har_mode: str | None = None,
 		record_har_path: str | Path | None = None,
+		record_har: bool | None = None,
+		record_har_max_body_size: int | None = None,
 		record_video_dir: str | Path | None = None,
 		record_video_framerate: int | None = None,
 		record_video_size: dict | None = None,
@@ -253,7 +356,12 @@ class BrowserSession(BaseModel):
 		deterministic_rendering: bool | None = None,
 		proxy: ProxySettings | None = None,
 		enable_default_extensions: bool | None = None,
+		extension_paths: list[str | Path] | None = None,
+		prefs: dict[str, Any] | None = None,
+		block_notifications: bool | None = None,
 		captcha_solver: bool | None = None,
+		device: str | None = None,
+		stealth: bool | None = None,
 		window_size: dict | None = None,
 		window_position: dict | None = None,
 		filter_highlight_ids: bool | None = None,
@@ -265,6 +373,8 @@ class BrowserSession(BaseModel):
 		# Core configuration
 		id: str | None = None,
 		cdp_url: str | None = None,
+		protocol: Literal['cdp', 'bidi'] | None = None,
+		bidi_url: str | None = None,
 		is_local: bool = False,
 		browser_profile: BrowserProfile | None = None,
 		# Cloud browser params (don't mix with local browser params)
@@ -293,13 +403,19 @@ class BrowserSession(BaseModel):
 		accept_downloads: bool | None = None,
 		permissions: list[str] | None = None,
 		user_agent: str | None = None,
+		locale: str | None = None,
+		timezone_id: str | None = None,
 		screen: dict | None = None,
 		viewport: dict | None = None,
 		no_viewport: bool | None = None,
 		device_scale_factor: float | None = None,
+		is_mobile: bool | None = None,
+		has_touch: bool | None = None,
 		record_har_content: str | None = None,
 		record_har_mode: str | None = None,
 		record_har_path: str | Path | None = None,
+		record_har: bool | None = None,
+		record_har_max_body_size: int | None = None,
 		record_video_dir: str | Path | None = None,
 		record_video_framerate: int | None = None,
 		record_video_size: dict | None = None,
@@ -312,20 +428,44 @@ class BrowserSession(BaseModel):
 		use_cloud: bool | None = None,
 		cloud_browser: bool | None = None,  # Backward compatibility alias
 		cloud_browser_params: CloudBrowserParams | None = None,
+		## Third-party hosted browser providers
+		cloud_provider: CloudProviderName | None = None,
+		cloud_provider_api_key: str | None = None,
+		cloud_provider_params: dict[str, Any] | None = None,
 		## Other params
 		disable_security: bool | None = None,
 		deterministic_rendering: bool | None = None,
 		allowed_domains: list[str] | None = None,
 		prohibited_domains: list[str] | None = None,
+		domain_rate_limit: float | None = None,
+		domain_rate_limit_jitter: float | None = None,
+		max_tabs_per_domain: int | None = None,
+		respect_robots_txt: bool | None = None,
+		reuse_same_origin_tabs: bool | None = None,
+		max_agent_tabs: int | None = None,
 		keep_alive: bool | None = None,
+		close_created_tabs: bool | None = None,
+		target_id: str | None = None,
+		target_url_pattern: str | None = None,
+		target_title_pattern: str | None = None,
+		restrict_to_target: bool | None = None,
+		isolated_context: bool | None = None,
+		browser_context_name: str | None = None,
 		proxy: ProxySettings | None = None,
 		enable_default_extensions: bool | None = None,
+		extension_paths: list[str | Path] | None = None,
+		prefs: dict[str, Any] | None = None,
+		block_notifications: bool | None = None,
 		captcha_solver: bool | None = None,
+		device: str | None = None,
+		stealth: bool | None = None,
 		window_size: dict | None = None,
 		window_position: dict | None = None,
 		minimum_wait_page_load_time: float | None = None,
 		wait_for_network_idle_page_load_time: float | None = None,
 		wait_between_actions: float | None = None,
+		typing_delay: float | None = None,
+		typing_delay_jitter: float | None = None,
 		filter_highlight_ids: bool | None = None,
 		auto_download_pdfs: bool | None = None,
 		profile_directory: str | None = None,
@@ -400,8 +540,8 @@ class BrowserSession(BaseModel):
 			profile_kwargs['is_local'] = True
 		# Only set is_local=True when cdp_url is missing if we're not using cloud browser
 		# (cloud browser will provide cdp_url later)
-		use_cloud = profile_kwargs.get('use_cloud') or profile_kwargs.get('cloud_browser')
-		if not cdp_url and not use_cloud:
+		use_cloud = profile_kwargs.get('use_cloud') or profile_kwargs.get('cloud_browser') or profile_kwargs.get('cloud_provider')
+		if not cdp_url and not use_cloud and protocol != 'bidi':
 			profile_kwargs['is_local'] = True
 
 		# Create browser profile from direct parameters or use provided one
@@ -433,8 +573,9 @@ class BrowserSession(BaseModel):
 		description='Target size (width, height) to resize screenshots before sending to LLM. Coordinates from LLM will be scaled back to original viewport size.',
 	)
 
-	# Cache of original viewport size for coordinate conversion (set when browser state is captured)
+	# Viewport size in CSS pixels and device pixel ratio for coordinate conversion (set when browser state is captured)
 	_original_viewport_size: tuple[int, int] | None = PrivateAttr(default=None)
+	_device_pixel_ratio: float = PrivateAttr(default=1.0)
 
 	@classmethod
 	def from_system_chrome(cls, profile_directory: str | None = None, **kwargs: Any) -> Self:
@@ -525,6 +666,15 @@ class BrowserSession(BaseModel):
 			return await self._captcha_watchdog.wait_if_captcha_solving(timeout=timeout)
 		return None
 
+	async def recover_unhealthy_tab(self) -> TabRecovery | None:
+		"""Reload or replace the agent's tab if it crashed, and navigate back if it was lost to a blank tab.
+
+		Returns what was done, or None if the tab is healthy or recover_crashed_tabs is off.
+		"""
+		if self._tab_health_watchdog is not None:
+			return await self._tab_health_watchdog.recover()
+		return None
+
 	@property
 	def is_reconnecting(self) -> bool:
 		"""Whether a WebSocket reconnection attempt is currently in progress."""
@@ -554,6 +704,9 @@ class BrowserSession(BaseModel):
 
 	# Mutable private state shared between watchdogs
 	_cdp_client_root: CDPClient | None = PrivateAttr(default=None)
+	_bidi_session: 'BiDiSession | None' = PrivateAttr(default=None)  # protocol='bidi', see start()
+	_cdp_traffic_logger: 'CDPTrafficLogger | None' = PrivateAttr(default=None)  # see BrowserProfile.cdp_log_dir
+	_politeness: 'DomainPoliteness | None' = PrivateAttr(default=None)  # see BrowserProfile.domain_rate_limit
 	_connection_lock: Any = PrivateAttr(default=None)  # asyncio.Lock for preventing concurrent connections
 
 	# PUBLIC: SessionManager instance (OWNS all targets and sessions)
@@ -562,9 +715,12 @@ class BrowserSession(BaseModel):
 	_cached_browser_state_summary: Any = PrivateAttr(default=None)
 	_cached_selector_map: dict[int, EnhancedDOMTreeNode] = PrivateAttr(default_factory=dict)
 	_cached_selector_indices: dict[tuple[str, int], int] = PrivateAttr(default_factory=dict)
+	_text_match_highlighted: bool = PrivateAttr(default=False)  # find_text outlined a match, removed after the next screenshot
+	_metrics: 'AgentMetrics | None' = PrivateAttr(default=None)  # Prometheus metrics, see attach_metrics()
 	_consecutive_state_refresh_timeouts: int = PrivateAttr(default=0)
 	_downloaded_files: list[str] = PrivateAttr(default_factory=list)  # Track files downloaded during this session
 	_closed_popup_messages: list[str] = PrivateAttr(default_factory=list)  # Store messages from auto-closed JavaScript dialogs
+	_page_errors: list[tuple[str, str]] = PrivateAttr(default_factory=list)  # (target_id, message) JS and network errors
 
 	# Watchdogs
 	_crash_watchdog: Any | None = PrivateAttr(default=None)
@@ -578,11 +734,37 @@ class BrowserSession(BaseModel):
 	_screenshot_watchdog: Any | None = PrivateAttr(default=None)
 	_permissions_watchdog: Any | None = PrivateAttr(default=None)
 	_recording_watchdog: Any | None = PrivateAttr(default=None)
+	_har_recording_watchdog: Any | None = PrivateAttr(default=None)
 	_captcha_watchdog: Any | None = PrivateAttr(default=None)
+	_page_errors_watchdog: Any | None = PrivateAttr(default=None)
+	_tab_health_watchdog: Any | None = PrivateAttr(default=None)
+	_stealth_watchdog: Any | None = PrivateAttr(default=None)
+	_network_capture_watchdog: Any | None = PrivateAttr(default=None)
+	_consent_watchdog: Any | None = PrivateAttr(default=None)
 	_watchdogs_attached: bool = PrivateAttr(default=False)
 
 	_cloud_browser_client: CloudBrowserClient = PrivateAttr(default_factory=lambda: CloudBrowserClient())
+	_cloud_provider: CloudProvider | None = PrivateAttr(default=None)
+	_remote_browser_session: RemoteBrowserSession | None = PrivateAttr(default=None)
+	_initial_target_ids: set[TargetID] | None = PrivateAttr(default=None)  # page targets already open when we connected
+	_pinned_target_id: TargetID | None = PrivateAttr(default=None)  # the only tab the agent may use, see restrict_to_target
+	_tab_last_used: dict[TargetID, float] = PrivateAttr(default_factory=dict)  # monotonic time of last focus, see max_agent_tabs
+	_browser_context_id: str | None = PrivateAttr(default=None)  # see isolated_context / browser_context_name
 	_demo_mode: 'DemoMode | None' = PrivateAttr(default=None)
+	_consumer_lock: asyncio.Lock = PrivateAttr(default_factory=asyncio.Lock)  # held by the SessionHandle in use
+	_last_handle: 'SessionHandle | None' = PrivateAttr(default=None)
+	_init_scripts: dict[str, str] = PrivateAttr(default_factory=dict)  # init script id -> source, injected into every page
+	_init_script_identifiers: dict[str, dict[TargetID, str]] = PrivateAttr(default_factory=dict)  # id -> CDP identifier per tab
+	_cdp_event_subscriptions: dict[str, 'CDPEventSubscription'] = PrivateAttr(default_factory=dict)  # see subscribe_cdp_event
+	_bindings: dict[str, 'BindingHandler'] = PrivateAttr(default_factory=dict)  # binding name -> handler, see add_binding
+	_binding_target_ids: dict[str, set[TargetID]] = PrivateAttr(default_factory=dict)  # binding name -> tabs it was added to
+	_binding_subscription_id: str | None = PrivateAttr(default=None)  # the Runtime.bindingCalled subscription
+	_dom_stable_waiters: dict[str, asyncio.Event] = PrivateAttr(default_factory=dict)  # see wait_for_dom_stable
+	_http_credentials: dict[str, tuple[str, str]] = PrivateAttr(default_factory=dict)  # origin pattern -> (username, password)
+	_answered_auth_requests: set[str] = PrivateAttr(default_factory=set)  # Fetch request ids we already sent credentials for
+	_fetch_subscription_ids: list[str] = PrivateAttr(default_factory=list)  # the Fetch.authRequired/requestPaused subscriptions
+	_viewport_override: ViewportSize | None = PrivateAttr(default=None)  # see set_viewport, replaces BrowserProfile.viewport
+	_viewport_follows_window: bool = PrivateAttr(default=False)  # see set_window_bounds, no emulated viewport
 
 	# WebSocket reconnection state
 	# Max wait = attempts * timeout_per_attempt + sum(delays) + small buffer
@@ -660,11 +842,23 @@ class BrowserSession(BaseModel):
 				self.logger.debug(f'Error closing CDP client during reset: {e}')
 
 		self._cdp_client_root = None  # type: ignore
+		if self._cdp_traffic_logger is not None:
+			self._cdp_traffic_logger.close()
+			self._cdp_traffic_logger = None
 		self._cached_browser_state_summary = None
 		self._cached_selector_map.clear()
 		self._cached_selector_indices.clear()
+		self._text_match_highlighted = False
 		self._consecutive_state_refresh_timeouts = 0
 		self._downloaded_files.clear()
+		self._page_errors.clear()
+		self._initial_target_ids = None
+		self._pinned_target_id = None
+		self._tab_last_used.clear()
+		# Init scripts are kept and injected again on the next start, their CDP identifiers died with the browser
+		self._init_script_identifiers = {script_id: {} for script_id in self._init_scripts}
+		self._binding_target_ids = {name: set() for name in self._bindings}
+		self._last_handle = None
 
 		self.agent_focus_target_id = None
 		if self.is_local:
@@ -681,7 +875,13 @@ class BrowserSession(BaseModel):
 		self._screenshot_watchdog = None
 		self._permissions_watchdog = None
 		self._recording_watchdog = None
+		self._har_recording_watchdog = None
 		self._captcha_watchdog = None
+		self._page_errors_watchdog = None
+		self._tab_health_watchdog = None
+		self._stealth_watchdog = None
+		self._network_capture_watchdog = None
+		self._consent_watchdog = None
 		self._watchdogs_attached = False
 		if self._demo_mode:
 			self._demo_mode.reset()
@@ -723,6 +923,9 @@ class BrowserSession(BaseModel):
 	@observe_debug(ignore_input=True, ignore_output=True, name='browser_session_start')
 	async def start(self) -> None:
 		"""Start the browser session."""
+		if self.browser_profile.protocol == 'bidi':
+			await self._start_bidi()
+			return
 		start_event = self.event_bus.dispatch(BrowserStartEvent())
 		await start_event
 		# Ensure any exceptions from the event handler are propagated
@@ -730,6 +933,9 @@ class BrowserSession(BaseModel):
 
 	async def kill(self) -> None:
 		"""Kill the browser session and reset all state."""
+		if self.browser_profile.protocol == 'bidi':
+			await self._stop_bidi()
+			return
 		self._intentional_stop = True
 		self.logger.debug('🛑 kill() called - stopping browser with force=True and resetting state')
 
@@ -748,12 +954,20 @@ class BrowserSession(BaseModel):
 		# Create fresh event bus
 		self.event_bus = ResilientEventBus()
 
-	async def stop(self) -> None:
+	async def stop(self, close_tabs: bool | None = None, close_browser: bool = False) -> None:
 		"""Stop the browser session without killing the browser process.
 
 		This clears event buses and cached state but keeps the browser alive.
 		Useful when you want to clean up resources but plan to reconnect later.
+
+		When connected to an existing browser via cdp_url:
+		- close_tabs: close the tabs and popups opened during the session (default: BrowserProfile.close_created_tabs,
+		  with keep_alive=True tabs are only closed when close_tabs=True is passed explicitly)
+		- close_browser: shut down the remote browser itself
 		"""
+		if self.browser_profile.protocol == 'bidi':
+			await self._stop_bidi()
+			return
 		self._intentional_stop = True
 		self.logger.debug('⏸️  stop() called - stopping browser gracefully (force=False) and resetting state')
 
@@ -764,7 +978,7 @@ class BrowserSession(BaseModel):
 		await save_event
 
 		# Now dispatch BrowserStopEvent to notify watchdogs
-		await self.event_bus.dispatch(BrowserStopEvent(force=False))
+		await self.event_bus.dispatch(BrowserStopEvent(force=False, close_tabs=close_tabs, close_browser=close_browser))
 
 		# Stop the event bus
 		await self.event_bus.stop(clear=True, timeout=5)
@@ -777,6 +991,34 @@ class BrowserSession(BaseModel):
 		"""Alias for stop()."""
 		await self.stop()
 
+	@property
+	def bidi(self) -> 'BiDiSession':
+		"""The WebDriver BiDi session of a protocol='bidi' browser, its pages have the same methods as the CDP actors."""
+		assert self._bidi_session is not None, "No BiDi session - use protocol='bidi' and start() the browser session first"
+		return self._bidi_session
+
+	async def _start_bidi(self) -> None:
+		"""Connect to bidi_url over WebDriver BiDi instead of launching or connecting a browser over CDP."""
+		if self._bidi_session is not None:
+			return
+		from browser_use.browser.bidi import BiDiClient, BiDiSession
+
+		assert self.browser_profile.bidi_url is not None
+		client = BiDiClient(
+			self.browser_profile.bidi_url,
+			additional_headers=self.browser_profile.headers or None,
+			max_message_size=self.browser_profile.cdp_max_message_size,
+		)
+		bidi_session = BiDiSession(client)
+		await bidi_session.start()
+		self._bidi_session = bidi_session
+		self.logger.debug(f'🌎 Connected over WebDriver BiDi to {_log_pretty_url(self.browser_profile.bidi_url)}')
+
+	async def _stop_bidi(self) -> None:
+		if self._bidi_session is not None:
+			await self._bidi_session.stop()
+			self._bidi_session = None
+
 	@observe_debug(ignore_input=True, ignore_output=True, name='browser_start_event_handler')
 	async def on_BrowserStartEvent(self, event: BrowserStartEvent) -> dict[str, str]:
 		"""Handle browser start request.
@@ -795,7 +1037,10 @@ class BrowserSession(BaseModel):
 		try:
 			# If no CDP URL, launch local browser or cloud browser
 			if not self.cdp_url:
-				if self.browser_profile.use_cloud or self.browser_profile.cloud_browser_params is not None:
+				if self.browser_profile.cloud_provider:
+					# Use a third-party hosted browser provider
+					await self._create_remote_browser_session()
+				elif self.browser_profile.use_cloud or self.browser_profile.cloud_browser_params is not None:
 					# Use cloud browser service
 					try:
 						# Use cloud_browser_params if provided, otherwise create empty request
@@ -897,12 +1142,12 @@ class BrowserSession(BaseModel):
 				)
 			raise
 
-	async def on_NavigateToUrlEvent(self, event: NavigateToUrlEvent) -> None:
+	async def on_NavigateToUrlEvent(self, event: NavigateToUrlEvent) -> NavigationResult | None:
 		"""Handle navigation requests - core browser functionality."""
 		self.logger.debug(f'[on_NavigateToUrlEvent] Received NavigateToUrlEvent: url={event.url}, new_tab={event.new_tab}')
 		if not self.agent_focus_target_id:
 			self.logger.warning('Cannot navigate - browser not connected')
-			return
+			return None
 
 		target_id = None
 		current_target_id = self.agent_focus_target_id
@@ -912,6 +1157,23 @@ class BrowserSession(BaseModel):
 		if event.new_tab and is_new_tab_page(current_target.url):
 			self.logger.debug(f'[on_NavigateToUrlEvent] Already on blank tab ({current_target.url}), reusing')
 			event.new_tab = False
+		if event.new_tab and self._pinned_target_id:
+			self.logger.info(f'📄 Agent is restricted to tab #{self._pinned_target_id[-4:]}, opening {event.url} there instead')
+			event.new_tab = False
+
+		reused_target_id = None
+		if event.new_tab and self.browser_profile.reuse_same_origin_tabs:
+			reused_target_id = self._find_same_origin_tab(event.url)
+			if reused_target_id is not None:
+				self.logger.info(f'♻️ Tab #{reused_target_id[-4:]} is already open on this site, navigating it to {event.url}')
+				event.new_tab = False
+				target_id = reused_target_id
+
+		# Politeness limits of scraping jobs, raise before anything is opened
+		self._check_domain_tab_limit(event.url, None if event.new_tab else target_id or current_target_id)
+		politeness = self._get_politeness()
+		if politeness is not None:
+			await politeness.before_navigation(event.url)
 
 		try:
 			# Find or create target for navigation
@@ -959,18 +1221,27 @@ class BrowserSession(BaseModel):
 			assert self.agent_focus_target_id is not None and self.agent_focus_target_id == target_id, (
 				'Agent focus not updated to new target_id after SwitchTabEvent should have switched to it'
 			)
+			closed_tab_ids = await self._close_least_recently_used_tabs(target_id) if event.new_tab else []
 
 			# Dispatch navigation started
 			await self.event_bus.dispatch(NavigationStartedEvent(target_id=target_id, url=event.url))
 
-			# Navigate to URL with proper lifecycle waiting
-			loading_status = await self._navigate_and_wait(
-				event.url,
-				target_id,
-				timeout=event.timeout_ms / 1000 if event.timeout_ms is not None else None,
-				wait_until=event.wait_until,
-				nav_timeout=event.event_timeout,
+			# Navigate to URL with proper lifecycle waiting, recording the responses of the main document
+			navigation = NavigationResult(
+				url=event.url, final_url=event.url, reused_tab_id=reused_target_id, closed_tab_ids=closed_tab_ids
 			)
+			subscription_ids = self._track_navigation_response(target_id, navigation)
+			try:
+				navigation.loading_status = await self._navigate_and_wait(
+					event.url,
+					target_id,
+					timeout=event.timeout_ms / 1000 if event.timeout_ms is not None else None,
+					wait_until=event.wait_until,
+					nav_timeout=event.event_timeout,
+				)
+			finally:
+				for subscription_id in subscription_ids:
+					self.unsubscribe_cdp_event(subscription_id)
 
 			# Close any extension options pages that might have opened
 			await self._close_extension_options_pages()
@@ -981,8 +1252,8 @@ class BrowserSession(BaseModel):
 				NavigationCompleteEvent(
 					target_id=target_id,
 					url=event.url,
-					status=None,  # CDP doesn't provide status directly
-					loading_status=loading_status,  # non-None when readiness timed out
+					status=navigation.status,
+					loading_status=navigation.loading_status,  # non-None when readiness timed out
 				)
 			)
 			await self.event_bus.dispatch(AgentFocusChangedEvent(target_id=target_id, url=event.url))
@@ -993,6 +1264,7 @@ class BrowserSession(BaseModel):
 			# - Dialog handling (dialog_watchdog)
 			# - Download handling (downloads_watchdog)
 			# - DOM rebuilding (dom_watchdog)
+			return navigation
 
 		except Exception as e:
 			self.logger.error(f'Navigation failed: {type(e).__name__}: {e}')
@@ -1008,6 +1280,38 @@ class BrowserSession(BaseModel):
 				await self.event_bus.dispatch(AgentFocusChangedEvent(target_id=target_id, url=event.url))
 			raise
 
+	def _track_navigation_response(self, target_id: TargetID, navigation: NavigationResult) -> list[str]:
+		"""Fill in the status, redirects and final URL of a navigation from the Network events of the tab's main document.
+
+		Returns the ids of the CDP event subscriptions, to unsubscribe once the navigation is done.
+		"""
+		document_request_ids: set[str] = set()
+
+		def on_event(event: 'CDPEvent') -> None:
+			params = event.params
+			if event.method == 'Network.requestWillBeSent':
+				# The main frame of a tab has the tab's target id
+				if params.get('type') != 'Document' or params.get('frameId') != target_id:
+					return
+				if redirect := params.get('redirectResponse'):
+					navigation.redirect_chain.append(redirect.get('url') or navigation.final_url)
+				elif document_request_ids:
+					# A new document request without a redirect response, e.g. a meta refresh or location.href redirect
+					navigation.redirect_chain.append(navigation.final_url)
+				document_request_ids.add(params['requestId'])
+				navigation.final_url = params.get('request', {}).get('url') or navigation.final_url
+				navigation.status, navigation.status_text = None, ''
+			elif event.method == 'Network.responseReceived' and params.get('requestId') in document_request_ids:
+				response = params.get('response', {})
+				navigation.final_url = response.get('url') or navigation.final_url
+				# file: and data: documents report status 0
+				navigation.status, navigation.status_text = response.get('status') or None, response.get('statusText', '')
+
+		return [
+			self.subscribe_cdp_event(method, on_event, target_id=target_id)
+			for method in ('Network.requestWillBeSent', 'Network.responseReceived')
+		]
+
 	async def _navigate_and_wait(
 		self,
 		url: str,
@@ -1056,10 +1360,12 @@ class BrowserSession(BaseModel):
 			)
 		except TimeoutError:
 			duration_ms = (asyncio.get_event_loop().time() - nav_start_time) * 1000
-			raise RuntimeError(f'Page.navigate() timed out after {nav_timeout}s ({duration_ms:.0f}ms) for {url}')
+			raise NavigationTimeoutError(
+				f'Page.navigate() timed out after {nav_timeout}s ({duration_ms:.0f}ms) for {url}', details={'url': url}
+			)
 
 		if nav_result.get('errorText'):
-			raise RuntimeError(f'Navigation failed: {nav_result["errorText"]}')
+			raise NavigationError(f'Navigation failed: {nav_result["errorText"]}', details={'url': url})
 
 		if wait_until == 'commit':
 			duration_ms = (asyncio.get_event_loop().time() - nav_start_time) * 1000
@@ -1146,7 +1452,7 @@ class BrowserSession(BaseModel):
 			else:
 				# No pages open at all, create a new one (handles switching to it automatically)
 				assert self._cdp_client_root is not None, 'CDP client root not initialized - browser may not be connected yet'
-				new_target = await self._cdp_client_root.send.Target.createTarget(params={'url': 'about:blank'})
+				new_target = await self._cdp_client_root.send.Target.createTarget(params=self._new_target_params('about:blank'))
 				target_id = new_target['targetId']
 				# Don't await, these may circularly trigger SwitchTabEvent and could deadlock, dispatch to enqueue and return
 				self.event_bus.dispatch(TabCreatedEvent(url='about:blank', target_id=target_id))
@@ -1155,6 +1461,10 @@ class BrowserSession(BaseModel):
 
 		# Switch to the target
 		assert event.target_id is not None, 'target_id must be set at this point'
+		if self._pinned_target_id and event.target_id != self._pinned_target_id:
+			raise BrowserError(
+				f'Cannot switch to tab #{event.target_id[-4:]}: the agent is restricted to tab #{self._pinned_target_id[-4:]}'
+			)
 		# Ensure session exists and update agent focus (only for page/tab targets)
 		cdp_session = await self.get_or_create_cdp_session(target_id=event.target_id, focus=True)
 
@@ -1180,6 +1490,7 @@ class BrowserSession(BaseModel):
 		try:
 			# Dispatch tab closed event
 			await self.event_bus.dispatch(TabClosedEvent(target_id=event.target_id))
+			self._tab_last_used.pop(event.target_id, None)
 
 			# Try to close the target, but don't fail if it's already closed
 			try:
@@ -1191,22 +1502,41 @@ class BrowserSession(BaseModel):
 			self.logger.warning(f'Error during tab close cleanup: {e}')
 
 	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
-		"""Handle tab creation - apply viewport settings to new tab."""
+		"""Handle tab creation - apply viewport settings and init scripts to new tab."""
 		# Note: Tab switching prevention is handled by the Force Background Tab extension
 		# The extension automatically keeps focus on the current tab when new tabs are created
 
+		for script_id in list(self._init_scripts):
+			try:
+				await self._inject_init_script(script_id, event.target_id)
+			except Exception as e:
+				self.logger.warning(f'Failed to add init script {script_id[-4:]} to new tab {event.target_id[-8:]}: {e}')
+
+		for name in list(self._bindings):
+			try:
+				await self._add_binding_to_target(name, event.target_id)
+			except Exception as e:
+				self.logger.warning(f'Failed to add binding {name!r} to new tab {event.target_id[-8:]}: {e}')
+
 		# Apply viewport settings if configured
-		if self.browser_profile.viewport and not self.browser_profile.no_viewport:
+		if viewport := self._emulated_viewport():
 			try:
-				viewport_width = self.browser_profile.viewport.width
-				viewport_height = self.browser_profile.viewport.height
+				viewport_width = viewport.width
+				viewport_height = viewport.height
 				device_scale_factor = self.browser_profile.device_scale_factor or 1.0
 
 				self.logger.info(
 					f'Setting viewport to {viewport_width}x{viewport_height} with device scale factor {device_scale_factor} whereas original device scale factor was {self.browser_profile.device_scale_factor}'
 				)
 				# Use the helper method with the new tab's target_id
-				await self._cdp_set_viewport(viewport_width, viewport_height, device_scale_factor, target_id=event.target_id)
+				await self._cdp_set_viewport(
+					viewport_width,
+					viewport_height,
+					device_scale_factor,
+					mobile=self.browser_profile.is_mobile,
+					has_touch=self.browser_profile.has_touch,
+					target_id=event.target_id,
+				)
 
 				self.logger.debug(f'Applied viewport {viewport_width}x{viewport_height} to tab {event.target_id[-8:]}')
 			except Exception as e:
@@ -1241,22 +1571,37 @@ class BrowserSession(BaseModel):
 
 		# Update agent focus if a specific target_id is provided (only for page/tab targets)
 		if event.target_id:
+			self._tab_last_used[event.target_id] = time.monotonic()
 			# Ensure session exists and update agent focus (validates target_type internally)
 			await self.get_or_create_cdp_session(target_id=event.target_id, focus=True)
 
 			# Apply viewport settings to the newly focused tab
-			if self.browser_profile.viewport and not self.browser_profile.no_viewport:
+			if viewport := self._emulated_viewport():
 				try:
-					viewport_width = self.browser_profile.viewport.width
-					viewport_height = self.browser_profile.viewport.height
+					viewport_width = viewport.width
+					viewport_height = viewport.height
 					device_scale_factor = self.browser_profile.device_scale_factor or 1.0
 
 					# Use the helper method with the current tab's target_id
-					await self._cdp_set_viewport(viewport_width, viewport_height, device_scale_factor, target_id=event.target_id)
+					await self._cdp_set_viewport(
+						viewport_width,
+						viewport_height,
+						device_scale_factor,
+						mobile=self.browser_profile.is_mobile,
+						has_touch=self.browser_profile.has_touch,
+						target_id=event.target_id,
+					)
 
 					self.logger.debug(f'Applied viewport {viewport_width}x{viewport_height} to tab {event.target_id[-8:]}')
 				except Exception as e:
 					self.logger.warning(f'Failed to set viewport for tab {event.target_id[-8:]}: {e}')
+			elif self._viewport_follows_window:
+				# The tab may still have the viewport emulated before set_window_bounds
+				try:
+					cdp_session = await self.get_or_create_cdp_session(event.target_id, focus=False)
+					await cdp_session.cdp_client.send.Emulation.clearDeviceMetricsOverride(session_id=cdp_session.session_id)
+				except Exception as e:
+					self.logger.debug(f'Failed to clear viewport for tab {event.target_id[-8:]}: {e}')
 		else:
 			raise RuntimeError('AgentFocusChangedEvent received with no target_id for newly focused tab')
 
@@ -1280,12 +1625,91 @@ class BrowserSession(BaseModel):
 		match = re.match(r'^([0-9a-fA-F-]{36})\.cdp\d+\.browser-use\.com$', host)
 		return match.group(1) if match else None
 
+	async def _create_remote_browser_session(self) -> None:
+		"""Create a browser on the configured cloud_provider and point this session's cdp_url at it."""
+		assert self.browser_profile.cloud_provider is not None
+		self._cloud_provider = get_cloud_provider(
+			self.browser_profile.cloud_provider,
+			api_key=self.browser_profile.cloud_provider_api_key,
+			params=self.browser_profile.cloud_provider_params,
+		)
+		self.logger.info(f'🌤️ Creating {self.browser_profile.cloud_provider} browser session...')
+		remote_session = await self._cloud_provider.create_session(keep_alive=bool(self.browser_profile.keep_alive))
+		self._remote_browser_session = remote_session
+
+		self.browser_profile.cdp_url = remote_session.cdp_url
+		self.browser_profile.is_local = False
+		if remote_session.headers:
+			self.browser_profile.headers = {**remote_session.headers, **(self.browser_profile.headers or {})}
+		self.logger.info(f'🌤️ Connected to {remote_session.provider} browser session {remote_session.id}')
+		if remote_session.live_url:
+			self.logger.info(f'\033[36m🔗 Live URL: {remote_session.live_url}\033[0m')
+
+	async def _stop_remote_browser_session(self) -> None:
+		"""Release the cloud_provider browser session created by _create_remote_browser_session()."""
+		remote_session, provider = self._remote_browser_session, self._cloud_provider
+		if remote_session is None or provider is None:
+			return
+		self._remote_browser_session = None
+		self._cloud_provider = None
+		try:
+			await provider.stop_session(remote_session.id)
+			self.logger.info(f'🌤️ Released {remote_session.provider} browser session {remote_session.id}')
+		except Exception as e:
+			self.logger.debug(f'Failed to release {remote_session.provider} browser session {remote_session.id}: {e}')
+		finally:
+			await provider.close()
+			# The cdp_url points at the released session, a restart must create a new one
+			if self.browser_profile.cdp_url == remote_session.cdp_url:
+				self.browser_profile.cdp_url = None
+
+	def get_created_target_ids(self) -> list[TargetID]:
+		"""Page targets opened during this session (new tabs, popups), i.e. not already open when we connected."""
+		if self.session_manager is None or self._initial_target_ids is None:
+			return []
+		return [
+			target.target_id
+			for target in self.session_manager.get_all_page_targets()
+			if target.target_id not in self._initial_target_ids
+		]
+
+	async def _close_created_targets(self) -> None:
+		"""Close the tabs opened during this session, leaving the browser's own tabs untouched."""
+		created_target_ids = self.get_created_target_ids()
+		if not created_target_ids or self._cdp_client_root is None:
+			return
+
+		# Closing the last tab would make the browser exit, keep one open if every tab is ours
+		if self.session_manager and len(created_target_ids) == len(self.session_manager.get_all_page_targets()):
+			created_target_ids = created_target_ids[1:]
+
+		for target_id in created_target_ids:
+			try:
+				await self._cdp_client_root.send.Target.closeTarget(params={'targetId': target_id})
+			except Exception as e:
+				self.logger.debug(f'Failed to close tab {target_id[-4:]} on stop: {e}')
+		if created_target_ids:
+			self.logger.info(f'🧹 Closed {len(created_target_ids)} tab(s) opened during the session')
+
+	async def _close_remote_browser(self) -> None:
+		"""Shut down the browser we are connected to over CDP (used for stop(close_browser=True))."""
+		if self._cdp_client_root is None:
+			return
+		try:
+			await self._cdp_client_root.send.Browser.close()
+			self.logger.info('🛑 Closed remote browser')
+		except Exception as e:
+			# The websocket usually drops before the response arrives
+			self.logger.debug(f'Browser.close did not return cleanly: {e}')
+
 	async def on_BrowserStopEvent(self, event: BrowserStopEvent) -> None:
 		"""Handle browser stop request."""
 
 		try:
 			# Check if we should keep the browser alive
-			if self.browser_profile.keep_alive and not event.force:
+			if self.browser_profile.keep_alive and not event.force and not event.close_browser:
+				if event.close_tabs:
+					await self._close_created_targets()
 				self.event_bus.dispatch(BrowserStoppedEvent(reason='Kept alive due to keep_alive=True'))
 				return
 
@@ -1293,6 +1717,16 @@ class BrowserSession(BaseModel):
 			# 1) native use_cloud sessions (current_session_id set by create_browser)
 			# 2) reconnected cdp_url sessions (derive UUID from host)
 			cloud_session_id = self._cloud_browser_client.current_session_id or self._cloud_session_id_from_cdp_url()
+
+			# Clean up what we opened in a browser we only connected to, it keeps running after we disconnect.
+			# Cloud / hosted provider browsers are released as a whole instead.
+			if not self.is_local and not cloud_session_id and not self._remote_browser_session and self.is_cdp_connected:
+				close_tabs = event.close_tabs if event.close_tabs is not None else self.browser_profile.close_created_tabs
+				if event.close_browser:
+					await self._close_remote_browser()
+				elif close_tabs:
+					await self._close_created_targets()
+
 			if cloud_session_id:
 				try:
 					await self._cloud_browser_client.stop_browser(cloud_session_id)
@@ -1306,6 +1740,11 @@ class BrowserSession(BaseModel):
 					except Exception:
 						pass
 
+			# Release the third-party hosted browser session, if this session created one
+			await self._stop_remote_browser_session()
+
+			await self._dispose_browser_context()
+
 			# Clear CDP session cache before stopping
 			self.logger.info(
 				f'📢 on_BrowserStopEvent - Calling reset() (force={event.force}, keep_alive={self.browser_profile.keep_alive})'
@@ -1339,10 +1778,7 @@ class BrowserSession(BaseModel):
 
 	async def new_page(self, url: str | None = None) -> 'Page':
 		"""Create a new page (tab)."""
-		from cdp_use.cdp.target.commands import CreateTargetParameters
-
-		params: CreateTargetParameters = {'url': url or 'about:blank'}
-		result = await self.cdp_client.send.Target.createTarget(params)
+		result = await self.cdp_client.send.Target.createTarget(self._new_target_params(url or 'about:blank'))
 
 		target_id = result['targetId']
 
@@ -1383,6 +1819,203 @@ class BrowserSession(BaseModel):
 
 		return targets
 
+	def create_handle(self, name: str | None = None) -> 'SessionHandle':
+		"""Create a handle for one consumer (agent, background poller, ...) of a session shared with others.
+
+		Work done inside `async with handle:` has exclusive use of the session, with the consumer's own tab
+		focus and selector map restored, so concurrent consumers don't corrupt each other's element indices.
+		"""
+		from browser_use.browser.session_handle import SessionHandle
+
+		return SessionHandle(self, name or f'handle-{uuid7str()[-4:]}')
+
+	async def add_init_script(self, script: str) -> str:
+		"""Evaluate a script in every page of the session before any page script runs, surviving navigations.
+
+		The script is injected into all open tabs (and runs right away in their current document) as well
+		as into every tab opened later. Returns an id to pass to remove_init_script().
+		"""
+		script_id = uuid7str()
+		self._init_scripts[script_id] = script
+		self._init_script_identifiers[script_id] = {}
+		for target in self.get_page_targets():
+			try:
+				await self._inject_init_script(script_id, target.target_id)
+			except Exception as e:
+				self.logger.warning(f'Failed to add init script {script_id[-4:]} to tab {target.target_id[-8:]}: {e}')
+		return script_id
+
+	async def remove_init_script(self, script_id: str) -> None:
+		"""Stop injecting a script added with add_init_script(), documents that already ran it are not affected."""
+		if script_id not in self._init_scripts:
+			raise ValueError(f'Unknown init script id: {script_id}')
+		del self._init_scripts[script_id]
+		for target_id, identifier in self._init_script_identifiers.pop(script_id, {}).items():
+			try:
+				cdp_session = await self.get_or_create_cdp_session(target_id, focus=False)
+				await cdp_session.cdp_client.send.Page.removeScriptToEvaluateOnNewDocument(
+					params={'identifier': identifier}, session_id=cdp_session.session_id
+				)
+			except Exception as e:
+				# The tab may have been closed in the meantime, its scripts are gone with it
+				self.logger.debug(f'Could not remove init script {script_id[-4:]} from tab {target_id[-8:]}: {e}')
+
+	async def add_binding(self, name: str, handler: 'BindingHandler') -> None:
+		"""Expose window[name](payload) to the JavaScript of every page, calling handler with a BindingCall for each call.
+
+		Lets page scripts push events to Python, e.g. custom instrumentation notifying the agent, instead of polling
+		with Runtime.evaluate. The payload must be a string. Bindings survive navigations and are added to tabs opened
+		later. Page scripts can see and call them too, so do not trust the payload.
+		"""
+		if not name.isidentifier():
+			raise ValueError(f'Binding name must be a valid JavaScript identifier, got {name!r}')
+		if name in self._bindings:
+			raise ValueError(f'Binding {name!r} is already added')
+		self._bindings[name] = handler
+		self._binding_target_ids[name] = set()
+		if self._binding_subscription_id is None:
+			self._binding_subscription_id = self.subscribe_cdp_event('Runtime.bindingCalled', self._on_binding_called)
+		for target in self.get_page_targets():
+			try:
+				await self._add_binding_to_target(name, target.target_id)
+			except Exception as e:
+				self.logger.warning(f'Failed to add binding {name!r} to tab {target.target_id[-8:]}: {e}')
+
+	async def remove_binding(self, name: str) -> None:
+		"""Remove a binding added with add_binding(), window[name] stays defined in documents that already have it."""
+		if self._bindings.pop(name, None) is None:
+			raise ValueError(f'Unknown binding: {name!r}')
+		for target_id in self._binding_target_ids.pop(name, set()):
+			try:
+				cdp_session = await self.get_or_create_cdp_session(target_id, focus=False)
+				await cdp_session.cdp_client.send.Runtime.removeBinding(params={'name': name}, session_id=cdp_session.session_id)
+			except Exception as e:
+				# The tab may have been closed in the meantime, its bindings are gone with it
+				self.logger.debug(f'Could not remove binding {name!r} from tab {target_id[-8:]}: {e}')
+
+	async def wait_for_dom_stable(self, quiet_ms: int = 500, timeout: float = 10.0) -> bool:
+		"""Wait until the DOM of the focused page had no mutations for quiet_ms, False if it kept changing until timeout.
+
+		An in-page MutationObserver signals through a binding once the page is quiet, so nothing is polled.
+		"""
+		if _DOM_STABLE_BINDING not in self._bindings:
+			await self.add_binding(_DOM_STABLE_BINDING, self._on_dom_stable)
+		token = uuid7str()
+		stable = self._dom_stable_waiters[token] = asyncio.Event()
+		try:
+			cdp_session = await self.get_or_create_cdp_session()
+			await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={
+					'expression': _DOM_STABLE_JS
+					% {'binding': _DOM_STABLE_BINDING, 'token': token, 'quiet_ms': quiet_ms, 'timeout_ms': int(timeout * 1000)}
+				},
+				session_id=cdp_session.session_id,
+			)
+			await asyncio.wait_for(stable.wait(), timeout=timeout)
+			return True
+		except TimeoutError:
+			return False
+		finally:
+			self._dom_stable_waiters.pop(token, None)
+
+	def _on_dom_stable(self, call: 'BindingCall') -> None:
+		if waiter := self._dom_stable_waiters.get(call.payload):
+			waiter.set()
+
+	def _on_binding_called(self, event: 'CDPEvent') -> Any:
+		from browser_use.browser.cdp_events import BindingCall
+
+		name = event.params.get('name', '')
+		handler = self._bindings.get(name)
+		if handler is None:
+			return None
+		call = BindingCall(
+			name=name,
+			payload=event.params.get('payload', ''),
+			target_id=event.target_id,
+			execution_context_id=event.params.get('executionContextId'),
+		)
+		return handler(call)
+
+	async def _add_binding_to_target(self, name: str, target_id: TargetID) -> None:
+		"""Add a binding to one tab, unless it was already added there."""
+		target_ids = self._binding_target_ids.setdefault(name, set())
+		if target_id in target_ids:
+			return
+		cdp_session = await self.get_or_create_cdp_session(target_id, focus=False)
+		await cdp_session.cdp_client.send.Runtime.addBinding(params={'name': name}, session_id=cdp_session.session_id)
+		target_ids.add(target_id)
+
+	def subscribe_cdp_event(self, method: str, handler: 'CDPEventHandler', target_id: TargetID | None = None) -> str:
+		"""Call handler with a CDPEvent for every CDP event matching method, e.g. 'Network.responseReceived' or 'Network.*'.
+
+		Unlike cdp_client.register, this does not replace the handlers browser-use's watchdogs rely on. Handlers may
+		be sync or async (async ones run as tasks so they never block the connection), pass target_id to only get the
+		events of one tab. Subscriptions survive reconnects. Returns an id to pass to unsubscribe_cdp_event().
+		"""
+		from browser_use.browser.cdp_events import CDPEventSubscription
+
+		subscription = CDPEventSubscription(id=uuid7str(), method=method, handler=handler, target_id=target_id)
+		self._cdp_event_subscriptions[subscription.id] = subscription
+		return subscription.id
+
+	def unsubscribe_cdp_event(self, subscription_id: str) -> None:
+		"""Remove a handler added with subscribe_cdp_event()."""
+		if self._cdp_event_subscriptions.pop(subscription_id, None) is None:
+			raise ValueError(f'Unknown CDP event subscription id: {subscription_id}')
+
+	def screencast(
+		self,
+		format: 'ScreencastFormat' = 'jpeg',
+		quality: int = 80,
+		max_width: int | None = None,
+		max_height: int | None = None,
+		every_nth_frame: int = 1,
+	) -> 'Screencast':
+		"""A live screencast of the agent's focused tab, started with `async with` or start().
+
+		Iterate screencast.frames() for ScreencastFrame objects, or serve it to dashboards with
+		browser_use.browser.screencast.mjpeg_handler(). Cannot run while the session records video.
+		"""
+		from browser_use.browser.screencast import Screencast
+
+		return Screencast(
+			self, format=format, quality=quality, max_width=max_width, max_height=max_height, every_nth_frame=every_nth_frame
+		)
+
+	def _dispatch_cdp_event(self, method: str, params: Any, session_id: str | None) -> None:
+		if not self._cdp_event_subscriptions:
+			return
+		from browser_use.browser.cdp_events import CDPEvent
+
+		target_id = None
+		if session_id and self.session_manager is not None:
+			target_id = self.session_manager.get_target_id_from_session_id(session_id)
+		event = None
+		for subscription in list(self._cdp_event_subscriptions.values()):
+			if not subscription.matches(method, target_id):
+				continue
+			event = event or CDPEvent(method=method, params=params or {}, session_id=session_id, target_id=target_id)
+			try:
+				result = subscription.handler(event)
+				if inspect.isawaitable(result):
+					create_task_with_error_handling(
+						_await_cdp_event_handler(result), name=f'cdp_event_{method}', logger_instance=self.logger
+					)
+			except Exception as e:
+				self.logger.warning(f'CDP event handler for {subscription.method} failed on {method}: {type(e).__name__}: {e}')
+
+	async def _inject_init_script(self, script_id: str, target_id: TargetID) -> None:
+		"""Add a session init script to one tab, unless it was already added there."""
+		identifiers = self._init_script_identifiers.setdefault(script_id, {})
+		if target_id in identifiers:
+			return
+		cdp_session = await self.get_or_create_cdp_session(target_id, focus=False)
+		result = await cdp_session.cdp_client.send.Page.addScriptToEvaluateOnNewDocument(
+			params={'source': self._init_scripts[script_id], 'runImmediately': True}, session_id=cdp_session.session_id
+		)
+		identifiers[target_id] = result['identifier']
+
 	def get_focused_target(self) -> 'Target | None':
 		"""Get the target that currently has agent focus.
 
@@ -1421,12 +2054,18 @@ class BrowserSession(BaseModel):
 	async def cookies(self) -> list['Cookie']:
 		"""Get cookies, optionally filtered by URLs."""
 
-		result = await self.cdp_client.send.Storage.getCookies()
+		if self._browser_context_id:
+			result = await self.cdp_client.send.Storage.getCookies(params={'browserContextId': self._browser_context_id})
+		else:
+			result = await self.cdp_client.send.Storage.getCookies()
 		return result['cookies']
 
 	async def clear_cookies(self) -> None:
 		"""Clear all cookies."""
-		await self.cdp_client.send.Network.clearBrowserCookies()
+		if self._browser_context_id:
+			await self.cdp_client.send.Storage.clearCookies(params={'browserContextId': self._browser_context_id})
+		else:
+			await self.cdp_client.send.Network.clearBrowserCookies()
 
 	async def export_storage_state(self, output_path: str | Path | None = None) -> dict[str, Any]:
 		"""Export all browser cookies and storage to storage_state format.
@@ -1473,6 +2112,67 @@ class BrowserSession(BaseModel):
 
 		return storage_state
 
+	def llm_screenshot_dimensions(self) -> tuple[int, int] | None:
+		"""Size of the screenshots the LLM sees: llm_screenshot_size, or the viewport in device pixels. None before any state."""
+		if self.llm_screenshot_size:
+			return self.llm_screenshot_size
+		if self._original_viewport_size is None:
+			return None
+		width, height = self._original_viewport_size
+		return round(width * self._device_pixel_ratio), round(height * self._device_pixel_ratio)
+
+	def llm_coordinates_to_css(self, x: float, y: float) -> tuple[int, int]:
+		"""Map a point on the screenshot the LLM saw to CSS pixels of the viewport, the unit of Input.dispatchMouseEvent.
+
+		Undoes both the llm_screenshot_size resize and the device pixel ratio (screenshots are in device pixels).
+		"""
+		screenshot_size = self.llm_screenshot_dimensions()
+		if screenshot_size is None or self._original_viewport_size is None:
+			return int(x), int(y)
+		(viewport_width, viewport_height), (screenshot_width, screenshot_height) = self._original_viewport_size, screenshot_size
+		return int(x * viewport_width / screenshot_width), int(y * viewport_height / screenshot_height)
+
+	async def save_auth(self, name: str, sites: list[str] | None = None, vault: 'AuthVault | None' = None) -> 'SavedAuth':
+		"""Save the current cookies and localStorage/sessionStorage under name in the encrypted login vault.
+
+		Args:
+			name: Vault entry name, overwritten if it exists.
+			sites: Hostnames or URLs to keep cookies and storage for, all sites if None.
+			vault: Vault to save to, defaults to the one configured by BROWSER_USE_AUTH_VAULT_KEY / _DIR.
+		"""
+		from browser_use.browser.auth_vault import AuthVault
+
+		vault = vault or AuthVault()
+		storage_state = await self._cdp_get_storage_state()
+		entry = vault.save(name, dict(storage_state), sites=sites)
+		self.logger.info(
+			f'🔐 Saved login session {name!r} for {", ".join(entry.sites) or "no sites"} '
+			f'({entry.cookies_count} cookies, {entry.origins_count} origins)'
+		)
+		return entry
+
+	async def restore_auth(self, name: str, vault: 'AuthVault | None' = None, reload: bool = True) -> 'SavedAuth':
+		"""Restore a login session saved with save_auth().
+
+		Cookies apply immediately, localStorage/sessionStorage on the next load of each origin. With reload=True the
+		current page is reloaded when it belongs to one of the restored sites, so it picks up the session.
+		"""
+		from browser_use.browser.auth_vault import AuthVault
+
+		vault = vault or AuthVault()
+		entry = vault.load(name)
+		await self._cdp_set_storage_state(entry.storage_state)
+		self.logger.info(
+			f'🔐 Restored login session {name!r} for {", ".join(entry.sites) or "no sites"} '
+			f'({entry.cookies_count} cookies, {entry.origins_count} origins)'
+		)
+
+		if reload and self.agent_focus_target_id:
+			url = await self.get_current_page_url()
+			if entry.find_site(url):
+				await self.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=False))
+		return entry
+
 	async def get_or_create_cdp_session(self, target_id: TargetID | None = None, focus: bool = True) -> CDPSession:
 		"""Get CDP session for a target from the event-driven pool.
 
@@ -1487,7 +2187,7 @@ class BrowserSession(BaseModel):
 			CDPSession for the specified target.
 
 		Raises:
-			ValueError: If target doesn't exist or session is not available.
+			TargetNotFoundError: If target doesn't exist or session is not available (a ValueError subclass).
 		"""
 		assert self._cdp_client_root is not None, 'Root CDP client not initialized'
 		assert self.session_manager is not None, 'SessionManager not initialized'
@@ -1497,7 +2197,7 @@ class BrowserSession(BaseModel):
 			# Validate and wait for focus recovery if stale (centralized protection)
 			focus_valid = await self.session_manager.ensure_valid_focus(timeout=5.0)
 			if not focus_valid:
-				raise ValueError(
+				raise TargetNotFoundError(
 					'No valid agent focus available - target may have detached and recovery failed. '
 					'This indicates browser is in an unstable state.'
 				)
@@ -1521,12 +2221,12 @@ class BrowserSession(BaseModel):
 
 			if not session:
 				# Timeout - target doesn't exist
-				raise ValueError(f'Target {target_id} not found - may have detached or never existed')
+				raise TargetNotFoundError(f'Target {target_id} not found - may have detached or never existed')
 
 		# Validate session is still active
 		is_valid = await self.session_manager.validate_session(target_id)
 		if not is_valid:
-			raise ValueError(f'Target {target_id} has detached - no active sessions')
+			raise TargetNotFoundError(f'Target {target_id} has detached - no active sessions')
 
 		# Update focus if requested
 		# CRITICAL: Only allow focus change to 'page' type targets, not iframes/workers
@@ -1584,6 +2284,74 @@ class BrowserSession(BaseModel):
 			params={'headers': cast(Any, headers)}, session_id=cdp_session.session_id
 		)
 
+	def _emulated_viewport(self) -> ViewportSize | None:
+		"""Viewport emulated in every tab: set_viewport's, else BrowserProfile.viewport unless the page follows the window"""
+		if self._viewport_override is not None:
+			return self._viewport_override
+		if self._viewport_follows_window or self.browser_profile.no_viewport:
+			return None
+		return self.browser_profile.viewport
+
+	async def set_viewport(self, width: int, height: int) -> None:
+		"""Resize the page viewport in CSS pixels, for the focused tab and every tab the agent switches to afterwards.
+
+		Overrides BrowserProfile.viewport for the rest of the session, e.g. to get the desktop layout of a responsive
+		site or to normalize the resolution in tests. The window itself keeps its size, see set_window_bounds.
+		"""
+		if width <= 0 or height <= 0:
+			raise ValueError(f'Viewport must be at least 1x1, got {width}x{height}')
+		self._viewport_override = ViewportSize(width=width, height=height)
+		self._viewport_follows_window = False
+		if self.agent_focus_target_id:
+			await self._cdp_set_viewport(
+				width,
+				height,
+				self.browser_profile.device_scale_factor or 1.0,
+				mobile=self.browser_profile.is_mobile,
+				has_touch=self.browser_profile.has_touch,
+				target_id=self.agent_focus_target_id,
+			)
+		self._cached_browser_state_summary = None
+
+	async def set_window_bounds(
+		self,
+		width: int | None = None,
+		height: int | None = None,
+		left: int | None = None,
+		top: int | None = None,
+		state: Literal['normal', 'minimized', 'maximized', 'fullscreen'] = 'normal',
+	) -> dict[str, Any]:
+		"""Resize, move, maximize or fullscreen the window of the focused tab with Browser.setWindowBounds.
+
+		Size and position only apply to the 'normal' state, a maximized or fullscreen window is restored first.
+		Afterwards the page viewport follows the window: any emulated viewport (BrowserProfile.viewport,
+		set_viewport) is cleared. Returns the new window bounds.
+		"""
+		if not self.agent_focus_target_id:
+			raise RuntimeError('Cannot set window bounds: no tab is focused')
+		window = await self.cdp_client.send.Browser.getWindowForTarget(params={'targetId': self.agent_focus_target_id})
+		window_id = window['windowId']
+		if state != 'normal':
+			await self.cdp_client.send.Browser.setWindowBounds(params={'windowId': window_id, 'bounds': {'windowState': state}})
+		else:
+			if window['bounds'].get('windowState', 'normal') != 'normal':
+				await self.cdp_client.send.Browser.setWindowBounds(
+					params={'windowId': window_id, 'bounds': {'windowState': 'normal'}}
+				)
+			bounds = {'width': width, 'height': height, 'left': left, 'top': top}
+			bounds = {key: value for key, value in bounds.items() if value is not None}
+			if bounds:
+				await self.cdp_client.send.Browser.setWindowBounds(params={'windowId': window_id, 'bounds': cast(Any, bounds)})
+
+		self._viewport_override = None
+		self._viewport_follows_window = True
+		cdp_session = await self.get_or_create_cdp_session(self.agent_focus_target_id, focus=False)
+		await cdp_session.cdp_client.send.Emulation.clearDeviceMetricsOverride(session_id=cdp_session.session_id)
+		self._cached_browser_state_summary = None
+
+		result = await self.cdp_client.send.Browser.getWindowBounds(params={'windowId': window_id})
+		return dict(result['bounds'])
+
 	# endregion - ========== CDP-based ... ==========
 
 	# region - ========== Helper Methods ==========
@@ -1593,7 +2361,13 @@ class BrowserSession(BaseModel):
 		include_screenshot: bool = True,
 		cached: bool = False,
 		include_recent_events: bool = False,
+		include_dom: bool = True,
 	) -> BrowserStateSummary:
+		"""Get the current browser state.
+
+		include_dom: set to False to skip building the DOM tree, the state then has an empty selector_map
+		(used by computer-use mode, which works from the screenshot alone).
+		"""
 		if cached and self._cached_browser_state_summary is not None and self._cached_browser_state_summary.dom_state:
 			# Don't use cached state if it has 0 interactive elements
 			selector_map = self._cached_browser_state_summary.dom_state.selector_map
@@ -1614,7 +2388,7 @@ class BrowserSession(BaseModel):
 			BrowserStateRequestEvent,
 			self.event_bus.dispatch(
 				BrowserStateRequestEvent(
-					include_dom=True,
+					include_dom=include_dom,
 					include_screenshot=include_screenshot,
 					include_recent_events=include_recent_events,
 				)
@@ -1666,6 +2440,7 @@ class BrowserSession(BaseModel):
 
 		from browser_use.browser.watchdogs.aboutblank_watchdog import AboutBlankWatchdog
 		from browser_use.browser.watchdogs.captcha_watchdog import CaptchaWatchdog
+		from browser_use.browser.watchdogs.consent_watchdog import ConsentWatchdog
 
 		# from browser_use.browser.crash_watchdog import CrashWatchdog
 		from browser_use.browser.watchdogs.default_action_watchdog import DefaultActionWatchdog
@@ -1673,12 +2448,16 @@ class BrowserSession(BaseModel):
 		from browser_use.browser.watchdogs.downloads_watchdog import DownloadsWatchdog
 		from browser_use.browser.watchdogs.har_recording_watchdog import HarRecordingWatchdog
 		from browser_use.browser.watchdogs.local_browser_watchdog import LocalBrowserWatchdog
+		from browser_use.browser.watchdogs.network_capture_watchdog import NetworkCaptureWatchdog
+		from browser_use.browser.watchdogs.page_errors_watchdog import PageErrorsWatchdog
 		from browser_use.browser.watchdogs.permissions_watchdog import PermissionsWatchdog
 		from browser_use.browser.watchdogs.popups_watchdog import PopupsWatchdog
 		from browser_use.browser.watchdogs.recording_watchdog import RecordingWatchdog
 		from browser_use.browser.watchdogs.screenshot_watchdog import ScreenshotWatchdog
 		from browser_use.browser.watchdogs.security_watchdog import SecurityWatchdog
+		from browser_use.browser.watchdogs.stealth_watchdog import StealthWatchdog
 		from browser_use.browser.watchdogs.storage_state_watchdog import StorageStateWatchdog
+		from browser_use.browser.watchdogs.tab_health_watchdog import TabHealthWatchdog
 
 		# Initialize CrashWatchdog
 		# CrashWatchdog.model_rebuild()
@@ -1752,6 +2531,35 @@ class BrowserSession(BaseModel):
 		# self.event_bus.on(DialogCloseEvent, self._popups_watchdog.on_DialogCloseEvent)
 		self._popups_watchdog.attach_to_session()
 
+		# Initialize StealthWatchdog (fingerprint patches and user agent / locale / timezone emulation per tab)
+		profile = self.browser_profile
+		if profile.stealth or profile.locale or profile.timezone_id or (profile.user_agent and not self.is_local):
+			StealthWatchdog.model_rebuild()
+			self._stealth_watchdog = StealthWatchdog(event_bus=self.event_bus, browser_session=self)
+			self._stealth_watchdog.attach_to_session()
+
+		# Initialize PageErrorsWatchdog (collects JS exceptions, console errors and failed requests for the agent)
+		if self.browser_profile.capture_page_errors:
+			PageErrorsWatchdog.model_rebuild()
+			self._page_errors_watchdog = PageErrorsWatchdog(event_bus=self.event_bus, browser_session=self)
+			self._page_errors_watchdog.attach_to_session()
+
+		# Initialize TabHealthWatchdog (notices crashed and unexpectedly blank tabs, see recover_unhealthy_tab)
+		if self.browser_profile.recover_crashed_tabs:
+			TabHealthWatchdog.model_rebuild()
+			self._tab_health_watchdog = TabHealthWatchdog(event_bus=self.event_bus, browser_session=self)
+			self._tab_health_watchdog.attach_to_session()
+
+		# Initialize NetworkCaptureWatchdog (records XHR/fetch responses once enable_network_capture() is called)
+		NetworkCaptureWatchdog.model_rebuild()
+		self._network_capture_watchdog = NetworkCaptureWatchdog(event_bus=self.event_bus, browser_session=self)
+		self._network_capture_watchdog.attach_to_session()
+
+		# Initialize ConsentWatchdog (clicks reject/accept on cookie consent banners when the agent asks for it)
+		ConsentWatchdog.model_rebuild()
+		self._consent_watchdog = ConsentWatchdog(event_bus=self.event_bus, browser_session=self)
+		self._consent_watchdog.attach_to_session()
+
 		# Initialize PermissionsWatchdog (handles granting and revoking browser permissions like clipboard, microphone, camera, etc.)
 		PermissionsWatchdog.model_rebuild()
 		self._permissions_watchdog = PermissionsWatchdog(event_bus=self.event_bus, browser_session=self)
@@ -1793,8 +2601,8 @@ class BrowserSession(BaseModel):
 		self._recording_watchdog = RecordingWatchdog(event_bus=self.event_bus, browser_session=self)
 		self._recording_watchdog.attach_to_session()
 
-		# Initialize HarRecordingWatchdog if record_har_path is configured (handles HTTPS HAR capture)
-		if self.browser_profile.record_har_path:
+		# Initialize HarRecordingWatchdog if HAR recording is configured (handles HTTPS HAR capture)
+		if self.browser_profile.record_har_path or self.browser_profile.record_har:
 			HarRecordingWatchdog.model_rebuild()
 			self._har_recording_watchdog = HarRecordingWatchdog(event_bus=self.event_bus, browser_session=self)
 			self._har_recording_watchdog.attach_to_session()
@@ -1864,19 +2672,15 @@ class BrowserSession(BaseModel):
 
 		try:
 			# Create and store the CDP client for direct CDP communication
-			headers = dict(getattr(self.browser_profile, 'headers', None) or {})
-			if not self.is_local:
-				from browser_use.utils import get_browser_use_version
-
-				headers.setdefault('User-Agent', f'browser-use/{get_browser_use_version()}')
-			self._cdp_client_root = TimeoutWrappedCDPClient(
-				self.cdp_url,
-				additional_headers=headers or None,
-				max_ws_frame_size=200 * 1024 * 1024,  # Use 200MB limit to handle pages with very large DOMs
-			)
+			self._cdp_client_root = self._create_cdp_client()
 			assert self._cdp_client_root is not None
+			self._cdp_client_root.command_observer = self._observe_cdp_command
+			self._cdp_client_root.event_observer = self._dispatch_cdp_event
 			await self._cdp_client_root.start()
 
+			# Create or look up the browser context before discovering targets, the session manager only tracks its tabs
+			await self._setup_browser_context()
+
 			# Initialize event-driven session manager FIRST (before enabling autoAttach)
 			# SessionManager will:
 			# 1. Register attach/detach event handlers
@@ -1922,14 +2726,23 @@ class BrowserSession(BaseModel):
 			if redirect_tasks:
 				await asyncio.gather(*redirect_tasks, return_exceptions=True)
 
-			# Ensure we have at least one page
-			if not page_targets_from_manager:
-				new_target = await self._cdp_client_root.send.Target.createTarget(params={'url': 'about:blank'})
+			# Remember what was open before us, so stop() only closes the tabs opened during this session
+			self._initial_target_ids = {target.target_id for target in page_targets_from_manager}
+
+			# Ensure we have at least one page, attaching to the requested tab if one was given
+			requested_target = self._find_requested_target(page_targets_from_manager)
+			if requested_target is not None:
+				target_id = requested_target.target_id
+				self.logger.info(f'📄 Attaching to open tab #{target_id[-4:]}: {_log_pretty_url(requested_target.url)}')
+			elif not page_targets_from_manager:
+				new_target = await self._cdp_client_root.send.Target.createTarget(params=self._new_target_params('about:blank'))
 				target_id = new_target['targetId']
 				self.logger.debug(f'📄 Created new blank page: {target_id}')
 			else:
 				target_id = page_targets_from_manager[0].target_id
 				self.logger.debug(f'📄 Using existing page: {target_id}')
+			if self.browser_profile.restrict_to_target:
+				self._pinned_target_id = target_id
 
 			# Set up initial focus using the public API
 			# Note: get_or_create_cdp_session() will wait for attach event and set focus
@@ -1943,8 +2756,8 @@ class BrowserSession(BaseModel):
 			# Note: Lifecycle monitoring is enabled automatically in SessionManager._handle_target_attached()
 			# when targets attach, so no manual enablement needed!
 
-			# Enable proxy authentication handling if configured
-			await self._setup_proxy_auth()
+			# Enable proxy and HTTP authentication handling if configured
+			await self._setup_fetch_auth()
 
 			# Attach WS drop detection callback for auto-reconnection
 			self._intentional_stop = False
@@ -1964,9 +2777,9 @@ class BrowserSession(BaseModel):
 
 			# Dispatch initial focus event
 			if page_targets_from_manager:
-				initial_url = page_targets_from_manager[0].url
-				self.event_bus.dispatch(AgentFocusChangedEvent(target_id=page_targets_from_manager[0].target_id, url=initial_url))
-				self.logger.debug(f'Initial agent focus set to tab 0: {initial_url}')
+				initial_target = requested_target or page_targets_from_manager[0]
+				self.event_bus.dispatch(AgentFocusChangedEvent(target_id=initial_target.target_id, url=initial_target.url))
+				self.logger.debug(f'Initial agent focus set to tab #{initial_target.target_id[-4:]}: {initial_target.url}')
 
 		except Exception as e:
 			# Fatal error - browser is not usable without CDP connection
@@ -1997,136 +2810,251 @@ class BrowserSession(BaseModel):
 
 		return self
 
-	async def _setup_proxy_auth(self) -> None:
-		"""Enable CDP Fetch auth handling for authenticated proxy, if credentials provided.
+	@property
+	def is_recording_har(self) -> bool:
+		"""Whether network activity is being recorded for HAR export (record_har or record_har_path)."""
+		return self._har_recording_watchdog is not None and self._har_recording_watchdog.enabled
 
-		Handles HTTP proxy authentication challenges (Basic/Proxy) by providing
-		configured credentials from BrowserProfile.
+	def export_har(
+		self,
+		path: str | Path,
+		since: float | None = None,
+		sensitive_data: dict[str, str | dict[str, str]] | None = None,
+		redact: Callable[[str], str] | None = None,
+	) -> Path:
+		"""Write the network activity recorded so far as a HAR 1.2 file, only requests sent since `since` (epoch seconds).
+
+		sensitive_data values are replaced by their placeholders and redact is applied to the URLs, headers and bodies.
 		"""
+		from browser_use.browser.watchdogs.har_recording_watchdog import make_har_redactor
 
-		assert self._cdp_client_root
+		if not self.is_recording_har:
+			raise RuntimeError('HAR recording is not enabled, start the browser with record_har=True or record_har_path')
+		return self._har_recording_watchdog.write_har(path, since=since, redact=make_har_redactor(sensitive_data, redact))
 
-		try:
-			proxy_cfg = self.browser_profile.proxy
-			username = proxy_cfg.username if proxy_cfg else None
-			password = proxy_cfg.password if proxy_cfg else None
-			if not username or not password:
-				self.logger.debug('Proxy credentials not provided; skipping proxy auth setup')
+	@property
+	def browser_context_id(self) -> str | None:
+		"""The browser context this session's tabs live in, None for the browser's default context."""
+		return self._browser_context_id
+
+	def _new_target_params(self, url: str) -> CreateTargetParameters:
+		"""Target.createTarget params that open the tab in this session's browser context."""
+		params = CreateTargetParameters(url=url)
+		if self._browser_context_id:
+			params['browserContextId'] = self._browser_context_id
+		return params
+
+	async def _setup_browser_context(self) -> None:
+		"""Create the isolated browser context, or reuse the named one, when the profile asks for one."""
+		name = self.browser_profile.browser_context_name
+		if self._browser_context_id or not (name or self.browser_profile.isolated_context):
+			return
+		assert self._cdp_client_root is not None and self.cdp_url is not None
+
+		key = (self.cdp_url, name) if name else None
+		if key and key in _NAMED_BROWSER_CONTEXTS:
+			existing = await self._cdp_client_root.send.Target.getBrowserContexts()
+			if _NAMED_BROWSER_CONTEXTS[key] in existing.get('browserContextIds', []):
+				self._browser_context_id = _NAMED_BROWSER_CONTEXTS[key]
+				self.logger.info(f'🕶️ Reusing browser context "{name}" ({self._browser_context_id[-4:]})')
 				return
 
-			# Enable Fetch domain with auth handling (do not pause all requests)
-			try:
-				await self._cdp_client_root.send.Fetch.enable(params={'handleAuthRequests': True})
-				self.logger.debug('Fetch.enable(handleAuthRequests=True) enabled on root client')
-			except Exception as e:
-				self.logger.debug(f'Fetch.enable on root failed: {type(e).__name__}: {e}')
+		# Not disposeOnDetach: the context has to survive a websocket reconnect, stop() disposes it
+		result = await self._cdp_client_root.send.Target.createBrowserContext(params={'disposeOnDetach': False})
+		self._browser_context_id = result['browserContextId']
+		if key:
+			_NAMED_BROWSER_CONTEXTS[key] = self._browser_context_id
+		label = f'"{name}" ' if name else ''
+		self.logger.info(f'🕶️ Created isolated browser context {label}({self._browser_context_id[-4:]})')
+
+	async def _dispose_browser_context(self) -> None:
+		"""Close the isolated context with all its tabs, cookies and storage. Named contexts are kept for reuse."""
+		context_id, self._browser_context_id = self._browser_context_id, None
+		if not context_id or self.browser_profile.browser_context_name or self._cdp_client_root is None:
+			return
+		try:
+			await self._cdp_client_root.send.Target.disposeBrowserContext(params={'browserContextId': context_id})
+			self.logger.info(f'🧹 Disposed isolated browser context ({context_id[-4:]})')
+		except Exception as e:
+			self.logger.debug(f'Failed to dispose browser context {context_id[-4:]}: {e}')
+
+	def _find_requested_target(self, page_targets: list[Target]) -> Target | None:
+		"""The open tab matching the profile's target_id, target_url_pattern and target_title_pattern, None if none are set"""
+		profile = self.browser_profile
+		criteria = {
+			'target_id': profile.target_id,
+			'target_url_pattern': profile.target_url_pattern,
+			'target_title_pattern': profile.target_title_pattern,
+		}
+		if not any(criteria.values()):
+			return None
 
-			# Also enable on the focused target's session if available to ensure events are delivered
-			try:
-				if self.agent_focus_target_id:
-					cdp_session = await self.get_or_create_cdp_session(self.agent_focus_target_id, focus=False)
-					await cdp_session.cdp_client.send.Fetch.enable(
-						params={'handleAuthRequests': True},
-						session_id=cdp_session.session_id,
-					)
-					self.logger.debug('Fetch.enable(handleAuthRequests=True) enabled on focused session')
-			except Exception as e:
-				self.logger.debug(f'Fetch.enable on focused session failed: {type(e).__name__}: {e}')
+		for target in page_targets:
+			if profile.target_id and target.target_id != profile.target_id:
+				continue
+			if profile.target_url_pattern and not _tab_matches(target.url, profile.target_url_pattern):
+				continue
+			if profile.target_title_pattern and not _tab_matches(target.title, profile.target_title_pattern):
+				continue
+			return target
 
-			def _on_auth_required(event: AuthRequiredEvent, session_id: SessionID | None = None):
-				# event keys may be snake_case or camelCase depending on generator; handle both
-				request_id = event.get('requestId') or event.get('request_id')
-				if not request_id:
-					return
+		wanted = ', '.join(f'{name}={value!r}' for name, value in criteria.items() if value)
+		open_tabs = ', '.join(f'{target.title!r} ({target.url})' for target in page_targets) or 'none'
+		raise RuntimeError(f'No open tab matches {wanted}. Open tabs: {open_tabs}')
 
-				challenge = event.get('authChallenge') or event.get('auth_challenge') or {}
-				source = (challenge.get('source') or '').lower()
-				# Only respond to proxy challenges
-				if source == 'proxy' and request_id:
+	@property
+	def pinned_target_id(self) -> TargetID | None:
+		"""The tab the agent is restricted to when connected with restrict_to_target=True"""
+		return self._pinned_target_id
 
-					async def _respond():
-						assert self._cdp_client_root
-						try:
-							await self._cdp_client_root.send.Fetch.continueWithAuth(
-								params={
-									'requestId': request_id,
-									'authChallengeResponse': {
-										'response': 'ProvideCredentials',
-										'username': username,
-										'password': password,
-									},
-								},
-								session_id=session_id,
-							)
-						except Exception as e:
-							self.logger.debug(f'Proxy auth respond failed: {type(e).__name__}: {e}')
+	async def set_http_credentials(self, username: str, password: str, origin_pattern: str) -> None:
+		"""Answer HTTP basic/digest auth challenges of matching origins with these credentials.
 
-					# schedule
-					create_task_with_error_handling(
-						_respond(), name='auth_respond', logger_instance=self.logger, suppress_exceptions=True
-					)
-				else:
-					# Default behaviour for non-proxy challenges: let browser handle
-					async def _default():
-						assert self._cdp_client_root
-						try:
-							await self._cdp_client_root.send.Fetch.continueWithAuth(
-								params={'requestId': request_id, 'authChallengeResponse': {'response': 'Default'}},
-								session_id=session_id,
-							)
-						except Exception as e:
-							self.logger.debug(f'Default auth respond failed: {type(e).__name__}: {e}')
+		origin_pattern is matched against the origin of the challenged request (scheme://host[:port]) with * and ?
+		wildcards, e.g. 'https://intranet.example.com' or 'https://*.corp.example.com'. It must name a scheme and a
+		host, so credentials are never sent to every site. Only requests to matching origins are intercepted. Setting
+		credentials again for the same pattern replaces them. They are kept across restarts and reconnects of the session.
+		"""
+		origin_pattern = origin_pattern.strip().rstrip('/')
+		scheme, _, host = origin_pattern.partition('://')
+		if not scheme or not host or '/' in host or host.strip('*?.') == '':
+			raise ValueError(
+				f'origin_pattern must be an origin like https://intranet.example.com, got {origin_pattern!r}'
+			)
+		self._http_credentials[origin_pattern] = (username, password)
+		if self._cdp_client_root is not None:
+			await self._setup_fetch_auth()
 
-					if request_id:
-						create_task_with_error_handling(
-							_default(), name='auth_default', logger_instance=self.logger, suppress_exceptions=True
-						)
+	def _proxy_credentials(self) -> tuple[str, str] | None:
+		proxy_cfg = self.browser_profile.proxy
+		if proxy_cfg and proxy_cfg.username and proxy_cfg.password:
+			return proxy_cfg.username, proxy_cfg.password
+		return None
 
-			def _on_request_paused(event: RequestPausedEvent, session_id: SessionID | None = None):
-				# Continue all paused requests to avoid stalling the network
-				request_id = event.get('requestId') or event.get('request_id')
-				if not request_id:
-					return
+	def _http_credentials_for(self, url: str | None) -> tuple[str, str] | None:
+		if not url:
+			return None
+		parsed = urlparse(url)
+		origin = f'{parsed.scheme}://{parsed.netloc}'.lower()
+		for origin_pattern, credentials in self._http_credentials.items():
+			if fnmatch.fnmatchcase(origin, origin_pattern.lower()):
+				return credentials
+		return None
 
-				async def _continue():
-					assert self._cdp_client_root
-					try:
-						await self._cdp_client_root.send.Fetch.continueRequest(
-							params={'requestId': request_id},
-							session_id=session_id,
-						)
-					except Exception:
-						pass
+	def _fetch_auth_params(self) -> FetchEnableParameters | None:
+		"""Fetch.enable params that route auth challenges to us, None when there are no credentials to answer them.
 
-				create_task_with_error_handling(
-					_continue(), name='request_continue', logger_instance=self.logger, suppress_exceptions=True
-				)
+		Every request matching the patterns is paused and continued by _on_fetch_request_paused, so without proxy
+		credentials only the origins with HTTP credentials are intercepted.
+		"""
+		if self._proxy_credentials():
+			return {'handleAuthRequests': True}
+		if self._http_credentials:
+			return {
+				'handleAuthRequests': True,
+				'patterns': [{'urlPattern': f'{origin_pattern}/*'} for origin_pattern in self._http_credentials],
+			}
+		return None
+
+	async def _setup_fetch_auth(self) -> None:
+		"""Enable CDP Fetch auth handling for an authenticated proxy and HTTP credentials, if any are configured.
+
+		Proxy challenges are answered with the credentials of BrowserProfile.proxy, server challenges (HTTP basic
+		auth) with the credentials set by set_http_credentials() for the request's origin.
+		"""
+
+		assert self._cdp_client_root
+
+		params = self._fetch_auth_params()
+		if params is None:
+			self.logger.debug('No proxy or HTTP credentials provided; skipping auth setup')
+			return
+
+		if not self._fetch_subscription_ids:
+			# Subscribed once, subscriptions survive reconnects and see the events of every target session
+			self._fetch_subscription_ids = [
+				self.subscribe_cdp_event('Fetch.authRequired', lambda e: self._on_fetch_auth_required(e.params, e.session_id)),
+				self.subscribe_cdp_event('Fetch.requestPaused', lambda e: self._on_fetch_request_paused(e.params, e.session_id)),
+			]
+
+		try:
+			await self._cdp_client_root.send.Fetch.enable(params=params)
+			self.logger.debug('Fetch.enable(handleAuthRequests=True) enabled on root client')
+		except Exception as e:
+			self.logger.debug(f'Fetch.enable on root failed: {type(e).__name__}: {e}')
+
+		# Also enable on the attached target sessions to ensure events are delivered, new ones are handled by SessionManager
+		if self.session_manager is not None:
+			for cdp_session in list(self.session_manager.get_all_sessions().values()):
+				try:
+					await cdp_session.cdp_client.send.Fetch.enable(params=params, session_id=cdp_session.session_id)
+				except Exception as e:
+					self.logger.debug(f'Fetch.enable on session {cdp_session.session_id[-8:]} failed: {type(e).__name__}: {e}')
+
+	def _on_fetch_auth_required(self, event: AuthRequiredEvent, session_id: SessionID | None = None) -> None:
+		# event keys may be snake_case or camelCase depending on generator; handle both
+		request_id = event.get('requestId') or event.get('request_id')
+		if not request_id:
+			return
 
-			# Register event handler on root client
+		challenge = event.get('authChallenge') or event.get('auth_challenge') or {}
+		source = (challenge.get('source') or '').lower()
+		if source == 'proxy':
+			credentials = self._proxy_credentials()
+		else:
+			credentials = self._http_credentials_for(challenge.get('origin') or (event.get('request') or {}).get('url'))
+
+		if credentials and request_id in self._answered_auth_requests:
+			# The credentials were rejected, cancel instead of sending them again forever
+			self._answered_auth_requests.discard(request_id)
+			response: AuthChallengeResponse = {'response': 'CancelAuth'}
+		elif credentials:
+			self._answered_auth_requests.add(request_id)
+			response = {'response': 'ProvideCredentials', 'username': credentials[0], 'password': credentials[1]}
+		else:
+			# Let the browser handle challenges we have no credentials for
+			response = {'response': 'Default'}
+
+		async def _respond():
+			assert self._cdp_client_root
 			try:
-				self._cdp_client_root.register.Fetch.authRequired(_on_auth_required)
-				self._cdp_client_root.register.Fetch.requestPaused(_on_request_paused)
-				if self.agent_focus_target_id:
-					cdp_session = await self.get_or_create_cdp_session(self.agent_focus_target_id, focus=False)
-					cdp_session.cdp_client.register.Fetch.authRequired(_on_auth_required)
-					cdp_session.cdp_client.register.Fetch.requestPaused(_on_request_paused)
-				self.logger.debug('Registered Fetch.authRequired handlers')
+				await self._cdp_client_root.send.Fetch.continueWithAuth(
+					params={'requestId': request_id, 'authChallengeResponse': response},
+					session_id=session_id,
+				)
 			except Exception as e:
-				self.logger.debug(f'Failed to register authRequired handlers: {type(e).__name__}: {e}')
+				self.logger.debug(f'Auth respond failed: {type(e).__name__}: {e}')
 
-			# Ensure Fetch is enabled for the current focused target's session, too
+		create_task_with_error_handling(_respond(), name='auth_respond', logger_instance=self.logger, suppress_exceptions=True)
+
+	def _on_fetch_request_paused(self, event: RequestPausedEvent, session_id: SessionID | None = None) -> None:
+		# Continue all paused requests to avoid stalling the network
+		request_id = event.get('requestId') or event.get('request_id')
+		if not request_id:
+			return
+		self._answered_auth_requests.discard(request_id)
+
+		async def _continue():
+			assert self._cdp_client_root
 			try:
-				if self.agent_focus_target_id:
-					# Use safe API with focus=False to avoid changing focus
-					cdp_session = await self.get_or_create_cdp_session(self.agent_focus_target_id, focus=False)
-					await cdp_session.cdp_client.send.Fetch.enable(
-						params={'handleAuthRequests': True, 'patterns': [{'urlPattern': '*'}]},
-						session_id=cdp_session.session_id,
-					)
-			except Exception as e:
-				self.logger.debug(f'Fetch.enable on focused session failed: {type(e).__name__}: {e}')
-		except Exception as e:
-			self.logger.debug(f'Skipping proxy auth setup: {type(e).__name__}: {e}')
+				await self._cdp_client_root.send.Fetch.continueRequest(
+					params={'requestId': request_id},
+					session_id=session_id,
+				)
+			except Exception:
+				pass
+
+		create_task_with_error_handling(
+			_continue(), name='request_continue', logger_instance=self.logger, suppress_exceptions=True
+		)
+
+	def attach_metrics(self, metrics: 'AgentMetrics | None') -> None:
+		"""Record CDP command latency, reconnects and screenshot sizes of this session in the given metrics."""
+		self._metrics = metrics
+
+	def _observe_cdp_command(self, method: str, duration: float) -> None:
+		if self._metrics:
+			self._metrics.record_cdp_command(method, duration)
 
 	async def reconnect(self) -> None:
 		"""Re-establish the CDP WebSocket connection to an already-running browser.
@@ -2162,16 +3090,9 @@ class BrowserSession(BaseModel):
 		self.agent_focus_target_id = None
 
 		# 3. Create new CDPClient with the same cdp_url
-		headers = dict(getattr(self.browser_profile, 'headers', None) or {})
-		if not self.is_local:
-			from browser_use.utils import get_browser_use_version
-
-			headers.setdefault('User-Agent', f'browser-use/{get_browser_use_version()}')
-		self._cdp_client_root = TimeoutWrappedCDPClient(
-			self.cdp_url,
-			additional_headers=headers or None,
-			max_ws_frame_size=200 * 1024 * 1024,
-		)
+		self._cdp_client_root = self._create_cdp_client()
+		self._cdp_client_root.command_observer = self._observe_cdp_command
+		self._cdp_client_root.event_observer = self._dispatch_cdp_event
 		await self._cdp_client_root.start()
 
 		# 4. Re-initialize SessionManager
@@ -2205,13 +3126,13 @@ class BrowserSession(BaseModel):
 				self.logger.debug(f'🔄 Agent focus set to fallback target {fallback_id[:8]}...')
 			else:
 				# No pages exist — create one
-				new_target = await self._cdp_client_root.send.Target.createTarget(params={'url': 'about:blank'})
+				new_target = await self._cdp_client_root.send.Target.createTarget(params=self._new_target_params('about:blank'))
 				target_id = new_target['targetId']
 				await self.get_or_create_cdp_session(target_id, focus=True)
 				self.logger.debug(f'🔄 Created new blank page during reconnect: {target_id[:8]}...')
 
-		# 7. Re-enable proxy auth if configured
-		await self._setup_proxy_auth()
+		# 7. Re-enable proxy and HTTP auth if configured
+		await self._setup_fetch_auth()
 
 		# 8. Attach the WS drop detection callback to the new client
 		self._attach_ws_drop_callback()
@@ -2254,6 +3175,15 @@ class BrowserSession(BaseModel):
 						)
 					)
 					self.logger.info(f'🔄 WebSocket reconnected after {downtime:.1f}s (attempt {attempt})')
+					if self._metrics:
+						self._metrics.record_reconnect(success=True)
+					# CDP sessions and event handlers of the old connection are gone: watchdogs reset their
+					# per-tab state on BrowserReconnectedEvent and set every open tab up again, like on connect()
+					for identifiers in self._init_script_identifiers.values():
+						identifiers.clear()
+					if self.session_manager:
+						for target in self.session_manager.get_all_page_targets():
+							self.event_bus.dispatch(TabCreatedEvent(url=target.url, target_id=target.target_id))
 					return
 				except Exception as e:
 					self.logger.warning(f'🔄 Reconnection attempt {attempt} failed: {type(e).__name__}: {e}')
@@ -2263,6 +3193,8 @@ class BrowserSession(BaseModel):
 
 			# All attempts exhausted
 			self.logger.error(f'🔄 All {max_attempts} reconnection attempts failed')
+			if self._metrics:
+				self._metrics.record_reconnect(success=False)
 			self.event_bus.dispatch(
 				BrowserErrorEvent(
 					error_type='ReconnectionFailed',
@@ -2274,6 +3206,141 @@ class BrowserSession(BaseModel):
 			self._reconnecting = False
 			self._reconnect_event.set()  # wake up all waiters regardless of outcome
 
+	async def wait_for_reconnect(self, timeout: float | None = None) -> bool:
+		"""Wait for the CDP connection to come back after a websocket drop, returns whether it is connected.
+
+		Starts the reconnection itself if the drop was noticed by a failing command before the
+		message handler task exited, so callers can retry their work once this returns True.
+		"""
+		if self.is_cdp_connected and not self._reconnecting:
+			return True
+		if self._intentional_stop or not self.cdp_url:
+			return False
+
+		if not self._reconnecting and (self._reconnect_task is None or self._reconnect_task.done()):
+			self._reconnect_task = asyncio.create_task(self._auto_reconnect())
+			await asyncio.sleep(0)  # let _auto_reconnect mark the reconnection as in progress
+
+		try:
+			await asyncio.wait_for(self._reconnect_event.wait(), timeout=timeout or self.RECONNECT_WAIT_TIMEOUT)
+		except TimeoutError:
+			pass
+		return self.is_cdp_connected
+
+	def _create_cdp_client(self) -> TimeoutWrappedCDPClient:
+		"""Create the root CDP client for cdp_url with the profile's headers and message size limit."""
+		assert self.cdp_url is not None, 'CDP URL is None.'
+		headers = dict(getattr(self.browser_profile, 'headers', None) or {})
+		if not self.is_local:
+			from browser_use.utils import get_browser_use_version
+
+			headers.setdefault('User-Agent', f'browser-use/{get_browser_use_version()}')
+		client = TimeoutWrappedCDPClient(
+			self.cdp_url,
+			additional_headers=headers or None,
+			max_ws_frame_size=self.browser_profile.cdp_max_message_size,
+		)
+		client.traffic_logger = self._get_cdp_traffic_logger()
+		return client
+
+	def _get_cdp_traffic_logger(self) -> 'CDPTrafficLogger | None':
+		"""The CDP wire log of this session when cdp_log_dir is set, shared by the clients of reconnects."""
+		profile = self.browser_profile
+		if profile.cdp_log_dir is None:
+			return None
+		if self._cdp_traffic_logger is None:
+			from browser_use.browser.cdp_log import CDPTrafficLogger
+
+			self._cdp_traffic_logger = CDPTrafficLogger(
+				Path(profile.cdp_log_dir) / f'cdp_{self.id}.log',
+				include=profile.cdp_log_include,
+				exclude=profile.cdp_log_exclude,
+				max_payload_chars=profile.cdp_log_max_payload_chars,
+			)
+			self.logger.info(f'📝 Logging CDP traffic to {self._cdp_traffic_logger.path}')
+		return self._cdp_traffic_logger
+
+	def _get_politeness(self) -> 'DomainPoliteness | None':
+		"""Per-domain rate limits and robots.txt rules, None when neither domain_rate_limit nor respect_robots_txt is set."""
+		profile = self.browser_profile
+		if profile.domain_rate_limit is None and not profile.respect_robots_txt:
+			return None
+		if self._politeness is None:
+			from browser_use.browser.politeness import DomainPoliteness
+
+			self._politeness = DomainPoliteness(
+				rate_limit=profile.domain_rate_limit,
+				jitter=profile.domain_rate_limit_jitter,
+				respect_robots_txt=profile.respect_robots_txt,
+				user_agent=profile.user_agent,
+				proxy=profile.proxy.server if profile.proxy else None,
+			)
+		return self._politeness
+
+	def _check_domain_tab_limit(self, url: str, navigated_target_id: str | None) -> None:
+		"""Raise TooManyDomainTabsError when loading url would put more than max_tabs_per_domain tabs on its domain."""
+		limit = self.browser_profile.max_tabs_per_domain
+		if limit is None or self.session_manager is None:
+			return
+		from browser_use.browser.politeness import TooManyDomainTabsError, politeness_domain
+
+		domain = politeness_domain(url)
+		if domain is None:
+			return
+		open_tabs = [
+			target
+			for target in self.session_manager.get_all_page_targets()
+			if target.target_id != navigated_target_id and politeness_domain(target.url) == domain
+		]
+		if len(open_tabs) >= limit:
+			msg = f'{len(open_tabs)} tabs are already open on {domain} (max_tabs_per_domain={limit})'
+			raise TooManyDomainTabsError(
+				f'Navigation to {url} blocked: {msg}',
+				long_term_memory=f'{msg}, navigate in one of them or close one before opening another',
+			)
+
+	def _find_same_origin_tab(self, url: str) -> TargetID | None:
+		"""An open tab on the origin of url for reuse_same_origin_tabs, the focused one first, then the most recently used.
+
+		Only tabs opened during the session are reused, the browser's own tabs (open when we connected) are left alone.
+		"""
+		origin = _url_origin(url)
+		if origin is None or self.session_manager is None:
+			return None
+		created_target_ids = set(self.get_created_target_ids())
+		candidates = [
+			target.target_id
+			for target in self.session_manager.get_all_page_targets()
+			if target.target_id in created_target_ids and _url_origin(target.url) == origin
+		]
+		if not candidates:
+			return None
+		if self.agent_focus_target_id in candidates:
+			return self.agent_focus_target_id
+		return max(candidates, key=lambda target_id: self._tab_last_used.get(target_id, 0.0))
+
+	async def _close_least_recently_used_tabs(self, keep_target_id: TargetID) -> list[TargetID]:
+		"""Close the least recently used tabs opened during the session until at most max_agent_tabs are left.
+
+		Returns the ids of the closed tabs. The browser's own tabs (open when we connected) are never closed.
+		"""
+		limit = self.browser_profile.max_agent_tabs
+		if limit is None:
+			return []
+		created_target_ids = self.get_created_target_ids()
+		excess = len(created_target_ids) - limit
+		if excess <= 0:
+			return []
+		candidates = sorted(
+			(target_id for target_id in created_target_ids if target_id != keep_target_id),
+			key=lambda target_id: self._tab_last_used.get(target_id, 0.0),
+		)
+		closed_target_ids = candidates[:excess]
+		for target_id in closed_target_ids:
+			self.logger.info(f'🧹 Closing least recently used tab #{target_id[-4:]} (max_agent_tabs={limit})')
+			await self.event_bus.dispatch(CloseTabEvent(target_id=target_id))
+		return closed_target_ids
+
 	def _attach_ws_drop_callback(self) -> None:
 		"""Attach a done callback to the CDPClient's message handler task to detect WS drops."""
 		if not self._cdp_client_root or not hasattr(self._cdp_client_root, '_message_handler_task'):
@@ -2294,6 +3361,11 @@ class BrowserSession(BaseModel):
 				f'🔌 CDP WebSocket message handler exited unexpectedly'
 				f'{f": {type(exc).__name__}: {exc}" if exc else " (connection closed)"}'
 			)
+			if self._cdp_message_too_big():
+				self.logger.warning(
+					f'🔌 The browser sent a CDP message over the limit of {self.browser_profile.cdp_max_message_size} bytes, '
+					f'raise BrowserProfile(cdp_max_message_size=...) or set it to None for no limit'
+				)
 
 			# Fire auto-reconnect as an asyncio task
 			try:
@@ -2305,7 +3377,94 @@ class BrowserSession(BaseModel):
 
 		task.add_done_callback(_on_message_handler_done)
 
-	async def get_tabs(self) -> list[TabInfo]:
+	def _cdp_message_too_big(self) -> bool:
+		"""Whether the CDP WebSocket was closed because a message exceeded the size limit (close code 1009)."""
+		ws = self._cdp_client_root.ws if self._cdp_client_root else None
+		if ws is None:
+			return False
+		close_frames = (getattr(ws.protocol, 'close_sent', None), getattr(ws.protocol, 'close_rcvd', None))
+		return any(frame is not None and frame.code == 1009 for frame in close_frames)
+
+	async def get_tabs(self, include_thumbnails: bool = False) -> list[TabInfo]:
+		"""Get information about all open tabs using cached target data.
+
+		include_thumbnails: also capture a small JPEG screenshot of each tab into TabInfo.thumbnail.
+		"""
+		tabs = await self._get_tabs()
+		if include_thumbnails:
+			await self._attach_tab_thumbnails(tabs)
+		return tabs
+
+	async def _attach_tab_thumbnails(self, tabs: list[TabInfo]) -> None:
+		"""Capture thumbnails for all tabs in parallel; tabs that fail or time out keep thumbnail=None"""
+		results = await asyncio.gather(*(self._capture_tab_thumbnail(tab) for tab in tabs), return_exceptions=True)
+		for tab, thumbnail in zip(tabs, results):
+			if isinstance(thumbnail, BaseException):
+				self.logger.debug(f'📸 Could not capture thumbnail for tab #{tab.target_id[-4:]}: {type(thumbnail).__name__}')
+				continue
+			tab.thumbnail = thumbnail
+
+	async def _capture_tab_thumbnail(self, tab: TabInfo, width: int = 320, timeout: float = 3.0) -> str | None:
+		"""Capture a viewport screenshot of a tab (without focusing it), scaled down to `width` px"""
+		if is_new_tab_page(tab.url) or tab.url.startswith('chrome://'):
+			return None
+
+		async def _capture() -> str | None:
+			cdp_session = await self.get_or_create_cdp_session(tab.target_id, focus=False)
+			metrics = await cdp_session.cdp_client.send.Page.getLayoutMetrics(session_id=cdp_session.session_id)
+			viewport = metrics['cssVisualViewport']
+			scale = min(1.0, width / max(viewport['clientWidth'], 1))
+			result = await cdp_session.cdp_client.send.Page.captureScreenshot(
+				params={
+					'format': 'jpeg',
+					'quality': 50,
+					'clip': {
+						'x': viewport['pageX'],
+						'y': viewport['pageY'],
+						'width': viewport['clientWidth'],
+						'height': viewport['clientHeight'],
+						'scale': scale,
+					},
+				},
+				session_id=cdp_session.session_id,
+			)
+			return result.get('data')
+
+		return await asyncio.wait_for(_capture(), timeout=timeout)
+
+	async def get_markdown_in_background_tab(
+		self, url: str, extract_links: bool = False, timeout: float = 15.0
+	) -> tuple[str, dict[str, Any]]:
+		"""Open url in a background tab, extract its clean markdown and close the tab again.
+
+		The agent focus is not changed. URLs blocked by allowed_domains / prohibited_domains raise URLNotAllowedError.
+		"""
+		from browser_use.dom.markdown_extractor import extract_clean_markdown
+		from browser_use.dom.service import DomService
+
+		if self._security_watchdog and not self._security_watchdog._is_url_allowed(url):
+			raise URLNotAllowedError(f'Navigation to non-allowed URL {url} blocked by security policy')
+
+		target_id = await self._cdp_create_new_page('about:blank', background=True)
+		try:
+
+			async def _load_and_extract() -> tuple[str, dict[str, Any]]:
+				# A readiness timeout is not fatal: extract whatever has rendered so far
+				loading_status = await self._navigate_and_wait(url, target_id, timeout=timeout)
+				if loading_status:
+					self.logger.debug(f'Background tab {_log_pretty_url(url)} not fully loaded: {loading_status}')
+				return await extract_clean_markdown(
+					dom_service=DomService(self), target_id=target_id, extract_links=extract_links, base_url=url
+				)
+
+			return await asyncio.wait_for(_load_and_extract(), timeout=timeout + 15.0)
+		finally:
+			try:
+				await self._cdp_close_page(target_id)
+			except Exception as e:
+				self.logger.debug(f'Failed to close background tab #{target_id[-4:]}: {type(e).__name__}: {e}')
+
+	async def _get_tabs(self) -> list[TabInfo]:
 		"""Get information about all open tabs using cached target data."""
 		tabs = []
 
@@ -2313,8 +3472,10 @@ class BrowserSession(BaseModel):
 		if not self.session_manager:
 			return tabs
 
-		# Get all page targets from SessionManager
+		# Get all page targets from SessionManager, only the attached one when the agent is restricted to it
 		page_targets = self.session_manager.get_all_page_targets()
+		if self._pinned_target_id:
+			page_targets = [target for target in page_targets if target.target_id == self._pinned_target_id]
 
 		for i, target in enumerate(page_targets):
 			target_id = target.target_id
@@ -2354,11 +3515,15 @@ class BrowserSession(BaseModel):
 				else:
 					title = ''
 
+			history = self.session_manager.get_navigation_history(target_id)
+			if history and history[-1] == url:
+				history = history[:-1]
 			tab_info = TabInfo(
 				target_id=target_id,
 				url=url,
 				title=title,
 				parent_target_id=None,
+				recent_urls=history[-RECENT_TAB_URLS:],
 			)
 			tabs.append(tab_info)
 
@@ -2397,6 +3562,23 @@ class BrowserSession(BaseModel):
 			return target.title
 		return 'Unknown page title'
 
+	async def get_page_text(self, max_chars: int | None = 4000, main_content_only: bool = True) -> str:
+		"""Get the text of the current page as markdown, the same content the extract action reads.
+
+		Args:
+			max_chars: Character budget, the text is cut at the last line break before it. None for no limit.
+			main_content_only: Keep only the main content, without navigation, headers, footers, sidebars and banners.
+		"""
+		from browser_use.dom.markdown_extractor import extract_clean_markdown
+
+		text, _ = await extract_clean_markdown(browser_session=self, main_content_only=main_content_only)
+		if max_chars is None or len(text) <= max_chars:
+			return text
+		cut = text.rfind('\n', 0, max_chars)
+		if cut < max_chars // 2:
+			cut = max_chars
+		return f'{text[:cut].rstrip()}\n... [{len(text) - cut:,} more characters]'
+
 	async def navigate_to(self, url: str, new_tab: bool = False) -> None:
 		"""Navigate to a URL using the standard event system.
 
@@ -2895,6 +4077,18 @@ class BrowserSession(BaseModel):
 
 		return None
 
+	async def remove_text_match_highlight(self) -> None:
+		"""Remove the outline find_text drew around a text match."""
+		self._text_match_highlighted = False
+		try:
+			cdp_session = await self.get_or_create_cdp_session()
+			await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={'expression': "document.querySelectorAll('[data-browser-use-text-match]').forEach((el) => el.remove())"},
+				session_id=cdp_session.session_id,
+			)
+		except Exception as e:
+			self.logger.debug(f'Failed to remove text match highlight: {e}')
+
 	async def highlight_interaction_element(self, node: 'EnhancedDOMTreeNode') -> None:
 		"""Temporarily highlight an element during interaction for user visibility.
 
@@ -3372,6 +4566,53 @@ class BrowserSession(BaseModel):
 		"""
 		return self._downloaded_files.copy()
 
+	def get_page_errors(self, target_id: TargetID | None = None) -> list[str]:
+		"""Get the JS and network errors collected for a tab since the last clear_page_errors() call.
+
+		Repeated messages are merged into one entry with a count, e.g. "[network] ... (x3)".
+		Defaults to the tab the agent is focused on.
+		"""
+		target_id = target_id or self.agent_focus_target_id
+		counts: dict[str, int] = {}
+		for error_target_id, message in self._page_errors:
+			if error_target_id == target_id:
+				counts[message] = counts.get(message, 0) + 1
+		return [message if count == 1 else f'{message} (x{count})' for message, count in counts.items()]
+
+	def clear_page_errors(self) -> None:
+		"""Forget collected page errors, called once they have been shown to the agent."""
+		self._page_errors.clear()
+
+	async def enable_network_capture(self, patterns: list[str] | None = None, max_body_size: int = 100_000) -> None:
+		"""Record XHR/fetch requests and their response bodies, read them back with get_network_requests().
+
+		Patterns are globs on the full URL ('*/api/*') or substrings ('graphql'), None records every XHR/fetch request.
+		Response bodies longer than max_body_size characters are cut off.
+		"""
+		if self._network_capture_watchdog is None:
+			raise RuntimeError('Network capture is only available once the browser session has been started')
+		await self._network_capture_watchdog.enable(patterns=patterns, max_body_size=max_body_size)
+
+	def disable_network_capture(self) -> None:
+		"""Stop recording requests, requests captured so far stay available."""
+		if self._network_capture_watchdog is not None:
+			self._network_capture_watchdog.disable()
+
+	@property
+	def is_network_capture_enabled(self) -> bool:
+		return self._network_capture_watchdog is not None and self._network_capture_watchdog.enabled
+
+	def get_network_requests(self, url_pattern: str | None = None, limit: int | None = None) -> list[CapturedNetworkRequest]:
+		"""Get captured requests oldest first, optionally only those matching url_pattern and only the last `limit` ones."""
+		if self._network_capture_watchdog is None:
+			return []
+		return self._network_capture_watchdog.get_requests(url_pattern=url_pattern, limit=limit)
+
+	def clear_network_requests(self) -> None:
+		"""Forget captured requests."""
+		if self._network_capture_watchdog is not None:
+			self._network_capture_watchdog.clear()
+
 	# endregion - ========== Helper Methods ==========
 
 	# region - ========== CDP-based replacements for browser_context operations ==========
@@ -3424,7 +4665,8 @@ class BrowserSession(BaseModel):
 	async def _cdp_create_new_page(self, url: str = 'about:blank', background: bool = False, new_window: bool = False) -> str:
 		"""Create a new page/tab using CDP Target.createTarget. Returns target ID."""
 		# Only include newWindow when True, letting Chrome auto-create window as needed
-		params = CreateTargetParameters(url=url, background=background)
+		params = self._new_target_params(url)
+		params['background'] = background
 		if new_window:
 			params['newWindow'] = True
 		# Use the root CDP client to create tabs at the browser level
@@ -3441,6 +4683,8 @@ class BrowserSession(BaseModel):
 
 	async def _cdp_get_cookies(self) -> list[Cookie]:
 		"""Get cookies using CDP Network.getCookies."""
+		if self._browser_context_id:
+			return await self.cookies()
 		cdp_session = await self.get_or_create_cdp_session(target_id=None)
 		result = await asyncio.wait_for(
 			cdp_session.cdp_client.send.Storage.getCookies(session_id=cdp_session.session_id), timeout=8.0
@@ -3452,6 +4696,12 @@ class BrowserSession(BaseModel):
 		if not self.agent_focus_target_id or not cookies:
 			return
 
+		if self._browser_context_id:
+			await self.cdp_client.send.Storage.setCookies(
+				params={'cookies': cookies, 'browserContextId': self._browser_context_id}  # type: ignore[arg-type]
+			)
+			return
+
 		cdp_session = await self.get_or_create_cdp_session(target_id=None)
 		# Storage.setCookies expects params dict with 'cookies' key
 		await cdp_session.cdp_client.send.Storage.setCookies(
@@ -3501,7 +4751,13 @@ class BrowserSession(BaseModel):
 		)
 
 	async def _cdp_set_viewport(
-		self, width: int, height: int, device_scale_factor: float = 1.0, mobile: bool = False, target_id: str | None = None
+		self,
+		width: int,
+		height: int,
+		device_scale_factor: float = 1.0,
+		mobile: bool = False,
+		has_touch: bool = False,
+		target_id: str | None = None,
 	) -> None:
 		"""Set viewport using CDP Emulation.setDeviceMetricsOverride.
 
@@ -3510,6 +4766,7 @@ class BrowserSession(BaseModel):
 			height: Viewport height
 			device_scale_factor: Device scale factor (default 1.0)
 			mobile: Whether to emulate mobile device (default False)
+			has_touch: Whether to emulate a touch screen via Emulation.setTouchEmulationEnabled (default False)
 			target_id: Optional target ID to set viewport for. If not provided, uses agent_focus.
 		"""
 		if target_id:
@@ -3530,6 +4787,11 @@ class BrowserSession(BaseModel):
 			params={'width': width, 'height': height, 'deviceScaleFactor': device_scale_factor, 'mobile': mobile},
 			session_id=cdp_session.session_id,
 		)
+		if has_touch:
+			await cdp_session.cdp_client.send.Emulation.setTouchEmulationEnabled(
+				params={'enabled': True, 'maxTouchPoints': 5},
+				session_id=cdp_session.session_id,
+			)
 
 	async def _cdp_get_origins(self) -> list[dict[str, Any]]:
 		"""Get origins with localStorage and sessionStorage using CDP."""
@@ -3619,6 +4881,48 @@ class BrowserSession(BaseModel):
 			'origins': origins,
 		}
 
+	async def _cdp_set_storage_state(self, storage: dict[str, Any]) -> None:
+		"""Apply storage state cookies now and its localStorage/sessionStorage on the next load of each origin."""
+		import json
+
+		if storage.get('cookies'):
+			# Playwright exports session cookies with expires=0/-1. CDP treats expires=0 as expired.
+			# Normalize session cookies by omitting expires
+			normalized_cookies: list[Cookie] = []
+			for cookie in storage['cookies']:
+				if not isinstance(cookie, dict):
+					normalized_cookies.append(cookie)  # type: ignore[arg-type]
+					continue
+				c = dict(cookie)
+				expires = c.get('expires')
+				if expires in (0, 0.0, -1, -1.0):
+					c.pop('expires', None)
+				normalized_cookies.append(Cookie(**c))
+			await self._cdp_set_cookies(normalized_cookies)
+
+		for origin in storage.get('origins') or []:
+			origin_value = origin.get('origin')
+			if not origin_value:
+				continue
+
+			# Scope storage restoration to its origin to avoid cross-site pollution.
+			for storage_name in ('localStorage', 'sessionStorage'):
+				if not origin.get(storage_name):
+					continue
+				lines = [
+					f'window.{storage_name}.setItem({json.dumps(item["name"])}, {json.dumps(item["value"])});'
+					for item in origin[storage_name]
+				]
+				script = (
+					'(function(){\n'
+					f'  if (window.location && window.location.origin !== {json.dumps(origin_value)}) return;\n'
+					'  try {\n'
+					f'    {" ".join(lines)}\n'
+					'  } catch (e) {}\n'
+					'})();'
+				)
+				await self._cdp_add_init_script(script)
+
 	async def _cdp_navigate(self, url: str, target_id: TargetID | None = None) -> None:
 		"""Navigate to URL using CDP Page.navigate."""
 		# Use provided target_id or fall back to agent_focus_target_id
@@ -4016,6 +5320,17 @@ class BrowserSession(BaseModel):
 
 		cdp_session = await self.get_or_create_cdp_session()
 
+		if full_page and not clip:
+			metrics = await cdp_session.cdp_client.send.Page.getLayoutMetrics(session_id=cdp_session.session_id)
+			content_size = metrics.get('cssContentSize') or metrics.get('contentSize') or {}
+			if content_size.get('height', 0) > SCREENSHOT_CHUNK_HEIGHT:
+				screenshot_data = await self._take_screenshot_in_chunks(
+					cdp_session, content_size['width'], content_size['height'], format, quality
+				)
+				if path:
+					Path(path).write_bytes(screenshot_data)
+				return screenshot_data
+
 		# Build parameters dict explicitly to satisfy TypedDict expectations
 		params: CaptureScreenshotParameters = {
 			'format': format,
@@ -4048,6 +5363,52 @@ class BrowserSession(BaseModel):
 
 		return screenshot_data
 
+	async def _take_screenshot_in_chunks(
+		self, cdp_session: CDPSession, width: float, height: float, format: str, quality: int | None
+	) -> bytes:
+		"""Capture the page in strips of SCREENSHOT_CHUNK_HEIGHT CSS pixels and stitch them into one image.
+
+		Pages taller than SCREENSHOT_MAX_HEIGHT are captured up to that height.
+		"""
+		import base64
+		from io import BytesIO
+
+		from PIL import Image
+
+		if height > SCREENSHOT_MAX_HEIGHT:
+			self.logger.warning(
+				f'📸 Page is {height:.0f}px tall, the full-page screenshot is cut off at {SCREENSHOT_MAX_HEIGHT}px'
+			)
+			height = SCREENSHOT_MAX_HEIGHT
+		canvas: Image.Image | None = None
+		scale = 1.0  # device pixels per CSS pixel, known from the first strip
+		top = 0.0
+		while top < height:
+			strip_height = min(SCREENSHOT_CHUNK_HEIGHT, height - top)
+			result = await cdp_session.cdp_client.send.Page.captureScreenshot(
+				params={
+					# Lossless strips, the stitched image is encoded once in the requested format
+					'format': 'png',
+					'captureBeyondViewport': True,
+					'clip': {'x': 0, 'y': top, 'width': width, 'height': strip_height, 'scale': 1},
+				},
+				session_id=cdp_session.session_id,
+			)
+			if not result or 'data' not in result:
+				raise Exception('Screenshot failed - no data returned')
+			strip = Image.open(BytesIO(base64.b64decode(result['data'])))
+			if canvas is None:
+				scale = strip.width / width
+				canvas = Image.new('RGB', (strip.width, round(height * scale)), 'white')
+			canvas.paste(strip, (0, round(top * scale)))
+			top += strip_height
+
+		assert canvas is not None
+		buffer = BytesIO()
+		save_options: dict[str, Any] = {'quality': quality} if quality is not None and format == 'jpeg' else {}
+		canvas.save(buffer, format={'jpeg': 'JPEG', 'webp': 'WEBP'}.get(format, 'PNG'), **save_options)
+		return buffer.getvalue()
+
 	async def screenshot_element(
 		self,
 		selector: str,
@@ -0,0 +1,81 @@
+"""Per-consumer view of a BrowserSession that is shared by several agents or background tasks."""
+
+from typing import TYPE_CHECKING, Any
+
+from cdp_use.cdp.target import TargetID
+
+if TYPE_CHECKING:
+	from browser_use.browser.session import BrowserSession
+	from browser_use.dom.views import EnhancedDOMTreeNode
+
+
+class SessionHandle:
+	"""Exclusive, per-consumer access to a shared BrowserSession.
+
+	The session keeps a single agent focus and a single cached selector map, so two agents using it at the same
+	time would switch each other's tabs and click elements by indices taken from the other agent's page. Each
+	consumer gets its own handle and does its browser work inside `async with handle:`. Entering waits for the
+	session lock, then switches the session back to the consumer's tab and selector map if another handle used
+	the session in the meantime. Leaving saves them for next time and releases the lock.
+
+	Hold the handle only while talking to the browser (reading state, running actions), not during LLM calls,
+	so that consumers interleave step by step.
+	"""
+
+	def __init__(self, browser_session: 'BrowserSession', name: str):
+		self.browser_session = browser_session
+		self.name = name
+		self.focus_target_id: TargetID | None = None
+		self._state_summary: Any = None
+		self._selector_map: dict[int, 'EnhancedDOMTreeNode'] = {}
+		self._depth = 0
+
+	async def __aenter__(self) -> 'BrowserSession':
+		if self._depth:
+			# Already held by this consumer, e.g. a helper called from inside a step
+			self._depth += 1
+			return self.browser_session
+
+		await self.browser_session._consumer_lock.acquire()
+		self._depth = 1
+		try:
+			await self._restore()
+		except BaseException:
+			self._depth = 0
+			self.browser_session._consumer_lock.release()
+			raise
+		return self.browser_session
+
+	async def __aexit__(self, *exc_info: object) -> None:
+		self._depth -= 1
+		if self._depth:
+			return
+		try:
+			self._save()
+		finally:
+			self.browser_session._last_handle = self
+			self.browser_session._consumer_lock.release()
+
+	async def _restore(self) -> None:
+		session = self.browser_session
+		if session._last_handle is None or session._last_handle is self or self.focus_target_id is None:
+			# Nobody else used the session since we left it (or this is our first turn): its state is ours
+			return
+
+		target = session.session_manager.get_target(self.focus_target_id) if session.session_manager else None
+		if target is None:
+			# Our tab was closed by another consumer, continue on whatever the session is focused on
+			self.focus_target_id = None
+			return
+
+		if session.agent_focus_target_id != self.focus_target_id:
+			await session.get_or_create_cdp_session(self.focus_target_id, focus=True)
+		session._cached_browser_state_summary = self._state_summary
+		session.update_cached_selector_map(dict(self._selector_map))
+
+	def _save(self) -> None:
+		session = self.browser_session
+		self.focus_target_id = session.agent_focus_target_id
+		self._state_summary = session._cached_browser_state_summary
+		# Copied because the session clears its cached map in place when the focus changes
+		self._selector_map = dict(session._cached_selector_map)
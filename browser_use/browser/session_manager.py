@@ -10,12 +10,16 @@ from typing import TYPE_CHECKING, Any
 
 from cdp_use.cdp.target import AttachedToTargetEvent, DetachedFromTargetEvent, SessionID, TargetID
 
-from browser_use.utils import create_task_with_error_handling
+from browser_use.utils import create_task_with_error_handling, is_new_tab_page
 
 if TYPE_CHECKING:
+	from browser_use.browser.cdp_events import CDPEvent
 	from browser_use.browser.session import BrowserSession, CDPSession, Target
 
 
+NAVIGATION_HISTORY_SIZE = 20  # URLs remembered per tab
+
+
 class SessionManager:
 	"""Event-driven CDP session manager.
 
@@ -52,6 +56,11 @@ class SessionManager:
 		# leave every tab but the most recently attached one without lifecycle events.
 		self._lifecycle_events: dict[TargetID, deque[dict[str, Any]]] = {}
 
+		# URLs each tab's main frame navigated to, oldest first. Fed through subscribe_cdp_event() rather than
+		# cdp_client.register so the HAR watchdog's own Page.frameNavigated handler is not replaced.
+		self._navigation_history: dict[TargetID, deque[str]] = {}
+		self._navigation_subscription_ids: list[str] = []
+
 		self._lock = asyncio.Lock()
 		self._recovery_lock = asyncio.Lock()
 
@@ -130,6 +139,10 @@ class SessionManager:
 		cdp_client.register.Target.detachedFromTarget(on_detached)
 		cdp_client.register.Target.targetInfoChanged(on_target_info_changed)
 		cdp_client.register.Page.lifecycleEvent(on_lifecycle_event)
+		self._navigation_subscription_ids = [
+			self.browser_session.subscribe_cdp_event('Page.frameNavigated', self._on_navigation),
+			self.browser_session.subscribe_cdp_event('Page.navigatedWithinDocument', self._on_navigation),
+		]
 
 		self.logger.debug('[SessionManager] Event monitoring started')
 
@@ -144,6 +157,32 @@ class SessionManager:
 			self._lifecycle_events[target_id] = events
 		return events
 
+	def _on_navigation(self, event: 'CDPEvent') -> None:
+		"""Record main frame navigations (Page.frameNavigated) and same-document ones (pushState, hash changes)."""
+		if not event.target_id:
+			return
+		if event.method == 'Page.frameNavigated':
+			frame = event.params.get('frame', {})
+			if frame.get('parentId'):
+				return
+			url = frame.get('url', '')
+		else:
+			# A page target's main frame id is its target id
+			if event.params.get('frameId') != event.target_id:
+				return
+			url = event.params.get('url', '')
+		target = self._targets.get(event.target_id)
+		if not url or is_new_tab_page(url) or (target is not None and target.target_type not in ('page', 'tab')):
+			return
+		history = self._navigation_history.setdefault(event.target_id, deque(maxlen=NAVIGATION_HISTORY_SIZE))
+		# Reloads are not new visits
+		if not history or history[-1] != url:
+			history.append(url)
+
+	def get_navigation_history(self, target_id: TargetID) -> list[str]:
+		"""URLs the tab has visited, oldest first, consecutive duplicates collapsed."""
+		return list(self._navigation_history.get(target_id, ()))
+
 	def _get_session_for_target(self, target_id: TargetID) -> 'CDPSession | None':
 		"""Internal: Get ANY valid session for a target (picks first available).
 
@@ -213,6 +252,14 @@ class SessionManager:
 			self._sessions.clear()
 			self._target_sessions.clear()
 			self._session_to_target.clear()
+			self._navigation_history.clear()
+
+		for subscription_id in self._navigation_subscription_ids:
+			try:
+				self.browser_session.unsubscribe_cdp_event(subscription_id)
+			except ValueError:
+				pass
+		self._navigation_subscription_ids = []
 
 		self.logger.info('[SessionManager] Cleared all owned data (targets, sessions, mappings)')
 
@@ -423,6 +470,15 @@ class SessionManager:
 			)
 			return
 
+		# Tabs of other browser contexts belong to other sessions sharing the browser, see isolated_context
+		context_id = self.browser_session.browser_context_id
+		if context_id and target_info.get('browserContextId') != context_id:
+			try:
+				await self.browser_session._cdp_client_root.send.Target.detachFromTarget(params={'sessionId': session_id})
+			except Exception:
+				pass
+			return
+
 		# Enable auto-attach for this session's children (do this FIRST, outside lock)
 		try:
 			await self.browser_session._cdp_client_root.send.Target.setAutoAttach(
@@ -475,15 +531,13 @@ class SessionManager:
 		# Add to sessions dict
 		self._sessions[session_id] = cdp_session
 
-		# If proxy auth is configured, enable Fetch auth handling on this session
+		# If proxy auth or HTTP credentials are configured, enable Fetch auth handling on this session
 		# Avoids overwriting Target.attachedToTarget handlers elsewhere
 		try:
-			proxy_cfg = self.browser_session.browser_profile.proxy
-			username = proxy_cfg.username if proxy_cfg else None
-			password = proxy_cfg.password if proxy_cfg else None
-			if username and password:
+			fetch_params = self.browser_session._fetch_auth_params()
+			if fetch_params is not None:
 				await cdp_session.cdp_client.send.Fetch.enable(
-					params={'handleAuthRequests': True},
+					params=fetch_params,
 					session_id=cdp_session.session_id,
 				)
 				self.logger.debug(f'[SessionManager] Fetch.enable(handleAuthRequests=True) on session {session_id[:8]}...')
@@ -592,6 +646,7 @@ class SessionManager:
 					# Clean up tracking
 					del self._target_sessions[target_id]
 					self._lifecycle_events.pop(target_id, None)
+					self._navigation_history.pop(target_id, None)
 			else:
 				# Target not tracked - already removed or never attached
 				self.logger.debug(
@@ -804,9 +859,12 @@ class SessionManager:
 
 		# Just attach to ALL existing targets - Chrome fires attachedToTarget events
 		# The on_attached handler (via create_task) does ALL the work
+		context_id = self.browser_session.browser_context_id
 		for target in existing_targets:
 			target_id = target['targetId']
 			target_type = target.get('type', 'unknown')
+			if context_id and target.get('browserContextId') != context_id:
+				continue
 
 			try:
 				# Just attach - event handler does everything
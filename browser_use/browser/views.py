@@ -1,5 +1,5 @@
 from dataclasses import dataclass, field
-from typing import Any
+from typing import TYPE_CHECKING, Any, Literal
 
 from bubus import BaseEvent
 from cdp_use.cdp.target import TargetID
@@ -7,6 +7,9 @@ from pydantic import AliasChoices, BaseModel, ConfigDict, Field, field_serialize
 
 from browser_use.dom.views import DOMInteractedElement, SerializedDOMState
 
+if TYPE_CHECKING:
+	from browser_use.screenshots.service import ScreenshotStore
+
 # Known placeholder image data for about:blank pages - a 4x4 white PNG
 PLACEHOLDER_4PX_SCREENSHOT = (
 	'iVBORw0KGgoAAAANSUhEUgAAAAQAAAAECAIAAAAmkwkpAAAAFElEQVR4nGP8//8/AwwwMSAB3BwAlm4DBfIlvvkAAAAASUVORK5CYII='
@@ -31,6 +34,8 @@ class TabInfo(BaseModel):
 	parent_target_id: TargetID | None = Field(
 		default=None, serialization_alias='parent_tab_id', validation_alias=AliasChoices('parent_tab_id', 'parent_target_id')
 	)  # parent page that contains this popup or cross-origin iframe
+	thumbnail: str | None = Field(default=None, exclude=True, repr=False)  # base64 JPEG, only when requested
+	recent_urls: list[str] = Field(default_factory=list)  # URLs this tab visited before the current one, oldest first
 
 	@field_serializer('target_id')
 	def serialize_target_id(self, target_id: TargetID, _info: Any) -> str:
@@ -62,6 +67,9 @@ class PageInfo(BaseModel):
 	pixels_left: int
 	pixels_right: int
 
+	# Device pixels per CSS pixel, screenshots are captured in device pixels
+	device_pixel_ratio: float = 1.0
+
 	# Page statistics are now computed dynamically instead of stored
 
 
@@ -105,10 +113,12 @@ class BrowserStateSummary:
 	pixels_below: int = 0
 	browser_errors: list[str] = field(default_factory=list)
 	is_pdf_viewer: bool = False  # Whether the current page is a PDF viewer
+	page_notice: str | None = None  # Description of pages without a usable DOM (browser internal pages, PDF viewer)
 	recent_events: str | None = None  # Text summary of recent browser events
 	pending_network_requests: list[NetworkRequest] = field(default_factory=list)  # Currently loading network requests
 	pagination_buttons: list[PaginationButton] = field(default_factory=list)  # Detected pagination buttons
 	closed_popup_messages: list[str] = field(default_factory=list)  # Messages from auto-closed JavaScript dialogs
+	page_errors: list[str] = field(default_factory=list)  # JS exceptions, console errors and failed requests since last step
 
 
 @dataclass
@@ -121,10 +131,15 @@ class BrowserStateHistory:
 	interacted_element: list[DOMInteractedElement | None] | list[None]
 	screenshot_path: str | None = None
 
-	def get_screenshot(self) -> str | None:
-		"""Load screenshot from disk and return as base64 string"""
+	def get_screenshot(self, screenshot_store: 'ScreenshotStore | None' = None) -> str | None:
+		"""Load screenshot as base64 string, through screenshot_store when it keeps them off the local disk (S3, GCS)"""
 		if not self.screenshot_path:
 			return None
+		if screenshot_store is not None and not screenshot_store.is_local:
+			try:
+				return screenshot_store.get_screenshot_sync(self.screenshot_path)
+			except Exception:
+				return None
 
 		import base64
 		from pathlib import Path
@@ -150,6 +165,61 @@ class BrowserStateHistory:
 		return data
 
 
+class CapturedNetworkRequest(BaseModel):
+	"""An XHR/fetch request recorded by network capture, with its response body"""
+
+	url: str
+	method: str
+	resource_type: str
+	status: int | None = None
+	mime_type: str | None = None
+	request_body: str | None = None
+	body: str | None = None
+	body_truncated: bool = False
+	error: str | None = None
+	target_id: TargetID | None = None
+
+
+class DropdownOption(BaseModel):
+	"""An option of a native <select>, ARIA listbox/menu or custom dropdown, as listed by dropdown_options"""
+
+	text: str
+	value: str  # what select_dropdown also matches, the option text for dropdowns without values
+	index: int
+	selected: bool = False
+	disabled: bool = False
+	group: str | None = None  # label of the <optgroup> or ARIA group the option is in
+
+
+class TabRecovery(BaseModel):
+	"""What BrowserSession.recover_unhealthy_tab() did about a crashed, unexpectedly blank or missing agent tab"""
+
+	problem: Literal['crashed', 'blank', 'missing']
+	action: Literal['reloaded', 'reload_failed', 'renavigated', 'new_tab']
+	url: str | None = None  # the page the agent was on before the problem
+	target_id: str | None = None  # the tab the agent is on after the recovery
+
+	@property
+	def message(self) -> str:
+		page = self.url or 'a blank page'
+		tab = f' (tab_id: {self.target_id[-4:]})' if self.target_id else ''
+		if self.problem == 'crashed' and self.action == 'reloaded':
+			what = f'The tab crashed and was reloaded on {page}.'
+		elif self.action == 'reload_failed':
+			# Only for sessions restricted to one tab (restrict_to_target), which can't move to a new tab
+			return (
+				f'The tab crashed and is still unresponsive after reloading {page}. This session is restricted to this tab, '
+				'so it was not replaced. Wait a moment and check the page again, or finish with what you have.'
+			)
+		elif self.problem == 'crashed':
+			what = f'The tab crashed and could not be reloaded, it was replaced by a new tab{tab} on {page}.'
+		elif self.problem == 'blank':
+			what = f'The tab was lost and the browser fell back to a blank tab, it was navigated back to {page}.'
+		else:
+			what = f'No usable tab was left, a new tab{tab} was opened on {page}.'
+		return f'{what} Check the page before continuing, anything typed into it before was lost.'
+
+
 class BrowserError(Exception):
 	"""Browser error with structured memory for LLM context management.
 
@@ -158,6 +228,9 @@ class BrowserError(Exception):
 	- long_term_memory: Persistent error information stored across steps
 	"""
 
+	# Stable name of the failure kind, copied to ActionResult.error_type so callers don't have to parse messages
+	error_type: str = 'browser_error'
+
 	message: str
 	short_term_memory: str | None = None
 	long_term_memory: str | None = None
@@ -199,3 +272,58 @@ class BrowserError(Exception):
 
 class URLNotAllowedError(BrowserError):
 	"""Error raised when a URL is not allowed"""
+
+	error_type = 'url_not_allowed'
+
+
+class ElementNotFoundError(BrowserError):
+	"""The element is not on the page anymore (stale index or detached node), usually because the page changed"""
+
+	error_type = 'element_not_found'
+
+
+class TargetNotFoundError(BrowserError, ValueError):
+	"""The tab or iframe target has detached or never existed"""
+
+	error_type = 'target_not_found'
+
+
+class NavigationError(BrowserError, RuntimeError):
+	"""The page could not be loaded, e.g. DNS, connection or certificate errors reported by Page.navigate"""
+
+	error_type = 'navigation_failed'
+
+
+class NavigationTimeoutError(NavigationError, TimeoutError):
+	"""The browser did not respond to the navigation in time"""
+
+	error_type = 'navigation_timeout'
+
+
+class BrowserDisconnectedError(BrowserError, ConnectionError):
+	"""The CDP connection to the browser is gone and could not be re-established"""
+
+	error_type = 'browser_disconnected'
+
+
+_CONNECTION_ERROR_MARKERS = (
+	'websocket connection closed',
+	'connection closed',
+	'browser has been closed',
+	'browser closed',
+	'no browser',
+	'no close frame received',
+)
+
+
+def is_connection_error(error: BaseException) -> bool:
+	"""Whether an error means the CDP websocket to the browser is gone, rather than a failure of the command itself.
+
+	Connection errors can be recovered from by reconnecting and retrying the step, other errors can't.
+	"""
+	from websockets.exceptions import ConnectionClosed
+
+	if isinstance(error, (ConnectionError, ConnectionClosed)):
+		return True
+	error_str = str(error).lower()
+	return any(marker in error_str for marker in _CONNECTION_ERROR_MARKERS)
@@ -10,6 +10,7 @@ from bubus import BaseEvent, EventBus
 from pydantic import BaseModel, ConfigDict, Field
 
 from browser_use.browser.session import BrowserSession
+from browser_use.browser.views import BrowserDisconnectedError
 
 
 class BaseWatchdog(BaseModel):
@@ -105,13 +106,13 @@ class BaseWatchdog(BaseModel):
 						try:
 							await asyncio.wait_for(browser_session._reconnect_event.wait(), timeout=wait_timeout)
 						except TimeoutError:
-							raise ConnectionError(
+							raise BrowserDisconnectedError(
 								f'[{watchdog_class_name}.{actual_handler.__name__}] '
 								f'Reconnection wait timed out after {wait_timeout}s'
 							)
 						# After wait: check if reconnection actually succeeded
 						if not browser_session.is_cdp_connected:
-							raise ConnectionError(
+							raise BrowserDisconnectedError(
 								f'[{watchdog_class_name}.{actual_handler.__name__}] Reconnection failed — CDP still not connected'
 							)
 						# Reconnection succeeded — fall through to execute handler normally
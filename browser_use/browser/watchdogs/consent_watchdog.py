@@ -0,0 +1,191 @@
+"""Watchdog that detects cookie consent banners and clicks their reject or accept button."""
+
+import json
+from typing import TYPE_CHECKING, ClassVar
+
+from bubus import BaseEvent
+
+from browser_use.browser.events import DismissConsentBannerEvent
+from browser_use.browser.watchdog_base import BaseWatchdog
+
+if TYPE_CHECKING:
+	from browser_use.browser.session import CDPSession
+
+# Known consent management platforms: a container selector that identifies the banner, and the buttons to click.
+# Only these framework-specific buttons inside the framework's container are ever clicked.
+# shadow_hosts lists elements whose open shadow root contains the banner (Usercentrics renders inside one).
+# frame_urls marks platforms that render the banner in their own cross-origin iframe, they are only searched there.
+CONSENT_FRAMEWORKS: list[dict] = [
+	{
+		'name': 'OneTrust',
+		'container': '#onetrust-banner-sdk, #onetrust-consent-sdk',
+		'reject': ['#onetrust-reject-all-handler', '.ot-pc-refuse-all-handler'],
+		'accept': ['#onetrust-accept-btn-handler', '#accept-recommended-btn-handler'],
+	},
+	{
+		'name': 'Didomi',
+		'container': '#didomi-notice, #didomi-host',
+		'reject': ['#didomi-notice-disagree-button', '.didomi-continue-without-agreeing'],
+		'accept': ['#didomi-notice-agree-button'],
+	},
+	{
+		'name': 'Cookiebot',
+		'container': '#CybotCookiebotDialog',
+		'reject': ['#CybotCookiebotDialogBodyButtonDecline'],
+		'accept': ['#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll', '#CybotCookiebotDialogBodyButtonAccept'],
+	},
+	{
+		'name': 'Usercentrics',
+		'container': '#uc-center-container, [data-testid="uc-default-banner"]',
+		'shadow_hosts': ['#usercentrics-root', '#usercentrics-cmp-ui'],
+		'reject': ['[data-testid="uc-deny-all-button"]'],
+		'accept': ['[data-testid="uc-accept-all-button"]'],
+	},
+	{
+		'name': 'Quantcast',
+		'container': '.qc-cmp2-container, #qc-cmp2-ui',
+		'reject': [],
+		'accept': ['.qc-cmp2-summary-buttons button[mode="primary"]'],
+	},
+	{
+		'name': 'TrustArc',
+		'container': '#truste-consent-track, #truste-consent-content',
+		'reject': ['#truste-consent-required'],
+		'accept': ['#truste-consent-button'],
+	},
+	{
+		'name': 'Complianz',
+		'container': '.cmplz-cookiebanner',
+		'reject': ['.cmplz-btn.cmplz-deny'],
+		'accept': ['.cmplz-btn.cmplz-accept'],
+	},
+	{
+		'name': 'CookieYes',
+		'container': '.cky-consent-container',
+		'reject': ['.cky-btn-reject'],
+		'accept': ['.cky-btn-accept'],
+	},
+	{
+		'name': 'Sourcepoint',
+		'container': 'body',
+		'frame_urls': ['privacy-mgmt.com', 'sp-prod.net'],
+		'reject': ['button.sp_choice_type_13', 'button.sp_choice_type_REJECT_ALL'],
+		'accept': ['button.sp_choice_type_11', 'button.sp_choice_type_ACCEPT_ALL'],
+	},
+]
+
+_DISMISS_CONSENT_JS = """
+(function(frameworks, policy) {
+	const isVisible = (el) => {
+		if (!el || !el.getClientRects().length) return false;
+		const style = getComputedStyle(el);
+		return style.visibility !== 'hidden' && style.display !== 'none' && style.opacity !== '0';
+	};
+	const findButton = (container, selectors) => {
+		for (const selector of selectors) {
+			const button = container.querySelector(selector);
+			if (isVisible(button)) return button;
+		}
+		return null;
+	};
+	const searchRoot = (root) => {
+		for (const framework of frameworks) {
+			const roots = [root];
+			for (const host of framework.shadow_hosts || []) {
+				const hostEl = root.querySelector(host);
+				if (hostEl && hostEl.shadowRoot) roots.push(hostEl.shadowRoot);
+			}
+			for (const searchIn of roots) {
+				const container = searchIn.querySelector(framework.container);
+				if (!container || !isVisible(container)) continue;
+				const button = findButton(container, framework[policy]);
+				if (button) {
+					button.click();
+					return {framework: framework.name, button: (button.innerText || button.value || '').trim().slice(0, 50)};
+				}
+			}
+		}
+		return null;
+	};
+	const result = searchRoot(document);
+	if (result) return result;
+	for (const frame of document.querySelectorAll('iframe')) {
+		let frameDocument = null;
+		try { frameDocument = frame.contentDocument; } catch (e) {}
+		if (!frameDocument) continue;
+		const frameResult = searchRoot(frameDocument);
+		if (frameResult) return frameResult;
+	}
+	return null;
+})
+"""
+
+
+class ConsentWatchdog(BaseWatchdog):
+	"""Dismisses cookie consent banners of common consent management platforms.
+
+	The banner is looked up in the focused tab (including open shadow roots and same-origin iframes) and then in
+	the cross-origin CMP iframes of that tab. Only the known buttons of a detected framework are clicked, and with
+	the 'reject' policy never an accept button, so a banner without a reject option is left for the agent to handle.
+	"""
+
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [DismissConsentBannerEvent]
+	EMITS: ClassVar[list[type[BaseEvent]]] = []
+
+	async def on_DismissConsentBannerEvent(self, event: DismissConsentBannerEvent) -> str | None:
+		"""Click the reject/accept button of a detected consent banner and describe what was done."""
+		cdp_session = await self.browser_session.get_or_create_cdp_session(focus=False)
+		in_page = [framework for framework in CONSENT_FRAMEWORKS if not framework.get('frame_urls')]
+		searches = [(cdp_session, in_page)] + await self._cmp_frame_searches(cdp_session)
+
+		for session, frameworks in searches:
+			expression = f'{_DISMISS_CONSENT_JS}({json.dumps(frameworks)}, {json.dumps(event.policy)})'
+			try:
+				result = await session.cdp_client.send.Runtime.evaluate(
+					params={'expression': expression, 'returnByValue': True}, session_id=session.session_id
+				)
+			except Exception as e:
+				self.logger.debug(f'[ConsentWatchdog] Consent banner check failed: {e}')
+				continue
+			clicked = result.get('result', {}).get('value')
+			if clicked:
+				verb = 'Rejected non-essential cookies' if event.policy == 'reject' else 'Accepted cookies'
+				label = f' ("{clicked["button"]}")' if clicked.get('button') else ''
+				description = f'{verb} in the {clicked["framework"]} consent banner{label}'
+				self.logger.info(f'🍪 {description}')
+				return description
+
+		return None
+
+	async def _cmp_frame_searches(self, cdp_session: 'CDPSession') -> list[tuple['CDPSession', list[dict]]]:
+		"""The cross-origin CMP iframes of the focused tab, with the frameworks to look for in each."""
+		try:
+			frame_tree = await cdp_session.cdp_client.send.Page.getFrameTree(session_id=cdp_session.session_id)
+		except Exception as e:
+			self.logger.debug(f'[ConsentWatchdog] Could not read the frame tree of the focused tab: {e}')
+			return []
+		tab_frame_ids: set[str] = set()
+		nodes = [frame_tree['frameTree']]
+		while nodes:
+			node = nodes.pop()
+			tab_frame_ids.add(node['frame']['id'])
+			nodes.extend(node.get('childFrames', []))
+
+		searches: list[tuple[CDPSession, list[dict]]] = []
+		for target in await self.browser_session._cdp_get_all_pages(include_pages=False, include_iframes=True):
+			url = target.get('url', '')
+			frameworks = [
+				framework for framework in CONSENT_FRAMEWORKS if any(marker in url for marker in framework.get('frame_urls', []))
+			]
+			if not frameworks:
+				continue
+			try:
+				frame_session = await self.browser_session.get_or_create_cdp_session(target['targetId'], focus=False)
+				frame = (await frame_session.cdp_client.send.Page.getFrameTree(session_id=frame_session.session_id))['frameTree']
+			except Exception as e:
+				self.logger.debug(f'[ConsentWatchdog] Could not attach to consent iframe {url[:80]}: {e}')
+				continue
+			# Other tabs may show the same CMP, only the iframes embedded in the focused tab are searched
+			if frame['frame'].get('parentId') in tab_frame_ids:
+				searches.append((frame_session, frameworks))
+		return searches
@@ -13,6 +13,7 @@ from pydantic import Field, PrivateAttr
 from browser_use.browser.events import (
 	BrowserConnectedEvent,
 	BrowserErrorEvent,
+	BrowserReconnectedEvent,
 	BrowserStoppedEvent,
 	TabClosedEvent,
 	TabCreatedEvent,
@@ -41,6 +42,7 @@ class CrashWatchdog(BaseWatchdog):
 	# Event contracts
 	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [
 		BrowserConnectedEvent,
+		BrowserReconnectedEvent,
 		BrowserStoppedEvent,
 		TabCreatedEvent,
 		TabClosedEvent,
@@ -72,6 +74,11 @@ class CrashWatchdog(BaseWatchdog):
 		# logger.debug('[CrashWatchdog] Browser stopped, ending monitoring')
 		await self._stop_monitoring()
 
+	async def on_BrowserReconnectedEvent(self, event: BrowserReconnectedEvent) -> None:
+		"""Listeners were registered on the old websocket, attach again when tabs are re-announced."""
+		self._targets_with_listeners.clear()
+		self._active_requests.clear()
+
 	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
 		"""Attach to new tab."""
 		assert self.browser_session.agent_focus_target_id is not None, 'No current target ID'
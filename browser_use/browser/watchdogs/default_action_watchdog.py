@@ -1,12 +1,17 @@
 """Default browser action handlers using CDP."""
 
 import asyncio
+import difflib
 import json
 import os
+import random
+import re
+from typing import Any
 
 from cdp_use.cdp.input.commands import DispatchKeyEventParameters
 
-from browser_use.actor.utils import get_key_info
+from browser_use.actor.utils import describe_occluder, find_clickable_point, get_click_candidate_points, get_key_info
+from browser_use.browser.cdp_batch import send_pipelined
 from browser_use.browser.events import (
 	ClickCoordinateEvent,
 	ClickElementEvent,
@@ -22,7 +27,7 @@ from browser_use.browser.events import (
 	UploadFileEvent,
 	WaitEvent,
 )
-from browser_use.browser.views import BrowserError, URLNotAllowedError
+from browser_use.browser.views import BrowserError, ElementNotFoundError, URLNotAllowedError
 from browser_use.browser.watchdog_base import BaseWatchdog
 from browser_use.dom.service import EnhancedDOMTreeNode
 from browser_use.observability import observe_debug
@@ -38,6 +43,233 @@ ScrollEvent.model_rebuild()
 UploadFileEvent.model_rebuild()
 
 
+# Normalize key names from common aliases
+_KEY_ALIASES = {
+	'ctrl': 'Control',
+	'control': 'Control',
+	'alt': 'Alt',
+	'option': 'Alt',
+	'meta': 'Meta',
+	'cmd': 'Meta',
+	'command': 'Meta',
+	'shift': 'Shift',
+	'enter': 'Enter',
+	'return': 'Enter',
+	'tab': 'Tab',
+	'delete': 'Delete',
+	'backspace': 'Backspace',
+	'escape': 'Escape',
+	'esc': 'Escape',
+	'space': ' ',
+	'up': 'ArrowUp',
+	'down': 'ArrowDown',
+	'left': 'ArrowLeft',
+	'right': 'ArrowRight',
+	'pageup': 'PageUp',
+	'pagedown': 'PageDown',
+	'home': 'Home',
+	'end': 'End',
+}
+
+# Keys that are pressed, anything else is typed as text
+_SPECIAL_KEYS = {
+	'Enter',
+	'Tab',
+	'Delete',
+	'Backspace',
+	'Escape',
+	'ArrowUp',
+	'ArrowDown',
+	'ArrowLeft',
+	'ArrowRight',
+	'PageUp',
+	'PageDown',
+	'Home',
+	'End',
+	'Control',
+	'Alt',
+	'Meta',
+	'Shift',
+	'F1',
+	'F2',
+	'F3',
+	'F4',
+	'F5',
+	'F6',
+	'F7',
+	'F8',
+	'F9',
+	'F10',
+	'F11',
+	'F12',
+}
+
+
+_KEY_REPEAT_PATTERN = re.compile(r'(.+?)\*(\d+)')
+_MAX_KEY_REPEAT = 50
+
+
+def _is_key_or_shortcut(token: str) -> bool:
+	"""Whether token names a key (Tab, esc) or a shortcut (Control+a), rather than text."""
+	parts = token.split('+')
+	if any(not part for part in parts):
+		return False
+	*modifiers, main_key = parts
+	for part in modifiers:
+		if _KEY_ALIASES.get(part.lower(), part) not in ('Control', 'Alt', 'Meta', 'Shift'):
+			return False
+	if modifiers and len(main_key) == 1:
+		return True
+	return main_key.lower() in _KEY_ALIASES or main_key in _SPECIAL_KEYS
+
+
+def parse_key_sequence(keys: str) -> list[str] | None:
+	"""Split a key sequence like 'Tab Tab Enter' or 'ArrowDown*3 Enter' into single key presses.
+
+	Returns None when keys is a single key or shortcut, or text to type, which are sent as a whole.
+	"""
+	tokens = keys.split()
+	presses: list[str] = []
+	for token in tokens:
+		match = _KEY_REPEAT_PATTERN.fullmatch(token)
+		key, count = (match.group(1), int(match.group(2))) if match else (token, 1)
+		if not _is_key_or_shortcut(key):
+			return None
+		presses.extend([key] * min(count, _MAX_KEY_REPEAT))
+	if presses == [keys.strip()]:
+		return None
+	return presses
+
+
+_MIN_FUZZY_OPTION_RATIO = 0.75  # difflib ratio for an option to count as a fuzzy match of the requested text
+_MAX_DROPDOWN_SCROLLS = 40  # Scroll steps through a virtualized option list before giving up
+_DROPDOWN_OPEN_TIMEOUT = 2.0  # seconds to wait for a custom dropdown's listbox after clicking it
+
+
+def _normalize_option_text(text: str) -> str:
+	return ' '.join(text.lower().split())
+
+
+def _format_dropdown_option(option: dict[str, Any]) -> str:
+	"""One line per option for the LLM, e.g. '2: text="Paris", value="par", group="France" (selected)'."""
+	# JSON encoding shows the exact strings select_dropdown matches
+	line = f'{option["index"]}: text={json.dumps(option["text"])}, value={json.dumps(option["value"])}'
+	if option.get('group'):
+		line += f', group={json.dumps(option["group"])}'
+	flags = [flag for flag in ('selected', 'disabled') if option.get(flag)]
+	return line + (f' ({", ".join(flags)})' if flags else '')
+
+
+def match_dropdown_option(target: str, options: list[str]) -> tuple[int, str] | None:
+	"""Pick the option best matching target: an exact match, else a partial one, else the closest fuzzy one.
+
+	Returns the option's position and the kind of match ('exact', 'partial' or 'fuzzy'), None if nothing is close.
+	"""
+	wanted = _normalize_option_text(target)
+	if not wanted:
+		return None
+	normalized = [_normalize_option_text(option) for option in options]
+	for i, option in enumerate(normalized):
+		if option == wanted:
+			return i, 'exact'
+	partial = [i for i, option in enumerate(normalized) if wanted in option]
+	if partial:
+		# The shortest option containing the text, e.g. 'Germany' over 'East Germany (former)'
+		return min(partial, key=lambda i: len(normalized[i])), 'partial'
+	best_index, best_ratio = -1, 0.0
+	for i, option in enumerate(normalized):
+		ratio = difflib.SequenceMatcher(None, wanted, option).ratio()
+		if ratio > best_ratio:
+			best_index, best_ratio = i, ratio
+	if best_ratio >= _MIN_FUZZY_OPTION_RATIO:
+		return best_index, 'fuzzy'
+	return None
+
+
+# Finds the listbox a custom dropdown (React-select, MUI, Headless UI, ...) opened and works on its rendered options:
+# op 'collect' lists them, 'reveal' scrolls option i into view and returns its center, 'scroll' pages the list down
+_CUSTOM_DROPDOWN_JS = """
+function(op, arg) {
+	const trigger = this;
+	const isVisible = el => {
+		const rect = el.getBoundingClientRect();
+		const style = getComputedStyle(el);
+		return rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none';
+	};
+	// ARIA roles and known library classes are trusted first, class names of unstyled libraries (React-select's
+	// 'select__menu' / 'css-1nmdiq5-option', ids like 'react-select-2-option-0') only when nothing else matches
+	const exactOptions = '[role="option"], [role="menuitem"], [role="menuitemradio"]';
+	const exactListboxes = '[role="listbox"], [role="menu"], .MuiPopover-paper, .MuiAutocomplete-popper';
+	const hasClassSuffix = (el, suffix) => Array.from(el.classList).some(
+		name => name.endsWith('-' + suffix) || name.endsWith('__' + suffix)
+	);
+	const isPartialOption = el => hasClassSuffix(el, 'option') || /-option-\\d+$/.test(el.id);
+	const partialOptionsIn = container => Array.from(container.querySelectorAll('[class*="option"], [id*="-option-"]'))
+		.filter(el => isPartialOption(el) && isVisible(el));
+
+	const ids = [];
+	for (const el of [trigger, ...trigger.querySelectorAll('[aria-controls], [aria-owns]')]) {
+		for (const attr of ['aria-controls', 'aria-owns']) {
+			ids.push(...(el.getAttribute(attr) || '').split(/\\s+/).filter(Boolean));
+		}
+	}
+	let listbox = ids.map(id => document.getElementById(id)).find(el => el && isVisible(el));
+	if (!listbox) {
+		const exact = Array.from(document.querySelectorAll(exactListboxes)).filter(isVisible);
+		const partial = Array.from(document.querySelectorAll('[class*="menu"]'))
+			.filter(el => hasClassSuffix(el, 'menu') && isVisible(el));
+		listbox = exact.find(el => el.querySelector(exactOptions))
+			|| exact.find(el => el.getAttribute('role') === 'listbox')
+			|| partial.find(el => partialOptionsIn(el).length);
+	}
+	if (!listbox) return { listbox: false };
+
+	let options = Array.from(listbox.querySelectorAll(exactOptions)).filter(isVisible);
+	if (!options.length) options = partialOptionsIn(listbox);
+	options = options.filter(option => !options.some(other => other !== option && option.contains(other)));
+
+	if (op === 'reveal') {
+		const option = options[arg];
+		if (!option) return { listbox: true, found: false };
+		option.scrollIntoView({ block: 'nearest' });
+		const rect = option.getBoundingClientRect();
+		return { listbox: true, found: true, x: rect.left + rect.width / 2, y: rect.top + rect.height / 2 };
+	}
+	if (op === 'scroll') {
+		let container = null;
+		for (const el of [listbox, ...listbox.querySelectorAll('*')]) {
+			const overflow = getComputedStyle(el).overflowY;
+			if ((overflow === 'auto' || overflow === 'scroll') && el.scrollHeight > el.clientHeight + 1) {
+				container = el;
+				break;
+			}
+		}
+		if (!container) return { listbox: true, scrolled: false };
+		const before = container.scrollTop;
+		container.scrollTop = before + Math.max(container.clientHeight * 0.8, 40);
+		return { listbox: true, scrolled: container.scrollTop > before };
+	}
+	return {
+		listbox: true,
+		options: options.map(option => (option.innerText || option.textContent || '').trim().replace(/\\s+/g, ' ')),
+	};
+}
+"""
+
+# Text a custom dropdown shows for its current value: its own text and inputs, and that of a few ancestors
+_DROPDOWN_SHOWN_VALUE_JS = """
+function() {
+	const parts = [];
+	let el = this;
+	for (let depth = 0; el && depth < 4; depth++, el = el.parentElement) {
+		parts.push(el.innerText || '', el.value || '');
+		el.querySelectorAll('input').forEach(input => parts.push(input.value || ''));
+	}
+	return parts.join(' ');
+}
+"""
+
+
 class DefaultActionWatchdog(BaseWatchdog):
 	"""Handles default browser actions like click, type, and scroll using CDP."""
 
@@ -453,10 +685,9 @@ class DefaultActionWatchdog(BaseWatchdog):
 		try:
 			# Use the provided node
 			element_node = event.node
-			index_for_logging = self.browser_session.get_selector_index(element_node)
 
-			# Check if this is index 0 or a falsy index - type to the page (whatever has focus)
-			if not element_node.backend_node_id or element_node.backend_node_id == 0:
+			# Check if there is no node, or this is index 0 or a falsy index - type to the page (whatever has focus)
+			if element_node is None or not element_node.backend_node_id or element_node.backend_node_id == 0:
 				# Type to the page without focusing any specific element
 				await self._type_to_page(event.text)
 				# Log with sensitive data protection
@@ -469,6 +700,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 					self.logger.info(f'⌨️ Typed "{event.text}" to the page (current focus)')
 				return None  # No coordinates available for page typing
 			else:
+				index_for_logging = self.browser_session.get_selector_index(element_node)
 				try:
 					# Try to type to the specific element
 					input_metadata = await self._input_text_element_node_impl(
@@ -556,7 +788,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 					return None
 
 			# Perform target-level scroll
-			await self._scroll_with_cdp_gesture(pixels)
+			await self._scroll_with_cdp_gesture(pixels, event.coordinate_x, event.coordinate_y)
 
 			# Note: We don't clear cached state here - let multi_act handle DOM change detection
 			# by explicitly rebuilding and comparing when needed
@@ -584,121 +816,20 @@ class DefaultActionWatchdog(BaseWatchdog):
 		"""
 		try:
 			session_id = cdp_session.session_id
-
-			# Get target element info for comparison
-			target_result = await cdp_session.cdp_client.send.DOM.resolveNode(
-				params={'backendNodeId': backend_node_id}, session_id=session_id
-			)
-
-			if 'object' not in target_result:
-				self.logger.debug('Could not resolve target element, assuming occluded')
-				return True
-
-			object_id = target_result['object']['objectId']
-
-			# Get target element info
-			target_info_result = await cdp_session.cdp_client.send.Runtime.callFunctionOn(
-				params={
-					'objectId': object_id,
-					'functionDeclaration': """
-					function() {
-						const getElementInfo = (el) => {
-							return {
-								tagName: el.tagName,
-								id: el.id || '',
-								className: el.className || '',
-								textContent: (el.textContent || '').substring(0, 100)
-							};
-						};
-
-
-						const elementAtPoint = document.elementFromPoint(arguments[0], arguments[1]);
-						if (!elementAtPoint) {
-							return { targetInfo: getElementInfo(this), isClickable: false };
-						}
-
-
-						// Simple containment-based clickability logic
-						let isClickable = this === elementAtPoint ||
-							this.contains(elementAtPoint) ||
-							elementAtPoint.contains(this);
-
-						// Check label-input associations when containment check fails
-						if (!isClickable) {
-							const target = this;
-							const atPoint = elementAtPoint;
-
-							// Case 1: target is <input>, atPoint is its associated <label> (or child of that label)
-							if (target.tagName === 'INPUT' && target.id) {
-								const escapedId = CSS.escape(target.id);
-								const assocLabel = document.querySelector('label[for="' + escapedId + '"]');
-								if (assocLabel && (assocLabel === atPoint || assocLabel.contains(atPoint))) {
-									isClickable = true;
-								}
-							}
-
-							// Case 2: target is <input>, atPoint is inside a <label> ancestor that wraps the target
-							if (!isClickable && target.tagName === 'INPUT') {
-								let ancestor = atPoint;
-								for (let i = 0; i < 3 && ancestor; i++) {
-									if (ancestor.tagName === 'LABEL' && ancestor.contains(target)) {
-										isClickable = true;
-										break;
-									}
-									ancestor = ancestor.parentElement;
-								}
-							}
-
-							// Case 3: target is <label>, atPoint is the associated <input>
-							if (!isClickable && target.tagName === 'LABEL') {
-								if (target.htmlFor && atPoint.tagName === 'INPUT' && atPoint.id === target.htmlFor) {
-									isClickable = true;
-								}
-								// Also check if atPoint is an input inside the label
-								if (!isClickable && atPoint.tagName === 'INPUT' && target.contains(atPoint)) {
-									isClickable = true;
-								}
-							}
-						}
-
-						return {
-							targetInfo: getElementInfo(this),
-							elementAtPointInfo: getElementInfo(elementAtPoint),
-							isClickable: isClickable
-						};
-					}
-					""",
-					'arguments': [{'value': x}, {'value': y}],
-					'returnByValue': True,
-				},
-				session_id=session_id,
-			)
-
-			if 'result' not in target_info_result or 'value' not in target_info_result['result']:
-				self.logger.debug('Could not get target element info, assuming occluded')
-				return True
-
-			target_data = target_info_result['result']['value']
-			is_clickable = target_data.get('isClickable', False)
-
-			if is_clickable:
-				self.logger.debug('Element is clickable (target, contained, or semantically related)')
-				return False
-			else:
-				target_info = target_data.get('targetInfo', {})
-				element_at_point_info = target_data.get('elementAtPointInfo', {})
-				self.logger.debug(
-					f'Element is occluded. Target: {target_info.get("tagName", "unknown")} '
-					f'(id={target_info.get("id", "none")}), '
-					f'ElementAtPoint: {element_at_point_info.get("tagName", "unknown")} '
-					f'(id={element_at_point_info.get("id", "none")})'
-				)
-				return True
-
+			point, occluder = await find_clickable_point(cdp_session.cdp_client, session_id, backend_node_id, [(x, y)])
+		except RuntimeError:
+			self.logger.debug('Could not resolve target element, assuming occluded')
+			return True
 		except Exception as e:
 			self.logger.debug(f'Occlusion check failed: {e}, assuming not occluded')
 			return False
 
+		if point is not None:
+			self.logger.debug('Element is clickable (target, contained, or semantically related)')
+			return False
+		self.logger.debug(f'Element is occluded by {describe_occluder(occluder)} at ({x:.0f}, {y:.0f})')
+		return True
+
 	async def _click_element_node_impl(self, element_node) -> dict | None:
 		"""
 		Click an element using pure CDP with multiple fallback methods for getting element geometry.
@@ -758,20 +889,22 @@ class DefaultActionWatchdog(BaseWatchdog):
 				except Exception as e:
 					self.logger.debug(f'Could not capture pre-click checkbox state: {e}')
 
-			# Get viewport dimensions for visibility checks
-			layout_metrics = await cdp_session.cdp_client.send.Page.getLayoutMetrics(session_id=session_id)
+			# Get viewport dimensions for visibility checks and scroll element into view FIRST before getting coordinates,
+			# pipelined into one round-trip
+			layout_metrics, scrolled = await send_pipelined(
+				cdp_session.cdp_client,
+				[('Page.getLayoutMetrics', None), ('DOM.scrollIntoViewIfNeeded', {'backendNodeId': backend_node_id})],
+				session_id,
+			)
+			if isinstance(layout_metrics, Exception):
+				raise layout_metrics
 			viewport_width = layout_metrics['layoutViewport']['clientWidth']
 			viewport_height = layout_metrics['layoutViewport']['clientHeight']
-
-			# Scroll element into view FIRST before getting coordinates
-			try:
-				await cdp_session.cdp_client.send.DOM.scrollIntoViewIfNeeded(
-					params={'backendNodeId': backend_node_id}, session_id=session_id
-				)
+			if isinstance(scrolled, Exception):
+				self.logger.debug(f'Failed to scroll element into view: {scrolled}')
+			else:
 				await asyncio.sleep(0.05)  # Wait for scroll to complete
 				self.logger.debug('Scrolled element into view before getting coordinates')
-			except Exception as e:
-				self.logger.debug(f'Failed to scroll element into view: {e}')
 
 			# Get element coordinates using the unified method AFTER scrolling
 			element_rect = await self.browser_session.get_element_coordinates(backend_node_id, cdp_session)
@@ -819,7 +952,10 @@ class DefaultActionWatchdog(BaseWatchdog):
 					)
 					await asyncio.sleep(0.05)
 					# Navigation is handled by BrowserSession via events
-					return None
+					return {
+						'js_click_fallback': True,
+						'click_note': 'The element has no visible geometry, so it was clicked via JavaScript.',
+					}
 				except Exception as js_e:
 					self.logger.warning(f'CDP JavaScript click also failed: {js_e}')
 					if 'No node with given id found' in str(js_e):
@@ -864,19 +1000,25 @@ class DefaultActionWatchdog(BaseWatchdog):
 				best_quad = quads[0]
 				self.logger.warning('No visible quad found, using first quad')
 
-			# Calculate center point of the best quad
-			center_x = sum(best_quad[i] for i in range(0, 8, 2)) / 4
-			center_y = sum(best_quad[i] for i in range(1, 8, 2)) / 4
-
-			# Ensure click point is within viewport bounds
-			center_x = max(0, min(viewport_width - 1, center_x))
-			center_y = max(0, min(viewport_height - 1, center_y))
-
-			# Check for occlusion before attempting CDP click
-			is_occluded = await self._check_element_occlusion(backend_node_id, center_x, center_y, cdp_session)
+			# Actionability check: find a point where a real click actually reaches the element instead of an overlay.
+			# Try the center of the element's visible area first, then points around it (partially covered elements).
+			candidate_points = get_click_candidate_points(best_quad, viewport_width, viewport_height)
+			center_x, center_y = candidate_points[0]
+			occluder = None
+			try:
+				click_point, occluder = await find_clickable_point(
+					cdp_session.cdp_client, session_id, backend_node_id, candidate_points
+				)
+			except Exception as e:
+				self.logger.debug(f'Occlusion check failed: {e}, assuming not occluded')
+				click_point = (center_x, center_y)
 
-			if is_occluded:
-				self.logger.debug('🚫 Element is occluded, falling back to JavaScript click')
+			if click_point is None:
+				occluder_desc = describe_occluder(occluder)
+				self.logger.debug(
+					f'🚫 Element is covered by {occluder_desc} at all {len(candidate_points)} click points, '
+					'falling back to JavaScript click'
+				)
 				try:
 					result = await cdp_session.cdp_client.send.DOM.resolveNode(
 						params={'backendNodeId': backend_node_id},
@@ -895,63 +1037,74 @@ class DefaultActionWatchdog(BaseWatchdog):
 						session_id=session_id,
 					)
 					await asyncio.sleep(0.05)
-					return None
+					return {
+						'js_click_fallback': True,
+						'click_note': f'The element was covered by {occluder_desc}, so it was clicked via JavaScript. '
+						'If nothing changed, close or scroll past the overlay and retry.',
+					}
 				except Exception as js_e:
 					self.logger.error(f'JavaScript click fallback failed: {js_e}')
 					raise Exception(f'Failed to click occluded element: {js_e}')
 
+			if click_point != (center_x, center_y):
+				self.logger.debug(f'Element center is covered, clicking at ({click_point[0]:.0f}, {click_point[1]:.0f})')
+			center_x, center_y = click_point
+
 			# Perform the click using CDP (element is not occluded)
 			try:
-				self.logger.debug(f'👆 Dragging mouse over element before clicking x: {center_x}px y: {center_y}px ...')
-				# Move mouse to element
-				await cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-					params={
-						'type': 'mouseMoved',
-						'x': center_x,
-						'y': center_y,
-					},
-					session_id=session_id,
-				)
-				await asyncio.sleep(0.05)
-
-				# Mouse down
-				self.logger.debug(f'👆🏾 Clicking x: {center_x}px y: {center_y}px ...')
-				try:
-					await asyncio.wait_for(
-						cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-							params={
-								'type': 'mousePressed',
-								'x': center_x,
-								'y': center_y,
-								'button': 'left',
-								'clickCount': 1,
-							},
-							session_id=session_id,
-						),
-						timeout=3.0,  # 3 second timeout for mousePressed
+				if self.browser_session.browser_profile.has_touch:
+					await self._tap(cdp_session, center_x, center_y, session_id)
+				else:
+					self.logger.debug(f'👆 Dragging mouse over element before clicking x: {center_x}px y: {center_y}px ...')
+					# Move mouse to element
+					await cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+						params={
+							'type': 'mouseMoved',
+							'x': center_x,
+							'y': center_y,
+						},
+						session_id=session_id,
 					)
-					await asyncio.sleep(0.08)
-				except TimeoutError:
-					self.logger.debug('⏱️ Mouse down timed out (likely due to dialog), continuing...')
-					# Don't sleep if we timed out
+					await asyncio.sleep(0.05)
 
-				# Mouse up
-				try:
-					await asyncio.wait_for(
-						cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-							params={
-								'type': 'mouseReleased',
-								'x': center_x,
-								'y': center_y,
-								'button': 'left',
-								'clickCount': 1,
-							},
-							session_id=session_id,
-						),
-						timeout=5.0,  # 5 second timeout for mouseReleased
-					)
-				except TimeoutError:
-					self.logger.debug('⏱️ Mouse up timed out (possibly due to lag or dialog popup), continuing...')
+					# Mouse down
+					self.logger.debug(f'👆🏾 Clicking x: {center_x}px y: {center_y}px ...')
+					try:
+						await asyncio.wait_for(
+							cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+								params={
+									'type': 'mousePressed',
+									'x': center_x,
+									'y': center_y,
+									'button': 'left',
+									'clickCount': 1,
+								},
+								session_id=session_id,
+							),
+							timeout=3.0,  # 3 second timeout for mousePressed
+						)
+						await asyncio.sleep(0.08)
+					except TimeoutError:
+						self.logger.debug('⏱️ Mouse down timed out (likely due to dialog), continuing...')
+						# Don't sleep if we timed out
+
+					# Mouse up
+					try:
+						await asyncio.wait_for(
+							cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+								params={
+									'type': 'mouseReleased',
+									'x': center_x,
+									'y': center_y,
+									'button': 'left',
+									'clickCount': 1,
+								},
+								session_id=session_id,
+							),
+							timeout=5.0,  # 5 second timeout for mouseReleased
+						)
+					except TimeoutError:
+						self.logger.debug('⏱️ Mouse up timed out (possibly due to lag or dialog popup), continuing...')
 
 				self.logger.debug('🖱️ Clicked successfully using x,y coordinates')
 
@@ -1056,14 +1209,16 @@ class DefaultActionWatchdog(BaseWatchdog):
 			if selector_index:
 				error_detail += f' If the page changed after navigation/interaction, the index [{selector_index}] may be stale. Get fresh browser state before retrying.'
 
-			raise BrowserError(
+			# A detached node means the page changed under the agent, report it as such so callers can re-fetch the state
+			error_class = ElementNotFoundError if 'with given id' in str(e) else BrowserError
+			raise error_class(
 				message=f'Failed to click element: {str(e)}',
 				long_term_memory=error_detail,
 			)
 
 	async def _click_on_coordinate(self, coordinate_x: int, coordinate_y: int, force: bool = False) -> dict | None:
 		"""
-		Click directly at coordinates using CDP Input.dispatchMouseEvent.
+		Click directly at coordinates using CDP Input.dispatchMouseEvent (a touch tap when has_touch is set).
 
 		Args:
 			coordinate_x: X coordinate in viewport
@@ -1078,56 +1233,59 @@ class DefaultActionWatchdog(BaseWatchdog):
 			cdp_session = await self.browser_session.get_or_create_cdp_session()
 			session_id = cdp_session.session_id
 
-			self.logger.debug(f'👆 Moving mouse to ({coordinate_x}, {coordinate_y})...')
-
-			# Move mouse to coordinates
-			await cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-				params={
-					'type': 'mouseMoved',
-					'x': coordinate_x,
-					'y': coordinate_y,
-				},
-				session_id=session_id,
-			)
-			await asyncio.sleep(0.05)
+			if self.browser_session.browser_profile.has_touch:
+				await self._tap(cdp_session, coordinate_x, coordinate_y, session_id)
+			else:
+				self.logger.debug(f'👆 Moving mouse to ({coordinate_x}, {coordinate_y})...')
 
-			# Mouse down
-			self.logger.debug(f'👆🏾 Clicking at ({coordinate_x}, {coordinate_y})...')
-			try:
-				await asyncio.wait_for(
-					cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-						params={
-							'type': 'mousePressed',
-							'x': coordinate_x,
-							'y': coordinate_y,
-							'button': 'left',
-							'clickCount': 1,
-						},
-						session_id=session_id,
-					),
-					timeout=3.0,
+				# Move mouse to coordinates
+				await cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+					params={
+						'type': 'mouseMoved',
+						'x': coordinate_x,
+						'y': coordinate_y,
+					},
+					session_id=session_id,
 				)
 				await asyncio.sleep(0.05)
-			except TimeoutError:
-				self.logger.debug('⏱️ Mouse down timed out (likely due to dialog), continuing...')
 
-			# Mouse up
-			try:
-				await asyncio.wait_for(
-					cdp_session.cdp_client.send.Input.dispatchMouseEvent(
-						params={
-							'type': 'mouseReleased',
-							'x': coordinate_x,
-							'y': coordinate_y,
-							'button': 'left',
-							'clickCount': 1,
-						},
-						session_id=session_id,
-					),
-					timeout=5.0,
-				)
-			except TimeoutError:
-				self.logger.debug('⏱️ Mouse up timed out (possibly due to lag or dialog popup), continuing...')
+				# Mouse down
+				self.logger.debug(f'👆🏾 Clicking at ({coordinate_x}, {coordinate_y})...')
+				try:
+					await asyncio.wait_for(
+						cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+							params={
+								'type': 'mousePressed',
+								'x': coordinate_x,
+								'y': coordinate_y,
+								'button': 'left',
+								'clickCount': 1,
+							},
+							session_id=session_id,
+						),
+						timeout=3.0,
+					)
+					await asyncio.sleep(0.05)
+				except TimeoutError:
+					self.logger.debug('⏱️ Mouse down timed out (likely due to dialog), continuing...')
+
+				# Mouse up
+				try:
+					await asyncio.wait_for(
+						cdp_session.cdp_client.send.Input.dispatchMouseEvent(
+							params={
+								'type': 'mouseReleased',
+								'x': coordinate_x,
+								'y': coordinate_y,
+								'button': 'left',
+								'clickCount': 1,
+							},
+							session_id=session_id,
+						),
+						timeout=5.0,
+					)
+				except TimeoutError:
+					self.logger.debug('⏱️ Mouse up timed out (possibly due to lag or dialog popup), continuing...')
 
 			self.logger.debug(f'🖱️ Clicked successfully at ({coordinate_x}, {coordinate_y})')
 
@@ -1141,6 +1299,25 @@ class DefaultActionWatchdog(BaseWatchdog):
 				long_term_memory=f'Failed to click at coordinates ({coordinate_x}, {coordinate_y}). The coordinates may be outside viewport or the page may have changed.',
 			)
 
+	async def _tap(self, cdp_session, x: float, y: float, session_id: str) -> None:
+		"""Tap at viewport coordinates with a touch gesture, the browser turns it into the click events of a real tap."""
+		self.logger.debug(f'👆 Tapping at ({x:.0f}, {y:.0f})...')
+		await cdp_session.cdp_client.send.Input.dispatchTouchEvent(
+			params={'type': 'touchStart', 'touchPoints': [{'x': x, 'y': y}]},
+			session_id=session_id,
+		)
+		await asyncio.sleep(0.05)
+		try:
+			await asyncio.wait_for(
+				cdp_session.cdp_client.send.Input.dispatchTouchEvent(
+					params={'type': 'touchEnd', 'touchPoints': []},
+					session_id=session_id,
+				),
+				timeout=5.0,
+			)
+		except TimeoutError:
+			self.logger.debug('⏱️ Touch end timed out (possibly due to lag or dialog popup), continuing...')
+
 	async def _type_to_page(self, text: str):
 		"""
 		Type text to the page (whatever element currently has focus).
@@ -1209,7 +1386,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 						session_id=cdp_session.session_id,
 					)
 				# Add 10ms delay between keystrokes
-				await asyncio.sleep(0.010)
+				await self._typing_pause(0.010)
 		except Exception as e:
 			raise Exception(f'Failed to type to page: {str(e)}')
 
@@ -1992,7 +2169,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 						)
 
 				# Small delay between characters to look human (realistic typing speed)
-				await asyncio.sleep(0.001)
+				await self._typing_pause(0.001)
 
 			# Step 4: Trigger framework-aware DOM events after typing completion
 			# Modern JavaScript frameworks (React, Vue, Angular) rely on these events
@@ -2201,12 +2378,14 @@ class DefaultActionWatchdog(BaseWatchdog):
 			self.logger.warning(f'⚠️ Failed to trigger framework events: {type(e).__name__}: {e}')
 			# Don't raise - framework events are a best-effort enhancement
 
-	async def _scroll_with_cdp_gesture(self, pixels: int) -> bool:
+	async def _scroll_with_cdp_gesture(self, pixels: int, x: int | None = None, y: int | None = None) -> bool:
 		"""
 		Scroll using CDP Input.synthesizeScrollGesture to simulate realistic scroll gesture.
 
 		Args:
 			pixels: Number of pixels to scroll (positive = down, negative = up)
+			x: Viewport x coordinate to scroll at, scrolls whatever container is under it (default: viewport center)
+			y: Viewport y coordinate to scroll at (default: viewport center)
 
 		Returns:
 			True if successful, False if failed
@@ -2226,15 +2405,16 @@ class DefaultActionWatchdog(BaseWatchdog):
 				viewport_width = layout_metrics['layoutViewport']['clientWidth']
 				viewport_height = layout_metrics['layoutViewport']['clientHeight']
 
-			# Calculate center of viewport
-			center_x = viewport_width / 2
-			center_y = viewport_height / 2
+			# Scroll at the given point, or the center of the viewport
+			center_x = x if x is not None else viewport_width / 2
+			center_y = y if y is not None else viewport_height / 2
 
 			# For scroll gesture, positive yDistance scrolls up, negative scrolls down
 			# (opposite of mouseWheel deltaY convention)
 			y_distance = -pixels
 
 			# Synthesize scroll gesture - use very high speed for near-instant scrolling
+			# With touch emulation the gesture is a swipe, so touch-only scrollers (carousels, bottom sheets) react to it too
 			await cdp_client.send.Input.synthesizeScrollGesture(
 				params={
 					'x': center_x,
@@ -2242,6 +2422,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 					'xDistance': 0,
 					'yDistance': y_distance,
 					'speed': 50000,  # pixels per second (high = near-instant scroll)
+					'gestureSourceType': 'touch' if self.browser_session.browser_profile.has_touch else 'default',
 				},
 				session_id=session_id,
 			)
@@ -2476,206 +2657,162 @@ class DefaultActionWatchdog(BaseWatchdog):
 		"""Handle send keys request with CDP."""
 		cdp_session = await self.browser_session.get_or_create_cdp_session(focus=True)
 		try:
-			# Normalize key names from common aliases
-			key_aliases = {
-				'ctrl': 'Control',
-				'control': 'Control',
-				'alt': 'Alt',
-				'option': 'Alt',
-				'meta': 'Meta',
-				'cmd': 'Meta',
-				'command': 'Meta',
-				'shift': 'Shift',
-				'enter': 'Enter',
-				'return': 'Enter',
-				'tab': 'Tab',
-				'delete': 'Delete',
-				'backspace': 'Backspace',
-				'escape': 'Escape',
-				'esc': 'Escape',
-				'space': ' ',
-				'up': 'ArrowUp',
-				'down': 'ArrowDown',
-				'left': 'ArrowLeft',
-				'right': 'ArrowRight',
-				'pageup': 'PageUp',
-				'pagedown': 'PageDown',
-				'home': 'Home',
-				'end': 'End',
-			}
-
-			# Parse and normalize the key string
-			keys = event.keys
-			if '+' in keys:
-				# Handle key combinations like "ctrl+a"
-				parts = keys.split('+')
-				normalized_parts = []
-				for part in parts:
-					part_lower = part.strip().lower()
-					normalized = key_aliases.get(part_lower, part)
-					normalized_parts.append(normalized)
-				normalized_keys = '+'.join(normalized_parts)
+			sequence = parse_key_sequence(event.keys)
+			if sequence is None:
+				await self._send_key_or_text(cdp_session, event.keys)
 			else:
-				# Single key
-				keys_lower = keys.strip().lower()
-				normalized_keys = key_aliases.get(keys_lower, keys)
-
-			# Handle key combinations like "Control+A"
-			if '+' in normalized_keys:
-				parts = normalized_keys.split('+')
-				modifiers = parts[:-1]
-				main_key = parts[-1]
-
-				# Calculate modifier bitmask
-				modifier_value = 0
-				modifier_map = {'Alt': 1, 'Control': 2, 'Meta': 4, 'Shift': 8}
-				for mod in modifiers:
-					modifier_value |= modifier_map.get(mod, 0)
-
-				# Press modifier keys
-				for mod in modifiers:
-					await self._dispatch_key_event(cdp_session, 'keyDown', mod)
-
-				# Press main key with modifiers bitmask
-				await self._dispatch_key_event(cdp_session, 'keyDown', main_key, modifier_value)
-
-				await self._dispatch_key_event(cdp_session, 'keyUp', main_key, modifier_value)
-
-				# Release modifier keys
-				for mod in reversed(modifiers):
-					await self._dispatch_key_event(cdp_session, 'keyUp', mod)
-			else:
-				# Check if this is a text string or special key
-				special_keys = {
-					'Enter',
-					'Tab',
-					'Delete',
-					'Backspace',
-					'Escape',
-					'ArrowUp',
-					'ArrowDown',
-					'ArrowLeft',
-					'ArrowRight',
-					'PageUp',
-					'PageDown',
-					'Home',
-					'End',
-					'Control',
-					'Alt',
-					'Meta',
-					'Shift',
-					'F1',
-					'F2',
-					'F3',
-					'F4',
-					'F5',
-					'F6',
-					'F7',
-					'F8',
-					'F9',
-					'F10',
-					'F11',
-					'F12',
-				}
+				for i, key in enumerate(sequence):
+					if i > 0:
+						await self._typing_pause(0.05)  # let focus changes and dropdowns react between keys
+					await self._send_key_or_text(cdp_session, key)
 
-				# If it's a special key, use original logic
-				if normalized_keys in special_keys:
-					await self._dispatch_key_event(cdp_session, 'keyDown', normalized_keys)
-					# For Enter key, also dispatch a char event to trigger keypress listeners
-					if normalized_keys == 'Enter':
-						await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
-							params={
-								'type': 'char',
-								'text': '\r',
-								'key': 'Enter',
-							},
-							session_id=cdp_session.session_id,
-						)
-					await self._dispatch_key_event(cdp_session, 'keyUp', normalized_keys)
-				else:
-					# It's text (single character or string) - send each character as text input
-					# This is crucial for text to appear in focused input fields
-					for char in normalized_keys:
-						# Special-case newline characters to dispatch as Enter
-						if char in ('\n', '\r'):
-							await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
-								params={
-									'type': 'rawKeyDown',
-									'windowsVirtualKeyCode': 13,
-									'unmodifiedText': '\r',
-									'text': '\r',
-								},
-								session_id=cdp_session.session_id,
-							)
-							await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
-								params={
-									'type': 'char',
-									'windowsVirtualKeyCode': 13,
-									'unmodifiedText': '\r',
-									'text': '\r',
-								},
-								session_id=cdp_session.session_id,
-							)
-							await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
-								params={
-									'type': 'keyUp',
-									'windowsVirtualKeyCode': 13,
-									'unmodifiedText': '\r',
-									'text': '\r',
-								},
-								session_id=cdp_session.session_id,
-							)
-							continue
+			self.logger.info(f'⌨️ Sent keys: {event.keys}')
 
-						# Get proper modifiers and key info for the character
-						modifiers, vk_code, base_key = self._get_char_modifiers_and_vk(char)
-						key_code = self._get_key_code_for_char(base_key)
+			# Note: We don't clear cached state on Enter; multi_act will detect DOM changes
+			# and rebuild explicitly. We still wait briefly for potential navigation.
+			if 'enter' in event.keys.lower() or 'return' in event.keys.lower():
+				await asyncio.sleep(0.1)
+		except Exception as e:
+			raise
 
-						# Send keyDown
+	async def _typing_pause(self, default: float) -> None:
+		"""Wait between typed keys: the profile's typing_delay plus random jitter, or the given default."""
+		profile = self.browser_session.browser_profile
+		delay = profile.typing_delay if profile.typing_delay is not None else default
+		if profile.typing_delay_jitter:
+			delay += random.uniform(0, profile.typing_delay_jitter)
+		await asyncio.sleep(delay)
+
+	async def _send_key_or_text(self, cdp_session, keys: str) -> None:
+		"""Press a single key or shortcut (Enter, Control+a), or type keys as text character by character."""
+		# Parse and normalize the key string
+		if '+' in keys:
+			# Handle key combinations like "ctrl+a"
+			parts = keys.split('+')
+			normalized_parts = []
+			for part in parts:
+				part_lower = part.strip().lower()
+				normalized = _KEY_ALIASES.get(part_lower, part)
+				normalized_parts.append(normalized)
+			normalized_keys = '+'.join(normalized_parts)
+		else:
+			# Single key
+			keys_lower = keys.strip().lower()
+			normalized_keys = _KEY_ALIASES.get(keys_lower, keys)
+
+		# Handle key combinations like "Control+A"
+		if '+' in normalized_keys:
+			parts = normalized_keys.split('+')
+			modifiers = parts[:-1]
+			main_key = parts[-1]
+
+			# Calculate modifier bitmask
+			modifier_value = 0
+			modifier_map = {'Alt': 1, 'Control': 2, 'Meta': 4, 'Shift': 8}
+			for mod in modifiers:
+				modifier_value |= modifier_map.get(mod, 0)
+
+			# Press modifier keys
+			for mod in modifiers:
+				await self._dispatch_key_event(cdp_session, 'keyDown', mod)
+
+			# Press main key with modifiers bitmask
+			await self._dispatch_key_event(cdp_session, 'keyDown', main_key, modifier_value)
+
+			await self._dispatch_key_event(cdp_session, 'keyUp', main_key, modifier_value)
+
+			# Release modifier keys
+			for mod in reversed(modifiers):
+				await self._dispatch_key_event(cdp_session, 'keyUp', mod)
+		else:
+			# If it's a special key, use original logic
+			if normalized_keys in _SPECIAL_KEYS:
+				await self._dispatch_key_event(cdp_session, 'keyDown', normalized_keys)
+				# For Enter key, also dispatch a char event to trigger keypress listeners
+				if normalized_keys == 'Enter':
+					await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
+						params={
+							'type': 'char',
+							'text': '\r',
+							'key': 'Enter',
+						},
+						session_id=cdp_session.session_id,
+					)
+				await self._dispatch_key_event(cdp_session, 'keyUp', normalized_keys)
+			else:
+				# It's text (single character or string) - send each character as text input
+				# This is crucial for text to appear in focused input fields
+				for char in normalized_keys:
+					# Special-case newline characters to dispatch as Enter
+					if char in ('\n', '\r'):
 						await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
 							params={
-								'type': 'keyDown',
-								'key': base_key,
-								'code': key_code,
-								'modifiers': modifiers,
-								'windowsVirtualKeyCode': vk_code,
+								'type': 'rawKeyDown',
+								'windowsVirtualKeyCode': 13,
+								'unmodifiedText': '\r',
+								'text': '\r',
 							},
 							session_id=cdp_session.session_id,
 						)
-
-						# Send char event with text - this is what makes text appear in input fields
 						await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
 							params={
 								'type': 'char',
-								'text': char,
-								'key': char,
+								'windowsVirtualKeyCode': 13,
+								'unmodifiedText': '\r',
+								'text': '\r',
 							},
 							session_id=cdp_session.session_id,
 						)
-
-						# Send keyUp
 						await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
 							params={
 								'type': 'keyUp',
-								'key': base_key,
-								'code': key_code,
-								'modifiers': modifiers,
-								'windowsVirtualKeyCode': vk_code,
+								'windowsVirtualKeyCode': 13,
+								'unmodifiedText': '\r',
+								'text': '\r',
 							},
 							session_id=cdp_session.session_id,
 						)
+						continue
 
-						# Small delay between characters (10ms)
-						await asyncio.sleep(0.010)
+					# Get proper modifiers and key info for the character
+					modifiers, vk_code, base_key = self._get_char_modifiers_and_vk(char)
+					key_code = self._get_key_code_for_char(base_key)
 
-			self.logger.info(f'⌨️ Sent keys: {event.keys}')
+					# Send keyDown
+					await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
+						params={
+							'type': 'keyDown',
+							'key': base_key,
+							'code': key_code,
+							'modifiers': modifiers,
+							'windowsVirtualKeyCode': vk_code,
+						},
+						session_id=cdp_session.session_id,
+					)
 
-			# Note: We don't clear cached state on Enter; multi_act will detect DOM changes
-			# and rebuild explicitly. We still wait briefly for potential navigation.
-			if 'enter' in event.keys.lower() or 'return' in event.keys.lower():
-				await asyncio.sleep(0.1)
-		except Exception as e:
-			raise
+					# Send char event with text - this is what makes text appear in input fields
+					await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
+						params={
+							'type': 'char',
+							'text': char,
+							'key': char,
+						},
+						session_id=cdp_session.session_id,
+					)
+
+					# Send keyUp
+					await cdp_session.cdp_client.send.Input.dispatchKeyEvent(
+						params={
+							'type': 'keyUp',
+							'key': base_key,
+							'code': key_code,
+							'modifiers': modifiers,
+							'windowsVirtualKeyCode': vk_code,
+						},
+						session_id=cdp_session.session_id,
+					)
+
+					await self._typing_pause(0.010)
 
 	async def on_UploadFileEvent(self, event: UploadFileEvent) -> None:
 		"""Handle file upload request with CDP."""
@@ -2689,29 +2826,40 @@ class DefaultActionWatchdog(BaseWatchdog):
 				msg = f'Upload failed - element {index_for_logging} is not a file input.'
 				raise BrowserError(message=msg, long_term_memory=msg)
 
+			files = event.file_paths or [event.file_path]
+			attributes = element_node.attributes or {}
+			if len(files) > 1 and 'multiple' not in attributes and 'webkitdirectory' not in attributes:
+				msg = (
+					f'Upload failed - element {index_for_logging} accepts a single file, not {len(files)}. '
+					f'Upload one file, or use a file input that accepts multiple files.'
+				)
+				raise BrowserError(message=msg, long_term_memory=msg)
+
 			# Get CDP client and session
 			cdp_client = self.browser_session.cdp_client
 			session_id = await self._get_session_id_for_element(element_node)
 
-			# Validate file before upload
-			if os.path.exists(event.file_path):
-				file_size = os.path.getsize(event.file_path)
-				if file_size == 0:
-					msg = f'Upload failed - file {event.file_path} is empty (0 bytes).'
-					raise BrowserError(message=msg, long_term_memory=msg)
-				self.logger.debug(f'📎 File {event.file_path} validated ({file_size} bytes)')
+			# Validate files before upload
+			for file_path in files:
+				if os.path.exists(file_path):
+					file_size = os.path.getsize(file_path)
+					if file_size == 0:
+						msg = f'Upload failed - file {file_path} is empty (0 bytes).'
+						raise BrowserError(message=msg, long_term_memory=msg)
+					self.logger.debug(f'📎 File {file_path} validated ({file_size} bytes)')
 
 			# Set file(s) to upload
 			backend_node_id = element_node.backend_node_id
 			await cdp_client.send.DOM.setFileInputFiles(
 				params={
-					'files': [event.file_path],
+					'files': files,
 					'backendNodeId': backend_node_id,
 				},
 				session_id=session_id,
 			)
 
-			self.logger.info(f'📎 Uploaded file {event.file_path} to element {index_for_logging}')
+			uploaded = files[0] if len(files) == 1 else f'{len(files)} files'
+			self.logger.info(f'📎 Uploaded {uploaded} to element {index_for_logging}')
 		except Exception as e:
 			raise
 
@@ -2883,12 +3031,17 @@ class DefaultActionWatchdog(BaseWatchdog):
 					if (element.tagName.toLowerCase() === 'select') {
 						return {
 							type: 'select',
-							options: Array.from(element.options).map((opt, idx) => ({
-								text: opt.text.trim(),
-								value: opt.value,
-								index: idx,
-								selected: opt.selected
-							})),
+							options: Array.from(element.options).map((opt, idx) => {
+								const group = opt.parentElement && opt.parentElement.tagName === 'OPTGROUP' ? opt.parentElement : null;
+								return {
+									text: opt.text.trim(),
+									value: opt.value,
+									index: idx,
+									selected: opt.selected,
+									disabled: opt.disabled || (group !== null && group.disabled),
+									group: group ? group.label : null
+								};
+							}),
 							id: element.id || '',
 							name: element.name || '',
 							source: 'target'
@@ -2905,11 +3058,14 @@ class DefaultActionWatchdog(BaseWatchdog):
 						menuItems.forEach((item, idx) => {
 							const text = item.textContent ? item.textContent.trim() : '';
 							if (text) {
+								const group = item.closest('[role="group"]');
 								options.push({
 									text: text,
 									value: item.getAttribute('data-value') || text,
 									index: idx,
-									selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected')
+									selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected'),
+									disabled: item.getAttribute('aria-disabled') === 'true',
+									group: group && element.contains(group) ? group.getAttribute('aria-label') : null
 								});
 							}
 						});
@@ -2935,7 +3091,9 @@ class DefaultActionWatchdog(BaseWatchdog):
 									text: text,
 									value: item.getAttribute('data-value') || text,
 									index: idx,
-									selected: item.classList.contains('selected') || item.classList.contains('active')
+									selected: item.classList.contains('selected') || item.classList.contains('active'),
+									disabled: item.classList.contains('disabled') || item.getAttribute('aria-disabled') === 'true',
+									group: null
 								});
 							}
 						});
@@ -3019,13 +3177,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 					'selector_index': str(index_for_logging),
 				}
 
-			# Format options for display
-			formatted_options = []
-			for opt in dropdown_data['options']:
-				# Use JSON encoding to ensure exact string matching
-				encoded_text = json.dumps(opt['text'])
-				status = ' (selected)' if opt.get('selected') else ''
-				formatted_options.append(f'{opt["index"]}: text={encoded_text}, value={json.dumps(opt["value"])}{status}')
+			formatted_options = [_format_dropdown_option(opt) for opt in dropdown_data['options']]
 
 			dropdown_type = dropdown_data.get('type', 'select')
 			element_info = f'Index: {index_for_logging}, Type: {dropdown_type}, ID: {dropdown_data.get("id", "none")}, Name: {dropdown_data.get("name", "none")}'
@@ -3173,11 +3325,14 @@ class DefaultActionWatchdog(BaseWatchdog):
 			optionElements.forEach((item, idx) => {
 				const text = item.textContent ? item.textContent.trim() : '';
 				if (text) {
+					const group = item.closest('[role="group"]');
 					options.push({
 						text: text,
 						value: item.getAttribute('data-value') || item.getAttribute('value') || text,
 						index: idx,
-						selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected')
+						selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected'),
+						disabled: item.getAttribute('aria-disabled') === 'true',
+						group: group && listbox.contains(group) ? group.getAttribute('aria-label') : null
 					});
 				}
 			});
@@ -3193,7 +3348,9 @@ class DefaultActionWatchdog(BaseWatchdog):
 							text: text,
 							value: item.getAttribute('data-value') || item.getAttribute('value') || text,
 							index: idx,
-							selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected')
+							selected: item.getAttribute('aria-selected') === 'true' || item.classList.contains('selected'),
+							disabled: item.getAttribute('aria-disabled') === 'true',
+							group: null
 						});
 					}
 				});
@@ -3256,12 +3413,7 @@ class DefaultActionWatchdog(BaseWatchdog):
 				'selector_index': str(index_for_logging),
 			}
 
-		# Format options for display
-		formatted_options = []
-		for opt in dropdown_data['options']:
-			encoded_text = json.dumps(opt['text'])
-			status = ' (selected)' if opt.get('selected') else ''
-			formatted_options.append(f'{opt["index"]}: text={encoded_text}, value={json.dumps(opt["value"])}{status}')
+		formatted_options = [_format_dropdown_option(opt) for opt in dropdown_data['options']]
 
 		dropdown_type = dropdown_data.get('type', 'aria-combobox')
 		element_info = f'Index: {index_for_logging}, Type: {dropdown_type}, ID: {dropdown_data.get("id", "none")}, Name: {dropdown_data.get("name", "none")}'
@@ -3328,6 +3480,12 @@ class DefaultActionWatchdog(BaseWatchdog):
 								// Match against both text and value (case-insensitive)
 								if (optionTextLower === targetTextLower || optionValueLower === targetTextLower) {
 									const expectedValue = option.value;
+									if (option.disabled || (option.parentElement && option.parentElement.disabled)) {
+										return {
+											success: false,
+											error: `Option '${option.text.trim()}' is disabled and cannot be selected`
+										};
+									}
 
 									// Focus the element FIRST (important for Svelte/Vue/React and other reactive frameworks)
 									// This simulates the user focusing on the dropdown before changing it
@@ -3678,6 +3836,18 @@ class DefaultActionWatchdog(BaseWatchdog):
 						self.logger.warning(f'⚠️ Click fallback also failed: {fallback_data.get("error", "unknown")}')
 						# Continue to error handling below
 
+				# Custom dropdowns (React-select, MUI, ...) only render their options after being opened
+				if (
+					not selection_result.get('success')
+					and not selection_result.get('selectionReverted')
+					and element_node.tag_name != 'select'
+				):
+					custom_result = await self._select_custom_dropdown_option(
+						element_node, target_text, cdp_session, object_id, index_for_logging
+					)
+					if custom_result is not None:
+						return custom_result
+
 				if selection_result.get('success'):
 					msg = selection_result.get('message', f'Selected option: {target_text}')
 					self.logger.debug(f'{msg}')
@@ -3704,7 +3874,9 @@ class DefaultActionWatchdog(BaseWatchdog):
 							if isinstance(opt, dict):
 								text = opt.get('text', '').strip()
 								value = opt.get('value', '').strip()
-								if text:
+								if text and value and value != text:
+									short_term_options.append(f'- {text} (value: {value})')
+								elif text:
 									short_term_options.append(f'- {text}')
 								elif value:
 									short_term_options.append(f'- {value}')
@@ -3744,3 +3916,135 @@ class DefaultActionWatchdog(BaseWatchdog):
 			error_msg = f'Failed to select dropdown option "{target_text}" for element {index_for_logging}: {str(e)}'
 			self.logger.error(error_msg)
 			raise ValueError(error_msg) from e
+
+	async def _select_custom_dropdown_option(
+		self,
+		element_node: EnhancedDOMTreeNode,
+		target_text: str,
+		cdp_session,
+		object_id: str,
+		index_for_logging: int | str | None,
+	) -> dict[str, str] | None:
+		"""Select an option of a custom dropdown the way a user would.
+
+		Clicks the dropdown open, waits for its listbox, scrolls virtualized lists until an option matches the text
+		(exact, partial or fuzzy), types the text to filter searchable dropdowns as a last resort, clicks the option
+		and checks the dropdown shows it. Returns None if no listbox opened, so the caller reports its own error.
+		"""
+		steps: list[str] = []
+
+		async def call(function: str, *args) -> dict:
+			result = await cdp_session.cdp_client.send.Runtime.callFunctionOn(
+				params={
+					'functionDeclaration': function,
+					'arguments': [{'value': arg} for arg in args],
+					'objectId': object_id,
+					'returnByValue': True,
+				},
+				session_id=cdp_session.session_id,
+			)
+			return result.get('result', {}).get('value') or {}
+
+		async def wait_for_options(timeout: float) -> dict:
+			deadline = asyncio.get_running_loop().time() + timeout
+			while True:
+				state = await call(_CUSTOM_DROPDOWN_JS, 'collect')
+				if (state.get('listbox') and state.get('options')) or asyncio.get_running_loop().time() >= deadline:
+					return state
+				await asyncio.sleep(0.1)
+
+		box = await call(
+			'function() { this.scrollIntoView({block: "center"}); const r = this.getBoundingClientRect(); '
+			'return {x: r.left + r.width / 2, y: r.top + r.height / 2}; }'
+		)
+		if 'x' not in box:
+			return None
+		await self._click_on_coordinate(round(box['x']), round(box['y']))
+		steps.append('clicked to open')
+
+		state = await wait_for_options(_DROPDOWN_OPEN_TIMEOUT)
+		seen: list[str] = []
+		match = None
+		if state.get('listbox'):
+			steps.append(f'listbox showed {len(state.get("options", []))} options')
+			for _ in range(_MAX_DROPDOWN_SCROLLS):
+				options = state.get('options', [])
+				seen.extend(option for option in options if option not in seen)
+				match = match_dropdown_option(target_text, options)
+				if match or not (await call(_CUSTOM_DROPDOWN_JS, 'scroll')).get('scrolled'):
+					break
+				if 'scrolled the list' not in steps:
+					steps.append('scrolled the list')
+				await asyncio.sleep(0.15)  # Virtualized lists render the new rows after the scroll event
+				state = await call(_CUSTOM_DROPDOWN_JS, 'collect')
+
+		if match is None:
+			# Searchable dropdowns (React-select, MUI Autocomplete) focus an input, typing filters or loads the options
+			focused_input = await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={
+					'expression': "(a => !!a && (a.tagName === 'INPUT' || a.isContentEditable))(document.activeElement)",
+					'returnByValue': True,
+				},
+				session_id=cdp_session.session_id,
+			)
+			if focused_input.get('result', {}).get('value'):
+				await cdp_session.cdp_client.send.Input.insertText(
+					params={'text': target_text}, session_id=cdp_session.session_id
+				)
+				steps.append(f"typed '{target_text}' to filter")
+				await asyncio.sleep(0.3)
+				state = await wait_for_options(_DROPDOWN_OPEN_TIMEOUT)
+				options = state.get('options', [])
+				seen.extend(option for option in options if option not in seen)
+				match = match_dropdown_option(target_text, options)
+
+		if not state.get('listbox'):
+			self.logger.debug(f'No listbox opened after clicking dropdown {index_for_logging}')
+			return None
+
+		if match is None:
+			chain = ' -> '.join(steps)
+			error_msg = f"Custom dropdown option '{target_text}' not found ({chain})"
+			self.logger.warning(f'⚠️ {error_msg}')
+			result = {
+				'success': 'false',
+				'error': error_msg,
+				'fallback_chain': chain,
+				'backend_node_id': str(element_node.backend_node_id),
+				'selector_index': str(index_for_logging),
+			}
+			if seen:
+				result['short_term_memory'] = 'Available dropdown options  are:\n' + '\n'.join(f'- {option}' for option in seen)
+				result['long_term_memory'] = (
+					f"Couldn't select the dropdown option as '{target_text}' is not one of the available options."
+				)
+			return result
+
+		option_index, match_kind = match
+		option_text = state['options'][option_index]
+		point = await call(_CUSTOM_DROPDOWN_JS, 'reveal', option_index)
+		if not point.get('found'):
+			return None
+		await self._click_on_coordinate(round(point['x']), round(point['y']))
+		steps.append(f"clicked {match_kind} match '{option_text}'")
+
+		await asyncio.sleep(0.2)
+		shown = await cdp_session.cdp_client.send.Runtime.callFunctionOn(
+			params={'functionDeclaration': _DROPDOWN_SHOWN_VALUE_JS, 'objectId': object_id, 'returnByValue': True},
+			session_id=cdp_session.session_id,
+		)
+		verified = _normalize_option_text(option_text) in _normalize_option_text(shown.get('result', {}).get('value') or '')
+		steps.append('verified the dropdown shows it' if verified else 'could not verify the dropdown shows it')
+
+		chain = ' -> '.join(steps)
+		msg = f'Selected custom dropdown option: {option_text} ({chain})'
+		self.logger.info(f'✅ {msg}' if verified else f'⚠️ {msg}')
+		return {
+			'success': 'true',
+			'message': msg,
+			'value': option_text,
+			'verified': str(verified).lower(),
+			'fallback_chain': chain,
+			'backend_node_id': str(element_node.backend_node_id),
+			'selector_index': str(index_for_logging),
+		}
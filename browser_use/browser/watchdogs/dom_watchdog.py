@@ -17,13 +17,28 @@ from browser_use.dom.views import (
 	SerializedDOMState,
 )
 from browser_use.observability import observe_debug
-from browser_use.utils import create_task_with_error_handling, time_execution_async
+from browser_use.utils import create_task_with_error_handling, is_new_tab_page, time_execution_async
 
 if TYPE_CHECKING:
 	from browser_use.browser.views import BrowserStateSummary, NetworkRequest, PageInfo, PaginationButton
 
 _BROWSER_STATE_PARALLEL_TASK_BUDGET_SECONDS = 20.0
 
+_BROWSER_INTERNAL_SCHEMES = ('chrome', 'about', 'edge', 'brave', 'devtools', 'chrome-extension', 'chrome-untrusted')
+
+
+def _describe_non_web_page(page_url: str) -> str | None:
+	"""Describe a non-http(s) page whose DOM isn't captured, None for empty new tabs."""
+	if is_new_tab_page(page_url):
+		return None
+	scheme = page_url.split(':', 1)[0].lower()
+	if scheme in _BROWSER_INTERNAL_SCHEMES:
+		return (
+			f'Browser internal page ({page_url}). Its content cannot be read or interacted with: '
+			'navigate to a website, go back, or switch to another tab.'
+		)
+	return f'Non-web page ({scheme}: URL). Its content is not captured: navigate to an http(s) page to continue.'
+
 
 class DOMWatchdog(BaseWatchdog):
 	"""Handles DOM tree building, serialization, and element access via CDP.
@@ -265,6 +280,9 @@ class DOMWatchdog(BaseWatchdog):
 		# check if we should skip DOM tree build for pointless pages
 		not_a_meaningful_website = page_url.lower().split(':', 1)[0] not in ('http', 'https')
 
+		# The tab list doesn't depend on page stability, fetch it while we check for pending requests
+		tabs_task = asyncio.create_task(self.browser_session.get_tabs())
+
 		# Check for pending network requests BEFORE waiting (so we can see what's loading)
 		# Timeout after 2s — on slow CI machines or heavy pages, this call can hang
 		# for 15s+ eating into the 30s BrowserStateRequestEvent budget.
@@ -294,7 +312,8 @@ class DOMWatchdog(BaseWatchdog):
 
 		# Get tabs info once at the beginning for all paths
 		self.logger.debug('🔍 DOMWatchdog.on_BrowserStateRequestEvent: Getting tabs info...')
-		tabs_info = await self.browser_session.get_tabs()
+		tabs_info = await tabs_task
+		side_tasks: list[asyncio.Task] = []
 		self.logger.debug(f'🔍 DOMWatchdog.on_BrowserStateRequestEvent: Got {len(tabs_info)} tabs')
 		self.logger.debug(f'🔍 DOMWatchdog.on_BrowserStateRequestEvent: Tabs info: {tabs_info}')
 
@@ -338,21 +357,33 @@ class DOMWatchdog(BaseWatchdog):
 						pixels_right=0,
 					)
 
+				# Describe what the page is instead of leaving the agent with an empty state
+				is_pdf_viewer = self._is_pdf_page(page_url)
+				page_notice = await self._describe_pdf_page() if is_pdf_viewer else _describe_non_web_page(page_url)
+				title = 'Empty Tab'
+				if page_notice is not None:
+					try:
+						title = await asyncio.wait_for(self.browser_session.get_current_page_title(), timeout=1.0) or page_url
+					except Exception:
+						title = page_url
+
 				return BrowserStateSummary(
 					dom_state=content,
 					url=page_url,
-					title='Empty Tab',
+					title=title,
 					tabs=tabs_info,
 					screenshot=screenshot_b64,
 					page_info=page_info,
 					pixels_above=0,
 					pixels_below=0,
 					browser_errors=[],
-					is_pdf_viewer=False,
+					is_pdf_viewer=is_pdf_viewer,
+					page_notice=page_notice,
 					recent_events=self._get_recent_events_str() if event.include_recent_events else None,
 					pending_network_requests=[],  # Empty page has no pending requests
 					pagination_buttons=[],  # Empty page has no pagination
 					closed_popup_messages=self.browser_session._closed_popup_messages.copy(),
+					page_errors=self.browser_session.get_page_errors(),
 				)
 
 			# Execute DOM building and screenshot capture in parallel
@@ -360,6 +391,12 @@ class DOMWatchdog(BaseWatchdog):
 			screenshot_task = None
 			parallel_tasks_started_at = time.monotonic()
 
+			# Title and layout metrics don't depend on the DOM tree or the screenshot, fetch them while those are built
+			# (their 1s timeouts start once the DOM is built, a busy event loop during serialization doesn't count)
+			title_task = asyncio.create_task(self.browser_session.get_current_page_title())
+			page_info_task = asyncio.create_task(self._get_page_info())
+			side_tasks += [title_task, page_info_task]
+
 			# Start DOM building task if requested
 			if event.include_dom:
 				self.logger.debug('🔍 DOMWatchdog.on_BrowserStateRequestEvent: 🌳 Starting DOM tree build task...')
@@ -437,7 +474,7 @@ class DOMWatchdog(BaseWatchdog):
 			# Get target title safely
 			try:
 				self.logger.debug('🔍 DOMWatchdog.on_BrowserStateRequestEvent: Getting page title...')
-				title = await asyncio.wait_for(self.browser_session.get_current_page_title(), timeout=1.0)
+				title = await asyncio.wait_for(title_task, timeout=1.0)
 				self.logger.debug(f'🔍 DOMWatchdog.on_BrowserStateRequestEvent: Got title: {title}')
 			except Exception as e:
 				self.logger.debug(f'🔍 DOMWatchdog.on_BrowserStateRequestEvent: Failed to get title: {e}')
@@ -446,7 +483,7 @@ class DOMWatchdog(BaseWatchdog):
 			# Get comprehensive page info from CDP with timeout
 			try:
 				self.logger.debug('🔍 DOMWatchdog.on_BrowserStateRequestEvent: Getting page info from CDP...')
-				page_info = await asyncio.wait_for(self._get_page_info(), timeout=1.0)
+				page_info = await asyncio.wait_for(page_info_task, timeout=1.0)
 				self.logger.debug(f'🔍 DOMWatchdog.on_BrowserStateRequestEvent: Got page info from CDP: {page_info}')
 			except Exception as e:
 				self.logger.debug(
@@ -468,7 +505,8 @@ class DOMWatchdog(BaseWatchdog):
 				)
 
 			# Check for PDF viewer
-			is_pdf_viewer = page_url.endswith('.pdf') or '/pdf/' in page_url
+			is_pdf_viewer = page_url.endswith('.pdf') or '/pdf/' in page_url or self._is_pdf_page(page_url)
+			page_notice = await self._describe_pdf_page() if is_pdf_viewer else None
 
 			# Detect pagination buttons from the DOM
 			pagination_buttons_data = []
@@ -496,24 +534,29 @@ class DOMWatchdog(BaseWatchdog):
 				pixels_below=0,
 				browser_errors=[],
 				is_pdf_viewer=is_pdf_viewer,
+				page_notice=page_notice,
 				recent_events=self._get_recent_events_str() if event.include_recent_events else None,
 				pending_network_requests=pending_requests,
 				pagination_buttons=pagination_buttons_data,
 				closed_popup_messages=self.browser_session._closed_popup_messages.copy(),
+				page_errors=self.browser_session.get_page_errors(),
 			)
 
 			# Cache the state
 			self.browser_session._cached_browser_state_summary = browser_state
 
-			# Cache viewport size for coordinate conversion (if llm_screenshot_size is enabled)
+			# Cache viewport size and device pixel ratio to map screenshot coordinates back to CSS pixels
 			if page_info:
 				self.browser_session._original_viewport_size = (page_info.viewport_width, page_info.viewport_height)
+				self.browser_session._device_pixel_ratio = page_info.device_pixel_ratio
 
 			self.logger.debug('🔍 DOMWatchdog.on_BrowserStateRequestEvent: ✅ COMPLETED - Returning browser state')
 			return browser_state
 
 		except Exception as e:
 			self.logger.error(f'Failed to get browser state: {e}')
+			for task in side_tasks:
+				task.cancel()
 
 			# Return minimal recovery state
 			return BrowserStateSummary(
@@ -546,6 +589,29 @@ class DOMWatchdog(BaseWatchdog):
 				else [],
 			)
 
+	def _is_pdf_page(self, page_url: str) -> bool:
+		downloads_watchdog = self.browser_session._downloads_watchdog
+		return downloads_watchdog is not None and downloads_watchdog.is_pdf_document(page_url)
+
+	async def _describe_pdf_page(self) -> str:
+		"""Describe the PDF in the focused tab, with its page count when the PDF can be read."""
+		page_count = None
+		downloads_watchdog = self.browser_session._downloads_watchdog
+		target_id = self.browser_session.agent_focus_target_id
+		if downloads_watchdog is not None and target_id:
+			try:
+				pdf = await asyncio.wait_for(downloads_watchdog.read_pdf(target_id), timeout=5.0)
+				if pdf is not None:
+					page_count = pdf[1]
+			except TimeoutError:
+				self.logger.debug('Reading the PDF for its page count timed out (5s), skipping')
+
+		pages = f', {page_count} page{"" if page_count == 1 else "s"}' if page_count is not None else ''
+		return (
+			f'PDF document{pages}. The PDF viewer has no interactive elements: use extract to read its text, '
+			'or read_file on the downloaded PDF in available_file_paths.'
+		)
+
 	@time_execution_async('build_dom_tree_without_highlights')
 	@observe_debug(ignore_input=True, ignore_output=True, name='build_dom_tree_without_highlights')
 	async def _build_dom_tree_without_highlights(self, previous_state: SerializedDOMState | None = None) -> SerializedDOMState:
@@ -825,6 +891,7 @@ class DOMWatchdog(BaseWatchdog):
 			pixels_below=pixels_below,
 			pixels_left=pixels_left,
 			pixels_right=pixels_right,
+			device_pixel_ratio=round(device_pixel_ratio, 3),
 		)
 
 		return page_info
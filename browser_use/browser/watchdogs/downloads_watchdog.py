@@ -1,6 +1,8 @@
 """Downloads watchdog for monitoring and handling file downloads."""
 
 import asyncio
+import base64
+import io
 import json
 import os
 import re
@@ -19,6 +21,7 @@ from pydantic import PrivateAttr
 
 from browser_use.browser.events import (
 	BrowserLaunchEvent,
+	BrowserReconnectedEvent,
 	BrowserStateRequestEvent,
 	BrowserStoppedEvent,
 	DownloadProgressEvent,
@@ -104,12 +107,26 @@ def _should_auto_download_network_response(
 	return True
 
 
+def _extract_pdf_text(data: bytes) -> tuple[str, int]:
+	"""Return the text of a PDF with a marker per page (like read_file) and its page count."""
+	import pypdf
+
+	reader = pypdf.PdfReader(io.BytesIO(data))
+	page_texts = []
+	for page_number, page in enumerate(reader.pages, 1):
+		text = page.extract_text() or ''
+		if text.strip():
+			page_texts.append(f'--- Page {page_number} ---\n{text}')
+	return '\n\n'.join(page_texts), len(reader.pages)
+
+
 class DownloadsWatchdog(BaseWatchdog):
 	"""Monitors downloads and handles file download events."""
 
 	# Events this watchdog listens to (for documentation)
 	LISTENS_TO: ClassVar[list[type[BaseEvent[Any]]]] = [
 		BrowserLaunchEvent,
+		BrowserReconnectedEvent,
 		BrowserStateRequestEvent,
 		BrowserStoppedEvent,
 		TabCreatedEvent,
@@ -133,6 +150,8 @@ class DownloadsWatchdog(BaseWatchdog):
 	_cdp_event_tasks: set[asyncio.Task] = PrivateAttr(default_factory=set)  # Track CDP event handler tasks
 	_cdp_downloads_info: dict[str, dict[str, Any]] = PrivateAttr(default_factory=dict)  # Map guid -> info
 	_session_pdf_urls: dict[str, str] = PrivateAttr(default_factory=dict)  # URL -> path for PDFs downloaded this session
+	_pdf_document_urls: set[str] = PrivateAttr(default_factory=set)  # Page URLs served with an application/pdf content type
+	_pdf_text_cache: dict[str, tuple[str, int]] = PrivateAttr(default_factory=dict)  # URL -> (text, page count) of parsed PDFs
 	_initial_downloads_snapshot: set[str] = PrivateAttr(default_factory=set)  # Files present when watchdog started
 	_network_monitored_targets: set[str] = PrivateAttr(default_factory=set)  # Track targets with network monitoring enabled
 	_detected_downloads: set[str] = PrivateAttr(default_factory=set)  # Track detected download URLs to avoid duplicates
@@ -248,6 +267,14 @@ class DownloadsWatchdog(BaseWatchdog):
 		)
 		self.logger.debug('[DownloadsWatchdog] Successfully completed BrowserStateRequestEvent')
 
+	async def on_BrowserReconnectedEvent(self, event: BrowserReconnectedEvent) -> None:
+		"""Forget per-session setup from the old websocket so tabs get download handling again."""
+		self._download_cdp_session = None
+		self._download_cdp_session_setup = False
+		self._sessions_with_listeners.clear()
+		self._network_monitored_targets.clear()
+		self._network_callback_registered = False
+
 	async def on_BrowserStoppedEvent(self, event: BrowserStoppedEvent) -> None:
 		"""Clean up when browser stops."""
 		# Cancel all CDP event handler tasks
@@ -269,6 +296,8 @@ class DownloadsWatchdog(BaseWatchdog):
 		self._active_downloads.clear()
 		self._pdf_viewer_cache.clear()
 		self._session_pdf_urls.clear()
+		self._pdf_document_urls.clear()
+		self._pdf_text_cache.clear()
 		self._network_monitored_targets.clear()
 		self._detected_downloads.clear()
 		self._initial_downloads_snapshot.clear()
@@ -281,6 +310,7 @@ class DownloadsWatchdog(BaseWatchdog):
 		# Clear PDF cache for the navigated URL since content may have changed
 		if event.url in self._pdf_viewer_cache:
 			del self._pdf_viewer_cache[event.url]
+		self._pdf_text_cache.pop(event.url, None)
 
 		# Check if auto-download is enabled
 		auto_download_enabled = self._is_auto_download_enabled()
@@ -484,13 +514,15 @@ class DownloadsWatchdog(BaseWatchdog):
 					return
 				# Ensure path is properly expanded (~ -> absolute path)
 				expanded_downloads_path = Path(downloads_path).expanduser().resolve()
-				await cdp_client.send.Browser.setDownloadBehavior(
-					params={
-						'behavior': 'allow',
-						'downloadPath': str(expanded_downloads_path),  # Use expanded absolute path
-						'eventsEnabled': True,
-					}
-				)
+				download_behavior = {
+					'behavior': 'allow',
+					'downloadPath': str(expanded_downloads_path),  # Use expanded absolute path
+					'eventsEnabled': True,
+				}
+				# Download behavior is per browser context, without an id it only applies to the default one
+				if self.browser_session.browser_context_id:
+					download_behavior['browserContextId'] = self.browser_session.browser_context_id
+				await cdp_client.send.Browser.setDownloadBehavior(params=download_behavior)  # type: ignore[arg-type]
 
 				# Register the handlers with CDP
 				cdp_client.register.Browser.downloadWillBegin(download_will_begin_handler)  # type: ignore[arg-type]
@@ -573,6 +605,9 @@ class DownloadsWatchdog(BaseWatchdog):
 
 						# Check if it's a PDF
 						is_pdf = 'application/pdf' in content_type
+						if is_pdf and request_type == 'Document':
+							# Remember it so the page is described as a PDF even when the URL doesn't say so
+							self._pdf_document_urls.add(url)
 
 						# Check if it's marked as download via Content-Disposition header
 						content_disposition = str(headers.get('content-disposition', '')).lower()
@@ -1173,6 +1208,74 @@ class DownloadsWatchdog(BaseWatchdog):
 			self._pdf_viewer_cache[page_url] = False
 			return False
 
+	def is_pdf_document(self, url: str) -> bool:
+		"""Check if a page URL shows a PDF, from its content type when it was seen on the network or from the URL."""
+		return url in self._pdf_document_urls or self._check_url_for_pdf(url) or self._is_chrome_pdf_viewer_url(url)
+
+	async def read_pdf(self, target_id: TargetID) -> tuple[str, int] | None:
+		"""Extract the text and page count of the PDF shown in a tab.
+
+		Uses the copy downloaded in this session when there is one, otherwise fetches the PDF through the page
+		(served from the browser cache) and parses it in memory. Returns None if the PDF can't be read.
+		"""
+		target = self.browser_session.session_manager.get_target(target_id) if self.browser_session.session_manager else None
+		if not target or not target.url.startswith('http'):
+			return None
+		url = target.url
+		if url in self._pdf_text_cache:
+			return self._pdf_text_cache[url]
+
+		existing_path = self._session_pdf_urls.get(url)
+		if existing_path and os.path.exists(existing_path):
+			async with await anyio.open_file(existing_path, 'rb') as f:
+				data = await f.read()
+		else:
+			data = await self._fetch_pdf_bytes(target_id, url)
+		if not data:
+			return None
+
+		try:
+			pdf = await asyncio.to_thread(_extract_pdf_text, data)
+		except Exception as e:
+			self.logger.debug(f'[DownloadsWatchdog] Failed to parse PDF {url[:80]}: {type(e).__name__}: {e}')
+			return None
+		self._pdf_text_cache[url] = pdf
+		return pdf
+
+	async def _fetch_pdf_bytes(self, target_id: TargetID, url: str) -> bytes | None:
+		"""Fetch the PDF from inside the page and return its bytes, base64 encoded in chunks to keep the payload small."""
+		try:
+			temp_session = await self.browser_session.get_or_create_cdp_session(target_id, focus=False)
+			result = await asyncio.wait_for(
+				temp_session.cdp_client.send.Runtime.evaluate(
+					params={
+						'expression': f"""
+				(async () => {{
+					const response = await fetch({json.dumps(url)}, {{ cache: 'force-cache' }});
+					if (!response.ok) {{
+						throw new Error(`HTTP error! status: ${{response.status}}`);
+					}}
+					const bytes = new Uint8Array(await response.arrayBuffer());
+					let binary = '';
+					for (let i = 0; i < bytes.length; i += 0x8000) {{
+						binary += String.fromCharCode.apply(null, bytes.subarray(i, i + 0x8000));
+					}}
+					return btoa(binary);
+				}})()
+				""",
+						'awaitPromise': True,
+						'returnByValue': True,
+					},
+					session_id=temp_session.session_id,
+				),
+				timeout=10.0,
+			)
+			encoded = result.get('result', {}).get('value')
+			return base64.b64decode(encoded) if encoded else None
+		except Exception as e:
+			self.logger.debug(f'[DownloadsWatchdog] Failed to fetch PDF {url[:80]}: {type(e).__name__}: {e}')
+			return None
+
 	def _check_url_for_pdf(self, url: str) -> bool:
 		"""Check if URL indicates a PDF file."""
 		if not url:
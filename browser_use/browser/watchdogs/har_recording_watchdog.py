@@ -1,8 +1,9 @@
 """HAR Recording Watchdog for Browser-Use sessions.
 
 Captures HTTPS network activity via CDP Network domain and writes a HAR 1.2
-file on browser shutdown. Respects `record_har_content` (omit/embed/attach)
-and `record_har_mode` (full/minimal).
+file on browser shutdown (record_har_path) or on demand, e.g. per agent run into its
+artifacts (record_har=True). Respects `record_har_content` (omit/embed/attach),
+`record_har_mode` (full/minimal) and `record_har_max_body_size`.
 """
 
 from __future__ import annotations
@@ -10,6 +11,8 @@ from __future__ import annotations
 import base64
 import hashlib
 import json
+import urllib.parse
+from collections.abc import Callable
 from dataclasses import dataclass, field
 from importlib import metadata as importlib_metadata
 from pathlib import Path
@@ -27,6 +30,39 @@ from cdp_use.cdp.page.events import FrameNavigatedEvent, LifecycleEventEvent
 
 from browser_use.browser.events import BrowserConnectedEvent, BrowserStopEvent
 from browser_use.browser.watchdog_base import BaseWatchdog
+from browser_use.utils import collect_sensitive_data_values
+
+# Headers that carry credentials, masked in HAR files unless record_har_sensitive is set
+_SENSITIVE_HEADERS = frozenset(
+	{'authorization', 'proxy-authorization', 'cookie', 'set-cookie', 'x-api-key', 'x-auth-token', 'x-csrf-token'}
+)
+_REDACTED = '<redacted>'
+
+# Oldest requests are dropped beyond this, a kept-alive browser records across many runs
+MAX_HAR_ENTRIES = 5000
+
+
+def make_har_redactor(
+	sensitive_data: dict[str, str | dict[str, str]] | None = None, redact: Callable[[str], str] | None = None
+) -> Callable[[str], str] | None:
+	"""Text filter for HAR exports: sensitive_data values become <secret>name</secret>, then redact is applied.
+
+	Secrets are also matched URL-encoded, the form they have in query strings and form posts.
+	"""
+	replacements: list[tuple[str, str]] = []
+	for key, value in collect_sensitive_data_values(sensitive_data).items():
+		for form in {value, urllib.parse.quote(value, safe=''), urllib.parse.quote_plus(value)}:
+			replacements.append((form, f'<secret>{key}</secret>'))
+	replacements.sort(key=lambda item: len(item[0]), reverse=True)
+	if not replacements and redact is None:
+		return None
+
+	def redact_har_text(text: str) -> str:
+		for secret, placeholder in replacements:
+			text = text.replace(secret, placeholder)
+		return redact(text) if redact is not None else text
+
+	return redact_har_text
 
 
 @dataclass
@@ -154,18 +190,28 @@ class HarRecordingWatchdog(BaseWatchdog):
 		self._top_level_pages: dict[
 			str, dict
 		] = {}  # frameId -> {url, title, startedDateTime, monotonic_start, onContentLoad, onLoad}
+		self._har_path: Path | None = None
+		self._max_body_size: int | None = None
+		self._keep_sensitive: bool = False
+		self._subscription_ids: list[str] = []
+
+	@property
+	def enabled(self) -> bool:
+		return self._enabled
 
 	async def on_BrowserConnectedEvent(self, event: BrowserConnectedEvent) -> None:
 		profile = self.browser_session.browser_profile
-		if not profile.record_har_path:
+		if not (profile.record_har_path or profile.record_har) or self._enabled:
 			return
 
 		# Normalize config
 		self._content_mode = (profile.record_har_content or 'embed').lower()
 		self._mode = (profile.record_har_mode or 'full').lower()
-		self._har_path = Path(str(profile.record_har_path)).expanduser().resolve()
-		self._har_dir = self._har_path.parent
-		self._har_dir.mkdir(parents=True, exist_ok=True)
+		self._max_body_size = profile.record_har_max_body_size
+		self._keep_sensitive = profile.record_har_sensitive
+		if profile.record_har_path:
+			self._har_path = Path(str(profile.record_har_path)).expanduser().resolve()
+			self._har_path.parent.mkdir(parents=True, exist_ok=True)
 
 		try:
 			# Enable Network and Page domains for events
@@ -182,17 +228,26 @@ class HarRecordingWatchdog(BaseWatchdog):
 				self._browser_name = 'Chromium'
 				self._browser_version = ''
 
-			cdp = self.browser_session.cdp_client.register
-			cdp.Network.requestWillBeSent(self._on_request_will_be_sent)
-			cdp.Network.responseReceived(self._on_response_received)
-			cdp.Network.dataReceived(self._on_data_received)
-			cdp.Network.loadingFinished(self._on_loading_finished)
-			cdp.Network.loadingFailed(self._on_loading_failed)
-			cdp.Page.lifecycleEvent(self._on_lifecycle_event)
-			cdp.Page.frameNavigated(self._on_frame_navigated)
+			# Subscribe rather than cdp_client.register, which would replace the session manager's lifecycle
+			# handler and the downloads watchdog's responseReceived handler
+			handlers = {
+				'Network.requestWillBeSent': self._on_request_will_be_sent,
+				'Network.responseReceived': self._on_response_received,
+				'Network.dataReceived': self._on_data_received,
+				'Network.loadingFinished': self._on_loading_finished,
+				'Network.loadingFailed': self._on_loading_failed,
+				'Page.lifecycleEvent': self._on_lifecycle_event,
+				'Page.frameNavigated': self._on_frame_navigated,
+			}
+			self._subscription_ids = [
+				self.browser_session.subscribe_cdp_event(
+					method, lambda cdp_event, handler=handler: handler(cdp_event.params, cdp_event.session_id)
+				)
+				for method, handler in handlers.items()
+			]
 
 			self._enabled = True
-			self.logger.info(f'📊 Starting HAR recording to {self._har_path}')
+			self.logger.info(f'📊 Starting HAR recording{f" to {self._har_path}" if self._har_path else ""}')
 		except Exception as e:
 			self.logger.warning(f'Failed to enable HAR recording: {e}')
 			self._enabled = False
@@ -200,11 +255,26 @@ class HarRecordingWatchdog(BaseWatchdog):
 	async def on_BrowserStopEvent(self, event: BrowserStopEvent) -> None:
 		if not self._enabled:
 			return
-		try:
-			await self._write_har()
-			self.logger.info(f'📊 HAR file saved: {self._har_path}')
-		except Exception as e:
-			self.logger.warning(f'Failed to write HAR: {e}')
+		if self._har_path is not None:
+			try:
+				self.write_har(self._har_path)
+				self.logger.info(f'📊 HAR file saved: {self._har_path}')
+			except Exception as e:
+				self.logger.warning(f'Failed to write HAR: {e}')
+
+		# A kept-alive browser keeps recording for the next run
+		profile = self.browser_session.browser_profile
+		if profile.keep_alive and not event.force and not event.close_browser:
+			return
+		for subscription_id in self._subscription_ids:
+			try:
+				self.browser_session.unsubscribe_cdp_event(subscription_id)
+			except ValueError:
+				pass
+		self._subscription_ids = []
+		self._entries.clear()
+		self._top_level_pages.clear()
+		self._enabled = False
 
 	# =============== CDP Event Handlers (sync) ==================
 	def _on_request_will_be_sent(self, params: RequestWillBeSentEvent, session_id: str | None) -> None:
@@ -219,6 +289,11 @@ class HarRecordingWatchdog(BaseWatchdog):
 				return
 
 			entry = self._entries.setdefault(request_id, _HarEntryBuilder(request_id=request_id))
+			while len(self._entries) > MAX_HAR_ENTRIES:
+				oldest = next(iter(self._entries))
+				frame_id = self._entries.pop(oldest).frame_id
+				if frame_id and not any(e.frame_id == frame_id for e in self._entries.values()):
+					self._top_level_pages.pop(frame_id, None)
 			entry.url = url
 			entry.method = req.get('method') if isinstance(req, dict) else getattr(req, 'method', None)
 			entry.post_data = req.get('postData') if isinstance(req, dict) else getattr(req, 'postData', None)
@@ -385,6 +460,20 @@ class HarRecordingWatchdog(BaseWatchdog):
 				return
 			entry = self._entries[request_id]
 			entry.ts_finished = params.get('timestamp')
+			encoded_length = (
+				params.get('encodedDataLength') if hasattr(params, 'get') else getattr(params, 'encodedDataLength', None)
+			)
+			if encoded_length is not None:
+				try:
+					entry.encoded_data_length = int(encoded_length)
+					entry.transfer_size = entry.encoded_data_length
+				except Exception:
+					entry.encoded_data_length = None
+
+			# Bodies are left out of the HAR in omit mode and above record_har_max_body_size, don't fetch them
+			if self._content_mode == 'omit' or self._body_too_large(entry.encoded_data_length):
+				return
+
 			# Fetch response body via CDP as dataReceived may be incomplete
 			import asyncio as _asyncio
 
@@ -409,18 +498,7 @@ class HarRecordingWatchdog(BaseWatchdog):
 				except Exception:
 					pass
 
-			# Always schedule the response body fetch task
 			_asyncio.create_task(_fetch_body(self, request_id, session_id))
-
-			encoded_length = (
-				params.get('encodedDataLength') if hasattr(params, 'get') else getattr(params, 'encodedDataLength', None)
-			)
-			if encoded_length is not None:
-				try:
-					entry.encoded_data_length = int(encoded_length)
-					entry.transfer_size = entry.encoded_data_length
-				except Exception:
-					entry.encoded_data_length = None
 		except Exception as e:
 			self.logger.debug(f'loadingFinished handling error: {e}')
 
@@ -486,14 +564,31 @@ class HarRecordingWatchdog(BaseWatchdog):
 			self.logger.debug(f'frameNavigated handling error: {e}')
 
 	# ===================== HAR Writing ==========================
-	async def _write_har(self) -> None:
+	def _body_too_large(self, size: int | None) -> bool:
+		return self._max_body_size is not None and size is not None and size > self._max_body_size
+
+	def write_har(self, path: str | Path, since: float | None = None, redact: Callable[[str], str] | None = None) -> Path:
+		"""Write the recorded entries as a HAR 1.2 file, only requests sent at or after `since` (epoch seconds) if given.
+
+		Attached bodies go to a `{stem}_har_parts` directory next to the file. Unless record_har_sensitive is set,
+		credential headers (Authorization, Cookie, Set-Cookie, ...) are masked and request bodies left out. redact
+		(see make_har_redactor) is applied to URLs, header values, request bodies and text response bodies.
+		"""
+		clean = redact or (lambda text: text)
+		har_path = Path(path).expanduser().resolve()
+		har_path.parent.mkdir(parents=True, exist_ok=True)
+
 		# Filter by mode and HTTPS already respected at collection time
-		entries = [e for e in self._entries.values() if self._include_entry(e)]
+		entries = [
+			e
+			for e in list(self._entries.values())
+			if self._include_entry(e) and (since is None or (e.wall_time_request is not None and e.wall_time_request >= since))
+		]
 
 		har_entries = []
 		sidecar_dir: Path | None = None
 		if self._content_mode == 'attach':
-			sidecar_dir = self._har_dir / f'{self._har_path.stem}_har_parts'
+			sidecar_dir = har_path.parent / f'{har_path.stem}_har_parts'
 			sidecar_dir.mkdir(parents=True, exist_ok=True)
 
 		for e in entries:
@@ -526,11 +621,14 @@ class HarRecordingWatchdog(BaseWatchdog):
 			if e.content_length is not None and e.encoded_data_length is not None:
 				compression = max(0, e.content_length - e.encoded_data_length)
 
-			if self._content_mode == 'embed' and content_size > 0:
+			if self._content_mode != 'omit' and self._body_too_large(max(content_size, e.encoded_data_length or 0)):
+				content_obj['size'] = content_size or e.content_length or e.encoded_data_length or 0
+				content_obj['comment'] = f'Body omitted, larger than record_har_max_body_size ({self._max_body_size} bytes)'
+			elif self._content_mode == 'embed' and content_size > 0:
 				# Prefer plain text; fallback to base64 only if decoding fails
 				try:
 					text_decoded = body_bytes.decode('utf-8')
-					content_obj['text'] = text_decoded
+					content_obj['text'] = clean(text_decoded)
 					content_obj['size'] = content_size
 					content_obj['compression'] = compression
 				except UnicodeDecodeError:
@@ -551,17 +649,29 @@ class HarRecordingWatchdog(BaseWatchdog):
 					content_obj['compression'] = compression
 
 			started_date_time, total_time_ms, timings = self._compute_timings(e)
-			req_headers_list = [{'name': k, 'value': str(v)} for k, v in (e.request_headers or {}).items()]
-			resp_headers_list = [{'name': k, 'value': str(v)} for k, v in (e.response_headers or {}).items()]
+			req_headers_list = self._headers_list(e.request_headers, clean)
+			resp_headers_list = self._headers_list(e.response_headers, clean)
 			request_headers_size = self._calc_headers_size(e.method or 'GET', e.url or '', req_headers_list)
 			response_headers_size = self._calc_headers_size(None, None, resp_headers_list)
 			request_body_size = self._calc_request_body_size(e)
 			request_post_data = None
 			if e.post_data and self._content_mode != 'omit':
-				if self._content_mode == 'embed':
-					request_post_data = {'mimeType': e.request_headers.get('content-type', ''), 'text': e.post_data}
+				if not self._keep_sensitive:
+					request_post_data = {
+						'mimeType': e.request_headers.get('content-type', ''),
+						'text': '',
+						'comment': 'Request body omitted, set record_har_sensitive=True to keep it',
+					}
+				elif self._body_too_large(len(e.post_data.encode('utf-8'))):
+					request_post_data = {
+						'mimeType': e.request_headers.get('content-type', ''),
+						'text': '',
+						'comment': f'Body omitted, larger than record_har_max_body_size ({self._max_body_size} bytes)',
+					}
+				elif self._content_mode == 'embed':
+					request_post_data = {'mimeType': e.request_headers.get('content-type', ''), 'text': clean(e.post_data)}
 				elif self._content_mode == 'attach' and sidecar_dir is not None:
-					post_data_bytes = e.post_data.encode('utf-8')
+					post_data_bytes = clean(e.post_data).encode('utf-8')
 					req_mime_type = e.request_headers.get('content-type', 'text/plain')
 					req_filename = _generate_har_filename(post_data_bytes, req_mime_type)
 					(sidecar_dir / req_filename).write_bytes(post_data_bytes)
@@ -583,7 +693,7 @@ class HarRecordingWatchdog(BaseWatchdog):
 				'time': total_time_ms,
 				'request': {
 					'method': e.method or 'GET',
-					'url': e.url or '',
+					'url': clean(e.url or ''),
 					'httpVersion': http_version,
 					'headers': req_headers_list,
 					'queryString': [],
@@ -633,6 +743,9 @@ class HarRecordingWatchdog(BaseWatchdog):
 
 			har_entries.append(entry_dict)
 
+		# Only the pages that entries in this file belong to
+		page_refs = {e.frame_id for e in entries if self._page_ref_for_entry(e)}
+
 		# Try to include our library version in creator
 		try:
 			bu_version = importlib_metadata.version('browser-use')
@@ -648,7 +761,7 @@ class HarRecordingWatchdog(BaseWatchdog):
 				'pages': [
 					{
 						'id': f'page@{pid}',  # Use Playwright format: "page@{frame_id}"
-						'title': page_info.get('title', page_info.get('url', '')),
+						'title': clean(page_info.get('title', page_info.get('url', ''))),
 						'startedDateTime': self._format_page_started_datetime(page_info.get('startedDateTime')),
 						'pageTimings': (
 							(lambda _ocl, _ol: ({k: v for k, v in (('onContentLoad', _ocl), ('onLoad', _ol)) if v is not None}))(
@@ -657,16 +770,24 @@ class HarRecordingWatchdog(BaseWatchdog):
 							)
 						),
 					}
-					for pid, page_info in self._top_level_pages.items()
+					for pid, page_info in list(self._top_level_pages.items())
+					if pid in page_refs
 				],
 				'entries': har_entries,
 			}
 		}
 
-		tmp_path = self._har_path.with_suffix(self._har_path.suffix + '.tmp')
+		tmp_path = har_path.with_suffix(har_path.suffix + '.tmp')
 		# Write as bytes explicitly to avoid any text/binary mode confusion in different environments
 		tmp_path.write_bytes(json.dumps(har_obj, indent=2, ensure_ascii=False).encode('utf-8'))
-		tmp_path.replace(self._har_path)
+		tmp_path.replace(har_path)
+		return har_path
+
+	def _headers_list(self, headers: dict | None, clean: Callable[[str], str]) -> list[dict]:
+		return [
+			{'name': k, 'value': _REDACTED if not self._keep_sensitive and k.lower() in _SENSITIVE_HEADERS else clean(str(v))}
+			for k, v in (headers or {}).items()
+		]
 
 	def _format_page_started_datetime(self, timestamp: float | None) -> str:
 		"""Format page startedDateTime from timestamp."""
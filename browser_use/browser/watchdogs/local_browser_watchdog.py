@@ -103,9 +103,13 @@ class LocalBrowserWatchdog(BaseWatchdog):
 		self._original_user_data_dir = str(profile.user_data_dir) if profile.user_data_dir else None
 		self._temp_dirs_to_cleanup = []
 
+		# Fail loudly instead of falling back to a temp dir below, which would silently drop the persistent profile
+		profile.check_profile_not_in_use()
+
 		for attempt in range(max_retries):
 			try:
-				# Get launch args from profile
+				# Write configured Chrome prefs into the profile, then get launch args from profile
+				profile.write_prefs()
 				launch_args = profile.get_args()
 
 				# Add debugging port
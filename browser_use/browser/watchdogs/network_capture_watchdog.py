@@ -0,0 +1,190 @@
+"""Watchdog that records XHR/fetch responses so the agent can read the JSON APIs behind a page."""
+
+import asyncio
+import base64
+import fnmatch
+from typing import TYPE_CHECKING, ClassVar
+
+from bubus import BaseEvent
+from pydantic import PrivateAttr
+
+from browser_use.browser.events import BrowserReconnectedEvent, TabCreatedEvent
+from browser_use.browser.views import CapturedNetworkRequest
+from browser_use.browser.watchdog_base import BaseWatchdog
+
+if TYPE_CHECKING:
+	from browser_use.browser.cdp_events import CDPEvent
+
+# Captured requests kept per session, older entries are dropped first
+MAX_CAPTURED_REQUESTS = 200
+
+_CAPTURED_RESOURCE_TYPES = {'XHR', 'Fetch'}
+_TEXT_MIME_MARKERS = ('json', 'text', 'xml', 'javascript', 'graphql', 'x-www-form-urlencoded')
+
+
+class NetworkCaptureWatchdog(BaseWatchdog):
+	"""Records XHR/fetch requests and their response bodies once enabled with BrowserSession.enable_network_capture().
+
+	Listens with BrowserSession.subscribe_cdp_event(), so it runs next to HAR recording and other watchdogs:
+	requestWillBeSent for URL and resource type, responseReceivedExtraInfo for the status and headers,
+	and loadingFinished to fetch the body with Network.getResponseBody.
+	"""
+
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent, BrowserReconnectedEvent]
+	EMITS: ClassVar[list[type[BaseEvent]]] = []
+
+	_enabled: bool = PrivateAttr(default=False)
+	_patterns: list[str] | None = PrivateAttr(default=None)
+	_max_body_size: int = PrivateAttr(default=100_000)
+	_subscription_ids: list[str] = PrivateAttr(default_factory=list)
+	_enabled_targets: set[str] = PrivateAttr(default_factory=set)
+	_pending: dict[str, CapturedNetworkRequest] = PrivateAttr(default_factory=dict)  # requestId -> request awaiting its body
+	_response_info: dict[str, tuple[int | None, str | None]] = PrivateAttr(default_factory=dict)  # requestId -> status, mime
+	_requests: list[CapturedNetworkRequest] = PrivateAttr(default_factory=list)
+	_body_tasks: set[asyncio.Task] = PrivateAttr(default_factory=set)
+
+	@property
+	def enabled(self) -> bool:
+		return self._enabled
+
+	async def enable(self, patterns: list[str] | None = None, max_body_size: int = 100_000) -> None:
+		"""Start recording requests whose URL matches one of the glob patterns (all XHR/fetch requests if None)."""
+		self._patterns = patterns or None
+		self._max_body_size = max_body_size
+		self._enabled = True
+		self._subscribe()
+		if self.browser_session.session_manager:
+			for target in self.browser_session.session_manager.get_all_page_targets():
+				await self._enable_network(target.target_id)
+
+	def disable(self) -> None:
+		"""Stop recording new requests, already captured requests are kept."""
+		self._enabled = False
+		for subscription_id in self._subscription_ids:
+			self.browser_session.unsubscribe_cdp_event(subscription_id)
+		self._subscription_ids.clear()
+		self._pending.clear()
+		self._response_info.clear()
+
+	def get_requests(self, url_pattern: str | None = None, limit: int | None = None) -> list[CapturedNetworkRequest]:
+		requests = [request for request in self._requests if url_pattern is None or _matches(request.url, url_pattern)]
+		return requests[-limit:] if limit else requests
+
+	def clear(self) -> None:
+		self._requests.clear()
+
+	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
+		if self._enabled:
+			await self._enable_network(event.target_id)
+
+	async def on_BrowserReconnectedEvent(self, event: BrowserReconnectedEvent) -> None:
+		"""Requests in flight on the old connection never finish, tabs are re-announced and enabled again."""
+		self._pending.clear()
+		self._response_info.clear()
+		self._enabled_targets.clear()
+
+	async def _enable_network(self, target_id: str) -> None:
+		if target_id in self._enabled_targets:
+			return
+		try:
+			cdp_session = await self.browser_session.get_or_create_cdp_session(target_id, focus=False)
+			await cdp_session.cdp_client.send.Network.enable(session_id=cdp_session.session_id)
+			self._enabled_targets.add(target_id)
+		except Exception as e:
+			self.logger.debug(f'[NetworkCaptureWatchdog] Failed to enable network capture for tab {target_id[-4:]}: {e}')
+
+	def _subscribe(self) -> None:
+		"""Subscribe to the Network events once, subscriptions survive reconnects."""
+		if self._subscription_ids:
+			return
+		handlers = {
+			'Network.requestWillBeSent': self._on_request_will_be_sent,
+			'Network.responseReceivedExtraInfo': self._on_response_extra_info,
+			'Network.loadingFinished': self._on_loading_finished,
+			'Network.loadingFailed': self._on_loading_failed,
+		}
+		for method, handler in handlers.items():
+			self._subscription_ids.append(self.browser_session.subscribe_cdp_event(method, handler))
+
+	# --- CDP event handlers ---
+
+	def _on_request_will_be_sent(self, event: 'CDPEvent') -> None:
+		params = event.params
+		if not self._enabled or params.get('type') not in _CAPTURED_RESOURCE_TYPES:
+			return
+		request = params.get('request', {})
+		url = request.get('url', '')
+		if self._patterns and not any(_matches(url, pattern) for pattern in self._patterns):
+			return
+
+		self._pending[params['requestId']] = CapturedNetworkRequest(
+			url=url,
+			method=request.get('method', 'GET'),
+			resource_type=params['type'],
+			request_body=request.get('postData'),
+			target_id=event.target_id,
+		)
+
+	def _on_response_extra_info(self, event: 'CDPEvent') -> None:
+		# Can arrive before requestWillBeSent, so it's kept for every request until it finishes or fails
+		if not self._enabled:
+			return
+		headers = {name.lower(): value for name, value in (event.params.get('headers') or {}).items()}
+		content_type = headers.get('content-type')
+		mime_type = content_type.split(';')[0].strip() if content_type else None
+		self._response_info[event.params['requestId']] = (event.params.get('statusCode'), mime_type)
+
+	def _take_request(self, request_id: str) -> CapturedNetworkRequest | None:
+		response_info = self._response_info.pop(request_id, None)
+		request = self._pending.pop(request_id, None)
+		if request is not None and response_info is not None:
+			request.status, request.mime_type = response_info
+		return request
+
+	def _on_loading_finished(self, event: 'CDPEvent') -> None:
+		request = self._take_request(event.params['requestId'])
+		if request is None:
+			return
+		task = asyncio.create_task(self._fetch_body(request, event.params['requestId'], event.session_id))
+		self._body_tasks.add(task)
+		task.add_done_callback(self._body_tasks.discard)
+
+	def _on_loading_failed(self, event: 'CDPEvent') -> None:
+		request = self._take_request(event.params['requestId'])
+		if request is None:
+			return
+		request.error = event.params.get('errorText') or 'Request failed'
+		self._store(request)
+
+	async def _fetch_body(self, request: CapturedNetworkRequest, request_id: str, session_id: str | None) -> None:
+		try:
+			response = await self.browser_session.cdp_client.send.Network.getResponseBody(
+				params={'requestId': request_id}, session_id=session_id
+			)
+			body = response.get('body', '')
+			if response.get('base64Encoded'):
+				raw = base64.b64decode(body)
+				if request.mime_type and any(marker in request.mime_type for marker in _TEXT_MIME_MARKERS):
+					body = raw.decode('utf-8', errors='replace')
+				else:
+					body = f'[binary body, {len(raw)} bytes]'
+			if len(body) > self._max_body_size:
+				body = body[: self._max_body_size]
+				request.body_truncated = True
+			request.body = body
+		except Exception as e:
+			self.logger.debug(f'[NetworkCaptureWatchdog] Failed to get response body of {request.url}: {e}')
+		self._store(request)
+
+	def _store(self, request: CapturedNetworkRequest) -> None:
+		self._requests.append(request)
+		if len(self._requests) > MAX_CAPTURED_REQUESTS:
+			del self._requests[: len(self._requests) - MAX_CAPTURED_REQUESTS]
+		self.logger.debug(f'[NetworkCaptureWatchdog] Captured {request.method} {request.status} {request.url}')
+
+
+def _matches(url: str, pattern: str) -> bool:
+	"""Glob match on the full URL, a pattern without wildcards matches URLs containing it."""
+	if any(char in pattern for char in '*?['):
+		return fnmatch.fnmatchcase(url, pattern)
+	return pattern in url
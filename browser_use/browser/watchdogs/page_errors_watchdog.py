@@ -0,0 +1,122 @@
+"""Watchdog that collects JavaScript errors and failed network requests so they can be shown to the agent."""
+
+from typing import Any, ClassVar
+
+from bubus import BaseEvent
+from pydantic import PrivateAttr
+
+from browser_use.browser.events import TabCreatedEvent
+from browser_use.browser.watchdog_base import BaseWatchdog
+from browser_use.utils import safe_slice
+
+# Errors kept per session until the agent consumes them, older entries are dropped first
+MAX_BUFFERED_PAGE_ERRORS = 50
+MAX_PAGE_ERROR_LENGTH = 300
+
+# Log.entryAdded sources that only produce noise for the agent (deprecations, interventions, ...)
+_IGNORED_LOG_SOURCES = {'deprecation', 'intervention', 'recommendation', 'violation'}
+
+
+class PageErrorsWatchdog(BaseWatchdog):
+	"""Collects uncaught exceptions, console.error calls and failed requests per tab.
+
+	Phase one happens here: CDP events are recorded into BrowserSession._page_errors as they arrive.
+	Phase two happens when the browser state is built: errors of the focused tab are attached to
+	BrowserStateSummary.page_errors, and the agent clears the buffer once they are in its state message.
+
+	Failed requests are reported through Log.entryAdded ("Failed to load resource: ... status of 500"),
+	so no Network handler is registered - cdp-use allows one handler per event and Network.responseReceived
+	is already owned by the DownloadsWatchdog.
+	"""
+
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent]
+	EMITS: ClassVar[list[type[BaseEvent]]] = []
+
+	_registered_client: Any = PrivateAttr(default=None)
+	_enabled_targets: set[str] = PrivateAttr(default_factory=set)
+
+	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
+		"""Enable the Runtime and Log domains for the new tab and make sure the global handlers are registered."""
+		if event.target_id in self._enabled_targets:
+			return
+
+		try:
+			self._register_handlers()
+			cdp_session = await self.browser_session.get_or_create_cdp_session(event.target_id, focus=False)
+			await cdp_session.cdp_client.send.Runtime.enable(session_id=cdp_session.session_id)
+			await cdp_session.cdp_client.send.Log.enable(session_id=cdp_session.session_id)
+			self._enabled_targets.add(event.target_id)
+		except Exception as e:
+			self.logger.debug(f'[PageErrorsWatchdog] Failed to enable error collection for tab {event.target_id[-4:]}: {e}')
+
+	def _register_handlers(self) -> None:
+		"""Register the CDP event handlers once per CDP client (the client is replaced on reconnect)."""
+		cdp_client = self.browser_session.cdp_client
+		if self._registered_client is cdp_client:
+			return
+
+		cdp_client.register.Runtime.exceptionThrown(self._on_exception_thrown)  # type: ignore[arg-type]
+		cdp_client.register.Runtime.consoleAPICalled(self._on_console_api_called)  # type: ignore[arg-type]
+		cdp_client.register.Log.entryAdded(self._on_log_entry_added)  # type: ignore[arg-type]
+		self._registered_client = cdp_client
+		self._enabled_targets.clear()
+
+	# --- CDP event handlers ---
+
+	def _on_exception_thrown(self, event: dict, session_id: str | None = None) -> None:
+		details = event.get('exceptionDetails', {})
+		description = (details.get('exception') or {}).get('description') or details.get('text') or 'Uncaught exception'
+		# The description contains the full stack trace, the first line is enough for the agent
+		message = description.strip().split('\n')[0]
+		location = _format_location(details.get('url'), details.get('lineNumber'))
+		self._record(session_id, f'[exception] {message}{location}')
+
+	def _on_console_api_called(self, event: dict, session_id: str | None = None) -> None:
+		if event.get('type') not in ('error', 'assert'):
+			return
+		args = [_remote_object_to_text(arg) for arg in event.get('args', [])]
+		message = ' '.join(arg for arg in args if arg) or '(empty)'
+		self._record(session_id, f'[console.error] {message}')
+
+	def _on_log_entry_added(self, event: dict, session_id: str | None = None) -> None:
+		entry = event.get('entry', {})
+		if entry.get('level') != 'error' or entry.get('source') in _IGNORED_LOG_SOURCES:
+			return
+		text = entry.get('text', '')
+		if entry.get('source') == 'network':
+			url = entry.get('url', '')
+			self._record(session_id, f'[network] {text} {url}'.strip())
+		else:
+			location = _format_location(entry.get('url'), entry.get('lineNumber'))
+			self._record(session_id, f'[{entry.get("source", "log")}] {text}{location}')
+
+	def _record(self, session_id: str | None, message: str) -> None:
+		target_id = None
+		if session_id and self.browser_session.session_manager:
+			target_id = self.browser_session.session_manager.get_target_id_from_session_id(session_id)
+		if target_id is None:
+			return
+
+		message = ' '.join(message.split())
+		if len(message) > MAX_PAGE_ERROR_LENGTH:
+			message = safe_slice(message, MAX_PAGE_ERROR_LENGTH) + '...'
+
+		page_errors = self.browser_session._page_errors
+		page_errors.append((target_id, message))
+		if len(page_errors) > MAX_BUFFERED_PAGE_ERRORS:
+			del page_errors[: len(page_errors) - MAX_BUFFERED_PAGE_ERRORS]
+		self.logger.debug(f'[PageErrorsWatchdog] {message}')
+
+
+def _format_location(url: str | None, line_number: int | None) -> str:
+	if not url:
+		return ''
+	if line_number is None:
+		return f' ({url})'
+	return f' ({url}:{line_number + 1})'  # CDP line numbers are 0-based
+
+
+def _remote_object_to_text(remote_object: dict) -> str:
+	if 'value' in remote_object:
+		return str(remote_object['value'])
+	return remote_object.get('description') or remote_object.get('unserializableValue') or ''
@@ -34,9 +34,10 @@ class PermissionsWatchdog(BaseWatchdog):
 			# Grant permissions using CDP Browser.grantPermissions
 			# origin=None means grant to all origins
 			# Browser domain commands don't use session_id
-			await self.browser_session.cdp_client.send.Browser.grantPermissions(
-				params={'permissions': permissions}  # type: ignore
-			)
+			params = {'permissions': permissions}
+			if self.browser_session.browser_context_id:
+				params['browserContextId'] = self.browser_session.browser_context_id
+			await self.browser_session.cdp_client.send.Browser.grantPermissions(params=params)  # type: ignore
 			self.logger.debug(f'✅ Successfully granted permissions: {permissions}')
 		except Exception as e:
 			self.logger.error(f'❌ Failed to grant permissions: {str(e)}')
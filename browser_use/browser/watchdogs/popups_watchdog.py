@@ -6,7 +6,7 @@ from typing import ClassVar
 from bubus import BaseEvent
 from pydantic import PrivateAttr
 
-from browser_use.browser.events import TabCreatedEvent
+from browser_use.browser.events import BrowserReconnectedEvent, TabCreatedEvent
 from browser_use.browser.watchdog_base import BaseWatchdog
 
 
@@ -14,7 +14,7 @@ class PopupsWatchdog(BaseWatchdog):
 	"""Handles JavaScript dialogs (alert, confirm, prompt) by automatically accepting them immediately."""
 
 	# Events this watchdog listens to and emits
-	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent]
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent, BrowserReconnectedEvent]
 	EMITS: ClassVar[list[type[BaseEvent]]] = []
 
 	# Track which targets have dialog handlers registered
@@ -24,6 +24,10 @@ class PopupsWatchdog(BaseWatchdog):
 		super().__init__(**kwargs)
 		self.logger.debug(f'🚀 PopupsWatchdog initialized with browser_session={self.browser_session}, ID={id(self)}')
 
+	async def on_BrowserReconnectedEvent(self, event: BrowserReconnectedEvent) -> None:
+		"""Dialog handlers lived on the old websocket, register them again as tabs are re-announced."""
+		self._dialog_listeners_registered.clear()
+
 	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
 		"""Set up JavaScript dialog handling when a new tab is created."""
 		target_id = event.target_id
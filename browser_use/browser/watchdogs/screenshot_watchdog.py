@@ -80,6 +80,11 @@ class ScreenshotWatchdog(BaseWatchdog):
 			# Return base64-encoded screenshot data
 			if result and 'data' in result:
 				self.logger.debug('[ScreenshotWatchdog] Screenshot captured successfully')
+				# A find_text highlight is meant for exactly one screenshot
+				if self.browser_session._text_match_highlighted:
+					await self.browser_session.remove_text_match_highlight()
+				if self.browser_session._metrics:
+					self.browser_session._metrics.record_screenshot(len(result['data']) * 3 // 4)  # decoded base64 size
 				return result['data']
 
 			raise BrowserError('[ScreenshotWatchdog] Screenshot result missing data')
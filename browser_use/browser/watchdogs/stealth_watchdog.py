@@ -0,0 +1,175 @@
+"""Watchdog that applies fingerprint patches and user agent / locale / timezone emulation to every tab."""
+
+import json
+from typing import Any, ClassVar
+
+from bubus import BaseEvent
+from pydantic import PrivateAttr
+
+from browser_use.browser.events import BrowserReconnectedEvent, TabClosedEvent, TabCreatedEvent
+from browser_use.browser.watchdog_base import BaseWatchdog
+
+# Patches for the most common headless / automation checks. Each patch is guarded so it is a no-op when
+# the browser already looks like a regular one (e.g. headful Chrome already has plugins and window.chrome).
+STEALTH_INIT_SCRIPT = """
+(() => {
+	const languages = __LANGUAGES__;
+	const defineGetter = (target, name, getter) => {
+		try { Object.defineProperty(target, name, {get: getter, configurable: true}); } catch (e) {}
+	};
+
+	// navigator.webdriver is true when the browser is controlled via CDP
+	defineGetter(Navigator.prototype, 'webdriver', () => undefined);
+
+	if (languages.length) {
+		defineGetter(Navigator.prototype, 'languages', () => Object.freeze([...languages]));
+	}
+
+	// Headless Chrome used to report an empty plugin list
+	if (!navigator.plugins || navigator.plugins.length === 0) {
+		const names = ['PDF Viewer', 'Chrome PDF Viewer', 'Chromium PDF Viewer', 'Microsoft Edge PDF Viewer'];
+		const plugins = names.map(name => ({
+			name, filename: 'internal-pdf-viewer', description: 'Portable Document Format', length: 1,
+		}));
+		plugins.item = i => plugins[i] || null;
+		plugins.namedItem = name => plugins.find(p => p.name === name) || null;
+		plugins.refresh = () => {};
+		Object.setPrototypeOf(plugins, PluginArray.prototype);
+		defineGetter(Navigator.prototype, 'plugins', () => plugins);
+	}
+
+	// window.chrome only exists in regular (non-headless) Chrome
+	if (!window.chrome) {
+		Object.defineProperty(window, 'chrome', {value: {runtime: {}, app: {isInstalled: false}}, configurable: true});
+	}
+
+	// Headless reports "denied" for notifications from the permissions API while Notification.permission is "default"
+	const originalQuery = navigator.permissions && navigator.permissions.query;
+	if (originalQuery) {
+		navigator.permissions.query = function (parameters) {
+			if (parameters && parameters.name === 'notifications' && typeof Notification !== 'undefined') {
+				return Promise.resolve({state: Notification.permission, onchange: null});
+			}
+			return originalQuery.call(this, parameters);
+		};
+	}
+})();
+"""
+
+
+def build_stealth_init_script(languages: list[str]) -> str:
+	return STEALTH_INIT_SCRIPT.replace('__LANGUAGES__', json.dumps(languages))
+
+
+def locale_to_languages(locale: str | None) -> list[str]:
+	"""'de-DE' -> ['de-DE', 'de'], matching what Chrome reports for a single configured language."""
+	if not locale:
+		return []
+	base = locale.split('-')[0]
+	return [locale, base] if base != locale else [locale]
+
+
+class StealthWatchdog(BaseWatchdog):
+	"""Makes automated tabs look like a regular browser to basic bot detection.
+
+	Applied when each tab is created, before the agent navigates it:
+	- stealth=True: fingerprint patches via Page.addScriptToEvaluateOnNewDocument (navigator.webdriver,
+	  plugins, languages, window.chrome, notification permission) and a user agent without "HeadlessChrome",
+	  including matching UA client hints (Sec-CH-UA brands)
+	- user_agent / locale / timezone_id: emulated over CDP, which also works for remote browsers where
+	  launch flags like --user-agent can't be used
+	Viewport emulation is handled by BrowserSession.on_TabCreatedEvent.
+	"""
+
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent, TabClosedEvent, BrowserReconnectedEvent]
+	EMITS: ClassVar[list[type[BaseEvent]]] = []
+
+	_patched_targets: set[str] = PrivateAttr(default_factory=set)
+	_user_agent_override: dict[str, Any] | None = PrivateAttr(default=None)
+
+	async def on_BrowserReconnectedEvent(self, event: BrowserReconnectedEvent) -> None:
+		# Overrides were set on the old CDP sessions, re-apply them when tabs are re-announced
+		self._patched_targets.clear()
+
+	async def on_TabClosedEvent(self, event: TabClosedEvent) -> None:
+		self._patched_targets.discard(event.target_id)
+
+	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
+		if event.target_id in self._patched_targets:
+			return
+		profile = self.browser_session.browser_profile
+
+		try:
+			cdp_session = await self.browser_session.get_or_create_cdp_session(event.target_id, focus=False)
+			send = cdp_session.cdp_client.send
+			session_id = cdp_session.session_id
+
+			if profile.stealth:
+				script = build_stealth_init_script(locale_to_languages(profile.locale))
+				await send.Page.addScriptToEvaluateOnNewDocument(
+					params={'source': script, 'runImmediately': True}, session_id=session_id
+				)
+
+			user_agent_override = await self._get_user_agent_override()
+			if user_agent_override:
+				await send.Emulation.setUserAgentOverride(
+					params=user_agent_override,  # type: ignore[arg-type]
+					session_id=session_id,
+				)
+
+			if profile.locale:
+				await send.Emulation.setLocaleOverride(params={'locale': profile.locale}, session_id=session_id)
+
+			if profile.timezone_id:
+				timezone_params = {'timezoneId': profile.timezone_id}
+				await send.Emulation.setTimezoneOverride(params=timezone_params, session_id=session_id)
+
+			self._patched_targets.add(event.target_id)
+		except Exception as e:
+			self.logger.warning(f'[StealthWatchdog] Failed to apply emulation settings to tab {event.target_id[-4:]}: {e}')
+
+	async def _get_user_agent_override(self) -> dict[str, Any] | None:
+		"""Build the Emulation.setUserAgentOverride params once per session, None if nothing needs overriding."""
+		if self._user_agent_override is not None:
+			return self._user_agent_override or None
+
+		profile = self.browser_session.browser_profile
+		override: dict[str, Any] = {}
+		if profile.user_agent or profile.stealth or profile.locale:
+			version = await self.browser_session.cdp_client.send.Browser.getVersion()
+			user_agent = profile.user_agent or version['userAgent']
+			if profile.stealth:
+				user_agent = user_agent.replace('HeadlessChrome', 'Chrome')
+			override['userAgent'] = user_agent
+			if profile.locale:
+				override['acceptLanguage'] = ','.join(locale_to_languages(profile.locale))
+			if profile.stealth and not profile.user_agent:
+				# Client hints must agree with the user agent string, otherwise Sec-CH-UA still says "HeadlessChrome"
+				override['userAgentMetadata'] = _user_agent_metadata(version['product'], user_agent)
+
+		self._user_agent_override = override
+		return override or None
+
+
+def _user_agent_metadata(product: str, user_agent: str) -> dict[str, Any]:
+	"""UA client hints for Emulation.setUserAgentOverride, product is Browser.getVersion's e.g. "Chrome/120.0.6099.71"."""
+	full_version = product.split('/')[-1]
+	major_version = full_version.split('.')[0]
+	platform, platform_version = 'Linux', ''
+	if 'Windows' in user_agent:
+		platform, platform_version = 'Windows', '10.0.0'
+	elif 'Macintosh' in user_agent:
+		platform, platform_version = 'macOS', '14.0.0'
+	brands = [('Not_A Brand', '8'), ('Chromium', major_version), ('Google Chrome', major_version)]
+	return {
+		'brands': [{'brand': brand, 'version': version} for brand, version in brands],
+		'fullVersionList': [
+			{'brand': brand, 'version': full_version if brand != 'Not_A Brand' else '8.0.0.0'} for brand, _ in brands
+		],
+		'fullVersion': full_version,
+		'platform': platform,
+		'platformVersion': platform_version,
+		'architecture': 'x86',
+		'model': '',
+		'mobile': False,
+	}
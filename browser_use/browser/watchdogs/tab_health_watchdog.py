@@ -0,0 +1,195 @@
+"""Watchdog that notices crashed or unexpectedly blank agent tabs and brings the agent back to its page."""
+
+import asyncio
+import time
+from typing import TYPE_CHECKING, ClassVar
+
+from bubus import BaseEvent
+from cdp_use.cdp.target import TargetID
+from pydantic import PrivateAttr
+
+from browser_use.browser.events import (
+	BrowserStoppedEvent,
+	CloseTabEvent,
+	NavigateToUrlEvent,
+	SwitchTabEvent,
+	TabClosedEvent,
+	TabCreatedEvent,
+)
+from browser_use.browser.views import TabRecovery
+from browser_use.browser.watchdog_base import BaseWatchdog
+from browser_use.utils import is_new_tab_page
+
+if TYPE_CHECKING:
+	from browser_use.browser.cdp_events import CDPEvent
+
+# How long a reloaded or reopened tab gets to respond again before recovery moves on
+RECOVERY_TIMEOUT_S = 10.0
+
+
+def _is_blank(url: str | None) -> bool:
+	return not url or is_new_tab_page(url)
+
+
+class TabHealthWatchdog(BaseWatchdog):
+	"""Tracks renderer crashes (Inspector.targetCrashed) and the last real page of the agent's tab.
+
+	Nothing is repaired in the background: the agent calls BrowserSession.recover_unhealthy_tab() before
+	each step, so a recovery never races an action and the model is told what happened in the same step.
+	A crashed tab is reloaded, or replaced by a new tab if it crashed again after a reload. A blank tab the
+	session fell back to after the agent's tab disappeared is navigated back to the last page. A tab that
+	goes blank by itself is left alone, the agent may have gone back, clicked or been redirected there.
+	With restrict_to_target the pinned tab is never replaced, a crashed pinned tab is reloaded every time.
+	"""
+
+	LISTENS_TO: ClassVar[list[type[BaseEvent]]] = [
+		TabCreatedEvent,
+		CloseTabEvent,
+		TabClosedEvent,
+		BrowserStoppedEvent,
+	]
+	EMITS: ClassVar[list[type[BaseEvent]]] = [TabCreatedEvent, SwitchTabEvent, CloseTabEvent, NavigateToUrlEvent]
+
+	_subscription_id: str | None = PrivateAttr(default=None)
+	_crashed_target_ids: set[TargetID] = PrivateAttr(default_factory=set)
+	_reloaded_target_ids: set[TargetID] = PrivateAttr(default_factory=set)  # crashed once already, replaced on the next crash
+	_last_target_id: TargetID | None = PrivateAttr(default=None)
+	_last_url: str | None = PrivateAttr(default=None)  # last non-blank page of the agent's tab
+
+	async def on_TabCreatedEvent(self, event: TabCreatedEvent) -> None:
+		"""Enable the Inspector domain of the new tab, it reports renderer crashes."""
+		if self._subscription_id is None:
+			self._subscription_id = self.browser_session.subscribe_cdp_event('Inspector.targetCrashed', self._on_target_crashed)
+		try:
+			cdp_session = await self.browser_session.get_or_create_cdp_session(event.target_id, focus=False)
+			await cdp_session.cdp_client.send.Inspector.enable(session_id=cdp_session.session_id)
+		except Exception as e:
+			self.logger.debug(f'[TabHealthWatchdog] Failed to enable crash detection for tab {event.target_id[-4:]}: {e}')
+
+	async def on_CloseTabEvent(self, event: CloseTabEvent) -> None:
+		# The agent closed its tab on purpose, the blank tab it may land on is not a lost page
+		if event.target_id == self._last_target_id:
+			self._last_url = None
+
+	async def on_TabClosedEvent(self, event: TabClosedEvent) -> None:
+		self._crashed_target_ids.discard(event.target_id)
+		self._reloaded_target_ids.discard(event.target_id)
+
+	async def on_BrowserStoppedEvent(self, event: BrowserStoppedEvent) -> None:
+		if self._subscription_id is not None:
+			self.browser_session.unsubscribe_cdp_event(self._subscription_id)
+			self._subscription_id = None
+		self._crashed_target_ids.clear()
+		self._reloaded_target_ids.clear()
+		self._last_target_id = self._last_url = None
+
+	def _on_target_crashed(self, event: 'CDPEvent') -> None:
+		if event.target_id is None:
+			return
+		self._crashed_target_ids.add(event.target_id)
+		self.logger.warning(f'💥 Tab {event.target_id[-4:]} crashed')
+
+	async def recover(self) -> TabRecovery | None:
+		"""Repair the agent's tab if it crashed or is gone, returns None when it is healthy."""
+		session_manager = self.browser_session.session_manager
+		assert session_manager is not None, 'SessionManager not initialized'
+		target = self.browser_session.get_focused_target()
+		if target is None:
+			# The session replaces a detached tab by itself, give it the chance before opening one here
+			await session_manager.ensure_valid_focus(timeout=5.0)
+			target = self.browser_session.get_focused_target()
+		if target is None:
+			pinned_target_id = self.browser_session.pinned_target_id
+			if pinned_target_id is not None:
+				# The session may not switch to any other tab, there is nothing to recover onto
+				self.logger.warning(f'⚠️ Restricted tab {pinned_target_id[-4:]} is gone, cannot open a replacement')
+				return None
+			target_id = await self._open_fresh_tab(self._last_url, replaces=None)
+			return self._recovered(TabRecovery(problem='missing', action='new_tab', url=self._last_url, target_id=target_id))
+
+		target_id, url = target.target_id, target.url
+		if target_id in self._crashed_target_ids:
+			return self._recovered(await self._recover_crash(target_id, url))
+
+		previous_target_id = self._last_target_id
+		if _is_blank(url) and self._last_url and previous_target_id is not None and previous_target_id != target_id:
+			# The agent's tab disappeared and the session fell back to a blank tab
+			if session_manager.get_target(previous_target_id) is None:
+				self.logger.warning(f'🩹 Tab {previous_target_id[-4:]} is gone, navigating back to {self._last_url}')
+				await self.browser_session.navigate_to(self._last_url)
+				recovery = TabRecovery(problem='blank', action='renavigated', url=self._last_url, target_id=target_id)
+				return self._recovered(recovery)
+
+		self._last_target_id = target_id
+		if not _is_blank(url):
+			self._last_url = url
+		return None
+
+	async def _recover_crash(self, target_id: TargetID, url: str) -> TabRecovery:
+		self._crashed_target_ids.discard(target_id)
+		url = url if not _is_blank(url) else self._last_url or url
+		# The pinned tab can't be replaced, the session refuses to switch away from it
+		pinned = target_id == self.browser_session.pinned_target_id
+		if pinned or target_id not in self._reloaded_target_ids:
+			self._reloaded_target_ids.add(target_id)
+			self.logger.warning(f'🩹 Reloading crashed tab {target_id[-4:]}')
+			try:
+				cdp_session = await self.browser_session.get_or_create_cdp_session(target_id, focus=False)
+				await cdp_session.cdp_client.send.Page.reload(session_id=cdp_session.session_id)
+				if await self._wait_until_responsive(target_id):
+					return TabRecovery(problem='crashed', action='reloaded', url=url, target_id=target_id)
+			except Exception as e:
+				self.logger.debug(f'[TabHealthWatchdog] Reloading crashed tab {target_id[-4:]} failed: {type(e).__name__}: {e}')
+			if pinned:
+				self.logger.warning(f'⚠️ Restricted tab {target_id[-4:]} did not respond after the reload')
+				return TabRecovery(problem='crashed', action='reload_failed', url=url, target_id=target_id)
+
+		self.logger.warning(f'🩹 Replacing crashed tab {target_id[-4:]} with a new tab')
+		new_target_id = await self._open_fresh_tab(url, replaces=target_id)
+		return TabRecovery(problem='crashed', action='new_tab', url=url, target_id=new_target_id)
+
+	async def _open_fresh_tab(self, url: str | None, replaces: TargetID | None) -> TargetID:
+		"""Open url in a new focused tab and close the tab it replaces.
+
+		Tabs are created directly rather than with NavigateToUrlEvent(new_tab=True), which may reuse the
+		broken tab when reuse_same_origin_tabs is on. Never called for a session restricted to a pinned tab.
+		"""
+		assert self.browser_session.pinned_target_id is None, 'Cannot open a new tab, the session is restricted to one tab'
+		url = url or 'about:blank'
+		new_target_id = await self.browser_session._cdp_create_new_page(url)
+		self.event_bus.dispatch(TabCreatedEvent(url=url, target_id=new_target_id))
+		await self.event_bus.dispatch(SwitchTabEvent(target_id=new_target_id))
+		if replaces is not None:
+			try:
+				await self.event_bus.dispatch(CloseTabEvent(target_id=replaces))
+			except Exception as e:
+				self.logger.debug(f'[TabHealthWatchdog] Failed to close replaced tab {replaces[-4:]}: {e}')
+		await self._wait_until_responsive(new_target_id)
+		return new_target_id
+
+	async def _wait_until_responsive(self, target_id: TargetID) -> bool:
+		"""Wait until the tab's renderer evaluates JavaScript again."""
+		deadline = time.monotonic() + RECOVERY_TIMEOUT_S
+		while time.monotonic() < deadline:
+			if target_id in self._crashed_target_ids:
+				return False
+			try:
+				cdp_session = await self.browser_session.get_or_create_cdp_session(target_id, focus=False)
+				result = await asyncio.wait_for(
+					cdp_session.cdp_client.send.Runtime.evaluate(
+						params={'expression': 'document.readyState', 'returnByValue': True}, session_id=cdp_session.session_id
+					),
+					timeout=1.0,
+				)
+				if result.get('result', {}).get('value') in ('interactive', 'complete'):
+					return True
+			except Exception:
+				pass  # the renderer is still starting
+			await asyncio.sleep(0.2)
+		return False
+
+	def _recovered(self, recovery: TabRecovery) -> TabRecovery:
+		self._last_target_id = recovery.target_id
+		if not _is_blank(recovery.url):
+			self._last_url = recovery.url
+		return recovery
@@ -108,6 +108,148 @@ def _run_install_command(argv: list[str]) -> int:
 	return result.returncode or 1
 
 
+def _run_serve_command(argv: list[str]) -> int:
+	import argparse
+	import asyncio
+	import os
+
+	parser = argparse.ArgumentParser(
+		prog='browser-use serve', description='Run agents on request over an HTTP/WebSocket JSON API.'
+	)
+	parser.add_argument('--host', default='127.0.0.1', help='interface to bind (default: 127.0.0.1)')
+	parser.add_argument('--port', type=int, default=9243, help='port to listen on (default: 9243)')
+	parser.add_argument('--model', help='default model for tasks that name none, e.g. openai_gpt_4_1_mini')
+	parser.add_argument(
+		'--token',
+		default=os.environ.get('BROWSER_USE_SERVER_TOKEN'),
+		help='token for "Authorization: Bearer <token>" (default: $BROWSER_USE_SERVER_TOKEN, or a random one)',
+	)
+	args = parser.parse_args(argv)
+
+	from browser_use.agent.control_server import AgentControlServer
+
+	llm = None
+	if args.model:
+		from browser_use.llm.models import get_llm_by_name
+
+		llm = get_llm_by_name(args.model)
+	if args.host not in ('127.0.0.1', 'localhost', '::1') and not args.token:
+		print('Refusing to listen on a public interface without --token', file=sys.stderr)
+		return 2
+
+	server = AgentControlServer(llm=llm, host=args.host, port=args.port, token=args.token)
+	if not args.token:
+		print(f'Clients must send "Authorization: Bearer {server.token}"')
+	try:
+		asyncio.run(server.serve_forever())
+	except KeyboardInterrupt:
+		pass
+	return 0
+
+
+# Exit codes of `browser-use run`
+EXIT_SUCCESS = 0
+EXIT_TASK_FAILED = 1  # the agent gave up, judged its result unsuccessful or ran out of steps
+EXIT_USAGE = 2
+EXIT_ERROR = 3  # the browser or the LLM failed before the agent finished
+EXIT_INTERRUPTED = 130
+
+
+def _run_agent_command(argv: list[str]) -> int:
+	import argparse
+	import asyncio
+	import json
+
+	parser = argparse.ArgumentParser(
+		prog='browser-use run',
+		description='Run an agent on a task and print its result.',
+		epilog='exit codes: 0 success, 1 task failed or unfinished, 2 usage error, 3 browser or LLM error, 130 interrupted',
+	)
+	parser.add_argument('task', help='what the agent should do')
+	parser.add_argument('--model', default='openai_gpt_4_1_mini', help='LLM to use (default: openai_gpt_4_1_mini)')
+	browser = parser.add_mutually_exclusive_group()
+	browser.add_argument('--cdp-url', help='connect to a running Chrome, e.g. http://localhost:9222')
+	browser.add_argument('--launch', action='store_true', help='launch a new Chrome (the default)')
+	parser.add_argument('--headless', action='store_true', help='launch Chrome without a window')
+	parser.add_argument('--max-steps', type=int, default=100, help='stop after this many steps (default: 100)')
+	parser.add_argument(
+		'--vision',
+		choices=('on', 'off', 'auto'),
+		default='on',
+		help='send screenshots to the LLM: every step, never, or when it asks for one (default: on)',
+	)
+	parser.add_argument('--artifacts-dir', help='save the run bundle (steps, screenshots, files, result) in this directory')
+	parser.add_argument('--json', action='store_true', help='print the result as a JSON object instead of text')
+	try:
+		args = parser.parse_args(argv)
+	except SystemExit as exc:
+		return exc.code if isinstance(exc.code, int) else EXIT_USAGE
+	if args.max_steps < 1:
+		print('--max-steps must be at least 1', file=sys.stderr)
+		return EXIT_USAGE
+	if args.headless and args.cdp_url:
+		print('--headless only applies to a launched browser, not to --cdp-url', file=sys.stderr)
+		return EXIT_USAGE
+
+	from browser_use.llm.models import get_llm_by_name
+
+	try:
+		llm = get_llm_by_name(args.model)
+	except Exception as e:
+		print(f'Unknown model {args.model!r}: {e}', file=sys.stderr)
+		return EXIT_USAGE
+
+	try:
+		summary = asyncio.run(_run_agent(args, llm))
+	except KeyboardInterrupt:
+		print('Interrupted', file=sys.stderr)
+		return EXIT_INTERRUPTED
+
+	if args.json:
+		print(json.dumps(summary, ensure_ascii=False, indent=2))
+	elif summary['final_result']:
+		print(summary['final_result'])
+	if summary['error']:
+		print(summary['error'], file=sys.stderr)
+		return EXIT_ERROR
+	return EXIT_SUCCESS if summary['success'] else EXIT_TASK_FAILED
+
+
+async def _run_agent(args, llm) -> dict:
+	from browser_use.agent.service import Agent
+	from browser_use.browser import BrowserProfile, BrowserSession
+
+	if args.cdp_url:
+		browser_session = BrowserSession(cdp_url=args.cdp_url)
+	else:
+		browser_session = BrowserSession(browser_profile=BrowserProfile(headless=args.headless))
+	vision = {'on': True, 'off': False, 'auto': 'auto'}[args.vision]
+
+	error: str | None = None
+	agent = None
+	try:
+		agent = Agent(
+			task=args.task, llm=llm, browser_session=browser_session, use_vision=vision, artifacts_dir=args.artifacts_dir
+		)
+		await agent.run(max_steps=args.max_steps)
+	except Exception as e:
+		error = f'{type(e).__name__}: {e}'
+
+	history = agent.history if agent is not None else None
+	is_done = history.is_done() if history else False
+	return {
+		'task': args.task,
+		'is_done': is_done,
+		'success': bool(is_done and history and history.is_successful() is not False),
+		'final_result': history.final_result() if history else None,
+		'steps': history.number_of_steps() if history else 0,
+		'duration_seconds': round(history.total_duration_seconds(), 2) if history else 0,
+		'urls': [url for url in history.urls() if url] if history else [],
+		'errors': [e for e in history.errors() if e] if history else [],
+		'error': error,
+	}
+
+
 def _run_init_command(argv: list[str]) -> int | None:
 	from browser_use.init_cmd import main as init_main
 
@@ -210,7 +352,6 @@ _LEGACY_HINTS: dict[str, str] = {
 	'eval': 'print(js("document.title"))',
 	'cookies': 'print(cdp("Network.getCookies"))',
 	'python': '# the CLI runs Python directly now — pipe it on stdin as shown below',
-	'run': '# write the steps as Python using the pre-imported helpers shown below',
 	'connect': '# connecting is automatic — the default flow attaches to your running Chrome',
 	'close': '# restart the local daemon with `browser-use --reload`; stop cloud browsers with stop_remote_daemon(name)',
 	'sessions': '# named local sessions were removed — one default daemon; use BU_NAME=<name> for cloud daemons',
@@ -342,6 +483,10 @@ def _command_name(args: list[str]) -> str:
 		return 'mcp'
 	if args and args[0] == 'install':
 		return 'install'
+	if args and args[0] == 'serve':
+		return 'serve'
+	if args and args[0] == 'run':
+		return 'agent-run'
 	if args and args[0] == 'init':
 		return 'init'
 	if '--template' in args or '-t' in args:
@@ -363,6 +508,10 @@ def _dispatch(args: list[str]) -> tuple[int | None, str]:
 		return 0, 'mcp'
 	if args and args[0] == 'install':
 		return _run_install_command(args[1:]), 'install'
+	if args and args[0] == 'serve':
+		return _run_serve_command(args[1:]), 'serve'
+	if args and args[0] == 'run':
+		return _run_agent_command(args[1:]), 'agent-run'
 	if args and args[0] == 'init':
 		return _run_init_command(args[1:]), 'init'
 	if '--template' in args or '-t' in args:
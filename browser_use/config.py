@@ -270,6 +270,13 @@ class LLMEntry(DBStyleEntry):
 	model: str | None = None
 	temperature: float | None = None
 	max_tokens: int | None = None
+	model_provider: str | None = None  # 'openai' (default), 'azure' or 'bedrock'
+	base_url: str | None = None
+	# Azure OpenAI: requests go to {azure_endpoint}/openai/deployments/{azure_deployment}?api-version={api_version}
+	azure_endpoint: str | None = None
+	azure_deployment: str | None = None
+	api_version: str | None = None
+	azure_ad_token: str | None = None  # Entra ID (Azure AD) token, instead of api_key
 
 
 class AgentEntry(DBStyleEntry):
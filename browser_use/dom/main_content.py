@@ -0,0 +1,130 @@
+# @file purpose: Readability-style main content detection and boilerplate filtering on the enhanced DOM tree
+
+import re
+
+from browser_use.dom.views import EnhancedDOMTreeNode, NodeType
+
+# Elements and ARIA landmarks that hold site chrome rather than page content
+BOILERPLATE_TAGS = {'nav', 'aside', 'footer', 'noscript', 'template', 'dialog'}
+BOILERPLATE_ROLES = {'navigation', 'banner', 'contentinfo', 'complementary', 'search', 'menubar', 'dialog', 'alertdialog'}
+
+# class/id fragments of boilerplate containers, matched as whole words (cookie-banner, site_footer, siteHeader)
+_BOILERPLATE_NAMES = re.compile(
+	r'(?:^|[\s_-]|(?<=[a-z])(?=[A-Z]))'
+	r'(?i:nav|navbar|navigation|menu|header|footer|sidebar|breadcrumbs?|cookie|consent|banner|share|social|'
+	r'related|recommended|comments?|advert|ads|promo|newsletter|subscribe|signup|popup|modal|skip-link)'
+	r'(?=$|[\s_A-Z-])'
+)
+
+# Blocks whose text counts towards the content score of their ancestors
+_SCORED_TAGS = {'p', 'pre', 'blockquote', 'td', 'li', 'dd'}
+_MIN_SCORED_TEXT = 25  # characters, shorter blocks (buttons, labels) score nothing
+_MIN_MAIN_TEXT = 200  # characters a <main>/<article> needs before it is trusted as the main content
+
+
+def _in_article(node: EnhancedDOMTreeNode) -> bool:
+	parent = node.parent_node
+	while parent is not None:
+		if parent.node_type == NodeType.ELEMENT_NODE and parent.tag_name in {'article', 'main'}:
+			return True
+		parent = parent.parent_node
+	return False
+
+
+def is_boilerplate(node: EnhancedDOMTreeNode) -> bool:
+	"""Whether an element is navigation, a header/footer, sidebar, cookie banner or similar site chrome.
+
+	The <header> of an article (title, author, date) is content, only the page header outside articles is dropped.
+	"""
+	if node.node_type != NodeType.ELEMENT_NODE:
+		return False
+	tag = node.tag_name
+	attributes = node.attributes or {}
+	if tag in BOILERPLATE_TAGS or (tag == 'header' and not _in_article(node)):
+		return True
+	if attributes.get('role', '').lower() in BOILERPLATE_ROLES or attributes.get('aria-hidden') == 'true':
+		return True
+	if tag in {'body', 'main', 'article'}:
+		return False
+	names = f'{attributes.get("class", "")} {attributes.get("id", "")}'
+	return bool(_BOILERPLATE_NAMES.search(names))
+
+
+def _children(node: EnhancedDOMTreeNode) -> list[EnhancedDOMTreeNode]:
+	children = node.children_and_shadow_roots
+	if node.content_document is not None:
+		children.append(node.content_document)
+	return children
+
+
+class _TextStats:
+	"""Text and link text lengths per element, computed once bottom-up."""
+
+	def __init__(self, root: EnhancedDOMTreeNode):
+		self.text: dict[int, int] = {}
+		self.link_text: dict[int, int] = {}
+		self.nodes: list[EnhancedDOMTreeNode] = []
+		self._collect(root, in_link=False)
+
+	def _collect(self, node: EnhancedDOMTreeNode, in_link: bool) -> tuple[int, int]:
+		if node.node_type == NodeType.TEXT_NODE:
+			length = len(' '.join((node.node_value or '').split()))
+			return length, length if in_link else 0
+		if node.node_type == NodeType.ELEMENT_NODE and node.tag_name in {'script', 'style', 'head', 'template'}:
+			return 0, 0
+		in_link = in_link or (node.node_type == NodeType.ELEMENT_NODE and node.tag_name == 'a')
+		text = link_text = 0
+		for child in _children(node):
+			child_text, child_link_text = self._collect(child, in_link)
+			text += child_text
+			link_text += child_link_text
+		self.text[id(node)] = text
+		self.link_text[id(node)] = link_text
+		self.nodes.append(node)
+		return text, link_text
+
+	def link_density(self, node: EnhancedDOMTreeNode) -> float:
+		text = self.text.get(id(node), 0)
+		return self.link_text.get(id(node), 0) / text if text else 1.0
+
+
+def find_main_content(root: EnhancedDOMTreeNode) -> EnhancedDOMTreeNode | None:
+	"""Find the element holding the page's main content, None if the page has no recognizable one.
+
+	Uses the page's own markup first (a single <main>, role=main or <article> with enough text), otherwise scores
+	containers by the paragraphs they hold, Readability style: each paragraph adds to its parent and half to its
+	grandparent, and containers lose score by the share of their text that is link text.
+	"""
+	stats = _TextStats(root)
+	elements = [node for node in stats.nodes if node.node_type == NodeType.ELEMENT_NODE]
+
+	for selector in (
+		lambda node: node.tag_name == 'main' or (node.attributes or {}).get('role') == 'main',
+		lambda node: node.tag_name == 'article',
+	):
+		matches = [node for node in elements if selector(node) and stats.text[id(node)] >= _MIN_MAIN_TEXT]
+		if len(matches) == 1:
+			return matches[0]
+
+	scores: dict[int, float] = {}
+	candidates: dict[int, EnhancedDOMTreeNode] = {}
+	for node in elements:
+		if node.tag_name not in _SCORED_TAGS or stats.text[id(node)] < _MIN_SCORED_TEXT:
+			continue
+		text = stats.text[id(node)]
+		score = 1 + min(text / 100, 3)
+		parent = node.parent_node
+		for weight in (1.0, 0.5):
+			if parent is None or parent.node_type != NodeType.ELEMENT_NODE or parent.tag_name in {'body', 'html'}:
+				break
+			scores[id(parent)] = scores.get(id(parent), 0.0) + score * weight
+			candidates[id(parent)] = parent
+			parent = parent.parent_node
+
+	best: EnhancedDOMTreeNode | None = None
+	best_score = 0.0
+	for key, node in candidates.items():
+		score = scores[key] * (1 - stats.link_density(node))
+		if score > best_score:
+			best, best_score = node, score
+	return best
@@ -11,6 +11,7 @@ from dataclasses import dataclass
 from enum import Enum, auto
 from typing import TYPE_CHECKING, Any
 
+from browser_use.dom.main_content import find_main_content
 from browser_use.dom.serializer.html_serializer import HTMLSerializer
 from browser_use.dom.service import DomService
 from browser_use.dom.views import MarkdownChunk
@@ -26,6 +27,8 @@ async def extract_clean_markdown(
 	target_id: str | None = None,
 	extract_links: bool = False,
 	extract_images: bool = False,
+	base_url: str | None = None,
+	main_content_only: bool = False,
 ) -> tuple[str, dict[str, Any]]:
 	"""Extract clean markdown from browser content using enhanced DOM tree.
 
@@ -38,6 +41,8 @@ async def extract_clean_markdown(
 	    target_id: Target ID for the page (required when using dom_service)
 	    extract_links: Whether to preserve links in markdown
 	    extract_images: Whether to preserve inline image src URLs in markdown
+	    base_url: URL to resolve relative links against, defaults to the current page URL (browser_session path)
+	    main_content_only: Keep only the page's main content, without navigation, headers, footers, sidebars and banners
 
 	Returns:
 	    tuple: (clean_markdown_content, content_statistics)
@@ -50,8 +55,19 @@ async def extract_clean_markdown(
 		if dom_service is not None or target_id is not None:
 			raise ValueError('Cannot specify both browser_session and dom_service/target_id')
 		# Browser session path (tools service)
-		enhanced_dom_tree = await _get_enhanced_dom_tree_from_browser_session(browser_session)
 		current_url = await browser_session.get_current_page_url()
+		# The PDF viewer has no DOM with the document's text, read the PDF itself
+		pdf_text = await _get_pdf_text_from_browser_session(browser_session, current_url)
+		if pdf_text is not None:
+			return pdf_text, {
+				'method': 'pdf',
+				'original_html_chars': len(pdf_text),
+				'initial_markdown_chars': len(pdf_text),
+				'filtered_chars_removed': 0,
+				'final_filtered_chars': len(pdf_text),
+				'url': current_url,
+			}
+		enhanced_dom_tree = await _get_enhanced_dom_tree_from_browser_session(browser_session)
 		method = 'enhanced_dom_tree'
 	elif dom_service is not None and target_id is not None:
 		# DOM service path (page actor)
@@ -63,8 +79,11 @@ async def extract_clean_markdown(
 		raise ValueError('Must provide either browser_session or both dom_service and target_id')
 
 	# Use the HTML serializer with the enhanced DOM tree
-	html_serializer = HTMLSerializer(extract_links=extract_links)
-	page_html = html_serializer.serialize(enhanced_dom_tree)
+	html_serializer = HTMLSerializer(
+		extract_links=extract_links, base_url=base_url or current_url, skip_boilerplate=main_content_only
+	)
+	main_content = find_main_content(enhanced_dom_tree) if main_content_only else None
+	page_html = html_serializer.serialize(main_content or enhanced_dom_tree)
 
 	original_html_length = len(page_html)
 
@@ -81,6 +100,10 @@ async def extract_clean_markdown(
 		'final_filtered_chars': final_filtered_length,
 	}
 
+	if main_content_only:
+		# Tag of the detected main content element, 'page' when none was found and only boilerplate was removed
+		stats['main_content'] = main_content.tag_name if main_content is not None else 'page'
+
 	# Add URL to stats if available
 	if current_url:
 		stats['url'] = current_url
@@ -88,6 +111,16 @@ async def extract_clean_markdown(
 	return content, stats
 
 
+async def _get_pdf_text_from_browser_session(browser_session: 'BrowserSession', current_url: str) -> str | None:
+	"""Get the text of the PDF shown in the focused tab, None if the page isn't a readable PDF."""
+	downloads_watchdog = browser_session._downloads_watchdog
+	target_id = browser_session.agent_focus_target_id
+	if downloads_watchdog is None or target_id is None or not downloads_watchdog.is_pdf_document(current_url):
+		return None
+	pdf = await downloads_watchdog.read_pdf(target_id)
+	return pdf[0] if pdf and pdf[0] else None  # scanned PDFs have no text, fall back to the DOM
+
+
 async def _get_enhanced_dom_tree_from_browser_session(browser_session: 'BrowserSession'):
 	"""Get enhanced DOM tree from browser session via DOMWatchdog."""
 	# Get the enhanced DOM tree from DOMWatchdog
@@ -133,6 +166,7 @@ def convert_html_to_markdown(page_html: str, extract_images: bool = False) -> tu
 		autolinks=False,  # Don't convert URLs to <> format
 		default_title=False,  # Don't add default title attributes
 		keep_inline_images_in=_keep_inline_images_in,  # Include image src URLs when extract_images=True
+		table_infer_header=True,  # Use the first row as header for tables without <th> cells
 	)
 
 	initial_markdown_length = len(content)
@@ -1,5 +1,8 @@
 # @file purpose: Serializes enhanced DOM trees to HTML format including shadow roots
 
+from urllib.parse import urljoin
+
+from browser_use.dom.main_content import is_boilerplate
 from browser_use.dom.views import EnhancedDOMTreeNode, NodeType
 
 
@@ -16,13 +19,17 @@ class HTMLSerializer:
 	enhanced tree including shadow roots that are crucial for modern SPAs.
 	"""
 
-	def __init__(self, extract_links: bool = False):
+	def __init__(self, extract_links: bool = False, base_url: str | None = None, skip_boilerplate: bool = False):
 		"""Initialize the HTML serializer.
 
 		Args:
 			extract_links: If True, preserves all links. If False, removes href attributes.
+			base_url: Page URL used to resolve relative hrefs to absolute URLs when extracting links.
+			skip_boilerplate: If True, leaves out navigation, headers/footers, sidebars, cookie banners and the like.
 		"""
 		self.extract_links = extract_links
+		self.base_url = base_url
+		self.skip_boilerplate = skip_boilerplate
 
 	def serialize(self, node: EnhancedDOMTreeNode, depth: int = 0) -> str:
 		"""Serialize an enhanced DOM tree node to HTML.
@@ -70,6 +77,9 @@ class HTMLSerializer:
 			if tag_name in {'style', 'script', 'head', 'meta', 'link', 'title'}:
 				return ''
 
+			if self.skip_boilerplate and is_boilerplate(node):
+				return ''
+
 			# Skip code tags with display:none - these often contain JSON state for SPAs
 			if tag_name == 'code' and node.attributes:
 				style = node.attributes.get('style', '')
@@ -257,8 +267,14 @@ class HTMLSerializer:
 		parts = []
 		for key, value in attributes.items():
 			# Skip href if not extracting links
-			if not self.extract_links and key == 'href':
-				continue
+			if key == 'href':
+				if not self.extract_links:
+					continue
+				# javascript: links can't be followed, and relative links are useless once out of page context
+				if value.strip().lower().startswith('javascript:'):
+					continue
+				if self.base_url and value:
+					value = urljoin(self.base_url, value.strip())
 
 			# Skip data-* attributes as they often contain JSON payloads
 			# These are used by modern SPAs (React, Vue, Angular) for state management
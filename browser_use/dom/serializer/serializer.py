@@ -69,14 +69,15 @@ class DOMTreeSerializer:
 		self._interactive_counter = 1
 		self._selector_map: DOMSelectorMap = {}
 		self._previous_cached_selector_map = previous_cached_state.selector_map if previous_cached_state else None
-		self._previous_node_ids = (
-			{
-				(str(previous_node.session_id), previous_node.backend_node_id)
-				for previous_node in self._previous_cached_selector_map.values()
-			}
-			if self._previous_cached_selector_map
-			else set()
-		)
+		# Index each element had in the previous state, kept while the element stays on the page
+		self._previous_index_by_node_id = {
+			(str(previous_node.session_id), previous_node.backend_node_id): index
+			for index, previous_node in (self._previous_cached_selector_map or {}).items()
+		}
+		self._previous_node_ids = set(self._previous_index_by_node_id)
+		self._previous_index_values = set(self._previous_index_by_node_id.values())
+		# Indices of previous elements by identity, handed to an identical element that replaced one (e.g. re-rendered)
+		self._previous_index_by_identity: dict[tuple[str, int, int], int] | None = None
 		# Add timing tracking
 		self.timing_info: dict[str, float] = {}
 		# Cache for clickable element detection to avoid redundant calls
@@ -119,6 +120,7 @@ class DOMTreeSerializer:
 		self._clickable_cache = {}  # Clear cache for new serialization
 		self._reserved_backend_node_ids = set()
 		self._next_synthetic_index = 1
+		self._previous_index_by_identity = None
 
 		# Step 1: Create simplified tree (includes clickable element detection)
 		start_step1 = time.time()
@@ -642,12 +644,60 @@ class DOMTreeSerializer:
 			stack.extend(node.children)
 		self._next_synthetic_index = max(self._reserved_backend_node_ids, default=0) + 1
 
-	def _allocate_selector_index(self, backend_node_id: int) -> int:
-		"""Preserve unique backend IDs and allocate a collision-free model index otherwise."""
-		if backend_node_id not in self._selector_map:
-			return backend_node_id
+	@staticmethod
+	def _element_identity(node: EnhancedDOMTreeNode) -> tuple[str, int, int]:
+		"""Identity of an element that survives re-rendering: its tab, document and tag path, attributes, role and name."""
+		document = node
+		while document.parent_node is not None:
+			document = document.parent_node
+		return (str(node.target_id), document.backend_node_id, node.compute_stable_hash())
+
+	def _build_previous_identities(self) -> dict[tuple[str, int, int], int]:
+		"""Map the identities of the previous elements that are gone from the page to their indices."""
+		current_node_ids = set()
+		stack = [self.root_node]
+		while stack:
+			current = stack.pop()
+			current_node_ids.add((str(current.session_id), current.backend_node_id))
+			stack.extend(current.children_and_shadow_roots)
+			if current.content_document:
+				stack.append(current.content_document)
+
+		by_identity: dict[tuple[str, int, int], int] = {}
+		ambiguous: set[tuple[str, int, int]] = set()
+		for index, previous_node in (self._previous_cached_selector_map or {}).items():
+			identity = self._element_identity(previous_node)
+			if identity in by_identity:
+				ambiguous.add(identity)  # Identical elements, e.g. rows of "Edit" buttons, can't be told apart
+			elif (str(previous_node.session_id), previous_node.backend_node_id) not in current_node_ids:
+				by_identity[identity] = index
+		return {identity: index for identity, index in by_identity.items() if identity not in ambiguous}
+
+	def _sticky_selector_index(self, node: EnhancedDOMTreeNode) -> int | None:
+		"""The index the element, or an identical element it replaced, had in the previous state of the page."""
+		index = self._previous_index_by_node_id.get((str(node.session_id), node.backend_node_id))
+		if index is None:
+			if self._previous_index_by_identity is None:
+				self._previous_index_by_identity = self._build_previous_identities() if self._previous_node_ids else {}
+			if not self._previous_index_by_identity:
+				return None
+			index = self._previous_index_by_identity.pop(self._element_identity(node), None)
+		# Another element of the page may own the index by now
+		if index is None or index in self._selector_map:
+			return None
+		if index != node.backend_node_id and index in self._reserved_backend_node_ids:
+			return None
+		return index
 
-		while self._next_synthetic_index in self._reserved_backend_node_ids:
+	def _allocate_selector_index(self, node: EnhancedDOMTreeNode) -> int:
+		"""Keep the index of the previous state, preserve unique backend IDs, and allocate a collision-free index otherwise."""
+		sticky_index = self._sticky_selector_index(node)
+		if sticky_index is not None:
+			return sticky_index
+		if node.backend_node_id not in self._selector_map:
+			return node.backend_node_id
+
+		while self._next_synthetic_index in self._reserved_backend_node_ids or self._next_synthetic_index in self._selector_map:
 			self._next_synthetic_index += 1
 		selector_index = self._next_synthetic_index
 		self._next_synthetic_index += 1
@@ -748,7 +798,7 @@ class DOMTreeSerializer:
 			if should_make_interactive:
 				# Mark node as interactive
 				node.is_interactive = True
-				node.selector_index = self._allocate_selector_index(node.original_node.backend_node_id)
+				node.selector_index = self._allocate_selector_index(node.original_node)
 				self._selector_map[node.selector_index] = node.original_node
 				self._interactive_counter += 1
 
@@ -756,9 +806,9 @@ class DOMTreeSerializer:
 				if node.is_compound_component:
 					node.is_new = True
 				elif self._previous_node_ids:
-					# Check if node is new for regular elements
+					# Check if node is new for regular elements, an element keeping the index of one it replaced is not
 					current_node_id = (str(node.original_node.session_id), node.original_node.backend_node_id)
-					if current_node_id not in self._previous_node_ids:
+					if current_node_id not in self._previous_node_ids and node.selector_index not in self._previous_index_values:
 						node.is_new = True
 
 		# Process children
@@ -919,8 +969,18 @@ class DOMTreeSerializer:
 		return False
 
 	@staticmethod
-	def serialize_tree(node: SimplifiedNode | None, include_attributes: list[str], depth: int = 0) -> str:
-		"""Serialize the optimized tree to string format."""
+	def serialize_tree(
+		node: SimplifiedNode | None,
+		include_attributes: list[str],
+		depth: int = 0,
+		max_attribute_length: int = 100,
+		box_origin: tuple[float, float] | None = None,
+	) -> str:
+		"""Serialize the optimized tree to string format, attribute values are capped at max_attribute_length chars.
+
+		With box_origin (the page scroll position), interactive elements get a box=x,y,width,height attribute in
+		viewport CSS pixels.
+		"""
 		if not node:
 			return ''
 
@@ -928,7 +988,7 @@ class DOMTreeSerializer:
 		if hasattr(node, 'excluded_by_parent') and node.excluded_by_parent:
 			formatted_text = []
 			for child in node.children:
-				child_text = DOMTreeSerializer.serialize_tree(child, include_attributes, depth)
+				child_text = DOMTreeSerializer.serialize_tree(child, include_attributes, depth, max_attribute_length, box_origin)
 				if child_text:
 					formatted_text.append(child_text)
 			return '\n'.join(formatted_text)
@@ -941,7 +1001,9 @@ class DOMTreeSerializer:
 			# Skip displaying nodes marked as should_display=False
 			if not node.should_display:
 				for child in node.children:
-					child_text = DOMTreeSerializer.serialize_tree(child, include_attributes, depth)
+					child_text = DOMTreeSerializer.serialize_tree(
+						child, include_attributes, depth, max_attribute_length, box_origin
+					)
 					if child_text:
 						formatted_text.append(child_text)
 				return '\n'.join(formatted_text)
@@ -965,7 +1027,13 @@ class DOMTreeSerializer:
 					new_prefix = '*' if node.is_new else ''
 					line += f'{new_prefix}[{node.selector_index}]'
 				line += '<svg'
-				attributes_html_str = DOMTreeSerializer._build_attributes_string(node.original_node, include_attributes, '')
+				attributes_html_str = DOMTreeSerializer._build_attributes_string(
+					node.original_node, include_attributes, '', max_attribute_length
+				)
+				if node.is_interactive and box_origin is not None:
+					box_attr = DOMTreeSerializer._box_attribute(node.original_node, box_origin)
+					if box_attr:
+						attributes_html_str = f'{attributes_html_str} {box_attr}' if attributes_html_str else box_attr
 				if attributes_html_str:
 					line += f' {attributes_html_str}'
 				line += ' /> <!-- SVG content collapsed -->'
@@ -987,7 +1055,7 @@ class DOMTreeSerializer:
 				# Build attributes string with compound component info
 				text_content = ''
 				attributes_html_str = DOMTreeSerializer._build_attributes_string(
-					node.original_node, include_attributes, text_content
+					node.original_node, include_attributes, text_content, max_attribute_length
 				)
 
 				# Add compound component information to attributes if present
@@ -1025,6 +1093,11 @@ class DOMTreeSerializer:
 						else:
 							attributes_html_str = compound_attr
 
+				if node.is_interactive and box_origin is not None:
+					box_attr = DOMTreeSerializer._box_attribute(node.original_node, box_origin)
+					if box_attr:
+						attributes_html_str = f'{attributes_html_str} {box_attr}' if attributes_html_str else box_attr
+
 				# Build the line with shadow host indicator
 				shadow_prefix = ''
 				if node.is_shadow_host:
@@ -1078,7 +1151,9 @@ class DOMTreeSerializer:
 
 			# Process shadow DOM children
 			for child in node.children:
-				child_text = DOMTreeSerializer.serialize_tree(child, include_attributes, next_depth)
+				child_text = DOMTreeSerializer.serialize_tree(
+					child, include_attributes, next_depth, max_attribute_length, box_origin
+				)
 				if child_text:
 					formatted_text.append(child_text)
 
@@ -1101,7 +1176,9 @@ class DOMTreeSerializer:
 		# Process children (for non-shadow elements)
 		if node.original_node.node_type != NodeType.DOCUMENT_FRAGMENT_NODE:
 			for child in node.children:
-				child_text = DOMTreeSerializer.serialize_tree(child, include_attributes, next_depth)
+				child_text = DOMTreeSerializer.serialize_tree(
+					child, include_attributes, next_depth, max_attribute_length, box_origin
+				)
 				if child_text:
 					formatted_text.append(child_text)
 
@@ -1125,7 +1202,18 @@ class DOMTreeSerializer:
 		return '\n'.join(formatted_text)
 
 	@staticmethod
-	def _build_attributes_string(node: EnhancedDOMTreeNode, include_attributes: list[str], text: str) -> str:
+	def _box_attribute(node: EnhancedDOMTreeNode, box_origin: tuple[float, float]) -> str:
+		"""box=x,y,width,height of an element relative to the viewport, empty if it has no layout."""
+		box = node.absolute_position
+		if box is None or (box.width <= 0 and box.height <= 0):
+			return ''
+		x, y = round(box.x - box_origin[0]), round(box.y - box_origin[1])
+		return f'box={x},{y},{round(box.width)},{round(box.height)}'
+
+	@staticmethod
+	def _build_attributes_string(
+		node: EnhancedDOMTreeNode, include_attributes: list[str], text: str, max_attribute_length: int = 100
+	) -> str:
 		"""Build the attributes string for an element."""
 		attributes_to_include = {}
 
@@ -1319,7 +1407,7 @@ class DOMTreeSerializer:
 			# Format attributes, wrapping empty values in quotes for clarity
 			formatted_attrs = []
 			for key, value in attributes_to_include.items():
-				capped_value = cap_text_length(value, 100)
+				capped_value = cap_text_length(value, max_attribute_length)
 				# Show empty values as key='' instead of key=
 				if not capped_value:
 					formatted_attrs.append(f"{key}=''")
@@ -1,8 +1,11 @@
+from browser_use.utils import safe_slice
+
+
 def cap_text_length(text: str, max_length: int) -> str:
 	"""Cap text length for display."""
 	if len(text) <= max_length:
 		return text
-	return text[:max_length] + '...'
+	return safe_slice(text, max_length) + '...'
 
 
 def generate_css_selector_for_element(enhanced_node) -> str | None:
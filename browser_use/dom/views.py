@@ -939,6 +939,8 @@ class SerializedDOMState:
 	def llm_representation(
 		self,
 		include_attributes: list[str] | None = None,
+		max_attribute_length: int = 100,
+		box_origin: tuple[float, float] | None = None,
 	) -> str:
 		"""Kinda ugly, but leaving this as an internal method because include_attributes are a parameter on the agent, so we need to leave it as a 2 step process"""
 		from browser_use.dom.serializer.serializer import DOMTreeSerializer
@@ -948,7 +950,9 @@ class SerializedDOMState:
 
 		include_attributes = include_attributes or DEFAULT_INCLUDE_ATTRIBUTES
 
-		return DOMTreeSerializer.serialize_tree(self._root, include_attributes)
+		return DOMTreeSerializer.serialize_tree(
+			self._root, include_attributes, max_attribute_length=max_attribute_length, box_origin=box_origin
+		)
 
 	@observe_debug(ignore_input=True, ignore_output=True, name='eval_representation')
 	def eval_representation(
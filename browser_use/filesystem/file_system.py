@@ -2,15 +2,22 @@ import asyncio
 import base64
 import csv
 import io
+import logging
 import os
 import re
 import shutil
+import tempfile
+import weakref
 from abc import ABC, abstractmethod
 from concurrent.futures import ThreadPoolExecutor
 from pathlib import Path
 from typing import Any
 
+import psutil
 from pydantic import BaseModel, Field
+from uuid_extensions import uuid7str
+
+logger = logging.getLogger(__name__)
 
 UNSUPPORTED_BINARY_EXTENSIONS = {
 	'png',
@@ -74,6 +81,48 @@ def _build_filename_error_message(file_name: str, supported_extensions: list[str
 
 
 DEFAULT_FILE_SYSTEM_PATH = 'browseruse_agent_data'
+_OWNER_FILE = '.browseruse_owner'  # marks data dirs created by FileSystem, holds the pid of the process using it
+
+# Data dirs claimed by live file systems of this process with own_data_dir=True, an entry goes away with its file system
+_claimed_data_dirs: 'weakref.WeakValueDictionary[str, FileSystem]' = weakref.WeakValueDictionary()
+
+
+def atomic_write_bytes(path: Path, data: bytes) -> None:
+	"""Write to a temp file next to path and rename it over path, so readers never see a partial file"""
+	tmp_path = path.with_name(f'.{path.name}.{uuid7str()[-8:]}.tmp')
+	try:
+		tmp_path.write_bytes(data)
+		os.replace(tmp_path, path)
+	finally:
+		tmp_path.unlink(missing_ok=True)
+
+
+def atomic_write_text(path: Path, text: str) -> None:
+	atomic_write_bytes(path, text.encode('utf-8'))
+
+
+def _data_dir_in_use(data_dir: Path) -> bool:
+	"""Whether a file system of this process claimed data_dir, or a file system of another running process uses it"""
+	if os.path.abspath(data_dir) in _claimed_data_dirs:
+		return True
+	try:
+		pid = int((data_dir / _OWNER_FILE).read_text())
+	except (OSError, ValueError):
+		return False
+	return pid != os.getpid() and psutil.pid_exists(pid)
+
+
+def _free_data_dir(base_dir: Path) -> Path:
+	"""The default data dir under base_dir, or the first numbered sibling not in use (browseruse_agent_data_2, _3, ...)
+
+	Numbered dirs are reused and cleaned like the default one, so they don't pile up across runs.
+	"""
+	data_dir = base_dir / DEFAULT_FILE_SYSTEM_PATH
+	number = 2
+	while _data_dir_in_use(data_dir):
+		data_dir = base_dir / f'{DEFAULT_FILE_SYSTEM_PATH}_{number}'
+		number += 1
+	return data_dir
 
 
 class FileSystemError(Exception):
@@ -109,13 +158,12 @@ class BaseFile(BaseModel, ABC):
 		self.content = content
 
 	def sync_to_disk_sync(self, path: Path) -> None:
-		file_path = path / self.full_name
-		file_path.write_text(self.content)
+		atomic_write_text(path / self.full_name, self.content)
 
 	async def sync_to_disk(self, path: Path) -> None:
-		file_path = path / self.full_name
+		file_path, content = path / self.full_name, self.content
 		with ThreadPoolExecutor() as executor:
-			await asyncio.get_event_loop().run_in_executor(executor, lambda: file_path.write_text(self.content))
+			await asyncio.get_event_loop().run_in_executor(executor, lambda: atomic_write_text(file_path, content))
 
 	async def write(self, content: str, path: Path) -> None:
 		self.write_file_content(content)
@@ -251,9 +299,11 @@ class PdfFile(BaseFile):
 		from reportlab.platypus import Paragraph, SimpleDocTemplate, Spacer
 
 		file_path = path / self.full_name
+		# Built next to the target and renamed over it, readers never see a half-written PDF
+		tmp_path = file_path.with_name(f'.{file_path.name}.{uuid7str()[-8:]}.tmp')
 		try:
 			# Create PDF document
-			doc = SimpleDocTemplate(str(file_path), pagesize=letter)
+			doc = SimpleDocTemplate(str(tmp_path), pagesize=letter)
 			styles = getSampleStyleSheet()
 			story = []
 
@@ -278,8 +328,11 @@ class PdfFile(BaseFile):
 					story.append(Spacer(1, 6))
 
 			doc.build(story)
+			os.replace(tmp_path, file_path)
 		except Exception as e:
 			raise FileSystemError(f"Error: Could not write to file '{self.full_name}'. {str(e)}")
+		finally:
+			tmp_path.unlink(missing_ok=True)
 
 	async def sync_to_disk(self, path: Path) -> None:
 		with ThreadPoolExecutor() as executor:
@@ -295,6 +348,7 @@ class DocxFile(BaseFile):
 
 	def sync_to_disk_sync(self, path: Path) -> None:
 		file_path = path / self.full_name
+		tmp_path = file_path.with_name(f'.{file_path.name}.{uuid7str()[-8:]}.tmp')
 		try:
 			from docx import Document
 
@@ -317,9 +371,12 @@ class DocxFile(BaseFile):
 				else:
 					doc.add_paragraph()  # Empty paragraph for spacing
 
-			doc.save(str(file_path))
+			doc.save(str(tmp_path))
+			os.replace(tmp_path, file_path)
 		except Exception as e:
 			raise FileSystemError(f"Error: Could not write to file '{self.full_name}'. {str(e)}")
+		finally:
+			tmp_path.unlink(missing_ok=True)
 
 	async def sync_to_disk(self, path: Path) -> None:
 		with ThreadPoolExecutor() as executor:
@@ -347,23 +404,59 @@ class FileSystemState(BaseModel):
 
 	files: dict[str, dict[str, Any]] = Field(default_factory=dict)  # full filename -> file data
 	base_dir: str
+	data_dir: str | None = None  # None for states saved before per-agent data dirs, the default under base_dir
 	extracted_content_count: int = 0
+	in_memory: bool = False
+	max_file_size: int | None = None
+	max_total_size: int | None = None
+	allowed_extensions: list[str] | None = None
+	fallback_reason: str | None = None  # why an in-memory file system replaced the requested directory
 
 
 class FileSystem:
-	"""Enhanced file system with in-memory storage and multiple file type support"""
+	"""Enhanced file system with in-memory storage and multiple file type support
+
+	Files are kept in memory and mirrored to `base_dir/browseruse_agent_data`. Optional limits:
+	- max_file_size: max size of a single file in bytes (UTF-8 encoded content)
+	- max_total_size: max combined size of all files in bytes
+	- allowed_extensions: restrict writable file types, e.g. ['md', 'txt', 'json', 'csv', 'pdf']
+
+	With own_data_dir=True (agents sharing a file_system_path use it) the data dir is not shared: while another file
+	system claimed the default one the same way, or one of another running process uses it, the first free
+	`browseruse_agent_data_<n>` sibling is used instead. Pass data_dir to pick the location explicitly, it is only
+	cleared when empty or created by a FileSystem before. Writes to the same file are serialized and every file is
+	replaced atomically on disk.
+	"""
+
+	in_memory: bool = False
+
+	def __init__(
+		self,
+		base_dir: str | Path,
+		create_default_files: bool = True,
+		max_file_size: int | None = None,
+		max_total_size: int | None = None,
+		allowed_extensions: list[str] | None = None,
+		data_dir: str | Path | None = None,
+		own_data_dir: bool = False,
+	):
+		if max_file_size is not None and max_file_size <= 0:
+			raise ValueError(f'max_file_size must be positive, got {max_file_size}')
+		if max_total_size is not None and max_total_size <= 0:
+			raise ValueError(f'max_total_size must be positive, got {max_total_size}')
 
-	def __init__(self, base_dir: str | Path, create_default_files: bool = True):
 		# Handle the Path conversion before calling super().__init__
 		self.base_dir = Path(base_dir) if isinstance(base_dir, str) else base_dir
-		self.base_dir.mkdir(parents=True, exist_ok=True)
-
-		# Create and use a dedicated subfolder for all operations
-		self.data_dir = self.base_dir / DEFAULT_FILE_SYSTEM_PATH
-		if self.data_dir.exists():
-			# clean the data directory
-			shutil.rmtree(self.data_dir)
-		self.data_dir.mkdir(exist_ok=True)
+		self.data_dir = Path(data_dir) if data_dir is not None else self.base_dir / DEFAULT_FILE_SYSTEM_PATH
+		if data_dir is None and own_data_dir and not self.in_memory:
+			self.data_dir = _free_data_dir(self.base_dir)
+			if self.data_dir.name != DEFAULT_FILE_SYSTEM_PATH:
+				logger.info(f'💾 {self.base_dir / DEFAULT_FILE_SYSTEM_PATH} is used by another agent, using {self.data_dir}')
+		self._prepare_data_dir(caller_supplied=data_dir is not None)
+		if own_data_dir and not self.in_memory:
+			_claimed_data_dirs[os.path.abspath(self.data_dir)] = self
+		# One lock per file, so concurrent actions writing the same file do not interleave
+		self._locks: dict[str, asyncio.Lock] = {}
 
 		self._file_types: dict[str, type[BaseFile]] = {
 			'md': MarkdownFile,
@@ -376,14 +469,108 @@ class FileSystem:
 			'html': HtmlFile,
 			'xml': XmlFile,
 		}
+		if allowed_extensions is not None:
+			normalized = [ext.lower().lstrip('.') for ext in allowed_extensions]
+			unknown = [ext for ext in normalized if ext not in self._file_types]
+			if unknown:
+				raise ValueError(f'Unsupported extensions in allowed_extensions: {unknown}. Options: {list(self._file_types)}')
+			self._file_types = {ext: cls for ext, cls in self._file_types.items() if ext in normalized}
+
+		self.max_file_size = max_file_size
+		self.max_total_size = max_total_size
+		self.allowed_extensions = allowed_extensions
+		# Set when this file system replaced one that could not be created (see with_in_memory_fallback)
+		self.fallback_reason: str | None = None
 
 		self.files = {}
 		if create_default_files:
-			self.default_files = ['todo.md']
+			self.default_files = [name for name in ['todo.md'] if name.rsplit('.', 1)[1] in self._file_types]
 			self._create_default_files()
 
 		self.extracted_content_count = 0
 
+	@classmethod
+	def with_in_memory_fallback(cls, base_dir: str | Path, **kwargs: Any) -> 'FileSystem':
+		"""Create a FileSystem at base_dir, falling back to an InMemoryFileSystem if the directory is not writable
+
+		Read-only home directories are common in containers and serverless functions. The fallback keeps all
+		file actions working for the duration of the run and records why in `fallback_reason`.
+		"""
+		try:
+			return cls(base_dir, **kwargs)
+		except OSError as e:
+			fs = InMemoryFileSystem(base_dir, **kwargs)
+			fs.fallback_reason = f'{type(e).__name__}: {e}'
+			logger.warning(
+				f'💾 Could not create file system at {base_dir} ({fs.fallback_reason}). '
+				f'Falling back to an in-memory file system; files will not persist after the run.'
+			)
+			return fs
+
+	def _prepare_data_dir(self, caller_supplied: bool = False) -> None:
+		"""Create a clean dedicated subfolder for all operations"""
+		self.base_dir.mkdir(parents=True, exist_ok=True)
+		if self.data_dir.exists():
+			# A directory passed as data_dir may hold the caller's own files, only clear it if a FileSystem created it
+			if caller_supplied and any(self.data_dir.iterdir()) and not (self.data_dir / _OWNER_FILE).exists():
+				raise FileSystemError(
+					f'data_dir {self.data_dir} is not empty and was not created by a FileSystem, refusing to clear it'
+				)
+			# clean the data directory
+			shutil.rmtree(self.data_dir)
+		self.data_dir.mkdir(parents=True, exist_ok=True)
+		(self.data_dir / _OWNER_FILE).write_text(str(os.getpid()))
+
+	def _file_lock(self, full_filename: str) -> asyncio.Lock:
+		return self._locks.setdefault(full_filename, asyncio.Lock())
+
+	def _sync_file_sync(self, file_obj: BaseFile) -> None:
+		"""Mirror a file to disk"""
+		file_obj.sync_to_disk_sync(self.data_dir)
+
+	async def _sync_file(self, file_obj: BaseFile) -> None:
+		"""Mirror a file to disk without blocking the event loop"""
+		await file_obj.sync_to_disk(self.data_dir)
+
+	@staticmethod
+	def _content_size(content: str) -> int:
+		return len(content.encode('utf-8', errors='replace'))
+
+	def get_total_size(self) -> int:
+		"""Get the combined size of all files in bytes"""
+		return sum(self._content_size(file_obj.content) for file_obj in self.files.values())
+
+	def _check_size_limits(self, full_filename: str, new_content: str) -> str | None:
+		"""Return an error message for the LLM if writing new_content to full_filename would exceed a limit"""
+		new_size = self._content_size(new_content)
+		if self.max_file_size is not None and new_size > self.max_file_size:
+			return (
+				f"Error: File '{full_filename}' would be {new_size:,} bytes, "
+				f'which exceeds the per-file limit of {self.max_file_size:,} bytes. Write less content or split it across files.'
+			)
+		if self.max_total_size is not None:
+			existing = self.files.get(full_filename)
+			other_files_size = self.get_total_size() - (self._content_size(existing.content) if existing else 0)
+			if other_files_size + new_size > self.max_total_size:
+				return (
+					f"Error: Writing '{full_filename}' would bring the file system to {other_files_size + new_size:,} bytes, "
+					f'which exceeds the total limit of {self.max_total_size:,} bytes. Free space by overwriting existing files.'
+				)
+		return None
+
+	async def _write_checked(self, full_filename: str, file_obj: BaseFile, content: str, append: bool = False) -> str | None:
+		"""Write or append content to a file object if it fits within the limits, returning an error message otherwise"""
+		candidate = file_obj.model_copy()
+		if append:
+			candidate.append_file_content(content)
+		else:
+			candidate.write_file_content(content)
+		if error := self._check_size_limits(full_filename, candidate.content):
+			return error
+		file_obj.update_content(candidate.content)
+		await self._sync_file(file_obj)
+		return None
+
 	def get_allowed_extensions(self) -> list[str]:
 		"""Get allowed extensions"""
 		return list(self._file_types.keys())
@@ -402,7 +589,7 @@ class FileSystem:
 
 			file_obj = file_class(name=name_without_ext)
 			self.files[full_filename] = file_obj  # Use full filename as key
-			file_obj.sync_to_disk_sync(self.data_dir)
+			self._sync_file_sync(file_obj)
 
 	def _is_valid_filename(self, file_name: str) -> bool:
 		"""Check if filename matches the required pattern: name.extension
@@ -734,15 +921,14 @@ class FileSystem:
 			if not file_class:
 				raise ValueError(f"Error: Invalid file extension '{extension}' for file '{full_filename}'.")
 
-			# Create or get existing file using full filename as key
-			if full_filename in self.files:
-				file_obj = self.files[full_filename]
-			else:
-				file_obj = file_class(name=name_without_ext)
-				self.files[full_filename] = file_obj  # Use full filename as key
+			async with self._file_lock(full_filename):
+				# Create or get existing file using full filename as key
+				file_obj = self.files.get(full_filename) or file_class(name=name_without_ext)
 
-			# Use file-specific write method
-			await file_obj.write(content, self.data_dir)
+				# Use file-specific write method
+				if error := await self._write_checked(full_filename, file_obj, content):
+					return error
+				self.files[full_filename] = file_obj  # Use full filename as key
 			sanitize_note = f" (auto-corrected from '{original_filename}')" if was_sanitized else ''
 			return f'Data written to file {full_filename} successfully.{sanitize_note}'
 		except FileSystemError as e:
@@ -750,22 +936,35 @@ class FileSystem:
 		except Exception as e:
 			return f"Error: Could not write to file '{full_filename}'. {str(e)}"
 
-	async def append_file(self, full_filename: str, content: str) -> str:
-		"""Append content to file using file-specific append method"""
+	async def append_file(self, full_filename: str, content: str, create: bool = False) -> str:
+		"""Append content to file using file-specific append method
+
+		With create=True a missing file is created first, so concurrent writers can append to a shared log file
+		without racing to create it.
+		"""
 		original_filename = full_filename
 		resolved, was_sanitized = self._resolve_filename(full_filename)
 		if not self._is_valid_filename(resolved):
 			return _build_filename_error_message(full_filename, self.get_allowed_extensions())
 		full_filename = resolved
 
-		file_obj = self.files.get(full_filename)
-		if not file_obj:
-			if was_sanitized:
-				return f"File '{full_filename}' not found. (Filename was auto-corrected from '{original_filename}')"
-			return f"File '{full_filename}' not found."
-
 		try:
-			await file_obj.append(content, self.data_dir)
+			async with self._file_lock(full_filename):
+				file_obj = self.files.get(full_filename)
+				if not file_obj and create:
+					name_without_ext, extension = self._parse_filename(full_filename)
+					file_class = self._get_file_type_class(extension)
+					if not file_class:
+						raise ValueError(f"Error: Invalid file extension '{extension}' for file '{full_filename}'.")
+					file_obj = file_class(name=name_without_ext)
+				if not file_obj:
+					if was_sanitized:
+						return f"File '{full_filename}' not found. (Filename was auto-corrected from '{original_filename}')"
+					return f"File '{full_filename}' not found."
+
+				if error := await self._write_checked(full_filename, file_obj, content, append=True):
+					return error
+				self.files[full_filename] = file_obj
 			sanitize_note = f" (auto-corrected from '{original_filename}')" if was_sanitized else ''
 			return f'Data appended to file {full_filename} successfully.{sanitize_note}'
 		except FileSystemError as e:
@@ -784,16 +983,19 @@ class FileSystem:
 		if not old_str:
 			return 'Error: Cannot replace empty string. Please provide a non-empty string to replace.'
 
-		file_obj = self.files.get(full_filename)
-		if not file_obj:
-			if was_sanitized:
-				return f"File '{full_filename}' not found. (Filename was auto-corrected from '{original_filename}')"
-			return f"File '{full_filename}' not found."
-
 		try:
-			content = file_obj.read()
-			content = content.replace(old_str, new_str)
-			await file_obj.write(content, self.data_dir)
+			# Read and write under the lock, a write in between would otherwise be lost
+			async with self._file_lock(full_filename):
+				file_obj = self.files.get(full_filename)
+				if not file_obj:
+					if was_sanitized:
+						return f"File '{full_filename}' not found. (Filename was auto-corrected from '{original_filename}')"
+					return f"File '{full_filename}' not found."
+
+				content = file_obj.read()
+				content = content.replace(old_str, new_str)
+				if error := await self._write_checked(full_filename, file_obj, content):
+					return error
 			sanitize_note = f" (auto-corrected from '{original_filename}')" if was_sanitized else ''
 			return f'Successfully replaced all occurrences of "{old_str}" with "{new_str}" in file {full_filename}{sanitize_note}'
 		except FileSystemError as e:
@@ -802,13 +1004,19 @@ class FileSystem:
 			return f"Error: Could not replace string in file '{full_filename}'. {str(e)}"
 
 	async def save_extracted_content(self, content: str) -> str:
-		"""Save extracted content to a numbered file"""
+		"""Save extracted content to a numbered file
+
+		Raises FileSystemError if the content does not fit within the size limits.
+		"""
+		# Reserve the number before writing, concurrent saves would otherwise get the same one
 		initial_filename = f'extracted_content_{self.extracted_content_count}'
+		self.extracted_content_count += 1
 		extracted_filename = f'{initial_filename}.md'
 		file_obj = MarkdownFile(name=initial_filename)
-		await file_obj.write(content, self.data_dir)
-		self.files[extracted_filename] = file_obj
-		self.extracted_content_count += 1
+		async with self._file_lock(extracted_filename):
+			if error := await self._write_checked(extracted_filename, file_obj, content):
+				raise FileSystemError(error)
+			self.files[extracted_filename] = file_obj
 		return extracted_filename
 
 	def describe(self) -> str:
@@ -896,19 +1104,38 @@ class FileSystem:
 			files_data[full_filename] = {'type': file_obj.__class__.__name__, 'data': file_obj.model_dump()}
 
 		return FileSystemState(
-			files=files_data, base_dir=str(self.base_dir), extracted_content_count=self.extracted_content_count
+			files=files_data,
+			base_dir=str(self.base_dir),
+			data_dir=None if self.in_memory else str(self.data_dir),
+			extracted_content_count=self.extracted_content_count,
+			in_memory=self.in_memory,
+			max_file_size=self.max_file_size,
+			max_total_size=self.max_total_size,
+			allowed_extensions=self.allowed_extensions,
+			fallback_reason=self.fallback_reason,
 		)
 
 	def nuke(self) -> None:
 		"""Delete the file system directory"""
+		if _claimed_data_dirs.get(os.path.abspath(self.data_dir)) is self:
+			del _claimed_data_dirs[os.path.abspath(self.data_dir)]
 		shutil.rmtree(self.data_dir)
 
 	@classmethod
 	def from_state(cls, state: FileSystemState) -> 'FileSystem':
 		"""Restore file system from serializable state at the exact same location"""
+		fs_class = InMemoryFileSystem if state.in_memory else FileSystem
 		# Create file system without default files
-		fs = cls(base_dir=Path(state.base_dir), create_default_files=False)
+		fs = fs_class(
+			base_dir=Path(state.base_dir),
+			create_default_files=False,
+			max_file_size=state.max_file_size,
+			max_total_size=state.max_total_size,
+			allowed_extensions=state.allowed_extensions,
+			data_dir=state.data_dir,
+		)
 		fs.extracted_content_count = state.extracted_content_count
+		fs.fallback_reason = state.fallback_reason
 
 		# Restore all files
 		for full_filename, file_data in state.files.items():
@@ -936,6 +1163,46 @@ class FileSystem:
 
 			# Add to files dict and sync to disk
 			fs.files[full_filename] = file_obj
-			file_obj.sync_to_disk_sync(fs.data_dir)
+			fs._sync_file_sync(file_obj)
 
 		return fs
+
+
+class InMemoryFileSystem(FileSystem):
+	"""FileSystem that never writes agent files to disk, for tests and serverless environments.
+
+	base_dir is only used as an identifier. Actions that need a real path (upload_file, save_as_pdf,
+	screenshots) get a scratch directory from get_dir(), which is created lazily in the system temp dir.
+	"""
+
+	in_memory: bool = True
+
+	def _prepare_data_dir(self, caller_supplied: bool = False) -> None:
+		self._scratch_dir: Path | None = None
+
+	def _sync_file_sync(self, file_obj: BaseFile) -> None:
+		pass
+
+	async def _sync_file(self, file_obj: BaseFile) -> None:
+		pass
+
+	def get_dir(self) -> Path:
+		"""Get a scratch directory for actions that need a real path, created on first use"""
+		if self._scratch_dir is None:
+			self._scratch_dir = Path(tempfile.mkdtemp(prefix='browser_use_fs_'))
+		return self._scratch_dir
+
+	def materialize(self, full_filename: str) -> Path | None:
+		"""Write a single in-memory file to the scratch directory and return its path"""
+		file_obj = self.get_file(full_filename)
+		if file_obj is None:
+			return None
+		file_obj.sync_to_disk_sync(self.get_dir())
+		return self.get_dir() / file_obj.full_name
+
+	def nuke(self) -> None:
+		"""Drop all files and the scratch directory"""
+		self.files = {}
+		if self._scratch_dir is not None:
+			shutil.rmtree(self._scratch_dir, ignore_errors=True)
+			self._scratch_dir = None
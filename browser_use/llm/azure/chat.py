@@ -1,5 +1,6 @@
+import logging
 import os
-from dataclasses import dataclass
+from dataclasses import dataclass, field
 from typing import Any, TypeVar, overload
 
 import httpx
@@ -18,6 +19,7 @@ from browser_use.llm.views import ChatInvokeCompletion, ChatInvokeUsage
 
 T = TypeVar('T', bound=BaseModel)
 
+logger = logging.getLogger(__name__)
 
 # List of models that only support the Responses API
 RESPONSES_API_ONLY_MODELS: list[str] = [
@@ -29,6 +31,9 @@ RESPONSES_API_ONLY_MODELS: list[str] = [
 	'computer-use-preview',
 ]
 
+# Status codes of OpenAI-compatible gateways (proxies, vLLM, llama.cpp) that don't serve /responses
+RESPONSES_API_UNSUPPORTED_STATUS_CODES: tuple[int, ...] = (404, 405, 501)
+
 
 @dataclass
 class ChatAzureOpenAI(ChatOpenAILike):
@@ -40,7 +45,8 @@ class ChatAzureOpenAI(ChatOpenAILike):
 	    api_key (Optional[str]): The API key to use. Defaults to "not-provided".
 	    use_responses_api (bool): If True, use the Responses API instead of Chat Completions API.
 	        This is required for certain models like gpt-5.1-codex-mini on Azure OpenAI with
-	        api_version >= 2025-03-01-preview. Set to 'auto' to automatically detect based on model.
+	        api_version >= 2025-03-01-preview. Set to 'auto' to automatically detect based on model,
+	        falling back to Chat Completions when the endpoint has no Responses API.
 	"""
 
 	# Model configuration
@@ -60,6 +66,7 @@ class ChatAzureOpenAI(ChatOpenAILike):
 
 	# Responses API support
 	use_responses_api: bool | str = 'auto'  # True, False, or 'auto'
+	_responses_api_unsupported: bool = field(default=False, init=False, repr=False)
 
 	client: AsyncAzureOpenAIClient | None = None
 
@@ -121,6 +128,8 @@ class ChatAzureOpenAI(ChatOpenAILike):
 		"""Determine if the Responses API should be used based on model and settings."""
 		if isinstance(self.use_responses_api, bool):
 			return self.use_responses_api
+		if self._responses_api_unsupported:
+			return False
 
 		# Auto-detect: use Responses API for models that require it
 		model_lower = str(self.model).lower()
@@ -271,7 +280,8 @@ class ChatAzureOpenAI(ChatOpenAILike):
 		Invoke the model with the given messages.
 
 		This method routes to either the Responses API or the Chat Completions API
-		based on the model and settings.
+		based on the model and settings. In 'auto' mode, an endpoint that rejects
+		/responses is remembered and served through Chat Completions instead.
 
 		Args:
 			messages: List of chat messages
@@ -281,7 +291,15 @@ class ChatAzureOpenAI(ChatOpenAILike):
 			Either a string response or an instance of output_format
 		"""
 		if self._should_use_responses_api():
-			return await self._ainvoke_responses_api(messages, output_format, **kwargs)
-		else:
-			# Use the parent class implementation (Chat Completions API)
-			return await super().ainvoke(messages, output_format, **kwargs)
+			try:
+				return await self._ainvoke_responses_api(messages, output_format, **kwargs)
+			except ModelProviderError as e:
+				if self.use_responses_api != 'auto' or e.status_code not in RESPONSES_API_UNSUPPORTED_STATUS_CODES:
+					raise
+				logger.warning(
+					f'⚠️ Endpoint of {self.name} has no Responses API (HTTP {e.status_code}), using Chat Completions instead'
+				)
+				self._responses_api_unsupported = True
+
+		# Use the parent class implementation (Chat Completions API)
+		return await super().ainvoke(messages, output_format, **kwargs)
@@ -1,10 +1,12 @@
 class ModelError(Exception):
-	pass
+	error_type: str = 'llm_error'  # Machine-readable kind of failure, e.g. for retry policies
 
 
 class ModelProviderError(ModelError):
 	"""Exception raised when a model provider returns an error."""
 
+	error_type = 'llm_provider_error'
+
 	def __init__(
 		self,
 		message: str,
@@ -20,6 +22,8 @@ class ModelProviderError(ModelError):
 class ModelRateLimitError(ModelProviderError):
 	"""Exception raised when a model provider returns a rate limit error."""
 
+	error_type = 'llm_rate_limited'
+
 	def __init__(
 		self,
 		message: str,
@@ -35,6 +39,8 @@ class ModelOutputTruncatedError(ModelProviderError):
 	Status 400 keeps it out of same-provider retry loops; the agent's fallback switch treats it as recoverable.
 	"""
 
+	error_type = 'llm_output_truncated'
+
 	def __init__(
 		self,
 		message: str,
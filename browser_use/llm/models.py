@@ -175,7 +175,8 @@ def get_llm_by_name(model_name: str):
 	elif provider == 'azure':
 		api_key = os.getenv('AZURE_OPENAI_KEY') or os.getenv('AZURE_OPENAI_API_KEY')
 		azure_endpoint = os.getenv('AZURE_OPENAI_ENDPOINT')
-		return ChatAzureOpenAI(model=model, api_key=api_key, azure_endpoint=azure_endpoint)
+		api_version = os.getenv('AZURE_OPENAI_API_VERSION') or ChatAzureOpenAI.api_version
+		return ChatAzureOpenAI(model=model, api_key=api_key, azure_endpoint=azure_endpoint, api_version=api_version)
 
 	# Google Models
 	elif provider == 'google':
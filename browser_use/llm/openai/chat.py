@@ -1,9 +1,10 @@
+import logging
 from collections.abc import Iterable, Mapping
 from dataclasses import dataclass, field
 from typing import Any, Literal, TypeVar, overload
 
 import httpx
-from openai import APIConnectionError, APIStatusError, AsyncOpenAI, RateLimitError
+from openai import APIConnectionError, APIStatusError, AsyncOpenAI, BadRequestError, RateLimitError
 from openai.types.chat import ChatCompletionContentPartTextParam
 from openai.types.chat.chat_completion import ChatCompletion
 from openai.types.shared.chat_model import ChatModel
@@ -15,11 +16,33 @@ from browser_use.llm.base import BaseChatModel
 from browser_use.llm.exceptions import ModelOutputTruncatedError, ModelProviderError, ModelRateLimitError
 from browser_use.llm.messages import BaseMessage
 from browser_use.llm.openai.serializer import OpenAIMessageSerializer
-from browser_use.llm.schema import SchemaOptimizer
+from browser_use.llm.schema import SchemaOptimizer, validate_json_text
 from browser_use.llm.views import ChatInvokeCompletion, ChatInvokeUsage
 
 T = TypeVar('T', bound=BaseModel)
 
+logger = logging.getLogger(__name__)
+
+
+_RESPONSE_FORMAT_HINTS = ('response_format', 'response format', 'json_schema', 'structured output')
+_UNSUPPORTED_HINTS = ('unsupported', 'not supported', 'does not support', 'unknown', 'unrecognized', 'not allowed')
+
+
+def _response_format_rejection(error: BadRequestError) -> Literal['unsupported', 'invalid_schema'] | None:
+	"""Why a 400 rejects response_format json_schema, None when it is about something else.
+
+	'unsupported': the server has no json_schema response format, e.g. OpenAI-compatible servers without it.
+	'invalid_schema': the server supports it but rejects the schema of this request.
+	"""
+	message = str(error.message).lower()
+	if not any(hint in message for hint in _RESPONSE_FORMAT_HINTS):
+		return None
+	if 'invalid schema' in message:
+		return 'invalid_schema'
+	if any(hint in message for hint in _UNSUPPORTED_HINTS):
+		return 'unsupported'
+	return None
+
 
 @dataclass
 class ChatOpenAI(BaseChatModel):
@@ -42,6 +65,8 @@ class ChatOpenAI(BaseChatModel):
 	top_p: float | None = None
 	add_schema_to_system_prompt: bool = False  # Add JSON schema to system prompt instead of using response_format
 	dont_force_structured_output: bool = False  # If True, the model will not be forced to output a structured output
+	# If the server rejects response_format json_schema, send the schema in the system prompt and parse the JSON from the text
+	structured_output_fallback: bool = True
 	remove_min_items_from_schema: bool = (
 		False  # If True, remove minItems from JSON schema (for compatibility with some providers)
 	)
@@ -61,6 +86,7 @@ class ChatOpenAI(BaseChatModel):
 	default_query: Mapping[str, object] | None = None
 	http_client: httpx.AsyncClient | None = None
 	_strict_response_validation: bool = False
+	_json_schema_unsupported: bool = field(default=False, init=False, repr=False)  # set after the server rejected it
 	max_completion_tokens: int | None = 4096
 	reasoning_models: list[ChatModel | str] | None = field(
 		default_factory=lambda: [
@@ -141,6 +167,18 @@ class ChatOpenAI(BaseChatModel):
 
 		return usage
 
+	@staticmethod
+	def _add_schema_to_system_prompt(openai_messages: list[Any], response_format: JSONSchema) -> None:
+		if not openai_messages or openai_messages[0]['role'] != 'system':
+			return
+		schema_text = f'\n<json_schema>\n{response_format}\n</json_schema>'
+		if isinstance(openai_messages[0]['content'], str):
+			openai_messages[0]['content'] += schema_text
+		elif isinstance(openai_messages[0]['content'], Iterable):
+			openai_messages[0]['content'] = list(openai_messages[0]['content']) + [
+				ChatCompletionContentPartTextParam(text=schema_text, type='text')
+			]
+
 	@overload
 	async def ainvoke(
 		self, messages: list[BaseMessage], output_format: None = None, **kwargs: Any
@@ -233,16 +271,10 @@ class ChatOpenAI(BaseChatModel):
 				}
 
 				# Add JSON schema to system prompt if requested
-				if self.add_schema_to_system_prompt and openai_messages and openai_messages[0]['role'] == 'system':
-					schema_text = f'\n<json_schema>\n{response_format}\n</json_schema>'
-					if isinstance(openai_messages[0]['content'], str):
-						openai_messages[0]['content'] += schema_text
-					elif isinstance(openai_messages[0]['content'], Iterable):
-						openai_messages[0]['content'] = list(openai_messages[0]['content']) + [
-							ChatCompletionContentPartTextParam(text=schema_text, type='text')
-						]
-
-				if self.dont_force_structured_output:
+				if self.add_schema_to_system_prompt or self._json_schema_unsupported:
+					self._add_schema_to_system_prompt(openai_messages, response_format)
+
+				if self.dont_force_structured_output or self._json_schema_unsupported:
 					response = await self.get_client().chat.completions.create(
 						model=self.model,
 						messages=openai_messages,
@@ -250,12 +282,36 @@ class ChatOpenAI(BaseChatModel):
 					)
 				else:
 					# Return structured response
-					response = await self.get_client().chat.completions.create(
-						model=self.model,
-						messages=openai_messages,
-						response_format=ResponseFormatJSONSchema(json_schema=response_format, type='json_schema'),
-						**model_params,
-					)
+					try:
+						response = await self.get_client().chat.completions.create(
+							model=self.model,
+							messages=openai_messages,
+							response_format=ResponseFormatJSONSchema(json_schema=response_format, type='json_schema'),
+							**model_params,
+						)
+					except BadRequestError as e:
+						rejection = _response_format_rejection(e)
+						if not self.structured_output_fallback or rejection is None:
+							raise
+						if rejection == 'unsupported':
+							logger.warning(
+								f'⚠️ {self.name} does not support response_format json_schema, '
+								f'sending the schema in the system prompt from now on: {e.message}'
+							)
+							self._json_schema_unsupported = True
+						else:
+							# Only this output format's schema was rejected, other requests still use json_schema
+							logger.warning(
+								f'⚠️ {self.name} rejected the json_schema of {output_format.__name__}, '
+								f'sending it in the system prompt for this request: {e.message}'
+							)
+						if not self.add_schema_to_system_prompt:
+							self._add_schema_to_system_prompt(openai_messages, response_format)
+						response = await self.get_client().chat.completions.create(
+							model=self.model,
+							messages=openai_messages,
+							**model_params,
+						)
 
 				choice = response.choices[0] if response.choices else None
 				if choice is None:
@@ -298,7 +354,11 @@ class ChatOpenAI(BaseChatModel):
 
 				usage = self._get_usage(response)
 
-				parsed = output_format.model_validate_json(choice.message.content)
+				if self.dont_force_structured_output or self._json_schema_unsupported:
+					# Nothing constrained the answer, models like to wrap JSON in a code fence or explain it
+					parsed = validate_json_text(choice.message.content, output_format)
+				else:
+					parsed = output_format.model_validate_json(choice.message.content)
 
 				return ChatInvokeCompletion(
 					completion=parsed,
@@ -2,9 +2,38 @@
 Utilities for creating optimized Pydantic schemas for LLM usage.
 """
 
-from typing import Any
-
-from pydantic import BaseModel
+import re
+from typing import Any, TypeVar
+
+from pydantic import BaseModel, ValidationError
+
+T = TypeVar('T', bound=BaseModel)
+
+_JSON_FENCE_PATTERN = re.compile(r'```(?:json)?\s*\n(.*?)```', re.DOTALL)
+
+
+def validate_json_text(text: str, output_format: type[T]) -> T:
+	"""Validate JSON a model wrote as plain text: bare, in a ```json fence or surrounded by prose.
+
+	For answers not constrained by a response_format. Raises the ValidationError of the most narrowly extracted
+	candidate when none validates.
+	"""
+	stripped = text.strip()
+	candidates = [stripped]
+	if fence := _JSON_FENCE_PATTERN.search(stripped):
+		candidates.append(fence.group(1).strip())
+	start, end = stripped.find('{'), stripped.rfind('}')
+	if start != -1 and end > start:
+		candidates.append(stripped[start : end + 1])
+
+	error: ValidationError | None = None
+	for candidate in dict.fromkeys(candidates):
+		try:
+			return output_format.model_validate_json(candidate)
+		except ValidationError as e:
+			error = e
+	assert error is not None
+	raise error
 
 
 class SchemaOptimizer:
@@ -0,0 +1,61 @@
+"""HTTP transport settings shared by all LLMs of an agent, for enterprise proxies and API gateways."""
+
+from collections.abc import Mapping
+from dataclasses import dataclass
+from typing import Any
+
+import httpx
+
+from browser_use.llm.base import BaseChatModel
+
+
+def _is_unset(value: Any) -> bool:
+	# The anthropic and openai SDKs use their own NotGiven sentinel as default for some client options
+	return value is None or type(value).__name__ == 'NotGiven'
+
+
+@dataclass
+class LLMTransport:
+	"""How LLM requests reach the provider: base URL, HTTP client (proxies, TLS, timeouts), headers, org/project.
+
+	Passed to Agent(llm_transport=...) it is applied to every model the agent calls: the main llm, the
+	page_extraction_llm used by extract, the judge, fallback models and the compaction model. Settings are
+	only filled in where the model leaves them unset, so values configured on a model itself take precedence.
+	Options a provider doesn't support (e.g. organization on Anthropic) are skipped for that model.
+	"""
+
+	base_url: str | None = None
+	http_client: httpx.AsyncClient | None = None
+	proxy: str | None = None  # e.g. 'http://proxy.corp:3128', used to build http_client when none is given
+	timeout: float | httpx.Timeout | None = None
+	default_headers: Mapping[str, str] | None = None
+	organization: str | None = None
+	project: str | None = None
+
+	def __post_init__(self):
+		if self.http_client is not None and self.proxy is not None:
+			raise ValueError('Pass either http_client or proxy, configure the proxy on your own http_client instead')
+		if self.proxy is not None:
+			self.http_client = httpx.AsyncClient(proxy=self.proxy, timeout=self.timeout or httpx.Timeout(600.0))
+
+	def apply(self, llm: BaseChatModel) -> list[str]:
+		"""Apply the transport to a chat model in place, returns the names of the options that were set."""
+		applied = []
+		options = {
+			'base_url': self.base_url,
+			'http_client': self.http_client,
+			'timeout': self.timeout,
+			'organization': self.organization,
+			'project': self.project,
+		}
+		for name, value in options.items():
+			if value is None or not hasattr(llm, name) or not _is_unset(getattr(llm, name)):
+				continue
+			setattr(llm, name, value)
+			applied.append(name)
+
+		if self.default_headers and hasattr(llm, 'default_headers'):
+			# Headers are merged, the model's own headers win on conflicts
+			setattr(llm, 'default_headers', {**self.default_headers, **(getattr(llm, 'default_headers') or {})})
+			applied.append('default_headers')
+		return applied
@@ -94,6 +94,8 @@ from browser_use import ActionModel, Agent
 from browser_use.browser import BrowserProfile, BrowserSession
 from browser_use.config import get_default_llm, get_default_profile, load_browser_use_config
 from browser_use.filesystem.file_system import FileSystem
+from browser_use.llm.azure.chat import ChatAzureOpenAI
+from browser_use.llm.base import BaseChatModel
 from browser_use.llm.openai.chat import ChatOpenAI
 from browser_use.tools.service import Tools
 
@@ -184,6 +186,62 @@ def get_parent_process_cmdline() -> str | None:
 		return None
 
 
+def create_llm_from_config(llm_config: dict[str, Any], model: str | None = None, default_model: str = 'gpt-4o') -> BaseChatModel:
+	"""Create the LLM of the llm config entry: OpenAI (or compatible base_url), Azure OpenAI or Bedrock.
+
+	Azure is used with model_provider 'azure' (or MODEL_PROVIDER=azure) or when the entry has an azure_endpoint.
+	Requests go to the azure_deployment with the api_version, authenticated with the API key or an Entra ID
+	(Azure AD) token. Each setting falls back to AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_DEPLOYMENT,
+	AZURE_OPENAI_API_VERSION, AZURE_OPENAI_KEY / AZURE_OPENAI_API_KEY and AZURE_OPENAI_AD_TOKEN.
+	Raises ValueError when credentials are missing.
+	"""
+	model_provider = (llm_config.get('model_provider') or os.getenv('MODEL_PROVIDER') or '').lower()
+
+	if model_provider == 'bedrock':
+		aws_region = llm_config.get('region') or os.getenv('REGION') or 'us-east-1'
+		return ChatAWSBedrock(
+			model=llm_config.get('model') or os.getenv('MODEL') or 'us.anthropic.claude-sonnet-4-20250514-v1:0',
+			aws_region=aws_region,
+			aws_sso_auth=llm_config.get('aws_sso_auth', False),
+		)
+
+	if model_provider == 'azure' or (not model_provider and llm_config.get('azure_endpoint')):
+		azure_endpoint = llm_config.get('azure_endpoint') or os.getenv('AZURE_OPENAI_ENDPOINT')
+		azure_deployment = llm_config.get('azure_deployment') or os.getenv('AZURE_OPENAI_DEPLOYMENT')
+		# OPENAI_API_KEY is copied into the entry's api_key, the Azure key wins over it
+		api_key = os.getenv('AZURE_OPENAI_KEY') or os.getenv('AZURE_OPENAI_API_KEY') or llm_config.get('api_key')
+		azure_ad_token = llm_config.get('azure_ad_token') or os.getenv('AZURE_OPENAI_AD_TOKEN')
+		if not azure_endpoint:
+			raise ValueError('AZURE_OPENAI_ENDPOINT not set in config (azure_endpoint) or environment')
+		if not api_key and not azure_ad_token:
+			raise ValueError('AZURE_OPENAI_KEY or AZURE_OPENAI_AD_TOKEN not set in config or environment')
+		azure_kwargs: dict[str, Any] = {}
+		if api_version := llm_config.get('api_version') or os.getenv('AZURE_OPENAI_API_VERSION'):
+			azure_kwargs['api_version'] = api_version
+		return ChatAzureOpenAI(
+			model=model or llm_config.get('model') or azure_deployment or default_model,
+			api_key=api_key,
+			azure_endpoint=azure_endpoint,
+			azure_deployment=azure_deployment,
+			azure_ad_token=azure_ad_token,
+			temperature=llm_config.get('temperature', 0.7),
+			**azure_kwargs,
+		)
+
+	api_key = llm_config.get('api_key') or os.getenv('OPENAI_API_KEY')
+	if not api_key:
+		raise ValueError('OPENAI_API_KEY not set in config or environment')
+	kwargs = {}
+	if base_url := llm_config.get('base_url'):
+		kwargs['base_url'] = base_url
+	return ChatOpenAI(
+		model=model or llm_config.get('model', default_model),
+		api_key=api_key,
+		temperature=llm_config.get('temperature', 0.7),
+		**kwargs,
+	)
+
+
 class BrowserUseServer:
 	"""MCP Server for browser-use capabilities."""
 
@@ -196,7 +254,7 @@ class BrowserUseServer:
 		self.agent: Agent | None = None
 		self.browser_session: BrowserSession | None = None
 		self.tools: Tools | None = None
-		self.llm: ChatOpenAI | None = None
+		self.llm: BaseChatModel | None = None
 		self.file_system: FileSystem | None = None
 		self._telemetry = ProductTelemetry()
 		self._start_time = time.time()
@@ -614,23 +672,15 @@ class BrowserUseServer:
 		# Create tools for direct actions
 		self.tools = Tools()
 
-		# Initialize LLM from config
-		llm_config = get_default_llm(self.config)
-		base_url = llm_config.get('base_url', None)
-		kwargs = {}
-		if base_url:
-			kwargs['base_url'] = base_url
-		if api_key := llm_config.get('api_key'):
-			self.llm = ChatOpenAI(
-				model=llm_config.get('model', 'gpt-o4-mini'),
-				api_key=api_key,
-				temperature=llm_config.get('temperature', 0.7),
-				**kwargs,
-			)
+		# Initialize LLM from config, extraction is unavailable without credentials
+		try:
+			self.llm = create_llm_from_config(get_default_llm(self.config), default_model='gpt-o4-mini')
+		except ValueError as e:
+			logger.debug(f'No LLM for extraction: {e}')
 
 		# Initialize FileSystem for extraction actions
 		file_system_path = profile_config.get('file_system_path', '~/.browser-use-mcp')
-		self.file_system = FileSystem(base_dir=Path(file_system_path).expanduser())
+		self.file_system = FileSystem.with_in_memory_fallback(Path(file_system_path).expanduser())
 
 		logger.debug('Browser session initialized')
 
@@ -645,42 +695,11 @@ class BrowserUseServer:
 		"""Run an autonomous agent task."""
 		logger.debug(f'Running agent task: {task}')
 
-		# Get LLM config
-		llm_config = get_default_llm(self.config)
-
-		# Get LLM provider
-		model_provider = llm_config.get('model_provider') or os.getenv('MODEL_PROVIDER')
-
-		# Get Bedrock-specific config
-		if model_provider and model_provider.lower() == 'bedrock':
-			llm_model = llm_config.get('model') or os.getenv('MODEL') or 'us.anthropic.claude-sonnet-4-20250514-v1:0'
-			aws_region = llm_config.get('region') or os.getenv('REGION')
-			if not aws_region:
-				aws_region = 'us-east-1'
-			aws_sso_auth = llm_config.get('aws_sso_auth', False)
-			llm = ChatAWSBedrock(
-				model=llm_model,  # or any Bedrock model
-				aws_region=aws_region,
-				aws_sso_auth=aws_sso_auth,
-			)
-		else:
-			api_key = llm_config.get('api_key') or os.getenv('OPENAI_API_KEY')
-			if not api_key:
-				return 'Error: OPENAI_API_KEY not set in config or environment'
-
-			# Use explicit model from tool call, otherwise fall back to configured default
-			llm_model = model or llm_config.get('model', 'gpt-4o')
-
-			base_url = llm_config.get('base_url', None)
-			kwargs = {}
-			if base_url:
-				kwargs['base_url'] = base_url
-			llm = ChatOpenAI(
-				model=llm_model,
-				api_key=api_key,
-				temperature=llm_config.get('temperature', 0.7),
-				**kwargs,
-			)
+		# Use explicit model from tool call, otherwise fall back to configured default
+		try:
+			llm = create_llm_from_config(get_default_llm(self.config), model=model)
+		except ValueError as e:
+			return f'Error: {e}'
 
 		# Get profile config and merge with tool parameters
 		profile_config = get_default_profile(self.config)
@@ -983,7 +1002,7 @@ class BrowserUseServer:
 	async def _extract_content(self, query: str, extract_links: bool = False) -> str:
 		"""Extract content from current page."""
 		if not self.llm:
-			return 'Error: LLM not initialized (set OPENAI_API_KEY)'
+			return 'Error: LLM not initialized (set OPENAI_API_KEY or configure Azure OpenAI)'
 
 		if not self.file_system:
 			return 'Error: FileSystem not initialized'
@@ -0,0 +1,141 @@
+"""
+Prometheus metrics for agents and their browser sessions.
+
+Requires the optional prometheus-client dependency: pip install "browser-use[metrics]"
+
+Create one AgentMetrics and pass it to every agent of a process, then expose it to your Prometheus scraper:
+
+	metrics = AgentMetrics()
+	metrics.start_http_server(9464)  # or mount metrics.asgi_app() on an existing ASGI server at /metrics
+	agent = Agent(task=..., llm=..., metrics=metrics)
+"""
+
+import logging
+import time
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+	from browser_use.agent.views import ActionResult, AgentHistoryList
+	from browser_use.llm.base import BaseChatModel
+
+logger = logging.getLogger(__name__)
+
+_LATENCY_BUCKETS = (0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60)
+_LLM_LATENCY_BUCKETS = (0.25, 0.5, 1, 2, 4, 8, 15, 30, 60, 120)
+_STEPS_BUCKETS = (1, 2, 5, 10, 20, 50, 100, 200, 500)
+_SCREENSHOT_BUCKETS = (25_000, 50_000, 100_000, 250_000, 500_000, 1_000_000, 2_500_000, 5_000_000)
+
+
+class AgentMetrics:
+	"""Counters and histograms for agent runs, actions, LLM calls and CDP health.
+
+	Metrics are registered in their own CollectorRegistry unless one is passed, so several AgentMetrics can
+	coexist in a process. Share one instance between agents, label values tell models and actions apart.
+	"""
+
+	def __init__(self, registry: Any | None = None, namespace: str = 'browser_use'):
+		try:
+			from prometheus_client import CollectorRegistry, Counter, Histogram
+		except ImportError as e:
+			raise ImportError('AgentMetrics requires prometheus-client: pip install "browser-use[metrics]"') from e
+
+		self.registry = registry if registry is not None else CollectorRegistry()
+		self._instrumented_llms: set[int] = set()
+		common: dict[str, Any] = {'namespace': namespace, 'registry': self.registry}
+
+		self.runs = Counter('agent_runs', 'Finished agent runs by outcome', ['outcome'], **common)
+		self.steps = Counter('agent_steps', 'Agent steps executed', **common)
+		self.steps_per_run = Histogram('agent_steps_per_run', 'Steps taken per agent run', buckets=_STEPS_BUCKETS, **common)
+		self.actions = Counter('agent_actions', 'Executed actions by name and status', ['action', 'status'], **common)
+		self.llm_latency = Histogram(
+			'llm_request_duration_seconds',
+			'LLM request latency',
+			['provider', 'model', 'status'],
+			buckets=_LLM_LATENCY_BUCKETS,
+			**common,
+		)
+		self.llm_tokens = Counter('llm_tokens', 'LLM tokens used by kind', ['provider', 'model', 'kind'], **common)
+		self.cdp_latency = Histogram(
+			'cdp_command_duration_seconds', 'CDP command latency', ['method'], buckets=_LATENCY_BUCKETS, **common
+		)
+		self.cdp_reconnects = Counter('cdp_reconnects', 'CDP websocket reconnections by result', ['result'], **common)
+		self.screenshot_size = Histogram(
+			'screenshot_size_bytes', 'Size of captured screenshots', buckets=_SCREENSHOT_BUCKETS, **common
+		)
+
+	# --- Recording ---
+
+	def record_run(self, history: 'AgentHistoryList', error: str | None = None) -> None:
+		"""Record a finished run: its outcome (success, failure, incomplete or error) and number of steps."""
+		if error:
+			outcome = 'error'
+		elif history.is_done():
+			outcome = 'success' if history.is_successful() else 'failure'
+		else:
+			outcome = 'incomplete'
+		self.runs.labels(outcome=outcome).inc()
+		self.steps_per_run.observe(history.number_of_steps())
+
+	def record_step(self, action_names: list[str], results: list['ActionResult']) -> None:
+		"""Record a finished step and the status of each action it executed."""
+		self.steps.inc()
+		for action_name, result in zip(action_names, results):
+			self.actions.labels(action=action_name, status='error' if result.error else 'success').inc()
+
+	def record_cdp_command(self, method: str, duration: float) -> None:
+		self.cdp_latency.labels(method=method).observe(duration)
+
+	def record_reconnect(self, success: bool) -> None:
+		self.cdp_reconnects.labels(result='success' if success else 'failure').inc()
+
+	def record_screenshot(self, size_bytes: int) -> None:
+		self.screenshot_size.observe(size_bytes)
+
+	def instrument_llm(self, llm: 'BaseChatModel') -> None:
+		"""Wrap llm.ainvoke to record request latency and token usage, each instance is wrapped once."""
+		if id(llm) in self._instrumented_llms:
+			return
+		self._instrumented_llms.add(id(llm))
+
+		original_ainvoke = llm.ainvoke
+		metrics = self
+
+		async def instrumented_ainvoke(messages, output_format=None, **kwargs):
+			labels = {'provider': str(llm.provider), 'model': str(llm.model)}
+			start = time.perf_counter()
+			try:
+				result = await original_ainvoke(messages, output_format, **kwargs)
+			except BaseException:
+				metrics.llm_latency.labels(**labels, status='error').observe(time.perf_counter() - start)
+				raise
+			metrics.llm_latency.labels(**labels, status='success').observe(time.perf_counter() - start)
+			if result.usage:
+				metrics.llm_tokens.labels(**labels, kind='prompt').inc(result.usage.prompt_tokens)
+				metrics.llm_tokens.labels(**labels, kind='completion').inc(result.usage.completion_tokens)
+				if result.usage.prompt_cached_tokens:
+					metrics.llm_tokens.labels(**labels, kind='prompt_cached').inc(result.usage.prompt_cached_tokens)
+			return result
+
+		# Same runtime patch as TokenCost.register_llm, setattr would be rejected by pydantic-backed models
+		object.__setattr__(llm, 'ainvoke', instrumented_ainvoke)
+
+	# --- Exposition ---
+
+	def render(self) -> bytes:
+		"""Metrics in the Prometheus text format, for serving from your own HTTP handler."""
+		from prometheus_client import generate_latest
+
+		return generate_latest(self.registry)
+
+	def asgi_app(self) -> Any:
+		"""ASGI app serving the metrics, e.g. app.mount('/metrics', metrics.asgi_app()) in FastAPI or Starlette."""
+		from prometheus_client import make_asgi_app
+
+		return make_asgi_app(registry=self.registry)
+
+	def start_http_server(self, port: int, addr: str = '0.0.0.0') -> None:
+		"""Serve the metrics on http://{addr}:{port}/metrics from a background thread."""
+		from prometheus_client import start_http_server
+
+		start_http_server(port, addr=addr, registry=self.registry)
+		logger.info(f'📈 Serving Prometheus metrics on http://{addr}:{port}/metrics')
@@ -0,0 +1,109 @@
+"""
+Screenshot stores that keep step screenshots in cloud object storage instead of the local disk.
+"""
+
+import asyncio
+import base64
+import uuid
+from typing import Any
+
+from browser_use.screenshots.service import ScreenshotStore
+
+
+def _split_uri(uri: str, scheme: str) -> tuple[str, str]:
+	"""Split s3://bucket/key style URIs into bucket and key."""
+	prefix = f'{scheme}://'
+	if not uri.startswith(prefix) or '/' not in uri[len(prefix) :]:
+		raise ValueError(f'Not a {prefix} screenshot reference: {uri}')
+	bucket, key = uri[len(prefix) :].split('/', 1)
+	return bucket, key
+
+
+def _default_prefix() -> str:
+	# Every store gets its own folder so agents sharing a bucket don't overwrite each other's step_N.png
+	return f'browser-use/screenshots/{uuid.uuid4().hex}'
+
+
+class S3ScreenshotStore(ScreenshotStore):
+	"""Stores screenshots as S3 objects and returns s3://bucket/key references (needs boto3)"""
+
+	def __init__(self, bucket: str, prefix: str | None = None, client: Any | None = None):
+		if client is None:
+			try:
+				import boto3  # type: ignore
+			except ImportError:
+				raise ImportError(
+					'`boto3` not installed. Please install using `pip install browser-use[aws] or pip install browser-use[all]`'
+				)
+			client = boto3.client('s3')
+		self.client = client
+		self.bucket = bucket
+		self.prefix = (prefix or _default_prefix()).strip('/')
+
+	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
+		key = f'{self.prefix}/step_{step_number}.png'
+		await asyncio.to_thread(
+			self.client.put_object,
+			Bucket=self.bucket,
+			Key=key,
+			Body=base64.b64decode(screenshot_b64),
+			ContentType='image/png',
+		)
+		return f's3://{self.bucket}/{key}'
+
+	async def get_screenshot(self, screenshot_path: str) -> str | None:
+		bucket, key = _split_uri(screenshot_path, 's3')
+
+		def download() -> bytes:
+			return self.client.get_object(Bucket=bucket, Key=key)['Body'].read()
+
+		try:
+			data = await asyncio.to_thread(download)
+		except Exception:
+			return None
+		return base64.b64encode(data).decode('utf-8')
+
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		bucket, key = _split_uri(screenshot_path, 's3')
+		await asyncio.to_thread(self.client.delete_object, Bucket=bucket, Key=key)
+
+
+class GCSScreenshotStore(ScreenshotStore):
+	"""Stores screenshots as Google Cloud Storage blobs and returns gs://bucket/key references (needs google-cloud-storage)"""
+
+	def __init__(self, bucket: str, prefix: str | None = None, client: Any | None = None):
+		if client is None:
+			try:
+				from google.cloud import storage  # type: ignore
+			except ImportError:
+				raise ImportError(
+					'`google-cloud-storage` not installed. Please install using `pip install browser-use[gcs] or pip install browser-use[all]`'
+				)
+			client = storage.Client()
+		self.client = client
+		self.bucket = bucket
+		self.prefix = (prefix or _default_prefix()).strip('/')
+
+	def _blob(self, bucket: str, key: str) -> Any:
+		return self.client.bucket(bucket).blob(key)
+
+	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
+		key = f'{self.prefix}/step_{step_number}.png'
+		blob = self._blob(self.bucket, key)
+		await asyncio.to_thread(blob.upload_from_string, base64.b64decode(screenshot_b64), content_type='image/png')
+		return f'gs://{self.bucket}/{key}'
+
+	async def get_screenshot(self, screenshot_path: str) -> str | None:
+		bucket, key = _split_uri(screenshot_path, 'gs')
+		try:
+			data = await asyncio.to_thread(self._blob(bucket, key).download_as_bytes)
+		except Exception:
+			return None
+		return base64.b64encode(data).decode('utf-8')
+
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		bucket, key = _split_uri(screenshot_path, 'gs')
+		try:
+			await asyncio.to_thread(self._blob(bucket, key).delete)
+		except Exception:
+			pass  # Already deleted
@@ -2,7 +2,10 @@
 Screenshot storage service for browser-use agents.
 """
 
+import asyncio
 import base64
+from abc import ABC, abstractmethod
+from concurrent.futures import ThreadPoolExecutor
 from pathlib import Path
 
 import anyio
@@ -10,7 +13,37 @@ import anyio
 from browser_use.observability import observe_debug
 
 
-class ScreenshotService:
+class ScreenshotStore(ABC):
+	"""Where the agent keeps step screenshots, the history only holds the reference returned by store_screenshot"""
+
+	@abstractmethod
+	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
+		"""Store a base64 PNG screenshot and return a reference to it (a path or URI)"""
+
+	@abstractmethod
+	async def get_screenshot(self, screenshot_path: str) -> str | None:
+		"""Load a stored screenshot as base64, None if it doesn't exist"""
+
+	@abstractmethod
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		"""Delete a stored screenshot, missing screenshots are ignored"""
+
+	@property
+	def is_local(self) -> bool:
+		"""Whether references are paths on the local disk"""
+		return False
+
+	def get_screenshot_sync(self, screenshot_path: str) -> str | None:
+		"""Blocking get_screenshot() for sync callers like GIF rendering, runs on a helper thread inside an event loop"""
+		try:
+			asyncio.get_running_loop()
+		except RuntimeError:
+			return asyncio.run(self.get_screenshot(screenshot_path))
+		with ThreadPoolExecutor(max_workers=1) as executor:
+			return executor.submit(asyncio.run, self.get_screenshot(screenshot_path)).result()
+
+
+class ScreenshotService(ScreenshotStore):
 	"""Simple screenshot storage service that saves screenshots to disk"""
 
 	def __init__(self, agent_directory: str | Path):
@@ -21,6 +54,10 @@ class ScreenshotService:
 		self.screenshots_dir = self.agent_directory / 'screenshots'
 		self.screenshots_dir.mkdir(parents=True, exist_ok=True)
 
+	@property
+	def is_local(self) -> bool:
+		return True
+
 	@observe_debug(ignore_input=True, ignore_output=True, name='store_screenshot')
 	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
 		"""Store screenshot to disk and return the full path as string"""
@@ -50,3 +87,25 @@ class ScreenshotService:
 			screenshot_data = await f.read()
 
 		return base64.b64encode(screenshot_data).decode('utf-8')
+
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		"""Delete a screenshot from disk"""
+		await anyio.Path(screenshot_path).unlink(missing_ok=True)
+
+
+class InMemoryScreenshotStore(ScreenshotStore):
+	"""Keeps screenshots in memory for the run, used when the agent directory is not writable"""
+
+	def __init__(self):
+		self._screenshots: dict[str, str] = {}
+
+	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
+		screenshot_path = f'memory://screenshots/step_{step_number}.png'
+		self._screenshots[screenshot_path] = screenshot_b64
+		return screenshot_path
+
+	async def get_screenshot(self, screenshot_path: str) -> str | None:
+		return self._screenshots.get(screenshot_path)
+
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		self._screenshots.pop(screenshot_path, None)
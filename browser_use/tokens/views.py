@@ -110,3 +110,7 @@ class UsageSummary(BaseModel):
 	entry_count: int
 
 	by_model: dict[str, ModelUsageStats] = Field(default_factory=dict)
+
+	# Screenshot budget of the agent run, see VisionBudget
+	screenshots_sent: int = 0
+	screenshots_skipped: int = 0
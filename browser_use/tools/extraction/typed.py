@@ -0,0 +1,130 @@
+"""Typed extraction: the current page's content as an instance of a Pydantic model.
+
+	class Product(BaseModel):
+		name: str
+		price: float
+		in_stock: bool | None = None
+
+	class Products(BaseModel):
+		products: list[Product]
+
+	result = await agent.extract('all products on the page', Products)  # -> Products
+
+The model's JSON schema is sent to page_extraction_llm as the output format and its answer is validated with the model
+itself (nested models, optional fields and custom validators included). When the answer does not validate, the errors
+are sent back and the LLM tries again, up to max_retries times.
+"""
+
+import json
+import logging
+from collections.abc import Callable
+from typing import TYPE_CHECKING, TypeVar
+
+from pydantic import BaseModel, ValidationError
+
+from browser_use.llm.base import BaseChatModel
+from browser_use.llm.exceptions import ModelProviderError
+from browser_use.llm.messages import BaseMessage, SystemMessage, UserMessage
+from browser_use.utils import sanitize_text
+
+if TYPE_CHECKING:
+	from browser_use.browser.session import BrowserSession
+
+logger = logging.getLogger(__name__)
+
+T = TypeVar('T', bound=BaseModel)
+
+STRUCTURED_EXTRACTION_SYSTEM_PROMPT = """
+You are an expert at extracting structured data from the markdown of a webpage.
+
+<input>
+You will be given a query, a JSON Schema, and the markdown of a webpage that has been filtered to remove noise and advertising content.
+</input>
+
+<instructions>
+- Extract ONLY information present in the webpage. Do not guess or fabricate values.
+- Your response MUST conform to the provided JSON Schema exactly.
+- If a required field's value cannot be found on the page, use null (if the schema allows it) or an empty string / empty array as appropriate.
+- If the content was truncated, extract what is available from the visible portion.
+- If <already_collected> items are provided, skip any items whose name/title/URL matches those listed — do not include duplicates.
+</instructions>
+""".strip()
+
+
+class ExtractionValidationError(ValueError):
+	"""The LLM's answers did not match the output model, after all retries"""
+
+	def __init__(self, output_model: type[BaseModel], attempts: int, error: Exception):
+		super().__init__(f'Could not extract a valid {output_model.__name__} after {attempts} attempts: {error}')
+		self.output_model = output_model
+		self.attempts = attempts
+		self.error = error
+
+
+def _schema_violation(error: Exception) -> ValidationError | None:
+	"""The validation error behind a failed structured LLM call, None for other failures (network, rate limits)."""
+	if isinstance(error, ValidationError):
+		return error
+	if isinstance(error, ModelProviderError) and isinstance(error.__cause__, ValidationError):
+		return error.__cause__
+	return None
+
+
+async def extract_typed(
+	browser_session: 'BrowserSession',
+	llm: BaseChatModel,
+	query: str,
+	output_model: type[T],
+	max_retries: int = 2,
+	extract_links: bool = False,
+	main_content_only: bool = False,
+	max_chars: int = 100_000,
+	redact_text: Callable[[str], str] | None = None,
+) -> T:
+	"""Extract the data the query asks for from the current page as an instance of output_model.
+
+	Raises ExtractionValidationError when the answers still don't validate after max_retries retries.
+	"""
+	from browser_use.dom.markdown_extractor import chunk_markdown_by_structure, extract_clean_markdown
+
+	content, _ = await extract_clean_markdown(
+		browser_session=browser_session, extract_links=extract_links, main_content_only=main_content_only
+	)
+	chunks = chunk_markdown_by_structure(content, max_chunk_chars=max_chars)
+	content = sanitize_text(chunks[0].content if chunks else '')
+	if chunks and chunks[0].has_more:
+		logger.info(f'📄 Page is longer than {max_chars:,} chars, extracting {output_model.__name__} from the first part')
+	if redact_text is not None:
+		content = redact_text(content)
+
+	schema_json = json.dumps(output_model.model_json_schema(), indent=2)
+	messages: list[BaseMessage] = [
+		SystemMessage(content=STRUCTURED_EXTRACTION_SYSTEM_PROMPT),
+		UserMessage(
+			content=f'<query>\n{sanitize_text(query)}\n</query>\n\n'
+			f'<output_schema>\n{schema_json}\n</output_schema>\n\n'
+			f'<webpage_content>\n{content}\n</webpage_content>'
+		),
+	]
+
+	for attempt in range(max_retries + 1):
+		try:
+			response = await llm.ainvoke(messages, output_format=output_model)
+			completion = response.completion
+			# Validate with the caller's model even if the provider returned a plain dict
+			return completion if isinstance(completion, output_model) else output_model.model_validate(completion)
+		except Exception as e:
+			violation = _schema_violation(e)
+			if violation is None:
+				raise
+			if attempt == max_retries:
+				raise ExtractionValidationError(output_model, attempt + 1, violation) from e
+			errors = violation.error_count()
+			logger.warning(f'⚠️ Extracted data does not match {output_model.__name__} ({errors} errors), retrying')
+			messages = messages[:2] + [
+				UserMessage(
+					content=f'Your previous answer did not match the JSON schema:\n{violation}\n\n'
+					'Answer again with data from the webpage that matches the schema exactly.'
+				),
+			]
+	raise AssertionError('unreachable')
@@ -6,12 +6,13 @@ import re
 from collections.abc import Callable
 from inspect import Parameter, iscoroutinefunction, signature
 from types import UnionType
-from typing import Any, Generic, Optional, TypeVar, Union, get_args, get_origin
+from typing import Any, Generic, Literal, Optional, TypeVar, Union, get_args, get_origin
 
 import pyotp
-from pydantic import BaseModel, Field, RootModel, create_model
+from pydantic import BaseModel, Field, RootModel, ValidationError, create_model
 
 from browser_use.browser import BrowserSession
+from browser_use.browser.backend import BrowserBackend
 from browser_use.browser.views import BrowserError
 from browser_use.filesystem.file_system import FileSystem
 from browser_use.llm.base import BaseChatModel
@@ -22,8 +23,9 @@ from browser_use.tools.registry.views import (
 	ActionRegistry,
 	RegisteredAction,
 	SpecialActionParameters,
+	format_params_validation_error,
 )
-from browser_use.utils import is_new_tab_page, match_url_with_domain_pattern, time_execution_async
+from browser_use.utils import get_browser_use_version, is_new_tab_page, match_url_with_domain_pattern, time_execution_async
 
 Context = TypeVar('Context')
 
@@ -70,6 +72,7 @@ class Registry(Generic[Context]):
 			'has_sensitive_data': bool,
 			'file_system': FileSystem,
 			'extraction_schema': None,  # dict | None, skip type validation
+			'redact_text': None,  # Callable[[str], str] | None, skip type validation
 		}
 
 	def _normalize_action_function_signature(
@@ -133,6 +136,8 @@ class Registry(Generic[Context]):
 						or
 						# Handle list[T] vs list comparison
 						(expected_type is list and (param_type is list or get_origin(param_type) is list))
+						# Actions that only dispatch events may take the backend interface BrowserSession implements
+						or (expected_type is BrowserSession and param_type is BrowserBackend)
 					)
 
 					if not types_compatible:
@@ -338,6 +343,7 @@ class Registry(Generic[Context]):
 		sensitive_data: dict[str, str | dict[str, str]] | None = None,
 		available_file_paths: list[str] | None = None,
 		extraction_schema: dict | None = None,
+		redact_text: Callable[[str], str] | None = None,
 	) -> Any:
 		"""Execute a registered action with simplified parameter handling"""
 		if action_name not in self.registry.actions:
@@ -345,9 +351,11 @@ class Registry(Generic[Context]):
 
 		action = self.registry.actions[action_name]
 		try:
-			# Create the validated Pydantic model
+			# Validate against the action's schema before touching the browser
 			try:
 				validated_params = action.param_model(**params)
+			except ValidationError as e:
+				raise ValueError(f'Invalid parameters for action {action_name}: {format_params_validation_error(e)}') from e
 			except Exception as e:
 				raise ValueError(f'Invalid parameters {params} for action {action_name}: {type(e)}: {e}') from e
 
@@ -372,6 +380,7 @@ class Registry(Generic[Context]):
 				'has_sensitive_data': action_name == 'input' and bool(sensitive_data),
 				'file_system': file_system,
 				'extraction_schema': extraction_schema,
+				'redact_text': redact_text,
 			}
 
 			# Only pass sensitive_data to actions that explicitly need it (input)
@@ -609,3 +618,124 @@ class Registry(Generic[Context]):
 		based on their domain filters
 		"""
 		return self.registry.get_prompt_description(page_url=page_url)
+
+	def export_tool_definitions(
+		self,
+		format: Literal['json_schema', 'openapi'] = 'json_schema',
+		page_url: str | None = None,
+	) -> dict[str, Any]:
+		"""Export the registered actions (including custom actions) as a standalone schema document
+
+		Args:
+			format: 'json_schema' emits a JSON Schema (draft 2020-12) whose oneOf variants are the actions,
+				exactly as the LLM sees them. 'openapi' emits an OpenAPI 3.1 document with one POST
+				operation per action (`/actions/{name}`) that returns an ActionResult.
+			page_url: If provided, only the actions available on this URL are exported: the unrestricted ones and
+				the domain-filtered ones matching it. Otherwise all registered actions are exported, with their domain
+				filters as `x-domains`.
+
+		Parameter models are exported under `<action name>.<model name>`, so custom actions whose models share a
+		class name (e.g. `Params`) don't overwrite each other.
+
+		Returns:
+			A JSON-serializable dict.
+		"""
+		if page_url is None:
+			actions = list(self.registry.actions.values())
+		else:
+			actions = [a for a in self.registry.actions.values() if self.registry._match_domains(a.domains, page_url)]
+
+		if format == 'openapi':
+			return self._export_openapi_document(actions)
+		if format != 'json_schema':
+			raise ValueError(f"Unsupported tool definition format: {format!r}. Options: 'json_schema', 'openapi'")
+
+		defs: dict[str, Any] = {}
+		variants: list[dict[str, Any]] = []
+		for action in actions:
+			params_schema = self._action_params_schema(action, '#/$defs/', defs)
+			variants.append(
+				{
+					'title': action.name,
+					'description': action.description,
+					'type': 'object',
+					'properties': {action.name: params_schema},
+					'required': [action.name],
+					'additionalProperties': False,
+					**self._action_extensions(action),
+				}
+			)
+
+		document: dict[str, Any] = {
+			'$schema': 'https://json-schema.org/draft/2020-12/schema',
+			'title': 'browser-use actions',
+			'x-browser-use-version': get_browser_use_version(),
+			'oneOf': variants,
+		}
+		if defs:
+			document['$defs'] = defs
+		return document
+
+	def _export_openapi_document(self, actions: list[RegisteredAction]) -> dict[str, Any]:
+		"""Build an OpenAPI 3.1 document with one POST operation per action"""
+		from browser_use.agent.views import ActionResult
+
+		schemas: dict[str, Any] = {}
+		ref_template = '#/components/schemas/{model}'
+
+		result_schema = ActionResult.model_json_schema(ref_template=ref_template)
+		schemas.update(result_schema.pop('$defs', {}))
+		schemas['ActionResult'] = result_schema
+
+		paths: dict[str, Any] = {}
+		for action in actions:
+			params_name = self._schema_name(action, action.param_model.__name__)
+			schemas[params_name] = self._action_params_schema(action, '#/components/schemas/', schemas)
+			paths[f'/actions/{action.name}'] = {
+				'post': {
+					'operationId': action.name,
+					'summary': action.description,
+					'requestBody': {
+						'required': True,
+						'content': {
+							'application/json': {'schema': {'$ref': ref_template.format(model=params_name)}}
+						},
+					},
+					'responses': {
+						'200': {
+							'description': 'Result of the action',
+							'content': {'application/json': {'schema': {'$ref': ref_template.format(model='ActionResult')}}},
+						}
+					},
+					**self._action_extensions(action),
+				}
+			}
+
+		return {
+			'openapi': '3.1.0',
+			'info': {'title': 'browser-use actions', 'version': get_browser_use_version()},
+			'paths': paths,
+			'components': {'schemas': schemas},
+		}
+
+	@staticmethod
+	def _schema_name(action: RegisteredAction, model_name: str) -> str:
+		"""Name of a model in the exported definitions, namespaced by the action using it"""
+		return f'{action.name}.{model_name}'
+
+	@classmethod
+	def _action_params_schema(cls, action: RegisteredAction, ref_prefix: str, defs: dict[str, Any]) -> dict[str, Any]:
+		"""Get the JSON schema of an action's parameters, hoisting nested model definitions into defs"""
+		schema = action.param_model.model_json_schema(ref_template=ref_prefix + cls._schema_name(action, '{model}'))
+		defs.update({cls._schema_name(action, name): model for name, model in schema.pop('$defs', {}).items()})
+		return schema
+
+	@staticmethod
+	def _action_extensions(action: RegisteredAction) -> dict[str, Any]:
+		"""Vendor extensions carrying registry metadata that has no JSON Schema equivalent"""
+		extensions: dict[str, Any] = {}
+		if action.domains is not None:
+			extensions['x-domains'] = action.domains
+		if action.terminates_sequence:
+			extensions['x-terminates-sequence'] = True
+		return extensions
@@ -1,7 +1,7 @@
 from collections.abc import Callable
 from typing import TYPE_CHECKING, Any
 
-from pydantic import BaseModel, ConfigDict
+from pydantic import BaseModel, ConfigDict, ValidationError
 
 from browser_use.browser import BrowserSession
 from browser_use.filesystem.file_system import FileSystem
@@ -11,6 +11,65 @@ if TYPE_CHECKING:
 	pass
 
 
+_JSON_TYPE_NAMES: dict[type, str] = {
+	str: 'string',
+	int: 'integer',
+	float: 'number',
+	bool: 'boolean',
+	list: 'array',
+	tuple: 'array',
+	dict: 'object',
+	type(None): 'null',
+}
+
+# pydantic error type -> expected JSON type, for "'index' must be an integer, got string 'five'"
+_EXPECTED_TYPE_BY_ERROR: dict[str, str] = {
+	'int_type': 'an integer',
+	'int_parsing': 'an integer',
+	'int_from_float': 'an integer',
+	'float_type': 'a number',
+	'float_parsing': 'a number',
+	'string_type': 'a string',
+	'bool_type': 'a boolean',
+	'bool_parsing': 'a boolean',
+	'list_type': 'an array',
+	'dict_type': 'an object',
+	'model_type': 'an object',
+	'model_attributes_type': 'an object',
+}
+
+
+def format_params_validation_error(error: ValidationError, max_errors: int = 5) -> str:
+	"""Turn a pydantic ValidationError into short, precise messages the LLM can act on
+
+	e.g. "'index' must be an integer, got string 'five'; 'text' is required"
+	"""
+	messages: list[str] = []
+	for err in error.errors()[:max_errors]:
+		field = '.'.join(str(part) for part in err['loc']) or 'params'
+		error_type = err['type']
+		value = err.get('input')
+		got = f'{_JSON_TYPE_NAMES.get(type(value), type(value).__name__)} {value!r}'
+		if len(got) > 80:
+			got = got[:77] + '...'
+
+		if error_type == 'missing':
+			messages.append(f"'{field}' is required")
+		elif error_type == 'extra_forbidden':
+			messages.append(f"'{field}' is not a valid parameter")
+		elif error_type in _EXPECTED_TYPE_BY_ERROR:
+			messages.append(f"'{field}' must be {_EXPECTED_TYPE_BY_ERROR[error_type]}, got {got}")
+		elif error_type in ('literal_error', 'enum'):
+			messages.append(f"'{field}' must be one of {err.get('ctx', {}).get('expected', '')}, got {got}")
+		else:
+			messages.append(f"'{field}' {err['msg'][0].lower()}{err['msg'][1:]}, got {got}")
+
+	remaining = error.error_count() - len(messages)
+	if remaining > 0:
+		messages.append(f'and {remaining} more error(s)')
+	return '; '.join(messages)
+
+
 class RegisteredAction(BaseModel):
 	"""Model for a registered action"""
 
@@ -172,6 +231,7 @@ class SpecialActionParameters(BaseModel):
 	available_file_paths: list[str] | None = None
 	has_sensitive_data: bool = False
 	extraction_schema: dict | None = None
+	redact_text: Callable[[str], str] | None = None  # masks page text sent to an LLM, see Agent(redaction=...)
 
 	@classmethod
 	def get_browser_requiring_params(cls) -> set[str]:
@@ -0,0 +1,144 @@
+"""
+Scripted browser control with the agent's own actions, no LLM involved.
+
+	script = BrowserScript(browser)
+	await script.navigate('https://example.com/login')
+	await script.fill(ById('email'), 'me@example.com')
+	await script.click(ByText('Sign in'))
+	await Agent(task='Download the latest invoice', llm=llm, browser=browser).run()  # same tab, same indices
+
+Every call runs the registered action through Tools, so waiting, page-change handling and error messages are the
+same as when the agent acts. Elements are targeted by their index in the agent's element list (ByIndex), or by id,
+visible text or CSS selector, which are resolved to that index on a fresh browser state.
+"""
+
+from dataclasses import dataclass
+from typing import TYPE_CHECKING
+
+from browser_use.agent.views import ActionResult
+
+if TYPE_CHECKING:
+	from browser_use.browser.session import BrowserSession
+	from browser_use.browser.views import BrowserStateSummary
+	from browser_use.dom.views import EnhancedDOMTreeNode
+	from browser_use.tools.service import Tools
+
+
+@dataclass(frozen=True)
+class ByIndex:
+	"""The [index] of an element in the latest browser state, as the agent sees it."""
+
+	index: int
+
+
+@dataclass(frozen=True)
+class ById:
+	"""An interactive element by its id attribute."""
+
+	id: str
+
+
+@dataclass(frozen=True)
+class ByText:
+	"""The first interactive element whose text (or value, aria-label, placeholder) contains text, case-insensitive."""
+
+	text: str
+	exact: bool = False
+
+
+@dataclass(frozen=True)
+class BySelector:
+	"""The first interactive element matching a CSS selector."""
+
+	selector: str
+
+
+Target = ByIndex | ById | ByText | BySelector
+
+
+class ScriptError(Exception):
+	"""A scripted action failed or its target element was not found."""
+
+	def __init__(self, message: str, result: ActionResult | None = None):
+		super().__init__(message)
+		self.result = result
+
+
+def _text_matches(element: 'EnhancedDOMTreeNode', target: ByText) -> bool:
+	text = ' '.join(element.get_meaningful_text_for_llm().split()).lower()
+	wanted = ' '.join(target.text.split()).lower()
+	return text == wanted if target.exact else wanted in text
+
+
+class BrowserScript:
+	"""Typed, awaitable access to the agent's actions on a browser session."""
+
+	def __init__(self, browser_session: 'BrowserSession', tools: 'Tools | None' = None):
+		if tools is None:
+			from browser_use.tools.service import Tools
+
+			tools = Tools()
+		self.browser_session = browser_session
+		self.tools = tools
+
+	async def state(self, include_screenshot: bool = False) -> 'BrowserStateSummary':
+		"""Capture the browser state and assign element indices, like the agent does before each step."""
+		return await self.browser_session.get_browser_state_summary(include_screenshot=include_screenshot)
+
+	async def resolve(self, target: Target) -> int:
+		"""Element index of a target. ByIndex is used as is, other targets are looked up on a fresh browser state."""
+		if isinstance(target, ByIndex):
+			return target.index
+
+		await self.state()
+		selector_map = await self.browser_session.get_selector_map()
+		if isinstance(target, BySelector):
+			page = await self.browser_session.get_current_page()
+			matches = await page.get_elements_by_css_selector(target.selector) if page else []
+			backend_node_ids = [element._backend_node_id for element in matches]
+			index_by_node = {element.backend_node_id: index for index, element in selector_map.items()}
+			for backend_node_id in backend_node_ids:
+				if backend_node_id in index_by_node:
+					return index_by_node[backend_node_id]
+		else:
+			for index in sorted(selector_map):
+				element = selector_map[index]
+				if isinstance(target, ById) and (element.attributes or {}).get('id') == target.id:
+					return index
+				if isinstance(target, ByText) and _text_matches(element, target):
+					return index
+		raise ScriptError(f'No interactive element matches {target}')
+
+	async def run(self, action: str, **params) -> ActionResult:
+		"""Run any registered action by name, raising ScriptError if it reports an error."""
+		result = await getattr(self.tools, action)(browser_session=self.browser_session, **params)
+		if result.error:
+			raise ScriptError(f'{action} failed: {result.error}', result)
+		return result
+
+	async def navigate(self, url: str, new_tab: bool = False) -> ActionResult:
+		return await self.run('navigate', url=url, new_tab=new_tab)
+
+	async def go_back(self) -> ActionResult:
+		return await self.run('go_back')
+
+	async def click(self, target: Target) -> ActionResult:
+		return await self.run('click', index=await self.resolve(target))
+
+	async def fill(self, target: Target, text: str, clear: bool = True) -> ActionResult:
+		"""Type text into an input, replacing its value unless clear=False."""
+		return await self.run('input', index=await self.resolve(target), text=text, clear=clear)
+
+	async def select(self, target: Target, option: str) -> ActionResult:
+		"""Pick a dropdown option by its text, native <select> or custom dropdown."""
+		return await self.run('select_dropdown', index=await self.resolve(target), text=option)
+
+	async def scroll(self, down: bool = True, pages: float = 1.0, target: Target | None = None) -> ActionResult:
+		index = await self.resolve(target) if target is not None else None
+		return await self.run('scroll', down=down, pages=pages, index=index)
+
+	async def send_keys(self, keys: str) -> ActionResult:
+		return await self.run('send_keys', keys=keys)
+
+	async def wait(self, seconds: int = 3) -> ActionResult:
+		return await self.run('wait', seconds=seconds)
@@ -1,11 +1,18 @@
 import asyncio
+import base64
+import fnmatch
 import json
 import logging
 import math
+import mimetypes
 import os
-from typing import Generic, TypeVar
+import re
+from collections.abc import Callable
+from pathlib import Path
+from typing import Any, Generic, Literal, TypeVar
 
 import anyio
+from cdp_use.cdp.network import Cookie
 
 try:
 	from lmnr import Laminar  # type: ignore
@@ -13,8 +20,10 @@ except ImportError:
 	Laminar = None  # type: ignore
 from pydantic import BaseModel
 
-from browser_use.agent.views import ActionModel, ActionResult
+from browser_use.agent.views import ActionModel, ActionResult, AgentError
 from browser_use.browser import BrowserSession
+from browser_use.browser.backend import BrowserBackend
+from browser_use.browser.auth_vault import AUTH_VAULT_KEY_ENV, AuthVault, AuthVaultError, site_host
 from browser_use.browser.events import (
 	ClickCoordinateEvent,
 	ClickElementEvent,
@@ -29,37 +38,71 @@ from browser_use.browser.events import (
 	TypeTextEvent,
 	UploadFileEvent,
 )
-from browser_use.browser.views import BrowserError
+from browser_use.browser.views import (
+	BrowserDisconnectedError,
+	BrowserError,
+	CapturedNetworkRequest,
+	DropdownOption,
+	ElementNotFoundError,
+	NavigationError,
+)
 from browser_use.dom.service import EnhancedDOMTreeNode
-from browser_use.filesystem.file_system import FileSystem
+from browser_use.filesystem.file_system import FileSystem, FileSystemError, InMemoryFileSystem, atomic_write_bytes
 from browser_use.llm.base import BaseChatModel
 from browser_use.llm.messages import SystemMessage, UserMessage
 from browser_use.observability import observe_debug
 from browser_use.tools.registry.service import Registry
 from browser_use.tools.utils import get_click_description
 from browser_use.tools.views import (
+	AssertAction,
+	ClickCoordinateAction,
 	ClickElementAction,
 	ClickElementActionIndexOnly,
 	CloseTabAction,
+	CopyAction,
 	DoneAction,
 	ExtractAction,
+	FillFormAction,
 	FindElementsAction,
+	FindTextAction,
+	FormField,
+	GetBoundingBoxAction,
 	GetDropdownOptionsAction,
+	GetNetworkRequestsAction,
 	InputTextAction,
+	InspectElementAction,
+	ListTabsAction,
 	NavigateAction,
 	NoParamsAction,
+	PaginateExtractAction,
+	PasteAction,
+	ProcessLinksAction,
+	RestoreLoginAction,
 	SaveAsPdfAction,
 	ScreenshotAction,
 	ScrollAction,
+	ScrollCoordinateAction,
 	SearchAction,
 	SearchPageAction,
 	SelectDropdownOptionAction,
 	SendKeysAction,
+	SetViewportAction,
+	SetWindowAction,
 	StructuredOutputAction,
 	SwitchTabAction,
+	TypeTextAction,
+	UploadDropzoneAction,
 	UploadFileAction,
+	WaitForElementAction,
+	WaitForUrlAction,
+)
+from browser_use.utils import (
+	create_task_with_error_handling,
+	safe_slice,
+	sanitize_text,
+	time_execution_sync,
+	truncate_text,
 )
-from browser_use.utils import create_task_with_error_handling, sanitize_surrogates, time_execution_sync
 
 logger = logging.getLogger(__name__)
 
@@ -141,6 +184,20 @@ def _parse_env_action_timeout(raw: str | None) -> float:
 
 _DEFAULT_ACTION_TIMEOUT_S = _parse_env_action_timeout(os.getenv('BROWSER_USE_ACTION_TIMEOUT_S'))
 
+# Actions that address elements by their browser_state index, removed in computer-use mode where there is no DOM index
+_DOM_INDEX_ACTIONS = (
+	'input',
+	'upload_file',
+	'upload_dropzone',
+	'dropdown_options',
+	'select_dropdown',
+	'fill_form',
+	'find_elements',
+	'copy',
+	'paste',
+	'screenshot',
+)
+
 
 def _coerce_valid_action_timeout(value: float | None) -> float:
 	"""Normalize a caller-supplied action_timeout to a finite positive value.
@@ -163,6 +220,70 @@ def _coerce_valid_action_timeout(value: float | None) -> float:
 	return float(value)
 
 
+# Built-in search engines, {query} is replaced by the URL-encoded query
+SEARCH_ENGINES: dict[str, str] = {
+	'duckduckgo': 'https://duckduckgo.com/?q={query}',
+	'google': 'https://www.google.com/search?q={query}&udm=14',
+	'bing': 'https://www.bing.com/search?q={query}',
+	'brave': 'https://search.brave.com/search?q={query}',
+	'startpage': 'https://www.startpage.com/do/search?query={query}',
+	'kagi': 'https://kagi.com/search?q={query}',
+}
+KAGI_API_KEY_ENV = 'KAGI_API_KEY'
+# Kagi's session link token is sent as this cookie, never in the URL where the page state and history would show it
+KAGI_SESSION_COOKIE = 'kagi_session'
+
+# Engines tried in order when a search lands on a captcha or block page (Google often blocks datacenter IPs)
+DEFAULT_SEARCH_FALLBACK = ['duckduckgo', 'brave', 'bing']
+_MAX_SEARCH_FALLBACKS = 2
+
+_SEARCH_BLOCKED_URL_MARKERS = ('/sorry/', 'captcha', '/challenge', '/cdn-cgi/')
+_SEARCH_BLOCKED_TITLE_MARKERS = ('just a moment', 'attention required', 'access denied', 'are you a robot')
+_SEARCH_BLOCKED_TEXT_MARKERS = (
+	'unusual traffic',
+	'captcha',
+	"i'm not a robot",
+	'are you a robot',
+	'verify you are human',
+	'verify you are a human',
+	'bots use duckduckgo too',
+	'complete the security check',
+	'automated queries',
+)
+_SEARCH_BLOCKED_MAX_TEXT = 3000  # Block pages are short, longer pages mentioning a captcha are real results
+
+_SEARCH_PAGE_SUMMARY_JS = """
+(() => {
+	const text = document.body ? document.body.innerText : '';
+	return {url: location.href, title: document.title, text: text.slice(0, 3000), length: text.length};
+})()
+"""
+
+
+def is_blocked_search_page(url: str, title: str, text: str, text_length: int | None = None) -> bool:
+	"""Whether a search result page is a captcha, bot check or block page instead of results."""
+	from urllib.parse import urlparse
+
+	# Only the path, the query string holds the search terms
+	path, title, text = urlparse(url).path.lower(), title.lower(), text.lower()
+	if any(marker in path for marker in _SEARCH_BLOCKED_URL_MARKERS):
+		return True
+	if any(marker in title for marker in _SEARCH_BLOCKED_TITLE_MARKERS):
+		return True
+	if (text_length if text_length is not None else len(text)) > _SEARCH_BLOCKED_MAX_TEXT:
+		return False
+	return any(marker in text for marker in _SEARCH_BLOCKED_TEXT_MARKERS)
+
+
+def _auth_vault_from_env() -> AuthVault | None:
+	"""The login vault configured by BROWSER_USE_AUTH_VAULT_KEY, None if unset or cryptography is missing."""
+	try:
+		return AuthVault.from_env()
+	except ImportError as e:
+		logging.getLogger(__name__).warning(f'{AUTH_VAULT_KEY_ENV} is set but the login vault is unavailable: {e}')
+		return None
+
+
 def _detect_sensitive_key_name(text: str, sensitive_data: dict[str, str | dict[str, str]] | None) -> str | None:
 	"""Detect which sensitive key name corresponds to the given text value."""
 	if not sensitive_data or not text:
@@ -186,10 +307,13 @@ def handle_browser_error(e: BrowserError) -> ActionResult:
 	if e.long_term_memory is not None:
 		if e.short_term_memory is not None:
 			return ActionResult(
-				extracted_content=e.short_term_memory, error=e.long_term_memory, include_extracted_content_only_once=True
+				extracted_content=e.short_term_memory,
+				error=e.long_term_memory,
+				error_type=e.error_type,
+				include_extracted_content_only_once=True,
 			)
 		else:
-			return ActionResult(error=e.long_term_memory)
+			return ActionResult(error=e.long_term_memory, error_type=e.error_type)
 	# Fallback to original error handling if long_term_memory is None
 	logger.warning(
 		'⚠️ A BrowserError was raised without long_term_memory - always set long_term_memory when raising BrowserError to propagate right messages to LLM.'
@@ -197,6 +321,142 @@ def handle_browser_error(e: BrowserError) -> ActionResult:
 	raise e
 
 
+_READ_SELECTION_JS = """(() => {
+	const el = document.activeElement;
+	if (el && typeof el.value === 'string' && typeof el.selectionStart === 'number' && el.selectionEnd > el.selectionStart) {
+		return el.value.substring(el.selectionStart, el.selectionEnd);
+	}
+	return window.getSelection().toString();
+})()"""
+
+_READ_ELEMENT_TEXT_JS = """function() {
+	if (typeof this.value === 'string') return this.value;
+	return this.innerText || this.textContent || '';
+}"""
+
+
+# Creates a hidden file input in the drop zone's document so DOM.setFileInputFiles can attach real File objects
+_CREATE_DROPZONE_INPUT_JS = """function() {
+	const input = this.ownerDocument.createElement('input');
+	input.type = 'file';
+	input.multiple = true;
+	input.style.display = 'none';
+	this.ownerDocument.body.appendChild(input);
+	return input;
+}"""
+
+# Larger files are dropped through a helper input instead of being sent as base64 over CDP
+_MAX_INLINE_DROP_BYTES = 25 * 1024 * 1024
+
+# Limits of one upload_file call, directories are expanded to their files
+_MAX_UPLOAD_FILES = 500
+_MAX_UPLOAD_BYTES = 2 * 1024 * 1024 * 1024
+
+
+def _expand_upload_paths(paths: list[str]) -> tuple[list[str], str | None]:
+	"""Expand directories to their files and check that every file exists and has content. Returns (files, error)."""
+	files: list[str] = []
+	for path in paths:
+		if os.path.isdir(path):
+			directory_files = sorted(
+				os.path.join(root, name) for root, _, names in os.walk(path) for name in names if not name.startswith('.')
+			)
+			if not directory_files:
+				return [], f'Directory {path} has no files to upload'
+			files.extend(directory_files)
+		elif os.path.exists(path):
+			files.append(path)
+		else:
+			return [], f'File {path} does not exist'
+
+	if len(files) > _MAX_UPLOAD_FILES:
+		return [], f'{len(files)} files to upload, at most {_MAX_UPLOAD_FILES} can be uploaded at once'
+	empty = [file for file in files if os.path.getsize(file) == 0]
+	if empty:
+		return [], f'File {empty[0]} is empty (0 bytes). The file may not have been saved correctly.'
+	total_size = sum(os.path.getsize(file) for file in files)
+	if total_size > _MAX_UPLOAD_BYTES:
+		return [], f'Files to upload total {total_size / 1024**3:.1f} GB, more than the {_MAX_UPLOAD_BYTES // 1024**3} GB limit'
+	return files, None
+
+
+# Finds the hidden file input an uploader widget keeps for its drop zone, null if there is none
+_FIND_DROPZONE_INPUT_JS = """function() {
+	const doc = this.ownerDocument;
+	if (this.tagName === 'INPUT' && this.type === 'file') return this;
+	if (this.tagName === 'LABEL' && this.control && this.control.type === 'file') return this.control;
+	// Most uploaders (react-dropzone, FilePond, ...) render the input inside the zone
+	const inside = this.querySelector('input[type=file]');
+	if (inside) return inside;
+	// Dropzone.js appends its input to the body
+	if (this.classList.contains('dropzone') || this.classList.contains('dz-clickable')) {
+		const hidden = doc.querySelector('input.dz-hidden-input');
+		if (hidden) return hidden;
+	}
+	// Otherwise the only file input of a close ancestor, e.g. the uploader's container
+	let parent = this.parentElement;
+	for (let depth = 0; parent && depth < 3; depth++, parent = parent.parentElement) {
+		const inputs = parent.querySelectorAll('input[type=file]');
+		if (inputs.length === 1) return inputs[0];
+		if (inputs.length > 1) break;
+	}
+	return null;
+}"""
+
+# Fires the drag-and-drop sequence a user's drop would produce. The DataTransfer carries either the files of
+# a helper input, or files built from base64 payloads ({name, type, data}) when no input is passed
+_DROP_FILES_JS = """function(input, payloads) {
+	const dataTransfer = new DataTransfer();
+	if (input) {
+		for (const file of input.files) dataTransfer.items.add(file);
+		input.remove();
+	} else {
+		for (const payload of payloads) {
+			const bytes = Uint8Array.from(atob(payload.data), (c) => c.charCodeAt(0));
+			dataTransfer.items.add(new File([bytes], payload.name, {type: payload.type}));
+		}
+	}
+	const rect = this.getBoundingClientRect();
+	const init = {
+		bubbles: true,
+		cancelable: true,
+		composed: true,
+		dataTransfer,
+		clientX: rect.left + rect.width / 2,
+		clientY: rect.top + rect.height / 2,
+	};
+	for (const type of ['dragenter', 'dragover', 'drop']) {
+		this.dispatchEvent(new DragEvent(type, init));
+	}
+	return dataTransfer.files.length;
+}"""
+
+
+async def _read_browser_clipboard(browser_session: BrowserSession) -> str:
+	"""Read navigator.clipboard of the focused page, granting clipboard access for its origin if the profile did not"""
+	from urllib.parse import urlparse
+
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	if 'clipboardReadWrite' not in browser_session.browser_profile.permissions:
+		parsed = urlparse(await browser_session.get_current_page_url())
+		params = {'permissions': ['clipboardReadWrite'], 'origin': f'{parsed.scheme}://{parsed.netloc}'}
+		if browser_session.browser_context_id:
+			params['browserContextId'] = browser_session.browser_context_id
+		await browser_session.cdp_client.send.Browser.grantPermissions(params=params)  # type: ignore
+	# navigator.clipboard rejects reads from unfocused documents, which background/headless tabs are
+	await cdp_session.cdp_client.send.Emulation.setFocusEmulationEnabled(
+		params={'enabled': True}, session_id=cdp_session.session_id
+	)
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': 'navigator.clipboard.readText()', 'awaitPromise': True, 'userGesture': True},
+		session_id=cdp_session.session_id,
+	)
+	if result.get('exceptionDetails'):
+		description = result.get('result', {}).get('description') or result['exceptionDetails'].get('text', 'unknown error')
+		raise BrowserError(f'Could not read the browser clipboard: {description}')
+	return result.get('result', {}).get('value') or ''
+
+
 # --- JS templates for search_page and find_elements ---
 
 _SEARCH_PAGE_JS_BODY = """\
@@ -408,7 +668,7 @@ def _format_find_results(data: dict, selector: str) -> str:
 			# Collapse whitespace for readability
 			display_text = ' '.join(text.split())
 			if len(display_text) > 120:
-				display_text = display_text[:120] + '...'
+				display_text = safe_slice(display_text, 120) + '...'
 			parts.append(f'"{display_text}"')
 		if attrs:
 			attr_strs = [f'{k}="{v}"' for k, v in attrs.items()]
@@ -422,6 +682,432 @@ def _format_find_results(data: dict, selector: str) -> str:
 	return '\n'.join(lines)
 
 
+# --- assert: verification checks with structured pass/fail results ---
+
+# Text checks report whether the text is found, element checks the match count and the live value of the first match
+_ASSERT_JS = """
+(function(p) {
+	const norm = s => (s || '').replace(/\\s+/g, ' ').trim();
+	if (p.check === 'text_present' || p.check === 'text_absent') {
+		let text = norm(document.body ? document.body.innerText : '');
+		let needle = norm(p.value);
+		if (!p.case_sensitive) {
+			text = text.toLowerCase();
+			needle = needle.toLowerCase();
+		}
+		return {found: text.includes(needle)};
+	}
+	let elements;
+	try {
+		elements = document.querySelectorAll(p.selector);
+	} catch (e) {
+		return {error: 'Invalid CSS selector: ' + p.selector};
+	}
+	if (p.check === 'element_exists' || !elements.length) return {count: elements.length};
+	const el = elements[0];
+	let actual;
+	if (p.attribute === 'value' && 'value' in el) actual = String(el.value);
+	else if (p.attribute === 'checked' && 'checked' in el) actual = String(el.checked);
+	else if (p.attribute === 'text') actual = norm(el.innerText || el.textContent);
+	else actual = el.getAttribute(p.attribute);
+	return {count: elements.length, actual: actual};
+})
+"""
+
+
+def _describe_assertion(params: AssertAction) -> str:
+	if params.check == 'text_present':
+		return f'text "{params.value}" is present'
+	if params.check == 'text_absent':
+		return f'text "{params.value}" is absent'
+	if params.check == 'element_exists':
+		return f'element "{params.selector}" exists'
+	if params.check == 'url_matches':
+		return f'URL matches "{params.value}"'
+	return f'{params.attribute} of "{params.selector}" equals "{params.value}"'
+
+
+# --- inspect_element: full details of one element, beyond the truncated browser_state line ---
+
+_INSPECT_ELEMENT_JS = """
+function(maxHtmlChars) {
+	const html = this.outerHTML || '';
+	const rect = this.getBoundingClientRect();
+	const style = window.getComputedStyle(this);
+	const attributes = {};
+	for (const attr of Array.from(this.attributes || [])) attributes[attr.name] = attr.value;
+	return {
+		html: html.slice(0, maxHtmlChars),
+		html_length: html.length,
+		attributes: attributes,
+		text: (this.innerText || this.textContent || '').replace(/\\s+/g, ' ').trim().slice(0, 1000),
+		rect: {x: Math.round(rect.x), y: Math.round(rect.y), width: Math.round(rect.width), height: Math.round(rect.height)},
+		visible: rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none',
+		in_viewport: rect.bottom > 0 && rect.right > 0 && rect.top < window.innerHeight && rect.left < window.innerWidth,
+		value: 'value' in this && typeof this.value === 'string' ? this.value : null,
+	};
+}
+"""
+
+
+def _format_element_details(index: int, details: dict) -> str:
+	lines = [f'Element [{index}] <{details["tag"]}>']
+	if details.get('role') or details.get('name'):
+		lines.append(f'Role: {details.get("role") or "none"}, accessible name: "{details.get("name") or ""}"')
+	if details.get('description'):
+		lines.append(f'Accessible description: "{details["description"]}"')
+	rect = details['rect']
+	visibility = 'visible' if details['visible'] else 'hidden'
+	if details['visible'] and not details['in_viewport']:
+		visibility += ', outside the viewport'
+	box = f'x={rect["x"]}, y={rect["y"]}, width={rect["width"]}, height={rect["height"]}'
+	lines.append(f'Bounding box (viewport): {box} ({visibility})')
+	lines.append(f'XPath: {details["xpath"]}')
+	if details['attributes']:
+		lines.append('Attributes:')
+		lines.extend(f'  {name}="{value}"' for name, value in details['attributes'].items())
+	if details.get('value') is not None:
+		lines.append(f'Current value: "{details["value"]}"')
+	if details['text']:
+		lines.append(f'Text: {details["text"]}')
+	shown = len(details['html'])
+	truncated = f', showing the first {shown}' if shown < details['html_length'] else ''
+	lines.append(f'outerHTML ({details["html_length"]} chars{truncated}):')
+	lines.append(details['html'])
+	return '\n'.join(lines)
+
+
+# --- find_text: ranked exact / case-insensitive / fuzzy text matches ---
+
+# Returns the ranked matches as a JSON string plus the matched elements by reference, so their backend node ids can be resolved
+_FIND_TEXT_JS = """
+(function(query, maxResults) {
+	const SKIP_TAGS = new Set(['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'SVG']);
+	const KIND_RANK = {exact: 0, case_insensitive: 1, fuzzy: 2};
+	// Bounds for the edit distance, which is quadratic: element text and query length, and elements compared
+	const MAX_FUZZY_TEXT = 300;
+	const MAX_FUZZY_QUERY = 100;
+	const MAX_FUZZY_ELEMENTS = 2000;
+	const MAX_CANDIDATES = 5000;
+	const FUZZY_THRESHOLD = 0.75;
+	const normalize = (text) => (text || '').replace(/\\s+/g, ' ').trim();
+	const target = normalize(query);
+	const targetFolded = target.toLowerCase();
+	const targetWords = targetFolded.split(' ');
+
+	const similarity = (a, b) => {
+		let previous = Array.from({length: b.length + 1}, (_, i) => i);
+		for (let i = 1; i <= a.length; i++) {
+			const current = [i];
+			for (let j = 1; j <= b.length; j++) {
+				current.push(Math.min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + (a[i - 1] === b[j - 1] ? 0 : 1)));
+			}
+			previous = current;
+		}
+		return 1 - previous[b.length] / Math.max(a.length, b.length, 1);
+	};
+	// Best match of the query against any run of words of about the same length
+	const fuzzyMatch = (text) => {
+		const words = text.toLowerCase().split(' ');
+		let best = {score: 0, position: 0, length: 0};
+		for (let size = Math.max(1, targetWords.length - 1); size <= targetWords.length + 1; size++) {
+			for (let i = 0; i + size <= words.length; i++) {
+				const candidate = words.slice(i, i + size).join(' ');
+				const score = similarity(candidate, targetFolded);
+				if (score > best.score) {
+					const position = words.slice(0, i).join(' ').length + (i > 0 ? 1 : 0);
+					best = {score: score, position: position, length: candidate.length};
+				}
+			}
+		}
+		return best;
+	};
+	const textOf = (el) => {
+		for (const child of el.childNodes) {
+			if (child.nodeType === Node.TEXT_NODE && child.textContent.trim()) return normalize(el.textContent);
+		}
+		const isButtonInput = el.tagName === 'INPUT' && ['button', 'submit', 'reset'].includes(el.type);
+		const label = el.getAttribute('aria-label') || el.getAttribute('placeholder') || el.getAttribute('alt');
+		return normalize(label || (isButtonInput ? el.value : ''));
+	};
+	const isVisible = (el) => {
+		if (!el.getClientRects().length) return false;
+		const style = getComputedStyle(el);
+		return style.visibility !== 'hidden' && style.display !== 'none';
+	};
+
+	const candidates = [];
+	const root = document.body || document.documentElement;
+	const walker = document.createTreeWalker(root, NodeFilter.SHOW_ELEMENT, {
+		acceptNode: (el) => SKIP_TAGS.has(el.tagName.toUpperCase()) ? NodeFilter.FILTER_REJECT : NodeFilter.FILTER_ACCEPT,
+	});
+	let order = 0, fuzzyChecked = 0;
+	while (walker.nextNode() && candidates.length < MAX_CANDIDATES) {
+		const el = walker.currentNode;
+		const text = textOf(el);
+		if (!text) continue;
+		let kind = null, score = 1, position = text.indexOf(target), length = target.length;
+		if (position >= 0) {
+			kind = 'exact';
+		} else if ((position = text.toLowerCase().indexOf(targetFolded)) >= 0) {
+			kind = 'case_insensitive';
+		} else if (
+			target.length >= 4 && target.length <= MAX_FUZZY_QUERY && text.length <= MAX_FUZZY_TEXT
+			&& fuzzyChecked++ < MAX_FUZZY_ELEMENTS
+		) {
+			const best = fuzzyMatch(text);
+			if (best.score >= FUZZY_THRESHOLD) {
+				kind = 'fuzzy';
+				score = Math.round(best.score * 100) / 100;
+				position = best.position;
+				length = best.length;
+			}
+		}
+		if (kind) candidates.push({el, text, kind, score, position, length, order: order++, visible: isVisible(el)});
+	}
+
+	// An element's text includes its children's, keep only the innermost element for each match
+	const byElement = new Map(candidates.map((m) => [m.el, m]));
+	const covered = new Set();
+	for (const m of candidates) {
+		for (let parent = m.el.parentElement; parent; parent = parent.parentElement) {
+			const outer = byElement.get(parent);
+			if (outer && KIND_RANK[m.kind] <= KIND_RANK[outer.kind]) covered.add(outer);
+		}
+	}
+	const matches = candidates.filter((m) => !covered.has(m));
+	matches.sort((a, b) => KIND_RANK[a.kind] - KIND_RANK[b.kind] || b.score - a.score
+		|| Number(b.visible) - Number(a.visible) || a.text.length - b.text.length || a.order - b.order);
+
+	const top = matches.slice(0, maxResults);
+	const excerpt = (m) => {
+		const start = Math.max(0, m.position - 40);
+		const end = Math.min(m.text.length, m.position + m.length + 40);
+		return (start > 0 ? '...' : '') + m.text.slice(start, end) + (end < m.text.length ? '...' : '');
+	};
+	return {
+		data: JSON.stringify({
+			total: matches.length,
+			matches: top.map((m) => ({
+				kind: m.kind, score: m.score, text: excerpt(m), tag: m.el.tagName.toLowerCase(), visible: m.visible,
+			})),
+		}),
+		elements: top.map((m) => m.el),
+	};
+})
+"""
+
+# Scrolls a match into view and optionally outlines it, the outline is removed after the next screenshot
+_SHOW_TEXT_MATCH_JS = """
+function(highlight, color) {
+	this.scrollIntoView({block: 'center', inline: 'nearest'});
+	document.querySelectorAll('[data-browser-use-text-match]').forEach((el) => el.remove());
+	if (!highlight) return;
+	const rect = this.getBoundingClientRect();
+	const box = document.createElement('div');
+	box.setAttribute('data-browser-use-text-match', '');
+	Object.assign(box.style, {
+		position: 'absolute',
+		left: rect.left + window.scrollX - 4 + 'px',
+		top: rect.top + window.scrollY - 4 + 'px',
+		width: rect.width + 8 + 'px',
+		height: rect.height + 8 + 'px',
+		border: '3px solid ' + color,
+		borderRadius: '4px',
+		boxSizing: 'border-box',
+		pointerEvents: 'none',
+		zIndex: '2147483647',
+	});
+	document.documentElement.appendChild(box);
+}
+"""
+
+# How many levels above an interactive element matched text may sit and still be reported with its index
+_TEXT_MATCH_MAX_DEPTH = 3
+
+
+def _interactive_indices_by_backend_id(selector_map: dict[int, EnhancedDOMTreeNode], session_id: str | None) -> dict[int, int]:
+	"""Map backend node ids of interactive elements and their close descendants to the interactive element's index."""
+	indices: dict[int, tuple[int, int]] = {}  # backend_node_id -> (depth below the interactive element, index)
+	for index, node in selector_map.items():
+		if str(node.session_id) != str(session_id):
+			continue
+		stack = [(node, 0)]
+		while stack:
+			current, depth = stack.pop()
+			known = indices.get(current.backend_node_id)
+			if known is None or depth < known[0]:
+				indices[current.backend_node_id] = (depth, index)
+			if depth < _TEXT_MATCH_MAX_DEPTH:
+				stack.extend((child, depth + 1) for child in current.children_and_shadow_roots)
+	return {backend_node_id: index for backend_node_id, (_, index) in indices.items()}
+
+
+def _format_text_matches(matches: list[dict], total: int, text: str, scrolled_to: int | None) -> str:
+	"""Format ranked find_text matches for the agent."""
+	if not matches:
+		return f"Text '{text}' not found on page"
+
+	lines = [f'Found {total} match{"es" if total != 1 else ""} for "{text}", best first:']
+	for rank, match in enumerate(matches, start=1):
+		kind = match['kind'].replace('_', '-')
+		if kind == 'fuzzy':
+			kind = f'fuzzy {match["score"]:.2f}'
+		parts = [f'{rank}. [{kind}] "{match["text"]}" <{match["tag"]}>']
+		if match.get('index') is not None:
+			parts.append(f'index {match["index"]}')
+		if not match['visible']:
+			parts.append('(hidden)')
+		lines.append(' '.join(parts))
+	if total > len(matches):
+		lines.append(f'... showing {len(matches)} of {total} matches. Increase max_results to see more.')
+	if scrolled_to is not None:
+		lines.append(f'Scrolled to match {scrolled_to}.')
+	return '\n'.join(lines)
+
+
+# --- paginate_extract: click through result pages ---
+
+_CLICK_NEXT_PAGE_JS = """
+(function(selector, text) {
+	const isVisible = (el) => {
+		if (!el.getClientRects().length) return false;
+		const style = getComputedStyle(el);
+		return style.visibility !== 'hidden' && style.display !== 'none';
+	};
+	const isDisabled = (el) => el.disabled || el.getAttribute('aria-disabled') === 'true' || el.classList.contains('disabled');
+	let candidates;
+	if (selector) {
+		try {
+			candidates = Array.from(document.querySelectorAll(selector));
+		} catch (e) {
+			return {error: 'Invalid CSS selector: ' + e.message};
+		}
+	} else {
+		const wanted = text.trim().toLowerCase();
+		const labelOf = (el) => (
+			el.innerText || el.value || el.getAttribute('aria-label') || el.getAttribute('title') || ''
+		).trim().toLowerCase();
+		const clickable = Array.from(document.querySelectorAll(
+			'a, button, [role="button"], [role="link"], input[type="button"], input[type="submit"]'
+		));
+		candidates = clickable.filter((el) => labelOf(el) === wanted);
+		if (!candidates.length) candidates = clickable.filter((el) => labelOf(el).includes(wanted));
+	}
+	const next = candidates.find((el) => isVisible(el) && !isDisabled(el));
+	if (!next) return {clicked: false};
+	next.scrollIntoView({block: 'center'});
+	next.click();
+	return {clicked: true};
+})
+"""
+
+# Identifies what the page shows, to tell when the next page has loaded
+_PAGE_FINGERPRINT_JS = """
+(() => {
+	const text = document.body ? document.body.innerText : '';
+	let hash = 0;
+	for (let i = 0; i < text.length; i++) hash = (hash * 31 + text.charCodeAt(i)) | 0;
+	return {url: location.href, hash: hash, ready: document.readyState};
+})()
+"""
+
+_NEXT_PAGE_TIMEOUT_S = 10.0
+
+
+async def _page_fingerprint(browser_session: BrowserSession) -> dict | None:
+	try:
+		cdp_session = await browser_session.get_or_create_cdp_session()
+		result = await cdp_session.cdp_client.send.Runtime.evaluate(
+			params={'expression': _PAGE_FINGERPRINT_JS, 'returnByValue': True}, session_id=cdp_session.session_id
+		)
+	except Exception:
+		# The execution context goes away while the next page navigates
+		return None
+	return result.get('result', {}).get('value')
+
+
+async def _go_to_next_page(browser_session: BrowserSession, selector: str | None, text: str | None) -> str | None:
+	"""Click the next page link and wait until the new page content has settled.
+
+	Returns None when the next page is shown, otherwise why pagination ends.
+	"""
+	before = await _page_fingerprint(browser_session)
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': f'{_CLICK_NEXT_PAGE_JS}({json.dumps(selector)}, {json.dumps(text)})', 'returnByValue': True},
+		session_id=cdp_session.session_id,
+	)
+	clicked = result.get('result', {}).get('value') or {}
+	if clicked.get('error'):
+		raise BrowserError(clicked['error'])
+	if not clicked.get('clicked'):
+		return 'no next page link found'
+
+	# Wait for the content to change, then for it to stay the same for one poll so lazy-loaded results are in
+	previous = before
+	deadline = asyncio.get_running_loop().time() + _NEXT_PAGE_TIMEOUT_S
+	while asyncio.get_running_loop().time() < deadline:
+		await asyncio.sleep(_WAIT_POLL_INTERVAL_S)
+		current = await _page_fingerprint(browser_session)
+		if current is None or current.get('ready') != 'complete':
+			previous = None
+			continue
+		if current != before and current == previous:
+			return None
+		previous = current
+	return 'page did not change after clicking the next page link'
+
+
+def _format_network_requests(requests: list[CapturedNetworkRequest], max_body_chars: int) -> str:
+	"""Format captured network requests and their response bodies for the agent."""
+	blocks = []
+	for request in requests:
+		status = request.error or (str(request.status) if request.status is not None else 'no status')
+		header = f'{request.method} {request.url} -> {status}'
+		if request.mime_type:
+			header += f' ({request.mime_type})'
+		lines = [header]
+		if request.request_body:
+			lines.append(f'Request body: {truncate_text(request.request_body, 500)}')
+		if request.body:
+			body = request.body
+			if len(body) > max_body_chars or request.body_truncated:
+				body = safe_slice(body, max_body_chars) + '... [truncated]'
+			lines.append(body)
+		blocks.append('\n'.join(lines))
+	return '\n\n'.join(blocks)
+
+
+# Called on the element (or null), reports whether it is in the DOM and shown
+_ELEMENT_STATE_JS = """(el) => {
+	if (!el || !el.isConnected) return {attached: false, visible: false};
+	const style = getComputedStyle(el);
+	const rect = el.getBoundingClientRect();
+	const visible = style.display !== 'none' && style.visibility !== 'hidden' && rect.width > 0 && rect.height > 0;
+	return {attached: true, visible};
+}"""
+
+_WAIT_POLL_INTERVAL_S = 0.2
+
+
+def _element_state_reached(state: dict[str, bool], wanted: str) -> bool:
+	if wanted == 'attached':
+		return state['attached']
+	if wanted == 'hidden':
+		return not state['visible']
+	return state['visible']
+
+
+def _url_matches(url: str, pattern: str, regex: bool) -> bool:
+	"""Match a URL against a regex, a glob with *, or a plain substring."""
+	if regex:
+		return re.search(pattern, url) is not None
+	if '*' in pattern:
+		return fnmatch.fnmatchcase(url, pattern)
+	return pattern in url
+
+
 def _is_autocomplete_field(node: EnhancedDOMTreeNode) -> bool:
 	"""Detect if a node is an autocomplete/combobox field from its attributes."""
 	attrs = node.attributes or {}
@@ -438,17 +1124,85 @@ def _is_autocomplete_field(node: EnhancedDOMTreeNode) -> bool:
 	return False
 
 
+_TRUTHY_FORM_VALUES = ('true', 'yes', 'on', 'checked', '1')
+_FORM_FIELD_ROLES = ('textbox', 'combobox', 'checkbox', 'radio')
+
+
+def _find_form_field(selector_map: dict[int, EnhancedDOMTreeNode], label: str) -> int | None:
+	"""Find the index of a form control by its accessible name, aria-label, placeholder, name or id."""
+	wanted = label.strip().lower()
+	partial_match = None
+	for index, node in selector_map.items():
+		attrs = node.attributes or {}
+		if node.tag_name not in ('input', 'textarea', 'select') and attrs.get('role') not in _FORM_FIELD_ROLES:
+			continue
+		names = [node.ax_node.name if node.ax_node else None] + [
+			attrs.get(key) for key in ('aria-label', 'placeholder', 'name', 'id')
+		]
+		names = [name.strip().lower() for name in names if name]
+		if wanted in names:
+			return index
+		if partial_match is None and any(wanted in name for name in names):
+			partial_match = index
+	return partial_match
+
+
+def _is_checked(node: EnhancedDOMTreeNode) -> bool:
+	"""Current checked state of a checkbox or radio, preferring the live accessibility state over attributes."""
+	if node.ax_node and node.ax_node.properties:
+		for prop in node.ax_node.properties:
+			if prop.name == 'checked':
+				return prop.value is True or prop.value == 'true'
+	attrs = node.attributes or {}
+	return 'checked' in attrs or attrs.get('aria-checked', 'false').lower() == 'true'
+
+
 class Tools(Generic[Context]):
 	def __init__(
 		self,
 		exclude_actions: list[str] | None = None,
 		output_model: type[T] | None = None,
 		display_files_in_done_text: bool = True,
+		action_timeout: float | None = None,
+		action_timeouts: dict[str, float] | None = None,
+		allow_host_uploads: bool = False,
+		extract_max_chars: int = 100_000,
+		auth_vault: AuthVault | None = None,
+		default_search_engine: str = 'duckduckgo',
+		search_engines: dict[str, str] | None = None,
+		search_fallback: list[str] | None = None,
+		kagi_api_key: str | None = None,
 	):
 		self.registry = Registry[Context](exclude_actions if exclude_actions is not None else [])
 		self.display_files_in_done_text = display_files_in_done_text
+		# When False, upload_file on local browsers only accepts FileSystem files, available_file_paths and downloads
+		self.allow_host_uploads = allow_host_uploads
+		# Markdown characters the extract action sends to the extraction LLM per call, longer pages continue via start_from_char
+		self.extract_max_chars = extract_max_chars
+		# Agent-side clipboard shared by the copy / paste actions, independent of the OS clipboard
+		self.clipboard: str | None = None
+		# Per-action wall-clock caps (seconds): action_timeouts overrides action_timeout by action name,
+		# e.g. Tools(action_timeout=30, action_timeouts={'extract': 150, 'evaluate': 10})
+		self.action_timeout: float | None = _coerce_valid_action_timeout(action_timeout) if action_timeout is not None else None
+		self.action_timeouts: dict[str, float] = {
+			name: _coerce_valid_action_timeout(timeout) for name, timeout in (action_timeouts or {}).items()
+		}
 		self._output_model: type[BaseModel] | None = output_model
 		self._coordinate_clicking_enabled: bool = False
+		# Saved login sessions the agent may restore with restore_login, from BROWSER_USE_AUTH_VAULT_KEY if not passed
+		self.auth_vault: AuthVault | None = auth_vault if auth_vault is not None else _auth_vault_from_env()
+		# Search engine URL templates by name, custom ones use {query} for the URL-encoded query
+		self.search_engines: dict[str, str] = {**SEARCH_ENGINES, **{k.lower(): v for k, v in (search_engines or {}).items()}}
+		self.default_search_engine = default_search_engine.lower()
+		if self.default_search_engine not in self.search_engines:
+			options = ', '.join(self.search_engines)
+			raise ValueError(f'Unknown default_search_engine {default_search_engine!r}, options: {options}')
+		# Engines tried when a search hits a captcha/block page, [] disables the fallback
+		self.search_fallback: list[str] = [
+			name.lower() for name in (search_fallback if search_fallback is not None else DEFAULT_SEARCH_FALLBACK)
+		]
+		self.kagi_api_key: str | None = kagi_api_key or os.getenv(KAGI_API_KEY_ENV) or None
+		self._computer_use_mode: bool = False
 
 		"""Register all default browser actions"""
 
@@ -461,43 +1215,50 @@ class Tools(Generic[Context]):
 			terminates_sequence=True,
 		)
 		async def search(params: SearchAction, browser_session: BrowserSession):
-			import urllib.parse
-
-			# Encode query for URL safety
-			encoded_query = urllib.parse.quote_plus(params.query)
-
-			# Build search URL based on search engine
-			search_engines = {
-				'duckduckgo': f'https://duckduckgo.com/?q={encoded_query}',
-				'google': f'https://www.google.com/search?q={encoded_query}&udm=14',
-				'bing': f'https://www.bing.com/search?q={encoded_query}',
-			}
-
-			if params.engine.lower() not in search_engines:
-				return ActionResult(error=f'Unsupported search engine: {params.engine}. Options: duckduckgo, google, bing')
-
-			search_url = search_engines[params.engine.lower()]
-
-			# Simple tab logic: use current tab by default
-			use_new_tab = False
-
-			# Dispatch navigation event
-			try:
-				event = browser_session.event_bus.dispatch(
-					NavigateToUrlEvent(
-						url=search_url,
-						new_tab=use_new_tab,
-					)
+			engine = (params.engine or self.default_search_engine).lower()
+			if engine not in self.search_engines:
+				return ActionResult(
+					error=f'Unsupported search engine: {params.engine}. Options: {", ".join(self.search_engines)}'
 				)
-				await event
-				await event.event_result(raise_if_any=True, raise_if_none=False)
-				memory = f"Searched {params.engine.title()} for '{params.query}'"
-				msg = f'🔍  {memory}'
-				logger.info(msg)
+
+			# The chosen engine first, then the fallbacks if it shows a captcha or block page
+			engines = [engine] + [name for name in self.search_fallback if name != engine and name in self.search_engines]
+			engines = engines[: _MAX_SEARCH_FALLBACKS + 1]
+			blocked: list[str] = []
+			for name in engines:
+				try:
+					search_url = self._search_url(name, params.query)
+					cookies = self._search_cookies(name)
+				except ValueError as e:
+					if name == engine:
+						return ActionResult(error=str(e))
+					continue
+
+				# Dispatch navigation event, in the current tab
+				try:
+					if cookies:
+						await browser_session._cdp_set_cookies(cookies)
+					event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=search_url, new_tab=False))
+					await event
+					await event.event_result(raise_if_any=True, raise_if_none=False)
+				except Exception as e:
+					logger.error(f'Failed to search {name}: {e}')
+					return ActionResult(error=f'Failed to search {name} for "{params.query}": {str(e)}')
+
+				if await self._search_page_blocked(browser_session):
+					logger.warning(f'⚠️ {name.title()} showed a captcha or block page, trying the next search engine')
+					blocked.append(name.title())
+					continue
+
+				memory = f"Searched {name.title()} for '{params.query}'"
+				if blocked:
+					memory += f' ({", ".join(blocked)} showed a captcha or block page)'
+				logger.info(f'🔍  {memory}')
 				return ActionResult(extracted_content=memory, long_term_memory=memory)
-			except Exception as e:
-				logger.error(f'Failed to search {params.engine}: {e}')
-				return ActionResult(error=f'Failed to search {params.engine} for "{params.query}": {str(e)}')
+
+			return ActionResult(
+				error=f'Could not search for "{params.query}": {", ".join(blocked)} showed a captcha or block page'
+			)
 
 		@self.registry.action(
 			'',
@@ -507,9 +1268,21 @@ class Tools(Generic[Context]):
 		async def navigate(params: NavigateAction, browser_session: BrowserSession):
 			try:
 				# Dispatch navigation event
-				event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=params.url, new_tab=params.new_tab))
+				event = browser_session.event_bus.dispatch(
+					NavigateToUrlEvent(url=params.url, new_tab=params.new_tab, wait_until=params.wait_until)
+				)
 				await event
-				await event.event_result(raise_if_any=True, raise_if_none=False)
+				navigation = await event.event_result(raise_if_any=True, raise_if_none=False)
+				metadata = {'navigation': navigation.model_dump()} if navigation else None
+
+				# The page is open, but it is an error page the model should not mistake for the content it wanted
+				if navigation and (http_error := navigation.error_message()):
+					browser_session.logger.warning(f'⚠️ {http_error}')
+					return ActionResult(
+						error=f'Navigation to {params.url} failed: {http_error}',
+						error_type=NavigationError.error_type,
+						metadata=metadata,
+					)
 
 				# Health check: detect empty DOM for http/https pages and retry once.
 				# Uses _root is None (truly blank) OR empty llm_representation() (no actionable
@@ -543,24 +1316,43 @@ class Tools(Generic[Context]):
 									f'or have a connection issue (e.g. tunnel/proxy error). Try a different URL or approach.'
 								)
 
-				if params.new_tab:
-					memory = f'Opened new tab with URL {params.url}'
-					msg = f'🔗  Opened new tab with url {params.url}'
+				destination = navigation.describe() if navigation else params.url
+				if navigation and navigation.reused_tab_id:
+					tab_id = navigation.reused_tab_id[-4:]
+					memory = f'Navigated the tab already open on this site (tab_id: {tab_id}) to {destination} instead of a new tab'
+					msg = f'🔗 {memory}'
+				elif params.new_tab:
+					memory = f'Opened new tab with URL {destination}'
+					msg = f'🔗  Opened new tab with url {destination}'
 				else:
-					memory = f'Navigated to {params.url}'
+					memory = f'Navigated to {destination}'
 					msg = f'🔗 {memory}'
+				if navigation and navigation.closed_tab_ids:
+					closed = ', '.join(target_id[-4:] for target_id in navigation.closed_tab_ids)
+					memory += f' (closed least recently used tab_id(s) {closed} to stay within the tab limit)'
 
 				logger.info(msg)
-				return ActionResult(extracted_content=msg, long_term_memory=memory)
+				return ActionResult(extracted_content=msg, long_term_memory=memory, metadata=metadata)
 			except Exception as e:
 				error_msg = str(e)
+				error_type = AgentError.get_error_type(e)
 				# Always log the actual error first for debugging
 				browser_session.logger.error(f'❌ Navigation failed: {error_msg}')
 
 				# Check if it's specifically a RuntimeError about CDP client
 				if isinstance(e, RuntimeError) and 'CDP client not initialized' in error_msg:
 					browser_session.logger.error('❌ Browser connection failed - CDP client not properly initialized')
-					return ActionResult(error=f'Browser connection error: {error_msg}')
+					return ActionResult(
+						error=f'Browser connection error: {error_msg}', error_type=BrowserDisconnectedError.error_type
+					)
+				# The browser shows a security warning instead of the page
+				elif any(err in error_msg for err in ['ERR_CERT_', 'ERR_SSL_', 'ERR_BAD_SSL_CLIENT_AUTH']):
+					cert_error_msg = (
+						f'Navigation failed - the secure connection to {params.url} could not be established ({error_msg}). '
+						f'The site certificate is invalid or untrusted, do not try to work around the browser warning. '
+						f'Use another source for the information instead.'
+					)
+					return ActionResult(error=cert_error_msg, error_type=error_type or NavigationError.error_type)
 				# Check for network-related errors
 				elif any(
 					err in error_msg
@@ -575,13 +1367,13 @@ class Tools(Generic[Context]):
 				):
 					site_unavailable_msg = f'Navigation failed - site unavailable: {params.url}'
 					browser_session.logger.warning(f'⚠️ {site_unavailable_msg} - {error_msg}')
-					return ActionResult(error=site_unavailable_msg)
+					return ActionResult(error=site_unavailable_msg, error_type=error_type or NavigationError.error_type)
 				else:
 					# Return error in ActionResult instead of re-raising
-					return ActionResult(error=f'Navigation failed: {str(e)}')
+					return ActionResult(error=f'Navigation failed: {str(e)}', error_type=error_type)
 
 		@self.registry.action('Go back', param_model=NoParamsAction, terminates_sequence=True)
-		async def go_back(_: NoParamsAction, browser_session: BrowserSession):
+		async def go_back(_: NoParamsAction, browser_session: BrowserBackend):
 			try:
 				event = browser_session.event_bus.dispatch(GoBackEvent())
 				await event
@@ -607,23 +1399,110 @@ class Tools(Generic[Context]):
 			await asyncio.sleep(actual_seconds)
 			return ActionResult(extracted_content=memory, long_term_memory=memory)
 
-		# Helper function for coordinate conversion
-		def _convert_llm_coordinates_to_viewport(llm_x: int, llm_y: int, browser_session: BrowserSession) -> tuple[int, int]:
-			"""Convert coordinates from LLM screenshot size to original viewport size."""
-			if browser_session.llm_screenshot_size and browser_session._original_viewport_size:
-				original_width, original_height = browser_session._original_viewport_size
-				llm_width, llm_height = browser_session.llm_screenshot_size
+		@self.registry.action(
+			'Wait until an element (CSS selector or index) is visible, hidden or attached, instead of waiting a fixed time. '
+			'Returns as soon as the state is reached.',
+			param_model=WaitForElementAction,
+		)
+		async def wait_for_element(params: WaitForElementAction, browser_session: BrowserSession):
+			target = f'"{params.selector}"' if params.selector is not None else f'element {params.index}'
+
+			node_session, object_id = None, None
+			if params.index is not None:
+				node = await browser_session.get_element_by_index(params.index)
+				if node is None:
+					msg = f'Element index {params.index} not available - page may have changed. Try refreshing browser state.'
+					return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+				node_session = await browser_session.cdp_client_for_node(node)
+				try:
+					resolved = await node_session.cdp_client.send.DOM.resolveNode(
+						params={'backendNodeId': node.backend_node_id}, session_id=node_session.session_id
+					)
+					object_id = resolved['object'].get('objectId')
+				except Exception as e:
+					# Removed from the page since the last browser_state, it can only still become "hidden"
+					logger.debug(f'Could not resolve element {params.index} for wait_for_element: {e}')
+
+			loop = asyncio.get_running_loop()
+			start = loop.time()
+			while True:
+				if params.index is not None:
+					result = {}
+					if node_session is not None and object_id is not None:
+						try:
+							result = await node_session.cdp_client.send.Runtime.callFunctionOn(
+								params={
+									'functionDeclaration': f'function() {{ return ({_ELEMENT_STATE_JS})(this); }}',
+									'objectId': object_id,
+									'returnByValue': True,
+								},
+								session_id=node_session.session_id,
+							)
+						except Exception as e:
+							# The node was removed or its document is gone (navigation), it is no longer on the page
+							logger.debug(f'Element {params.index} is no longer on the page: {e}')
+				else:
+					cdp_session = await browser_session.get_or_create_cdp_session()
+					result = await cdp_session.cdp_client.send.Runtime.evaluate(
+						params={
+							'expression': f'({_ELEMENT_STATE_JS})(document.querySelector({json.dumps(params.selector)}))',
+							'returnByValue': True,
+						},
+						session_id=cdp_session.session_id,
+					)
+					if result.get('exceptionDetails'):
+						error_text = result['exceptionDetails'].get('text', 'Unknown JS error')
+						return ActionResult(error=f'wait_for_element failed for {target}: {error_text}')
+
+				state = result.get('result', {}).get('value') or {'attached': False, 'visible': False}
+				elapsed = loop.time() - start
+				if _element_state_reached(state, params.state):
+					memory = f'Waited {elapsed:.1f}s until {target} was {params.state}'
+					logger.info(f'🕒 {memory}')
+					return ActionResult(extracted_content=memory, long_term_memory=memory)
+				if elapsed >= params.timeout:
+					msg = f'Timed out after {params.timeout:g}s waiting for {target} to be {params.state}'
+					return ActionResult(error=msg, error_type='timeout')
+				await asyncio.sleep(_WAIT_POLL_INTERVAL_S)
 
-				# Convert coordinates using fractions
-				actual_x = int((llm_x / llm_width) * original_width)
-				actual_y = int((llm_y / llm_height) * original_height)
+		@self.registry.action(
+			'Wait until the current URL matches a pattern (substring, glob with *, or regex), e.g. after submitting a form.',
+			param_model=WaitForUrlAction,
+		)
+		async def wait_for_url(params: WaitForUrlAction, browser_session: BrowserSession):
+			if params.regex:
+				try:
+					re.compile(params.pattern)
+				except re.error as e:
+					return ActionResult(error=f'Invalid regex pattern "{params.pattern}": {e}')
+
+			loop = asyncio.get_running_loop()
+			start = loop.time()
+			while True:
+				url = await browser_session.get_current_page_url()
+				elapsed = loop.time() - start
+				if _url_matches(url, params.pattern, params.regex):
+					memory = f'Waited {elapsed:.1f}s until the URL matched "{params.pattern}": {url}'
+					logger.info(f'🕒 {memory}')
+					return ActionResult(extracted_content=memory, long_term_memory=memory)
+				if elapsed >= params.timeout:
+					msg = f'Timed out after {params.timeout:g}s waiting for the URL to match "{params.pattern}", it is {url}'
+					return ActionResult(error=msg, error_type='timeout')
+				await asyncio.sleep(_WAIT_POLL_INTERVAL_S)
 
+		# Helper function for coordinate conversion
+		def _convert_llm_coordinates_to_viewport(llm_x: int, llm_y: int, browser_session: BrowserSession) -> tuple[int, int]:
+			"""Convert coordinates on the screenshot the LLM saw (resized, device pixels) to CSS pixels of the viewport."""
+			actual_x, actual_y = browser_session.llm_coordinates_to_css(llm_x, llm_y)
+			if (actual_x, actual_y) != (llm_x, llm_y):
+				screenshot_width, screenshot_height = browser_session.llm_screenshot_dimensions() or (0, 0)
+				viewport_width, viewport_height = browser_session._original_viewport_size or (0, 0)
 				logger.info(
-					f'🔄 Converting coordinates: LLM ({llm_x}, {llm_y}) @ {llm_width}x{llm_height} '
-					f'→ Viewport ({actual_x}, {actual_y}) @ {original_width}x{original_height}'
+					f'🔄 Converting coordinates: LLM ({llm_x}, {llm_y}) @ {screenshot_width}x{screenshot_height} '
+					f'→ Viewport ({actual_x}, {actual_y}) @ {viewport_width}x{viewport_height} '
+					f'(DPR {browser_session._device_pixel_ratio:g})'
 				)
-				return actual_x, actual_y
-			return llm_x, llm_y
+			return actual_x, actual_y
 
 		# Element Interaction Actions
 		async def _detect_new_tab_opened(
@@ -653,7 +1532,9 @@ class Tools(Generic[Context]):
 				pass
 			return ''
 
-		async def _click_by_coordinate(params: ClickElementAction, browser_session: BrowserSession) -> ActionResult:
+		async def _click_by_coordinate(
+			params: ClickElementAction | ClickCoordinateAction, browser_session: BrowserSession
+		) -> ActionResult:
 			# Ensure coordinates are provided (type safety)
 			if params.coordinate_x is None or params.coordinate_y is None:
 				return ActionResult(error='Both coordinate_x and coordinate_y must be provided')
@@ -689,7 +1570,13 @@ class Tools(Generic[Context]):
 
 				return ActionResult(
 					extracted_content=memory,
-					metadata={'click_x': actual_x, 'click_y': actual_y},
+					metadata={
+						'click_x': actual_x,
+						'click_y': actual_y,
+						'screenshot_x': params.coordinate_x,
+						'screenshot_y': params.coordinate_y,
+						'device_pixel_ratio': browser_session._device_pixel_ratio,
+					},
 				)
 			except BrowserError as e:
 				return handle_browser_error(e)
@@ -746,6 +1633,8 @@ class Tools(Generic[Context]):
 
 				# Build memory with element info
 				memory = f'Clicked {element_desc}'
+				if isinstance(click_metadata, dict) and click_metadata.get('click_note'):
+					memory += f'. {click_metadata["click_note"]}'
 				memory += await _detect_new_tab_opened(browser_session, tabs_before)
 				logger.info(f'🖱️ {memory}')
 
@@ -763,6 +1652,7 @@ class Tools(Generic[Context]):
 		# Store click handlers for re-registration
 		self._click_by_index = _click_by_index
 		self._click_by_coordinate = _click_by_coordinate
+		self._convert_llm_coordinates_to_viewport = _convert_llm_coordinates_to_viewport
 
 		# Register click action (index-only by default)
 		self._register_click_action()
@@ -860,66 +1750,28 @@ class Tools(Generic[Context]):
 		async def upload_file(
 			params: UploadFileAction, browser_session: BrowserSession, available_file_paths: list[str], file_system: FileSystem
 		):
-			# Check if file is in available_file_paths (user-provided or downloaded files)
-			# For remote browsers (is_local=False), we allow absolute remote paths even if not tracked locally
-			if params.path not in available_file_paths:
-				# Also check if it's a recently downloaded file that might not be in available_file_paths yet
-				downloaded_files = browser_session.downloaded_files
-				if params.path not in downloaded_files:
-					# Finally, check if it's a file in the FileSystem service.
-					# Only rewrite to the local FileSystem path on local sessions —
-					# on remote sessions, params.path is meant to address a file on
-					# the remote machine, and a coincidental basename collision with
-					# a local managed file (e.g. `/tmp/note.md` colliding with a
-					# local `note.md`) must not silently upload the local file.
-					if browser_session.is_local and file_system and file_system.get_dir():
-						# Check if the file is actually managed by the FileSystem service
-						# The path should be just the filename for FileSystem files
-						file_obj = file_system.get_file(params.path)
-						if file_obj:
-							# Construct the upload path from the FileSystem-owned basename
-							# (file_obj.full_name), NOT from params.path. The agent-controlled
-							# params.path may contain '..' traversal sequences that escape
-							# data_dir when naively joined — get_file() matches by basename
-							# so a path like '../../../note.md' would otherwise resolve to a
-							# sibling file outside the FileSystem directory.
-							# GHSA-j9hj-92j8-jv9h.
-							file_system_path = str(file_system.get_dir() / file_obj.full_name)
-							# Defense in depth: refuse any path that resolves outside data_dir.
-							real_path = os.path.realpath(file_system_path)
-							real_dir = os.path.realpath(str(file_system.get_dir()))
-							if not (real_path == real_dir or real_path.startswith(real_dir + os.sep)):
-								msg = f'Upload of {params.path!r} escapes FileSystem directory; refusing.'
-								logger.error(f'❌ {msg}')
-								return ActionResult(error=msg)
-							params = UploadFileAction(index=params.index, path=file_system_path)
-						else:
-							msg = f'File path {params.path} is not available. To fix: The user must add this file path to the available_file_paths parameter when creating the Agent. Example: Agent(task="...", llm=llm, browser=browser, available_file_paths=["{params.path}"])'
-							logger.error(f'❌ {msg}')
-							return ActionResult(error=msg)
-					else:
-						# If browser is remote, allow passing a remote-accessible absolute path
-						if not browser_session.is_local:
-							pass
-						else:
-							msg = f'File path {params.path} is not available. To fix: The user must add this file path to the available_file_paths parameter when creating the Agent. Example: Agent(task="...", llm=llm, browser=browser, available_file_paths=["{params.path}"])'
-							raise BrowserError(message=msg, long_term_memory=msg)
-
-			# For local browsers, ensure the file exists and has content
+			upload_paths: list[str] = []
+			for path in [params.path] if isinstance(params.path, str) else params.path:
+				upload_path, error = self._resolve_upload_path(path, browser_session, available_file_paths, file_system)
+				if error is not None:
+					logger.error(f'❌ {error}')
+					return ActionResult(error=error, long_term_memory=error)
+				assert upload_path is not None
+				upload_paths.append(upload_path)
+			if not upload_paths:
+				return ActionResult(error='No file to upload, pass a path')
+
+			# For local browsers, expand directories and ensure the files exist and have content
 			if browser_session.is_local:
-				if not os.path.exists(params.path):
-					msg = f'File {params.path} does not exist'
-					return ActionResult(error=msg)
-				file_size = os.path.getsize(params.path)
-				if file_size == 0:
-					msg = f'File {params.path} is empty (0 bytes). The file may not have been saved correctly.'
-					return ActionResult(error=msg)
+				upload_paths, error = _expand_upload_paths(upload_paths)
+				if error is not None:
+					return ActionResult(error=error, long_term_memory=error)
 
 			# Get the selector map to find the node
 			selector_map = await browser_session.get_selector_map()
 			if params.index not in selector_map:
 				msg = f'Element with index {params.index} does not exist.'
-				return ActionResult(error=msg)
+				return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
 
 			node = selector_map[params.index]
 
@@ -982,19 +1834,122 @@ class Tools(Generic[Context]):
 
 			# Dispatch upload file event with the file input node
 			try:
-				event = browser_session.event_bus.dispatch(UploadFileEvent(node=file_input_node, file_path=params.path))
+				event = browser_session.event_bus.dispatch(
+					UploadFileEvent(node=file_input_node, file_path=upload_paths[0], file_paths=upload_paths)
+				)
 				await event
 				await event.event_result(raise_if_any=True, raise_if_none=False)
-				msg = f'Successfully uploaded file to index {params.index}'
+				if len(upload_paths) == 1:
+					msg = f'Successfully uploaded file to index {params.index}'
+					memory = f'Uploaded file {upload_paths[0]} to element {params.index}'
+				else:
+					msg = f'Successfully uploaded {len(upload_paths)} files to index {params.index}'
+					names = ', '.join(os.path.basename(path) for path in upload_paths[:10])
+					more = f' and {len(upload_paths) - 10} more' if len(upload_paths) > 10 else ''
+					memory = f'Uploaded {len(upload_paths)} files ({names}{more}) to element {params.index}'
 				logger.info(f'📁 {msg}')
-				return ActionResult(
-					extracted_content=msg,
-					long_term_memory=f'Uploaded file {params.path} to element {params.index}',
-				)
+				return ActionResult(extracted_content=msg, long_term_memory=memory)
+			except BrowserError as e:
+				return handle_browser_error(e)
 			except Exception as e:
 				logger.error(f'Failed to upload file: {e}')
 				raise BrowserError(f'Failed to upload file: {e}')
 
+		@self.registry.action(
+			'Upload a file to a drag-and-drop zone at index (Dropzone-style widgets). Use when the page shows no file '
+			'input for upload_file. Sets the zone\'s hidden file input if it has one, otherwise drops the file on it.',
+			param_model=UploadDropzoneAction,
+		)
+		async def upload_dropzone(
+			params: UploadDropzoneAction,
+			browser_session: BrowserSession,
+			available_file_paths: list[str],
+			file_system: FileSystem,
+		):
+			upload_path, error = self._resolve_upload_path(params.path, browser_session, available_file_paths, file_system)
+			if error is not None:
+				logger.error(f'❌ {error}')
+				return ActionResult(error=error, long_term_memory=error)
+			assert upload_path is not None
+
+			if browser_session.is_local:
+				if not os.path.exists(upload_path):
+					return ActionResult(error=f'File {upload_path} does not exist')
+				if os.path.getsize(upload_path) == 0:
+					msg = f'File {upload_path} is empty (0 bytes). The file may not have been saved correctly.'
+					return ActionResult(error=msg)
+
+			node = await browser_session.get_element_by_index(params.index)
+			if node is None:
+				msg = f'Element with index {params.index} does not exist.'
+				return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+
+			try:
+				cdp_session = await browser_session.cdp_client_for_node(node)
+				send = cdp_session.cdp_client.send
+				resolved = await send.DOM.resolveNode(
+					params={'backendNodeId': node.backend_node_id}, session_id=cdp_session.session_id
+				)
+				dropzone_object_id = resolved['object']['objectId']
+
+				if params.mode != 'drop':
+					found = await send.Runtime.callFunctionOn(
+						params={'functionDeclaration': _FIND_DROPZONE_INPUT_JS, 'objectId': dropzone_object_id},
+						session_id=cdp_session.session_id,
+					)
+					if file_input_object_id := found.get('result', {}).get('objectId'):
+						# The widget's own input fires the change event its upload logic listens to
+						await send.DOM.setFileInputFiles(
+							params={'files': [upload_path], 'objectId': file_input_object_id}, session_id=cdp_session.session_id
+						)
+						msg = f'Uploaded file {params.path} through the file input of drop zone {params.index}'
+						logger.info(f'📁 {msg}')
+						return ActionResult(extracted_content=msg, long_term_memory=msg)
+					if params.mode == 'input':
+						msg = f'Drop zone {params.index} has no file input, use mode="drop" to drop the file on it instead.'
+						return ActionResult(error=msg)
+
+				if browser_session.is_local and os.path.getsize(upload_path) <= _MAX_INLINE_DROP_BYTES:
+					# Send the file content along with the drop, the page builds the File from base64
+					payload = {
+						'name': os.path.basename(upload_path),
+						'type': mimetypes.guess_type(upload_path)[0] or '',
+						'data': base64.b64encode(await anyio.Path(upload_path).read_bytes()).decode(),
+					}
+					drop_arguments = [{'value': None}, {'value': [payload]}]
+				else:
+					# The path is on the browser's machine: let the browser read it into a helper input
+					input_result = await send.Runtime.callFunctionOn(
+						params={'functionDeclaration': _CREATE_DROPZONE_INPUT_JS, 'objectId': dropzone_object_id},
+						session_id=cdp_session.session_id,
+					)
+					input_object_id = input_result['result']['objectId']
+					await send.DOM.setFileInputFiles(
+						params={'files': [upload_path], 'objectId': input_object_id}, session_id=cdp_session.session_id
+					)
+					drop_arguments = [{'objectId': input_object_id}, {'value': []}]
+
+				drop_result = await send.Runtime.callFunctionOn(
+					params={
+						'functionDeclaration': _DROP_FILES_JS,
+						'objectId': dropzone_object_id,
+						'arguments': drop_arguments,
+						'returnByValue': True,
+					},
+					session_id=cdp_session.session_id,
+				)
+			except Exception as e:
+				logger.error(f'Failed to drop file on element {params.index}: {type(e).__name__}: {e}')
+				raise BrowserError(f'Failed to drop file on element {params.index}: {e}')
+
+			if drop_result.get('exceptionDetails') or not drop_result.get('result', {}).get('value'):
+				msg = f'Could not drop {params.path} on element {params.index}: the file could not be attached.'
+				return ActionResult(error=msg)
+
+			msg = f'Dropped file {params.path} on element {params.index}'
+			logger.info(f'📁 {msg}')
+			return ActionResult(extracted_content=msg, long_term_memory=msg)
+
 		# Tab Management Actions
 
 		@self.registry.action(
@@ -1002,10 +1957,13 @@ class Tools(Generic[Context]):
 			param_model=SwitchTabAction,
 			terminates_sequence=True,
 		)
-		async def switch(params: SwitchTabAction, browser_session: BrowserSession):
+		async def switch(params: SwitchTabAction, browser_session: BrowserBackend):
 			# Simple switch tab logic
 			try:
 				target_id = await browser_session.get_target_id_from_tab_id(params.tab_id)
+				pinned_target_id = browser_session.pinned_target_id
+				if pinned_target_id and target_id != pinned_target_id:
+					return ActionResult(error=f'Cannot switch tabs: this session is restricted to tab #{pinned_target_id[-4:]}')
 
 				event = browser_session.event_bus.dispatch(SwitchTabEvent(target_id=target_id))
 				await event
@@ -1023,11 +1981,33 @@ class Tools(Generic[Context]):
 				memory = f'Attempted to switch to tab #{params.tab_id}'
 				return ActionResult(extracted_content=memory, long_term_memory=memory)
 
+		@self.registry.action(
+			'List all open tabs with tab_id, title and URL. Set include_thumbnails=True to also see a small screenshot of each tab, e.g. to compare results across tabs before switching.',
+			param_model=ListTabsAction,
+		)
+		async def list_tabs(params: ListTabsAction, browser_session: BrowserBackend):
+			tabs = await browser_session.get_tabs(include_thumbnails=params.include_thumbnails)
+			current_target_id = browser_session.agent_focus_target_id
+			lines = []
+			for tab in tabs:
+				marker = ' (current)' if tab.target_id == current_target_id else ''
+				lines.append(f'Tab {tab.target_id[-4:]}{marker}: {tab.title or "(no title)"} - {tab.url}')
+			images = [{'name': f'tab_{tab.target_id[-4:]}.jpg', 'data': tab.thumbnail} for tab in tabs if tab.thumbnail]
+
+			memory = f'Listed {len(tabs)} open tab(s)' + (f' with {len(images)} thumbnail(s)' if images else '')
+			logger.info(f'🗂️  {memory}')
+			return ActionResult(
+				extracted_content='\n'.join(lines),
+				long_term_memory=memory,
+				include_extracted_content_only_once=True,
+				images=images or None,
+			)
+
 		@self.registry.action(
 			'Close a tab by tab_id. Tab IDs are shown in browser state tabs list (last 4 chars of target_id). Use to clean up tabs you no longer need.',
 			param_model=CloseTabAction,
 		)
-		async def close(params: CloseTabAction, browser_session: BrowserSession):
+		async def close(params: CloseTabAction, browser_session: BrowserBackend):
 			# Simple close tab logic
 			try:
 				target_id = await browser_session.get_target_id_from_tab_id(params.tab_id)
@@ -1052,6 +2032,36 @@ class Tools(Generic[Context]):
 					long_term_memory=memory,
 				)
 
+		# Window Management Actions
+
+		@self.registry.action(
+			'Resize the page viewport in CSS px, e.g. widen it to 1920 when a responsive layout hides navigation or content '
+			'behind a hamburger menu. Stays in effect for all tabs.',
+			param_model=SetViewportAction,
+		)
+		async def set_viewport(params: SetViewportAction, browser_session: BrowserSession):
+			try:
+				await browser_session.set_viewport(params.width, params.height)
+			except Exception as e:
+				return ActionResult(error=f'Failed to set viewport: {type(e).__name__}: {e}')
+			memory = f'Set viewport to {params.width}x{params.height}'
+			logger.info(f'🖥️  {memory}')
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+		@self.registry.action(
+			'Resize, maximize or fullscreen the browser window. The page then uses the window size instead of an emulated '
+			'viewport.',
+			param_model=SetWindowAction,
+		)
+		async def set_window(params: SetWindowAction, browser_session: BrowserSession):
+			try:
+				bounds = await browser_session.set_window_bounds(width=params.width, height=params.height, state=params.state)
+			except Exception as e:
+				return ActionResult(error=f'Failed to set window: {type(e).__name__}: {e}')
+			memory = f'Window is {bounds.get("windowState", params.state)}, {bounds.get("width")}x{bounds.get("height")} px'
+			logger.info(f'🖥️  {memory}')
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
 		@self.registry.action(
 			"""LLM extracts structured data from page markdown. Use when: on right page, know what to extract, haven't called before on same page+query. Can't get interactive elements. Set extract_links=True for URLs. Set extract_images=True for image src URLs. Use start_from_char if previous extraction was truncated to extract data further down the page. When paginating across pages, pass already_collected with item identifiers (names/URLs) from prior pages to avoid duplicates.""",
 			param_model=ExtractAction,
@@ -1062,13 +2072,13 @@ class Tools(Generic[Context]):
 			page_extraction_llm: BaseChatModel,
 			file_system: FileSystem,
 			extraction_schema: dict | None = None,
+			redact_text: Callable[[str], str] | None = None,
 		):
-			# Constants
-			MAX_CHAR_LIMIT = 100000
 			query = params['query'] if isinstance(params, dict) else params.query
 			extract_links = params['extract_links'] if isinstance(params, dict) else params.extract_links
 			extract_images = params.get('extract_images', False) if isinstance(params, dict) else params.extract_images
 			start_from_char = params['start_from_char'] if isinstance(params, dict) else params.start_from_char
+			main_content_only = params.get('main_content_only', False) if isinstance(params, dict) else params.main_content_only
 			output_schema: dict | None = params.get('output_schema') if isinstance(params, dict) else params.output_schema
 			already_collected: list[str] = (
 				params.get('already_collected', []) if isinstance(params, dict) else params.already_collected
@@ -1099,7 +2109,10 @@ class Tools(Generic[Context]):
 				from browser_use.dom.markdown_extractor import extract_clean_markdown
 
 				content, content_stats = await extract_clean_markdown(
-					browser_session=browser_session, extract_links=extract_links, extract_images=extract_images
+					browser_session=browser_session,
+					extract_links=extract_links,
+					extract_images=extract_images,
+					main_content_only=main_content_only,
 				)
 			except Exception as e:
 				raise RuntimeError(f'Could not extract clean markdown: {type(e).__name__}')
@@ -1110,7 +2123,7 @@ class Tools(Generic[Context]):
 			# Structure-aware chunking replaces naive char-based truncation
 			from browser_use.dom.markdown_extractor import chunk_markdown_by_structure
 
-			chunks = chunk_markdown_by_structure(content, max_chunk_chars=MAX_CHAR_LIMIT, start_from_char=start_from_char)
+			chunks = chunk_markdown_by_structure(content, max_chunk_chars=self.extract_max_chars, start_from_char=start_from_char)
 			if not chunks:
 				return ActionResult(
 					error=f'start_from_char ({start_from_char}) exceeds content length {final_filtered_length} characters.'
@@ -1144,29 +2157,22 @@ class Tools(Generic[Context]):
 				stats_summary += f' → {len(content):,} final chars ({chunk_info}use start_from_char={content_stats["next_start_char"]} to continue)'
 			elif chars_filtered > 0:
 				stats_summary += f' (filtered {chars_filtered:,} chars of noise)'
+			if main_content_only:
+				stats_summary += f' (main content only: <{content_stats["main_content"]}>)'
 
 			# Sanitize surrogates from content to prevent UTF-8 encoding errors
-			content = sanitize_surrogates(content)
-			query = sanitize_surrogates(query)
+			content = sanitize_text(content)
+			query = sanitize_text(query)
+			if redact_text is not None:
+				# The page goes to page_extraction_llm, mask it like the agent's own state (Agent(redaction=...))
+				content = redact_text(content)
 
 			# --- Structured extraction path ---
 			if structured_model is not None:
 				assert output_schema is not None
-				system_prompt = """
-You are an expert at extracting structured data from the markdown of a webpage.
-
-<input>
-You will be given a query, a JSON Schema, and the markdown of a webpage that has been filtered to remove noise and advertising content.
-</input>
+				from browser_use.tools.extraction.typed import STRUCTURED_EXTRACTION_SYSTEM_PROMPT
 
-<instructions>
-- Extract ONLY information present in the webpage. Do not guess or fabricate values.
-- Your response MUST conform to the provided JSON Schema exactly.
-- If a required field's value cannot be found on the page, use null (if the schema allows it) or an empty string / empty array as appropriate.
-- If the content was truncated, extract what is available from the visible portion.
-- If <already_collected> items are provided, skip any items whose name/title/URL matches those listed — do not include duplicates.
-</instructions>
-""".strip()
+				system_prompt = STRUCTURED_EXTRACTION_SYSTEM_PROMPT
 
 				schema_json = json.dumps(output_schema, indent=2)
 				already_collected_section = ''
@@ -1212,8 +2218,11 @@ You will be given a query, a JSON Schema, and the markdown of a webpage that has
 						memory = extracted_content
 						include_extracted_content_only_once = False
 					else:
-						file_name = await file_system.save_extracted_content(extracted_content)
-						memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+						try:
+							file_name = await file_system.save_extracted_content(extracted_content)
+							memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+						except FileSystemError as e:
+							memory = f'Query: {query}\nContent shown once in <read_state>, not saved to a file: {e}'
 						include_extracted_content_only_once = True
 
 					logger.info(f'📄 {memory}')
@@ -1276,8 +2285,11 @@ You will be given a query and the markdown of a webpage that has been filtered t
 					memory = extracted_content
 					include_extracted_content_only_once = False
 				else:
-					file_name = await file_system.save_extracted_content(extracted_content)
-					memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+					try:
+						file_name = await file_system.save_extracted_content(extracted_content)
+						memory = f'Query: {query}\nContent in {file_name} and once in <read_state>.'
+					except FileSystemError as e:
+						memory = f'Query: {query}\nContent shown once in <read_state>, not saved to a file: {e}'
 					include_extracted_content_only_once = True
 
 				logger.info(f'📄 {memory}')
@@ -1290,26 +2302,222 @@ You will be given a query and the markdown of a webpage that has been filtered t
 				logger.debug(f'Error extracting content: {e}')
 				raise RuntimeError(str(e))
 
-		# --- Page search and exploration tools (zero LLM cost) ---
-
 		@self.registry.action(
-			"""Search page text for a pattern (like grep). Zero LLM cost, instant. Returns matches with surrounding context. Use to find specific text, verify content exists, or locate data on the page. Set regex=True for regex patterns. Use css_scope to search within a specific section.""",
-			param_model=SearchPageAction,
+			"""Open several links in background tabs in parallel, run the same extraction query on each page and return aggregated results. Use instead of many navigate+extract steps when processing a list of items (e.g. search results, product links). Does not change the current tab.""",
+			param_model=ProcessLinksAction,
 		)
-		async def search_page(params: SearchPageAction, browser_session: BrowserSession):
-			js_code = _build_search_page_js(
-				pattern=params.pattern,
-				regex=params.regex,
-				case_sensitive=params.case_sensitive,
-				context_chars=params.context_chars,
-				css_scope=params.css_scope,
-				max_results=params.max_results,
-			)
+		async def process_links(
+			params: ProcessLinksAction,
+			browser_session: BrowserSession,
+			page_extraction_llm: BaseChatModel,
+			file_system: FileSystem,
+			redact_text: Callable[[str], str] | None = None,
+		):
+			from urllib.parse import urljoin
 
-			cdp_session = await browser_session.get_or_create_cdp_session()
-			result = await cdp_session.cdp_client.send.Runtime.evaluate(
-				params={'expression': js_code, 'returnByValue': True, 'awaitPromise': True},
-				session_id=cdp_session.session_id,
+			from browser_use.dom.markdown_extractor import chunk_markdown_by_structure
+
+			MAX_CHARS_PER_PAGE = 30000
+			query = sanitize_text(params.query)
+
+			# Resolve relative hrefs against the current page and drop duplicates, keeping order
+			current_url = await browser_session.get_current_page_url()
+			urls: list[str] = []
+			for href in params.urls:
+				url = urljoin(current_url, href.strip())
+				if url not in urls:
+					urls.append(url)
+
+			system_prompt = """
+You are an expert at extracting data from the markdown of a webpage.
+
+<instructions>
+- Extract the information relevant to the query from the webpage.
+- ONLY use information available in the webpage. Do not make up information.
+- If the information is not available on the page, say so in one short sentence.
+- Directly output the relevant information in a concise way, not in conversational format.
+</instructions>
+""".strip()
+
+			semaphore = asyncio.Semaphore(params.max_concurrency)
+
+			async def _process(url: str) -> tuple[str, str | None, str | None]:
+				"""Returns (url, result, error)"""
+				if not url.startswith(('http://', 'https://')):
+					return url, None, 'unsupported URL scheme'
+				async with semaphore:
+					try:
+						content, _ = await browser_session.get_markdown_in_background_tab(url, extract_links=params.extract_links)
+						chunks = chunk_markdown_by_structure(content, max_chunk_chars=MAX_CHARS_PER_PAGE)
+						if not chunks:
+							return url, None, 'page has no content'
+						page_content = sanitize_text(chunks[0].content)
+						if redact_text is not None:
+							page_content = redact_text(page_content)
+						if chunks[0].has_more:
+							page_content += f'\n... [Page truncated after {MAX_CHARS_PER_PAGE} characters]'
+						prompt = f'<query>\n{query}\n</query>\n\n<webpage_content>\n{page_content}\n</webpage_content>'
+						response = await asyncio.wait_for(
+							page_extraction_llm.ainvoke([SystemMessage(content=system_prompt), UserMessage(content=prompt)]),
+							timeout=120.0,
+						)
+						return url, str(response.completion).strip(), None
+					except Exception as e:
+						logger.debug(f'process_links failed for {url}: {type(e).__name__}: {e}')
+						return url, None, f'{type(e).__name__}: {e}'
+
+			results = await asyncio.gather(*(_process(url) for url in urls))
+
+			succeeded = [r for r in results if r[2] is None]
+			if not succeeded:
+				failures = '; '.join(f'{url}: {error}' for url, _, error in results)
+				return ActionResult(error=f'Could not process any of the {len(urls)} links: {failures}')
+
+			sections = []
+			for url, result, error in results:
+				if error is None:
+					sections.append(f'<page url="{url}">\n{result}\n</page>')
+				else:
+					sections.append(f'<page url="{url}" error="{error}" />')
+			summary = f'Processed {len(succeeded)}/{len(urls)} links'
+			extracted_content = f'<query>\n{query}\n</query>\n<results>\n' + '\n'.join(sections) + '\n</results>'
+
+			MAX_MEMORY_LENGTH = 10000
+			if len(extracted_content) < MAX_MEMORY_LENGTH:
+				memory = f'{summary}\n{extracted_content}'
+				include_extracted_content_only_once = False
+			else:
+				try:
+					file_name = await file_system.save_extracted_content(extracted_content)
+					memory = f'{summary} for query: {query}\nContent in {file_name} and once in <read_state>.'
+				except FileSystemError as e:
+					memory = f'{summary} for query: {query}\nContent shown once in <read_state>, not saved to a file: {e}'
+				include_extracted_content_only_once = True
+
+			logger.info(f'📑 {summary} for query: {query}')
+			return ActionResult(
+				extracted_content=extracted_content,
+				include_extracted_content_only_once=include_extracted_content_only_once,
+				long_term_memory=memory,
+				metadata={'processed_links': len(succeeded), 'failed_links': len(urls) - len(succeeded)},
+			)
+
+		@self.registry.action(
+			'Extract items from paginated results in one action: extracts the fields from the current page, clicks the next '
+			'page link (next_selector or next_text) and repeats until max_pages or there is no next page. The merged, '
+			'deduplicated items are saved as a JSON array to file_name. Use instead of many extract + click steps.',
+			param_model=PaginateExtractAction,
+		)
+		async def paginate_extract(
+			params: PaginateExtractAction,
+			browser_session: BrowserSession,
+			page_extraction_llm: BaseChatModel,
+			file_system: FileSystem,
+			redact_text: Callable[[str], str] | None = None,
+		):
+			from browser_use.dom.markdown_extractor import chunk_markdown_by_structure, extract_clean_markdown
+			from browser_use.tools.extraction.schema_utils import schema_dict_to_pydantic_model
+
+			item_schema = {'type': 'object', 'properties': {f: {'type': 'string', 'nullable': True} for f in params.fields}}
+			page_model = schema_dict_to_pydantic_model(
+				{'type': 'object', 'properties': {'items': {'type': 'array', 'items': item_schema}}, 'required': ['items']}
+			)
+			query = sanitize_text(params.query)
+			system_prompt = """
+You are an expert at extracting structured data from the markdown of a webpage.
+
+<instructions>
+- The page is one page of a paginated list. Extract every item on it that matches the query.
+- Extract ONLY information present in the webpage. Do not guess or fabricate values.
+- Use null for fields an item does not have. Return an empty items list if the page has no matching items.
+</instructions>
+""".strip()
+
+			items: list[dict] = []
+			seen: set[str] = set()
+			pages = 0
+			stop_reason = f'reached max_pages={params.max_pages}'
+			while True:
+				try:
+					content, _ = await extract_clean_markdown(browser_session=browser_session, extract_links=params.extract_links)
+					# A long page is split into chunks, every chunk is extracted so no items past the first one are lost
+					page_items: list[dict] = []
+					for chunk in chunk_markdown_by_structure(content, max_chunk_chars=self.extract_max_chars):
+						chunk_content = chunk.content
+						if chunk.overlap_prefix:
+							chunk_content = chunk.overlap_prefix + '\n' + chunk_content
+						page_content = sanitize_text(chunk_content)
+						if redact_text is not None:
+							page_content = redact_text(page_content)
+						prompt = f'<query>\n{query}\n</query>\n\n<webpage_content>\n{page_content}\n</webpage_content>'
+						response = await asyncio.wait_for(
+							page_extraction_llm.ainvoke(
+								[SystemMessage(content=system_prompt), UserMessage(content=prompt)], output_format=page_model
+							),
+							timeout=120.0,
+						)
+						page_items.extend(response.completion.model_dump(mode='json')['items'])  # type: ignore[union-attr]
+				except Exception as e:
+					if pages == 0:
+						raise RuntimeError(f'Could not extract the first page: {type(e).__name__}: {e}') from e
+					stop_reason = f'extraction failed on page {pages + 1}: {type(e).__name__}: {e}'
+					break
+
+				pages += 1
+				new_items = 0
+				for item in page_items:
+					key = json.dumps(item, sort_keys=True)
+					if key not in seen:
+						seen.add(key)
+						items.append(item)
+						new_items += 1
+				logger.info(f'📑 Page {pages}: {len(page_items)} items, {new_items} new')
+
+				if pages > 1 and new_items == 0:
+					stop_reason = f'page {pages} had no new items'
+					break
+				if pages >= params.max_pages:
+					break
+				if end_reason := await _go_to_next_page(browser_session, params.next_selector, params.next_text):
+					stop_reason = end_reason
+					break
+
+			items_json = json.dumps(items, indent=2, ensure_ascii=False)
+			write_result = await file_system.write_file(params.file_name, items_json)
+			summary = f'Extracted {len(items)} items from {pages} page{"s" if pages != 1 else ""}, stopped: {stop_reason}'
+			logger.info(f'📑 {summary}')
+
+			extracted_content = f'{summary}\n{write_result}'
+			MAX_INLINE_LENGTH = 10000
+			if len(items_json) < MAX_INLINE_LENGTH:
+				extracted_content += f'\n<items>\n{items_json}\n</items>'
+			return ActionResult(
+				extracted_content=extracted_content,
+				include_extracted_content_only_once=True,
+				long_term_memory=f'{summary}. {write_result}',
+				metadata={'pages': pages, 'items': len(items), 'stop_reason': stop_reason},
+			)
+
+		# --- Page search and exploration tools (zero LLM cost) ---
+
+		@self.registry.action(
+			"""Search page text for a pattern (like grep). Zero LLM cost, instant. Returns matches with surrounding context. Use to find specific text, verify content exists, or locate data on the page. Set regex=True for regex patterns. Use css_scope to search within a specific section.""",
+			param_model=SearchPageAction,
+		)
+		async def search_page(params: SearchPageAction, browser_session: BrowserSession):
+			js_code = _build_search_page_js(
+				pattern=params.pattern,
+				regex=params.regex,
+				case_sensitive=params.case_sensitive,
+				context_chars=params.context_chars,
+				css_scope=params.css_scope,
+				max_results=params.max_results,
+			)
+
+			cdp_session = await browser_session.get_or_create_cdp_session()
+			result = await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={'expression': js_code, 'returnByValue': True, 'awaitPromise': True},
+				session_id=cdp_session.session_id,
 			)
 
 			if result.get('exceptionDetails'):
@@ -1364,6 +2572,91 @@ You will be given a query and the markdown of a webpage that has been filtered t
 			logger.info(f'🔍 {memory}')
 			return ActionResult(extracted_content=formatted, long_term_memory=memory)
 
+		async def assert_(params: AssertAction, browser_session: BrowserSession):
+			actual: str | None
+			if params.check == 'url_matches':
+				assert params.value is not None
+				actual = await browser_session.get_current_page_url()
+				try:
+					passed = re.search(params.value, actual) is not None
+				except re.error:
+					passed = params.value in actual
+			else:
+				cdp_session = await browser_session.get_or_create_cdp_session()
+				result = await cdp_session.cdp_client.send.Runtime.evaluate(
+					params={'expression': f'({_ASSERT_JS})({json.dumps(params.model_dump())})', 'returnByValue': True},
+					session_id=cdp_session.session_id,
+				)
+				if result.get('exceptionDetails'):
+					error_text = result['exceptionDetails'].get('text', 'Unknown JS error')
+					return ActionResult(error=f'assert failed: {error_text}')
+				data = result.get('result', {}).get('value') or {}
+				if data.get('error'):
+					return ActionResult(error=f'assert: {data["error"]}')
+
+				if params.check in ('text_present', 'text_absent'):
+					passed = data['found'] == (params.check == 'text_present')
+					actual = 'found on page' if data['found'] else 'not found on page'
+				elif params.check == 'element_exists':
+					passed = data['count'] > 0
+					actual = f'{data["count"]} matching elements'
+				elif data['count'] == 0:
+					passed, actual = False, 'no element matches the selector'
+				else:
+					actual = data.get('actual')
+					passed = actual is not None and actual.strip() == (params.value or '').strip()
+
+			label = params.description or _describe_assertion(params)
+			if passed:
+				message = f'✅ Assertion passed: {label}'
+			else:
+				message = f'❌ Assertion failed: {label} (actual: {actual})'
+			logger.info(message)
+			assertion = {
+				'check': params.check,
+				'passed': passed,
+				'description': label,
+				'expected': params.value,
+				'actual': actual,
+				'selector': params.selector,
+				'attribute': params.attribute,
+			}
+			return ActionResult(extracted_content=message, long_term_memory=message, metadata={'assertion': assertion})
+
+		# `assert` is a Python keyword, so the function is renamed before it is registered
+		assert_.__name__ = 'assert'
+		self.registry.action(
+			'Verify the page state for testing: text present/absent, element exists, URL matches (regex or substring) or '
+			'attribute equals. Records a pass/fail result, a failed assertion is not an error. Use it to validate, then '
+			'continue or finish with done.',
+			param_model=AssertAction,
+		)(assert_)
+
+		@self.registry.action(
+			'Read the XHR/fetch requests the page made and their response bodies (usually JSON). Zero LLM cost. '
+			'Use to get data straight from the APIs behind a page instead of scraping the rendered HTML, e.g. for lists, '
+			'prices or search results. The first call starts recording: reload or repeat the interaction, then call again.',
+			param_model=GetNetworkRequestsAction,
+		)
+		async def get_network_requests(params: GetNetworkRequestsAction, browser_session: BrowserSession):
+			if not browser_session.is_network_capture_enabled:
+				await browser_session.enable_network_capture()
+				memory = 'Started recording network requests, none were captured before this.'
+				msg = f'{memory} Reload the page or repeat the interaction that loads the data, then call this action again.'
+				logger.info(f'📡 {memory}')
+				return ActionResult(extracted_content=msg, long_term_memory=memory)
+
+			requests = browser_session.get_network_requests(url_pattern=params.url_pattern, limit=params.limit)
+			if not requests:
+				pattern_note = f' matching "{params.url_pattern}"' if params.url_pattern else ''
+				memory = f'No network requests{pattern_note} captured yet.'
+				return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+			formatted = _format_network_requests(requests, params.max_body_chars)
+			memory = f'Read {len(requests)} captured network request{"s" if len(requests) != 1 else ""}.'
+			logger.info(f'📡 {memory}')
+			return ActionResult(extracted_content=formatted, long_term_memory=memory, include_extracted_content_only_once=True)
+
 		@self.registry.action(
 			"""Scroll by pages. REQUIRED: down=True/False (True=scroll down, False=scroll up, default=True). Optional: pages=0.5-10.0 (default 1.0). Use index for scroll elements (dropdowns/custom UI). High pages (10) reaches bottom. Multi-page scrolls sequentially. Viewport-based height, fallback 1000px/page.""",
 			param_model=ScrollAction,
@@ -1378,7 +2671,7 @@ You will be given a query and the markdown of a webpage that has been filtered t
 					if node is None:
 						# Element does not exist
 						msg = f'Element index {params.index} not found in browser state'
-						return ActionResult(error=msg)
+						return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
 
 				direction = 'down' if params.down else 'up'
 				target = f'element {params.index}' if params.index is not None and params.index != 0 else ''
@@ -1488,26 +2781,297 @@ You will be given a query and the markdown of a webpage that has been filtered t
 				error_msg = f'Failed to send keys: {str(e)}'
 				return ActionResult(error=error_msg)
 
-		@self.registry.action('Scroll to text.')
-		async def find_text(text: str, browser_session: BrowserSession):  # type: ignore
-			# Dispatch scroll to text event
-			event = browser_session.event_bus.dispatch(ScrollToTextEvent(text=text))
+		@self.registry.action(
+			'Copy text into the agent clipboard: text of element at index, else the selected text. '
+			'Use paste to insert it elsewhere (also on other pages) without retyping it.',
+			param_model=CopyAction,
+		)
+		async def copy(params: CopyAction, browser_session: BrowserSession):
+			try:
+				if params.from_system_clipboard:
+					text = await _read_browser_clipboard(browser_session)
+					source = 'browser clipboard'
+				elif params.index is not None:
+					node = await browser_session.get_element_by_index(params.index)
+					if node is None:
+						msg = f'Element with index {params.index} does not exist.'
+						return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+					cdp_session = await browser_session.cdp_client_for_node(node)
+					resolved = await cdp_session.cdp_client.send.DOM.resolveNode(
+						params={'backendNodeId': node.backend_node_id}, session_id=cdp_session.session_id
+					)
+					result = await cdp_session.cdp_client.send.Runtime.callFunctionOn(
+						params={
+							'functionDeclaration': _READ_ELEMENT_TEXT_JS,
+							'objectId': resolved['object']['objectId'],
+							'returnByValue': True,
+						},
+						session_id=cdp_session.session_id,
+					)
+					text = result.get('result', {}).get('value') or ''
+					source = f'element {params.index}'
+				else:
+					cdp_session = await browser_session.get_or_create_cdp_session()
+					result = await cdp_session.cdp_client.send.Runtime.evaluate(
+						params={'expression': _READ_SELECTION_JS, 'returnByValue': True}, session_id=cdp_session.session_id
+					)
+					text = result.get('result', {}).get('value') or ''
+					source = 'selection'
+			except BrowserError as e:
+				return ActionResult(error=e.message)
+			except Exception as e:
+				logger.error(f'Failed to copy text: {type(e).__name__}: {e}')
+				return ActionResult(error=f'Failed to copy text: {type(e).__name__}: {e}')
+
+			if not text:
+				return ActionResult(error=f'Nothing to copy: the {source} has no text.')
+
+			self.clipboard = text
+			memory = f'Copied {len(text)} characters from {source}: "{truncate_text(text, 100)}"'
+			logger.info(f'📋 {memory}')
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+		@self.registry.action(
+			'Paste the agent clipboard (filled by copy) into the input at index, or into the focused element.',
+			param_model=PasteAction,
+		)
+		async def paste(params: PasteAction, browser_session: BrowserSession):
+			if not self.clipboard:
+				return ActionResult(error='Clipboard is empty. Use copy first.')
 
 			try:
-				# The handler returns None on success or raises an exception if text not found
-				await event.event_result(raise_if_any=True, raise_if_none=False)
-				memory = f'Scrolled to text: {text}'
-				msg = f'🔍  {memory}'
-				logger.info(msg)
-				return ActionResult(extracted_content=memory, long_term_memory=memory)
+				if params.index is not None:
+					node = await browser_session.get_element_by_index(params.index)
+					if node is None:
+						msg = f'Element with index {params.index} does not exist.'
+						return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+					cdp_session = await browser_session.cdp_client_for_node(node)
+					await cdp_session.cdp_client.send.DOM.focus(
+						params={'backendNodeId': node.backend_node_id}, session_id=cdp_session.session_id
+					)
+				else:
+					cdp_session = await browser_session.get_or_create_cdp_session()
+				await cdp_session.cdp_client.send.Input.insertText(
+					params={'text': self.clipboard}, session_id=cdp_session.session_id
+				)
 			except Exception as e:
-				# Text not found
-				msg = f"Text '{text}' not found or not visible on page"
-				logger.info(msg)
-				return ActionResult(
-					extracted_content=msg,
-					long_term_memory=f"Tried scrolling to text '{text}' but it was not found",
+				logger.error(f'Failed to paste text: {type(e).__name__}: {e}')
+				return ActionResult(error=f'Failed to paste text: {type(e).__name__}: {e}')
+
+			target = f'element {params.index}' if params.index is not None else 'focused element'
+			memory = f'Pasted {len(self.clipboard)} characters into {target}'
+			logger.info(f'📋 {memory}')
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+		@self.registry.action(
+			'Find text on the page and scroll to it. Returns ranked matches (exact, case-insensitive, fuzzy) with the '
+			'index of the interactive element containing each match. Use scroll_to to pick another match.',
+			param_model=FindTextAction,
+		)
+		async def find_text(params: FindTextAction, browser_session: BrowserSession):
+			cdp_session = await browser_session.get_or_create_cdp_session()
+			cdp_client, session_id = cdp_session.cdp_client, cdp_session.session_id
+			object_group = 'browser-use-find-text'
+			try:
+				result = await cdp_client.send.Runtime.evaluate(
+					params={
+						'expression': f'{_FIND_TEXT_JS}({json.dumps(params.text)}, {params.max_results})',
+						'objectGroup': object_group,
+					},
+					session_id=session_id,
+				)
+				if result.get('exceptionDetails') or 'objectId' not in result.get('result', {}):
+					error_text = result.get('exceptionDetails', {}).get('text', 'no result')
+					return ActionResult(error=f'find_text failed: {error_text}')
+
+				properties = await cdp_client.send.Runtime.getProperties(
+					params={'objectId': result['result']['objectId'], 'ownProperties': True}, session_id=session_id
+				)
+				values = {prop['name']: prop.get('value', {}) for prop in properties['result']}
+				data = json.loads(values['data']['value'])
+				matches: list[dict] = data['matches']
+
+				element_properties = await cdp_client.send.Runtime.getProperties(
+					params={'objectId': values['elements']['objectId'], 'ownProperties': True}, session_id=session_id
+				)
+				element_ids = {
+					int(prop['name']): prop['value']['objectId']
+					for prop in element_properties['result']
+					if prop['name'].isdigit() and 'objectId' in prop.get('value', {})
+				}
+
+				selector_map = await browser_session.get_selector_map()
+				interactive_indices = _interactive_indices_by_backend_id(selector_map, session_id)
+				for position, match in enumerate(matches):
+					if position not in element_ids:
+						continue
+					described = await cdp_client.send.DOM.describeNode(
+						params={'objectId': element_ids[position]}, session_id=session_id
+					)
+					match['index'] = interactive_indices.get(described['node']['backendNodeId'])
+
+				scrolled_to = None
+				position = min(params.scroll_to, len(matches))
+				# The element may have been detached between the search and now, then there is nothing to scroll to
+				if matches and position - 1 in element_ids:
+					scrolled_to = position
+					await cdp_client.send.Runtime.callFunctionOn(
+						params={
+							'functionDeclaration': _SHOW_TEXT_MATCH_JS,
+							'objectId': element_ids[position - 1],
+							'arguments': [
+								{'value': params.highlight},
+								{'value': browser_session.browser_profile.interaction_highlight_color},
+							],
+						},
+						session_id=session_id,
+					)
+					if params.highlight:
+						browser_session._text_match_highlighted = True
+			finally:
+				await cdp_client.send.Runtime.releaseObjectGroup(params={'objectGroup': object_group}, session_id=session_id)
+
+			if not matches:
+				# The ranked search only sees the top document, the DOM search behind ScrollToTextEvent also covers iframes
+				event = browser_session.event_bus.dispatch(ScrollToTextEvent(text=params.text))
+				try:
+					await event.event_result(raise_if_any=True, raise_if_none=False)
+				except Exception:
+					pass
+				else:
+					memory = f'Scrolled to text: {params.text} (found inside a frame, no ranked matches)'
+					logger.info(f'🔍 {memory}')
+					return ActionResult(extracted_content=memory, long_term_memory=memory, metadata={'matches': []})
+
+			formatted = _format_text_matches(matches, data['total'], params.text, scrolled_to)
+			if not matches:
+				memory = f"Tried finding text '{params.text}' but it was not found"
+			elif scrolled_to is None:
+				found = f'{data["total"]} match{"es" if data["total"] != 1 else ""}'
+				memory = f'Found {found} for "{params.text}", but the match is no longer on the page'
+			else:
+				best = matches[scrolled_to - 1]
+				target = f' (element {best["index"]})' if best.get('index') is not None else ''
+				found = f'{data["total"]} match{"es" if data["total"] != 1 else ""}'
+				memory = f'Found {found} for "{params.text}", scrolled to match {scrolled_to}{target}'
+			logger.info(f'🔍 {memory}')
+			return ActionResult(extracted_content=formatted, long_term_memory=memory, metadata={'matches': matches})
+
+		@self.registry.action(
+			'Show everything about one element when its browser_state line is not enough to tell it apart: full outerHTML '
+			'(bounded by max_html_chars), all attributes, accessible role and name, current value and bounding box.',
+			param_model=InspectElementAction,
+		)
+		async def inspect_element(params: InspectElementAction, browser_session: BrowserSession):
+			node = await browser_session.get_element_by_index(params.index)
+			if node is None:
+				msg = f'Element index {params.index} not found in browser state'
+				return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+
+			cdp_session = await browser_session.cdp_client_for_node(node)
+			send = cdp_session.cdp_client.send
+			try:
+				resolved = await send.DOM.resolveNode(
+					params={'backendNodeId': node.backend_node_id}, session_id=cdp_session.session_id
+				)
+				result = await send.Runtime.callFunctionOn(
+					params={
+						'functionDeclaration': _INSPECT_ELEMENT_JS,
+						'objectId': resolved['object']['objectId'],
+						'arguments': [{'value': params.max_html_chars}],
+						'returnByValue': True,
+					},
+					session_id=cdp_session.session_id,
 				)
+			except Exception as e:
+				return ActionResult(error=f'Could not inspect element {params.index}, it may have been removed: {e}')
+			details = result.get('result', {}).get('value')
+			if not isinstance(details, dict):
+				return ActionResult(error=f'Could not inspect element {params.index}')
+
+			# Role and name as computed by the browser, the cached accessibility node may be stale or missing
+			ax_node = node.ax_node
+			role, name, description = (ax_node.role, ax_node.name, ax_node.description) if ax_node else (None, None, None)
+			try:
+				ax_tree = await send.Accessibility.getPartialAXTree(
+					params={'backendNodeId': node.backend_node_id, 'fetchRelatives': False}, session_id=cdp_session.session_id
+				)
+				if ax_tree['nodes']:
+					live_node = ax_tree['nodes'][0]
+					role = live_node.get('role', {}).get('value') or role
+					name = live_node.get('name', {}).get('value') or name
+					description = live_node.get('description', {}).get('value') or description
+			except Exception as e:
+				logger.debug(f'Accessibility info unavailable for element {params.index}: {e}')
+
+			details.update(tag=node.tag_name.lower(), xpath=node.xpath, role=role, name=name, description=description)
+			memory = f'Inspected element {params.index} <{details["tag"]}>'
+			logger.info(f'🔬 {memory}')
+			return ActionResult(
+				extracted_content=_format_element_details(params.index, details),
+				long_term_memory=memory,
+				include_extracted_content_only_once=True,
+				metadata={'element': details},
+			)
+
+		@self.registry.action(
+			'Get the position and size of an element in CSS pixels, relative to the viewport, and how far it is out of view. '
+			'Use it to pick scroll amounts or click coordinates inside an element (e.g. a canvas).',
+			param_model=GetBoundingBoxAction,
+		)
+		async def get_bounding_box(params: GetBoundingBoxAction, browser_session: BrowserSession):
+			from browser_use.actor.element import Element
+
+			node = await browser_session.get_element_by_index(params.index)
+			if node is None:
+				msg = f'Element index {params.index} not found in browser state'
+				return ActionResult(error=msg, error_type=ElementNotFoundError.error_type)
+
+			cdp_session = await browser_session.cdp_client_for_node(node)
+			box = await Element(browser_session, node.backend_node_id, cdp_session.session_id).get_bounding_box()
+			if box is None:
+				return ActionResult(error=f'Element {params.index} has no layout box, it may be hidden or removed')
+			try:
+				metrics = await cdp_session.cdp_client.send.Page.getLayoutMetrics(session_id=cdp_session.session_id)
+				css_viewport = metrics.get('cssVisualViewport', {})
+				viewport = {
+					'width': round(css_viewport.get('clientWidth', 0)),
+					'height': round(css_viewport.get('clientHeight', 0)),
+					'scroll_x': round(css_viewport.get('pageX', 0)),
+					'scroll_y': round(css_viewport.get('pageY', 0)),
+				}
+			except Exception as e:
+				logger.debug(f'Layout metrics unavailable for element {params.index}: {e}')
+				viewport = None
+
+			x, y, width, height = (round(box[key]) for key in ('x', 'y', 'width', 'height'))
+			center_x, center_y = round(box['x'] + box['width'] / 2), round(box['y'] + box['height'] / 2)
+			lines = [
+				f'Element {params.index} <{node.tag_name.lower()}>: x={x}, y={y}, width={width}, height={height} '
+				f'(viewport CSS pixels), center ({center_x}, {center_y})'
+			]
+			if viewport and viewport['height'] > 0:
+				lines.append(f'Page position: x={x + viewport["scroll_x"]}, y={y + viewport["scroll_y"]}')
+				below, above = y + height - viewport['height'], -y
+				if y >= viewport['height']:
+					pages = y / viewport['height']
+					visibility = f'{y - viewport["height"]}px below the viewport, scroll down about {pages:.1f} pages'
+				elif y + height <= 0:
+					pages = above / viewport['height']
+					visibility = f'{above - height}px above the viewport, scroll up about {pages:.1f} pages'
+				elif below > 0 or above > 0 or x < 0 or x + width > viewport['width']:
+					visibility = 'partly visible'
+				else:
+					visibility = 'fully visible'
+				lines.append(f'Viewport {viewport["width"]}x{viewport["height"]}: {visibility}')
+
+			memory = f'Element {params.index} box: x={x}, y={y}, width={width}, height={height}'
+			logger.info(f'📐 {memory}')
+			return ActionResult(
+				extracted_content='\n'.join(lines),
+				long_term_memory=memory,
+				metadata={'bounding_box': {'x': x, 'y': y, 'width': width, 'height': height}, 'viewport': viewport},
+			)
 
 		@self.registry.action(
 			'Take a screenshot of the current viewport. If file_name is provided, saves to that file and returns the path. '
@@ -1529,7 +3093,7 @@ You will be given a query and the markdown of a webpage that has been filtered t
 
 				screenshot_bytes = await browser_session.take_screenshot(full_page=False)
 				file_path = file_system.get_dir() / file_name
-				file_path.write_bytes(screenshot_bytes)
+				atomic_write_bytes(file_path, screenshot_bytes)
 
 				result = f'Screenshot saved to {file_name}'
 				logger.info(f'📸 {result}. Full path: {file_path}')
@@ -1685,15 +3249,26 @@ You will be given a query and the markdown of a webpage that has been filtered t
 			if not dropdown_data:
 				raise ValueError('Failed to get dropdown options - no data returned')
 
+			# The options as data for programmatic callers, e.g. to pick a value without parsing the text
+			metadata = None
+			if 'options' in dropdown_data:
+				options = [DropdownOption.model_validate(option) for option in json.loads(dropdown_data['options'])]
+				metadata = {
+					'dropdown_type': dropdown_data.get('type'),
+					'options': [option.model_dump() for option in options],
+					'selected': [option.value for option in options if option.selected],
+				}
+
 			# Use structured memory from the handler
 			return ActionResult(
 				extracted_content=dropdown_data['short_term_memory'],
 				long_term_memory=dropdown_data['long_term_memory'],
 				include_extracted_content_only_once=True,
+				metadata=metadata,
 			)
 
 		@self.registry.action(
-			'Set the option of a <select> element.',
+			'Set the option of a <select> element or a custom dropdown (opened and searched for the option text).',
 			param_model=SelectDropdownOptionAction,
 		)
 		async def select_dropdown(params: SelectDropdownOptionAction, browser_session: BrowserSession):
@@ -1714,14 +3289,20 @@ You will be given a query and the markdown of a webpage that has been filtered t
 			if not selection_data:
 				raise ValueError('Failed to select dropdown option - no data returned')
 
+			# Steps taken to open and search a custom dropdown, reported for debugging
+			chain = selection_data.get('fallback_chain')
+			metadata = {'fallback_chain': chain} if chain else None
+
 			# Check if the selection was successful
 			if selection_data.get('success') == 'true':
 				# Extract the message from the returned data
 				msg = selection_data.get('message', f'Selected option: {params.text}')
+				selected = selection_data.get('value', params.text)
 				return ActionResult(
 					extracted_content=msg,
 					include_in_memory=True,
-					long_term_memory=f"Selected dropdown option '{params.text}' at index {params.index}",
+					long_term_memory=f"Selected dropdown option '{selected}' at index {params.index}",
+					metadata=metadata,
 				)
 			else:
 				# Handle structured error response
@@ -1731,11 +3312,105 @@ You will be given a query and the markdown of a webpage that has been filtered t
 						extracted_content=selection_data['short_term_memory'],
 						long_term_memory=selection_data['long_term_memory'],
 						include_extracted_content_only_once=True,
+						metadata=metadata,
 					)
 				else:
 					# Fallback to regular error
 					error_msg = selection_data.get('error', f'Failed to select option: {params.text}')
-					return ActionResult(error=error_msg)
+					return ActionResult(error=error_msg, metadata=metadata)
+
+		@self.registry.action(
+			'Fill several form fields in one step, in order. Address each field by index, or by label when the index is unknown. '
+			'For <select> pass the option text, for checkboxes and radios pass true/false. '
+			'Optionally click submit_index after every field was filled successfully.',
+			param_model=FillFormAction,
+		)
+		async def fill_form(
+			params: FillFormAction,
+			browser_session: BrowserSession,
+			has_sensitive_data: bool = False,
+			sensitive_data: dict[str, str | dict[str, str]] | None = None,
+		):
+			from browser_use.browser.events import SelectDropdownOptionEvent
+
+			async def fill_field(field: FormField) -> str:
+				"""Fill a single field and describe the result, raises when the field could not be filled."""
+				selector_map = await browser_session.get_selector_map()
+				index = field.index
+				if index is None:
+					assert field.label is not None
+					index = _find_form_field(selector_map, field.label)
+					if index is None:
+						raise ElementNotFoundError(f'No form field labelled "{field.label}" found')
+				node = selector_map.get(index)
+				if node is None:
+					raise ElementNotFoundError(f'Element index {index} not available')
+
+				name = field.label or f'index {index}'
+				input_type = (node.attributes.get('type') or '').lower()
+				role = node.attributes.get('role')
+
+				if node.tag_name == 'select':
+					event = browser_session.event_bus.dispatch(SelectDropdownOptionEvent(node=node, text=field.value))
+					await event
+					selection_data = await event.event_result(raise_if_any=True, raise_if_none=False)
+					if not selection_data or selection_data.get('success') != 'true':
+						error = (selection_data or {}).get('error') or (selection_data or {}).get('short_term_memory')
+						raise ValueError(error or f'Failed to select option "{field.value}"')
+					return f"{name}: selected '{field.value}'"
+
+				if input_type in ('checkbox', 'radio') or role in ('checkbox', 'radio'):
+					should_check = field.value.strip().lower() in _TRUTHY_FORM_VALUES
+					if should_check == _is_checked(node):
+						return f'{name}: already {"checked" if should_check else "unchecked"}'
+					if not should_check and 'radio' in (input_type, role):
+						raise ValueError('A radio button cannot be unchecked, select another option of the group instead')
+					event = browser_session.event_bus.dispatch(ClickElementEvent(node=node))
+					await event
+					await event.event_result(raise_if_any=True, raise_if_none=False)
+					return f'{name}: {"checked" if should_check else "unchecked"}'
+
+				result = await input(
+					params=InputTextAction(index=index, text=field.value),
+					browser_session=browser_session,
+					has_sensitive_data=has_sensitive_data,
+					sensitive_data=sensitive_data,
+				)
+				if result.error:
+					raise ValueError(result.error)
+				return f'{name}: {result.extracted_content}'
+
+			filled: list[str] = []
+			failed: list[str] = []
+			for field in params.fields:
+				try:
+					filled.append(await fill_field(field))
+				except Exception as e:
+					name = field.label or f'index {field.index}'
+					error = e.message if isinstance(e, BrowserError) else str(e)
+					logger.warning(f'⚠️ Could not fill form field {name}: {error}')
+					failed.append(f'{name}: {error}')
+
+			lines = [f'Filled {len(filled)}/{len(params.fields)} form fields']
+			lines += [f'✅ {line}' for line in filled]
+			lines += [f'❌ {line}' for line in failed]
+
+			if params.submit_index is not None:
+				if failed:
+					lines.append(f'Did not click submit button {params.submit_index} because some fields failed')
+				else:
+					submit_result = await self._click_by_index(ClickElementAction(index=params.submit_index), browser_session)
+					if submit_result.error:
+						failed.append(f'submit: {submit_result.error}')
+						lines.append(f'❌ Failed to click submit button {params.submit_index}: {submit_result.error}')
+					else:
+						lines.append(f'Submitted form: {submit_result.extracted_content}')
+
+			msg = '\n'.join(lines)
+			logger.info(f'📝 {lines[0]}')
+			if failed:
+				return ActionResult(error=msg, long_term_memory=msg)
+			return ActionResult(extracted_content=msg, long_term_memory=msg)
 
 		# File System Actions
 
@@ -1897,7 +3572,7 @@ Validated Code (after quote fixing):
 
 				# Apply length limit with better truncation (after image extraction)
 				if len(result_text) > 20000:
-					result_text = result_text[:19950] + '\n... [Truncated after 20000 characters]'
+					result_text = safe_slice(result_text, 19950) + '\n... [Truncated after 20000 characters]'
 
 				# Don't log the code - it's already visible in the user's cell
 				logger.debug(f'JavaScript executed successfully, result length: {len(result_text)}')
@@ -1926,6 +3601,41 @@ Validated Code (after quote fixing):
 				logger.debug(f'JavaScript code that failed: {code[:200]}...')
 				return ActionResult(error=error_msg)
 
+		if self.auth_vault is not None:
+			self._register_restore_login_action(self.auth_vault)
+
+	def _register_restore_login_action(self, vault: AuthVault) -> None:
+		"""Let the agent restore a saved login session when it runs into a login wall."""
+
+		@self.registry.action(
+			'Restore a saved login session (cookies and storage) when the current site asks you to log in, then reload. '
+			'Prefer this over typing credentials. Omit name to use the session saved for the current site.',
+			param_model=RestoreLoginAction,
+		)
+		async def restore_login(params: RestoreLoginAction, browser_session: BrowserSession):
+			name = params.name
+			if name is None:
+				url = await browser_session.get_current_page_url()
+				matches = await asyncio.to_thread(vault.find, url)
+				if not matches:
+					saved = ', '.join(vault.names()) or 'none'
+					msg = f'No saved login session for {site_host(url) or url}. Saved sessions: {saved}'
+					return ActionResult(error=msg)
+				name = matches[0].name
+
+			try:
+				entry = await browser_session.restore_auth(name, vault=vault)
+			except AuthVaultError as e:
+				return ActionResult(error=str(e))
+
+			sites = ', '.join(entry.sites) or 'no sites'
+			memory = f'Restored saved login session {name!r} for {sites}'
+			return ActionResult(
+				extracted_content=f'{memory} and reloaded the page. Check whether you are logged in now.',
+				long_term_memory=memory,
+				metadata={'auth_session': {'name': name, 'sites': entry.sites, 'saved_at': entry.saved_at.isoformat()}},
+			)
+
 	def _validate_and_fix_javascript(self, code: str) -> str:
 		"""Validate and fix common JavaScript issues before execution"""
 
@@ -2041,7 +3751,7 @@ Validated Code (after quote fixing):
 
 				len_text = len(params.text)
 				len_max_memory = 100
-				memory = f'Task completed: {params.success} - {params.text[:len_max_memory]}'
+				memory = f'Task completed: {params.success} - {safe_slice(params.text, len_max_memory)}'
 				if len_text > len_max_memory:
 					memory += f' - {len_text - len_max_memory} more characters'
 
@@ -2075,6 +3785,36 @@ Validated Code (after quote fixing):
 					attachments=attachments,
 				)
 
+	def _search_url(self, engine: str, query: str) -> str:
+		"""Fill the engine's URL template with the query."""
+		import urllib.parse
+
+		return self.search_engines[engine].replace('{query}', urllib.parse.quote_plus(query))
+
+	def _search_cookies(self, engine: str) -> list[Cookie]:
+		"""Cookies that sign the browser in to the engine, raises ValueError if Kagi has no session token."""
+		if engine != 'kagi':
+			return []
+		if not self.kagi_api_key:
+			raise ValueError(f'Search engine {engine} needs Tools(kagi_api_key=...) or the {KAGI_API_KEY_ENV} env var')
+		cookie = Cookie(  # type: ignore
+			name=KAGI_SESSION_COOKIE, value=self.kagi_api_key, domain='kagi.com', path='/', secure=True, httpOnly=True
+		)
+		return [cookie]
+
+	async def _search_page_blocked(self, browser_session: BrowserSession) -> bool:
+		"""Whether the page a search landed on is a captcha or block page, False if it can't be read."""
+		try:
+			cdp_session = await browser_session.get_or_create_cdp_session()
+			result = await cdp_session.cdp_client.send.Runtime.evaluate(
+				params={'expression': _SEARCH_PAGE_SUMMARY_JS, 'returnByValue': True}, session_id=cdp_session.session_id
+			)
+		except Exception as e:
+			logger.debug(f'Could not check the search result page: {e}')
+			return False
+		page = result.get('result', {}).get('value') or {}
+		return is_blocked_search_page(page.get('url', ''), page.get('title', ''), page.get('text', ''), page.get('length'))
+
 	def use_structured_output_action(self, output_model: type[T]):
 		self._output_model = output_model
 		self._register_done_action(output_model)
@@ -2092,6 +3832,22 @@ Validated Code (after quote fixing):
 		"""
 		return self.registry.action(description, **kwargs)
 
+	def export_tool_definitions(
+		self,
+		format: Literal['json_schema', 'openapi'] = 'json_schema',
+		path: str | Path | None = None,
+	) -> dict[str, Any]:
+		"""Export all registered actions (including custom actions) as a JSON Schema or OpenAPI document.
+
+		Args:
+			format: 'json_schema' or 'openapi'
+			path: Optional file path to also write the document to as JSON
+		"""
+		document = self.registry.export_tool_definitions(format=format)
+		if path is not None:
+			Path(path).write_text(json.dumps(document, indent=2), encoding='utf-8')
+		return document
+
 	def exclude_action(self, action_name: str) -> None:
 		"""Exclude an action from the tools registry.
 
@@ -2103,13 +3859,33 @@ Validated Code (after quote fixing):
 		"""
 		self.registry.exclude_action(action_name)
 
+	def copy(self) -> 'Tools[Context]':
+		"""A copy with its own action registry and timeouts, for actions only one agent should get when agents share tools.
+
+		Actions registered on the copy are not added to these tools, the built-in actions and their state stay shared.
+		"""
+		tools = object.__new__(type(self))
+		tools.__dict__.update(self.__dict__)
+		tools.registry = Registry[Context](list(self.registry.exclude_actions))
+		tools.registry.registry.actions = dict(self.registry.registry.actions)
+		tools.action_timeouts = dict(self.action_timeouts)
+		return tools
+
 	def _register_click_action(self) -> None:
 		"""Register the click action with or without coordinate support based on current setting."""
 		# Remove existing click action if present
 		if 'click' in self.registry.registry.actions:
 			del self.registry.registry.actions['click']
 
-		if self._coordinate_clicking_enabled:
+		if self._computer_use_mode:
+			# Register click action with coordinates only, there are no element indices in computer-use mode
+			@self.registry.action(
+				'Click at coordinates on the current screenshot.',
+				param_model=ClickCoordinateAction,
+			)
+			async def click(params: ClickCoordinateAction, browser_session: BrowserSession):
+				return await self._click_by_coordinate(params, browser_session)
+		elif self._coordinate_clicking_enabled:
 			# Register click action WITH coordinate support
 			@self.registry.action(
 				'Click element by index or coordinates. Use coordinates only if the index is not available. Either provide coordinates or index.',
@@ -2158,6 +3934,163 @@ Validated Code (after quote fixing):
 		self._register_click_action()
 		logger.debug(f'Coordinate clicking {"enabled" if enabled else "disabled"}')
 
+	def use_computer_use_actions(self) -> None:
+		"""Switch to the reduced, coordinate-only action set of computer-use mode.
+
+		Actions that address elements by their browser_state index are removed. click, scroll and type_text
+		take coordinates on the screenshot instead and send_keys presses keys, so no DOM index is needed.
+		"""
+		if self._computer_use_mode:
+			return
+		self._computer_use_mode = True
+
+		for action_name in _DOM_INDEX_ACTIONS:
+			self.registry.exclude_action(action_name)
+		self._register_click_action()
+		if 'scroll' in self.registry.registry.actions:
+			del self.registry.registry.actions['scroll']
+
+		def _get_point(
+			coordinate_x: int | None, coordinate_y: int | None, browser_session: BrowserSession
+		) -> tuple[int, int] | None:
+			if coordinate_x is None or coordinate_y is None:
+				return None
+			return self._convert_llm_coordinates_to_viewport(coordinate_x, coordinate_y, browser_session)
+
+		@self.registry.action(
+			'Scroll by pages. Give coordinates to scroll the area under that point (dropdowns, panels), omit them for the page.',
+			param_model=ScrollCoordinateAction,
+		)
+		async def scroll(params: ScrollCoordinateAction, browser_session: BrowserSession):
+			if (params.coordinate_x is None) != (params.coordinate_y is None):
+				return ActionResult(error='Provide both coordinate_x and coordinate_y, or neither to scroll the page')
+			point = _get_point(params.coordinate_x, params.coordinate_y, browser_session)
+
+			viewport_height = browser_session._original_viewport_size[1] if browser_session._original_viewport_size else 1000
+			direction = 'down' if params.down else 'up'
+			try:
+				event = browser_session.event_bus.dispatch(
+					ScrollEvent(
+						direction=direction,
+						amount=int(params.pages * viewport_height),
+						coordinate_x=point[0] if point else None,
+						coordinate_y=point[1] if point else None,
+					)
+				)
+				await event
+				await event.event_result(raise_if_any=True, raise_if_none=False)
+			except Exception as e:
+				logger.error(f'Failed to dispatch ScrollEvent: {type(e).__name__}: {e}')
+				return ActionResult(error='Failed to execute scroll action.')
+
+			memory = f'Scrolled {direction} {params.pages} pages'
+			if point:
+				memory += f' at {params.coordinate_x}, {params.coordinate_y}'
+			logger.info(f'🔍 {memory}')
+			return ActionResult(extracted_content=memory, long_term_memory=memory)
+
+		@self.registry.action(
+			'Type text. Give coordinates to click that field first, omit them to type into the focused element.',
+			param_model=TypeTextAction,
+		)
+		async def type_text(
+			params: TypeTextAction,
+			browser_session: BrowserSession,
+			has_sensitive_data: bool = False,
+			sensitive_data: dict[str, str | dict[str, str]] | None = None,
+		):
+			if (params.coordinate_x is None) != (params.coordinate_y is None):
+				return ActionResult(error='Provide both coordinate_x and coordinate_y, or neither to type into the focused field')
+			if params.coordinate_x is not None and params.coordinate_y is not None:
+				click_result = await self._click_by_coordinate(
+					ClickCoordinateAction(coordinate_x=params.coordinate_x, coordinate_y=params.coordinate_y), browser_session
+				)
+				if click_result.error:
+					return click_result
+
+			sensitive_key_name = None
+			if has_sensitive_data and sensitive_data:
+				sensitive_key_name = _detect_sensitive_key_name(params.text, sensitive_data)
+			try:
+				# No node: types into whatever has focus
+				event = browser_session.event_bus.dispatch(
+					TypeTextEvent(text=params.text, is_sensitive=has_sensitive_data, sensitive_key_name=sensitive_key_name)
+				)
+				await event
+				await event.event_result(raise_if_any=True, raise_if_none=False)
+			except BrowserError as e:
+				return handle_browser_error(e)
+			except Exception as e:
+				logger.error(f'Failed to dispatch TypeTextEvent: {type(e).__name__}: {e}')
+				return ActionResult(error=f'Failed to type text: {e}')
+
+			if has_sensitive_data:
+				msg = f'Typed {sensitive_key_name}' if sensitive_key_name else 'Typed sensitive data'
+			else:
+				msg = f"Typed '{params.text}'"
+			if params.coordinate_x is not None:
+				msg += f' at {params.coordinate_x}, {params.coordinate_y}'
+			return ActionResult(extracted_content=msg, long_term_memory=msg)
+
+		logger.debug('Computer-use actions enabled')
+
+	def _resolve_upload_path(
+		self,
+		path: str,
+		browser_session: BrowserSession,
+		available_file_paths: list[str] | None,
+		file_system: FileSystem | None,
+	) -> tuple[str | None, str | None]:
+		"""Resolve the path the model passed to upload_file. Returns (upload_path, error).
+
+		Lookup order:
+		1. Files the agent wrote into its FileSystem (matched by file name), local sessions only
+		2. Paths the user passed in available_file_paths, or files downloaded during the session
+		3. On remote sessions, any other path, it addresses a file on the remote browser's machine
+		4. Any other host path, only when allow_host_uploads is enabled
+		"""
+		authorized_paths = set(available_file_paths or []) | set(browser_session.downloaded_files)
+
+		# On remote sessions `path` addresses a file on the remote machine, and a coincidental basename
+		# collision with a local managed file (e.g. `/tmp/note.md` vs `note.md`) must not upload the local file.
+		if path not in authorized_paths and browser_session.is_local and file_system is not None:
+			file_obj = file_system.get_file(path)
+			if file_obj is not None:
+				# Build the upload path from the FileSystem-owned basename (file_obj.full_name), NOT from `path`:
+				# get_file() matches by basename, so `../../../note.md` would otherwise escape data_dir.
+				# GHSA-j9hj-92j8-jv9h.
+				if isinstance(file_system, InMemoryFileSystem):
+					# In-memory files only exist on disk once written to the scratch directory
+					file_system_path = str(file_system.materialize(file_obj.full_name))
+				else:
+					file_system_path = str(file_system.get_dir() / file_obj.full_name)
+				# Defense in depth: refuse any path that resolves outside data_dir.
+				real_path = os.path.realpath(file_system_path)
+				real_dir = os.path.realpath(str(file_system.get_dir()))
+				if not (real_path == real_dir or real_path.startswith(real_dir + os.sep)):
+					return None, f'Upload of {path!r} escapes FileSystem directory; refusing.'
+				return file_system_path, None
+
+		if path in authorized_paths or not browser_session.is_local or self.allow_host_uploads:
+			return path, None
+
+		return None, (
+			f'File path {path} is not available. Upload a file you wrote with write_file (by its file name), '
+			f'or a path from available_file_paths. To fix: The user must add this file path to the available_file_paths '
+			f'parameter when creating the Agent, e.g. Agent(task="...", llm=llm, available_file_paths=["{path}"]), '
+			f'or allow arbitrary host paths with Tools(allow_host_uploads=True).'
+		)
+
+	def get_action_timeout(self, action_name: str, override: float | None = None) -> float:
+		"""Resolve the timeout for an action: explicit override > per-action config > Tools default > global default"""
+		if override is not None:
+			return _coerce_valid_action_timeout(override)
+		if action_name in self.action_timeouts:
+			return self.action_timeouts[action_name]
+		if self.action_timeout is not None:
+			return self.action_timeout
+		return _DEFAULT_ACTION_TIMEOUT_S
+
 	# Act --------------------------------------------------------------------
 	@observe_debug(ignore_input=True, ignore_output=True, name='act')
 	@time_execution_sync('--act')
@@ -2171,20 +4104,22 @@ Validated Code (after quote fixing):
 		file_system: FileSystem | None = None,
 		extraction_schema: dict | None = None,
 		action_timeout: float | None = None,
+		redact_text: Callable[[str], str] | None = None,
 	) -> ActionResult:
 		"""Execute an action.
 
 		action_timeout: per-action wall-clock cap (seconds). Prevents actions from hanging
 		indefinitely when a CDP WebSocket goes silent — a common failure mode with remote
 		browsers where internal CDP calls (tab switches, lifecycle waits) have no timeouts.
-		Defaults to BROWSER_USE_ACTION_TIMEOUT_S env var or 180s (above the 120s
-		page_extraction_llm cap used by the `extract` action).
+		Defaults to the Tools(action_timeouts={name: ...}) override for the action, then
+		Tools(action_timeout=...), then BROWSER_USE_ACTION_TIMEOUT_S env var or 180s (above
+		the 120s page_extraction_llm cap used by the `extract` action).
 		"""
 
-		timeout_s = _coerce_valid_action_timeout(action_timeout)
-
 		for action_name, params in action.model_dump(exclude_unset=True).items():
 			if params is not None:
+				timeout_s = self.get_action_timeout(action_name, action_timeout)
+
 				# Use Laminar span if available, otherwise use no-op context manager
 				if Laminar is not None:
 					span_context = Laminar.start_as_current_span(
@@ -2213,6 +4148,7 @@ Validated Code (after quote fixing):
 								sensitive_data=sensitive_data,
 								available_file_paths=available_file_paths,
 								extraction_schema=extraction_schema,
+								redact_text=redact_text,
 							),
 							timeout=timeout_s,
 						)
@@ -2229,14 +4165,15 @@ Validated Code (after quote fixing):
 						result = ActionResult(
 							error=(
 								f'Action {action_name} timed out after {timeout_s:.0f}s. '
-								f'The browser may be unresponsive (dead CDP WebSocket). '
+								f'The page may be slow or the browser unresponsive (dead CDP WebSocket). '
 								f'Try again or a different approach.'
-							)
+							),
+							error_type='timeout',
 						)
 					except Exception as e:
 						# Log the original exception with traceback for observability
 						logger.error(f"Action '{action_name}' failed with error: {str(e)}")
-						result = ActionResult(error=str(e))
+						result = ActionResult(error=str(e), error_type=AgentError.get_error_type(e))
 
 					if Laminar is not None:
 						Laminar.set_span_output(result)
@@ -1,6 +1,6 @@
-from typing import Generic, TypeVar
+from typing import Generic, Literal, TypeVar
 
-from pydantic import BaseModel, ConfigDict, Field
+from pydantic import BaseModel, ConfigDict, Field, model_validator
 from pydantic.json_schema import SkipJsonSchema
 
 
@@ -17,6 +17,11 @@ class ExtractAction(BaseModel):
 	start_from_char: int = Field(
 		default=0, description='Use this for long markdowns to start from a specific character (not index in browser_state)'
 	)
+	main_content_only: bool = Field(
+		default=False,
+		description='Set True to read only the main content (article, docs, product details), '
+		'without navigation, headers, footers, sidebars and cookie banners',
+	)
 	output_schema: SkipJsonSchema[dict | None] = Field(
 		default=None,
 		description='Optional JSON Schema dict. When provided, extraction returns validated JSON matching this schema instead of free-text.',
@@ -27,6 +32,56 @@ class ExtractAction(BaseModel):
 	)
 
 
+class ProcessLinksAction(BaseModel):
+	urls: list[str] = Field(min_length=1, max_length=50, description='Links to process (absolute or page-relative)')
+	query: str = Field(description='What to extract from each linked page')
+	max_concurrency: int = Field(default=4, ge=1, le=8, description='Max background tabs open at once')
+	extract_links: bool = Field(default=False, description='Set True if the query requires links from the linked pages')
+
+
+class PaginateExtractAction(BaseModel):
+	query: str = Field(description='What to extract from each page, e.g. "all listed products"')
+	fields: list[str] = Field(min_length=1, max_length=20, description='Fields of each item, e.g. ["name", "price", "url"]')
+	next_selector: str | None = Field(default=None, description='CSS selector of the next page link or button')
+	next_text: str | None = Field(default=None, description='Visible text of the next page link or button, e.g. "Next"')
+	max_pages: int = Field(default=5, ge=1, le=50, description='Maximum pages to extract, including the current one')
+	file_name: str = Field(default='paginated_results.json', description='JSON file the merged items are saved to')
+	extract_links: bool = Field(default=False, description='Set True if the fields include URLs')
+
+	@model_validator(mode='after')
+	def _require_next_locator(self) -> 'PaginateExtractAction':
+		if not self.next_selector and not self.next_text:
+			raise ValueError('Provide next_selector or next_text to locate the next page link')
+		return self
+
+
+class AssertAction(BaseModel):
+	check: Literal['text_present', 'text_absent', 'element_exists', 'url_matches', 'attribute_equals']
+	value: str | None = Field(
+		default=None, description='Text for text checks, regex or substring for url_matches, expected value for attribute_equals'
+	)
+	selector: str | None = Field(default=None, description='CSS selector for element_exists and attribute_equals')
+	attribute: str | None = Field(
+		default=None, description='Attribute for attribute_equals, "value", "checked" and "text" read the live element state'
+	)
+	case_sensitive: bool = Field(default=False, description='Case-sensitive text checks')
+	description: str | None = Field(default=None, description='What is verified, e.g. "cart shows 2 items"')
+
+	@model_validator(mode='after')
+	def _require_check_fields(self) -> 'AssertAction':
+		required = {
+			'text_present': ('value',),
+			'text_absent': ('value',),
+			'element_exists': ('selector',),
+			'url_matches': ('value',),
+			'attribute_equals': ('selector', 'attribute', 'value'),
+		}[self.check]
+		missing = [name for name in required if getattr(self, name) is None]
+		if missing:
+			raise ValueError(f'{self.check} requires {", ".join(missing)}')
+		return self
+
+
 class SearchPageAction(BaseModel):
 	pattern: str = Field(description='Text or regex pattern to search for in page content')
 	regex: bool = Field(default=False, description='Treat pattern as regex (default: literal text match)')
@@ -36,6 +91,13 @@ class SearchPageAction(BaseModel):
 	max_results: int = Field(default=25, description='Maximum matches to return')
 
 
+class FindTextAction(BaseModel):
+	text: str = Field(min_length=1, description='Text to find on the page')
+	max_results: int = Field(default=5, ge=1, le=20, description='Maximum ranked matches to return')
+	scroll_to: int = Field(default=1, ge=1, description='Rank of the match to scroll into view (1 = best match)')
+	highlight: bool = Field(default=False, description='Outline the scrolled-to match in the next screenshot')
+
+
 class FindElementsAction(BaseModel):
 	selector: str = Field(description='CSS selector to query elements (e.g. "table tr", "a.link", "div.product")')
 	attributes: list[str] | None = Field(
@@ -48,8 +110,9 @@ class FindElementsAction(BaseModel):
 
 class SearchAction(BaseModel):
 	query: str
-	engine: str = Field(
-		default='duckduckgo', description='duckduckgo, google, bing (use duckduckgo by default because less captchas)'
+	engine: str | None = Field(
+		default=None,
+		description='duckduckgo, google, bing, brave, startpage, kagi or a configured custom engine; omit for the default engine',
 	)
 
 
@@ -60,6 +123,10 @@ SearchAction = SearchAction
 class NavigateAction(BaseModel):
 	url: str
 	new_tab: bool = Field(default=False)
+	wait_until: Literal['load', 'domcontentloaded', 'networkidle', 'commit'] = Field(
+		default='load',
+		description="'domcontentloaded' returns sooner on slow pages, 'networkidle' waits for content loaded after load",
+	)
 
 
 # Backward compatibility alias
@@ -80,12 +147,57 @@ class ClickElementActionIndexOnly(BaseModel):
 	index: int = Field(ge=1, description='Element index from browser_state')
 
 
+class ClickCoordinateAction(BaseModel):
+	model_config = ConfigDict(title='ClickElementAction')
+
+	coordinate_x: int = Field(description='Horizontal coordinate relative to the screenshot left edge')
+	coordinate_y: int = Field(description='Vertical coordinate relative to the screenshot top edge')
+
+
 class InputTextAction(BaseModel):
 	index: int = Field(ge=0, description='from browser_state')
 	text: str = Field(description='Text to enter. With clear=True, text="" clears the field without typing.')
 	clear: bool = Field(default=True, description='Clear existing text before typing. Set to False to append instead.')
 
 
+class WaitForElementAction(BaseModel):
+	selector: str | None = Field(default=None, description='CSS selector of the element, e.g. "#results .item"')
+	index: int | None = Field(default=None, ge=0, description='Element index from browser_state, instead of a selector')
+	state: Literal['visible', 'hidden', 'attached'] = Field(
+		default='visible', description='visible=shown on the page, hidden=not shown or removed, attached=present in the DOM'
+	)
+	timeout: float = Field(default=10, ge=0, le=30, description='Maximum seconds to wait')
+
+	@model_validator(mode='after')
+	def _require_selector_or_index(self) -> 'WaitForElementAction':
+		if (self.selector is None) == (self.index is None):
+			raise ValueError('Provide either selector or index')
+		return self
+
+
+class WaitForUrlAction(BaseModel):
+	pattern: str = Field(description='Part of the URL, a glob like "*/checkout/*", or a regex with regex=True')
+	regex: bool = Field(default=False, description='Treat pattern as a regular expression')
+	timeout: float = Field(default=10, ge=0, le=30, description='Maximum seconds to wait')
+
+
+class FormField(BaseModel):
+	index: int | None = Field(default=None, ge=0, description='Element index from browser_state')
+	label: str | None = Field(default=None, description='Label, placeholder or name of the field, used when index is not given')
+	value: str = Field(description='Text to enter, option text for <select>, or true/false for checkboxes and radios')
+
+	@model_validator(mode='after')
+	def _require_index_or_label(self) -> 'FormField':
+		if self.index is None and not self.label:
+			raise ValueError('Each form field needs an index or a label')
+		return self
+
+
+class FillFormAction(BaseModel):
+	fields: list[FormField] = Field(min_length=1, description='Fields to fill, in order')
+	submit_index: int | None = Field(default=None, ge=1, description='Element to click after all fields were filled')
+
+
 class DoneAction(BaseModel):
 	text: str = Field(
 		description=(
@@ -123,23 +235,86 @@ class SwitchTabAction(BaseModel):
 	tab_id: str = Field(min_length=4, max_length=4, description='4-char id')
 
 
+class ListTabsAction(BaseModel):
+	include_thumbnails: bool = Field(default=False, description='Attach a small screenshot of each tab')
+
+
 class CloseTabAction(BaseModel):
 	tab_id: str = Field(min_length=4, max_length=4, description='4-char id')
 
 
+class SetViewportAction(BaseModel):
+	width: int = Field(ge=320, le=7680, description='CSS px, e.g. 1920 for the desktop layout')
+	height: int = Field(ge=200, le=4320, description='CSS px')
+
+
+class SetWindowAction(BaseModel):
+	state: Literal['normal', 'maximized', 'fullscreen'] = Field(default='normal')
+	width: int | None = Field(default=None, ge=320, le=7680, description='Window width in px, only for state=normal')
+	height: int | None = Field(default=None, ge=200, le=4320, description='Window height in px, only for state=normal')
+
+
 class ScrollAction(BaseModel):
 	down: bool = Field(default=True, description='down=True=scroll down, down=False scroll up')
 	pages: float = Field(default=1.0, description='0.5=half page, 1=full page, 10=to bottom/top')
 	index: int | None = Field(default=None, description='Optional element index to scroll within specific element')
 
 
+class ScrollCoordinateAction(BaseModel):
+	model_config = ConfigDict(title='ScrollAction')
+
+	down: bool = Field(default=True, description='down=True=scroll down, down=False scroll up')
+	pages: float = Field(default=1.0, description='0.5=half page, 1=full page, 10=to bottom/top')
+	coordinate_x: int | None = Field(default=None, description='Scroll the area under this point, omit for the page')
+	coordinate_y: int | None = Field(default=None, description='Scroll the area under this point, omit for the page')
+
+
+class TypeTextAction(BaseModel):
+	text: str = Field(description='Text to type, \n presses Enter')
+	coordinate_x: int | None = Field(default=None, description='Field to click first, omit to type into the focused element')
+	coordinate_y: int | None = Field(default=None, description='Field to click first, omit to type into the focused element')
+
+
+class GetNetworkRequestsAction(BaseModel):
+	url_pattern: str | None = Field(
+		default=None, description='Only requests whose URL contains this text or matches this glob (e.g. "*/api/products*")'
+	)
+	limit: int = Field(default=10, ge=1, le=50, description='Return the most recent N matching requests')
+	max_body_chars: int = Field(default=5000, description='Characters of each response body to return')
+
+
 class SendKeysAction(BaseModel):
-	keys: str = Field(description='keys (Escape, Enter, PageDown) or shortcuts (Control+o)')
+	keys: str = Field(
+		description='keys (Escape, Enter, PageDown), shortcuts (Control+o) or space-separated sequences with repeats (Tab Tab Enter, ArrowDown*3)'
+	)
+
+
+class UploadDropzoneAction(BaseModel):
+	index: int = Field(description='Drop zone element to drop the file on')
+	path: str = Field(description='File name of a file you wrote with write_file, or a path from available_file_paths')
+	mode: Literal['auto', 'drop', 'input'] = Field(
+		default='auto',
+		description="'drop' fires drag-and-drop events, 'input' sets the zone's hidden file input, 'auto' prefers the input",
+	)
+
+
+class CopyAction(BaseModel):
+	index: int | None = Field(default=None, description='Element to copy text from. Omit to copy the selected text')
+	from_system_clipboard: bool = Field(
+		default=False, description='Copy what the page put on the browser clipboard (e.g. after clicking a "Copy" button)'
+	)
+
+
+class PasteAction(BaseModel):
+	index: int | None = Field(default=None, description='Input to paste into. Omit to paste into the focused element')
 
 
 class UploadFileAction(BaseModel):
 	index: int
-	path: str
+	path: str | list[str] = Field(
+		description='File name of a file you wrote with write_file or a path from available_file_paths. A list uploads several '
+		'files at once (inputs with multiple), a directory uploads its files (directory inputs, webkitdirectory).'
+	)
 
 
 class NoParamsAction(BaseModel):
@@ -196,6 +371,23 @@ class SaveAsPdfAction(BaseModel):
 	)
 
 
+class InspectElementAction(BaseModel):
+	index: int = Field(ge=1, description='Element index from browser_state')
+	max_html_chars: int = Field(default=4000, ge=200, le=20000, description='Maximum characters of outerHTML to return')
+
+
+class GetBoundingBoxAction(BaseModel):
+	index: int = Field(ge=1, description='Element index from browser_state')
+
+
+class AskHumanAction(BaseModel):
+	question: str = Field(description='Self-contained question for the user, say what you need and why')
+
+
+class RestoreLoginAction(BaseModel):
+	name: str | None = Field(default=None, description='Saved session name, omit to use the one saved for the current site')
+
+
 class GetDropdownOptionsAction(BaseModel):
 	index: int
 
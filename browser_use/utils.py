@@ -5,6 +5,7 @@ import platform
 import re
 import signal
 import time
+import unicodedata
 from collections.abc import Callable, Coroutine
 from fnmatch import fnmatch
 from functools import cache, wraps
@@ -784,7 +785,7 @@ def _log_pretty_url(s: str, max_len: int | None = 22) -> str:
 	"""Truncate/pretty-print a URL with a maximum length, removing the protocol and www. prefix"""
 	s = s.replace('https://', '').replace('http://', '').replace('www.', '')
 	if max_len is not None and len(s) > max_len:
-		return s[:max_len] + '…'
+		return safe_slice(s, max_len) + '…'
 	return s
 
 
@@ -866,3 +867,51 @@ def sanitize_surrogates(text: str) -> str:
 		Text with surrogate characters removed
 	"""
 	return text.encode('utf-8', errors='ignore').decode('utf-8')
+
+
+# C0/C1 control characters except tab, newline and carriage return
+_CONTROL_CHARS_PATTERN = re.compile(r'[\x00-\x08\x0b\x0c\x0e-\x1f\x7f-\x9f]')
+
+# Characters that attach to the preceding character and must not be separated from it
+_ZERO_WIDTH_JOINER = '\u200d'
+_VARIATION_SELECTORS = {chr(c) for c in range(0xFE00, 0xFE10)}
+_EMOJI_MODIFIERS = {chr(c) for c in range(0x1F3FB, 0x1F400)}
+
+
+def sanitize_text(text: str) -> str:
+	"""Make text safe to send to LLM endpoints and write to logs.
+
+	Removes unpaired surrogates and control characters (keeping tab/newline/carriage return)
+	and applies Unicode NFC normalization so equivalent strings compare and count the same.
+	"""
+	text = sanitize_surrogates(text)
+	text = _CONTROL_CHARS_PATTERN.sub('', text)
+	return unicodedata.normalize('NFC', text)
+
+
+def _is_attached_char(char: str) -> bool:
+	return (
+		unicodedata.combining(char) != 0
+		or char == _ZERO_WIDTH_JOINER
+		or char in _VARIATION_SELECTORS
+		or char in _EMOJI_MODIFIERS
+		or '\udc00' <= char <= '\udfff'  # low surrogate of a split pair
+	)
+
+
+def safe_slice(text: str, end: int) -> str:
+	"""Return text[:end], moved back so that it never splits a character from its combining marks,
+	a ZWJ emoji sequence, a variation selector / skin tone modifier, or a surrogate pair."""
+	if end >= len(text):
+		return text
+	end = max(end, 0)
+	while end > 0 and (_is_attached_char(text[end]) or text[end - 1] == _ZERO_WIDTH_JOINER):
+		end -= 1
+	return text[:end]
+
+
+def truncate_text(text: str, max_length: int, suffix: str = '...') -> str:
+	"""Truncate text to at most max_length characters (including suffix) without splitting characters."""
+	if len(text) <= max_length:
+		return text
+	return safe_slice(text, max_length - len(suffix)).rstrip() + suffix
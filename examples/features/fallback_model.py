@@ -10,6 +10,9 @@ with exponential backoff) before the fallback is triggered. This means transient
 are handled by the provider's built-in retries, and the fallback only kicks in when
 the provider truly can't recover.
 
+Pass a list to fallback_llm to configure a chain: each model is tried in order when the
+previous one fails. The model that served each step is recorded in the step metadata.
+
 This is useful for:
 - High availability: Keep your agent running even when one provider has issues
 - Cost optimization: Use a cheaper model as fallback when the primary is rate limited
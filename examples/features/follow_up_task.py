@@ -14,8 +14,8 @@ async def main():
 
 	agent = Agent(task='search for browser-use.', browser_session=browser)
 	await agent.run(max_steps=2)
-	agent.add_new_task('return the title of first result')
-	await agent.run()
+	# Continues with the same history, file system and open tabs
+	await agent.run_task('return the title of first result', max_steps=5)
 
 	await browser.kill()
 
@@ -0,0 +1,88 @@
+"""Tests for the login vault: named, encrypted login sessions saved and restored per site."""
+
+import pytest
+
+pytest.importorskip('cryptography')
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.auth_vault import AuthVault, AuthVaultError
+from browser_use.tools.service import Tools
+from browser_use.tools.views import RestoreLoginAction
+from tests.ci.conftest import evaluate_js
+
+STORAGE_STATE = {
+	'cookies': [
+		{'name': 'session', 'value': 'gh-123', 'domain': '.github.com', 'path': '/', 'expires': -1},
+		{'name': 'sid', 'value': 'ex-456', 'domain': 'example.com', 'path': '/', 'expires': -1},
+	],
+	'origins': [
+		{'origin': 'https://github.com', 'localStorage': [{'name': 'theme', 'value': 'dark'}]},
+		{'origin': 'https://example.com', 'localStorage': [{'name': 'cart', 'value': '2'}]},
+	],
+}
+
+
+def test_vault_encrypts_and_filters_by_site(tmp_path):
+	vault = AuthVault(directory=tmp_path, key='correct horse battery staple')
+	entry = vault.save('github-work', STORAGE_STATE, sites=['https://www.github.com/login'])
+	assert entry.sites == ['github.com']
+	assert [c['name'] for c in entry.storage_state['cookies']] == ['session']
+	assert [o['origin'] for o in entry.storage_state['origins']] == ['https://github.com']
+
+	raw = (tmp_path / 'github-work.vault').read_text()
+	assert 'gh-123' not in raw and 'github.com' not in raw
+
+	assert vault.load('github-work').storage_state == entry.storage_state
+	assert [e.name for e in vault.find('https://gist.github.com/me')] == ['github-work']
+	assert vault.find('https://example.com') == []
+
+	# A subdomain login keeps the parent domain's cookies but is not offered on the parent domain
+	app_entry = vault.save('example-app', STORAGE_STATE, sites=['app.example.com'])
+	assert [c['name'] for c in app_entry.storage_state['cookies']] == ['sid']
+	assert app_entry.storage_state['origins'] == []
+	assert [e.name for e in vault.find('https://app.example.com/home')] == ['example-app']
+	assert vault.find('https://example.com') == []
+	assert vault.delete('example-app') is True
+
+	with pytest.raises(AuthVaultError, match='Could not decrypt'):
+		AuthVault(directory=tmp_path, key='wrong key').load('github-work')
+	with pytest.raises(AuthVaultError, match='No saved auth session'):
+		vault.load('gitlab')
+	with pytest.raises(AuthVaultError, match='Invalid auth session name'):
+		vault.save('../escape', STORAGE_STATE)
+
+	assert vault.delete('github-work') is True
+	assert vault.names() == []
+
+
+async def test_save_and_restore_auth(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/app').respond_with_data('<html><body>App</body></html>', content_type='text/html')
+	vault = AuthVault(directory=tmp_path, key='secret')
+	tools = Tools(auth_vault=vault)
+	url = httpserver.url_for('/app')
+
+	await tools.navigate(url=url, new_tab=False, browser_session=browser_session)
+	await evaluate_js(browser_session, "document.cookie = 'token=abc; path=/'; localStorage.setItem('user', 'ada')")
+	entry = await browser_session.save_auth('local-app', sites=[url], vault=vault)
+	assert entry.sites == ['localhost']
+	assert entry.cookies_count == 1 and entry.origins_count == 1
+
+	# Log out, then let the agent restore the session saved for the current site
+	await browser_session._cdp_clear_cookies()
+	await evaluate_js(browser_session, 'localStorage.clear()')
+	result = await tools.restore_login(params=RestoreLoginAction(), browser_session=browser_session)
+	assert result.error is None
+	assert result.metadata is not None and result.metadata['auth_session']['name'] == 'local-app'
+
+	assert await evaluate_js(browser_session, 'document.cookie') == 'token=abc'
+	assert await evaluate_js(browser_session, "localStorage.getItem('user')") == 'ada'
+
+	result = await tools.restore_login(params=RestoreLoginAction(name='unknown'), browser_session=browser_session)
+	assert result.error is not None and 'unknown' in result.error
+
+
+def test_restore_login_only_registered_with_a_vault(tmp_path, monkeypatch):
+	monkeypatch.delenv('BROWSER_USE_AUTH_VAULT_KEY', raising=False)
+	assert 'restore_login' not in Tools().registry.registry.actions
+	assert 'restore_login' in Tools(auth_vault=AuthVault(directory=tmp_path, key='secret')).registry.registry.actions
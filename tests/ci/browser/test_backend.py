@@ -0,0 +1,42 @@
+"""Tests for the BrowserBackend interface between the action layer and the browser."""
+
+from bubus import EventBus
+
+from browser_use.browser.backend import ACTION_EVENTS, BrowserBackend, missing_action_handlers
+from browser_use.browser.events import NavigateToUrlEvent
+from browser_use.tools.service import Tools
+
+
+class PartialBackend:
+	"""A backend that only implements navigation."""
+
+	def __init__(self):
+		self.event_bus = EventBus()
+		self.event_bus.on(NavigateToUrlEvent, self.on_NavigateToUrlEvent)
+
+	async def on_NavigateToUrlEvent(self, event: NavigateToUrlEvent) -> None:
+		pass
+
+
+async def test_browser_session_implements_backend(browser_session):
+	assert isinstance(browser_session, BrowserBackend)
+	assert missing_action_handlers(browser_session) == []
+
+
+def test_missing_action_handlers_of_partial_backend():
+	backend = PartialBackend()
+	assert not isinstance(backend, BrowserBackend)  # lacks the query methods
+	missing = missing_action_handlers(backend)  # type: ignore[arg-type]
+	assert 'NavigateToUrlEvent' not in missing and 'ClickElementEvent' in missing
+	assert len(missing) == len(ACTION_EVENTS) - 1
+
+
+async def test_action_typed_against_backend_gets_the_session(browser_session):
+	tools = Tools()
+
+	@tools.action('Report which tab the agent is on')
+	async def current_tab(browser_session: BrowserBackend):
+		return f'on {browser_session.agent_focus_target_id}'
+
+	result = await tools.registry.execute_action('current_tab', {}, browser_session=browser_session)
+	assert result == f'on {browser_session.agent_focus_target_id}'
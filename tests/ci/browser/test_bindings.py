@@ -0,0 +1,64 @@
+"""Tests for Runtime.addBinding based callbacks from page JavaScript to Python."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.cdp_events import BindingCall
+from browser_use.browser.events import NavigateToUrlEvent
+from tests.ci.conftest import evaluate_js
+
+
+async def test_page_calls_binding_across_navigations(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data('<h1>Instrumented</h1>', content_type='text/html')
+	calls: list[BindingCall] = []
+	received = asyncio.Event()
+
+	async def on_call(call: BindingCall) -> None:
+		calls.append(call)
+		received.set()
+
+	await browser_session.add_binding('notifyAgent', on_call)
+	with pytest.raises(ValueError):
+		await browser_session.add_binding('notifyAgent', on_call)
+
+	# The binding is still there after navigating
+	await browser_session.event_bus.dispatch(NavigateToUrlEvent(url=httpserver.url_for('/page')))
+	await evaluate_js(browser_session, 'window.notifyAgent(JSON.stringify({event: "ready", items: 3}))')
+	await asyncio.wait_for(received.wait(), timeout=5)
+	assert calls[0].name == 'notifyAgent' and calls[0].json() == {'event': 'ready', 'items': 3}
+	assert calls[0].target_id == browser_session.agent_focus_target_id
+
+	await browser_session.remove_binding('notifyAgent')
+	received.clear()
+	await evaluate_js(browser_session, 'window.notifyAgent("late")')
+	await asyncio.sleep(0.5)
+	assert len(calls) == 1
+
+
+async def test_wait_for_dom_stable(browser_session, httpserver: HTTPServer):
+	page = """
+	<ul id="feed"></ul>
+	<script>
+		let count = 0;
+		const timer = setInterval(() => {
+			document.getElementById('feed').insertAdjacentHTML('beforeend', '<li>item</li>');
+			if (++count === 5) clearInterval(timer);
+		}, 100);
+	</script>
+	"""
+	httpserver.expect_request('/feed').respond_with_data(page, content_type='text/html')
+	await browser_session.event_bus.dispatch(NavigateToUrlEvent(url=httpserver.url_for('/feed'), wait_until='commit'))
+
+	assert await browser_session.wait_for_dom_stable(quiet_ms=300, timeout=5)
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': 'document.querySelectorAll("#feed li").length', 'returnByValue': True},
+		session_id=cdp_session.session_id,
+	)
+	assert result['result']['value'] == 5
+
+	# A page that never settles times out
+	await evaluate_js(browser_session, 'setInterval(() => document.body.append("."), 50)')
+	assert not await browser_session.wait_for_dom_stable(quiet_ms=300, timeout=1)
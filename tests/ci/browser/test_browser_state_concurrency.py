@@ -0,0 +1,43 @@
+"""Tests that the browser state request fetches tabs, title and layout metrics concurrently with the DOM and screenshot."""
+
+import asyncio
+import time
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.browser.watchdogs.dom_watchdog import DOMWatchdog
+from browser_use.tools.service import Tools
+
+DELAY = 0.4
+
+
+def _slowed(method):
+	async def slow(*args, **kwargs):
+		await asyncio.sleep(DELAY)
+		return await method(*args, **kwargs)
+
+	return slow
+
+
+async def test_independent_state_calls_overlap(browser_session, httpserver: HTTPServer, monkeypatch):
+	httpserver.expect_request('/page').respond_with_data('<h1>State</h1><button>Go</button>', content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/page'), new_tab=False, browser_session=browser_session)
+
+	started = time.monotonic()
+	await browser_session.get_browser_state_summary()
+	baseline = time.monotonic() - started
+
+	monkeypatch.setattr(BrowserSession, 'get_tabs', _slowed(BrowserSession.get_tabs))
+	monkeypatch.setattr(BrowserSession, 'get_current_page_title', _slowed(BrowserSession.get_current_page_title))
+	monkeypatch.setattr(DOMWatchdog, '_get_page_info', _slowed(DOMWatchdog._get_page_info))
+
+	started = time.monotonic()
+	state = await browser_session.get_browser_state_summary()
+	slowed = time.monotonic() - started
+
+	assert state.title != 'Page'  # the fallback when the title isn't fetched within its timeout
+	assert state.page_info is not None and state.page_info.viewport_width > 0
+	assert any('Go' in (node.get_all_children_text() or '') for node in state.dom_state.selector_map.values())
+	# Three calls delayed by DELAY each: run one after another they would add 3 * DELAY
+	assert slowed - baseline < 2 * DELAY
@@ -0,0 +1,64 @@
+"""Tests for the public CDP event subscriptions of BrowserSession."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.cdp_events import CDPEvent, NetworkResponseReceived, RuntimeConsoleAPICalled
+from browser_use.browser.events import NavigateToUrlEvent
+
+
+async def test_subscribe_decode_and_unsubscribe(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data('<html><body>Hello</body></html>', content_type='text/html')
+	url = httpserver.url_for('/page')
+
+	responses: list[NetworkResponseReceived] = []
+	network_methods: list[str] = []
+	other_tab_events: list[CDPEvent] = []
+
+	async def on_network_event(event: CDPEvent) -> None:
+		network_methods.append(event.method)
+
+	def on_response(event: CDPEvent) -> None:
+		assert event.target_id == browser_session.agent_focus_target_id
+		responses.append(event.decode(NetworkResponseReceived))
+
+	response_id = browser_session.subscribe_cdp_event('Network.responseReceived', on_response)
+	wildcard_id = browser_session.subscribe_cdp_event('Network.*', on_network_event)
+	other_id = browser_session.subscribe_cdp_event('Network.*', other_tab_events.append, target_id='not-a-real-target')
+
+	await browser_session.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=False))
+	await asyncio.sleep(0.5)
+
+	page_responses = [response for response in responses if response.url == url]
+	assert len(page_responses) == 1
+	assert page_responses[0].status == 200
+	assert page_responses[0].mime_type == 'text/html'
+	assert {'Network.requestWillBeSent', 'Network.responseReceived'} <= set(network_methods)
+	assert other_tab_events == []
+
+	# Built-in handlers keep working next to the subscriptions
+	assert await browser_session.get_current_page_url() == url
+
+	for subscription_id in (response_id, wildcard_id, other_id):
+		browser_session.unsubscribe_cdp_event(subscription_id)
+	responses.clear()
+	await browser_session.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=False))
+	await asyncio.sleep(0.5)
+	assert responses == []
+
+	with pytest.raises(ValueError):
+		browser_session.unsubscribe_cdp_event(response_id)
+
+
+def test_decode_checks_the_method():
+	event = CDPEvent(
+		method='Runtime.consoleAPICalled',
+		params={'type': 'warning', 'args': [{'type': 'string', 'value': 'low'}, {'type': 'number', 'value': 3}]},
+	)
+	message = event.decode(RuntimeConsoleAPICalled)
+	assert (message.type, message.text) == ('warning', 'low 3')
+
+	with pytest.raises(ValueError, match='decodes Network.responseReceived events'):
+		event.decode(NetworkResponseReceived)
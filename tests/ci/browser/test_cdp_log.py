@@ -0,0 +1,53 @@
+"""Tests for the CDP wire log: method filtering, payload truncation and redaction of base64 blobs."""
+
+import base64
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.cdp_log import CDPTrafficLogger, redact_blobs
+from browser_use.tools.service import Tools
+
+
+def test_filters_truncates_and_redacts(tmp_path):
+	log = CDPTrafficLogger(
+		tmp_path / 'cdp.log', include=['Page.*', 'Runtime.*'], exclude=['Page.lifecycleEvent'], max_payload_chars=60
+	)
+	screenshot = base64.b64encode(b'\x89PNG' * 500).decode()
+	log.log_command('Page.captureScreenshot', {'format': 'png'}, 'SESSION-A1B2')
+	log.log_response('Page.captureScreenshot', {'data': screenshot}, 'SESSION-A1B2', 0.25)
+	log.log_event('Page.lifecycleEvent', {'name': 'load'}, 'SESSION-A1B2')
+	log.log_command('DOM.getDocument', {}, 'SESSION-A1B2')
+	log.log_command('Runtime.evaluate', {'expression': 'x' * 100}, None)
+	log.log_error('Runtime.evaluate', TimeoutError('no response'), None, 1.5)
+	log.close()
+
+	lines = (tmp_path / 'cdp.log').read_text().splitlines()
+	assert len(lines) == 4
+	assert lines[0].endswith('→ Page.captureScreenshot [A1B2] {"format": "png"}')
+	assert '← Page.captureScreenshot [A1B2] 250ms {"data": "<base64 2668 chars>"}' in lines[1]
+	assert lines[2].endswith('(118 chars)') and '→ Runtime.evaluate {"expression": "xxx' in lines[2]
+	assert lines[3].endswith('✖ Runtime.evaluate 1500ms TimeoutError: no response')
+
+	assert redact_blobs({'url': 'data:image/png;base64,' + screenshot})['url'] == '<data:image/png 2690 chars>'
+	assert redact_blobs({'text': 'hello world ' * 30})['text'] == 'hello world ' * 30
+
+
+async def test_session_writes_wire_log(httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/page').respond_with_data('<h1>Logged</h1>', content_type='text/html')
+	session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True, user_data_dir=None, cdp_log_dir=tmp_path, cdp_log_exclude=['Network.*', 'DOMSnapshot.*']
+		)
+	)
+	await session.start()
+	try:
+		await Tools().navigate(url=httpserver.url_for('/page'), new_tab=False, browser_session=session)
+		await session.take_screenshot()
+	finally:
+		await session.kill()
+
+	log = (tmp_path / f'cdp_{session.id}.log').read_text()
+	assert '→ Page.navigate' in log and '← Page.navigate' in log and '⚡ Page.frameNavigated' in log
+	assert '<base64 ' in log
+	assert 'Network.' not in log
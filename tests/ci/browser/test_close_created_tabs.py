@@ -0,0 +1,68 @@
+"""Tests for closing the tabs a session opened when it disconnects from a browser it doesn't own."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import NavigateToUrlEvent
+
+
+@pytest.fixture(scope='module')
+async def host_browser():
+	"""The browser we connect to over cdp_url, stands in for a remote browser someone else owns."""
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True))
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+@pytest.fixture
+def page_url(httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data('<html><body>Page</body></html>', content_type='text/html')
+	return httpserver.url_for('/page')
+
+
+async def _page_target_ids(host_browser: BrowserSession) -> set[str]:
+	targets = await host_browser.cdp_client.send.Target.getTargets()
+	return {target['targetId'] for target in targets['targetInfos'] if target['type'] == 'page'}
+
+
+async def _connect_and_open_tabs(host_browser: BrowserSession, page_url: str, **kwargs) -> BrowserSession:
+	session = BrowserSession(cdp_url=host_browser.cdp_url, **kwargs)
+	await session.start()
+	for _ in range(2):
+		await session.event_bus.dispatch(NavigateToUrlEvent(url=page_url, new_tab=True))
+	assert len(session.get_created_target_ids()) == 2
+	return session
+
+
+async def test_stop_closes_only_tabs_opened_by_the_session(host_browser, page_url):
+	tabs_before = await _page_target_ids(host_browser)
+	session = await _connect_and_open_tabs(host_browser, page_url)
+	assert session.is_local is False
+	assert len(await _page_target_ids(host_browser)) == len(tabs_before) + 2
+
+	await session.stop()
+	assert await _page_target_ids(host_browser) == tabs_before
+
+
+async def test_close_tabs_false_and_keep_alive_leave_tabs_open(host_browser, page_url):
+	tabs_before = await _page_target_ids(host_browser)
+
+	session = await _connect_and_open_tabs(host_browser, page_url)
+	created = set(session.get_created_target_ids())
+	await session.stop(close_tabs=False)
+	assert await _page_target_ids(host_browser) == tabs_before | created
+
+	# keep_alive leaves everything running unless closing tabs is requested explicitly
+	session = await _connect_and_open_tabs(host_browser, page_url, keep_alive=True)
+	kept_alive = set(session.get_created_target_ids())
+	await session.stop()
+	assert await _page_target_ids(host_browser) == tabs_before | created | kept_alive
+
+	session = BrowserSession(cdp_url=host_browser.cdp_url, keep_alive=True)
+	await session.start()
+	await session.event_bus.dispatch(NavigateToUrlEvent(url=page_url, new_tab=True))
+	await session.stop(close_tabs=True)
+	assert await _page_target_ids(host_browser) == tabs_before | created | kept_alive
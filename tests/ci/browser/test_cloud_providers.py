@@ -0,0 +1,114 @@
+"""Tests for the third-party hosted browser provider connectors (Browserbase, Steel, Anchor)."""
+
+import json
+from urllib.parse import parse_qs, urlparse
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.cloud.providers import AnchorProvider, BrowserbaseProvider, SteelProvider, get_cloud_provider
+from browser_use.browser.cloud.views import CloudBrowserAuthError, CloudBrowserError
+from browser_use.browser.session import BrowserSession
+
+
+@pytest.fixture
+def api_server():
+	server = HTTPServer()
+	server.start()
+	yield server
+	server.clear()
+	server.stop()
+
+
+def _api_url(server: HTTPServer) -> str:
+	return f'http://{server.host}:{server.port}'
+
+
+async def test_browserbase_creates_and_releases_session(api_server, monkeypatch):
+	monkeypatch.setenv('BROWSERBASE_PROJECT_ID', 'proj-1')
+	api_server.expect_request(
+		'/v1/sessions', method='POST', headers={'X-BB-API-Key': 'bb-key'}, json={'projectId': 'proj-1', 'keepAlive': True}
+	).respond_with_json({'id': 'bb-session', 'connectUrl': 'wss://connect.browserbase.com?signingKey=abc'})
+	api_server.expect_request('/v1/sessions/bb-session/debug', method='GET').respond_with_json(
+		{'debuggerFullscreenUrl': 'https://www.browserbase.com/devtools/bb-session'}
+	)
+	api_server.expect_request(
+		'/v1/sessions/bb-session', method='POST', json={'status': 'REQUEST_RELEASE', 'projectId': 'proj-1'}
+	).respond_with_json({'id': 'bb-session', 'status': 'COMPLETED'})
+
+	provider = BrowserbaseProvider(api_key='bb-key', api_base_url=_api_url(api_server))
+	session = await provider.create_session(keep_alive=True)
+	assert session.id == 'bb-session'
+	assert session.cdp_url == 'wss://connect.browserbase.com?signingKey=abc'
+	assert session.live_url == 'https://www.browserbase.com/devtools/bb-session'
+	assert 'signingKey' not in repr(session)
+
+	await provider.stop_session(session.id)
+	await provider.close()
+	api_server.check_assertions()
+
+
+async def test_steel_adds_api_key_and_session_to_websocket_url(api_server):
+	api_server.expect_request(
+		'/v1/sessions', method='POST', headers={'steel-api-key': 'steel-key'}, json={'solveCaptcha': True}
+	).respond_with_json({'id': 'steel-session', 'websocketUrl': 'wss://connect.steel.dev/', 'sessionViewerUrl': 'https://viewer'})
+
+	provider = SteelProvider(api_key='steel-key', params={'solveCaptcha': True}, api_base_url=_api_url(api_server))
+	session = await provider.create_session()
+	query = parse_qs(urlparse(session.cdp_url).query)
+	assert session.cdp_url.startswith('wss://connect.steel.dev/?')
+	assert query == {'apiKey': ['steel-key'], 'sessionId': ['steel-session']}
+	assert session.live_url == 'https://viewer'
+	await provider.close()
+
+
+async def test_anchor_session_and_error_handling(api_server, monkeypatch):
+	monkeypatch.setenv('ANCHOR_API_KEY', 'anchor-key')
+	api_server.expect_oneshot_request('/v1/sessions', method='POST').respond_with_json(
+		{'data': {'id': 'anchor-session', 'cdp_url': 'wss://connect.anchorbrowser.io?sessionId=anchor-session'}}
+	)
+	api_server.expect_oneshot_request('/v1/sessions', method='POST').respond_with_data('quota exceeded', status=429)
+	api_server.expect_oneshot_request('/v1/sessions', method='POST').respond_with_data('bad key', status=401)
+
+	provider = get_cloud_provider('anchor', api_base_url=_api_url(api_server))
+	assert isinstance(provider, AnchorProvider)
+	session = await provider.create_session()
+	assert session.cdp_url == 'wss://connect.anchorbrowser.io?sessionId=anchor-session'
+
+	with pytest.raises(CloudBrowserError, match='HTTP 429 - quota exceeded'):
+		await provider.create_session()
+	with pytest.raises(CloudBrowserAuthError):
+		await provider.create_session()
+	await provider.close()
+
+
+def test_provider_requires_api_key_and_known_name(monkeypatch):
+	monkeypatch.delenv('STEEL_API_KEY', raising=False)
+	with pytest.raises(CloudBrowserAuthError, match='STEEL_API_KEY'):
+		get_cloud_provider('steel')
+	with pytest.raises(ValueError, match='Unknown cloud browser provider'):
+		get_cloud_provider('hyperbrowser', api_key='key')
+
+
+async def test_browser_session_releases_provider_session_on_stop(api_server, monkeypatch):
+	monkeypatch.delenv('BROWSERBASE_PROJECT_ID', raising=False)
+	api_server.expect_request('/v1/sessions/bb-session', method='POST').respond_with_json({'id': 'bb-session'})
+
+	session = BrowserSession(cloud_provider='browserbase', cloud_provider_api_key='bb-key', headers={'X-Custom': '1'})
+	assert session.is_local is False
+
+	# Simulate what start() does without connecting to a real remote browser
+	api_server.expect_request('/v1/sessions', method='POST').respond_with_json(
+		{'id': 'bb-session', 'connectUrl': 'wss://connect.browserbase.com?signingKey=abc'}
+	)
+	monkeypatch.setattr(BrowserbaseProvider, 'default_api_base_url', _api_url(api_server))
+	await session._create_remote_browser_session()
+	assert session.cdp_url == 'wss://connect.browserbase.com?signingKey=abc'
+	assert session.browser_profile.headers == {'X-Custom': '1'}
+
+	await session._stop_remote_browser_session()
+	assert session.cdp_url is None
+	assert session._remote_browser_session is None
+	release_requests = [req for req, _ in api_server.log if req.path == '/v1/sessions/bb-session']
+	assert len(release_requests) == 1
+	assert json.loads(release_requests[0].data) == {'status': 'REQUEST_RELEASE'}
@@ -0,0 +1,77 @@
+"""Tests for attaching a session to a specific open tab of an existing browser, optionally restricted to it."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import NavigateToUrlEvent
+from browser_use.tools.service import Tools
+
+
+@pytest.fixture(scope='module')
+async def host_browser():
+	"""The browser we connect to over cdp_url, stands in for an operator's browser with tabs already open."""
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True))
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+@pytest.fixture
+async def operator_tabs(host_browser: BrowserSession, httpserver: HTTPServer) -> dict[str, str]:
+	"""Open a dashboard and an inbox tab in the host browser, returns their target ids by name."""
+	for name, title in (('dashboard', 'Operator Dashboard'), ('inbox', 'Inbox')):
+		httpserver.expect_request(f'/{name}').respond_with_data(
+			f'<html><head><title>{title}</title></head><body>{title}</body></html>', content_type='text/html'
+		)
+	httpserver.expect_request('/other').respond_with_data('<html><body>Other</body></html>', content_type='text/html')
+
+	target_ids = {}
+	for name in ('dashboard', 'inbox'):
+		await host_browser.event_bus.dispatch(NavigateToUrlEvent(url=httpserver.url_for(f'/{name}'), new_tab=True))
+		assert host_browser.agent_focus_target_id is not None
+		target_ids[name] = host_browser.agent_focus_target_id
+	return target_ids
+
+
+async def test_attach_by_url_title_or_target_id(host_browser, operator_tabs):
+	for options, expected in (
+		({'target_url_pattern': '*/dashboard'}, 'dashboard'),
+		({'target_title_pattern': 'inbox'}, 'inbox'),
+		({'target_id': operator_tabs['dashboard']}, 'dashboard'),
+	):
+		session = BrowserSession(cdp_url=host_browser.cdp_url, **options)
+		await session.start()
+		try:
+			assert session.agent_focus_target_id == operator_tabs[expected]
+			assert session.pinned_target_id is None
+			assert len(await session.get_tabs()) >= 2
+		finally:
+			await session.stop()
+
+	session = BrowserSession(cdp_url=host_browser.cdp_url, target_title_pattern='Billing')
+	with pytest.raises(Exception, match='No open tab matches'):
+		await session.start()
+	await session.stop()
+
+
+async def test_restrict_to_target(host_browser, operator_tabs, httpserver: HTTPServer):
+	session = BrowserSession(cdp_url=host_browser.cdp_url, target_url_pattern='/dashboard', restrict_to_target=True)
+	await session.start()
+	try:
+		dashboard_id = operator_tabs['dashboard']
+		assert session.pinned_target_id == dashboard_id
+		assert [tab.target_id for tab in await session.get_tabs()] == [dashboard_id]
+
+		# new_tab navigations stay in the attached tab
+		await session.event_bus.dispatch(NavigateToUrlEvent(url=httpserver.url_for('/other'), new_tab=True))
+		assert session.agent_focus_target_id == dashboard_id
+		assert session.get_created_target_ids() == []
+		assert await session.get_current_page_url() == httpserver.url_for('/other')
+
+		result = await Tools().switch(tab_id=operator_tabs['inbox'][-4:], browser_session=session)
+		assert result.error is not None and 'restricted' in result.error
+		assert session.agent_focus_target_id == dashboard_id
+	finally:
+		await session.stop()
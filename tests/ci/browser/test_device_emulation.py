@@ -0,0 +1,76 @@
+"""Tests for device emulation presets: screen metrics, touch emulation, user agent and touch gestures."""
+
+import pytest
+from pydantic import ValidationError
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import ClickCoordinateEvent, ScrollEvent
+from browser_use.browser.profile import DEVICE_PRESETS, ViewportSize
+from browser_use.tools.service import Tools
+from tests.ci.conftest import evaluate_js
+
+TOUCH_PAGE = """
+<!DOCTYPE html>
+<html>
+<head><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body style="margin: 0">
+	<button id="tap" style="width: 200px; height: 60px" onclick="window.clicks = (window.clicks || 0) + 1">Tap me</button>
+	<div style="height: 5000px">Long page</div>
+	<script>
+		window.touches = 0;
+		document.addEventListener('touchstart', () => window.touches++);
+	</script>
+</body>
+</html>
+"""
+
+
+@pytest.fixture(scope='module')
+async def browser_session():
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True, device='Pixel 7'))
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+def test_device_preset_fills_profile():
+	profile = BrowserProfile(headless=True, device='iphone 15')
+	preset = DEVICE_PRESETS['iPhone 15']
+	assert profile.device == 'iPhone 15'
+	assert profile.viewport == preset.viewport
+	assert profile.device_scale_factor == 3
+	assert profile.is_mobile and profile.has_touch
+	assert profile.user_agent is not None and 'iPhone' in profile.user_agent
+
+	# Explicit values win over the preset
+	profile = BrowserProfile(headless=True, device='iPad Mini', viewport=ViewportSize(width=1024, height=768), has_touch=False)
+	assert profile.viewport == ViewportSize(width=1024, height=768)
+	assert profile.has_touch is False
+	assert profile.is_mobile is True
+
+	with pytest.raises(ValidationError):
+		BrowserProfile(headless=True, device='Nokia 3310')
+
+
+async def test_device_emulation_and_touch_gestures(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/touch').respond_with_data(TOUCH_PAGE, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/touch'), new_tab=False, browser_session=browser_session)
+
+	assert await evaluate_js(browser_session, 'window.innerWidth') == 412
+	assert await evaluate_js(browser_session, 'window.devicePixelRatio') == 2.625
+	assert await evaluate_js(browser_session, 'navigator.maxTouchPoints') > 0
+	assert 'Pixel 7' in await evaluate_js(browser_session, 'navigator.userAgent')
+
+	# Clicks are sent as taps: the page sees a touchstart and the resulting click
+	event = browser_session.event_bus.dispatch(ClickCoordinateEvent(coordinate_x=100, coordinate_y=30, force=True))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+	assert await evaluate_js(browser_session, 'window.touches') >= 1
+	assert await evaluate_js(browser_session, 'window.clicks') == 1
+
+	event = browser_session.event_bus.dispatch(ScrollEvent(direction='down', amount=500))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+	assert await evaluate_js(browser_session, 'window.scrollY') > 0
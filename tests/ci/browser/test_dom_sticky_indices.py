@@ -0,0 +1,81 @@
+"""Element indices stay the same across steps while an element, or an identical one replacing it, is on the page."""
+
+from browser_use.dom.serializer.serializer import DOMTreeSerializer
+from browser_use.dom.views import DOMRect, EnhancedDOMTreeNode, EnhancedSnapshotNode, NodeType, SerializedDOMState
+
+
+def _node(tag_name: str, backend_node_id: int, attributes: dict[str, str] | None = None) -> EnhancedDOMTreeNode:
+	return EnhancedDOMTreeNode(
+		node_id=backend_node_id,
+		backend_node_id=backend_node_id,
+		node_type=NodeType.ELEMENT_NODE,
+		node_name=tag_name.upper(),
+		node_value='',
+		attributes=attributes or {},
+		is_scrollable=False,
+		is_visible=True,
+		absolute_position=DOMRect(x=0, y=0, width=100, height=30),
+		target_id='target-main',
+		frame_id=None,
+		session_id='main',
+		content_document=None,
+		shadow_root_type=None,
+		shadow_roots=None,
+		parent_node=None,
+		children_nodes=[],
+		ax_node=None,
+		snapshot_node=EnhancedSnapshotNode(
+			is_clickable=None,
+			cursor_style='auto',
+			bounds=DOMRect(x=0, y=0, width=100, height=30),
+			clientRects=DOMRect(x=0, y=0, width=100, height=30),
+			scrollRects=None,
+			computed_styles={'display': 'block', 'visibility': 'visible', 'opacity': '1'},
+			paint_order=None,
+			stacking_contexts=None,
+		),
+	)
+
+
+def _serialize(
+	document_id: int, children: list[EnhancedDOMTreeNode], previous: SerializedDOMState | None = None
+) -> SerializedDOMState:
+	root = _node('html', document_id)
+	root.children_nodes = children
+	for child in children:
+		child.parent_node = root
+	return DOMTreeSerializer(
+		root, previous, enable_bbox_filtering=False, paint_order_filtering=False
+	).serialize_accessible_elements()[0]
+
+
+def _indices(state: SerializedDOMState) -> dict[str, int]:
+	return {node.attributes.get('name', ''): index for index, node in state.selector_map.items()}
+
+
+def test_replaced_element_keeps_its_index():
+	first = _serialize(100, [_node('input', 5, {'name': 'email'}), _node('input', 6, {'name': 'password'})])
+	assert _indices(first) == {'email': 5, 'password': 6}
+
+	# The email input was re-rendered (new backend node), a search input was added
+	inputs = [_node('input', 7, {'name': 'email'}), _node('input', 6, {'name': 'password'}), _node('input', 8, {'name': 'q'})]
+	second = _serialize(100, inputs, first)
+	assert _indices(second) == {'email': 5, 'password': 6, 'q': 8}
+	text = second.llm_representation()
+	assert '*[8]' in text and '*[5]' not in text
+
+	# The index stays with the element on the following steps
+	third = _serialize(100, [_node('input', 7, {'name': 'email'})], second)
+	assert _indices(third) == {'email': 5}
+
+
+def test_indices_are_not_reused_across_documents_or_for_ambiguous_elements():
+	first = _serialize(100, [_node('input', 5, {'name': 'email'}), _node('button', 10), _node('button', 11)])
+
+	# Identical buttons can't be told apart, they get fresh indices
+	second = _serialize(100, [_node('button', 12), _node('button', 13)], first)
+	assert sorted(second.selector_map) == [12, 13]
+
+	# After a navigation (new document) elements get fresh indices even if they look the same
+	after_navigation = _serialize(200, [_node('input', 9, {'name': 'email'})], first)
+	assert _indices(after_navigation) == {'email': 9}
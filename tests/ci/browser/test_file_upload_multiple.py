@@ -0,0 +1,68 @@
+"""Tests for uploading several files and directories with upload_file."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.tools.service import Tools
+
+PAGE = """
+<input type="file" id="single">
+<input type="file" id="multiple" multiple>
+<input type="file" id="directory" webkitdirectory>
+"""
+
+
+async def _file_names(browser_session, input_id: str) -> list[str]:
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': f'[...document.getElementById("{input_id}").files].map(f => f.name)', 'returnByValue': True},
+		session_id=cdp_session.session_id,
+	)
+	return result['result']['value']
+
+
+async def test_upload_several_files_and_a_directory(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/upload').respond_with_data(PAGE, content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/upload'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary(include_screenshot=False)
+
+	first, second = tmp_path / 'a.txt', tmp_path / 'b.txt'
+	first.write_text('first')
+	second.write_text('second')
+	photos = tmp_path / 'photos'
+	(photos / 'trip').mkdir(parents=True)
+	(photos / 'one.jpg').write_bytes(b'jpeg')
+	(photos / 'trip' / 'two.jpg').write_bytes(b'jpeg')
+	(photos / '.DS_Store').write_bytes(b'junk')
+	available = [str(first), str(second), str(photos)]
+	file_system = FileSystem(base_dir=tmp_path / 'fs')
+
+	async def upload(input_id: str, path):
+		index = await browser_session.get_index_by_id(input_id)
+		assert index is not None
+		return await tools.upload_file(
+			index=index,
+			path=path,
+			browser_session=browser_session,
+			available_file_paths=available,
+			file_system=file_system,
+		)
+
+	result = await upload('multiple', [str(first), str(second)])
+	assert result.error is None and '2 files' in (result.extracted_content or '')
+	assert await _file_names(browser_session, 'multiple') == ['a.txt', 'b.txt']
+
+	result = await upload('directory', str(photos))
+	assert result.error is None
+	assert sorted(await _file_names(browser_session, 'directory')) == ['one.jpg', 'two.jpg']
+
+	# A plain file input takes one file
+	result = await upload('single', [str(first), str(second)])
+	assert result.error is not None and 'single file' in result.error
+	assert await _file_names(browser_session, 'single') == []
+
+	(tmp_path / 'empty.txt').write_text('')
+	available.append(str(tmp_path / 'empty.txt'))
+	result = await upload('multiple', [str(first), str(tmp_path / 'empty.txt')])
+	assert result.error is not None and 'empty' in result.error
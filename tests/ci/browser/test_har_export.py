@@ -0,0 +1,151 @@
+"""Tests for HAR export of recorded network activity (record_har, export_har, network.har in run artifacts)."""
+
+import asyncio
+import json
+import time
+
+import pytest
+
+from browser_use.agent.service import Agent
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.tools.service import Tools
+from tests.ci.conftest import create_mock_llm
+
+
+@pytest.fixture(scope='module')
+async def har_session():
+	session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True, user_data_dir=None, keep_alive=True, record_har=True, record_har_max_body_size=100
+		)
+	)
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+def _record(
+	watchdog, request_id: str, url: str, body: str, wall_time: float, request: dict | None = None, response_headers=None
+) -> None:
+	"""Feed the CDP events of one finished HTTPS request to the watchdog, as the browser would send them."""
+	watchdog._on_request_will_be_sent(
+		{
+			'requestId': request_id,
+			'frameId': 'frame-1',
+			'type': 'Document' if request_id == 'doc' else 'Fetch',
+			'timestamp': 1.0,
+			'wallTime': wall_time,
+			'request': {'url': url, 'method': 'GET', 'headers': {'Accept': '*/*'}, **(request or {})},
+		},
+		None,
+	)
+	watchdog._on_response_received(
+		{
+			'requestId': request_id,
+			'timestamp': 1.1,
+			'response': {
+				'status': 200,
+				'statusText': 'OK',
+				'mimeType': 'text/plain',
+				'headers': response_headers or {},
+				'protocol': 'h2',
+			},
+		},
+		None,
+	)
+	watchdog._on_data_received({'requestId': request_id, 'data': body}, None)
+	watchdog._on_loading_finished({'requestId': request_id, 'timestamp': 1.2, 'encodedDataLength': len(body)}, None)
+
+
+async def test_export_har_filters_by_time_and_caps_bodies(har_session, tmp_path):
+	assert har_session.is_recording_har
+	watchdog = har_session._har_recording_watchdog
+	now = time.time()
+	_record(watchdog, 'old', 'https://example.com/old', 'before the run', now - 600)
+	_record(watchdog, 'doc', 'https://example.com/', 'hello', now)
+	_record(watchdog, 'big', 'https://example.com/big', 'x' * 500, now)
+
+	har_path = har_session.export_har(tmp_path / 'run.har', since=now - 60)
+	har = json.loads(har_path.read_text())['log']
+	assert har['version'] == '1.2'
+	entries = {entry['request']['url']: entry for entry in har['entries']}
+	assert set(entries) == {'https://example.com/', 'https://example.com/big'}
+	assert entries['https://example.com/']['response']['content']['text'] == 'hello'
+	assert entries['https://example.com/']['pageref'] == 'page@frame-1'
+	big_content = entries['https://example.com/big']['response']['content']
+	assert 'text' not in big_content and 'record_har_max_body_size' in big_content['comment']
+	assert [page['id'] for page in har['pages']] == ['page@frame-1']
+
+	everything = json.loads(har_session.export_har(tmp_path / 'all.har').read_text())['log']
+	assert len(everything['entries']) == 3
+
+
+async def test_export_har_masks_credentials_and_sensitive_data(har_session, tmp_path):
+	watchdog = har_session._har_recording_watchdog
+	now = time.time()
+	_record(
+		watchdog,
+		'login',
+		'https://example.com/login?user=ada&pin=p%40ss',
+		'welcome ada',
+		now,
+		request={
+			'method': 'POST',
+			'headers': {'Authorization': 'Bearer abc', 'Cookie': 'sid=1'},
+			'postData': 'password=p%40ss',
+		},
+		response_headers={'Set-Cookie': 'sid=2'},
+	)
+
+	har_path = har_session.export_har(tmp_path / 'login.har', since=now, sensitive_data={'pin': 'p@ss'})
+	[entry] = json.loads(har_path.read_text())['log']['entries']
+	assert entry['request']['url'] == 'https://example.com/login?user=ada&pin=<secret>pin</secret>'
+	headers = {header['name']: header['value'] for header in entry['request']['headers']}
+	assert headers['authorization'] == headers['cookie'] == '<redacted>'
+	assert entry['response']['headers'] == [{'name': 'set-cookie', 'value': '<redacted>'}]
+	assert entry['request']['postData']['text'] == ''
+	assert 'p@ss' not in har_path.read_text() and 'p%40ss' not in har_path.read_text()
+
+
+async def test_har_entries_are_capped(har_session, monkeypatch):
+	from browser_use.browser.watchdogs import har_recording_watchdog
+
+	watchdog = har_session._har_recording_watchdog
+	monkeypatch.setattr(har_recording_watchdog, 'MAX_HAR_ENTRIES', 3)
+	now = time.time()
+	for n in range(5):
+		_record(watchdog, f'capped-{n}', f'https://example.com/{n}', 'x', now)
+	assert list(watchdog._entries)[-3:] == ['capped-2', 'capped-3', 'capped-4']
+	assert len(watchdog._entries) == 3
+
+
+async def test_agent_artifacts_include_network_har(har_session, tmp_path):
+	agent = Agent(task='Nothing to do', llm=create_mock_llm(), browser_session=har_session)
+	bundle = agent.export_artifacts(tmp_path / 'bundle')
+	har = json.loads((bundle / 'network.har').read_text())
+	assert har['log']['creator']['name'] == 'browser-use'
+
+
+async def test_export_har_requires_recording(browser_session, tmp_path):
+	assert not browser_session.is_recording_har
+	with pytest.raises(RuntimeError):
+		browser_session.export_har(tmp_path / 'run.har')
+
+
+async def test_network_capture_runs_alongside_har_recording(har_session, httpserver):
+	httpserver.expect_request('/app').respond_with_data("<script>fetch('/api/ping')</script>", content_type='text/html')
+	httpserver.expect_request('/api/ping').respond_with_json({'ok': True})
+
+	await har_session.enable_network_capture(patterns=['*/api/*'])
+	try:
+		await Tools().navigate(url=httpserver.url_for('/app'), new_tab=False, browser_session=har_session)
+		for _ in range(50):
+			if har_session.get_network_requests():
+				break
+			await asyncio.sleep(0.1)
+		[request] = har_session.get_network_requests()
+		assert request.url.endswith('/api/ping') and request.status == 200
+		assert har_session.is_recording_har
+	finally:
+		har_session.disable_network_capture()
@@ -0,0 +1,53 @@
+"""Tests for answering HTTP basic auth challenges with set_http_credentials()."""
+
+import base64
+from urllib.parse import urlparse
+
+import pytest
+from pytest_httpserver import HTTPServer
+from werkzeug import Response
+
+from browser_use.tools.service import Tools
+
+
+def _basic_auth(request):
+	expected = 'Basic ' + base64.b64encode(b'admin:hunter2').decode()
+	if request.headers.get('Authorization') != expected:
+		return Response('Login required', status=401, headers={'WWW-Authenticate': 'Basic realm="internal"'})
+	return Response('<h1>Internal dashboard</h1>', content_type='text/html')
+
+
+def _origin(url: str) -> str:
+	return '{0.scheme}://{0.netloc}'.format(urlparse(url))
+
+
+async def _page_text(browser_session, url: str) -> str:
+	await Tools().navigate(url=url, new_tab=False, browser_session=browser_session)
+	return await browser_session.get_page_text(max_chars=None, main_content_only=False)
+
+
+async def test_basic_auth_with_matching_credentials(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/dashboard').respond_with_handler(_basic_auth)
+	url = httpserver.url_for('/dashboard')
+
+	# Credentials for another origin are not sent
+	await browser_session.set_http_credentials('admin', 'hunter2', 'https://other.example.com')
+	assert 'Internal dashboard' not in await _page_text(browser_session, url)
+
+	await browser_session.set_http_credentials('admin', 'hunter2', _origin(url))
+	assert 'Internal dashboard' in await _page_text(browser_session, url)
+
+
+async def test_wrong_credentials_are_not_retried_forever(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/dashboard').respond_with_handler(_basic_auth)
+	url = httpserver.url_for('/dashboard')
+	# Replaces the working credentials of the previous test for this origin
+	await browser_session.set_http_credentials('admin', 'wrong', _origin(url))
+
+	assert 'Internal dashboard' not in await _page_text(browser_session, url)
+
+
+async def test_origin_pattern_must_name_an_origin(browser_session):
+	for pattern in ('*', '', 'intranet.example.com', 'https://*', 'https://example.com/admin'):
+		with pytest.raises(ValueError, match='origin_pattern must be an origin'):
+			await browser_session.set_http_credentials('admin', 'hunter2', pattern)
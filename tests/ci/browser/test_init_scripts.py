@@ -0,0 +1,50 @@
+"""Tests for init scripts that are evaluated before page scripts and survive navigations."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.browser.events import NavigateToUrlEvent
+from tests.ci.conftest import evaluate_js
+
+PAGE = '<html><head><script>window.seenMarker = window.__marker === true;</script></head><body>page</body></html>'
+
+
+async def _navigate(browser_session: BrowserSession, url: str, new_tab: bool = False) -> None:
+	event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=new_tab))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+
+
+async def test_page_init_script_survives_navigation(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data(PAGE, content_type='text/html')
+	url = httpserver.url_for('/page')
+	await _navigate(browser_session, url)
+
+	page = await browser_session.must_get_current_page()
+	identifier = await page.add_init_script('window.__marker = true;')
+	# Runs right away in the current document, and before page scripts after every navigation
+	assert await evaluate_js(browser_session, 'window.__marker') is True
+	await _navigate(browser_session, url)
+	assert await evaluate_js(browser_session, 'window.seenMarker') is True
+
+	await page.remove_init_script(identifier)
+	await _navigate(browser_session, url)
+	assert await evaluate_js(browser_session, 'window.seenMarker') is False
+
+
+async def test_session_init_script_applies_to_new_tabs(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data(PAGE, content_type='text/html')
+	url = httpserver.url_for('/page')
+
+	script_id = await browser_session.add_init_script('window.__marker = true;')
+	try:
+		await _navigate(browser_session, url)
+		assert await evaluate_js(browser_session, 'window.seenMarker') is True
+
+		await _navigate(browser_session, url, new_tab=True)
+		assert await evaluate_js(browser_session, 'window.seenMarker') is True
+	finally:
+		await browser_session.remove_init_script(script_id)
+
+	await _navigate(browser_session, url)
+	assert await evaluate_js(browser_session, 'window.seenMarker') is False
@@ -0,0 +1,74 @@
+"""Tests for isolated browser contexts: sessions sharing one browser without sharing cookies or tabs."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import NavigateToUrlEvent
+
+
+@pytest.fixture(scope='module')
+async def host_browser():
+	"""The browser several sessions connect to over cdp_url."""
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True))
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+@pytest.fixture
+def login_url(httpserver: HTTPServer):
+	httpserver.expect_request('/login').respond_with_data(
+		'<html><body>Logged in</body></html>', content_type='text/html', headers={'Set-Cookie': 'sid=secret; Path=/'}
+	)
+	return httpserver.url_for('/login')
+
+
+async def _connect(host_browser: BrowserSession, **kwargs) -> BrowserSession:
+	session = BrowserSession(cdp_url=host_browser.cdp_url, **kwargs)
+	await session.start()
+	return session
+
+
+async def _context_ids(host_browser: BrowserSession) -> list[str]:
+	return (await host_browser.cdp_client.send.Target.getBrowserContexts())['browserContextIds']
+
+
+async def test_isolated_sessions_do_not_share_cookies_or_tabs(host_browser, login_url):
+	first = await _connect(host_browser, isolated_context=True)
+	second = await _connect(host_browser, isolated_context=True)
+	try:
+		assert first.browser_context_id and second.browser_context_id
+		assert first.browser_context_id != second.browser_context_id
+
+		await first.event_bus.dispatch(NavigateToUrlEvent(url=login_url))
+		assert any(cookie['name'] == 'sid' for cookie in await first.cookies())
+		assert not any(cookie['name'] == 'sid' for cookie in await second.cookies())
+		assert not any(cookie['name'] == 'sid' for cookie in await host_browser.cookies())
+
+		# Each session only sees the tabs of its own context
+		first_tabs = {tab.target_id for tab in await first.get_tabs()}
+		second_tabs = {tab.target_id for tab in await second.get_tabs()}
+		assert first_tabs and second_tabs and not first_tabs & second_tabs
+	finally:
+		context_id = first.browser_context_id
+		await first.stop()
+		await second.stop()
+
+	assert context_id not in await _context_ids(host_browser)
+
+
+async def test_named_context_is_reused(host_browser, login_url):
+	first = await _connect(host_browser, browser_context_name='shop-account')
+	await first.event_bus.dispatch(NavigateToUrlEvent(url=login_url))
+	context_id = first.browser_context_id
+	await first.stop()
+	assert context_id in await _context_ids(host_browser)
+
+	second = await _connect(host_browser, browser_context_name='shop-account')
+	try:
+		assert second.browser_context_id == context_id
+		assert any(cookie['name'] == 'sid' for cookie in await second.cookies())
+	finally:
+		await second.stop()
@@ -0,0 +1,78 @@
+"""Tests for large CDP messages: WebSocket compression, the message size limit and chunked full-page screenshots."""
+
+import json
+from io import BytesIO
+
+import pytest
+from PIL import Image
+from pytest_httpserver import HTTPServer
+from websockets.asyncio.server import serve
+
+from browser_use.browser import session as session_module
+from browser_use.browser._cdp_timeout import TimeoutWrappedCDPClient
+from browser_use.tools.service import Tools
+
+SCREENSHOT_SIZE = 5 * 1024 * 1024
+
+
+async def _answer_with_screenshot(websocket):
+	async for message in websocket:
+		request = json.loads(message)
+		await websocket.send(json.dumps({'id': request['id'], 'result': {'data': 'A' * SCREENSHOT_SIZE}}))
+
+
+async def test_compressed_connection_and_message_size_limit():
+	async with serve(_answer_with_screenshot, 'localhost', 0, max_size=None) as server:
+		port = server.sockets[0].getsockname()[1]
+
+		client = TimeoutWrappedCDPClient(f'ws://localhost:{port}', max_ws_frame_size=None)
+		await client.start()
+		try:
+			assert client.ws is not None
+			assert [extension.name for extension in client.ws.protocol.extensions] == ['permessage-deflate']
+			result = await client.send_raw('Page.captureScreenshot')
+			assert len(result['data']) == SCREENSHOT_SIZE
+		finally:
+			await client.stop()
+
+		client = TimeoutWrappedCDPClient(f'ws://localhost:{port}', max_ws_frame_size=1024 * 1024, cdp_request_timeout_s=3)
+		await client.start()
+		try:
+			assert client.ws is not None
+			with pytest.raises(Exception):
+				await client.send_raw('Page.captureScreenshot')
+			assert client.ws.protocol.close_sent is not None and client.ws.protocol.close_sent.code == 1009
+		finally:
+			await client.stop()
+
+
+async def test_full_page_screenshot_of_tall_page_is_stitched(browser_session, httpserver: HTTPServer, monkeypatch):
+	monkeypatch.setattr(session_module, 'SCREENSHOT_CHUNK_HEIGHT', 1000)
+	httpserver.expect_request('/tall').respond_with_data(
+		'<body style="margin: 0"><div style="height: 2500px; background: rgb(255, 0, 0)"></div>'
+		'<div style="height: 500px; background: rgb(0, 0, 255)"></div></body>',
+		content_type='text/html',
+	)
+	await Tools().navigate(url=httpserver.url_for('/tall'), new_tab=False, browser_session=browser_session)
+
+	image = Image.open(BytesIO(await browser_session.take_screenshot(full_page=True))).convert('RGB')
+	scale = image.height / 3000
+	assert image.getpixel((10, round(2400 * scale))) == (255, 0, 0)
+	assert image.getpixel((10, round(2600 * scale))) == (0, 0, 255)
+	assert image.getpixel((10, image.height - 1)) == (0, 0, 255)
+
+
+async def test_full_page_screenshot_is_cut_off_at_max_height(browser_session, httpserver: HTTPServer, monkeypatch):
+	monkeypatch.setattr(session_module, 'SCREENSHOT_CHUNK_HEIGHT', 1000)
+	monkeypatch.setattr(session_module, 'SCREENSHOT_MAX_HEIGHT', 2000)
+	httpserver.expect_request('/endless').respond_with_data(
+		'<body style="margin: 0"><div style="height: 5000px; background: rgb(0, 128, 0)"></div></body>',
+		content_type='text/html',
+	)
+	await Tools().navigate(url=httpserver.url_for('/endless'), new_tab=False, browser_session=browser_session)
+
+	image = Image.open(BytesIO(await browser_session.take_screenshot(full_page=True)))
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	metrics = await cdp_session.cdp_client.send.Page.getLayoutMetrics(session_id=cdp_session.session_id)
+	scale = image.width / metrics['cssContentSize']['width']
+	assert image.height == round(2000 * scale)
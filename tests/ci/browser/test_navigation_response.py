@@ -0,0 +1,39 @@
+"""Tests for the navigation response reported by the navigate action: final URL, HTTP status and redirect chain."""
+
+from pytest_httpserver import HTTPServer
+from werkzeug import Response
+
+from browser_use.browser.events import NavigateToUrlEvent, NavigationResult
+from browser_use.tools.service import Tools
+
+
+async def test_redirect_chain_and_final_url(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/old').respond_with_response(Response(status=301, headers={'Location': '/moved'}))
+	httpserver.expect_request('/moved').respond_with_response(Response(status=302, headers={'Location': '/new'}))
+	httpserver.expect_request('/new').respond_with_data('<h1>New home</h1>', content_type='text/html')
+
+	event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=httpserver.url_for('/old')))
+	await event
+	navigation = await event.event_result()
+	assert isinstance(navigation, NavigationResult)
+	assert navigation.status == 200 and navigation.final_url == httpserver.url_for('/new')
+	assert navigation.redirect_chain == [httpserver.url_for('/old'), httpserver.url_for('/moved')]
+
+	result = await Tools().navigate(url=httpserver.url_for('/old'), new_tab=False, browser_session=browser_session)
+	assert result.error is None
+	assert httpserver.url_for('/new') in (result.long_term_memory or '') and 'HTTP 200' in (result.long_term_memory or '')
+	assert result.metadata and result.metadata['navigation']['redirect_chain'][0] == httpserver.url_for('/old')
+
+
+async def test_http_errors_fail_with_actionable_message(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/missing').respond_with_data('<h1>Not here</h1>', status=404, content_type='text/html')
+	httpserver.expect_request('/broken').respond_with_data('<h1>Oops</h1>', status=503, content_type='text/html')
+	tools = Tools()
+
+	result = await tools.navigate(url=httpserver.url_for('/missing'), new_tab=False, browser_session=browser_session)
+	assert result.error and 'HTTP 404' in result.error and 'does not exist' in result.error
+
+	result = await tools.navigate(
+		url=httpserver.url_for('/broken'), new_tab=False, wait_until='domcontentloaded', browser_session=browser_session
+	)
+	assert result.error and 'HTTP 503' in result.error and 'Retry later' in result.error
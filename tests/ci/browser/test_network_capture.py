@@ -0,0 +1,98 @@
+"""Tests for capturing XHR/fetch responses and reading them with the get_network_requests action."""
+
+import asyncio
+import json
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.tools.service import Tools
+
+APP_PAGE = """
+<!DOCTYPE html>
+<html>
+<body>
+	<ul id="products"></ul>
+	<img src="/logo.png">
+	<script>
+		fetch('/api/products?page=1')
+			.then((response) => response.json())
+			.then((data) => {
+				for (const product of data.products) {
+					const item = document.createElement('li');
+					item.textContent = product.name;
+					document.getElementById('products').appendChild(item);
+				}
+			});
+		fetch('/api/missing');
+	</script>
+</body>
+</html>
+"""
+
+PRODUCTS = {'products': [{'name': 'Blue mug', 'price': 12.5}, {'name': 'Red mug', 'price': 11}]}
+
+
+@pytest.fixture
+def app_url(httpserver: HTTPServer):
+	httpserver.expect_request('/app').respond_with_data(APP_PAGE, content_type='text/html')
+	httpserver.expect_request('/api/products').respond_with_json(PRODUCTS)
+	httpserver.expect_request('/api/missing').respond_with_data('not found', status=404, content_type='text/plain')
+	httpserver.expect_request('/logo.png').respond_with_data(b'\x89PNG', content_type='image/png')
+	return httpserver.url_for('/app')
+
+
+async def _wait_for_requests(browser_session: BrowserSession, count: int) -> None:
+	for _ in range(50):
+		if len(browser_session.get_network_requests()) >= count:
+			return
+		await asyncio.sleep(0.1)
+
+
+async def test_capture_records_fetch_responses(browser_session, app_url):
+	browser_session.clear_network_requests()
+	await browser_session.enable_network_capture(patterns=['*/api/*'])
+	try:
+		await Tools().navigate(url=app_url, new_tab=False, browser_session=browser_session)
+		await _wait_for_requests(browser_session, 2)
+
+		requests = {request.url.split('/')[-1]: request for request in browser_session.get_network_requests()}
+		assert set(requests) == {'products?page=1', 'missing'}  # the page and the image are not XHR/fetch
+
+		products = requests['products?page=1']
+		assert products.method == 'GET'
+		assert products.resource_type == 'Fetch'
+		assert products.status == 200
+		assert products.mime_type == 'application/json'
+		assert products.body is not None
+		assert json.loads(products.body) == PRODUCTS
+		assert products.target_id == browser_session.agent_focus_target_id
+
+		assert requests['missing'].status == 404
+		assert browser_session.get_network_requests(url_pattern='products') == [products]
+	finally:
+		browser_session.disable_network_capture()
+
+
+async def test_get_network_requests_action(browser_session, app_url):
+	browser_session.clear_network_requests()
+	tools = Tools()
+
+	# The first call starts recording and asks for a reload
+	result = await tools.get_network_requests(browser_session=browser_session)
+	assert browser_session.is_network_capture_enabled
+	assert 'Reload the page' in (result.extracted_content or '')
+	try:
+		await tools.navigate(url=app_url, new_tab=False, browser_session=browser_session)
+		await _wait_for_requests(browser_session, 2)
+
+		result = await tools.get_network_requests(url_pattern='/api/products', browser_session=browser_session)
+		assert result.error is None
+		assert result.extracted_content is not None
+		assert '/api/products?page=1 -> 200 (application/json)' in result.extracted_content
+		assert '"Blue mug"' in result.extracted_content
+		assert '/api/missing' not in result.extracted_content
+		assert result.long_term_memory == 'Read 1 captured network request.'
+	finally:
+		browser_session.disable_network_capture()
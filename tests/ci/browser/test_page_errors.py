@@ -0,0 +1,89 @@
+"""Tests for the PageErrorsWatchdog: JS exceptions, console errors and failed requests surfaced in the browser state."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.prompts import AgentMessagePrompt
+from browser_use.browser import BrowserSession
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.tools.service import Tools
+
+_FORM_PAGE = """<html><body>
+<button id="submit" onclick="submitForm()">Submit</button>
+<script>
+	function submitForm() {
+		fetch('/api/submit', {method: 'POST'}).then(r => { if (!r.ok) console.error('Submit failed with', r.status); });
+	}
+	setTimeout(() => { throw new TypeError('Cannot read properties of undefined (reading "price")'); }, 0);
+</script>
+</body></html>"""
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/form').respond_with_data(_FORM_PAGE, content_type='text/html')
+	server.expect_request('/api/submit', method='POST').respond_with_data('boom', status=500)
+	yield server
+	server.stop()
+
+
+@pytest.fixture(scope='session')
+def base_url(http_server):
+	return f'http://{http_server.host}:{http_server.port}'
+
+
+async def _wait_for_page_errors(browser_session: BrowserSession, count: int) -> list[str]:
+	for _ in range(20):
+		errors = browser_session.get_page_errors()
+		if len(errors) >= count:
+			return errors
+		await asyncio.sleep(0.1)
+	return browser_session.get_page_errors()
+
+
+async def test_page_errors_collected_and_cleared(browser_session, base_url, tmp_path):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/form', new_tab=False, browser_session=browser_session)
+
+	errors = await _wait_for_page_errors(browser_session, 1)
+	assert any(error.startswith('[exception] TypeError: Cannot read properties of undefined') for error in errors)
+
+	# The state built for the next step carries the errors, after clearing only new errors are collected
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+	assert state.page_errors == errors
+	browser_session.clear_page_errors()
+	assert browser_session.get_page_errors() == []
+
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	await cdp_session.cdp_client.send.Runtime.evaluate(params={'expression': 'submitForm()'}, session_id=cdp_session.session_id)
+
+	errors = await _wait_for_page_errors(browser_session, 2)
+	assert any(error.startswith('[network] Failed to load resource') and '500' in error for error in errors)
+	assert any(error.startswith('[network]') and error.endswith('/api/submit') for error in errors)
+	assert '[console.error] Submit failed with 500' in errors
+
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+	prompt = AgentMessagePrompt(browser_state_summary=state, file_system=FileSystem(base_dir=tmp_path))
+	description = prompt._get_browser_state_description()
+	assert '<page_errors>\n' in description
+	assert '[console.error] Submit failed with 500\n' in description
+
+
+async def test_repeated_page_errors_are_merged(browser_session, base_url):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/form', new_tab=False, browser_session=browser_session)
+	await _wait_for_page_errors(browser_session, 1)
+	browser_session.clear_page_errors()
+
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "for (let i = 0; i < 3; i++) console.error('Widget failed to load')"},
+		session_id=cdp_session.session_id,
+	)
+
+	errors = await _wait_for_page_errors(browser_session, 1)
+	assert errors == ['[console.error] Widget failed to load (x3)']
@@ -0,0 +1,67 @@
+"""Tests for the politeness controls: robots.txt, per-domain rate limits and tab caps."""
+
+import time
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.politeness import DomainPoliteness, politeness_domain
+from browser_use.tools.service import Tools
+
+ROBOTS_TXT = """User-agent: *
+Disallow: /private
+Crawl-delay: 0.4
+"""
+
+
+def _serve_pages(httpserver: HTTPServer) -> None:
+	httpserver.expect_request('/robots.txt').respond_with_data(ROBOTS_TXT, content_type='text/plain')
+	for path in ('/a', '/b', '/private/report'):
+		httpserver.expect_request(path).respond_with_data(f'<h1>{path}</h1>', content_type='text/html')
+
+
+def test_politeness_domain():
+	assert politeness_domain('https://www.Example.com/page') == 'example.com'
+	assert politeness_domain('http://localhost:8000/a') == 'localhost'
+	assert politeness_domain('about:blank') is None
+	assert politeness_domain('data:text/html,<h1>x</h1>') is None
+
+
+async def test_rate_limit_spaces_navigations_per_domain():
+	politeness = DomainPoliteness(rate_limit=0.3)
+
+	start = time.monotonic()
+	await politeness.before_navigation('https://example.com/1')
+	await politeness.before_navigation('https://other.example.org/1')
+	assert time.monotonic() - start < 0.2  # first visits don't wait
+
+	await politeness.before_navigation('https://www.example.com/2')
+	assert time.monotonic() - start >= 0.3
+
+
+async def test_robots_txt_and_tab_cap(httpserver: HTTPServer):
+	_serve_pages(httpserver)
+	session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True, user_data_dir=None, respect_robots_txt=True, max_tabs_per_domain=1, keep_alive=True
+		)
+	)
+	await session.start()
+	tools = Tools()
+	try:
+		result = await tools.navigate(url=httpserver.url_for('/private/report'), new_tab=False, browser_session=session)
+		assert result.error is not None and 'robots.txt' in result.error
+
+		start = time.monotonic()
+		result = await tools.navigate(url=httpserver.url_for('/a'), new_tab=False, browser_session=session)
+		assert result.error is None
+		result = await tools.navigate(url=httpserver.url_for('/b'), new_tab=False, browser_session=session)
+		assert result.error is None
+		assert time.monotonic() - start >= 0.4  # Crawl-delay
+
+		# The one tab on the domain may navigate, a second one is refused
+		result = await tools.navigate(url=httpserver.url_for('/a'), new_tab=True, browser_session=session)
+		assert result.error is not None and 'max_tabs_per_domain=1' in result.error
+		assert len(await session.get_tabs()) == 1
+	finally:
+		await session.kill()
@@ -0,0 +1,68 @@
+"""Tests for persistent profile management: Chrome prefs, unpacked extensions and the profile lock check."""
+
+import json
+import os
+import socket
+import sys
+from pathlib import Path
+
+import pytest
+
+from browser_use.browser.profile import BrowserProfile, ProfileInUseError
+
+
+def _make_extension(path: Path) -> Path:
+	path.mkdir(parents=True)
+	(path / 'manifest.json').write_text(json.dumps({'manifest_version': 3, 'name': 'Test extension', 'version': '1.0'}))
+	return path
+
+
+def test_write_prefs_merges_into_existing_preferences(tmp_path: Path):
+	user_data_dir = tmp_path / 'profile'
+	(user_data_dir / 'Default').mkdir(parents=True)
+	(user_data_dir / 'Default' / 'Preferences').write_text(json.dumps({'download': {'prompt_for_download': True}, 'keep': 1}))
+
+	profile = BrowserProfile(
+		user_data_dir=user_data_dir,
+		headless=True,
+		block_notifications=True,
+		prefs={'download.default_directory': str(tmp_path / 'downloads'), 'intl': {'accept_languages': 'de'}},
+	)
+	profile.write_prefs()
+
+	prefs = json.loads((user_data_dir / 'Default' / 'Preferences').read_text())
+	assert prefs['keep'] == 1
+	assert prefs['download'] == {'prompt_for_download': True, 'default_directory': str(tmp_path / 'downloads')}
+	assert prefs['intl'] == {'accept_languages': 'de'}
+	assert prefs['profile']['default_content_setting_values']['notifications'] == 2
+
+
+def test_extension_paths_are_loaded(tmp_path: Path):
+	extension = _make_extension(tmp_path / 'my-extension')
+	(tmp_path / 'not-an-extension').mkdir()
+
+	profile = BrowserProfile(
+		headless=True,
+		enable_default_extensions=False,
+		extension_paths=[extension, tmp_path / 'not-an-extension'],
+	)
+	load_args = [arg for arg in profile.get_args() if arg.startswith('--load-extension=')]
+	assert load_args == [f'--load-extension={extension.resolve()}']
+
+
+@pytest.mark.skipif(sys.platform == 'win32', reason='SingletonLock symlinks are only used on macOS/Linux')
+def test_profile_lock_check(tmp_path: Path):
+	user_data_dir = tmp_path / 'profile'
+	user_data_dir.mkdir()
+	profile = BrowserProfile(user_data_dir=user_data_dir, headless=True)
+	profile.check_profile_not_in_use()
+
+	# A live browser on this machine holds the profile
+	os.symlink(f'{socket.gethostname()}-{os.getpid()}', user_data_dir / 'SingletonLock')
+	with pytest.raises(ProfileInUseError, match='already in use'):
+		profile.check_profile_not_in_use()
+
+	# A lock left behind by a crashed browser doesn't block the launch
+	(user_data_dir / 'SingletonLock').unlink()
+	os.symlink(f'{socket.gethostname()}-999999999', user_data_dir / 'SingletonLock')
+	profile.check_profile_not_in_use()
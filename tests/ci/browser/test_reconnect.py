@@ -0,0 +1,47 @@
+"""Tests for recovering from a dropped CDP websocket."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+from websockets.exceptions import ConnectionClosedError
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.views import is_connection_error
+from browser_use.tools.service import Tools
+
+
+@pytest.fixture(scope='module')
+async def host_browser():
+	"""A browser we connect to over cdp_url, so the websocket can be dropped without the browser going away."""
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True))
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+def test_is_connection_error():
+	assert is_connection_error(ConnectionResetError('Connection reset by peer'))
+	assert is_connection_error(ConnectionClosedError(None, None))
+	assert is_connection_error(RuntimeError('WebSocket connection closed'))
+	assert not is_connection_error(ValueError('Element with index 12 not found'))
+	assert not is_connection_error(TimeoutError('Navigation timed out'))
+
+
+async def test_session_recovers_after_websocket_drop(host_browser, httpserver: HTTPServer):
+	httpserver.expect_request('/after').respond_with_data('<html><body><h1>After</h1></body></html>', content_type='text/html')
+	session = BrowserSession(cdp_url=host_browser.cdp_url, keep_alive=True)
+	await session.start()
+	try:
+		assert await session.wait_for_reconnect() is True  # nothing to wait for while connected
+
+		assert session._cdp_client_root is not None
+		await session._cdp_client_root.ws.close()
+		assert await session.wait_for_reconnect(timeout=30) is True
+		assert session.is_cdp_connected
+
+		url = httpserver.url_for('/after')
+		await Tools().navigate(url=url, new_tab=False, browser_session=session)
+		assert await session.get_current_page_url() == url
+	finally:
+		await session.kill()
+		await session.event_bus.stop(clear=True, timeout=5)
@@ -0,0 +1,79 @@
+"""Tests for live screencasts of a session and the MJPEG handler serving them."""
+
+import asyncio
+
+import aiohttp
+import pytest
+from aiohttp import web
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.screencast import MJPEG_BOUNDARY, Screencast, mjpeg_handler
+from browser_use.tools.service import Tools
+
+# A page that keeps changing, Chrome only sends screencast frames when something is repainted
+TICKING_PAGE = """<h1 id="tick">0</h1>
+<script>let n = 0; setInterval(() => { document.getElementById('tick').textContent = ++n; }, 50);</script>"""
+
+JPEG_MAGIC = b'\xff\xd8\xff'
+
+
+@pytest.fixture
+async def ticking_page(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/ticking').respond_with_data(TICKING_PAGE, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/ticking'), new_tab=False, browser_session=browser_session)
+	return browser_session
+
+
+async def _first_frames(screencast: Screencast, count: int):
+	frames = []
+	async for frame in screencast.frames():
+		frames.append(frame)
+		if len(frames) == count:
+			break
+	return frames
+
+
+async def test_frames_of_the_focused_tab(ticking_page):
+	async with ticking_page.screencast(quality=50, max_width=640) as screencast:
+		frames = await asyncio.wait_for(_first_frames(screencast, 3), timeout=10)
+
+	assert not screencast.is_running
+	assert all(frame.data.startswith(JPEG_MAGIC) and frame.mime_type == 'image/jpeg' for frame in frames)
+	assert frames[0].target_id == ticking_page.agent_focus_target_id
+	assert frames[0].width and frames[0].height
+	assert frames[0].timestamp <= frames[-1].timestamp
+
+	# Frames stop once the screencast is stopped
+	assert [frame async for frame in screencast.frames()] == []
+
+
+def test_rejects_unsupported_settings(browser_session):
+	with pytest.raises(ValueError, match="'jpeg' or 'png'"):
+		Screencast(browser_session, format='webp')  # type: ignore[arg-type]
+	with pytest.raises(ValueError, match='MJPEG'):
+		mjpeg_handler(Screencast(browser_session, format='png'))
+
+
+async def test_mjpeg_handler_streams_jpeg_parts(ticking_page):
+	async with ticking_page.screencast(quality=50) as screencast:
+		app = web.Application()
+		app.router.add_get('/live', mjpeg_handler(screencast))
+		runner = web.AppRunner(app, access_log=None)
+		await runner.setup()
+		site = web.TCPSite(runner, '127.0.0.1', 0)
+		await site.start()
+		host, port = runner.addresses[0][:2]
+		try:
+			async with aiohttp.ClientSession() as client:
+				async with client.get(f'http://{host}:{port}/live') as response:
+					assert response.headers['Content-Type'] == f'multipart/x-mixed-replace; boundary={MJPEG_BOUNDARY}'
+					body = b''
+					while body.count(f'--{MJPEG_BOUNDARY}'.encode()) < 3:
+						body += await asyncio.wait_for(response.content.readany(), timeout=10)
+		finally:
+			await runner.cleanup()
+
+	part = body.split(f'--{MJPEG_BOUNDARY}\r\n'.encode())[1]
+	headers, image = part.split(b'\r\n\r\n', 1)
+	assert b'Content-Type: image/jpeg' in headers
+	assert image.startswith(JPEG_MAGIC)
@@ -0,0 +1,64 @@
+"""Tests for SessionHandle: several consumers sharing one BrowserSession without corrupting each other's state."""
+
+import asyncio
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.events import NavigateToUrlEvent
+
+
+async def _navigate(browser_session, url: str, new_tab: bool) -> None:
+	event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=new_tab))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+
+
+async def test_handles_restore_their_own_tab_and_selector_map(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/shop').respond_with_data('<html><body><button>Add to cart</button></body></html>')
+	httpserver.expect_request('/mail').respond_with_data(
+		'<html><body><a href="/inbox">Inbox</a><button>Compose</button></body></html>'
+	)
+
+	shopper = browser_session.create_handle('shopper')
+	mailer = browser_session.create_handle('mailer')
+
+	async with shopper:
+		await _navigate(browser_session, httpserver.url_for('/shop'), new_tab=False)
+		await browser_session.get_browser_state_summary(include_screenshot=False)
+		shop_tab = browser_session.agent_focus_target_id
+		shop_map = dict(await browser_session.get_selector_map())
+
+	async with mailer:
+		await _navigate(browser_session, httpserver.url_for('/mail'), new_tab=True)
+		await browser_session.get_browser_state_summary(include_screenshot=False)
+		mail_tab = browser_session.agent_focus_target_id
+	assert mail_tab != shop_tab
+
+	# The shopper gets its tab and element indices back, not the mailer's
+	async with shopper:
+		assert browser_session.agent_focus_target_id == shop_tab
+		assert '/shop' in await browser_session.get_current_page_url()
+		for index, node in shop_map.items():
+			restored = await browser_session.get_element_by_index(index)
+			assert restored is not None and restored.backend_node_id == node.backend_node_id
+
+	async with mailer:
+		assert browser_session.agent_focus_target_id == mail_tab
+
+
+async def test_handles_are_exclusive(browser_session):
+	first = browser_session.create_handle('first')
+	second = browser_session.create_handle('second')
+	events: list[str] = []
+
+	async def use(handle, name: str) -> None:
+		async with handle:
+			events.append(f'{name} start')
+			await asyncio.sleep(0.1)
+			# Re-entering the same handle from inside does not deadlock
+			async with handle:
+				events.append(f'{name} nested')
+			events.append(f'{name} end')
+
+	await asyncio.gather(use(first, 'first'), use(second, 'second'))
+	assert events == ['first start', 'first nested', 'first end', 'second start', 'second nested', 'second end']
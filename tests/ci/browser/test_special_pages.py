@@ -0,0 +1,98 @@
+"""Test the browser state of pages without a usable DOM: browser internal pages and the PDF viewer."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.watchdogs.dom_watchdog import _describe_non_web_page
+from browser_use.browser.watchdogs.downloads_watchdog import _extract_pdf_text
+from browser_use.dom.markdown_extractor import extract_clean_markdown
+from browser_use.tools.service import Tools
+
+
+def _make_pdf(pages: list[str]) -> bytes:
+	"""Build a minimal PDF with one line of Helvetica text per page."""
+	objects: list[str] = ['<< /Type /Catalog /Pages 2 0 R >>', '', '<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>']
+	kids = []
+	for text in pages:
+		stream = f'BT /F1 24 Tf 72 720 Td ({text}) Tj ET'
+		objects.append(f'<< /Length {len(stream)} >>\nstream\n{stream}\nendstream')
+		objects.append(
+			'<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] '
+			f'/Resources << /Font << /F1 3 0 R >> >> /Contents {len(objects)} 0 R >>'
+		)
+		kids.append(f'{len(objects)} 0 R')
+	objects[1] = f'<< /Type /Pages /Kids [{" ".join(kids)}] /Count {len(pages)} >>'
+
+	pdf = b'%PDF-1.4\n'
+	offsets = []
+	for number, obj in enumerate(objects, start=1):
+		offsets.append(len(pdf))
+		pdf += f'{number} 0 obj\n{obj}\nendobj\n'.encode()
+	xref_offset = len(pdf)
+	pdf += f'xref\n0 {len(objects) + 1}\n0000000000 65535 f \n'.encode()
+	for offset in offsets:
+		pdf += f'{offset:010d} 00000 n \n'.encode()
+	pdf += f'trailer\n<< /Size {len(objects) + 1} /Root 1 0 R >>\nstartxref\n{xref_offset}\n%%EOF\n'.encode()
+	return pdf
+
+
+REPORT_PDF = _make_pdf(['Quarterly revenue grew 12 percent', 'Outlook for next year'])
+
+
+@pytest.fixture(scope='module')
+async def browser_session(tmp_path_factory):
+	session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True,
+			user_data_dir=None,
+			keep_alive=True,
+			downloads_path=str(tmp_path_factory.mktemp('downloads')),
+		)
+	)
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+def test_describe_non_web_page():
+	assert _describe_non_web_page('about:blank') is None
+	assert _describe_non_web_page('chrome://newtab/') is None
+	notice = _describe_non_web_page('chrome://settings/')
+	assert notice is not None and notice.startswith('Browser internal page (chrome://settings/)')
+	notice = _describe_non_web_page('data:text/plain,hello')
+	assert notice is not None and notice.startswith('Non-web page (data: URL)')
+
+
+def test_extract_pdf_text():
+	text, page_count = _extract_pdf_text(REPORT_PDF)
+	assert page_count == 2
+	assert '--- Page 1 ---\nQuarterly revenue grew 12 percent' in text
+	assert '--- Page 2 ---\nOutlook for next year' in text
+
+
+async def test_browser_internal_page_state(browser_session):
+	await Tools().navigate(url='chrome://version/', new_tab=False, browser_session=browser_session)
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+
+	assert not state.is_pdf_viewer
+	assert state.page_notice is not None
+	assert state.page_notice.startswith('Browser internal page (chrome://version/)')
+	assert state.title != 'Empty Tab'
+
+
+async def test_pdf_page_state_and_extract(browser_session, httpserver: HTTPServer):
+	# No .pdf in the URL, the page is recognised from its content type
+	httpserver.expect_request('/reports/latest').respond_with_data(REPORT_PDF, content_type='application/pdf')
+	await Tools().navigate(url=httpserver.url_for('/reports/latest'), new_tab=False, browser_session=browser_session)
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+
+	assert state.is_pdf_viewer
+	assert state.page_notice is not None
+	assert state.page_notice.startswith('PDF document, 2 pages.')
+
+	content, stats = await extract_clean_markdown(browser_session=browser_session)
+	assert stats['method'] == 'pdf'
+	assert 'Quarterly revenue grew 12 percent' in content
+	assert 'Outlook for next year' in content
@@ -0,0 +1,81 @@
+"""Tests for the StealthWatchdog: fingerprint patches and user agent / locale / timezone emulation."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import CloseTabEvent
+from browser_use.browser.watchdogs.stealth_watchdog import locale_to_languages
+from browser_use.tools.service import Tools
+from tests.ci.conftest import evaluate_js
+
+
+@pytest.fixture(scope='module')
+async def browser_session():
+	session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True,
+			user_data_dir=None,
+			keep_alive=True,
+			stealth=True,
+			locale='de-DE',
+			timezone_id='Europe/Berlin',
+		)
+	)
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/page').respond_with_data('<html><body><h1>Fingerprint</h1></body></html>', content_type='text/html')
+	yield server
+	server.stop()
+
+
+def test_locale_to_languages():
+	assert locale_to_languages('de-DE') == ['de-DE', 'de']
+	assert locale_to_languages('fr') == ['fr']
+	assert locale_to_languages(None) == []
+
+
+async def test_stealth_patches_and_emulation(browser_session, http_server):
+	tools = Tools()
+	url = f'http://{http_server.host}:{http_server.port}/page'
+	await tools.navigate(url=url, new_tab=False, browser_session=browser_session)
+
+	assert await evaluate_js(browser_session, 'navigator.webdriver === undefined') is True
+	assert await evaluate_js(browser_session, 'navigator.plugins.length > 0') is True
+	assert await evaluate_js(browser_session, 'typeof window.chrome') == 'object'
+	assert await evaluate_js(browser_session, 'navigator.languages') == ['de-DE', 'de']
+	assert await evaluate_js(browser_session, 'Intl.DateTimeFormat().resolvedOptions().timeZone') == 'Europe/Berlin'
+	assert 'HeadlessChrome' not in await evaluate_js(browser_session, 'navigator.userAgent')
+
+	request, _ = http_server.log[-1]
+	assert request.headers['Accept-Language'].startswith('de-DE')
+
+
+async def test_stealth_applies_to_new_tabs(browser_session, http_server):
+	tools = Tools()
+	url = f'http://{http_server.host}:{http_server.port}/page'
+	await tools.navigate(url=url, new_tab=True, browser_session=browser_session)
+
+	assert await evaluate_js(browser_session, 'navigator.webdriver === undefined') is True
+	assert await evaluate_js(browser_session, 'Intl.DateTimeFormat().resolvedOptions().timeZone') == 'Europe/Berlin'
+
+	# Closed tabs are forgotten
+	target_id = browser_session.agent_focus_target_id
+	watchdog = browser_session._stealth_watchdog
+	assert target_id in watchdog._patched_targets
+	await browser_session.event_bus.dispatch(CloseTabEvent(target_id=target_id))
+	for _ in range(50):
+		if target_id not in watchdog._patched_targets:
+			break
+		await asyncio.sleep(0.1)
+	assert target_id not in watchdog._patched_targets
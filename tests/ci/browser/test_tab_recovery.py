@@ -0,0 +1,113 @@
+"""Tests for recovering the agent's tab after a renderer crash or an unexpected blank page."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.views import TabRecovery
+from browser_use.tools.service import Tools
+from tests.ci.conftest import evaluate_js
+
+
+async def _start(httpserver: HTTPServer, **profile) -> BrowserSession:
+	httpserver.expect_request('/shop').respond_with_data('<h1>Shop</h1>', content_type='text/html')
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True, **profile))
+	await session.start()
+	await Tools().navigate(url=httpserver.url_for('/shop'), new_tab=False, browser_session=session)
+	assert await session.recover_unhealthy_tab() is None  # healthy, remembers the page
+	return session
+
+
+@pytest.fixture
+async def session(httpserver: HTTPServer):
+	session = await _start(httpserver)
+	yield session
+	await session.kill()
+
+
+@pytest.fixture
+async def pinned_session(httpserver: HTTPServer):
+	session = await _start(httpserver, restrict_to_target=True)
+	yield session
+	await session.kill()
+
+
+async def _wait_for(condition, timeout: float = 10) -> None:
+	for _ in range(int(timeout / 0.1)):
+		if condition():
+			return
+		await asyncio.sleep(0.1)
+	raise AssertionError('Condition not met in time')
+
+
+async def _crash(session: BrowserSession) -> str:
+	target_id = session.agent_focus_target_id
+	assert target_id is not None
+	cdp_session = await session.get_or_create_cdp_session()
+	try:
+		await asyncio.wait_for(cdp_session.cdp_client.send.Page.crash(session_id=cdp_session.session_id), timeout=2)
+	except Exception:
+		pass  # the renderer may die before it answers
+	watchdog = session._tab_health_watchdog
+	assert watchdog is not None
+	await _wait_for(lambda: target_id in watchdog._crashed_target_ids)
+	return target_id
+
+
+async def test_crashed_tab_is_reloaded(session: BrowserSession, httpserver: HTTPServer):
+	target_id = await _crash(session)
+
+	recovery = await session.recover_unhealthy_tab()
+	assert recovery is not None
+	assert (recovery.problem, recovery.action, recovery.target_id) == ('crashed', 'reloaded', target_id)
+	assert recovery.url == httpserver.url_for('/shop')
+	assert 'crashed and was reloaded' in recovery.message
+	assert await evaluate_js(session, 'document.querySelector("h1").textContent') == 'Shop'
+	assert await session.recover_unhealthy_tab() is None
+
+
+async def test_pinned_tab_is_reloaded_instead_of_replaced(pinned_session: BrowserSession, httpserver: HTTPServer):
+	target_id = pinned_session.pinned_target_id
+	assert target_id is not None
+	for _ in range(2):
+		assert await _crash(pinned_session) == target_id
+		recovery = await pinned_session.recover_unhealthy_tab()
+		assert recovery is not None
+		assert recovery.action in ('reloaded', 'reload_failed') and recovery.target_id == target_id
+
+	assert pinned_session.agent_focus_target_id == target_id
+	assert pinned_session.session_manager is not None
+	assert [target.target_id for target in pinned_session.session_manager.get_all_page_targets()] == [target_id]
+
+
+def test_failed_reload_of_pinned_tab_is_reported():
+	recovery = TabRecovery(problem='crashed', action='reload_failed', url='https://shop.example', target_id='ABCD1234')
+	assert 'still unresponsive' in recovery.message and 'was reloaded' not in recovery.message
+
+
+async def test_lost_tab_is_navigated_back_on_the_fallback_tab(session: BrowserSession, httpserver: HTTPServer):
+	target_id = session.agent_focus_target_id
+	assert target_id is not None
+	fallback_target_id = await session._cdp_create_new_page('about:blank', background=True)
+	await session._cdp_close_page(target_id)
+	focused = session.get_focused_target
+	await _wait_for(lambda: (target := focused()) is not None and target.target_id == fallback_target_id)
+
+	recovery = await session.recover_unhealthy_tab()
+	assert recovery is not None
+	assert (recovery.problem, recovery.action, recovery.target_id) == ('blank', 'renavigated', fallback_target_id)
+	assert await session.get_current_page_url() == httpserver.url_for('/shop')
+
+
+async def test_tab_that_goes_blank_by_itself_is_kept(session: BrowserSession):
+	# e.g. a redirect, going back or a click that ends on a blank page
+	await evaluate_js(session, 'location.href = "about:blank"')
+	focused = session.get_focused_target
+	await _wait_for(lambda: (target := focused()) is not None and target.url == 'about:blank')
+	assert await session.recover_unhealthy_tab() is None
+
+	await Tools().navigate(url='about:blank', new_tab=False, browser_session=session)
+	assert await session.recover_unhealthy_tab() is None
+	assert await session.get_current_page_url() == 'about:blank'
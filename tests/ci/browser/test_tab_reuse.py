@@ -0,0 +1,76 @@
+"""Tests for reusing same-origin tabs on navigate(new_tab=True) and closing least recently used tabs past max_agent_tabs."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.events import SwitchTabEvent
+from browser_use.tools.service import Tools
+
+
+@pytest.fixture
+def pages(httpserver: HTTPServer) -> HTTPServer:
+	for path in ('/a', '/b', '/c', '/d'):
+		httpserver.expect_request(path).respond_with_data(f'<h1>{path}</h1>', content_type='text/html')
+	return httpserver
+
+
+async def _session(**profile_kwargs) -> BrowserSession:
+	session = BrowserSession(browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True, **profile_kwargs))
+	await session.start()
+	return session
+
+
+async def _tab_urls(session: BrowserSession, expected_count: int) -> list[str]:
+	"""URLs of the open tabs, once closed tabs are gone from the session's targets."""
+	for _ in range(50):
+		tabs = await session.get_tabs()
+		if len(tabs) == expected_count:
+			break
+		await asyncio.sleep(0.1)
+	return sorted(tab.url for tab in tabs)
+
+
+async def test_new_tab_reuses_a_tab_on_the_same_origin(pages: HTTPServer):
+	session = await _session(reuse_same_origin_tabs=True)
+	tools = Tools()
+	try:
+		# The launch tab was open before the session connected, it is the browser's own and never reused
+		await tools.navigate(url=pages.url_for('/a'), new_tab=False, browser_session=session)
+		result = await tools.navigate(url=pages.url_for('/b'), new_tab=True, browser_session=session)
+		assert result.error is None and 'Opened new tab' in (result.long_term_memory or '')
+
+		result = await tools.navigate(url=pages.url_for('/c'), new_tab=True, browser_session=session)
+		assert result.error is None
+		assert result.long_term_memory and 'already open on this site' in result.long_term_memory
+		assert await _tab_urls(session, 2) == [pages.url_for('/a'), pages.url_for('/c')]
+
+		# Another origin (same server, different host name) still gets its own tab
+		other_origin = f'http://127.0.0.1:{pages.port}/d'
+		result = await tools.navigate(url=other_origin, new_tab=True, browser_session=session)
+		assert result.error is None and 'Opened new tab' in (result.long_term_memory or '')
+		assert len(await session.get_tabs()) == 3
+	finally:
+		await session.kill()
+
+
+async def test_max_agent_tabs_closes_least_recently_used_tabs(pages: HTTPServer):
+	session = await _session(max_agent_tabs=2)
+	tools = Tools()
+	try:
+		# The launch tab was open before the session connected, it never counts or gets closed
+		await tools.navigate(url=pages.url_for('/a'), new_tab=False, browser_session=session)
+		await tools.navigate(url=pages.url_for('/b'), new_tab=True, browser_session=session)
+		tab_b = session.agent_focus_target_id
+		await tools.navigate(url=pages.url_for('/c'), new_tab=True, browser_session=session)
+		assert tab_b is not None
+		await session.event_bus.dispatch(SwitchTabEvent(target_id=tab_b))  # /c is now the least recently used
+
+		result = await tools.navigate(url=pages.url_for('/d'), new_tab=True, browser_session=session)
+		assert result.error is None
+		assert result.long_term_memory and 'closed least recently used' in result.long_term_memory
+		assert await _tab_urls(session, 3) == [pages.url_for(path) for path in ('/a', '/b', '/d')]
+	finally:
+		await session.kill()
@@ -0,0 +1,46 @@
+"""Tests for viewport and window management: set_viewport, set_window_bounds and their actions."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.tools.service import Tools
+
+
+async def _inner_width(browser_session: BrowserSession) -> int:
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': 'window.innerWidth', 'returnByValue': True}, session_id=cdp_session.session_id
+	)
+	return result['result']['value']
+
+
+async def test_set_viewport_applies_to_later_tabs(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data('<h1>Responsive page</h1>', content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/page'), new_tab=False, browser_session=browser_session)
+
+	result = await tools.set_viewport(width=1600, height=900, browser_session=browser_session)
+	assert result.error is None and '1600x900' in (result.extracted_content or '')
+	assert await _inner_width(browser_session) == 1600
+
+	await tools.navigate(url=httpserver.url_for('/page'), new_tab=True, browser_session=browser_session)
+	assert await _inner_width(browser_session) == 1600
+
+	with pytest.raises(ValueError):
+		await browser_session.set_viewport(0, 900)
+
+
+async def test_set_window_bounds_lets_page_follow_window(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/page').respond_with_data('<h1>Responsive page</h1>', content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/page'), new_tab=False, browser_session=browser_session)
+	await browser_session.set_viewport(1600, 900)
+
+	bounds = await browser_session.set_window_bounds(width=1000, height=700)
+	assert bounds['width'] == 1000 and bounds['height'] == 700
+	# The emulated viewport is gone, the page is as wide as the window
+	assert 600 < await _inner_width(browser_session) <= 1000
+
+	result = await tools.set_window(width=1200, height=800, browser_session=browser_session)
+	assert result.error is None and '1200x800' in (result.extracted_content or '')
@@ -172,6 +172,15 @@ async def browser_session():
 	await session.event_bus.stop(clear=True, timeout=5)
 
 
+async def evaluate_js(browser_session: BrowserSession, expression: str):
+	"""Evaluate a JavaScript expression in the focused tab and return its value"""
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': expression, 'returnByValue': True}, session_id=cdp_session.session_id
+	)
+	return result.get('result', {}).get('value')
+
+
 @pytest.fixture(scope='function')
 def cloud_sync(httpserver: HTTPServer):
 	"""
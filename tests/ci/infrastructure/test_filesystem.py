@@ -12,6 +12,7 @@ from browser_use.filesystem.file_system import (
 	FileSystem,
 	FileSystemState,
 	HtmlFile,
+	InMemoryFileSystem,
 	JsonFile,
 	JsonlFile,
 	MarkdownFile,
@@ -1420,3 +1421,138 @@ class TestCsvNormalization:
 		csv_file = CsvFile(name='test')
 		csv_file.write_file_content(' name , age \nAlice, 30 ')
 		assert csv_file.content == ' name , age \nAlice, 30 '
+
+
+class TestFileSystemLimits:
+	"""Test size limits and extension allowlist."""
+
+	async def test_per_file_size_limit(self, tmp_path):
+		fs = FileSystem(tmp_path, max_file_size=10)
+
+		result = await fs.write_file('small.txt', '0123456789')
+		assert 'successfully' in result
+
+		result = await fs.write_file('big.txt', '0123456789a')
+		assert 'exceeds the per-file limit' in result
+		assert fs.get_file('big.txt') is None
+		assert not (fs.get_dir() / 'big.txt').exists()
+
+		result = await fs.append_file('small.txt', 'x')
+		assert 'exceeds the per-file limit' in result
+		assert fs.display_file('small.txt') == '0123456789'
+
+	async def test_total_size_limit_counts_overwrites_once(self, tmp_path):
+		fs = FileSystem(tmp_path, create_default_files=False, max_total_size=20)
+
+		assert 'successfully' in await fs.write_file('a.txt', 'a' * 15)
+		# Overwriting the same file replaces its size instead of adding to it
+		assert 'successfully' in await fs.write_file('a.txt', 'b' * 18)
+		result = await fs.write_file('c.txt', 'c' * 5)
+		assert 'exceeds the total limit' in result
+		assert fs.get_total_size() == 18
+
+	def test_allowed_extensions(self, tmp_path):
+		fs = FileSystem(tmp_path, allowed_extensions=['.TXT', 'json'])
+		assert fs.get_allowed_extensions() == ['txt', 'json']
+		# todo.md is not created when markdown is not allowed
+		assert fs.list_files() == []
+
+		with pytest.raises(ValueError, match='Unsupported extensions'):
+			FileSystem(tmp_path, allowed_extensions=['exe'])
+
+	async def test_disallowed_extension_is_rejected(self, tmp_path):
+		fs = FileSystem(tmp_path, allowed_extensions=['txt'])
+		result = await fs.write_file('notes.md', 'hello')
+		assert 'Unsupported file extension' in result
+
+	async def test_limits_survive_state_roundtrip(self, tmp_path):
+		fs = FileSystem(tmp_path, max_file_size=100, max_total_size=1000, allowed_extensions=['md', 'txt'])
+		await fs.write_file('notes.txt', 'hello')
+
+		restored = FileSystem.from_state(fs.get_state())
+		assert restored.max_file_size == 100
+		assert restored.max_total_size == 1000
+		assert restored.get_allowed_extensions() == ['md', 'txt']
+		assert restored.display_file('notes.txt') == 'hello'
+
+
+class TestInMemoryFileSystem:
+	"""Test the in-memory FileSystem implementation."""
+
+	async def test_does_not_touch_base_dir(self, tmp_path):
+		base_dir = tmp_path / 'never_created'
+		fs = InMemoryFileSystem(base_dir)
+
+		result = await fs.write_file('data.json', '{"a": 1}')
+		assert 'successfully' in result
+		assert await fs.read_file('data.json') == 'Read from file data.json.\n<content>\n{"a": 1}\n</content>'
+		assert not base_dir.exists()
+
+	async def test_state_roundtrip_stays_in_memory(self, tmp_path):
+		fs = InMemoryFileSystem(tmp_path / 'virtual')
+		await fs.write_file('notes.md', '# Notes')
+
+		state = fs.get_state()
+		assert state.in_memory is True
+		restored = FileSystem.from_state(state)
+		assert isinstance(restored, InMemoryFileSystem)
+		assert restored.display_file('notes.md') == '# Notes'
+		assert not (tmp_path / 'virtual').exists()
+
+	async def test_materialize_writes_to_scratch_dir(self, tmp_path):
+		fs = InMemoryFileSystem(tmp_path / 'virtual')
+		await fs.write_file('report.txt', 'hello')
+
+		path = fs.materialize('report.txt')
+		assert path is not None
+		assert path.read_text() == 'hello'
+		assert fs.materialize('missing.txt') is None
+
+		fs.nuke()
+		assert not path.exists()
+		assert fs.list_files() == []
+
+	def test_fallback_when_directory_is_not_writable(self, tmp_path, monkeypatch):
+		def _read_only_mkdir(self, *args, **kwargs):
+			raise PermissionError(f'Read-only file system: {self}')
+
+		monkeypatch.setattr(Path, 'mkdir', _read_only_mkdir)
+		fs = FileSystem.with_in_memory_fallback(tmp_path / 'readonly', max_file_size=50)
+
+		assert isinstance(fs, InMemoryFileSystem)
+		assert fs.fallback_reason is not None and 'PermissionError' in fs.fallback_reason
+		assert fs.max_file_size == 50
+		assert fs.get_file('todo.md') is not None
+
+	def test_no_fallback_when_directory_is_writable(self, tmp_path):
+		fs = FileSystem.with_in_memory_fallback(tmp_path)
+		assert type(fs) is FileSystem
+		assert fs.fallback_reason is None
+
+	def test_fallback_reason_survives_state_restore(self, tmp_path, monkeypatch):
+		def _read_only_mkdir(self, *args, **kwargs):
+			raise PermissionError(f'Read-only file system: {self}')
+
+		monkeypatch.setattr(Path, 'mkdir', _read_only_mkdir)
+		fs = FileSystem.with_in_memory_fallback(tmp_path / 'readonly')
+
+		restored = FileSystem.from_state(fs.get_state())
+		assert isinstance(restored, InMemoryFileSystem)
+		assert restored.fallback_reason == fs.fallback_reason
+
+	async def test_agent_keeps_screenshots_in_memory_when_directory_is_not_writable(self, monkeypatch):
+		from browser_use.agent.service import Agent
+		from browser_use.screenshots.service import InMemoryScreenshotStore, ScreenshotService
+		from tests.ci.conftest import create_mock_llm
+
+		def _read_only_init(self, agent_directory):
+			raise PermissionError(f'Read-only file system: {agent_directory}')
+
+		monkeypatch.setattr(ScreenshotService, '__init__', _read_only_init)
+		agent = Agent(task='Check the weather', llm=create_mock_llm())
+
+		assert isinstance(agent.screenshot_service, InMemoryScreenshotStore)
+		path = await agent.screenshot_service.store_screenshot('aGVsbG8=', step_number=1)
+		assert await agent.screenshot_service.get_screenshot(path) == 'aGVsbG8='
+		await agent.screenshot_service.delete_screenshot(path)
+		assert await agent.screenshot_service.get_screenshot(path) is None
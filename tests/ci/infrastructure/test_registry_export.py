@@ -0,0 +1,93 @@
+"""Tests for exporting the action registry as JSON Schema / OpenAPI documents."""
+
+import json
+
+from pydantic import BaseModel, Field, create_model
+
+from browser_use.agent.views import ActionResult
+from browser_use.tools.service import Tools
+
+
+class LookupOrderParams(BaseModel):
+	order_id: str = Field(description='Order number')
+	include_items: bool = False
+
+
+def _tools_with_custom_action() -> Tools:
+	tools = Tools()
+
+	@tools.action('Look up an order by id', param_model=LookupOrderParams, domains=['*.shop.example'])
+	async def lookup_order(params: LookupOrderParams):
+		return ActionResult(extracted_content=params.order_id)
+
+	return tools
+
+
+def test_json_schema_export_includes_builtin_and_custom_actions():
+	tools = _tools_with_custom_action()
+	document = tools.export_tool_definitions()
+
+	variants = {variant['title']: variant for variant in document['oneOf']}
+	assert set(variants) == set(tools.registry.registry.actions)
+	assert 'navigate' in variants
+
+	lookup = variants['lookup_order']
+	assert lookup['description'] == 'Look up an order by id'
+	assert lookup['required'] == ['lookup_order']
+	assert lookup['x-domains'] == ['*.shop.example']
+	assert lookup['properties']['lookup_order']['required'] == ['order_id']
+
+	assert variants['navigate']['x-terminates-sequence'] is True
+	# Must be serializable as a standalone document
+	json.dumps(document)
+
+
+def test_page_url_filters_domain_restricted_actions():
+	tools = _tools_with_custom_action()
+
+	other = tools.registry.export_tool_definitions(page_url='https://example.com')
+	assert 'lookup_order' not in {variant['title'] for variant in other['oneOf']}
+
+	shop = tools.registry.export_tool_definitions(page_url='https://www.shop.example/orders')
+	assert 'lookup_order' in {variant['title'] for variant in shop['oneOf']}
+
+
+def test_actions_with_same_model_name_do_not_collide():
+	tools = Tools()
+	order_filters = create_model('Filters', status=(str, ...))
+	product_filters = create_model('Filters', category=(str, ...))
+
+	@tools.action('Search orders', param_model=create_model('Params', filters=(order_filters, ...)))
+	async def search_orders(params):
+		return ActionResult()
+
+	@tools.action('Search products', param_model=create_model('Params', filters=(product_filters, ...), limit=(int, 10)))
+	async def search_products(params):
+		return ActionResult()
+
+	defs = tools.export_tool_definitions()['$defs']
+	assert defs['search_orders.Filters']['required'] == ['status']
+	assert defs['search_products.Filters']['required'] == ['category']
+
+	schemas = tools.export_tool_definitions(format='openapi')['components']['schemas']
+	assert 'limit' not in schemas['search_orders.Params']['properties']
+	assert 'limit' in schemas['search_products.Params']['properties']
+
+
+def test_openapi_export_has_operation_per_action(tmp_path):
+	tools = _tools_with_custom_action()
+	path = tmp_path / 'tools.json'
+	document = tools.export_tool_definitions(format='openapi', path=path)
+
+	assert document['openapi'].startswith('3.1')
+	operation = document['paths']['/actions/lookup_order']['post']
+	assert operation['operationId'] == 'lookup_order'
+	assert operation['requestBody']['content']['application/json']['schema'] == {
+		'$ref': '#/components/schemas/lookup_order.LookupOrderParams'
+	}
+	assert 'ActionResult' in document['components']['schemas']
+
+	# Every $ref must resolve inside the document
+	serialized = path.read_text()
+	for ref in {part.split('"')[0] for part in serialized.split('"$ref": "')[1:]}:
+		assert ref.removeprefix('#/components/schemas/') in document['components']['schemas']
@@ -545,3 +545,45 @@ class TestParamsModelArgsAndKwargs:
 			# logger.info(f'Success with our fix! Result: {result3}')
 		except Exception as e:
 			logger.error(f'Error with our manual test: {str(e)}')
+
+
+class TestParameterValidationErrors:
+	"""Validation errors returned to the model should be short and precise"""
+
+	async def test_type_error_names_field_expected_and_actual(self):
+		registry = Registry()
+
+		class ClickAction(BaseActionModel):
+			index: int
+
+		@registry.action('Click element', param_model=ClickAction)
+		async def click_element(params: ClickAction):
+			return ActionResult(extracted_content=f'Clicked {params.index}')
+
+		with pytest.raises(RuntimeError) as exc_info:
+			await registry.execute_action('click_element', {'index': 'five'})
+
+		assert "'index' must be an integer, got string 'five'" in str(exc_info.value)
+		# The raw pydantic dump (with docs URLs) must not leak into the message
+		assert 'errors.pydantic.dev' not in str(exc_info.value)
+
+	async def test_missing_extra_and_enum_errors(self):
+		from typing import Literal
+
+		registry = Registry()
+
+		class ScrollParams(BaseActionModel):
+			direction: Literal['up', 'down']
+			pages: float
+
+		@registry.action('Scroll', param_model=ScrollParams)
+		async def scroll_page(params: ScrollParams):
+			return ActionResult()
+
+		with pytest.raises(RuntimeError) as exc_info:
+			await registry.execute_action('scroll_page', {'direction': 'left', 'speed': 3})
+
+		message = str(exc_info.value)
+		assert "'direction' must be one of 'up' or 'down', got string 'left'" in message
+		assert "'pages' is required" in message
+		assert "'speed' is not a valid parameter" in message
@@ -0,0 +1,101 @@
+"""Test the click actionability checks: partially covered elements get a real click at a visible point,
+fully covered elements fall back to a JavaScript click and the result says so.
+"""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.actor.utils import get_click_candidate_points
+from browser_use.browser import BrowserSession
+from browser_use.tools.service import Tools
+from tests.ci.conftest import evaluate_js
+
+OVERLAY_HTML = """
+<!DOCTYPE html>
+<html>
+<head><title>Click Occlusion Test</title>
+<style>
+	#target { position: absolute; top: 100px; left: 100px; width: 200px; height: 100px; }
+	#banner { position: fixed; top: 100px; left: 0; width: 100%; height: 60px; background: #333; }
+	#modal { position: fixed; inset: 0; background: rgba(0, 0, 0, 0.5); }
+</style>
+</head>
+<body>
+	<button id="target">Buy now</button>
+	<div id="banner">Cookie banner covering the top of the button</div>
+	<div id="result"></div>
+	<script>
+		document.getElementById('target').addEventListener('click', e => {
+			document.getElementById('result').textContent = e.isTrusted ? 'trusted' : 'script';
+		});
+	</script>
+</body>
+</html>
+"""
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/overlay').respond_with_data(OVERLAY_HTML, content_type='text/html')
+	yield server
+	server.stop()
+
+
+@pytest.fixture(scope='session')
+def base_url(http_server):
+	return f'http://{http_server.host}:{http_server.port}'
+
+
+async def _load_and_find_target(browser_session: BrowserSession, tools: Tools, base_url: str) -> int:
+	await tools.navigate(url=f'{base_url}/overlay', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+	await browser_session.get_browser_state_summary()
+	selector_map = await browser_session.get_selector_map()
+	return next(idx for idx, node in selector_map.items() if node.attributes.get('id') == 'target')
+
+
+def test_click_candidate_points_start_at_visible_center():
+	quad = [100, 100, 300, 100, 300, 200, 100, 200]
+	points = get_click_candidate_points(quad, viewport_width=1280, viewport_height=720)
+	assert points[0] == (200, 150)
+	assert len(points) == len(set(points)) > 1
+	assert all(100 <= x <= 300 and 100 <= y <= 200 for x, y in points)
+
+	# Only the visible part of an element partially scrolled out of the viewport is used
+	points = get_click_candidate_points([100, -100, 300, -100, 300, 100, 100, 100], 1280, 720)
+	assert points[0] == (200, 50)
+
+
+async def test_partially_covered_element_gets_real_click(browser_session, base_url):
+	"""The banner covers the button's center, the click should land on its uncovered lower part."""
+	tools = Tools()
+	index = await _load_and_find_target(browser_session, tools, base_url)
+
+	result = await tools.click(index=index, browser_session=browser_session)
+
+	assert result.error is None
+	assert await evaluate_js(browser_session, "document.getElementById('result').textContent") == 'trusted'
+	assert result.metadata is not None and 'click_y' in result.metadata
+	assert result.metadata['click_y'] > 160  # below the banner
+	assert 'JavaScript' not in (result.extracted_content or '')
+
+
+async def test_fully_covered_element_falls_back_to_js_click_with_note(browser_session, base_url):
+	"""A modal that appeared after the DOM snapshot covers the button entirely."""
+	tools = Tools()
+	index = await _load_and_find_target(browser_session, tools, base_url)
+	await evaluate_js(
+		browser_session, "const m = document.createElement('div'); m.id = 'modal'; document.body.appendChild(m); true"
+	)
+
+	result = await tools.click(index=index, browser_session=browser_session)
+
+	assert result.error is None
+	assert await evaluate_js(browser_session, "document.getElementById('result').textContent") == 'script'
+	assert result.extracted_content is not None
+	assert 'covered by <div id="modal">' in result.extracted_content
+	assert 'clicked via JavaScript' in result.extracted_content
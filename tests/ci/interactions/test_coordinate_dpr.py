@@ -0,0 +1,82 @@
+"""Coordinate clicks land on the right element when the screenshot is in device pixels (DPR > 1) or resized for the LLM."""
+
+import base64
+import io
+
+import pytest
+from PIL import Image
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.browser.profile import BrowserProfile, ViewportSize
+from browser_use.tools.service import Tools
+
+GRID_HTML = """
+<!DOCTYPE html>
+<html>
+<head><style>
+	body { margin: 0; }
+	button { position: absolute; width: 100px; height: 60px; }
+	#left { left: 100px; top: 100px; }
+	#right { left: 500px; top: 400px; }
+</style></head>
+<body>
+	<button id="left" onclick="document.title = 'left'">Left</button>
+	<button id="right" onclick="document.title = 'right'">Right</button>
+</body>
+</html>
+"""
+
+
+@pytest.fixture(scope='module')
+async def browser_session():
+	browser_session = BrowserSession(
+		browser_profile=BrowserProfile(
+			headless=True,
+			user_data_dir=None,
+			keep_alive=True,
+			viewport=ViewportSize(width=800, height=600),
+			device_scale_factor=2,
+		)
+	)
+	await browser_session.start()
+	yield browser_session
+	await browser_session.kill()
+	await browser_session.event_bus.stop(clear=True, timeout=5)
+
+
+async def _title_after_click(browser_session: BrowserSession, x: int, y: int) -> str:
+	tools = Tools()
+	tools.set_coordinate_clicking(True)
+	result = await tools.click(coordinate_x=x, coordinate_y=y, browser_session=browser_session)
+	assert result.error is None
+	return await browser_session.get_current_page_title()
+
+
+async def test_coordinates_scaled_by_device_pixel_ratio(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/grid').respond_with_data(GRID_HTML, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/grid'), new_tab=False, browser_session=browser_session)
+	state = await browser_session.get_browser_state_summary(include_screenshot=True)
+
+	assert state.page_info is not None and state.page_info.device_pixel_ratio == 2
+	assert state.screenshot is not None
+	screenshot = Image.open(io.BytesIO(base64.b64decode(state.screenshot)))
+	assert screenshot.size == (1600, 1200) == browser_session.llm_screenshot_dimensions()
+
+	# The right button's center is at CSS (550, 430), which is (1100, 860) on the device pixel screenshot
+	assert browser_session.llm_coordinates_to_css(1100, 860) == (550, 430)
+	assert await _title_after_click(browser_session, 1100, 860) == 'right'
+
+
+async def test_coordinates_scaled_from_resized_screenshot(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/grid').respond_with_data(GRID_HTML, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/grid'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+
+	browser_session.llm_screenshot_size = (400, 300)
+	try:
+		# The left button's center is at CSS (150, 130), which is (75, 65) on a 400x300 screenshot
+		assert browser_session.llm_coordinates_to_css(75, 65) == (150, 130)
+		assert await _title_after_click(browser_session, 75, 65) == 'left'
+	finally:
+		browser_session.llm_screenshot_size = None
@@ -0,0 +1,153 @@
+"""select_dropdown on custom dropdowns that only render their (virtualized) options after being opened."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.browser.watchdogs.default_action_watchdog import match_dropdown_option
+from browser_use.tools.service import Tools
+
+# A React-select like dropdown: the menu is a portal appended to <body> on mousedown, the list is virtualized (only the
+# rows in view exist) and the searchable variant renders no options until something is typed. The site navigation above
+# it has menu-like class names and must not be mistaken for the dropdown's list
+CUSTOM_DROPDOWN_HTML = """
+<!DOCTYPE html>
+<html>
+<head><style>
+	.control { width: 240px; padding: 8px; border: 1px solid #888; cursor: pointer; margin: 20px; }
+	.select__menu { position: absolute; width: 240px; background: white; border: 1px solid #888; }
+	.select__menu-list { height: 150px; overflow-y: auto; position: relative; }
+	.select__option { position: absolute; left: 0; right: 0; height: 30px; line-height: 30px; padding: 0 8px; }
+</style></head>
+<body>
+	<div class="site-menu-bar"><a class="nav-option-link" href="#">Switzerland travel guide</a></div>
+	<div id="country" class="control" role="combobox" aria-expanded="false" tabindex="0">Choose a country</div>
+	<div id="city" class="control" role="combobox" aria-expanded="false" tabindex="0">
+		<input id="city-search" placeholder="Search a city" autocomplete="off">
+	</div>
+<script>
+	const COUNTRIES = Array.from({ length: 200 }, (_, i) => i === 100 ? 'Switzerland' : `Country ${i}`);
+	const CITIES = ['Berlin', 'Bern', 'Paris', 'Porto', 'Zurich'];
+	let menu = null;
+
+	function closeMenu() {
+		if (menu) menu.remove();
+		menu = null;
+		document.querySelectorAll('.control').forEach(control => control.setAttribute('aria-expanded', 'false'));
+	}
+
+	function openMenu(control, items, onPick) {
+		closeMenu();
+		control.setAttribute('aria-expanded', 'true');
+		menu = document.createElement('div');
+		menu.className = 'select__menu';
+		const rect = control.getBoundingClientRect();
+		menu.style.left = rect.left + 'px';
+		menu.style.top = rect.bottom + window.scrollY + 'px';
+		const list = document.createElement('div');
+		list.className = 'select__menu-list';
+		const spacer = document.createElement('div');
+		spacer.style.height = items.length * 30 + 'px';
+		list.appendChild(spacer);
+		const render = () => {
+			list.querySelectorAll('.select__option').forEach(row => row.remove());
+			const first = Math.floor(list.scrollTop / 30);
+			for (let i = first; i < Math.min(items.length, first + 6); i++) {
+				const row = document.createElement('div');
+				row.className = 'select__option';
+				row.style.top = i * 30 + 'px';
+				row.textContent = items[i];
+				row.addEventListener('mousedown', event => { event.preventDefault(); onPick(items[i]); closeMenu(); });
+				list.appendChild(row);
+			}
+		};
+		list.addEventListener('scroll', () => setTimeout(render, 30));
+		render();
+		menu.appendChild(list);
+		document.body.appendChild(menu);
+	}
+
+	const country = document.getElementById('country');
+	country.addEventListener('mousedown', () => {
+		setTimeout(() => openMenu(country, COUNTRIES, value => { country.textContent = value; }), 100);
+	});
+
+	const city = document.getElementById('city');
+	const search = document.getElementById('city-search');
+	search.addEventListener('input', () => {
+		const query = search.value.toLowerCase();
+		const matches = CITIES.filter(name => name.toLowerCase().includes(query));
+		setTimeout(() => openMenu(city, matches, value => { search.value = value; }), 100);
+	});
+	city.addEventListener('mousedown', () => {
+		search.focus();
+		setTimeout(() => openMenu(city, [], () => {}), 50);
+	});
+</script>
+</body>
+</html>
+"""
+
+
+async def _open_page(browser_session: BrowserSession, httpserver: HTTPServer) -> Tools:
+	httpserver.expect_request('/custom-dropdown').respond_with_data(CUSTOM_DROPDOWN_HTML, content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/custom-dropdown'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+	return tools
+
+
+async def _text_of(browser_session: BrowserSession, expression: str) -> str:
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': expression, 'returnByValue': True}, session_id=cdp_session.session_id
+	)
+	return result['result'].get('value')
+
+
+def test_match_dropdown_option():
+	options = ['East Germany (former)', 'Germany', 'Switzerland']
+	assert match_dropdown_option('germany', options) == (1, 'exact')
+	assert match_dropdown_option('Switz', options) == (2, 'partial')
+	assert match_dropdown_option('Swizerland', options) == (2, 'fuzzy')
+	assert match_dropdown_option('Japan', options) is None
+
+
+async def test_scrolls_virtualized_list_to_option(browser_session: BrowserSession, httpserver: HTTPServer):
+	tools = await _open_page(browser_session, httpserver)
+	index = await browser_session.get_index_by_id('country')
+	assert index is not None
+
+	result = await tools.select_dropdown(index=index, text='switzerland', browser_session=browser_session)
+
+	assert result.error is None
+	assert await _text_of(browser_session, "document.getElementById('country').textContent") == 'Switzerland'
+	assert result.metadata is not None
+	chain = result.metadata['fallback_chain']
+	assert 'clicked to open' in chain and 'scrolled the list' in chain
+	assert "clicked exact match 'Switzerland'" in chain and 'verified the dropdown shows it' in chain
+
+
+async def test_types_to_filter_searchable_dropdown(browser_session: BrowserSession, httpserver: HTTPServer):
+	tools = await _open_page(browser_session, httpserver)
+	index = await browser_session.get_index_by_id('city')
+	assert index is not None
+
+	result = await tools.select_dropdown(index=index, text='zur', browser_session=browser_session)
+
+	assert result.error is None
+	assert await _text_of(browser_session, "document.getElementById('city-search').value") == 'Zurich'
+	assert result.metadata is not None
+	assert "typed 'zur' to filter" in result.metadata['fallback_chain']
+	assert "partial match 'Zurich'" in result.metadata['fallback_chain']
+
+
+async def test_reports_options_when_nothing_matches(browser_session: BrowserSession, httpserver: HTTPServer):
+	tools = await _open_page(browser_session, httpserver)
+	index = await browser_session.get_index_by_id('country')
+	assert index is not None
+
+	result = await tools.select_dropdown(index=index, text='Atlantis', browser_session=browser_session)
+
+	assert result.extracted_content is not None
+	assert '- Country 0' in result.extracted_content and '- Country 50' in result.extracted_content
+	assert result.metadata is not None and 'scrolled the list' in result.metadata['fallback_chain']
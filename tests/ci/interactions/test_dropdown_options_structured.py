@@ -0,0 +1,73 @@
+"""Tests for the structured dropdown_options result: values, selected and disabled flags and option groups."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserSession
+from browser_use.browser.views import DropdownOption
+from browser_use.tools.service import Tools
+
+GROUPED_SELECT = """<!DOCTYPE html>
+<html><body>
+	<select id="city">
+		<option value="">Choose a city</option>
+		<optgroup label="France">
+			<option value="par" selected>Paris</option>
+			<option value="lyo" disabled>Lyon</option>
+		</optgroup>
+		<optgroup label="Germany" disabled>
+			<option value="ber">Berlin</option>
+		</optgroup>
+		<option value="nyc">New York</option>
+	</select>
+	<div id="result"></div>
+	<script>
+		document.getElementById('city').addEventListener('change', e => {
+			document.getElementById('result').textContent = e.target.value;
+		});
+	</script>
+</body></html>"""
+
+
+@pytest.fixture
+async def city_index(browser_session: BrowserSession, httpserver: HTTPServer) -> int:
+	httpserver.expect_request('/cities').respond_with_data(GROUPED_SELECT, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/cities'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+	index = await browser_session.get_index_by_id('city')
+	assert index is not None
+	return index
+
+
+async def test_options_are_returned_as_metadata(browser_session: BrowserSession, city_index: int):
+	result = await Tools().dropdown_options(index=city_index, browser_session=browser_session)
+
+	assert result.metadata is not None
+	assert result.metadata['dropdown_type'] == 'select'
+	assert result.metadata['selected'] == ['par']
+	options = [DropdownOption.model_validate(option) for option in result.metadata['options']]
+	assert [(o.text, o.value, o.group) for o in options] == [
+		('Choose a city', '', None),
+		('Paris', 'par', 'France'),
+		('Lyon', 'lyo', 'France'),
+		('Berlin', 'ber', 'Germany'),
+		('New York', 'nyc', None),
+	]
+	assert [o.index for o in options if o.disabled] == [2, 3]  # disabled option and option of a disabled group
+
+	assert result.extracted_content is not None
+	assert '1: text="Paris", value="par", group="France" (selected)' in result.extracted_content
+	assert '2: text="Lyon", value="lyo", group="France" (disabled)' in result.extracted_content
+
+
+async def test_select_by_value_and_disabled_options(browser_session: BrowserSession, city_index: int):
+	tools = Tools()
+
+	result = await tools.select_dropdown(index=city_index, text='lyo', browser_session=browser_session)
+	assert result.error is not None and 'disabled' in result.error
+
+	result = await tools.select_dropdown(index=city_index, text='nyc', browser_session=browser_session)
+	assert result.error is None
+
+	result = await tools.dropdown_options(index=city_index, browser_session=browser_session)
+	assert result.metadata is not None and result.metadata['selected'] == ['nyc']
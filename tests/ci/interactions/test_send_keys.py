@@ -0,0 +1,78 @@
+"""Test send_keys key sequences with repeat counts and typing delays."""
+
+import time
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.watchdogs.default_action_watchdog import parse_key_sequence
+from browser_use.tools.service import Tools
+from tests.ci.conftest import evaluate_js
+
+FORM_PAGE = """
+<!DOCTYPE html>
+<html>
+<body>
+	<input id="first" />
+	<input id="second" />
+	<input id="third" />
+	<script>
+		window.keyEvents = [];
+		document.addEventListener('keydown', (e) => window.keyEvents.push(e.key));
+	</script>
+</body>
+</html>
+"""
+
+
+@pytest.fixture(scope='module')
+async def browser_session():
+	session = BrowserSession(
+		browser_profile=BrowserProfile(headless=True, user_data_dir=None, keep_alive=True, typing_delay=0.05)
+	)
+	await session.start()
+	yield session
+	await session.kill()
+	await session.event_bus.stop(clear=True, timeout=5)
+
+
+def test_parse_key_sequence():
+	assert parse_key_sequence('Tab Tab Enter') == ['Tab', 'Tab', 'Enter']
+	assert parse_key_sequence('ArrowDown*3 Enter') == ['ArrowDown', 'ArrowDown', 'ArrowDown', 'Enter']
+	assert parse_key_sequence('Tab*2') == ['Tab', 'Tab']
+	assert parse_key_sequence('ctrl+a Delete') == ['ctrl+a', 'Delete']
+	# Single keys, shortcuts and text are sent as a whole like before
+	assert parse_key_sequence('Enter') is None
+	assert parse_key_sequence('Control+o') is None
+	assert parse_key_sequence('hello world') is None
+	assert parse_key_sequence('Tab and more') is None
+
+
+async def test_send_keys_sequence_moves_focus(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/form').respond_with_data(FORM_PAGE, content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/form'), new_tab=False, browser_session=browser_session)
+	await evaluate_js(browser_session, "document.getElementById('first').focus()")
+
+	result = await tools.send_keys(keys='Tab*2', browser_session=browser_session)
+	assert result.error is None
+	assert await evaluate_js(browser_session, 'document.activeElement.id') == 'third'
+
+	await tools.send_keys(keys='Shift+Tab Shift+Tab', browser_session=browser_session)
+	assert await evaluate_js(browser_session, 'document.activeElement.id') == 'first'
+
+
+async def test_send_keys_types_text_with_typing_delay(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/form').respond_with_data(FORM_PAGE, content_type='text/html')
+	tools = Tools()
+	await tools.navigate(url=httpserver.url_for('/form'), new_tab=False, browser_session=browser_session)
+	await evaluate_js(browser_session, "document.getElementById('first').focus()")
+
+	start = time.monotonic()
+	await tools.send_keys(keys='hello', browser_session=browser_session)
+	elapsed = time.monotonic() - start
+
+	assert await evaluate_js(browser_session, "document.getElementById('first').value") == 'hello'
+	assert await evaluate_js(browser_session, 'window.keyEvents') == ['h', 'e', 'l', 'l', 'o']
+	assert elapsed >= 5 * 0.05
@@ -5,6 +5,7 @@ import os
 import pytest
 
 from browser_use.llm.azure.chat import RESPONSES_API_ONLY_MODELS, ChatAzureOpenAI
+from browser_use.llm.exceptions import ModelProviderError
 from browser_use.llm.messages import (
 	AssistantMessage,
 	ContentPartImageParam,
@@ -15,7 +16,9 @@ from browser_use.llm.messages import (
 	ToolCall,
 	UserMessage,
 )
+from browser_use.llm.openai.chat import ChatOpenAI
 from browser_use.llm.openai.responses_serializer import ResponsesAPIMessageSerializer
+from browser_use.llm.views import ChatInvokeCompletion
 
 
 class TestResponsesAPIMessageSerializer:
@@ -202,6 +205,33 @@ class TestChatAzureOpenAIShouldUseResponsesAPI:
 		for model in expected_models:
 			assert model in RESPONSES_API_ONLY_MODELS, f'{model} should be in RESPONSES_API_ONLY_MODELS'
 
+	async def test_auto_falls_back_to_chat_completions(self, monkeypatch):
+		"""Test that auto mode switches to Chat Completions when the endpoint has no /responses."""
+		llm = ChatAzureOpenAI(model='gpt-5.1-codex-mini', api_key='test', base_url='https://llm-gateway.corp/v1')
+		responses_calls = []
+
+		async def no_responses_api(messages, output_format=None, **kwargs):
+			responses_calls.append(messages)
+			raise ModelProviderError(message='Not Found', status_code=404, model=llm.name)
+
+		async def chat_completions(self, messages, output_format=None, **kwargs):
+			return ChatInvokeCompletion(completion='from chat completions', usage=None)
+
+		monkeypatch.setattr(llm, '_ainvoke_responses_api', no_responses_api)
+		monkeypatch.setattr(ChatOpenAI, 'ainvoke', chat_completions)
+
+		messages = [UserMessage(content='Hello')]
+		assert (await llm.ainvoke(messages)).completion == 'from chat completions'
+		assert llm._should_use_responses_api() is False
+		assert (await llm.ainvoke(messages)).completion == 'from chat completions'
+		assert len(responses_calls) == 1
+
+		# An explicit use_responses_api=True surfaces the error instead
+		llm = ChatAzureOpenAI(model='gpt-5.1-codex-mini', api_key='test', use_responses_api=True)
+		monkeypatch.setattr(llm, '_ainvoke_responses_api', no_responses_api)
+		with pytest.raises(ModelProviderError):
+			await llm.ainvoke(messages)
+
 
 class TestChatAzureOpenAIIntegration:
 	"""Integration tests for Azure OpenAI with Responses API.
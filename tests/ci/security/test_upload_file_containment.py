@@ -137,7 +137,8 @@ async def test_traversal_with_no_basename_match_still_fails_safely(
 
 class _StubRemoteBrowserSession(_StubBrowserSession):
 	"""Stub for a remote (non-local) browser session — the upload action's
-	rules differ here. Remote paths are passed through to the browser process."""
+	rules differ here. Paths outside the local FileSystem and available_file_paths
+	are passed through to the remote browser, they address files on its machine."""
 
 	is_local = False
 
@@ -194,3 +195,55 @@ async def test_remote_session_does_not_rewrite_to_local_filesystem_on_basename_c
 		f'Remote session upload silently rewrote to local FileSystem path: {rewrites}. '
 		f'The agent intended to upload {remote_path!r}; the rewrite would have uploaded the local managed file instead.'
 	)
+
+
+async def test_in_memory_filesystem_file_is_materialized_for_upload(stub_session: _StubBrowserSession) -> None:
+	"""Files the agent wrote into an in-memory FileSystem are written to its scratch dir before upload."""
+	from browser_use.filesystem.file_system import InMemoryFileSystem
+
+	fs = InMemoryFileSystem(base_dir='unused')
+	await fs.write_file('report.md', 'generated by the agent')
+
+	tools = Tools()
+	upload_path, error = tools._resolve_upload_path('report.md', stub_session, [], fs)  # type: ignore[arg-type]
+
+	assert error is None
+	assert upload_path == str(fs.get_dir() / 'report.md')
+	with open(upload_path) as f:
+		assert f.read() == 'generated by the agent'
+	fs.nuke()
+
+
+async def test_host_path_upload_requires_allow_host_uploads(tmp_path, stub_session: _StubBrowserSession) -> None:
+	"""Host paths that are not in available_file_paths or the FileSystem are refused unless explicitly allowed."""
+	host_file = tmp_path / 'host.txt'
+	host_file.write_text('host content')
+	fs = FileSystem(base_dir=tmp_path / 'agent')
+
+	result = await Tools().registry.execute_action(
+		'upload_file',
+		{'index': 0, 'path': str(host_file)},
+		browser_session=stub_session,  # type: ignore[arg-type]
+		file_system=fs,
+		available_file_paths=[],
+	)
+	assert result.error is not None
+	assert 'allow_host_uploads' in result.error
+
+	# Explicitly listed paths keep working without the option
+	tools = Tools()
+	upload_path, error = tools._resolve_upload_path(str(host_file), stub_session, [str(host_file)], fs)  # type: ignore[arg-type]
+	assert (upload_path, error) == (str(host_file), None)
+
+	tools = Tools(allow_host_uploads=True)
+	upload_path, error = tools._resolve_upload_path(str(host_file), stub_session, [], fs)  # type: ignore[arg-type]
+	assert (upload_path, error) == (str(host_file), None)
+
+
+async def test_remote_session_passes_remote_paths_through_without_allow_host_uploads(tmp_path) -> None:
+	"""Remote paths address the remote browser's machine, so they need no allow_host_uploads opt-in."""
+	fs = FileSystem(base_dir=tmp_path)
+	remote_session = _StubRemoteBrowserSession()
+
+	upload_path, error = Tools()._resolve_upload_path('/data/invoice.pdf', remote_session, [], fs)  # type: ignore[arg-type]
+	assert (upload_path, error) == ('/data/invoice.pdf', None)
@@ -120,6 +120,39 @@ async def test_act_rejects_invalid_action_timeout_override():
 		assert result.error is None, f'override {bad!r} should have fallen back'
 
 
+@pytest.mark.asyncio
+async def test_per_action_timeout_overrides():
+	"""Tools(action_timeouts={name: ...}) caps only that action; others use Tools(action_timeout=...)."""
+	tools = Tools(action_timeout=5.0, action_timeouts={'hung_action': 0.3})
+
+	async def _hanging_execute_action(**_kwargs):
+		await asyncio.sleep(30.0)
+		return ActionResult(extracted_content='should never be reached')
+
+	tools.registry.execute_action = _hanging_execute_action  # type: ignore[assignment]
+
+	start = time.monotonic()
+	result = await tools.act(action=_StubActionModel(hung_action={'x': 1}), browser_session=None)  # type: ignore[arg-type]
+	elapsed = time.monotonic() - start
+
+	assert elapsed < 2.0, f'per-action override not applied; took {elapsed:.2f}s'
+	assert result.error is not None
+	assert 'hung_action timed out' in result.error
+
+	assert tools.get_action_timeout('hung_action') == 0.3
+	assert tools.get_action_timeout('fast_action') == 5.0
+	# An explicit per-call override still wins
+	assert tools.get_action_timeout('hung_action', 12.0) == 12.0
+
+
+def test_invalid_configured_action_timeouts_fall_back_to_default():
+	from browser_use.tools.service import _DEFAULT_ACTION_TIMEOUT_S
+
+	tools = Tools(action_timeout=float('nan'), action_timeouts={'extract': -1.0})
+	assert tools.get_action_timeout('extract') == _DEFAULT_ACTION_TIMEOUT_S
+	assert tools.get_action_timeout('navigate') == _DEFAULT_ACTION_TIMEOUT_S
+
+
 def test_default_action_timeout_accommodates_extract_action():
 	"""The module-level default must sit above extract's 120s LLM inner cap."""
 	from browser_use.tools.service import _DEFAULT_ACTION_TIMEOUT_S
@@ -0,0 +1,125 @@
+"""Tests for the agent control server that runs agent tasks over HTTP and WebSocket."""
+
+import io
+import json
+import zipfile
+
+import aiohttp
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.control_server import AgentControlServer
+from tests.ci.conftest import create_mock_llm
+
+PROFILE = {'headless': True}
+
+
+def _navigate_then_done(url: str) -> list[str]:
+	navigate = {
+		'evaluation_previous_goal': 'Start',
+		'memory': 'Nothing yet',
+		'next_goal': 'Open the page',
+		'action': [{'navigate': {'url': url}}],
+	}
+	done = {
+		'evaluation_previous_goal': 'Page opened',
+		'memory': 'Saw the page',
+		'next_goal': 'Finish',
+		'action': [{'done': {'text': 'Control server page', 'success': True}}],
+	}
+	return [json.dumps(navigate), json.dumps(done)]
+
+
+async def test_control_server_runs_task_and_streams_events(httpserver: HTTPServer):
+	httpserver.expect_request('/').respond_with_data('<h1>Control server page</h1>', content_type='text/html')
+	server = AgentControlServer(llm=create_mock_llm(_navigate_then_done(httpserver.url_for('/'))), port=0)
+	await server.start()
+	headers = {'Authorization': f'Bearer {server.token}'}
+	try:
+		async with aiohttp.ClientSession(base_url=server.url, headers=headers) as client:
+			async with client.post('/sessions', json={'profile': PROFILE}) as response:
+				assert response.status == 201
+				session_id = (await response.json())['session_id']
+
+			async with client.post(f'/sessions/{session_id}/tasks', json={'task': 'Open the page', 'max_steps': 5}) as response:
+				assert response.status == 201
+				task_id = (await response.json())['task_id']
+
+			events = []
+			async with client.ws_connect(f'/tasks/{task_id}/events') as ws:
+				async for message in ws:
+					events.append(json.loads(message.data))
+			assert [event['type'] for event in events] == ['step', 'step', 'finished']
+			assert events[0]['model_output']['action'][0]['navigate']['url'] == httpserver.url_for('/')
+			assert events[-1]['status'] == 'done' and events[-1]['final_result'] == 'Control server page'
+
+			async with client.get(f'/tasks/{task_id}') as response:
+				task = await response.json()
+			assert task['status'] == 'done' and task['success'] is True and task['steps'] == 2
+
+			async with client.post(f'/tasks/{task_id}/pause') as response:
+				assert response.status == 409
+
+			async with client.get(f'/tasks/{task_id}/artifacts') as response:
+				assert response.status == 200
+				bundle = zipfile.ZipFile(io.BytesIO(await response.read()))
+			assert any(name.endswith('steps.json') for name in bundle.namelist())
+			async with client.get(f'/tasks/{task_id}/artifacts') as response:
+				assert response.status == 200
+			artifacts_dir = server._tasks[task_id].artifacts_dir
+			assert artifacts_dir is not None and artifacts_dir.exists()
+
+			async with client.get(f'/tasks/{task_id}/step/1/screenshot') as response:
+				assert response.status == 200
+				assert response.content_type in ('image/png', 'image/jpeg', 'image/webp')
+
+			async with client.delete(f'/sessions/{session_id}') as response:
+				assert (await response.json())['closed'] is True
+			async with client.get('/sessions') as response:
+				assert (await response.json())['sessions'] == []
+			assert not artifacts_dir.exists()
+	finally:
+		await server.stop()
+	assert not server.is_running
+
+
+async def test_control_server_rejects_bad_requests():
+	server = AgentControlServer(port=0, token='secret')
+	await server.start()
+	try:
+		async with aiohttp.ClientSession(base_url=server.url) as client:
+			async with client.get('/sessions') as response:
+				assert response.status == 401
+
+		headers = {'Authorization': 'Bearer secret'}
+		async with aiohttp.ClientSession(base_url=server.url, headers=headers) as client:
+			async with client.get('/tasks/missing') as response:
+				assert response.status == 404
+			async with client.post('/sessions/missing/tasks', json={'task': 'Anything'}) as response:
+				assert response.status == 404
+			async with client.post('/sessions', data='not json') as response:
+				assert response.status == 415
+			async with client.post('/sessions', data='not json', headers={'Content-Type': 'application/json'}) as response:
+				assert response.status == 400
+			async with client.post('/sessions', json={'profile': {'executable_path': '/bin/sh'}}) as response:
+				assert response.status == 400
+				assert 'executable_path' in await response.text()
+			async with client.get('/sessions', headers={'Origin': 'https://evil.example'}) as response:
+				assert response.status == 403
+			async with client.get('/sessions', headers={'Origin': server.url}) as response:
+				assert response.status == 200
+	finally:
+		await server.stop()
+
+
+async def test_control_server_generates_a_token():
+	server = AgentControlServer(port=0)
+	await server.start()
+	try:
+		assert server.token
+		async with aiohttp.ClientSession(base_url=server.url) as client:
+			async with client.get('/sessions') as response:
+				assert response.status == 401
+			async with client.get('/sessions', headers={'Authorization': f'Bearer {server.token}'}) as response:
+				assert response.status == 200
+	finally:
+		await server.stop()
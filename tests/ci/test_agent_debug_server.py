@@ -0,0 +1,111 @@
+"""Tests for the agent debug server that exposes per-step state over HTTP."""
+
+import aiohttp
+import pytest
+
+from browser_use.agent.debug_server import AgentDebugServer
+from browser_use.agent.service import Agent
+from browser_use.agent.views import ActionResult, AgentHistory
+from browser_use.browser.views import BrowserStateHistory
+from tests.ci.conftest import create_mock_llm
+
+_MODEL_OUTPUT = """
+{
+	"evaluation_previous_goal": "Start",
+	"memory": "Nothing yet",
+	"next_goal": "Open example.com",
+	"action": [{"navigate": {"url": "https://example.com"}}]
+}
+"""
+
+
+def _agent_with_one_step(debug_server: AgentDebugServer) -> Agent:
+	agent = Agent(task='Debug server test task', llm=create_mock_llm(), debug_server=debug_server)
+	agent.history.add_item(
+		AgentHistory(
+			model_output=agent.AgentOutput.model_validate_json(_MODEL_OUTPUT),
+			result=[ActionResult(extracted_content='Navigated to https://example.com', long_term_memory='Navigated')],
+			state=BrowserStateHistory(url='https://example.com', title='Example', tabs=[], interacted_element=[None]),
+			state_message='<browser_state>Example page</browser_state>',
+		)
+	)
+	return agent
+
+
+async def test_debug_server_serves_step_state():
+	server = AgentDebugServer(port=0)
+	agent = _agent_with_one_step(server)
+	await agent._start_debug_server()
+	try:
+		assert server.is_running
+		async with aiohttp.ClientSession(base_url=server.url, headers={'Authorization': f'Bearer {server.token}'}) as client:
+			async with client.get('/debug/runs') as response:
+				runs = (await response.json())['runs']
+			assert [run['id'] for run in runs] == [agent.id]
+
+			async with client.get(f'/debug/run/{agent.id}') as response:
+				run = await response.json()
+			assert run['n_steps'] == 1
+			assert run['steps'][0]['actions'] == ['navigate']
+
+			async with client.get(f'/debug/run/{agent.id}/step/1') as response:
+				assert response.status == 200
+				step = await response.json()
+			assert step['state_message'] == '<browser_state>Example page</browser_state>'
+			assert step['model_output']['action'][0]['navigate']['url'] == 'https://example.com'
+			assert step['results'][0]['extracted_content'] == 'Navigated to https://example.com'
+			assert step['screenshot_url'] is None
+
+			async with client.get(f'/debug/run/{agent.id}/step/1', headers={'Accept': 'text/html'}) as response:
+				assert response.content_type == 'text/html'
+				assert '&lt;browser_state&gt;Example page' in await response.text()
+	finally:
+		await server.stop()
+	assert not server.is_running
+
+
+async def test_debug_server_unknown_run_and_step_return_404():
+	server = AgentDebugServer(port=0)
+	agent = _agent_with_one_step(server)
+	await agent._start_debug_server()
+	try:
+		async with aiohttp.ClientSession(base_url=server.url, headers={'Authorization': f'Bearer {server.token}'}) as client:
+			async with client.get('/debug/run/missing/step/1') as response:
+				assert response.status == 404
+			async with client.get(f'/debug/run/{agent.id}/step/2') as response:
+				assert response.status == 404
+			async with client.get(f'/debug/run/{agent.id}/step/1/screenshot') as response:
+				assert response.status == 404
+	finally:
+		await server.stop()
+
+
+async def test_debug_server_requires_token_and_own_host():
+	server = AgentDebugServer(port=0)
+	agent = _agent_with_one_step(server)
+	await agent._start_debug_server()
+	try:
+		# unsafe=True keeps cookies of IP addresses like 127.0.0.1
+		async with aiohttp.ClientSession(base_url=server.url, cookie_jar=aiohttp.CookieJar(unsafe=True)) as client:
+			async with client.get('/debug/runs') as response:
+				assert response.status == 401
+			async with client.get('/debug/runs', params={'token': 'wrong'}) as response:
+				assert response.status == 401
+			# A DNS-rebinding page sends its own name as Host, even with a valid token
+			rebound_headers = {'Host': f'attacker.example:{server.port}', 'Authorization': f'Bearer {server.token}'}
+			async with client.get('/debug/runs', headers=rebound_headers) as response:
+				assert response.status == 403
+
+			# The token in the run URL sets a cookie, the HTML links then work without it
+			async with client.get(server.run_url(agent.id).removeprefix(server.url)) as response:
+				assert response.status == 200
+			async with client.get(f'/debug/run/{agent.id}/step/1') as response:
+				assert response.status == 200
+	finally:
+		await server.stop()
+
+
+def test_debug_server_needs_token_outside_localhost():
+	with pytest.raises(ValueError, match='without a token'):
+		AgentDebugServer(host='0.0.0.0')
+	assert AgentDebugServer(host='0.0.0.0', token='secret').token == 'secret'
@@ -0,0 +1,71 @@
+"""Tests for Agent.evaluate: judging a finished run with the judge LLM."""
+
+from unittest.mock import AsyncMock
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import ActionResult, AgentHistory, JudgementResult
+from browser_use.browser.views import BrowserStateHistory
+from browser_use.llm.base import BaseChatModel
+from browser_use.llm.views import ChatInvokeCompletion
+from tests.ci.conftest import create_mock_llm
+
+
+def _create_judge_llm(judgement: JudgementResult) -> tuple[BaseChatModel, AsyncMock]:
+	"""Mock judge LLM, plus its ainvoke mock (the agent wraps llm.ainvoke for token tracking)."""
+	judge_llm = AsyncMock(spec=BaseChatModel)
+	judge_llm.provider = 'mock'
+	judge_llm.model = 'mock-judge'
+	judge_llm.name = 'mock-judge'
+	judge_llm.model_name = 'mock-judge'
+	judge_llm.ainvoke = AsyncMock(return_value=ChatInvokeCompletion(completion=judgement, usage=None))
+	return judge_llm, judge_llm.ainvoke
+
+
+def _add_step(agent: Agent, result: ActionResult) -> None:
+	agent.history.add_item(
+		AgentHistory(
+			model_output=None,
+			result=[result],
+			state=BrowserStateHistory(url='https://shop.test/cart', title='Cart', tabs=[], interacted_element=[None]),
+		)
+	)
+
+
+async def test_evaluate_attaches_verdict_with_missing_steps():
+	judgement = JudgementResult(
+		reasoning='The item was added to the cart but checkout was never started.',
+		verdict=False,
+		failure_reason='Checkout was not completed.',
+		missing_steps=['Start checkout', 'Confirm the order'],
+	)
+	judge_llm, judge_ainvoke = _create_judge_llm(judgement)
+	agent = Agent(task='Buy the blue mug', llm=create_mock_llm(), judge_llm=judge_llm, use_judge=False)
+
+	# Nothing to judge before the agent has taken a step
+	assert await agent.evaluate() is None
+	judge_ainvoke.assert_not_called()
+
+	_add_step(agent, ActionResult(extracted_content='Added blue mug to cart'))
+	_add_step(agent, ActionResult(is_done=True, success=True, extracted_content='Bought the blue mug'))
+
+	result = await agent.evaluate()
+	assert result == judgement
+	assert agent.history.is_judged()
+	assert agent.history.is_validated() is False
+	assert agent.history.is_successful() is True  # the agent's self-report is kept
+	assert agent.history.judgement()['missing_steps'] == ['Start checkout', 'Confirm the order']
+
+	judge_messages, output_format = judge_ainvoke.call_args.args[:2]
+	assert 'Buy the blue mug' in judge_messages[1].text
+	assert output_format is JudgementResult
+
+
+async def test_evaluate_judges_runs_that_did_not_finish():
+	judge_llm, _ = _create_judge_llm(JudgementResult(verdict=False, failure_reason='Ran out of steps.'))
+	agent = Agent(task='Buy the blue mug', llm=create_mock_llm(), judge_llm=judge_llm, use_judge=False)
+	_add_step(agent, ActionResult(error='Failed to complete task in maximum steps', include_in_memory=True))
+
+	await agent.evaluate()
+	assert agent.history.is_done() is False
+	assert agent.history.is_validated() is False
+	assert agent.history.judgement()['failure_reason'] == 'Ran out of steps.'
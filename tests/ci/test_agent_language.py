@@ -0,0 +1,65 @@
+"""Tests for Agent(language=...): the system prompt, error reminders and the check of the final answer."""
+
+import json
+
+from browser_use.agent.language import normalize_language, text_matches_language
+from browser_use.agent.prompts import SystemPrompt
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+GERMAN_ANSWER = 'Der Preis des Produkts ist 20 Euro und die Lieferung dauert zwei Tage, es ist leider nicht auf Lager.'
+ENGLISH_ANSWER = 'The price of the product is 20 euros and the delivery takes two days, it is not in stock at the moment.'
+
+
+def _done(text: str) -> str:
+	return json.dumps({'memory': 'Antwort gefunden', 'action': [{'done': {'text': text, 'success': True}}]})
+
+
+def test_text_matches_language():
+	assert normalize_language('de-DE') == normalize_language('Deutsch') == 'german'
+	assert text_matches_language(GERMAN_ANSWER, 'German') is True
+	assert text_matches_language(ENGLISH_ANSWER, 'de') is False
+	assert text_matches_language('该产品的价格是二十欧元，送货需要两天时间。', 'Chinese') is True
+	assert text_matches_language(ENGLISH_ANSWER, 'zh-CN') is False
+	# Too short or unknown languages are never rejected
+	assert text_matches_language('42 EUR', 'German') is None
+	assert text_matches_language(ENGLISH_ANSWER, 'Klingon') is None
+
+
+def test_language_replaces_default_prompt_settings():
+	prompt = SystemPrompt(language='Japanese').get_system_message().text
+	assert prompt.count('<language_settings>') == 1
+	assert 'Working language: **Japanese**' in prompt
+	assert 'Always respond in the same language as the user request' not in prompt
+
+	custom = SystemPrompt(override_system_message='You are a browser agent.', language='Japanese').get_system_message().text
+	assert custom.startswith('You are a browser agent.') and 'Working language: **Japanese**' in custom
+
+
+async def test_done_in_wrong_language_is_sent_back_once(browser_session):
+	llm = create_mock_llm([_done(ENGLISH_ANSWER), _done(GERMAN_ANSWER)])
+	agent = Agent(task='Finde den Preis', llm=llm, browser_session=browser_session, language='German')
+	history = await agent.run(max_steps=5)
+
+	assert history.is_done()
+	assert history.final_result() == GERMAN_ANSWER
+	errors = [error for error in history.errors() if error]
+	assert len(errors) == 1 and 'must be written in German' in errors[0]
+
+	# The rejection reaches the model together with the reminder to keep the language
+	last_messages = llm.ainvoke.call_args_list[-1].args[0]
+	assert any('keep writing in German' in message.text for message in last_messages)
+
+
+async def test_done_in_wrong_language_accepted_after_one_retry(browser_session):
+	agent = Agent(
+		task='Finde den Preis',
+		llm=create_mock_llm([_done(ENGLISH_ANSWER), _done(ENGLISH_ANSWER)]),
+		browser_session=browser_session,
+		language='German',
+	)
+	history = await agent.run(max_steps=5)
+
+	assert history.is_done()
+	assert history.final_result() == ENGLISH_ANSWER
+	assert len(history.history) == 2
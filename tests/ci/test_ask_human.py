@@ -0,0 +1,117 @@
+"""Tests for the ask_human action that hands a question to the user and waits for the answer."""
+
+import asyncio
+import json
+import threading
+import time
+
+from browser_use.agent.service import Agent
+from browser_use.tools.service import Tools
+from tests.ci.conftest import create_mock_llm
+
+
+def _ask(question: str) -> str:
+	return json.dumps(
+		{
+			'evaluation_previous_goal': 'Login form asks for a code',
+			'memory': 'Need the 2FA code',
+			'next_goal': 'Ask the user for the code',
+			'action': [{'ask_human': {'question': question}}],
+		}
+	)
+
+
+def test_ask_human_only_registered_with_callback():
+	assert 'ask_human' not in Agent(task='Test', llm=create_mock_llm()).tools.registry.registry.actions
+
+	tools = Tools()
+	agent = Agent(task='Test', llm=create_mock_llm(), tools=tools, register_ask_human_callback=lambda question: 'yes')
+	assert 'ask_human' in agent.tools.registry.registry.actions
+	# The action is bound to that agent, other agents sharing the tools don't get it
+	assert 'ask_human' not in tools.registry.registry.actions
+	assert 'ask_human' not in Agent(task='Test', llm=create_mock_llm(), tools=tools).tools.registry.registry.actions
+
+
+async def test_answer_is_added_to_history(browser_session):
+	questions = []
+
+	async def answer(question: str) -> str:
+		questions.append(question)
+		return '482913'
+
+	agent = Agent(
+		task='Log in',
+		llm=create_mock_llm([_ask('What is the 2FA code sent to your phone?')]),
+		browser_session=browser_session,
+		register_ask_human_callback=answer,
+	)
+	history = await agent.run(max_steps=3)
+
+	assert questions == ['What is the 2FA code sent to your phone?']
+	result = history.history[0].result[0]
+	assert result.error is None
+	assert 'The user answered: 482913' in (result.long_term_memory or '')
+	assert history.is_done()
+
+
+async def test_sync_callback_and_timeout(browser_session):
+	agent = Agent(
+		task='Pick a plan',
+		llm=create_mock_llm(),
+		browser_session=browser_session,
+		register_ask_human_callback=lambda question: 'The blue one',
+	)
+	action = agent.ActionModel.model_validate({'ask_human': {'question': 'Which plan?'}})
+	result = await agent.tools.act(action, browser_session=browser_session)
+	assert 'The blue one' in (result.extracted_content or '')
+
+	async def never_answers(question: str) -> str:
+		await asyncio.sleep(10)
+		return 'too late'
+
+	agent = Agent(
+		task='Pick a plan',
+		llm=create_mock_llm(),
+		browser_session=browser_session,
+		register_ask_human_callback=never_answers,
+		ask_human_timeout=0.2,
+	)
+	result = await agent.tools.act(action, browser_session=browser_session)
+	assert result.error and 'did not answer' in result.error
+
+
+async def test_sync_callback_that_never_returns_is_abandoned(browser_session):
+	release = threading.Event()
+	agent = Agent(
+		task='Pick a plan',
+		llm=create_mock_llm(),
+		browser_session=browser_session,
+		register_ask_human_callback=lambda question: release.wait() and 'too late',
+		ask_human_timeout=0.2,
+	)
+	action = agent.ActionModel.model_validate({'ask_human': {'question': 'Which plan?'}})
+	result = await agent.tools.act(action, browser_session=browser_session)
+	assert result.error and 'did not answer' in result.error
+	# The blocked callback runs in a daemon thread, so it cannot keep the interpreter from exiting
+	assert all(thread.daemon for thread in threading.enumerate() if thread.name == 'ask_human_callback')
+	release.set()
+
+
+async def test_step_timeout_is_paused_while_the_user_answers(browser_session):
+	async def slow_answer(question: str) -> str:
+		await asyncio.sleep(2.5)
+		return '482913'
+
+	agent = Agent(
+		task='Log in',
+		llm=create_mock_llm([_ask('What is the 2FA code?')]),
+		browser_session=browser_session,
+		register_ask_human_callback=slow_answer,
+		step_timeout=2,
+	)
+	started = time.monotonic()
+	history = await agent.run(max_steps=3)
+
+	assert time.monotonic() - started > 2.5
+	result = history.history[0].result[0]
+	assert result.error is None and 'The user answered: 482913' in (result.long_term_memory or '')
@@ -0,0 +1,88 @@
+"""Tests for the assert action and the assertion summary in the final result."""
+
+import json
+
+import pytest
+from pydantic import ValidationError
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from browser_use.tools.service import Tools
+from browser_use.tools.views import AssertAction
+from tests.ci.conftest import create_mock_llm
+
+CART_PAGE = """
+<html><body>
+	<h1>Your cart</h1>
+	<p class="summary">2 items in cart</p>
+	<input id="qty" value="1">
+	<button id="checkout" data-state="enabled">Checkout</button>
+</body></html>
+"""
+
+
+@pytest.fixture
+def cart_url(httpserver: HTTPServer):
+	httpserver.expect_request('/cart').respond_with_data(CART_PAGE, content_type='text/html')
+	return httpserver.url_for('/cart')
+
+
+async def test_assert_checks(browser_session, cart_url):
+	tools = Tools()
+	assert_action = getattr(tools, 'assert')
+	await tools.navigate(url=cart_url, new_tab=False, browser_session=browser_session)
+
+	async def check(**kwargs) -> dict:
+		result = await assert_action(params=AssertAction(**kwargs), browser_session=browser_session)
+		assert result.error is None
+		assert result.metadata is not None
+		return result.metadata['assertion']
+
+	assert (await check(check='text_present', value='2 ITEMS  in cart'))['passed'] is True
+	assert (await check(check='text_present', value='2 ITEMS in cart', case_sensitive=True))['passed'] is False
+	assert (await check(check='text_absent', value='Your cart is empty'))['passed'] is True
+	assert (await check(check='element_exists', selector='#checkout'))['passed'] is True
+	assert (await check(check='url_matches', value=r'/cart$'))['passed'] is True
+	assert (await check(check='attribute_equals', selector='#checkout', attribute='data-state', value='enabled'))['passed']
+
+	failed = await check(check='attribute_equals', selector='#qty', attribute='value', value='3', description='quantity is 3')
+	assert failed == {
+		'check': 'attribute_equals',
+		'passed': False,
+		'description': 'quantity is 3',
+		'expected': '3',
+		'actual': '1',
+		'selector': '#qty',
+		'attribute': 'value',
+	}
+
+	result = await assert_action(params=AssertAction(check='element_exists', selector='##'), browser_session=browser_session)
+	assert result.error is not None and 'Invalid CSS selector' in result.error
+
+	with pytest.raises(ValidationError):
+		AssertAction(check='attribute_equals', selector='#qty', value='1')
+
+
+async def test_final_result_summarizes_assertions(browser_session, cart_url):
+	def step(action: dict) -> str:
+		return json.dumps({'memory': 'verifying the cart', 'action': [action]})
+
+	actions = [
+		step({'navigate': {'url': cart_url, 'new_tab': False}}),
+		step({'assert': {'check': 'text_present', 'value': '2 items in cart'}}),
+		step({'assert': {'check': 'element_exists', 'selector': '#coupon', 'description': 'coupon field shown'}}),
+		step({'done': {'text': 'Cart verified', 'success': True}}),
+	]
+	agent = Agent(task='Verify the cart', llm=create_mock_llm(actions), browser_session=browser_session)
+	history = await agent.run(max_steps=6)
+
+	assert [a['passed'] for a in history.assertion_results()] == [True, False]
+	final_result = history.final_result()
+	assert final_result is not None
+	assert final_result.startswith('Cart verified')
+	assert 'Assertions: 1/2 passed' in final_result
+	assert '❌ coupon field shown (actual: 0 matching elements)' in final_result
+
+	done_metadata = history.history[-1].result[-1].metadata
+	assert done_metadata is not None
+	assert (done_metadata['assertions']['passed'], done_metadata['assertions']['failed']) == (1, 1)
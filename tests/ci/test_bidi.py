@@ -0,0 +1,112 @@
+"""Tests for the WebDriver BiDi transport and its page and element actors, against a scripted BiDi endpoint."""
+
+import json
+
+import pytest
+from websockets.asyncio.server import serve
+
+from browser_use.actor.bidi import deserialize_remote_value
+from browser_use.agent.service import Agent
+from browser_use.browser import BrowserProfile, BrowserSession
+from browser_use.browser.bidi import BiDiError
+from tests.ci.conftest import create_mock_llm
+
+
+class FakeBiDiBrowser:
+	"""Answers BiDi commands for a single tab and records them."""
+
+	def __init__(self):
+		self.commands: list[tuple[str, dict]] = []
+		self.url = 'about:blank'
+
+	def result(self, method: str, params: dict) -> dict:
+		if method == 'session.new':
+			return {'sessionId': 'session-1', 'capabilities': {}}
+		if method == 'browsingContext.getTree':
+			return {'contexts': [{'context': 'tab-1', 'url': self.url, 'children': []}]}
+		if method == 'browsingContext.navigate':
+			self.url = params['url']
+			return {'navigation': 'nav-1', 'url': self.url}
+		if method == 'browsingContext.locateNodes':
+			return {'nodes': [{'type': 'node', 'sharedId': 'node-1'}, {'type': 'node', 'sharedId': 'node-2'}]}
+		if method == 'script.callFunction':
+			if 'getAttribute' in params['functionDeclaration']:
+				return {'type': 'success', 'result': {'type': 'string', 'value': 'submit'}, 'realm': 'r'}
+			return {
+				'type': 'success',
+				'result': {'type': 'object', 'value': [['sum', {'type': 'number', 'value': 3}]]},
+				'realm': 'r',
+			}
+		return {}
+
+	async def handle(self, websocket):
+		async for raw in websocket:
+			message = json.loads(raw)
+			method, params = message['method'], message['params']
+			self.commands.append((method, params))
+			if method == 'browsingContext.traverseHistory':
+				reply = {'type': 'error', 'id': message['id'], 'error': 'no such history entry', 'message': 'at start'}
+			else:
+				reply = {'type': 'success', 'id': message['id'], 'result': self.result(method, params)}
+			await websocket.send(json.dumps(reply))
+
+	def sent(self, method: str) -> list[dict]:
+		return [params for name, params in self.commands if name == method]
+
+
+async def test_page_and_element_apis_over_bidi():
+	browser = FakeBiDiBrowser()
+	async with serve(browser.handle, 'localhost', 0) as server:
+		port = server.sockets[0].getsockname()[1]
+		session = BrowserSession(protocol='bidi', bidi_url=f'ws://localhost:{port}/session')
+		await session.start()
+
+		page = await session.bidi.must_get_current_page()
+		await page.goto('https://example.com/form')
+		assert await page.get_url() == 'https://example.com/form'
+		assert await page.evaluate('(a, b) => ({sum: a + b})', 1, 2) == '{"sum": 3}'
+		assert browser.sent('script.callFunction')[-1]['arguments'] == [
+			{'type': 'number', 'value': 1},
+			{'type': 'number', 'value': 2},
+		]
+
+		button, field = await page.get_elements_by_css_selector('form *')
+		assert await button.get_attribute('type') == 'submit'
+		await button.click()
+		pointer = browser.sent('input.performActions')[-1]['actions'][0]
+		assert pointer['actions'][0]['origin'] == {'type': 'element', 'element': {'sharedId': 'node-1'}}
+		assert [action['type'] for action in pointer['actions']] == ['pointerMove', 'pointerDown', 'pointerUp']
+
+		await field.fill('hi')
+		keys = browser.sent('input.performActions')[-1]['actions'][0]['actions']
+		assert [key['value'] for key in keys] == ['h', 'h', 'i', 'i']
+		await page.press('Control+a')
+		keys = browser.sent('input.performActions')[-1]['actions'][0]['actions']
+		assert keys == [
+			{'type': 'keyDown', 'value': ''},
+			{'type': 'keyDown', 'value': 'a'},
+			{'type': 'keyUp', 'value': 'a'},
+			{'type': 'keyUp', 'value': ''},
+		]
+
+		with pytest.raises(RuntimeError, match='no such history entry'):
+			await page.go_back()
+
+		await session.stop()
+		assert browser.commands[-1][0] == 'session.end'
+
+
+def test_bidi_needs_url_and_values_deserialize():
+	with pytest.raises(ValueError, match='bidi_url'):
+		BrowserProfile(protocol='bidi')
+
+	assert deserialize_remote_value(
+		{'type': 'array', 'value': [{'type': 'string', 'value': 'a'}, {'type': 'number', 'value': 'NaN'}, {'type': 'node'}]}
+	) == ['a', None, None]
+	assert str(BiDiError('script.evaluate', 'invalid argument', 'bad')) == 'script.evaluate failed: invalid argument: bad'
+
+
+def test_agent_rejects_bidi_browser():
+	session = BrowserSession(browser_profile=BrowserProfile(protocol='bidi', bidi_url='ws://127.0.0.1:9222/session'))
+	with pytest.raises(ValueError, match='Agent needs a CDP browser'):
+		Agent(task='Open example.com', llm=create_mock_llm(), browser_session=session)
@@ -0,0 +1,52 @@
+"""Tests for element bounding boxes: the get_bounding_box action and boxes in the element list."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools
+from browser_use.tools.views import GetBoundingBoxAction
+
+BOX_PAGE = """
+<html><body style="margin: 0; height: 4000px">
+	<button id="top" style="position: absolute; left: 20px; top: 40px; width: 200px; height: 30px">Top</button>
+	<button id="far" style="position: absolute; left: 50px; top: 1500px; width: 100px; height: 40px">Far</button>
+</body></html>
+"""
+
+
+async def _open(browser_session, httpserver: HTTPServer) -> Tools:
+	tools = Tools()
+	httpserver.expect_request('/boxes').respond_with_data(BOX_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/boxes'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+	return tools
+
+
+async def test_get_bounding_box_reports_viewport_position(browser_session, httpserver: HTTPServer):
+	tools = await _open(browser_session, httpserver)
+
+	top_index = await browser_session.get_index_by_id('top')
+	assert top_index is not None
+	result = await tools.get_bounding_box(params=GetBoundingBoxAction(index=top_index), browser_session=browser_session)
+	assert result.error is None and result.metadata is not None
+	assert result.metadata['bounding_box'] == {'x': 20, 'y': 40, 'width': 200, 'height': 30}
+	assert result.extracted_content is not None
+	assert 'center (120, 55)' in result.extracted_content and 'fully visible' in result.extracted_content
+
+	far_index = await browser_session.get_index_by_id('far')
+	assert far_index is not None
+	result = await tools.get_bounding_box(params=GetBoundingBoxAction(index=far_index), browser_session=browser_session)
+	assert result.metadata is not None and result.metadata['bounding_box']['y'] == 1500
+	assert result.extracted_content is not None and 'below the viewport, scroll down' in result.extracted_content
+
+	result = await tools.get_bounding_box(params=GetBoundingBoxAction(index=999), browser_session=browser_session)
+	assert result.error is not None and 'not found' in result.error
+
+
+async def test_element_list_boxes_follow_scroll(browser_session, httpserver: HTTPServer):
+	await _open(browser_session, httpserver)
+
+	state = await browser_session.get_browser_state_summary()
+	assert 'box=' not in state.dom_state.llm_representation()
+	assert 'box=20,40,200,30' in state.dom_state.llm_representation(box_origin=(0, 0))
+	# Page coordinates are shifted to the viewport by the scroll position
+	assert 'box=20,-60,200,30' in state.dom_state.llm_representation(box_origin=(0, 100))
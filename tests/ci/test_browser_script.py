@@ -0,0 +1,49 @@
+"""Scripted steps through BrowserScript: typed targets resolved to the agent's element indices."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.script import BrowserScript, ById, ByIndex, BySelector, ByText, ScriptError
+from tests.ci.conftest import evaluate_js
+
+FORM_HTML = """
+<html><body>
+	<input id="email" placeholder="Email">
+	<select class="plan"><option>Free</option><option>Pro</option></select>
+	<button onclick="document.title = document.getElementById('email').value + ' ' + document.querySelector('.plan').value">
+		Sign up
+	</button>
+</body></html>
+"""
+
+
+async def test_fill_select_and_click_by_typed_targets(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/signup').respond_with_data(FORM_HTML, content_type='text/html')
+	script = BrowserScript(browser_session)
+	await script.navigate(httpserver.url_for('/signup'))
+
+	await script.fill(ById('email'), 'me@example.com')
+	await script.select(BySelector('select.plan'), 'Pro')
+	await script.click(ByText('sign up'))
+
+	assert await evaluate_js(browser_session, 'document.title') == 'me@example.com Pro'
+
+	# ByIndex uses the index exactly as the agent sees it in the browser state
+	await script.state()
+	email_index = await browser_session.get_index_by_id('email')
+	assert email_index is not None and await script.resolve(ByIndex(email_index)) == email_index
+	await script.fill(ByIndex(email_index), ' again', clear=False)
+	assert await evaluate_js(browser_session, "document.getElementById('email').value") == 'me@example.com again'
+
+
+async def test_missing_target_and_failed_action_raise(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/signup').respond_with_data(FORM_HTML, content_type='text/html')
+	script = BrowserScript(browser_session)
+	await script.navigate(httpserver.url_for('/signup'))
+
+	with pytest.raises(ScriptError, match='No interactive element matches'):
+		await script.click(ByText('Log in'))
+
+	with pytest.raises(ScriptError) as error:
+		await script.click(ByIndex(9999))
+	assert error.value.result is not None and error.value.result.error
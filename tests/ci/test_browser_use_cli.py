@@ -46,3 +46,71 @@ def test_browser_use_tui_is_deprecated_alias(monkeypatch, capsys):
 
 	assert browser_use_cli.browser_use_tui_main() == 0
 	assert capsys.readouterr().err == 'browser-use-tui is deprecated; use browser-use instead.\n'
+
+
+def test_run_help_lists_flags():
+	result = _run_browser_use_cli('run', '--help')
+
+	assert result.returncode == 0
+	for flag in ('--model', '--cdp-url', '--launch', '--max-steps', '--vision', '--artifacts-dir', '--json'):
+		assert flag in result.stdout
+
+
+def _summary(**overrides):
+	summary = {
+		'task': 'Find the price',
+		'is_done': True,
+		'success': True,
+		'final_result': '$42',
+		'steps': 3,
+		'duration_seconds': 12.5,
+		'urls': ['https://example.com'],
+		'errors': [],
+		'error': None,
+	}
+	return {**summary, **overrides}
+
+
+def test_run_exit_codes_and_json_output(monkeypatch, capsys):
+	import json
+
+	import browser_use.cli as browser_use_cli
+	import browser_use.llm.models as models
+
+	received = {}
+
+	def run_with(summary):
+		async def fake_run_agent(args, llm):
+			received.update(vars(args))
+			return summary
+
+		monkeypatch.setattr(browser_use_cli, '_run_agent', fake_run_agent)
+
+	monkeypatch.setattr(models, 'get_llm_by_name', lambda name: object())
+
+	run_with(_summary())
+	assert browser_use_cli._run_agent_command(['Find the price', '--max-steps', '5', '--vision', 'auto', '--json']) == 0
+	assert json.loads(capsys.readouterr().out)['final_result'] == '$42'
+	assert received['max_steps'] == 5 and received['vision'] == 'auto' and received['cdp_url'] is None
+
+	run_with(_summary())
+	assert browser_use_cli._run_agent_command(['Find the price', '--cdp-url', 'http://localhost:9222']) == 0
+	assert capsys.readouterr().out == '$42\n'
+	assert received['cdp_url'] == 'http://localhost:9222'
+
+	run_with(_summary(is_done=False, success=False, final_result=None))
+	assert browser_use_cli._run_agent_command(['Find the price']) == browser_use_cli.EXIT_TASK_FAILED
+
+	run_with(_summary(is_done=False, success=False, final_result=None, error='ConnectionError: browser crashed'))
+	assert browser_use_cli._run_agent_command(['Find the price']) == browser_use_cli.EXIT_ERROR
+	assert 'browser crashed' in capsys.readouterr().err
+
+
+def test_run_usage_errors(monkeypatch, capsys):
+	import browser_use.cli as browser_use_cli
+
+	assert browser_use_cli._run_agent_command([]) == browser_use_cli.EXIT_USAGE
+	assert browser_use_cli._run_agent_command(['task', '--cdp-url', 'http://localhost:9222', '--launch']) == 2
+	assert browser_use_cli._run_agent_command(['task', '--cdp-url', 'http://localhost:9222', '--headless']) == 2
+	assert browser_use_cli._run_agent_command(['task', '--max-steps', '0']) == 2
+	assert browser_use_cli._run_agent_command(['task', '--model', 'no_such_model']) == 2
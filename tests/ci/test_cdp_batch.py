@@ -0,0 +1,49 @@
+"""Tests for pipelined CDP commands."""
+
+import asyncio
+from typing import Any, cast
+
+from cdp_use import CDPClient
+
+from browser_use.browser.cdp_batch import result_or_none, send_pipelined
+
+
+class FakeClient:
+	"""Answers every command after a fixed latency, recording when requests went out."""
+
+	def __init__(self, latency: float):
+		self.latency = latency
+		self.sent: list[str] = []
+		self.in_flight = 0
+		self.max_in_flight = 0
+
+	async def send_raw(self, method: str, params: Any | None = None, session_id: str | None = None) -> dict[str, Any]:
+		self.sent.append(method)
+		self.in_flight += 1
+		self.max_in_flight = max(self.max_in_flight, self.in_flight)
+		await asyncio.sleep(self.latency)
+		self.in_flight -= 1
+		if method == 'DOM.getContentQuads':
+			raise RuntimeError('Could not compute content quads.')
+		return {'method': method, 'params': params, 'session_id': session_id}
+
+
+async def test_commands_share_one_round_trip_and_keep_order():
+	client = FakeClient(latency=0.2)
+	commands = [
+		('Page.getLayoutMetrics', None),
+		('DOM.scrollIntoViewIfNeeded', {'backendNodeId': 7}),
+		('DOM.getContentQuads', {'backendNodeId': 7}),
+		('DOM.resolveNode', {'backendNodeId': 7}),
+	]
+
+	start = asyncio.get_running_loop().time()
+	results = await send_pipelined(cast(CDPClient, client), commands, session_id='session-1')
+	elapsed = asyncio.get_running_loop().time() - start
+
+	assert elapsed < 0.4  # one round-trip, not four
+	assert client.sent == [method for method, _ in commands] and client.max_in_flight == 4
+	metrics, scrolled, quads, resolved = results
+	assert result_or_none(metrics) == {'method': 'Page.getLayoutMetrics', 'params': None, 'session_id': 'session-1'}
+	assert isinstance(quads, RuntimeError) and result_or_none(quads) is None
+	assert result_or_none(resolved) and resolved['params'] == {'backendNodeId': 7}  # type: ignore[index]
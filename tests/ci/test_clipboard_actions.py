@@ -0,0 +1,78 @@
+"""Tests for the copy / paste actions and the agent-side clipboard."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.views import ActionResult
+from browser_use.browser import BrowserSession
+from browser_use.tools.service import Tools
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/form').respond_with_data(
+		"""<html><body>
+		<input id="source" value="Order #12345">
+		<input id="target" value="">
+		</body></html>""",
+		content_type='text/html',
+	)
+	yield server
+	server.stop()
+
+
+@pytest.fixture(scope='session')
+def base_url(http_server):
+	return f'http://{http_server.host}:{http_server.port}'
+
+
+async def _get_input_indices(browser_session: BrowserSession) -> dict[str, int]:
+	await browser_session.get_browser_state_summary()
+	selector_map = await browser_session.get_selector_map()
+	return {
+		element.attributes.get('id', ''): idx for idx, element in selector_map.items() if element.tag_name.lower() == 'input'
+	}
+
+
+async def test_copy_element_text_and_paste_into_input(browser_session, base_url):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/form', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+	indices = await _get_input_indices(browser_session)
+
+	result = await tools.copy(index=indices['source'], browser_session=browser_session)
+	assert isinstance(result, ActionResult)
+	assert result.error is None
+	assert tools.clipboard == 'Order #12345'
+	assert result.long_term_memory == f'Copied 12 characters from element {indices["source"]}: "Order #12345"'
+
+	result = await tools.paste(index=indices['target'], browser_session=browser_session)
+	assert result.error is None
+
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	value = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "document.getElementById('target').value"}, session_id=cdp_session.session_id
+	)
+	assert value['result']['value'] == 'Order #12345'
+
+
+async def test_copy_selection_and_empty_clipboard(browser_session, base_url):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/form', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+
+	result = await tools.paste(browser_session=browser_session)
+	assert result.error == 'Clipboard is empty. Use copy first.'
+
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "const el = document.getElementById('source'); el.focus(); el.setSelectionRange(6, 12);"},
+		session_id=cdp_session.session_id,
+	)
+	result = await tools.copy(browser_session=browser_session)
+	assert result.error is None
+	assert tools.clipboard == '#12345'
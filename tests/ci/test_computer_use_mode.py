@@ -0,0 +1,93 @@
+"""Tests for computer-use mode: screenshot-only state and coordinate click/scroll/type actions."""
+
+import json
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from browser_use.tools.service import Tools
+from tests.ci.conftest import create_mock_llm, evaluate_js
+
+FORM_PAGE = """
+<!DOCTYPE html>
+<html>
+<body style="margin: 0">
+	<input id="query" style="position: absolute; left: 100px; top: 100px; width: 200px; height: 30px">
+	<button id="go" style="position: absolute; left: 100px; top: 200px; width: 100px; height: 40px"
+		onclick="document.title = 'Searched ' + document.getElementById('query').value">Go</button>
+	<div id="list" style="position: absolute; left: 400px; top: 100px; width: 200px; height: 200px; overflow: auto">
+		<div style="height: 2000px">Long list</div>
+	</div>
+</body>
+</html>
+"""
+
+
+@pytest.fixture
+def form_url(httpserver: HTTPServer):
+	httpserver.expect_request('/form').respond_with_data(FORM_PAGE, content_type='text/html')
+	return httpserver.url_for('/form')
+
+
+def test_computer_use_action_set():
+	tools = Tools()
+	tools.use_computer_use_actions()
+	actions = tools.registry.registry.actions
+
+	for name in ('click', 'scroll', 'type_text', 'send_keys', 'navigate', 'done'):
+		assert name in actions
+	for name in ('input', 'select_dropdown', 'upload_file', 'find_elements', 'screenshot'):
+		assert name not in actions
+	assert set(actions['click'].param_model.model_fields) == {'coordinate_x', 'coordinate_y'}
+
+
+async def test_coordinate_actions(browser_session, form_url):
+	tools = Tools()
+	tools.use_computer_use_actions()
+	await tools.navigate(url=form_url, new_tab=False, browser_session=browser_session)
+
+	result = await tools.type_text(text='shoes', coordinate_x=200, coordinate_y=115, browser_session=browser_session)
+	assert result.error is None
+	assert await evaluate_js(browser_session, "document.getElementById('query').value") == 'shoes'
+
+	result = await tools.click(coordinate_x=150, coordinate_y=220, browser_session=browser_session)
+	assert result.error is None
+	assert await evaluate_js(browser_session, 'document.title') == 'Searched shoes'
+
+	# Scrolling at a point scrolls the container under it, not the page
+	result = await tools.scroll(down=True, pages=0.5, coordinate_x=500, coordinate_y=200, browser_session=browser_session)
+	assert result.error is None
+	assert await evaluate_js(browser_session, "document.getElementById('list').scrollTop") > 0
+	assert await evaluate_js(browser_session, 'window.scrollY') == 0
+
+
+async def test_agent_state_has_no_dom_elements(browser_session, form_url):
+	type_step = json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Unknown',
+			'memory': 'On the form',
+			'next_goal': 'Search for shoes',
+			'action': [{'type_text': {'text': 'boots', 'coordinate_x': 200, 'coordinate_y': 115}}],
+		}
+	)
+	agent = Agent(
+		task=f'Search for boots on {form_url}',
+		llm=create_mock_llm(actions=[type_step]),
+		browser_session=browser_session,
+		computer_use_mode=True,
+		use_vision=False,
+	)
+	assert agent.settings.use_vision is True
+
+	history = await agent.run(max_steps=3)
+	assert history.is_done()
+	assert await evaluate_js(browser_session, "document.getElementById('query').value") == 'boots'
+
+	state_message = agent._message_manager.last_state_message_text
+	assert state_message is not None
+	assert '<screenshot_info>' in state_message
+	assert 'Interactive elements' not in state_message
+	assert browser_session._cached_browser_state_summary is not None
+	assert browser_session._cached_browser_state_summary.dom_state.selector_map == {}
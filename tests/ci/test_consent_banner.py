@@ -0,0 +1,131 @@
+"""Tests for auto-dismissing cookie consent banners before agent steps."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from browser_use.browser.events import DismissConsentBannerEvent, NavigateToUrlEvent
+from tests.ci.conftest import create_mock_llm
+
+ONETRUST_PAGE = """
+<!DOCTYPE html>
+<html>
+<head><title>Article</title></head>
+<body>
+	<h1>Article</h1>
+	<div id="onetrust-banner-sdk" style="position: fixed; bottom: 0; left: 0; right: 0; height: 120px; background: #eee">
+		<p>We use cookies</p>
+		<button id="onetrust-accept-btn-handler" onclick="dismiss('accepted')">Accept All</button>
+		<button id="onetrust-reject-all-handler" onclick="dismiss('rejected')">Reject All</button>
+	</div>
+	<script>
+		function dismiss(choice) {
+			document.title = choice;
+			document.getElementById('onetrust-banner-sdk').remove();
+		}
+	</script>
+</body>
+</html>
+"""
+
+# CookieYes banner that only offers to accept
+COOKIEYES_ACCEPT_ONLY_PAGE = """
+<!DOCTYPE html>
+<html>
+<head><title>Shop</title></head>
+<body>
+	<div class="cky-consent-container">
+		<button class="cky-btn cky-btn-accept" onclick="document.title = 'accepted'">Accept</button>
+	</div>
+</body>
+</html>
+"""
+
+# A site's own dialog that only looks like a consent banner, its buttons must not be clicked
+LOOKALIKE_PAGE = """
+<!DOCTYPE html>
+<html>
+<head><title>Newsletter</title></head>
+<body>
+	<div class="message-container cookie-notice">
+		<p>Subscribe to our newsletter?</p>
+		<button onclick="document.title = 'declined'">Reject</button>
+		<button onclick="document.title = 'subscribed'">Accept</button>
+	</div>
+</body>
+</html>
+"""
+
+
+@pytest.fixture
+def consent_urls(httpserver: HTTPServer):
+	httpserver.expect_request('/onetrust').respond_with_data(ONETRUST_PAGE, content_type='text/html')
+	httpserver.expect_request('/accept-only').respond_with_data(COOKIEYES_ACCEPT_ONLY_PAGE, content_type='text/html')
+	httpserver.expect_request('/lookalike').respond_with_data(LOOKALIKE_PAGE, content_type='text/html')
+	httpserver.expect_request('/plain').respond_with_data('<html><body>No banner</body></html>', content_type='text/html')
+	return {name: httpserver.url_for(f'/{name}') for name in ('onetrust', 'accept-only', 'lookalike', 'plain')}
+
+
+async def _open(browser_session, url: str) -> None:
+	event = browser_session.event_bus.dispatch(NavigateToUrlEvent(url=url, new_tab=False))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+
+
+async def _dismiss(browser_session, policy: str) -> str | None:
+	event = browser_session.event_bus.dispatch(DismissConsentBannerEvent(policy=policy))
+	await event
+	return await event.event_result(raise_if_any=True, raise_if_none=False)
+
+
+async def _title(browser_session) -> str:
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	result = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': 'document.title', 'returnByValue': True}, session_id=cdp_session.session_id
+	)
+	return result['result']['value']
+
+
+async def test_dismiss_by_policy(browser_session, consent_urls):
+	await _open(browser_session, consent_urls['onetrust'])
+	note = await _dismiss(browser_session, 'reject')
+	assert note is not None and 'OneTrust' in note and note.startswith('Rejected')
+	assert await _title(browser_session) == 'rejected'
+	# The banner is gone, nothing left to dismiss
+	assert await _dismiss(browser_session, 'reject') is None
+
+	await _open(browser_session, consent_urls['onetrust'])
+	note = await _dismiss(browser_session, 'accept')
+	assert note is not None and note.startswith('Accepted')
+	assert await _title(browser_session) == 'accepted'
+
+
+async def test_reject_never_accepts(browser_session, consent_urls):
+	await _open(browser_session, consent_urls['accept-only'])
+	assert await _dismiss(browser_session, 'reject') is None
+	assert await _title(browser_session) == 'Shop'
+
+	await _open(browser_session, consent_urls['plain'])
+	assert await _dismiss(browser_session, 'accept') is None
+
+
+async def test_only_known_framework_buttons_are_clicked(browser_session, consent_urls):
+	await _open(browser_session, consent_urls['lookalike'])
+	assert await _dismiss(browser_session, 'reject') is None
+	assert await _dismiss(browser_session, 'accept') is None
+	assert await _title(browser_session) == 'Newsletter'
+
+
+async def test_agent_notes_dismissed_banner_in_history(browser_session, consent_urls):
+	await _open(browser_session, consent_urls['onetrust'])
+	agent = Agent(
+		task='Read the article',
+		llm=create_mock_llm(),
+		browser_session=browser_session,
+		dismiss_consent_banners='reject',
+	)
+	history = await agent.run(max_steps=2)
+	assert history.is_done()
+	assert await _title(browser_session) == 'rejected'
+	history_description = agent._message_manager.agent_history_description
+	assert '<sys>Rejected non-essential cookies in the OneTrust consent banner' in history_description
@@ -0,0 +1,88 @@
+"""Incremental element lists in the state message (Agent(dom_diff_mode=True))."""
+
+import json
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.message_manager.dom_diff import DOMDiffState
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+BEFORE = """[1]<a href=/home />
+\tHome
+[2]<input type=text value=foo />
+Welcome back
+[3]<button />
+\tSave
+[4]<button />
+\tDelete"""
+
+AFTER = """[1]<a href=/home />
+\tHome
+[2]<input type=text value=bar />
+Welcome back
+Saved successfully
+[3]<button />
+\tSave
+*[5]<button />
+\tUndo"""
+
+
+def test_diff_lists_new_changed_removed_and_shortens_unchanged():
+	state = DOMDiffState()
+	text, header = state.render(BEFORE, 'https://example.com/', full_refresh_every=5)
+	assert (text, header) == (BEFORE, None)
+
+	text, header = state.render(AFTER, 'https://example.com/', full_refresh_every=5)
+	assert header == 'changes since the last step: 1 new, 1 changed, 1 removed, 2 unchanged'
+	assert text == (
+		'New:\n[5]<button />\n\tUndo\n'
+		'Changed:\n[2]<input type=text value=bar />\n'
+		'Removed: [4]\n'
+		'New text on the page:\nSaved successfully\n'
+		'Unchanged (shortened):\n[1]<a> Home\n[3]<button> Save'
+	)
+
+
+def test_full_list_after_navigation_and_on_schedule():
+	state = DOMDiffState()
+	state.render(BEFORE, 'https://example.com/', full_refresh_every=3)
+	assert state.render(AFTER, 'https://example.com/other', full_refresh_every=3)[1] is None
+
+	assert state.render(BEFORE, 'https://example.com/other', full_refresh_every=3)[1] is not None
+	assert state.render(AFTER, 'https://example.com/other', full_refresh_every=3)[1] is not None
+	assert state.render(BEFORE, 'https://example.com/other', full_refresh_every=3)[1] is None
+
+
+def _step(action: dict) -> str:
+	return json.dumps({'memory': 'Working on it', 'action': [action]})
+
+
+async def test_state_messages_switch_to_diffs(browser_session, httpserver: HTTPServer):
+	buttons = ''.join(f'<button>Option {i}</button>' for i in range(5))
+	httpserver.expect_request('/options').respond_with_data(f'<html><body>{buttons}</body></html>', content_type='text/html')
+	add_button = (
+		"(function(){const b=document.createElement('button');b.textContent='Added later';document.body.appendChild(b)})()"
+	)
+	llm = create_mock_llm(
+		actions=[
+			_step({'navigate': {'url': httpserver.url_for('/options'), 'new_tab': False}}),
+			_step({'evaluate': {'code': add_button}}),
+		]
+	)
+	llm_ainvoke = llm.ainvoke
+	agent = Agent(
+		task='Pick an option', llm=llm, browser_session=browser_session, dom_diff_mode=True, directly_open_url=False
+	)
+	history = await agent.run(max_steps=5)
+	assert history.is_done()
+
+	state_messages = [
+		next(message.text for message in call.args[0] if '<browser_state>' in message.text)
+		for call in llm_ainvoke.call_args_list
+	]
+	# The options page was just opened, so its first state has the full list
+	assert 'changes since the last step' not in state_messages[1]
+	assert 'Option 4' in state_messages[1]
+	assert 'changes since the last step: 1 new, 0 changed, 0 removed, 5 unchanged' in state_messages[2]
+	assert 'Added later' in state_messages[2] and '<button> Option 4' in state_messages[2]
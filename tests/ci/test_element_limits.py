@@ -0,0 +1,73 @@
+"""Tests for the configurable limits of the interactive elements list (element count, attribute length, attributes)."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.prompts import AgentMessagePrompt
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.tools.service import Tools
+
+_BUTTON_COUNT = 30
+
+
+def _catalog_page() -> str:
+	buttons = '\n'.join(
+		f'<button data-testid="add-to-cart-{i}" title="Add product number {i} to the shopping cart">Add {i}</button><br>'
+		for i in range(_BUTTON_COUNT)
+	)
+	return f'<html><body>{buttons}</body></html>'
+
+
+async def test_element_count_and_attribute_limits(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/catalog').respond_with_data(_catalog_page(), content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/catalog'), new_tab=False, browser_session=browser_session)
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+	file_system = FileSystem(base_dir=tmp_path, create_default_files=False)
+
+	prompt = AgentMessagePrompt(
+		browser_state_summary=state,
+		file_system=file_system,
+		include_attributes=['data-testid', 'title'],
+		max_interactive_elements=10,
+		max_attribute_length=15,
+	)
+	description = prompt._get_browser_state_description()
+
+	assert 'Interactive elements (truncated to 10 elements):' in description
+	assert 'data-testid=add-to-cart-0' in description
+	assert 'add-to-cart-9' in description
+	assert 'add-to-cart-10' not in description
+	assert f'... {_BUTTON_COUNT - 10} more interactive elements not shown (showing 10 of {_BUTTON_COUNT})' in description
+	assert '[End of page]' not in description
+	# Attribute values are capped at max_attribute_length
+	assert 'title=Add product num...' in description
+	assert 'Add product number 0 to the shopping cart' not in description
+
+	# Without limits every element is listed in full
+	prompt = AgentMessagePrompt(
+		browser_state_summary=state,
+		file_system=file_system,
+		include_attributes=['data-testid', 'title'],
+		max_attribute_length=200,
+	)
+	description = prompt._get_browser_state_description()
+	assert f'add-to-cart-{_BUTTON_COUNT - 1}' in description
+	assert 'Add product number 0 to the shopping cart' in description
+	assert 'more interactive elements not shown' not in description
+
+
+async def test_character_limit_cuts_with_paging_note(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/catalog').respond_with_data(_catalog_page(), content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for('/catalog'), new_tab=False, browser_session=browser_session)
+	state = await browser_session.get_browser_state_summary(include_screenshot=False)
+
+	prompt = AgentMessagePrompt(
+		browser_state_summary=state,
+		file_system=FileSystem(base_dir=tmp_path, create_default_files=False),
+		include_attributes=['data-testid'],
+		max_clickable_elements_length=300,
+	)
+	elements_text, truncated_text = prompt._truncate_elements_text(state.dom_state.llm_representation(['data-testid']))
+
+	assert truncated_text == ' (truncated to 300 characters)'
+	assert 'more interactive elements not shown' in elements_text
+	assert f'of {_BUTTON_COUNT})' in elements_text
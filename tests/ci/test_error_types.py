@@ -0,0 +1,75 @@
+"""Tests for typed errors and ActionResult.error_type."""
+
+import pytest
+from pydantic import BaseModel, ValidationError
+
+from browser_use.agent.views import ActionResult, AgentError, AgentHistory, AgentHistoryList
+from browser_use.browser.views import (
+	BrowserDisconnectedError,
+	BrowserError,
+	BrowserStateHistory,
+	ElementNotFoundError,
+	NavigationError,
+	NavigationTimeoutError,
+	TargetNotFoundError,
+	is_connection_error,
+)
+from browser_use.llm.exceptions import ModelProviderError, ModelRateLimitError
+from browser_use.tools.service import Tools
+
+
+def test_error_types_are_catchable_by_their_builtin_base():
+	timeout = NavigationTimeoutError('Page.navigate() timed out', long_term_memory='timed out')
+	assert isinstance(timeout, NavigationError) and isinstance(timeout, BrowserError)
+	assert isinstance(timeout, TimeoutError) and isinstance(timeout, RuntimeError)
+	assert timeout.long_term_memory == 'timed out'
+
+	assert isinstance(TargetNotFoundError('gone'), ValueError)
+	assert is_connection_error(BrowserDisconnectedError('Reconnection failed'))
+
+
+def test_get_error_type():
+	class Model(BaseModel):
+		value: int
+
+	with pytest.raises(ValidationError) as validation_error:
+		Model(value='x')  # type: ignore[arg-type]
+
+	assert AgentError.get_error_type(ElementNotFoundError('stale')) == 'element_not_found'
+	assert AgentError.get_error_type(NavigationTimeoutError('slow')) == 'navigation_timeout'
+	assert AgentError.get_error_type(BrowserError('other')) == 'browser_error'
+	assert AgentError.get_error_type(ModelRateLimitError('slow down')) == 'llm_rate_limited'
+	assert AgentError.get_error_type(ModelProviderError('boom')) == 'llm_provider_error'
+	assert AgentError.get_error_type(validation_error.value) == 'invalid_model_output'
+	assert AgentError.get_error_type(ConnectionError('websocket connection closed')) == 'browser_disconnected'
+	assert AgentError.get_error_type(KeyError('x')) is None
+
+
+def test_history_error_types():
+	def step(*results: ActionResult) -> AgentHistory:
+		state = BrowserStateHistory(url='https://shop.test', title='Shop', tabs=[], interacted_element=[None])
+		return AgentHistory(model_output=None, result=list(results), state=state)
+
+	history = AgentHistoryList(
+		history=[
+			step(ActionResult(extracted_content='ok')),
+			step(ActionResult(error='Element with index 5 does not exist.', error_type='element_not_found')),
+			step(ActionResult(error='something odd')),
+		]
+	)
+	assert history.error_types() == [None, 'element_not_found', None]
+
+
+async def test_actions_report_error_types(browser_session):
+	tools = Tools()
+
+	result = await tools.scroll(down=True, pages=1, index=99999, browser_session=browser_session)
+	assert result.error is not None
+	assert result.error_type == 'element_not_found'
+
+	result = await tools.navigate(url='http://this-domain-does-not-exist.invalid', new_tab=False, browser_session=browser_session)
+	assert result.error is not None
+	assert result.error_type == 'navigation_failed'
+
+	with pytest.raises(TargetNotFoundError):
+		await browser_session.get_or_create_cdp_session('0' * 32, focus=False)
@@ -275,6 +275,42 @@ class TestFallbackLLMSwitching:
 		assert result is False
 		assert agent.llm is fallback  # Still on fallback
 
+	def test_switch_through_fallback_chain(self):
+		"""Test that a list of fallbacks is tried in order until the chain is exhausted."""
+		from browser_use import Agent
+
+		primary = create_mock_llm('primary-model')
+		first = create_mock_llm('first-fallback')
+		second = create_mock_llm('second-fallback')
+
+		agent = Agent(task='Test task', llm=primary, fallback_llm=[first, second])
+		assert agent._fallback_llm is first
+
+		error = ModelProviderError(message='Service unavailable', status_code=503, model='primary-model')
+		assert agent._try_switch_to_fallback_llm(error) is True
+		assert agent.llm is first
+
+		assert agent._try_switch_to_fallback_llm(error) is True
+		assert agent.llm is second
+
+		assert agent._try_switch_to_fallback_llm(error) is False
+		assert agent.llm is second
+
+	def test_switch_on_context_overflow(self):
+		"""Test that a 400 caused by an oversized prompt switches, since a fallback may have a larger context window."""
+		from browser_use import Agent
+
+		primary = create_mock_llm('primary-model')
+		fallback = create_mock_llm('fallback-model')
+
+		agent = Agent(task='Test task', llm=primary, fallback_llm=fallback)
+
+		error = ModelProviderError(
+			message="This model's maximum context length is 128000 tokens", status_code=400, model='primary-model'
+		)
+		assert agent._try_switch_to_fallback_llm(error) is True
+		assert agent.llm is fallback
+
 
 class TestFallbackLLMIntegration:
 	"""Integration tests for fallback LLM behavior in get_model_output."""
@@ -434,6 +470,42 @@ class TestFallbackLLMIntegration:
 		with pytest.raises((ModelRateLimitError, ModelProviderError)):
 			await agent.get_model_output(messages)
 
+	@pytest.mark.asyncio
+	async def test_get_model_output_walks_chain_and_records_serving_model(self, browser_session):
+		"""Test that failing fallbacks are skipped and the model that served the step is recorded."""
+		from browser_use import Agent
+		from browser_use.agent.views import ActionResult
+
+		placeholder = create_mock_llm('placeholder')
+		agent = Agent(task='Test task', llm=placeholder, browser_session=browser_session)
+
+		primary = self._create_failing_mock_llm('primary', fail_with=ModelRateLimitError, fail_status_code=429)
+		broken = self._create_failing_mock_llm('broken', fail_with=ModelProviderError, fail_status_code=503)
+		working = self._create_succeeding_mock_llm('working', agent)
+
+		agent.llm = primary
+		agent._original_llm = primary
+		agent._fallback_llms = [broken, working]
+
+		from browser_use.llm.messages import BaseMessage, UserMessage
+
+		messages: list[BaseMessage] = [UserMessage(content='Test message')]
+
+		result = await agent.get_model_output(messages)
+
+		assert result is not None
+		assert agent.llm is working
+
+		agent.state.last_result = [ActionResult(extracted_content='done')]
+		agent.step_start_time = 0.0
+		browser_state_summary = await browser_session.get_browser_state_summary()
+		await agent._finalize(browser_state_summary)
+
+		metadata = agent.history.history[-1].metadata
+		assert metadata is not None
+		assert metadata.llm_provider == 'mock'
+		assert metadata.llm_model == 'working'
+
 
 if __name__ == '__main__':
 	pytest.main([__file__, '-v'])
@@ -0,0 +1,68 @@
+"""Tests for concurrent writes to the FileSystem and file systems sharing a base dir."""
+
+import asyncio
+import os
+
+import pytest
+
+from browser_use.filesystem.file_system import DEFAULT_FILE_SYSTEM_PATH, FileSystem, FileSystemError, atomic_write_text
+
+
+async def test_concurrent_writes_do_not_interleave(tmp_path):
+	fs = FileSystem(tmp_path, create_default_files=False)
+
+	results = await asyncio.gather(*(fs.append_file('log.jsonl', f'{{"n": {i}}}\n', create=True) for i in range(20)))
+	assert all('successfully' in result for result in results)
+	lines = (fs.data_dir / 'log.jsonl').read_text().splitlines()
+	assert sorted(lines) == sorted(f'{{"n": {i}}}' for i in range(20))
+
+	names = await asyncio.gather(*(fs.save_extracted_content(f'page {i}') for i in range(5)))
+	assert sorted(names) == [f'extracted_content_{i}.md' for i in range(5)]
+
+	await asyncio.gather(fs.write_file('notes.md', 'a' * 10_000), fs.replace_file_str('notes.md', 'a', 'b'))
+	assert fs.get_file('notes.md').read() == (fs.data_dir / 'notes.md').read_text()  # type: ignore[union-attr]
+	# No temp files are left behind by the atomic writes
+	assert not [name for name in os.listdir(fs.data_dir) if name.endswith('.tmp')]
+
+
+async def test_file_systems_sharing_a_base_dir_get_their_own_data_dir(tmp_path):
+	first = FileSystem(tmp_path, own_data_dir=True)
+	second = FileSystem(tmp_path, own_data_dir=True)
+	assert first.data_dir.name == DEFAULT_FILE_SYSTEM_PATH
+	assert second.data_dir == tmp_path / f'{DEFAULT_FILE_SYSTEM_PATH}_2'
+
+	await first.write_file('result.md', 'first')
+	await second.write_file('result.md', 'second')
+	assert (first.data_dir / 'result.md').read_text() == 'first'
+
+	# Restoring from state keeps the exact data dir
+	restored = FileSystem.from_state(second.get_state())
+	assert restored.data_dir == second.data_dir
+	assert (restored.data_dir / 'result.md').read_text() == 'second'
+
+	# Numbered dirs are reused once their file system is gone instead of piling up
+	second.nuke()
+	del second, restored
+	third = FileSystem(tmp_path, own_data_dir=True)
+	assert third.data_dir == tmp_path / f'{DEFAULT_FILE_SYSTEM_PATH}_2'
+	assert sorted(path.name for path in tmp_path.iterdir()) == [DEFAULT_FILE_SYSTEM_PATH, f'{DEFAULT_FILE_SYSTEM_PATH}_2']
+
+
+def test_caller_data_dir_with_foreign_files_is_not_cleared(tmp_path):
+	data_dir = tmp_path / 'reports'
+	data_dir.mkdir()
+	(data_dir / 'q3.xlsx').write_text('keep me')
+	with pytest.raises(FileSystemError, match='refusing to clear'):
+		FileSystem(tmp_path, data_dir=data_dir)
+	assert (data_dir / 'q3.xlsx').read_text() == 'keep me'
+
+	# An empty directory, or one a FileSystem created before, is used and cleaned
+	fs = FileSystem(tmp_path, data_dir=tmp_path / 'fresh')
+	assert FileSystem(tmp_path, data_dir=fs.data_dir).data_dir == tmp_path / 'fresh'
+
+
+def test_atomic_write_replaces_file(tmp_path):
+	path = tmp_path / 'data.txt'
+	atomic_write_text(path, 'old')
+	atomic_write_text(path, 'new')
+	assert path.read_text() == 'new' and os.listdir(tmp_path) == ['data.txt']
@@ -0,0 +1,85 @@
+"""Tests for the fill_form action that fills several form fields in one step."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools
+from browser_use.tools.views import FillFormAction, FormField
+from tests.ci.conftest import evaluate_js
+
+FORM_PAGE = """
+<!DOCTYPE html>
+<html>
+<head><title>Signup</title></head>
+<body>
+	<form onsubmit="event.preventDefault(); document.title = 'submitted';">
+		<label for="name">Full name</label>
+		<input id="name" type="text">
+		<input id="email" type="email" placeholder="Email address">
+		<select id="country" aria-label="Country">
+			<option value="">Choose</option>
+			<option value="de">Germany</option>
+			<option value="fr">France</option>
+		</select>
+		<label><input id="terms" type="checkbox"> Accept terms</label>
+		<button id="submit" type="submit">Sign up</button>
+	</form>
+</body>
+</html>
+"""
+
+
+async def _open_form(browser_session, httpserver: HTTPServer, tools: Tools) -> dict[str, int]:
+	httpserver.expect_request('/signup').respond_with_data(FORM_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/signup'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+	selector_map = await browser_session.get_selector_map()
+	return {node.attributes['id']: index for index, node in selector_map.items() if node.attributes.get('id')}
+
+
+async def test_fill_form_by_index_and_label(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	indices = await _open_form(browser_session, httpserver, tools)
+
+	result = await tools.fill_form(
+		params=FillFormAction(
+			fields=[
+				FormField(label='Full name', value='Ada Lovelace'),
+				FormField(label='email address', value='ada@example.com'),
+				FormField(index=indices['country'], value='France'),
+				FormField(index=indices['terms'], value='true'),
+			],
+			submit_index=indices['submit'],
+		),
+		browser_session=browser_session,
+	)
+
+	assert result.error is None, result.error
+	assert result.extracted_content is not None and 'Filled 4/4 form fields' in result.extracted_content
+	assert await evaluate_js(browser_session, 'document.getElementById("name").value') == 'Ada Lovelace'
+	assert await evaluate_js(browser_session, 'document.getElementById("email").value') == 'ada@example.com'
+	assert await evaluate_js(browser_session, 'document.getElementById("country").value') == 'fr'
+	assert await evaluate_js(browser_session, 'document.getElementById("terms").checked') is True
+	assert await evaluate_js(browser_session, 'document.title') == 'submitted'
+
+
+async def test_fill_form_reports_failed_fields_and_skips_submit(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	indices = await _open_form(browser_session, httpserver, tools)
+
+	result = await tools.fill_form(
+		params=FillFormAction(
+			fields=[
+				FormField(label='Full name', value='Grace Hopper'),
+				FormField(label='Phone number', value='555-0100'),
+			],
+			submit_index=indices['submit'],
+		),
+		browser_session=browser_session,
+	)
+
+	assert result.error is not None
+	assert 'Filled 1/2 form fields' in result.error
+	assert 'Phone number' in result.error
+	assert 'Did not click submit button' in result.error
+	assert await evaluate_js(browser_session, 'document.getElementById("name").value') == 'Grace Hopper'
+	assert await evaluate_js(browser_session, 'document.title') == 'Signup'
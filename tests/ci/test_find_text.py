@@ -0,0 +1,95 @@
+"""Tests for find_text: ranked exact, case-insensitive and fuzzy matches with element indices."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.browser.events import ScreenshotEvent
+from browser_use.tools.service import Tools
+from browser_use.tools.views import FindTextAction
+from tests.ci.conftest import evaluate_js
+
+ACCOUNT_PAGE = """
+<html><body>
+	<p>Already have an account? SIGN IN below to continue.</p>
+	<div style="height: 3000px"></div>
+	<button id="sign-in"><span>Sign in</span></button>
+	<p>Need help with the sign-on page? Contact support.</p>
+	<a href="/recover">Forgot your pasword?</a>
+</body></html>
+"""
+
+
+async def test_find_text_ranks_matches(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/account').respond_with_data(ACCOUNT_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/account'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+
+	result = await tools.find_text(params=FindTextAction(text='Sign in'), browser_session=browser_session)
+	assert result.error is None
+	assert result.metadata is not None
+	matches = result.metadata['matches']
+	assert [match['kind'] for match in matches[:2]] == ['exact', 'case_insensitive']
+
+	# The exact match sits inside the button, which is reported with its index
+	button_index = await browser_session.get_index_by_id('sign-in')
+	assert button_index is not None
+	assert matches[0]['index'] == button_index
+	assert matches[1]['index'] is None
+	assert result.extracted_content is not None and f'index {button_index}' in result.extracted_content
+	assert await evaluate_js(browser_session, 'window.scrollY') > 0
+
+	# Typos still find the link through fuzzy matching
+	result = await tools.find_text(params=FindTextAction(text='Forgot your password'), browser_session=browser_session)
+	assert result.metadata is not None
+	assert result.metadata['matches'][0]['kind'] == 'fuzzy'
+	assert result.metadata['matches'][0]['tag'] == 'a'
+
+	result = await tools.find_text(params=FindTextAction(text='Checkout now'), browser_session=browser_session)
+	assert result.metadata == {'matches': []}
+	assert result.extracted_content == "Text 'Checkout now' not found on page"
+
+
+async def test_find_text_scroll_to_and_highlight(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/account').respond_with_data(ACCOUNT_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/account'), new_tab=False, browser_session=browser_session)
+
+	result = await tools.find_text(
+		params=FindTextAction(text='Sign in', scroll_to=2, highlight=True), browser_session=browser_session
+	)
+	assert result.extracted_content is not None and 'Scrolled to match 2.' in result.extracted_content
+	assert await evaluate_js(browser_session, 'window.scrollY') == 0
+	assert await evaluate_js(browser_session, "document.querySelectorAll('[data-browser-use-text-match]').length") == 1
+
+	# The outline shows up in one screenshot and is removed afterwards
+	event = browser_session.event_bus.dispatch(ScreenshotEvent(full_page=False))
+	await event
+	await event.event_result(raise_if_any=True, raise_if_none=False)
+	assert await evaluate_js(browser_session, "document.querySelectorAll('[data-browser-use-text-match]').length") == 0
+
+
+async def test_find_text_falls_back_to_iframes(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/framed').respond_with_data(
+		'<html><body><h1>Checkout</h1><iframe src="/inner" style="height: 200px"></iframe></body></html>',
+		content_type='text/html',
+	)
+	httpserver.expect_request('/inner').respond_with_data(
+		'<html><body><div style="height: 2000px"></div><p>Order summary</p></body></html>', content_type='text/html'
+	)
+	await tools.navigate(url=httpserver.url_for('/framed'), new_tab=False, browser_session=browser_session)
+
+	result = await tools.find_text(params=FindTextAction(text='Order summary'), browser_session=browser_session)
+	assert result.error is None
+	assert result.extracted_content is not None and 'found inside a frame' in result.extracted_content
+	frame_scroll = 'document.querySelector("iframe").contentWindow.scrollY'
+	assert await evaluate_js(browser_session, frame_scroll) > 0
+
+
+async def test_find_text_with_long_query_skips_fuzzy_matching(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/account').respond_with_data(ACCOUNT_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/account'), new_tab=False, browser_session=browser_session)
+
+	result = await tools.find_text(params=FindTextAction(text='Forgot your password ' * 10), browser_session=browser_session)
+	assert result.metadata == {'matches': []}
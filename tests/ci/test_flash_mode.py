@@ -0,0 +1,84 @@
+"""Flash mode end to end: tolerant parsing of the short output format and the reduced state message."""
+
+import json
+
+import pytest
+from pydantic import ValidationError
+
+from browser_use.agent.prompts import AgentMessagePrompt
+from browser_use.agent.views import AgentOutput
+from browser_use.browser.views import BrowserStateSummary, PageInfo, TabInfo
+from browser_use.dom.views import SerializedDOMState
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.tools.service import Tools
+
+
+@pytest.fixture(scope='module')
+def flash_output():
+	return AgentOutput.type_with_custom_actions_flash_mode(Tools().registry.create_action_model())
+
+
+def test_parses_short_and_abbreviated_outputs(flash_output):
+	output = flash_output.model_validate_json(json.dumps({'memory': 'On the login page', 'action': [{'scroll': {}}]}))
+	assert output.memory == 'On the login page' and len(output.action) == 1
+	assert output.current_state.evaluation_previous_goal == '' and output.current_state.next_goal == ''
+
+	# A single action object, "actions" instead of "action" and leftover reasoning keys
+	output = flash_output.model_validate_json(
+		json.dumps({'reasoning': 'scroll to see more', 'actions': {'navigate': {'url': 'https://example.com'}}})
+	)
+	assert output.memory is None
+	assert output.action[0].model_dump(exclude_unset=True)['navigate']['url'] == 'https://example.com'
+
+	# A bare list of actions
+	output = flash_output.model_validate([{'go_back': {}}])
+	assert len(output.action) == 1
+
+	with pytest.raises(ValidationError):
+		flash_output.model_validate({'memory': 'no action'})
+
+
+def test_full_mode_still_rejects_unknown_keys():
+	full_output = AgentOutput.type_with_custom_actions(Tools().registry.create_action_model())
+	with pytest.raises(ValidationError):
+		full_output.model_validate({'memory': 'x', 'reasoning': 'y', 'action': [{'go_back': {}}]})
+
+
+def _state_message(tmp_path, flash_mode: bool) -> str:
+	state = BrowserStateSummary(
+		url='https://example.test/long',
+		title='Long page',
+		tabs=[TabInfo(target_id='abcd1234', url='https://example.test/long', title='Long page')],
+		page_info=PageInfo(
+			viewport_width=1280,
+			viewport_height=720,
+			page_width=1280,
+			page_height=2160,
+			scroll_x=0,
+			scroll_y=720,
+			pixels_above=720,
+			pixels_below=720,
+			pixels_left=0,
+			pixels_right=0,
+		),
+		dom_state=SerializedDOMState(_root=None, selector_map={}),
+		screenshot=None,
+	)
+	prompt = AgentMessagePrompt(
+		browser_state_summary=state,
+		file_system=FileSystem(base_dir=str(tmp_path / str(flash_mode)), create_default_files=False),
+		task='Read the article',
+		flash_mode=flash_mode,
+	)
+	return prompt.get_user_message(use_vision=False).text
+
+
+def test_flash_mode_state_message_is_reduced(tmp_path):
+	full = _state_message(tmp_path, flash_mode=False)
+	flash = _state_message(tmp_path, flash_mode=True)
+
+	assert '<page_info>1.0 pages above, 1.0 pages below</page_info>' in flash
+	assert 'Available tabs' not in flash and 'empty todo.md' not in flash
+	assert 'Available tabs' in full and 'viewport 1280x720px' in full
+	assert '<user_request>\nRead the article' in flash
+	assert len(flash) < len(full)
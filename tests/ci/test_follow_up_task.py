@@ -0,0 +1,51 @@
+"""Tests for Agent.run_task: follow-up tasks that continue on the same agent, history and browser."""
+
+import json
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+def _navigate_action(url: str) -> str:
+	return json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Starting',
+			'memory': 'Opening the page',
+			'next_goal': 'Open the page',
+			'action': [{'navigate': {'url': url, 'new_tab': False}}],
+		}
+	)
+
+
+@pytest.fixture
+def page_url(httpserver: HTTPServer):
+	html = '<html><body><h1>Products</h1></body></html>'
+	httpserver.expect_request('/products').respond_with_data(html, content_type='text/html')
+	return httpserver.url_for('/products')
+
+
+async def test_run_task_continues_history_and_browser(browser_session, page_url):
+	llm = create_mock_llm(actions=[_navigate_action(page_url)])
+	agent = Agent(task='Open the products page', llm=llm, browser_session=browser_session, directly_open_url=False)
+
+	history = await agent.run(max_steps=5)
+	assert history.is_done()
+	steps_after_first_task = len(history.history)
+	assert steps_after_first_task == 2
+
+	# The follow-up gets its own step budget and keeps the tab of the first task
+	history = await agent.run_task('Summarize the products', max_steps=1)
+	assert history.is_done()
+	assert len(history.history) == steps_after_first_task + 1
+	assert await browser_session.get_current_page_url() == page_url
+
+	assert '<initial_user_request>Open the products page</initial_user_request>' in agent._message_manager.task
+	assert '<follow_up_user_request> Summarize the products </follow_up_user_request>' in agent._message_manager.task
+	assert any(
+		item.system_message and 'Summarize the products' in item.system_message
+		for item in agent._message_manager.state.agent_history_items
+	)
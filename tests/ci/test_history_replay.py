@@ -0,0 +1,82 @@
+"""Tests for replaying recorded actions with HistoryReplayer, without an LLM."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.replay import HistoryReplayer
+from browser_use.dom.views import DOMInteractedElement
+from browser_use.tools.service import Tools
+
+RECORDED_PAGE = """
+<html><body>
+	<input id="email" aria-label="Email">
+	<button id="go" onclick="location.href = '/done?email=' + document.getElementById('email').value">Continue</button>
+</body></html>
+"""
+
+# Same form, but with more elements in front of it so every index has shifted since the recording
+CHANGED_PAGE = """
+<html><body>
+	<nav><a href="/a">Home</a> <a href="/b">Pricing</a> <a href="/c">Blog</a></nav>
+	<div><button>Menu</button></div>
+	<input id="email" aria-label="Email">
+	<button id="go" onclick="location.href = '/done?email=' + document.getElementById('email').value">Continue</button>
+</body></html>
+"""
+
+
+async def _record_actions(browser_session, httpserver: HTTPServer) -> list[dict]:
+	"""Exported action list like AgentHistoryList.model_actions(), recorded on the original page."""
+	await Tools().navigate(url=httpserver.url_for('/recorded'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary(include_screenshot=False)
+	selector_map = await browser_session.get_selector_map()
+	indices = {node.attributes.get('id'): index for index, node in selector_map.items()}
+	elements = {
+		element_id: DOMInteractedElement.load_from_enhanced_dom_tree(selector_map[index]) for element_id, index in indices.items()
+	}
+	return [
+		{'navigate': {'url': httpserver.url_for('/changed'), 'new_tab': False}},
+		{'input': {'index': indices['email'], 'text': 'ada@example.com'}, 'interacted_element': elements['email'].to_dict()},
+		{
+			'click': {'index': indices['go']},
+			'interacted_element': elements['go'].to_dict(),
+			'expected_url': httpserver.url_for('/done'),
+		},
+		{'done': {'text': 'Signed up', 'success': True}},
+	]
+
+
+async def test_replay_re_resolves_elements_and_checks_urls(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/recorded').respond_with_data(RECORDED_PAGE, content_type='text/html')
+	httpserver.expect_request('/changed').respond_with_data(CHANGED_PAGE, content_type='text/html')
+	httpserver.expect_request('/done').respond_with_data('<html><body>Done</body></html>', content_type='text/html')
+	actions = await _record_actions(browser_session, httpserver)
+
+	replayer = HistoryReplayer(browser_session, delay=0, retry_delay=0)
+	results = await replayer.replay(actions)
+
+	assert [result.success for result in results] == [True, True, True, True]
+	assert results[1].match_level is not None
+	assert results[3].skipped  # done is not replayed
+	assert '/done?email=ada%40example.com' in await browser_session.get_current_page_url()
+
+
+async def test_replay_stops_on_failed_step_check_without_repeating_the_action(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/recorded').respond_with_data(RECORDED_PAGE, content_type='text/html')
+	httpserver.expect_request('/changed').respond_with_data(CHANGED_PAGE, content_type='text/html')
+	actions = await _record_actions(browser_session, httpserver)
+
+	checked: list[str] = []
+
+	def step_check(step, results, browser_session) -> bool:
+		checked.append(step.name)
+		return step.name != 'input'
+
+	replayer = HistoryReplayer(browser_session, delay=0, max_retries=1, retry_delay=0, step_check=step_check)
+	results = await replayer.replay(actions)
+
+	assert len(results) == 2  # stopped after the failing input step
+	assert results[0].success
+	assert not results[1].success and results[1].executed
+	assert results[1].attempts == 1  # the text was typed once, retrying would type it again
+	assert results[1].error is not None and 'step_check' in results[1].error
+	assert checked == ['navigate', 'input']
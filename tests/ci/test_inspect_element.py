@@ -0,0 +1,66 @@
+"""Tests for inspect_element: full details of a single element by index."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools
+from browser_use.tools.views import InspectElementAction
+
+FORM_PAGE = """
+<html><body>
+	<form>
+		<input id="email" name="email" type="email" value="ada@example.com" aria-label="Work email"
+			data-testid="email-field" style="position: absolute; left: 20px; top: 40px; width: 200px; height: 30px">
+		<button id="save" type="submit" style="margin-top: 120px" data-tracking="{TRACKING}">Save <span>changes</span></button>
+	</form>
+</body></html>
+""".replace('{TRACKING}', 'x' * 500)
+
+
+async def test_inspect_element_returns_full_details(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/form').respond_with_data(FORM_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/form'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+
+	email_index = await browser_session.get_index_by_id('email')
+	assert email_index is not None
+	result = await tools.inspect_element(params=InspectElementAction(index=email_index), browser_session=browser_session)
+	assert result.error is None
+	assert result.metadata is not None
+	element = result.metadata['element']
+	assert element['tag'] == 'input'
+	assert element['attributes']['data-testid'] == 'email-field'
+	assert element['value'] == 'ada@example.com'
+	assert element['role'] == 'textbox'
+	assert element['name'] == 'Work email'
+	assert element['rect'] == {'x': 20, 'y': 40, 'width': 200, 'height': 30}
+	assert element['visible'] is True
+	assert result.extracted_content is not None
+	assert 'data-testid="email-field"' in result.extracted_content
+	assert result.long_term_memory == f'Inspected element {email_index} <input>'
+	assert result.include_extracted_content_only_once is True
+
+	# outerHTML is bounded by max_html_chars
+	save_index = await browser_session.get_index_by_id('save')
+	assert save_index is not None
+	result = await tools.inspect_element(
+		params=InspectElementAction(index=save_index, max_html_chars=200), browser_session=browser_session
+	)
+	assert result.metadata is not None
+	element = result.metadata['element']
+	assert element['html'].startswith('<button id="save"')
+	assert len(element['html']) == 200 and element['html_length'] > 500
+	assert result.extracted_content is not None and 'showing the first 200' in result.extracted_content
+	assert element['role'] == 'button'
+	assert element['name'] == 'Save changes'
+	assert element['text'] == 'Save changes'
+
+
+async def test_inspect_element_unknown_index(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/form').respond_with_data(FORM_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/form'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+
+	result = await tools.inspect_element(params=InspectElementAction(index=999), browser_session=browser_session)
+	assert result.error is not None and 'not found' in result.error
@@ -0,0 +1,76 @@
+"""Tests for interactive (step approval) mode."""
+
+import asyncio
+
+import pytest
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import AgentOutput
+from tests.ci.conftest import create_mock_llm
+
+_PROPOSED_OUTPUT = """
+{
+	"evaluation_previous_goal": "Start",
+	"memory": "Nothing yet",
+	"next_goal": "Open example.com",
+	"action": [{"navigate": {"url": "https://example.com"}}]
+}
+"""
+
+
+def _agent_with_proposal(**kwargs) -> Agent:
+	agent = Agent(task='Test task', llm=create_mock_llm(), **kwargs)
+	agent.state.last_model_output = agent.AgentOutput.model_validate_json(_PROPOSED_OUTPUT)
+	return agent
+
+
+async def test_non_interactive_agent_skips_approval():
+	calls = []
+	agent = _agent_with_proposal(register_step_approval_callback=lambda *args: calls.append(args))
+
+	assert await agent._confirm_step_actions(None)  # type: ignore[arg-type]
+	assert calls == []
+
+
+async def test_rejected_actions_are_not_executed():
+	agent = _agent_with_proposal(interactive=True, register_step_approval_callback=lambda *args: False)
+
+	assert not await agent._confirm_step_actions(None)  # type: ignore[arg-type]
+	assert agent.state.last_result is not None
+	assert 'rejected' in (agent.state.last_result[0].long_term_memory or '')
+	assert agent.state.last_result[0].error is None
+
+
+async def test_async_callback_can_edit_actions():
+	async def approve_with_edit(browser_state_summary, model_output: AgentOutput, n_steps: int) -> AgentOutput:
+		assert n_steps == 1
+		edited = model_output.action[0].model_copy(deep=True)
+		edited.navigate.url = 'https://example.org'  # type: ignore[attr-defined]
+		return model_output.model_copy(update={'action': [edited]})
+
+	agent = _agent_with_proposal(interactive=True, register_step_approval_callback=approve_with_edit)
+
+	assert await agent._confirm_step_actions(None)  # type: ignore[arg-type]
+	assert agent.state.last_model_output is not None
+	assert agent.state.last_model_output.action[0].navigate.url == 'https://example.org'  # type: ignore[attr-defined]
+
+
+async def test_step_timeout_is_paused_only_while_approval_is_pending():
+	async def slow_approval(*args) -> bool:
+		await asyncio.sleep(1.5)
+		return True
+
+	agent = _agent_with_proposal(interactive=True, register_step_approval_callback=slow_approval, step_timeout=1)
+
+	async def approving_step(step_info=None) -> None:
+		assert await agent._confirm_step_actions(None)  # type: ignore[arg-type]
+
+	agent.step = approving_step  # type: ignore[method-assign]
+	await agent._run_step_with_timeout(None)  # type: ignore[arg-type]
+
+	async def hung_step(step_info=None) -> None:
+		await asyncio.sleep(30)
+
+	agent.step = hung_step  # type: ignore[method-assign]
+	with pytest.raises(TimeoutError):
+		await agent._run_step_with_timeout(None)  # type: ignore[arg-type]
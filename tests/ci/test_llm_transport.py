@@ -0,0 +1,65 @@
+"""Tests for LLMTransport: a shared base URL, HTTP client and headers for every LLM an agent calls."""
+
+import httpx
+import pytest
+
+from browser_use.agent.service import Agent
+from browser_use.llm.anthropic.chat import ChatAnthropic
+from browser_use.llm.openai.chat import ChatOpenAI
+from browser_use.llm.transport import LLMTransport
+
+
+def test_apply_fills_only_unset_options():
+	http_client = httpx.AsyncClient()
+	transport = LLMTransport(
+		base_url='https://llm-gateway.corp/v1',
+		http_client=http_client,
+		timeout=30,
+		default_headers={'X-Team': 'qa', 'X-Env': 'ci'},
+		organization='org-123',
+	)
+
+	llm = ChatOpenAI(model='gpt-4.1-mini', api_key='sk-test', default_headers={'X-Env': 'prod'}, timeout=5)
+	applied = transport.apply(llm)
+	assert set(applied) == {'base_url', 'http_client', 'organization', 'default_headers'}
+	assert llm.base_url == 'https://llm-gateway.corp/v1'
+	assert llm.http_client is http_client
+	assert llm.organization == 'org-123'
+	# Values set on the model itself win
+	assert llm.timeout == 5
+	assert llm.default_headers == {'X-Team': 'qa', 'X-Env': 'prod'}
+	assert llm.get_client().base_url == httpx.URL('https://llm-gateway.corp/v1/')
+
+	# Anthropic has no organization option and uses the SDK's NotGiven default for timeout
+	anthropic = ChatAnthropic(model='claude-sonnet-4-0', api_key='sk-ant-test')
+	assert set(transport.apply(anthropic)) == {'base_url', 'http_client', 'timeout', 'default_headers'}
+	assert anthropic.timeout == 30
+
+
+def test_proxy_builds_http_client():
+	transport = LLMTransport(proxy='http://proxy.corp:3128', timeout=10)
+	assert isinstance(transport.http_client, httpx.AsyncClient)
+	assert transport.http_client.timeout == httpx.Timeout(10)
+
+	with pytest.raises(ValueError):
+		LLMTransport(proxy='http://proxy.corp:3128', http_client=httpx.AsyncClient())
+
+
+def test_agent_applies_transport_to_all_models():
+	llm = ChatOpenAI(model='gpt-4.1-mini', api_key='sk-test')
+	extraction_llm = ChatOpenAI(model='gpt-4.1-nano', api_key='sk-test')
+	judge_llm = ChatAnthropic(model='claude-sonnet-4-0', api_key='sk-ant-test', base_url='https://judge.corp')
+	setattr(llm, '_verified_api_keys', True)
+
+	Agent(
+		task='Open the dashboard',
+		llm=llm,
+		page_extraction_llm=extraction_llm,
+		judge_llm=judge_llm,
+		llm_transport=LLMTransport(base_url='https://llm-gateway.corp/v1', default_headers={'X-Team': 'qa'}),
+	)
+
+	assert llm.base_url == extraction_llm.base_url == 'https://llm-gateway.corp/v1'
+	assert extraction_llm.default_headers == {'X-Team': 'qa'}
+	assert judge_llm.base_url == 'https://judge.corp'
+	assert judge_llm.default_headers == {'X-Team': 'qa'}
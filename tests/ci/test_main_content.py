@@ -0,0 +1,74 @@
+"""Tests for main content extraction: boilerplate filtering and Readability-style main content detection."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.dom.markdown_extractor import extract_clean_markdown
+from browser_use.tools.service import Tools
+
+PARAGRAPHS = ''.join(
+	f'<p>Paragraph {i} of the story explains, in some detail, how the harbour bridge was rebuilt after the flood.</p>'
+	for i in range(6)
+)
+
+# No <main> or <article>: the story has to be found by its paragraph density
+NEWS_PAGE = f"""
+<html><body>
+	<header><a href="/">Daily News</a> <a href="/login">Log in</a></header>
+	<nav><a href="/world">World</a> <a href="/sport">Sport</a> <a href="/culture">Culture</a></nav>
+	<div class="cookie-banner">We use cookies to improve your experience. <button>Accept all</button></div>
+	<div class="layout">
+		<div class="story"><h1>Harbour bridge reopens</h1>{PARAGRAPHS}</div>
+		<div class="sidebarRight">
+			<h3>Most read</h3><ul><li><a href="/a">Another story about something else entirely</a></li></ul>
+		</div>
+	</div>
+	<footer>Copyright Daily News. <a href="/privacy">Privacy policy</a></footer>
+</body></html>
+"""
+
+ARTICLE_PAGE = f"""
+<html><body>
+	<nav><a href="/docs">Docs</a></nav>
+	<main><article><header><h1>Install guide</h1><p>Updated today</p></header>{PARAGRAPHS}
+		<div class="share-buttons">Share on social media</div></article></main>
+	<footer>Footer links</footer>
+</body></html>
+"""
+
+
+async def _open(browser_session, httpserver: HTTPServer, path: str, html: str) -> None:
+	httpserver.expect_request(path).respond_with_data(html, content_type='text/html')
+	await Tools().navigate(url=httpserver.url_for(path), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary()
+
+
+async def test_detects_story_by_paragraph_density(browser_session, httpserver: HTTPServer):
+	await _open(browser_session, httpserver, '/news', NEWS_PAGE)
+
+	text = await browser_session.get_page_text(max_chars=None)
+	assert 'Harbour bridge reopens' in text and 'Paragraph 5 of the story' in text
+	for noise in ('Daily News', 'Culture', 'We use cookies', 'Most read', 'Privacy policy'):
+		assert noise not in text
+
+	full_text = await browser_session.get_page_text(max_chars=None, main_content_only=False)
+	assert 'Culture' in full_text and 'Privacy policy' in full_text
+
+	_, stats = await extract_clean_markdown(browser_session=browser_session, main_content_only=True)
+	assert stats['main_content'] == 'div'
+
+
+async def test_uses_main_and_keeps_article_header(browser_session, httpserver: HTTPServer):
+	await _open(browser_session, httpserver, '/docs', ARTICLE_PAGE)
+
+	text = await browser_session.get_page_text(max_chars=None)
+	assert 'Install guide' in text and 'Updated today' in text and 'Paragraph 0' in text
+	assert 'Share on social media' not in text and 'Footer links' not in text and 'Docs' not in text
+
+
+async def test_page_text_budget(browser_session, httpserver: HTTPServer):
+	await _open(browser_session, httpserver, '/news', NEWS_PAGE)
+
+	text = await browser_session.get_page_text(max_chars=300)
+	body, marker = text.rsplit('\n', 1)
+	assert len(body) <= 300
+	assert marker.startswith('... [') and marker.endswith('more characters]')
@@ -144,3 +144,29 @@ class TestPreservesLinksAndEncoding:
 		assert '%20' in content
 		assert '%2F' in content
 		assert '%26' in content
+
+
+class TestLinksAndTablesAsMarkdown:
+	"""Links and tabular structure must survive the HTML -> markdown path used by extract."""
+
+	def test_table_without_header_cells_uses_first_row_as_header(self):
+		from browser_use.dom.markdown_extractor import convert_html_to_markdown
+
+		html = '<table><tr><td>Plan</td><td>Price</td></tr><tr><td>Pro</td><td>$20</td></tr></table>'
+		content, _, _ = convert_html_to_markdown(html)
+
+		lines = content.split('\n')
+		assert lines[0] == '| Plan | Price |'
+		assert lines[1] == '| --- | --- |'
+		assert lines[2] == '| Pro | $20 |'
+
+	def test_relative_links_resolved_against_page_url(self):
+		from browser_use.dom.serializer.html_serializer import HTMLSerializer
+
+		serializer = HTMLSerializer(extract_links=True, base_url='https://example.com/docs/intro')
+		assert serializer._serialize_attributes({'href': '../pricing?plan=pro'}) == 'href="https://example.com/pricing?plan=pro"'
+		assert serializer._serialize_attributes({'href': '#install'}) == 'href="https://example.com/docs/intro#install"'
+		assert serializer._serialize_attributes({'href': 'javascript:void(0)', 'class': 'btn'}) == 'class="btn"'
+
+		# Without extract_links hrefs are dropped entirely
+		assert HTMLSerializer(base_url='https://example.com')._serialize_attributes({'href': '/a', 'id': 'x'}) == 'id="x"'
@@ -0,0 +1,66 @@
+"""Tests for creating the MCP server's LLM from the llm config entry, including Azure OpenAI deployments."""
+
+import pytest
+
+from browser_use.llm.azure.chat import ChatAzureOpenAI
+from browser_use.llm.openai.chat import ChatOpenAI
+from browser_use.mcp.server import create_llm_from_config
+
+AZURE_ENV_VARS = (
+	'MODEL_PROVIDER',
+	'AZURE_OPENAI_ENDPOINT',
+	'AZURE_OPENAI_DEPLOYMENT',
+	'AZURE_OPENAI_API_VERSION',
+	'AZURE_OPENAI_KEY',
+	'AZURE_OPENAI_API_KEY',
+	'AZURE_OPENAI_AD_TOKEN',
+)
+
+
+@pytest.fixture(autouse=True)
+def clean_env(monkeypatch):
+	for name in AZURE_ENV_VARS:
+		monkeypatch.delenv(name, raising=False)
+
+
+def test_azure_entry_uses_deployment_url_and_api_version():
+	llm = create_llm_from_config(
+		{
+			'model_provider': 'azure',
+			'azure_endpoint': 'https://contoso.openai.azure.com',
+			'azure_deployment': 'browser-agent',
+			'api_version': '2025-03-01-preview',
+			'api_key': 'azure-key',
+		}
+	)
+	assert isinstance(llm, ChatAzureOpenAI)
+	assert llm.model == 'browser-agent'  # the deployment name when no model is configured
+
+	client = llm.get_client()
+	assert str(client.base_url) == 'https://contoso.openai.azure.com/openai/deployments/browser-agent/'
+	assert client.default_query['api-version'] == '2025-03-01-preview'
+
+
+def test_azure_from_env_with_ad_token(monkeypatch):
+	monkeypatch.setenv('MODEL_PROVIDER', 'azure')
+	monkeypatch.setenv('AZURE_OPENAI_ENDPOINT', 'https://contoso.openai.azure.com')
+	monkeypatch.setenv('AZURE_OPENAI_DEPLOYMENT', 'browser-agent')
+	monkeypatch.setenv('AZURE_OPENAI_AD_TOKEN', 'entra-token')
+
+	llm = create_llm_from_config({}, model='gpt-4.1')
+	assert isinstance(llm, ChatAzureOpenAI)
+	assert llm.model == 'gpt-4.1' and llm.azure_deployment == 'browser-agent'
+	assert llm.azure_ad_token == 'entra-token' and llm.api_key is None
+
+
+def test_missing_credentials_raise():
+	with pytest.raises(ValueError, match='AZURE_OPENAI_ENDPOINT'):
+		create_llm_from_config({'model_provider': 'azure', 'api_key': 'azure-key'})
+	with pytest.raises(ValueError, match='AZURE_OPENAI_KEY'):
+		create_llm_from_config({'azure_endpoint': 'https://contoso.openai.azure.com'})
+
+
+def test_openai_entry_keeps_base_url():
+	llm = create_llm_from_config({'api_key': 'sk-test', 'base_url': 'http://localhost:8000/v1', 'model': 'gpt-4.1-mini'})
+	assert isinstance(llm, ChatOpenAI) and not isinstance(llm, ChatAzureOpenAI)
+	assert llm.model == 'gpt-4.1-mini' and str(llm.base_url) == 'http://localhost:8000/v1'
@@ -0,0 +1,58 @@
+"""Tests for the Prometheus metrics of agents and browser sessions."""
+
+import pytest
+
+pytest.importorskip('prometheus_client')
+
+from browser_use.agent.service import Agent
+from browser_use.llm.views import ChatInvokeCompletion, ChatInvokeUsage
+from browser_use.metrics import AgentMetrics
+from tests.ci.conftest import create_mock_llm
+
+
+async def test_instrument_llm_records_latency_and_tokens():
+	metrics = AgentMetrics()
+	llm = create_mock_llm()
+	usage = ChatInvokeUsage(
+		prompt_tokens=120,
+		prompt_cached_tokens=100,
+		prompt_cache_creation_tokens=None,
+		prompt_image_tokens=None,
+		completion_tokens=30,
+		total_tokens=150,
+	)
+	llm.ainvoke.side_effect = None
+	llm.ainvoke.return_value = ChatInvokeCompletion(completion='ok', usage=usage)
+
+	metrics.instrument_llm(llm)
+	metrics.instrument_llm(llm)  # instrumenting twice must not double count
+	await llm.ainvoke([])
+
+	labels = {'provider': 'mock', 'model': 'mock-llm'}
+	assert metrics.registry.get_sample_value('browser_use_llm_tokens_total', {**labels, 'kind': 'prompt'}) == 120
+	assert metrics.registry.get_sample_value('browser_use_llm_tokens_total', {**labels, 'kind': 'completion'}) == 30
+	assert metrics.registry.get_sample_value('browser_use_llm_tokens_total', {**labels, 'kind': 'prompt_cached'}) == 100
+	count = metrics.registry.get_sample_value('browser_use_llm_request_duration_seconds_count', {**labels, 'status': 'success'})
+	assert count == 1
+	assert b'browser_use_llm_tokens_total' in metrics.render()
+
+
+async def test_agent_run_records_metrics(browser_session):
+	metrics = AgentMetrics()
+	actions = ['{"memory": "open", "action": [{"navigate": {"url": "about:blank", "new_tab": false}}]}']
+	agent = Agent(
+		task='Open a blank page', llm=create_mock_llm(actions), browser_session=browser_session, use_vision=True, metrics=metrics
+	)
+	history = await agent.run(max_steps=3)
+	assert history.is_done()
+
+	sample = metrics.registry.get_sample_value
+	assert sample('browser_use_agent_runs_total', {'outcome': 'success'}) == 1
+	assert sample('browser_use_agent_steps_total') == history.number_of_steps()
+	assert sample('browser_use_agent_steps_per_run_sum') == history.number_of_steps()
+	assert sample('browser_use_agent_actions_total', {'action': 'navigate', 'status': 'success'}) == 1
+	assert sample('browser_use_agent_actions_total', {'action': 'done', 'status': 'success'}) == 1
+	assert (sample('browser_use_cdp_command_duration_seconds_count', {'method': 'Runtime.evaluate'}) or 0) > 0
+	assert (sample('browser_use_screenshot_size_bytes_count') or 0) > 0
+
+	browser_session.attach_metrics(None)
@@ -0,0 +1,64 @@
+"""Tests for model routing: escalating to a strong model when the agent struggles and returning afterwards."""
+
+import json
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import AgentHistoryList, ModelRouting
+from tests.ci.conftest import create_mock_llm
+
+
+def _step(action: dict) -> str:
+	return json.dumps({'memory': 'working on it', 'action': [action]})
+
+
+def _models(history: AgentHistoryList) -> list[str | None]:
+	return [item.metadata.llm_model if item.metadata else None for item in history.history]
+
+
+def _strong_llm(actions: list[str]):
+	llm = create_mock_llm(actions)
+	llm.model = 'mock-strong'
+	return llm
+
+
+async def test_escalates_after_failures_and_deescalates(browser_session):
+	missing_click = _step({'click': {'index': 9999}})
+	blank = _step({'navigate': {'url': 'about:blank', 'new_tab': False}})
+	strong_llm = _strong_llm([blank, blank])
+	agent = Agent(
+		task='Click the button',
+		llm=create_mock_llm([missing_click, missing_click]),
+		browser_session=browser_session,
+		model_routing=ModelRouting(strong_llm=strong_llm, escalate_after_failures=2, deescalate_after_steps=2),
+	)
+	history = await agent.run(max_steps=8)
+
+	assert history.is_done()
+	assert _models(history) == ['mock-llm', 'mock-llm', 'mock-strong', 'mock-strong', 'mock-llm']
+	assert strong_llm.ainvoke.call_count == 2
+
+
+async def test_escalates_on_parse_errors_and_large_pages(browser_session, httpserver: HTTPServer):
+	buttons = ''.join(f'<button>Option {i}</button>' for i in range(3))
+	httpserver.expect_request('/options').respond_with_data(f'<html><body>{buttons}</body></html>', content_type='text/html')
+	strong_llm = _strong_llm(
+		[
+			_step({'navigate': {'url': httpserver.url_for('/options'), 'new_tab': False}}),
+			_step({'navigate': {'url': 'about:blank', 'new_tab': False}}),
+		]
+	)
+	agent = Agent(
+		task='Pick an option',
+		llm=create_mock_llm(['this is not json']),
+		browser_session=browser_session,
+		model_routing=ModelRouting(
+			strong_llm=strong_llm, escalate_after_failures=5, escalate_above_elements=2, deescalate_after_steps=1
+		),
+	)
+	history = await agent.run(max_steps=8)
+
+	# Parse error -> strong, then the options page keeps it strong, back on the blank page it returns
+	assert history.is_done()
+	assert _models(history) == ['mock-llm', 'mock-strong', 'mock-strong', 'mock-llm']
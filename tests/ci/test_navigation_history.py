@@ -0,0 +1,49 @@
+"""Per-tab navigation history recorded by the SessionManager and shown in the browser state."""
+
+import asyncio
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools
+
+
+def _serve(httpserver: HTTPServer, *paths: str) -> None:
+	for path in paths:
+		html = f'<html><head><title>{path}</title></head><body><a href="#more">{path}</a></body></html>'
+		httpserver.expect_request(path).respond_with_data(html, content_type='text/html')
+
+
+async def test_tab_history_in_browser_state(browser_session, httpserver: HTTPServer):
+	_serve(httpserver, '/a', '/b', '/c', '/other')
+	tools = Tools()
+	for path in ('/a', '/b', '/b', '/c'):
+		await tools.navigate(url=httpserver.url_for(path), new_tab=False, browser_session=browser_session)
+	first_tab = browser_session.agent_focus_target_id
+	assert first_tab is not None
+
+	await tools.navigate(url=httpserver.url_for('/other'), new_tab=True, browser_session=browser_session)
+	# Same-document navigations count as visits too
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "history.pushState({}, '', '/other/page-2')"}, session_id=cdp_session.session_id
+	)
+	await asyncio.sleep(0.3)
+	second_tab = browser_session.agent_focus_target_id
+
+	assert browser_session.session_manager is not None
+	# Reloading /b is not a new visit
+	assert browser_session.session_manager.get_navigation_history(first_tab) == [
+		httpserver.url_for('/a'),
+		httpserver.url_for('/b'),
+		httpserver.url_for('/c'),
+	]
+
+	state = await browser_session.get_browser_state_summary()
+	tabs = {tab.target_id: tab for tab in state.tabs}
+	assert tabs[first_tab].recent_urls == [httpserver.url_for('/a'), httpserver.url_for('/b')]
+	assert tabs[second_tab].url == httpserver.url_for('/other/page-2')
+	assert tabs[second_tab].recent_urls == [httpserver.url_for('/other')]
+
+	await tools.close(tab_id=second_tab[-4:], browser_session=browser_session)
+	await asyncio.sleep(0.3)
+	assert browser_session.session_manager.get_navigation_history(second_tab) == []
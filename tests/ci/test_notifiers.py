@@ -0,0 +1,96 @@
+"""Tests for run notifications: built-in webhook/Slack notifiers and the events an agent sends."""
+
+import json
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.notifiers import AgentNotification, Notifier, SlackNotifier, WebhookNotifier
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+class RecordingNotifier(Notifier):
+	def __init__(self):
+		self.notifications: list[AgentNotification] = []
+
+	async def notify(self, notification: AgentNotification) -> None:
+		self.notifications.append(notification)
+
+
+class BrokenNotifier(Notifier):
+	async def notify(self, notification: AgentNotification) -> None:
+		raise ConnectionError('unreachable')
+
+
+async def test_run_sends_success_notification(browser_session):
+	recorder = RecordingNotifier()
+	agent = Agent(task='Say hi', llm=create_mock_llm(), browser_session=browser_session, notifiers=[BrokenNotifier(), recorder])
+	history = await agent.run(max_steps=3)
+	assert history.is_done()
+
+	assert [notification.event for notification in recorder.notifications] == ['run_succeeded']
+	notification = recorder.notifications[0]
+	assert notification.task == 'Say hi' and notification.success is True
+	assert notification.message == 'Task completed successfully'
+	assert notification.agent_id == agent.id and notification.steps == 1
+
+
+async def test_interactive_approval_sends_human_input_needed():
+	recorder = RecordingNotifier()
+	agent = Agent(
+		task='Open example.com',
+		llm=create_mock_llm(),
+		interactive=True,
+		register_step_approval_callback=lambda *args: True,
+		notifiers=[recorder],
+	)
+	agent.state.last_model_output = agent.AgentOutput.model_validate_json(
+		json.dumps(
+			{
+				'evaluation_previous_goal': 'Start',
+				'memory': 'Nothing yet',
+				'next_goal': 'Open example.com',
+				'action': [{'navigate': {'url': 'https://example.com'}}],
+			}
+		)
+	)
+
+	assert await agent._confirm_step_actions(None)  # type: ignore[arg-type]
+	assert [notification.event for notification in recorder.notifications] == ['human_input_needed']
+	assert 'navigate' in recorder.notifications[0].message
+
+
+async def test_webhook_and_slack_payloads(httpserver: HTTPServer):
+	notification = AgentNotification(
+		event='run_failed', agent_id='abc', task='Export invoices', message='Login failed', steps=4, success=False
+	)
+	httpserver.expect_request('/hook', method='POST', headers={'X-Token': 't'}).respond_with_data('ok')
+	httpserver.expect_request('/slack', method='POST').respond_with_data('ok')
+
+	await WebhookNotifier(httpserver.url_for('/hook'), headers={'X-Token': 't'}).notify(notification)
+	await SlackNotifier(httpserver.url_for('/slack')).notify(notification)
+	# Filtered out by events, nothing is sent
+	await WebhookNotifier(httpserver.url_for('/filtered'), events=['run_succeeded']).notify(notification)
+
+	requests = [request for request, _ in httpserver.log]
+	assert [request.path for request in requests] == ['/hook', '/slack']
+	webhook_body = json.loads(requests[0].data)
+	assert webhook_body['event'] == 'run_failed' and webhook_body['message'] == 'Login failed'
+	slack_text = json.loads(requests[1].data)['text']
+	assert 'Export invoices' in slack_text and 'Login failed' in slack_text
+
+
+async def test_notifications_mask_sensitive_data():
+	recorder = RecordingNotifier()
+	agent = Agent(
+		task='Log in as jane@example.com with s3cr3t-pass',
+		llm=create_mock_llm(),
+		sensitive_data={'https://*.example.com': {'user': 'jane@example.com', 'password': 's3cr3t-pass'}},
+		notifiers=[recorder],
+	)
+
+	await agent._notify('run_failed', 'Rejected password s3cr3t-pass', False)
+	notification = recorder.notifications[0]
+	assert notification.task == 'Log in as <secret>user</secret> with <secret>password</secret>'
+	assert notification.message == 'Rejected password <secret>password</secret>'
+	assert 's3cr3t-pass' not in notification.model_dump_json()
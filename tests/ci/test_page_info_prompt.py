@@ -0,0 +1,62 @@
+"""Scroll position and viewport info in the <page_info> section of the browser state."""
+
+from browser_use.agent.prompts import AgentMessagePrompt
+from browser_use.browser.views import BrowserStateSummary, PageInfo, TabInfo
+from browser_use.dom.views import SerializedDOMState
+from browser_use.filesystem.file_system import FileSystem
+
+
+def _page_info_text(tmp_path, scroll_y: int, page_height: int = 2160, page_width: int = 1280, scroll_x: int = 0) -> str:
+	viewport_width, viewport_height = 1280, 720
+	page_info = PageInfo(
+		viewport_width=viewport_width,
+		viewport_height=viewport_height,
+		page_width=page_width,
+		page_height=page_height,
+		scroll_x=scroll_x,
+		scroll_y=scroll_y,
+		pixels_above=scroll_y,
+		pixels_below=max(0, page_height - viewport_height - scroll_y),
+		pixels_left=scroll_x,
+		pixels_right=max(0, page_width - viewport_width - scroll_x),
+	)
+	state = BrowserStateSummary(
+		url='https://example.test/long',
+		title='Long page',
+		tabs=[TabInfo(target_id='abcd1234', url='https://example.test/long', title='Long page')],
+		page_info=page_info,
+		dom_state=SerializedDOMState(_root=None, selector_map={}),
+		screenshot=None,
+	)
+	prompt = AgentMessagePrompt(
+		browser_state_summary=state, file_system=FileSystem(base_dir=str(tmp_path), create_default_files=False)
+	)
+	description = prompt._get_browser_state_description()
+	start = description.index('<page_info>')
+	return description[start : description.index('</page_info>') + len('</page_info>')]
+
+
+def test_page_info_at_top(tmp_path):
+	text = _page_info_text(tmp_path, scroll_y=0)
+	assert text == (
+		'<page_info>viewport 1280x720px, page 1280x2160px, scrollY 0px (at top of page)\n'
+		'0.0 pages above, 2.0 pages below — scroll down to reveal more content</page_info>'
+	)
+
+
+def test_page_info_middle_and_bottom(tmp_path):
+	middle = _page_info_text(tmp_path, scroll_y=720)
+	assert 'scrollY 720px\n1.0 pages above, 1.0 pages below' in middle
+
+	bottom = _page_info_text(tmp_path, scroll_y=1440)
+	assert bottom.endswith('scrollY 1440px (at bottom of page)\n2.0 pages above, 0.0 pages below</page_info>')
+
+
+def test_page_info_short_and_wide_page(tmp_path):
+	short = _page_info_text(tmp_path, scroll_y=0, page_height=600)
+	assert '(whole page visible)' in short
+	assert 'scroll down' not in short
+
+	wide = _page_info_text(tmp_path, scroll_y=0, page_width=2000, scroll_x=200)
+	assert 'scrollX 200px' in wide
+	assert '200px left, 520px right — the page also scrolls horizontally' in wide
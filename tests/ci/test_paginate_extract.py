@@ -0,0 +1,108 @@
+"""Tests for paginate_extract: extracting items across result pages into one JSON file."""
+
+import json
+import re
+from unittest.mock import AsyncMock
+
+import pytest
+from pydantic import ValidationError
+from pytest_httpserver import HTTPServer
+
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.llm.base import BaseChatModel
+from browser_use.llm.views import ChatInvokeCompletion
+from browser_use.tools.service import Tools
+from browser_use.tools.views import PaginateExtractAction
+
+
+def _results_page(page: int, last_page: int) -> str:
+	products = ''.join(f'<li>Product {page}-{n} costs ${page}{n}.00</li>' for n in (1, 2))
+	if page < last_page:
+		next_link = f'<a href="/products?page={page + 1}">Next</a>'
+	else:
+		next_link = '<a class="disabled" aria-disabled="true">Next</a>'
+	return f'<html><body><h1>Page {page}</h1><ul>{products}</ul>{next_link}</body></html>'
+
+
+def _products_llm() -> BaseChatModel:
+	"""Extraction LLM that returns the products listed in the page markdown."""
+	llm = AsyncMock(spec=BaseChatModel)
+	llm.model = 'mock-extraction-llm'
+	llm.provider = 'mock'
+
+	async def mock_ainvoke(messages, output_format=None, **kwargs):
+		found = re.findall(r'Product (\d+-\d+) costs (\$[\d.]+)', messages[-1].content)
+		items = [{'name': f'Product {name}', 'price': price} for name, price in found]
+		return ChatInvokeCompletion(completion=output_format.model_validate({'items': items}), usage=None)
+
+	llm.ainvoke.side_effect = mock_ainvoke
+	return llm
+
+
+@pytest.fixture
+def products_url(httpserver: HTTPServer):
+	for page in (1, 2, 3):
+		httpserver.expect_request('/products', query_string=f'page={page}').respond_with_data(
+			_results_page(page, last_page=3), content_type='text/html'
+		)
+	return httpserver.url_for('/products?page=1')
+
+
+async def test_paginate_extract_until_last_page(browser_session, products_url, tmp_path):
+	tools = Tools()
+	file_system = FileSystem(tmp_path)
+	await tools.navigate(url=products_url, new_tab=False, browser_session=browser_session)
+
+	result = await tools.paginate_extract(
+		params=PaginateExtractAction(query='all products', fields=['name', 'price'], next_text='Next', max_pages=10),
+		browser_session=browser_session,
+		page_extraction_llm=_products_llm(),
+		file_system=file_system,
+	)
+	assert result.error is None
+	assert result.metadata == {'pages': 3, 'items': 6, 'stop_reason': 'no next page link found'}
+
+	saved_file = file_system.get_file('paginated_results.json')
+	assert saved_file is not None
+	saved = json.loads(saved_file.read())
+	assert [item['name'] for item in saved] == [f'Product {page}-{n}' for page in (1, 2, 3) for n in (1, 2)]
+	assert saved[0] == {'name': 'Product 1-1', 'price': '$11.00'}
+	assert await browser_session.get_current_page_url() == products_url.replace('page=1', 'page=3')
+
+
+async def test_paginate_extract_stops_at_max_pages(browser_session, products_url, tmp_path):
+	tools = Tools()
+	file_system = FileSystem(tmp_path)
+	await tools.navigate(url=products_url, new_tab=False, browser_session=browser_session)
+
+	result = await tools.paginate_extract(
+		params=PaginateExtractAction(
+			query='all products', fields=['name'], next_selector='a[href*="page="]', max_pages=2, file_name='products.json'
+		),
+		browser_session=browser_session,
+		page_extraction_llm=_products_llm(),
+		file_system=file_system,
+	)
+	assert result.metadata == {'pages': 2, 'items': 4, 'stop_reason': 'reached max_pages=2'}
+	assert result.extracted_content is not None and '"Product 2-2"' in result.extracted_content
+
+	with pytest.raises(ValidationError):
+		PaginateExtractAction(query='all products', fields=['name'])
+
+
+async def test_paginate_extract_reads_every_chunk_of_a_long_page(browser_session, httpserver: HTTPServer, tmp_path):
+	products = ''.join(f'<p>Product 1-{n} costs $1{n}.00 and ships in two to three business days.</p>' for n in range(30))
+	httpserver.expect_request('/long').respond_with_data(f'<html><body>{products}</body></html>', content_type='text/html')
+	tools = Tools(extract_max_chars=500)
+	file_system = FileSystem(tmp_path)
+	await tools.navigate(url=httpserver.url_for('/long'), new_tab=False, browser_session=browser_session)
+
+	llm = _products_llm()
+	result = await tools.paginate_extract(
+		params=PaginateExtractAction(query='all products', fields=['name', 'price'], next_text='Next', max_pages=1),
+		browser_session=browser_session,
+		page_extraction_llm=llm,
+		file_system=file_system,
+	)
+	assert result.metadata is not None and result.metadata['items'] == 30
+	assert llm.ainvoke.call_count > 1
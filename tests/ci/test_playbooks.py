@@ -0,0 +1,92 @@
+"""Tests for site-specific playbooks: guidance and hooks applied while the agent is on matching pages."""
+
+import json
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.playbooks import Playbook, PlaybookRegistry
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+def _step(action: dict) -> str:
+	return json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Unknown',
+			'memory': 'Working on it',
+			'next_goal': 'Continue',
+			'action': [action],
+		}
+	)
+
+
+def test_registry_matches_domain_patterns():
+	registry = PlaybookRegistry([Playbook(name='linkedin', domains=['*.linkedin.com'], guidance='Use the search bar.')])
+	assert [playbook.name for playbook in registry.get_matching('https://www.linkedin.com/feed/')] == ['linkedin']
+	assert [playbook.name for playbook in registry.get_matching('https://linkedin.com/in/someone')] == ['linkedin']
+	assert registry.get_matching('https://example.com/?ref=linkedin.com') == []
+	assert registry.get_matching('about:blank') == []
+
+	with pytest.raises(ValueError):
+		registry.register(Playbook(name='linkedin', domains=['*.linkedin.com']))
+	with pytest.raises(ValueError):
+		Playbook(name='nowhere', domains=[])
+
+
+async def test_playbook_guidance_and_hooks_on_matching_pages(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/shop').respond_with_data('<html><body><h1>Shop</h1></body></html>', content_type='text/html')
+	shop_url = httpserver.url_for('/shop')
+
+	calls: list[str] = []
+
+	async def check_login(agent, browser_state_summary) -> str:
+		calls.append(f'first_visit {browser_state_summary.url}')
+		return 'You are not logged in, sign in before adding items to the cart.'
+
+	async def before_step(agent, browser_state_summary) -> None:
+		calls.append('before_step')
+
+	async def after_step(agent, browser_state_summary) -> str:
+		calls.append('after_step')
+		return 'Cart is empty.'
+
+	registry = PlaybookRegistry()
+	registry.register(
+		Playbook(
+			name='shop',
+			domains=['http://127.0.0.1', 'http://localhost'],
+			guidance='Prices include tax.',
+			on_first_visit=check_login,
+			before_step=before_step,
+			after_step=after_step,
+		)
+	)
+	registry.register(Playbook(name='other', domains=['*.example.com'], guidance='Never shown here.'))
+
+	llm = create_mock_llm(
+		actions=[
+			_step({'navigate': {'url': shop_url, 'new_tab': False}}),
+			_step({'scroll': {'down': True, 'pages': 1.0}}),
+		]
+	)
+	llm_ainvoke = llm.ainvoke
+	agent = Agent(task='Buy a mug', llm=llm, browser_session=browser_session, playbooks=registry, directly_open_url=False)
+	history = await agent.run(max_steps=5)
+	assert history.is_done()
+
+	# The first step starts on a blank page where no playbook applies, the rest run on the shop
+	assert calls == [f'first_visit {shop_url}', 'before_step', 'after_step', 'before_step', 'after_step']
+
+	state_messages = [
+		next(message.text for message in call.args[0] if '<browser_state>' in message.text) for call in llm_ainvoke.call_args_list
+	]
+	assert '<site_guidance>' not in state_messages[0]
+	assert (
+		'<site_guidance>\n[shop] Prices include tax.\n'
+		'[shop] You are not logged in, sign in before adding items to the cart.\n</site_guidance>'
+	) in state_messages[1]
+	assert 'Never shown here.' not in state_messages[1]
+	assert 'You are not logged in' not in state_messages[2]
+	assert '[shop] Cart is empty.' in state_messages[2]
@@ -0,0 +1,94 @@
+"""Tests for procedures learned from successful runs and reused by agents on similar tasks."""
+
+import json
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.procedures import FileProcedureStore, InMemoryProcedureStore, task_keywords
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+def _navigate(url: str) -> str:
+	return json.dumps(
+		{
+			'evaluation_previous_goal': 'Start',
+			'memory': 'Nothing yet',
+			'next_goal': 'Open the pricing page',
+			'action': [{'navigate': {'url': url}}],
+		}
+	)
+
+
+def test_task_keywords_ignore_filler_and_urls():
+	assert task_keywords('Please find the pricing of the Pro plan on https://example.com/pricing') == ['plan', 'pricing', 'pro']
+
+
+async def test_successful_run_is_learned_and_offered_to_similar_tasks(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/pricing').respond_with_data('<h1>Pro plan: $20</h1>', content_type='text/html')
+	store = InMemoryProcedureStore()
+	agent = Agent(
+		task='Look up the price of the Pro plan',
+		llm=create_mock_llm([_navigate(httpserver.url_for('/pricing'))]),
+		browser_session=browser_session,
+		procedure_store=store,
+	)
+	history = await agent.run(max_steps=3)
+	assert history.is_successful()
+
+	[procedure] = store.load()
+	assert procedure.domain == 'localhost'
+	assert [step.name for step in procedure.steps] == ['navigate']
+	assert procedure.steps[0].goal == 'Open the pricing page'
+	assert procedure.replay_actions()[0]['navigate']['url'] == httpserver.url_for('/pricing')
+
+	similar = Agent(task='Check the price of the Pro plan', llm=create_mock_llm(), procedure_store=store)
+	similar._add_procedure_guidance()
+	notes = [item.system_message or '' for item in similar._message_manager.state.agent_history_items]
+	assert any('Procedures that completed similar tasks before' in note and 'Open the pricing page' in note for note in notes)
+
+	# Procedures learned on another site are not offered for tasks naming a different domain
+	assert store.find('Check the price of the Pro plan', domain='other.example.com') == []
+	unrelated = Agent(task='Book a table for two tonight', llm=create_mock_llm(), procedure_store=store)
+	unrelated._add_procedure_guidance()
+	assert not any('Procedures' in (item.system_message or '') for item in unrelated._message_manager.state.agent_history_items)
+
+
+async def test_file_store_merges_and_shares_libraries(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/pricing').respond_with_data('<h1>Pro plan: $20</h1>', content_type='text/html')
+	store = FileProcedureStore(tmp_path / 'procedures')
+	for _ in range(2):
+		agent = Agent(
+			task='Look up the price of the Pro plan',
+			llm=create_mock_llm([_navigate(httpserver.url_for('/pricing'))]),
+			browser_session=browser_session,
+			procedure_store=store,
+		)
+		await agent.run(max_steps=3)
+
+	[procedure] = store.load()
+	assert procedure.success_count == 2
+
+	library = store.export_library(tmp_path / 'library.json')
+	team_store = FileProcedureStore(tmp_path / 'team')
+	assert team_store.import_library(library) == 1
+	assert team_store.find('Look up the Pro plan price')[0].id == procedure.id
+
+
+async def test_learned_procedure_masks_sensitive_data(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/login').respond_with_data('<input id="code">', content_type='text/html')
+	url = httpserver.url_for('/login') + '?token=tok-91f3'
+	store = InMemoryProcedureStore()
+	agent = Agent(
+		task='Open the login page with token tok-91f3',
+		llm=create_mock_llm([_navigate(url)]),
+		browser_session=browser_session,
+		sensitive_data={'token': 'tok-91f3'},
+		procedure_store=store,
+	)
+	await agent.run(max_steps=3)
+
+	[procedure] = store.load()
+	assert 'tok-91f3' not in procedure.model_dump_json()
+	assert procedure.task == 'Open the login page with token <secret>token</secret>'
+	assert procedure.steps[0].action['navigate']['url'].endswith('?token=<secret>token</secret>')
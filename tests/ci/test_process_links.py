@@ -0,0 +1,112 @@
+"""Tests for the process_links action (parallel extraction from links in background tabs)."""
+
+import asyncio
+import re
+import tempfile
+from unittest.mock import AsyncMock
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.views import ActionResult
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.llm.base import BaseChatModel
+from browser_use.llm.views import ChatInvokeCompletion
+from browser_use.tools.service import Tools
+
+
+def _make_extraction_llm() -> BaseChatModel:
+	"""Mock extraction LLM that answers with the first heading found in the page content."""
+	llm = AsyncMock(spec=BaseChatModel)
+	llm.model = 'mock-extraction-llm'
+	llm._verified_api_keys = True
+	llm.provider = 'mock'
+	llm.name = 'mock-extraction-llm'
+	llm.model_name = 'mock-extraction-llm'
+
+	async def mock_ainvoke(messages, output_format=None, **kwargs):
+		match = re.search(r'Item (\w+)', messages[-1].content)
+		return ChatInvokeCompletion(completion=f'Found item {match.group(1) if match else "none"}', usage=None)
+
+	llm.ainvoke.side_effect = mock_ainvoke
+	return llm
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/list').respond_with_data(
+		"""<html><body>
+		<a href="/item/alpha">Alpha</a>
+		<a href="/item/beta">Beta</a>
+		<a href="/item/gamma">Gamma</a>
+		</body></html>""",
+		content_type='text/html',
+	)
+	for name in ('alpha', 'beta', 'gamma'):
+		server.expect_request(f'/item/{name}').respond_with_data(
+			f'<html><body><h1>Item {name}</h1><p>Details for {name}</p></body></html>',
+			content_type='text/html',
+		)
+	yield server
+	server.stop()
+
+
+@pytest.fixture(scope='session')
+def base_url(http_server):
+	return f'http://{http_server.host}:{http_server.port}'
+
+
+async def test_process_links_aggregates_results_without_changing_focus(browser_session, base_url):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/list', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+
+	focus_before = browser_session.agent_focus_target_id
+	tabs_before = len(await browser_session.get_tabs())
+
+	with tempfile.TemporaryDirectory() as tmp:
+		result = await tools.process_links(
+			urls=['/item/alpha', f'{base_url}/item/beta', '/item/gamma', '/item/alpha'],
+			query='Which item is described?',
+			max_concurrency=2,
+			browser_session=browser_session,
+			page_extraction_llm=_make_extraction_llm(),
+			file_system=FileSystem(tmp),
+		)
+
+	assert isinstance(result, ActionResult)
+	assert result.error is None
+	assert result.extracted_content is not None
+	for name in ('alpha', 'beta', 'gamma'):
+		assert f'<page url="{base_url}/item/{name}">\nFound item {name}\n</page>' in result.extracted_content
+	# Duplicate links are only processed once
+	assert result.extracted_content.count(f'{base_url}/item/alpha') == 1
+	assert result.metadata == {'processed_links': 3, 'failed_links': 0}
+
+	# Background tabs are closed again and the agent stays on the list page
+	await asyncio.sleep(0.5)
+	assert browser_session.agent_focus_target_id == focus_before
+	assert len(await browser_session.get_tabs()) == tabs_before
+
+
+async def test_process_links_reports_failed_links(browser_session, base_url):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/list', new_tab=False, browser_session=browser_session)
+
+	with tempfile.TemporaryDirectory() as tmp:
+		result = await tools.process_links(
+			urls=['/item/beta', 'javascript:void(0)'],
+			query='Which item is described?',
+			browser_session=browser_session,
+			page_extraction_llm=_make_extraction_llm(),
+			file_system=FileSystem(tmp),
+		)
+
+	assert result.error is None
+	assert result.extracted_content is not None
+	assert 'Found item beta' in result.extracted_content
+	assert '<page url="javascript:void(0)" error="unsupported URL scheme" />' in result.extracted_content
+	assert result.long_term_memory is not None
+	assert result.long_term_memory.startswith('Processed 1/2 links')
@@ -0,0 +1,101 @@
+"""Tests for compiled system prompt templates and custom template files rendered with the tool registry's actions."""
+
+import os
+
+import pytest
+
+from browser_use.agent.prompts import PromptTemplate, SystemPrompt, load_prompt_template, load_prompt_template_file
+from browser_use.agent.service import Agent
+from browser_use.skills.views import Skill
+from browser_use.tools.service import Tools
+from tests.ci.conftest import create_mock_llm
+
+
+def test_prompt_template_renders_variables_and_literal_braces():
+	template = PromptTemplate('Use {max_actions} actions and answer like {{"done": true}}.')
+	assert template.variables == {'max_actions'}
+	assert template.render({'max_actions': '4'}) == 'Use 4 actions and answer like {"done": true}.'
+
+	with pytest.raises(ValueError, match=r"undefined variables \['max_actions'\]"):
+		template.render({})
+	with pytest.raises(ValueError, match='Invalid placeholder'):
+		PromptTemplate('Step {step.number}')
+
+
+def test_built_in_templates_are_compiled_once():
+	assert load_prompt_template('system_prompt.md') is load_prompt_template('system_prompt.md')
+
+	prompt = SystemPrompt(max_actions_per_step=7)
+	assert prompt.prompt_template is load_prompt_template('system_prompt.md')
+	text = prompt.get_system_message().text
+	assert 'maximum of 7 actions per step' in text
+	assert '<step_{step_number}>' in text
+
+
+def test_built_in_templates_list_custom_actions():
+	tools = Tools()
+
+	@tools.action('Look up the stock level of a product by its SKU')
+	async def check_inventory(sku: str):
+		return f'{sku}: 3 in stock'
+
+	for flash_mode in (False, True):
+		agent = Agent(task='Restock the bestsellers', llm=create_mock_llm(), tools=tools, flash_mode=flash_mode)
+		text = agent._message_manager.system_prompt.text
+		actions = text.split('<available_actions>')[1].split('</available_actions>')[0]
+		assert 'check_inventory' in actions and 'stock level of a product' in actions
+
+
+def test_template_file_is_recompiled_when_it_changes(tmp_path):
+	path = tmp_path / 'prompt.md'
+	path.write_text('Up to {max_actions} actions.')
+	first = load_prompt_template_file(path)
+	assert load_prompt_template_file(path) is first
+
+	path.write_text('At most {max_actions} actions.')
+	stat = path.stat()
+	os.utime(path, ns=(stat.st_atime_ns, stat.st_mtime_ns + 1_000_000))
+	second = load_prompt_template_file(path)
+	assert second is not first
+	assert second.render({'max_actions': '2'}) == 'At most 2 actions.'
+
+
+def test_agent_template_lists_custom_actions(tmp_path):
+	tools = Tools()
+
+	@tools.action('Look up the stock level of a product by its SKU')
+	async def check_inventory(sku: str):
+		return f'{sku}: 3 in stock'
+
+	path = tmp_path / 'prompt.md'
+	path.write_text('You work for {shop}. Task: {task}\n<actions>\n{available_actions}\n</actions>\nUse up to {max_actions}.')
+	agent = Agent(
+		task='Restock the bestsellers',
+		llm=create_mock_llm(),
+		tools=tools,
+		system_prompt_template=path,
+		system_prompt_variables={'shop': 'ACME'},
+		max_actions_per_step=2,
+	)
+
+	text = agent._message_manager.system_prompt.text
+	assert text.startswith('You work for ACME. Task: Restock the bestsellers')
+	actions = text.split('<actions>')[1].split('</actions>')[0]
+	assert 'check_inventory' in actions and 'stock level of a product' in actions
+	assert 'navigate' in actions
+	assert text.endswith('Use up to 2.')
+
+
+async def test_skill_actions_are_listed_once_registered():
+	class FakeSkillService:
+		async def get_all_skills(self) -> list[Skill]:
+			return [Skill(id='sk-1', title='Check Stock Levels', description='Look up warehouse stock levels', parameters=[])]
+
+	agent = Agent(task='Restock the bestsellers', llm=create_mock_llm(), skill_service=FakeSkillService())
+	assert 'check_stock_levels' not in agent._message_manager.system_prompt.text
+
+	await agent._register_skills_as_actions()
+	text = agent._message_manager.system_prompt.text
+	actions = text.split('<available_actions>')[1].split('</available_actions>')[0]
+	assert 'check_stock_levels' in actions and 'warehouse stock levels' in actions
+	assert agent._message_manager.state.history.system_message is agent._message_manager.system_prompt
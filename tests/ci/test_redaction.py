@@ -0,0 +1,94 @@
+"""Tests for redaction of sensitive page content in screenshots, state text, logs and artifacts."""
+
+import base64
+import json
+import logging
+from io import BytesIO
+
+from PIL import Image
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.redaction import RedactionConfig, Redactor, mask_screenshot
+from browser_use.agent.service import Agent
+from browser_use.llm.messages import UserMessage
+from tests.ci.conftest import create_mock_llm
+
+PAGE = """
+<div class="account" style="position: fixed; left: 0; top: 0; width: 300px; height: 80px; background: white">
+	Account 4417 1234 5678 9113
+</div>
+<p style="margin-top: 120px">Contact jane.doe@example.com for help</p>
+<label>Password <input type="password" value="hunter2hunter2"></label>
+"""
+
+
+def _png(width: int, height: int) -> str:
+	buffer = BytesIO()
+	Image.new('RGB', (width, height), 'white').save(buffer, format='PNG')
+	return base64.b64encode(buffer.getvalue()).decode()
+
+
+def _pixel(screenshot_b64: str, x: int, y: int) -> tuple[int, ...]:
+	return Image.open(BytesIO(base64.b64decode(screenshot_b64))).convert('RGB').getpixel((x, y))  # type: ignore[return-value]
+
+
+def test_redact_text_and_screenshot_boxes():
+	redactor = Redactor(RedactionConfig.pii(replacement='***'))
+	redactor.add_values(['  Jane Doe\nSt', ''])
+	text = 'Card 4417 1234 5678 9113 of Jane Doe, St mail jane@example.com'
+	assert redactor.redact_text(text) == 'Card *** of ***, St mail ***'
+
+	# Boxes are in CSS pixels and scaled to the screenshot, which is taken in device pixels
+	masked = mask_screenshot(_png(200, 100), [{'x': 10, 'y': 10, 'width': 20, 'height': 10}], viewport_width=100)
+	assert _pixel(masked, 30, 30) == (0, 0, 0)
+	assert _pixel(masked, 70, 30) == (255, 255, 255)
+
+	# Numbers failing the Luhn check, like millisecond timestamps, are not card numbers
+	assert redactor.redact_text('Updated at 1760000000000') == 'Updated at 1760000000000'
+	assert redactor.redact_text('Card 4417-1234-5678-9114') == 'Card 4417-1234-5678-9114'
+
+	# Labels in the text of a sensitive element are not masked all over the page, the values are
+	redactor.add_values(['Balance:\nIBAN\nDE89 3704 0044 0532 0130 00\nTotal'], element_text=True)
+	assert redactor.redact_text('Balance: IBAN Total DE89 3704 0044 0532 0130 00') == 'Balance: IBAN Total ***'
+
+	record = logging.LogRecord('browser_use.Agent', logging.INFO, __file__, 1, 'Typed %s', ('jane@example.com',), None)
+	assert redactor.log_filter().filter(record) and record.getMessage() == 'Typed ***'
+
+
+async def test_agent_redacts_state_screenshot_and_artifacts(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/account').respond_with_data(PAGE, content_type='text/html')
+	navigate = json.dumps(
+		{
+			'evaluation_previous_goal': 'Start',
+			'memory': 'Nothing yet',
+			'next_goal': 'Open the account page',
+			'action': [{'navigate': {'url': httpserver.url_for('/account')}}],
+		}
+	)
+	llm = create_mock_llm([navigate])
+	agent = Agent(
+		task='Read the account page',
+		llm=llm,
+		browser_session=browser_session,
+		redaction=RedactionConfig.pii(selectors=['.account']),
+		artifacts_dir=str(tmp_path),
+	)
+	history = await agent.run(max_steps=3)
+
+	prompts = [
+		message.text
+		for call in llm.ainvoke.call_args_list
+		for message in call.args[0]
+		if isinstance(message, UserMessage) and 'Contact' in message.text
+	]
+	assert prompts
+	for prompt in prompts:
+		assert '4417' not in prompt and 'jane.doe@example.com' not in prompt and 'hunter2hunter2' not in prompt
+		assert '[REDACTED]' in prompt
+
+	# The account box at the top left of the page is blacked out on the stored screenshot
+	screenshot = history.screenshots()[-1]
+	assert screenshot and _pixel(screenshot, 5, 5) == (0, 0, 0)
+
+	bundle = next(tmp_path.glob('browser_use_run_*'))
+	assert 'jane.doe@example.com' not in (bundle / 'steps.json').read_text()
@@ -0,0 +1,76 @@
+"""Escalation when the same action with the same params keeps failing: hint, strategy change, then abort."""
+
+import json
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import RepeatedFailureTracker
+from browser_use.llm.messages import UserMessage
+from tests.ci.conftest import create_mock_llm
+
+
+def _context_messages(agent: Agent) -> list[str]:
+	msgs = agent._message_manager.state.history.context_messages
+	return [m.content for m in msgs if isinstance(m, UserMessage) and isinstance(m.content, str)]
+
+
+def test_tracker_counts_failures_until_success():
+	tracker = RepeatedFailureTracker()
+	tracker.record('click', {'index': 7}, 'Element not found')
+	tracker.record('scroll', {'down': True}, None)
+	tracker.record('click', {'index': 7}, 'Element not found')
+	tracker.record('click', {'index': 8}, 'Element not clickable')
+	assert tracker.most_repeated() == ('click {"index": 7}', 2, 'Element not found')
+
+	tracker.record('click', {'index': 7}, None)
+	assert tracker.most_repeated() == ('click {"index": 8}', 1, 'Element not clickable')
+
+
+async def test_hint_then_strategy_change():
+	agent = Agent(task='Test task', llm=create_mock_llm())
+
+	agent.state.failure_tracker.record('click', {'index': 7}, 'Element not found')
+	agent._inject_repeated_failure_hint()
+	assert _context_messages(agent) == []
+
+	agent.state.failure_tracker.record('click', {'index': 7}, 'Element not found')
+	agent._inject_repeated_failure_hint()
+	agent.state.failure_tracker.record('click', {'index': 7}, 'Element not found')
+	agent._inject_repeated_failure_hint()
+
+	hint, strategy_change = _context_messages(agent)
+	assert hint.startswith('Note: `click {"index": 7}` has failed 2 times')
+	assert strategy_change.startswith('STRATEGY CHANGE REQUIRED')
+	assert 'aborted after 5 such failures' in strategy_change
+	assert agent._repeated_failure_error() is None
+
+
+async def test_run_aborts_on_repeated_failure(browser_session):
+	click_missing = json.dumps({'memory': 'clicking the button', 'action': [{'click': {'index': 999}}]})
+	agent = Agent(
+		task='Test task',
+		llm=create_mock_llm([click_missing] * 4),
+		browser_session=browser_session,
+		max_repeated_failures=3,
+		max_failures=10,
+	)
+
+	history = await agent.run(max_steps=10)
+
+	assert len(history.history) == 4
+	error = history.history[-1].result[0].error
+	assert error is not None and error.startswith('Stopped because `click {"index": 999}` failed 3 times')
+
+
+async def test_disabled_stages():
+	agent = Agent(
+		task='Test task',
+		llm=create_mock_llm(),
+		repeated_failure_hint_after=0,
+		repeated_failure_replan_after=0,
+		max_repeated_failures=0,
+	)
+	for _ in range(6):
+		agent.state.failure_tracker.record('click', {'index': 7}, 'Element not found')
+	agent._inject_repeated_failure_hint()
+	assert _context_messages(agent) == []
+	assert agent._repeated_failure_error() is None
@@ -0,0 +1,70 @@
+"""Tests for exporting a run as an artifacts bundle (steps, screenshots, files, result, conversation)."""
+
+import json
+import zipfile
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+def _step(action: dict) -> str:
+	return json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Unknown',
+			'memory': 'Collecting prices',
+			'next_goal': 'Continue',
+			'action': [action],
+		}
+	)
+
+
+async def test_run_writes_artifacts_bundle(browser_session, httpserver: HTTPServer, tmp_path):
+	httpserver.expect_request('/prices').respond_with_data('<html><body><p>Mug: $12</p></body></html>', content_type='text/html')
+	llm = create_mock_llm(
+		actions=[
+			_step({'navigate': {'url': httpserver.url_for('/prices'), 'new_tab': False}}),
+			_step({'write_file': {'file_name': 'prices.md', 'content': 'Mug: $12'}}),
+		]
+	)
+	artifacts_dir = tmp_path / 'artifacts'
+	agent = Agent(task='Write down the mug price', llm=llm, browser_session=browser_session, artifacts_dir=artifacts_dir)
+	history = await agent.run(max_steps=5)
+	assert history.is_done()
+
+	bundles = list(artifacts_dir.iterdir())
+	assert len(bundles) == 1
+	bundle = bundles[0]
+
+	steps = json.loads((bundle / 'steps.json').read_text())
+	assert len(steps['history']) == len(history.history)
+	for step in steps['history']:
+		screenshot = step['state']['screenshot_path']
+		if screenshot:
+			assert screenshot.startswith('screenshots/')
+			assert (bundle / screenshot).is_file()
+	assert any((bundle / 'screenshots').iterdir())
+
+	result = json.loads((bundle / 'result.json').read_text())
+	assert result['task'] == 'Write down the mug price'
+	assert result['is_done'] is True
+	assert result['final_result'] == history.final_result()
+	assert result['number_of_steps'] == len(history.history)
+
+	assert (bundle / 'files' / 'prices.md').read_text().strip() == 'Mug: $12'
+
+	conversation = sorted(path.name for path in (bundle / 'conversation').iterdir())
+	assert conversation == [f'step_{n}.txt' for n in range(1, len(history.history) + 1)]
+	assert 'Write down the mug price' in (bundle / 'conversation' / 'step_1.txt').read_text()
+
+	# The same bundle can be exported on demand as a zip
+	archive = agent.export_artifacts(tmp_path / 'export', as_zip=True)
+	assert archive == tmp_path / 'export.zip'
+	assert not (tmp_path / 'export').exists()
+	with zipfile.ZipFile(archive) as zf:
+		names = zf.namelist()
+	assert 'steps.json' in names
+	assert 'result.json' in names
+	assert 'files/prices.md' in names
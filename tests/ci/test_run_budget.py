@@ -0,0 +1,90 @@
+"""Tests for run budgets: max_duration, the budget exceeded callback and recording timed out steps."""
+
+import asyncio
+import json
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import BudgetExceeded
+from tests.ci.conftest import create_mock_llm
+
+
+def _write_file_step(n: int) -> str:
+	return json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Unknown',
+			'memory': f'Step {n}',
+			'next_goal': 'Keep notes',
+			'action': [{'write_file': {'file_name': 'notes.md', 'content': f'note {n}'}}],
+		}
+	)
+
+
+async def test_max_duration_gives_last_step_to_call_done(browser_session):
+	budgets: list[BudgetExceeded] = []
+
+	def on_budget_exceeded(agent: Agent, budget: BudgetExceeded) -> bool:
+		budgets.append(budget)
+		return True
+
+	agent = Agent(
+		task='Collect notes',
+		llm=create_mock_llm(),
+		browser_session=browser_session,
+		max_duration=0,
+		register_budget_exceeded_callback=on_budget_exceeded,
+	)
+	history = await agent.run(max_steps=5)
+
+	assert history.is_done()
+	assert len(budgets) == 1
+	assert budgets[0].reason == 'max_duration'
+	assert budgets[0].limit == 0
+
+
+async def test_budget_callback_can_stop_the_run(browser_session):
+	budgets: list[BudgetExceeded] = []
+
+	async def on_budget_exceeded(agent: Agent, budget: BudgetExceeded) -> bool:
+		budgets.append(budget)
+		return False
+
+	agent = Agent(
+		task='Collect notes',
+		llm=create_mock_llm(actions=[_write_file_step(1), _write_file_step(2), _write_file_step(3)]),
+		browser_session=browser_session,
+		register_budget_exceeded_callback=on_budget_exceeded,
+	)
+	history = await agent.run(max_steps=2)
+
+	# The last step is never taken, so the agent doesn't get to call done
+	assert not history.is_done()
+	assert history.number_of_steps() == 1
+	assert [budget.reason for budget in budgets] == ['max_steps']
+	assert budgets[0].n_steps == 1
+	assert budgets[0].limit == 2
+
+
+async def test_stuck_step_is_recorded_as_failed(browser_session):
+	llm = create_mock_llm()
+	answer = llm.ainvoke.side_effect
+	calls = 0
+
+	async def hang_on_first_call(*args, **kwargs):
+		nonlocal calls
+		calls += 1
+		if calls == 1:
+			await asyncio.sleep(30)
+		return await answer(*args, **kwargs)
+
+	llm.ainvoke.side_effect = hang_on_first_call
+	agent = Agent(task='Collect notes', llm=llm, browser_session=browser_session, step_timeout=3, llm_timeout=60)
+	history = await agent.run(max_steps=5)
+
+	assert history.is_done()
+	first_step = history.history[0]
+	assert first_step.metadata is not None
+	assert first_step.metadata.step_number == 1
+	assert first_step.result[0].error == 'Step 1 timed out after 3 seconds'
+	assert history.history[1].metadata is not None
+	assert history.history[1].metadata.step_number == 2
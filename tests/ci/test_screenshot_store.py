@@ -0,0 +1,121 @@
+"""Tests for pluggable screenshot stores and pruning screenshots of older steps."""
+
+import base64
+import json
+from typing import Any
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.agent.artifacts import export_run_artifacts
+from browser_use.agent.service import Agent
+from browser_use.agent.views import AgentHistory, AgentHistoryList
+from browser_use.browser.views import BrowserStateHistory
+from browser_use.screenshots.cloud import S3ScreenshotStore
+from browser_use.screenshots.service import ScreenshotService, ScreenshotStore
+from tests.ci.conftest import create_mock_llm
+
+PNG_B64 = base64.b64encode(b'\x89PNG\r\n\x1a\nfake').decode()
+
+
+class FakeS3Client:
+	def __init__(self):
+		self.objects: dict[tuple[str, str], bytes] = {}
+
+	def put_object(self, Bucket: str, Key: str, Body: bytes, ContentType: str) -> None:
+		self.objects[(Bucket, Key)] = Body
+
+	def get_object(self, Bucket: str, Key: str) -> dict[str, Any]:
+		body = self.objects[(Bucket, Key)]
+		return {'Body': type('Body', (), {'read': lambda self: body})()}
+
+	def delete_object(self, Bucket: str, Key: str) -> None:
+		self.objects.pop((Bucket, Key), None)
+
+
+class MemoryScreenshotStore(ScreenshotStore):
+	def __init__(self):
+		self.screenshots: dict[str, str] = {}
+
+	async def store_screenshot(self, screenshot_b64: str, step_number: int) -> str:
+		reference = f'memory://step_{step_number}'
+		self.screenshots[reference] = screenshot_b64
+		return reference
+
+	async def get_screenshot(self, screenshot_path: str) -> str | None:
+		return self.screenshots.get(screenshot_path)
+
+	async def delete_screenshot(self, screenshot_path: str) -> None:
+		self.screenshots.pop(screenshot_path, None)
+
+
+def _navigate_step(url: str) -> str:
+	return json.dumps(
+		{
+			'thinking': 'null',
+			'evaluation_previous_goal': 'Unknown',
+			'memory': 'Browsing',
+			'next_goal': 'Open the next page',
+			'action': [{'navigate': {'url': url, 'new_tab': False}}],
+		}
+	)
+
+
+async def test_s3_store_round_trip():
+	client = FakeS3Client()
+	store = S3ScreenshotStore(bucket='runs', prefix='agent-1/', client=client)
+
+	reference = await store.store_screenshot(PNG_B64, step_number=3)
+	assert reference == 's3://runs/agent-1/step_3.png'
+	assert client.objects[('runs', 'agent-1/step_3.png')].startswith(b'\x89PNG')
+	assert await store.get_screenshot(reference) == PNG_B64
+
+	await store.delete_screenshot(reference)
+	assert client.objects == {}
+	assert await store.get_screenshot(reference) is None
+
+
+async def test_history_and_artifacts_read_screenshots_through_the_store(tmp_path):
+	store = S3ScreenshotStore(bucket='runs', prefix='agent-1', client=FakeS3Client())
+	reference = await store.store_screenshot(PNG_B64, step_number=1)
+	state = BrowserStateHistory(
+		url='https://example.com', title='Example', tabs=[], interacted_element=[], screenshot_path=reference
+	)
+	history = AgentHistoryList(history=[AgentHistory(model_output=None, result=[], state=state)])
+
+	assert history.screenshots() == [None]  # an s3:// reference is not a local file
+	assert history.screenshots(screenshot_store=store) == [PNG_B64]
+
+	bundle = export_run_artifacts(tmp_path / 'bundle', task='Look at example.com', history=history, screenshot_store=store)
+	assert (bundle / 'screenshots' / 'step_1.png').read_bytes() == base64.b64decode(PNG_B64)
+	steps = json.loads((bundle / 'steps.json').read_text())
+	assert steps['history'][0]['state']['screenshot_path'] == 'screenshots/step_1.png'
+
+
+async def test_local_store_deletes_screenshots(tmp_path):
+	store = ScreenshotService(tmp_path)
+	path = await store.store_screenshot(PNG_B64, step_number=1)
+	assert await store.get_screenshot(path) == PNG_B64
+
+	await store.delete_screenshot(path)
+	assert await store.get_screenshot(path) is None
+	await store.delete_screenshot(path)  # Deleting twice is fine
+
+
+async def test_agent_keeps_only_recent_screenshots_in_custom_store(browser_session, httpserver: HTTPServer):
+	for page in ('one', 'two', 'three'):
+		html = f'<html><body><h1>{page}</h1></body></html>'
+		httpserver.expect_request(f'/{page}').respond_with_data(html, content_type='text/html')
+	store = MemoryScreenshotStore()
+	llm = create_mock_llm(actions=[_navigate_step(httpserver.url_for(f'/{page}')) for page in ('one', 'two', 'three')])
+	agent = Agent(task='Browse the pages', llm=llm, browser_session=browser_session, screenshot_store=store, max_screenshots=2)
+	history = await agent.run(max_steps=6)
+	assert history.is_done()
+
+	kept = [path for path in history.screenshot_paths() if path is not None]
+	assert len(kept) == 2
+	assert all(path.startswith('memory://') for path in kept)
+	assert sorted(store.screenshots) == sorted(kept)
+	# The screenshots of the earlier steps were dropped
+	paths = history.screenshot_paths()
+	assert all(path is None for path in paths[: paths.index(kept[0])])
+	assert paths.index(kept[0]) >= 2
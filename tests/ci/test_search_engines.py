@@ -0,0 +1,66 @@
+"""Tests for search engine configuration and the fallback when a result page is a captcha or block page."""
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools, is_blocked_search_page
+
+BLOCKED_PAGE = """
+<html><head><title>Sorry...</title></head><body>
+	<p>Our systems have detected unusual traffic from your computer network.</p>
+	<div class="g-recaptcha"></div>
+</body></html>
+"""
+
+RESULTS_PAGE = """
+<html><head><title>Results</title></head><body><h1>Results</h1><a href="https://browser-use.com">Browser Use</a></body></html>
+"""
+
+
+def test_is_blocked_search_page():
+	assert is_blocked_search_page('https://www.google.com/sorry/index?continue=x', 'Google', '')
+	assert is_blocked_search_page('https://example.com/', 'Just a moment...', '')
+	assert is_blocked_search_page('https://duckduckgo.com/', 'DuckDuckGo', 'Unfortunately, bots use DuckDuckGo too.')
+	assert not is_blocked_search_page('https://duckduckgo.com/?q=captcha', 'captcha at DuckDuckGo', 'Results ' * 1000)
+
+
+def test_search_engine_configuration(monkeypatch):
+	monkeypatch.delenv('KAGI_API_KEY', raising=False)
+	tools = Tools(default_search_engine='Brave', search_engines={'Docs': 'https://docs.example.com/find?term={query}'})
+	assert tools.default_search_engine == 'brave'
+	assert tools._search_url('docs', 'a b&c') == 'https://docs.example.com/find?term=a+b%26c'
+	assert tools._search_url('startpage', 'x') == 'https://www.startpage.com/do/search?query=x'
+	with pytest.raises(ValueError, match='KAGI_API_KEY'):
+		tools._search_cookies('kagi')
+	assert tools._search_cookies('brave') == []
+
+	# The Kagi token signs the browser in through a cookie and never appears in the search URL
+	kagi = Tools(kagi_api_key='tok')
+	assert kagi._search_url('kagi', 'x') == 'https://kagi.com/search?q=x'
+	[cookie] = kagi._search_cookies('kagi')
+	assert (cookie['name'], cookie['value'], cookie['domain']) == ('kagi_session', 'tok', 'kagi.com')
+	with pytest.raises(ValueError, match='Unknown default_search_engine'):
+		Tools(default_search_engine='altavista')
+
+
+async def test_falls_back_when_engine_is_blocked(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/blocked/sorry').respond_with_data(BLOCKED_PAGE, content_type='text/html')
+	httpserver.expect_request('/results').respond_with_data(RESULTS_PAGE, content_type='text/html')
+	tools = Tools(
+		default_search_engine='blocked',
+		search_engines={
+			'blocked': httpserver.url_for('/blocked/sorry') + '?q={query}',
+			'local': httpserver.url_for('/results') + '?q={query}',
+		},
+		search_fallback=['local'],
+	)
+
+	result = await tools.search(query='browser use', browser_session=browser_session)
+
+	assert result.error is None
+	assert result.long_term_memory == "Searched Local for 'browser use' (Blocked showed a captcha or block page)"
+	assert (await browser_session.get_current_page_url()).startswith(httpserver.url_for('/results'))
+
+	tools.search_fallback = []
+	result = await tools.search(query='browser use', browser_session=browser_session)
+	assert result.error is not None and 'Blocked showed a captcha or block page' in result.error
@@ -0,0 +1,67 @@
+"""Tests for the before/after step hooks that can change the state message, add results or cancel a step."""
+
+import pytest
+
+from browser_use.agent.service import Agent
+from browser_use.agent.views import ActionResult, StepContext
+from browser_use.llm.messages import UserMessage
+from tests.ci.conftest import create_mock_llm
+
+
+async def test_step_hooks_add_context_results_and_cancel(browser_session):
+	actions = ['{"memory": "open", "action": [{"navigate": {"url": "about:blank", "new_tab": false}}]}']
+	llm = create_mock_llm(actions)
+	after_steps: list[tuple[int, list[str], str | None]] = []
+
+	async def before_step(ctx: StepContext) -> None:
+		if ctx.step_number == 1:
+			ctx.add_context('CRM: the customer is a VIP')
+		elif ctx.step_number == 2:
+			ctx.cancel('waiting for CRM data')
+
+	def after_step(ctx: StepContext) -> None:
+		actions = ctx.model_output.action if ctx.model_output else []
+		action_names = [name for action in actions for name in action.model_dump(exclude_unset=True)]
+		after_steps.append((ctx.step_number, action_names, ctx.cancel_reason))
+		ctx.add_result(ActionResult(long_term_memory=f'Guardrail check passed for step {ctx.step_number}'))
+
+	agent = Agent(
+		task='Open a blank page',
+		llm=llm,
+		browser_session=browser_session,
+		register_before_step_hook=before_step,
+		register_after_step_hook=after_step,
+	)
+	history = await agent.run(max_steps=5)
+
+	assert history.is_done()
+	assert after_steps == [(1, ['navigate'], None), (2, [], 'waiting for CRM data'), (3, ['done'], None)]
+
+	# The context added before step 1 reached the LLM
+	first_call_messages = llm.ainvoke.call_args_list[0].args[0]
+	assert any(isinstance(m, UserMessage) and 'CRM: the customer is a VIP' in m.text for m in first_call_messages)
+	assert llm.ainvoke.call_count == 2
+
+	# The cancelled step made no LLM call and recorded why
+	cancelled = history.history[1]
+	assert cancelled.model_output is None
+	assert cancelled.result[0].long_term_memory == 'Step cancelled: waiting for CRM data'
+
+	# Injected results are kept, before the done result
+	assert history.history[0].result[-1].long_term_memory == 'Guardrail check passed for step 1'
+	assert history.history[-1].result[-2].long_term_memory == 'Guardrail check passed for step 3'
+
+
+def test_only_before_step_hooks_can_cancel():
+	ctx = StepContext(
+		step_number=1,
+		task='task',
+		browser_state=None,  # type: ignore[arg-type]
+		state_message=UserMessage(content='state'),
+		after_step=True,
+	)
+	with pytest.raises(RuntimeError):
+		ctx.cancel('too late')
+
+	ctx.add_context('extra')
+	assert ctx.state_message.text == 'state\nextra'
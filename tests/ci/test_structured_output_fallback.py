@@ -0,0 +1,113 @@
+"""ChatOpenAI against OpenAI-compatible servers without response_format json_schema: fall back to the schema in the
+system prompt and parse the JSON out of the answer text."""
+
+import json
+
+import pytest
+from pydantic import BaseModel
+from pytest_httpserver import HTTPServer
+from werkzeug import Request, Response
+
+from browser_use.llm.exceptions import ModelProviderError
+from browser_use.llm.messages import SystemMessage, UserMessage
+from browser_use.llm.openai.chat import ChatOpenAI
+from browser_use.llm.schema import validate_json_text
+
+
+class NextStep(BaseModel):
+	memory: str
+	next_goal: str
+
+
+def _completion(content: str) -> dict:
+	return {
+		'id': 'chatcmpl-test',
+		'object': 'chat.completion',
+		'created': 0,
+		'model': 'local-model',
+		'choices': [{'index': 0, 'message': {'role': 'assistant', 'content': content}, 'finish_reason': 'stop'}],
+		'usage': {'prompt_tokens': 10, 'completion_tokens': 8, 'total_tokens': 18},
+	}
+
+
+def test_validate_json_text():
+	expected = NextStep(memory='on the search page', next_goal='type the query')
+	bare = expected.model_dump_json()
+	assert validate_json_text(bare, NextStep) == expected
+	assert validate_json_text(f'```json\n{bare}\n```', NextStep) == expected
+	assert validate_json_text(f'Here is my next step:\n{bare}\nGood luck!', NextStep) == expected
+	with pytest.raises(ValueError, match='next_goal'):
+		validate_json_text('```json\n{"memory": "x"}\n```', NextStep)
+
+
+async def test_falls_back_when_json_schema_is_rejected(httpserver: HTTPServer):
+	requests: list[dict] = []
+
+	def handler(request: Request) -> Response:
+		body = json.loads(request.data)
+		requests.append(body)
+		if 'response_format' in body:
+			error = {'error': {'message': "Unsupported parameter: 'response_format' of type json_schema"}}
+			return Response(json.dumps(error), status=400, content_type='application/json')
+		content = '```json\n{"memory": "on the search page", "next_goal": "type the query"}\n```'
+		return Response(json.dumps(_completion(content)), content_type='application/json')
+
+	httpserver.expect_request('/v1/chat/completions', method='POST').respond_with_handler(handler)
+	llm = ChatOpenAI(model='local-model', api_key='test-key', base_url=httpserver.url_for('/v1'))
+	messages = [SystemMessage(content='You are a browser agent.'), UserMessage(content='next step?')]
+
+	result = await llm.ainvoke(messages, output_format=NextStep)
+	assert result.completion.next_goal == 'type the query'
+	assert len(requests) == 2
+	assert '<json_schema>' in requests[1]['messages'][0]['content'] and 'next_goal' in requests[1]['messages'][0]['content']
+
+	# The server is not asked again for json_schema
+	await llm.ainvoke(messages, output_format=NextStep)
+	assert len(requests) == 3 and 'response_format' not in requests[2]
+
+
+async def test_rejected_schema_falls_back_for_that_request_only(httpserver: HTTPServer):
+	requests: list[dict] = []
+
+	def handler(request: Request) -> Response:
+		body = json.loads(request.data)
+		requests.append(body)
+		if 'response_format' in body:
+			error = {'error': {'message': "Invalid schema for response_format 'NextStep': 'memory' is not allowed"}}
+			return Response(json.dumps(error), status=400, content_type='application/json')
+		content = '{"memory": "on the search page", "next_goal": "type the query"}'
+		return Response(json.dumps(_completion(content)), content_type='application/json')
+
+	httpserver.expect_request('/v1/chat/completions', method='POST').respond_with_handler(handler)
+	llm = ChatOpenAI(model='local-model', api_key='test-key', base_url=httpserver.url_for('/v1'))
+	messages = [SystemMessage(content='You are a browser agent.'), UserMessage(content='next step?')]
+
+	result = await llm.ainvoke(messages, output_format=NextStep)
+	assert result.completion.next_goal == 'type the query'
+	assert len(requests) == 2 and 'response_format' not in requests[1]
+
+	# The next request tries json_schema again
+	await llm.ainvoke(messages, output_format=NextStep)
+	assert len(requests) == 4 and 'response_format' in requests[2]
+
+
+async def test_other_response_format_errors_are_not_retried(httpserver: HTTPServer):
+	httpserver.expect_request('/v1/chat/completions', method='POST').respond_with_json(
+		{'error': {'message': 'response_format is too large for this model', 'type': 'invalid_request'}}, status=400
+	)
+	llm = ChatOpenAI(model='local-model', api_key='test-key', base_url=httpserver.url_for('/v1'))
+
+	with pytest.raises(ModelProviderError, match='too large'):
+		await llm.ainvoke([UserMessage(content='next step?')], output_format=NextStep)
+	assert len(httpserver.log) == 1
+
+
+async def test_other_bad_requests_are_not_retried(httpserver: HTTPServer):
+	httpserver.expect_request('/v1/chat/completions', method='POST').respond_with_json(
+		{'error': {'message': 'Invalid API key', 'type': 'invalid_request'}}, status=400
+	)
+	llm = ChatOpenAI(model='local-model', api_key='test-key', base_url=httpserver.url_for('/v1'))
+
+	with pytest.raises(ModelProviderError, match='Invalid API key'):
+		await llm.ainvoke([UserMessage(content='next step?')], output_format=NextStep)
+	assert len(httpserver.log) == 1
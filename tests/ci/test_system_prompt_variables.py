@@ -0,0 +1,54 @@
+"""Tests for {name} template variables in override_system_message and extend_system_message."""
+
+from datetime import datetime
+
+from browser_use.agent.prompts import SystemPrompt, render_prompt_variables
+from browser_use.agent.service import Agent
+from tests.ci.conftest import create_mock_llm
+
+
+def test_render_prompt_variables_keeps_unknown_braces():
+	text = 'Answer about {topic} as JSON like {"answer": "..."}, {unknown} stays.'
+	rendered = render_prompt_variables(text, {'topic': 'cats'})
+	assert rendered == 'Answer about cats as JSON like {"answer": "..."}, {unknown} stays.'
+
+
+def test_render_prompt_variables_escape_keeps_declared_name():
+	assert render_prompt_variables('Write {{topic}} for {topic}.', {'topic': 'cats'}) == 'Write {topic} for cats.'
+
+
+def test_messages_are_verbatim_without_declared_variables():
+	prompt = SystemPrompt(override_system_message='Task: {task}', extend_system_message='Date: {date}', task='Find flights')
+	assert prompt.get_system_message().text == 'Task: {task}\nDate: {date}'
+
+	prompt = SystemPrompt(override_system_message='Task: {task}', task='Find flights', prompt_variables={})
+	assert prompt.get_system_message().text == 'Task: Find flights'
+
+
+def test_override_system_message_variables():
+	prompt = SystemPrompt(
+		max_actions_per_step=4,
+		override_system_message='Task: {task}\nDate: {date}\nUp to {max_actions} actions.\n{available_actions}\n{tone}',
+		task='Find the cheapest flight',
+		available_actions='navigate: open a url',
+		prompt_variables={'tone': 'Be brief.'},
+	)
+	text = prompt.get_system_message().text
+	assert text == (
+		f'Task: Find the cheapest flight\nDate: {datetime.now().strftime("%Y-%m-%d")}\n'
+		'Up to 4 actions.\nnavigate: open a url\nBe brief.'
+	)
+
+
+def test_agent_extend_system_message_variables():
+	agent = Agent(
+		task='Compare laptop prices',
+		llm=create_mock_llm(),
+		extend_system_message='Customer: {customer}. Remember the task: {task}. Actions you have:\n{available_actions}',
+		system_prompt_variables={'customer': 'ACME'},
+	)
+	text = agent._message_manager.system_prompt.text
+	# The default prompt is kept and the extension is appended
+	assert text.startswith('You are an AI agent')
+	assert 'Customer: ACME. Remember the task: Compare laptop prices.' in text
+	assert 'navigate' in text.split('Actions you have:')[-1]
@@ -0,0 +1,51 @@
+"""Tests for Unicode-safe text truncation and sanitization helpers."""
+
+import unicodedata
+
+from browser_use.dom.utils import cap_text_length
+from browser_use.utils import _log_pretty_url, safe_slice, sanitize_text, truncate_text
+
+
+def test_safe_slice_does_not_split_combining_marks():
+	text = unicodedata.normalize('NFD', 'café au lait')  # 'e' + combining acute accent
+	assert safe_slice(text, 4) == 'caf'
+	assert safe_slice(text, 5) == text[:5]
+
+
+def test_safe_slice_does_not_split_emoji_sequences():
+	family = '\U0001f468‍\U0001f469‍\U0001f467'
+	text = f'a{family}b'
+	for end in range(2, 1 + len(family)):
+		assert safe_slice(text, end) == 'a'
+	assert safe_slice(text, 1 + len(family)) == f'a{family}'
+
+	thumbs_up = '\U0001f44d\U0001f3fd'  # with skin tone modifier
+	assert safe_slice(f'x{thumbs_up}y', 2) == 'x'
+
+
+def test_safe_slice_short_text_unchanged():
+	assert safe_slice('hello', 10) == 'hello'
+	assert safe_slice('hello', 0) == ''
+
+
+def test_truncate_text_respects_max_length():
+	assert truncate_text('hello world', 20) == 'hello world'
+	assert truncate_text('hello world', 8) == 'hello...'
+	assert len(truncate_text('x' * 100, 50)) == 50
+	assert truncate_text('日本語のテキストです', 6, suffix='…') == '日本語のテ…'
+
+
+def test_sanitize_text_normalizes_and_strips_control_chars():
+	decomposed = unicodedata.normalize('NFD', 'café')
+	assert sanitize_text(decomposed) == 'café'
+	assert len(sanitize_text(decomposed)) == 4
+	assert sanitize_text('a\x00b\x1bc\x7f') == 'abc'
+	assert sanitize_text('line1\n\tline2\r\n') == 'line1\n\tline2\r\n'
+	assert sanitize_text('bad\ud800surrogate') == 'badsurrogate'
+
+
+def test_display_helpers_use_safe_truncation():
+	text = unicodedata.normalize('NFD', 'résumé résumé')
+	capped = cap_text_length(text, 2)
+	assert capped == 'r...'
+	assert _log_pretty_url('https://example.com/' + 'é' * 40, max_len=10) == 'example.co…'
@@ -0,0 +1,87 @@
+"""Tests for typed extraction into Pydantic models with retries on schema violations."""
+
+from unittest.mock import AsyncMock
+
+import pytest
+from pydantic import BaseModel, field_validator
+from pytest_httpserver import HTTPServer
+
+from browser_use.llm.base import BaseChatModel
+from browser_use.llm.exceptions import ModelProviderError
+from browser_use.llm.views import ChatInvokeCompletion
+from browser_use.tools.extraction.typed import ExtractionValidationError, extract_typed
+from browser_use.tools.service import Tools
+
+
+class Product(BaseModel):
+	name: str
+	price: float
+	sku: str | None = None
+
+	@field_validator('price')
+	@classmethod
+	def positive(cls, value: float) -> float:
+		if value <= 0:
+			raise ValueError('price must be positive')
+		return value
+
+
+class Products(BaseModel):
+	products: list[Product]
+
+
+def _llm(*answers: dict) -> BaseChatModel:
+	"""An LLM that answers each call with the next dict, parsed like a provider client does."""
+	llm = AsyncMock(spec=BaseChatModel)
+	llm.model = 'mock-extraction-llm'
+	calls: list[list] = []
+
+	async def ainvoke(messages, output_format=None, **kwargs):
+		calls.append(messages)
+		answer = answers[min(len(calls), len(answers)) - 1]
+		try:
+			return ChatInvokeCompletion(completion=output_format.model_validate(answer), usage=None)
+		except Exception as e:
+			raise ModelProviderError(message=str(e), model='mock-extraction-llm') from e
+
+	llm.ainvoke.side_effect = ainvoke
+	llm.calls = calls
+	return llm
+
+
+@pytest.fixture
+async def products_page(browser_session, httpserver: HTTPServer):
+	httpserver.expect_request('/products').respond_with_data(
+		'<h1>Products</h1><ul><li>Widget A - $9.99</li><li>Widget B - $19.99 (SKU B-2)</li></ul>', content_type='text/html'
+	)
+	await Tools().navigate(url=httpserver.url_for('/products'), new_tab=False, browser_session=browser_session)
+	return browser_session
+
+
+async def test_returns_an_instance_of_the_model(products_page):
+	llm = _llm({'products': [{'name': 'Widget A', 'price': 9.99}, {'name': 'Widget B', 'price': 19.99, 'sku': 'B-2'}]})
+
+	result = await extract_typed(products_page, llm, 'all products', Products)
+
+	assert isinstance(result, Products)
+	assert [p.name for p in result.products] == ['Widget A', 'Widget B'] and result.products[1].sku == 'B-2'
+	prompt = llm.calls[0][1].text
+	assert 'Widget B - $19.99' in prompt and '"products"' in prompt
+
+
+async def test_retries_with_the_validation_errors(products_page):
+	llm = _llm({'products': [{'name': 'Widget A', 'price': -1}]}, {'products': [{'name': 'Widget A', 'price': 9.99}]})
+
+	result = await extract_typed(products_page, llm, 'all products', Products)
+
+	assert result.products[0].price == 9.99
+	assert len(llm.calls) == 2
+	assert 'price must be positive' in llm.calls[1][-1].text
+
+
+async def test_gives_up_after_max_retries(products_page):
+	llm = _llm({'items': []})
+
+	with pytest.raises(ExtractionValidationError, match='Products after 2 attempts'):
+		await extract_typed(products_page, llm, 'all products', Products, max_retries=1)
+	assert len(llm.calls) == 2
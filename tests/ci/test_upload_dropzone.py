@@ -0,0 +1,150 @@
+"""Tests for the upload_dropzone action (file upload via drag-and-drop zones without a file input)."""
+
+import asyncio
+
+import pytest
+from pytest_httpserver import HTTPServer
+
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.tools.service import Tools
+
+_DROPZONE_PAGE = """<html><body>
+<div id="dropzone" role="button" style="width:300px;height:150px;border:2px dashed #999">Drop files here</div>
+<div id="log"></div>
+<script>
+	const zone = document.getElementById('dropzone');
+	const events = [];
+	['dragenter', 'dragover'].forEach(type => zone.addEventListener(type, e => { events.push(type); e.preventDefault(); }));
+	zone.addEventListener('drop', async e => {
+		e.preventDefault();
+		events.push('drop');
+		const file = e.dataTransfer.files[0];
+		const text = await file.text();
+		document.getElementById('log').textContent = events.join(',') + '|' + file.name + '|' + text;
+	});
+</script>
+</body></html>"""
+
+# Uploader that keeps a hidden file input inside its drop zone, like react-dropzone
+_HIDDEN_INPUT_PAGE = """<html><body>
+<div id="uploader" role="button" style="width:300px;height:150px;border:2px dashed #999">
+	Drag a file here
+	<input type="file" id="hidden-input" style="display:none">
+</div>
+<div id="log"></div>
+<script>
+	const log = (source, file) => file.text().then(text => {
+		document.getElementById('log').textContent = source + '|' + file.name + '|' + file.type + '|' + text;
+	});
+	document.getElementById('hidden-input').addEventListener('change', e => log('change', e.target.files[0]));
+	const zone = document.getElementById('uploader');
+	zone.addEventListener('dragover', e => e.preventDefault());
+	zone.addEventListener('drop', e => { e.preventDefault(); log('drop', e.dataTransfer.files[0]); });
+</script>
+</body></html>"""
+
+
+@pytest.fixture(scope='session')
+def http_server():
+	server = HTTPServer()
+	server.start()
+	server.expect_request('/dropzone').respond_with_data(_DROPZONE_PAGE, content_type='text/html')
+	server.expect_request('/hidden-input').respond_with_data(_HIDDEN_INPUT_PAGE, content_type='text/html')
+	yield server
+	server.stop()
+
+
+@pytest.fixture(scope='session')
+def base_url(http_server):
+	return f'http://{http_server.host}:{http_server.port}'
+
+
+async def test_upload_dropzone_drops_agent_file(browser_session, base_url, tmp_path):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/dropzone', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+
+	file_system = FileSystem(base_dir=tmp_path)
+	await file_system.write_file('notes.txt', 'dropped content')
+
+	await browser_session.get_browser_state_summary()
+	selector_map = await browser_session.get_selector_map()
+	dropzone_index = next(idx for idx, node in selector_map.items() if node.attributes.get('id') == 'dropzone')
+
+	result = await tools.upload_dropzone(
+		index=dropzone_index,
+		path='notes.txt',
+		browser_session=browser_session,
+		available_file_paths=[],
+		file_system=file_system,
+	)
+	assert result.error is None, result.error
+	assert result.long_term_memory == f'Dropped file notes.txt on element {dropzone_index}'
+
+	await asyncio.sleep(0.2)
+	cdp_session = await browser_session.get_or_create_cdp_session()
+	log = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "document.getElementById('log').textContent"}, session_id=cdp_session.session_id
+	)
+	assert log['result']['value'] == 'dragenter,dragover,drop|notes.txt|dropped content'
+
+	# The helper file input is removed again after the drop
+	inputs = await cdp_session.cdp_client.send.Runtime.evaluate(
+		params={'expression': "document.querySelectorAll('input[type=file]').length"}, session_id=cdp_session.session_id
+	)
+	assert inputs['result']['value'] == 0
+
+
+async def test_upload_dropzone_rejects_unavailable_file(browser_session, base_url, tmp_path):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/dropzone', new_tab=False, browser_session=browser_session)
+
+	result = await tools.upload_dropzone(
+		index=1,
+		path='/etc/passwd',
+		browser_session=browser_session,
+		available_file_paths=[],
+		file_system=FileSystem(base_dir=tmp_path),
+	)
+	assert result.error is not None
+	assert 'not available' in result.error
+
+
+async def test_upload_dropzone_prefers_hidden_file_input(browser_session, base_url, tmp_path):
+	tools = Tools()
+	await tools.navigate(url=f'{base_url}/hidden-input', new_tab=False, browser_session=browser_session)
+	await asyncio.sleep(0.5)
+
+	file_system = FileSystem(base_dir=tmp_path)
+	await file_system.write_file('data.csv', 'a,b')
+
+	await browser_session.get_browser_state_summary()
+	selector_map = await browser_session.get_selector_map()
+	uploader_index = next(idx for idx, node in selector_map.items() if node.attributes.get('id') == 'uploader')
+	cdp_session = await browser_session.get_or_create_cdp_session()
+
+	async def read_log() -> str:
+		await asyncio.sleep(0.2)
+		result = await cdp_session.cdp_client.send.Runtime.evaluate(
+			params={'expression': "document.getElementById('log').textContent"}, session_id=cdp_session.session_id
+		)
+		return result['result']['value']
+
+	result = await tools.upload_dropzone(
+		index=uploader_index, path='data.csv', browser_session=browser_session, available_file_paths=[], file_system=file_system
+	)
+	assert result.error is None, result.error
+	assert 'through the file input' in (result.long_term_memory or '')
+	assert await read_log() == 'change|data.csv|text/csv|a,b'
+
+	# Forcing a drop sends the file content with the drop events, typed from its extension
+	result = await tools.upload_dropzone(
+		index=uploader_index,
+		path='data.csv',
+		mode='drop',
+		browser_session=browser_session,
+		available_file_paths=[],
+		file_system=file_system,
+	)
+	assert result.error is None, result.error
+	assert await read_log() == 'drop|data.csv|text/csv|a,b'
@@ -0,0 +1,91 @@
+"""Tests for VisionBudget: limiting the screenshots sent to the LLM."""
+
+import base64
+import io
+
+from PIL import Image
+
+from browser_use.agent.message_manager.service import MessageManager
+from browser_use.agent.views import ActionResult, AgentStepInfo, VisionBudget
+from browser_use.browser.views import BrowserStateSummary, TabInfo
+from browser_use.dom.views import SerializedDOMState
+from browser_use.filesystem.file_system import FileSystem
+from browser_use.llm.messages import ContentPartImageParam, SystemMessage
+
+
+def _screenshot(color: str) -> str:
+	buffer = io.BytesIO()
+	Image.new('RGB', (200, 120), color=color).save(buffer, format='PNG')
+	return base64.b64encode(buffer.getvalue()).decode()
+
+
+def _browser_state(screenshot: str) -> BrowserStateSummary:
+	return BrowserStateSummary(
+		url='https://example.com',
+		title='Example',
+		tabs=[TabInfo(target_id='tab-0', url='https://example.com', title='Example')],
+		screenshot=screenshot,
+		dom_state=SerializedDOMState(_root=None, selector_map={}),
+	)
+
+
+def _sent_images(mm: MessageManager) -> list[ContentPartImageParam]:
+	message = mm.state.history.state_message
+	assert message is not None
+	if isinstance(message.content, str):
+		return []
+	return [part for part in message.content if isinstance(part, ContentPartImageParam)]
+
+
+def _step(mm: MessageManager, step_number: int, screenshot: str, result: list[ActionResult] | None = None):
+	mm.create_state_messages(
+		browser_state_summary=_browser_state(screenshot),
+		result=result,
+		step_info=AgentStepInfo(step_number=step_number, max_steps=20),
+		use_vision=True,
+		skip_state_update=True,
+	)
+	return _sent_images(mm)
+
+
+def test_every_n_steps_max_screenshots_and_include_last(tmp_path):
+	mm = MessageManager(
+		task='test',
+		system_message=SystemMessage(content='system'),
+		file_system=FileSystem(tmp_path),
+		vision_budget=VisionBudget(every_n_steps=2, max_screenshots=2, include_last=2),
+	)
+	screenshots = [_screenshot(color) for color in ('red', 'green', 'blue', 'white', 'black')]
+
+	assert len(_step(mm, 0, screenshots[0])) == 1
+	assert _step(mm, 1, screenshots[1]) == []
+
+	# The second vision step also carries the previous screenshot
+	images = _step(mm, 2, screenshots[2])
+	assert [image.image_url.url for image in images] == [f'data:image/png;base64,{s}' for s in (screenshots[0], screenshots[2])]
+
+	# Budget exhausted, even on a cadence step
+	assert _step(mm, 4, screenshots[4]) == []
+	assert (mm.state.screenshots_sent, mm.state.screenshots_skipped) == (2, 2)
+
+
+def test_after_failures_only_and_jpeg_quality(tmp_path):
+	mm = MessageManager(
+		task='test',
+		system_message=SystemMessage(content='system'),
+		file_system=FileSystem(tmp_path),
+		vision_budget=VisionBudget(after_failures_only=True, jpeg_quality=50),
+	)
+	screenshot = _screenshot('red')
+
+	assert _step(mm, 0, screenshot, result=[ActionResult(extracted_content='clicked')]) == []
+
+	images = _step(mm, 1, screenshot, result=[ActionResult(error='Element not found')])
+	assert len(images) == 1
+	assert images[0].image_url.media_type == 'image/jpeg'
+	assert images[0].image_url.url.startswith('data:image/jpeg;base64,')
+
+	# Screenshots requested by an action skip the cadence rules
+	requested = [ActionResult(extracted_content='screenshot', metadata={'include_screenshot': True})]
+	assert len(_step(mm, 2, screenshot, result=requested)) == 1
+	assert (mm.state.screenshots_sent, mm.state.screenshots_skipped) == (2, 1)
@@ -0,0 +1,92 @@
+"""Tests for the wait_for_element and wait_for_url actions."""
+
+from pytest_httpserver import HTTPServer
+
+from browser_use.tools.service import Tools
+from browser_use.tools.views import WaitForElementAction, WaitForUrlAction
+
+SLOW_PAGE = """
+<html><body>
+	<div id="spinner">Loading...</div>
+	<div id="results" style="display: none">3 results</div>
+	<script>
+		setTimeout(() => {
+			document.getElementById('spinner').remove();
+			document.getElementById('results').style.display = 'block';
+			history.pushState({}, '', '/search/results?q=shoes');
+		}, 600);
+	</script>
+</body></html>
+"""
+
+
+async def _open_slow_page(browser_session, httpserver: HTTPServer, tools: Tools) -> None:
+	httpserver.expect_request('/search').respond_with_data(SLOW_PAGE, content_type='text/html')
+	await tools.navigate(url=httpserver.url_for('/search'), new_tab=False, browser_session=browser_session)
+
+
+async def test_wait_for_element_states(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	await _open_slow_page(browser_session, httpserver, tools)
+
+	# Present in the DOM right away, but only shown later
+	result = await tools.wait_for_element(
+		params=WaitForElementAction(selector='#results', state='attached', timeout=0), browser_session=browser_session
+	)
+	assert result.error is None
+
+	result = await tools.wait_for_element(params=WaitForElementAction(selector='#results'), browser_session=browser_session)
+	assert result.error is None
+	assert result.extracted_content is not None and '"#results" was visible' in result.extracted_content
+
+	result = await tools.wait_for_element(
+		params=WaitForElementAction(selector='#spinner', state='hidden', timeout=5), browser_session=browser_session
+	)
+	assert result.error is None
+
+	result = await tools.wait_for_element(
+		params=WaitForElementAction(selector='#never', timeout=0.5), browser_session=browser_session
+	)
+	assert result.error is not None and 'Timed out' in result.error
+	assert result.error_type == 'timeout'
+
+
+async def test_wait_for_element_by_index(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	httpserver.expect_request('/toast').respond_with_data(
+		'<html><body><button id="toast" onclick="setTimeout(() => this.remove(), 300)">Saved! Dismiss</button></body></html>',
+		content_type='text/html',
+	)
+	await tools.navigate(url=httpserver.url_for('/toast'), new_tab=False, browser_session=browser_session)
+	await browser_session.get_browser_state_summary(include_screenshot=False)
+	selector_map = await browser_session.get_selector_map()
+	toast_index = next(i for i, node in selector_map.items() if node.attributes.get('id') == 'toast')
+
+	result = await tools.wait_for_element(params=WaitForElementAction(index=toast_index), browser_session=browser_session)
+	assert result.error is None
+
+	await tools.click(index=toast_index, browser_session=browser_session)
+	result = await tools.wait_for_element(
+		params=WaitForElementAction(index=toast_index, state='hidden', timeout=5), browser_session=browser_session
+	)
+	assert result.error is None
+
+	result = await tools.wait_for_element(params=WaitForElementAction(index=99999), browser_session=browser_session)
+	assert result.error_type == 'element_not_found'
+
+
+async def test_wait_for_url(browser_session, httpserver: HTTPServer):
+	tools = Tools()
+	await _open_slow_page(browser_session, httpserver, tools)
+
+	result = await tools.wait_for_url(params=WaitForUrlAction(pattern='*/search/results*'), browser_session=browser_session)
+	assert result.error is None
+	assert result.extracted_content is not None and 'q=shoes' in result.extracted_content
+
+	result = await tools.wait_for_url(params=WaitForUrlAction(pattern='q=shoes'), browser_session=browser_session)
+	assert result.error is None
+
+	result = await tools.wait_for_url(
+		params=WaitForUrlAction(pattern=r'/checkout/\d+', regex=True, timeout=0.5), browser_session=browser_session
+	)
+	assert result.error is not None and result.error_type == 'timeout'